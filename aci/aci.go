@@ -30,7 +30,6 @@ import (
 	tm "github.com/buger/goterm"
 	"github.com/gobwas/ws"
 	"github.com/gobwas/ws/wsutil"
-	"github.com/morikuni/aec"
 	"github.com/pkg/errors"
 
 	"github.com/docker/compose-cli/aci/convert"
@@ -38,11 +37,12 @@ import (
 	"github.com/docker/compose-cli/api/containers"
 	"github.com/docker/compose-cli/context/store"
 	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/output"
 	"github.com/docker/compose-cli/progress"
 )
 
 func createACIContainers(ctx context.Context, aciContext store.AciContext, groupDefinition containerinstance.ContainerGroup) error {
-	containerGroupsClient, err := login.NewContainerGroupsClient(aciContext.SubscriptionID)
+	containerGroupsClient, err := login.NewContainerGroupsClient(aciContext.SubscriptionID, aciContext.Operations())
 	if err != nil {
 		return errors.Wrapf(err, "cannot get container group client")
 	}
@@ -66,10 +66,23 @@ func createACIContainers(ctx context.Context, aciContext store.AciContext, group
 
 func createOrUpdateACIContainers(ctx context.Context, aciContext store.AciContext, groupDefinition containerinstance.ContainerGroup) error {
 	w := progress.ContextWriter(ctx)
-	containerGroupsClient, err := login.NewContainerGroupsClient(aciContext.SubscriptionID)
+	containerGroupsClient, err := login.NewContainerGroupsClient(aciContext.SubscriptionID, aciContext.Operations())
 	if err != nil {
 		return errors.Wrapf(err, "cannot get container group client")
 	}
+	var patches []groupPatch
+	if zone, ok := groupDefinition.Tags[convert.ZoneTag]; ok {
+		patches = append(patches, zonePatch(to.String(zone)))
+	}
+	if aciContext.Private() {
+		if err := ensureSubnetDelegation(ctx, aciContext); err != nil {
+			return err
+		}
+		patches = append(patches, subnetPatch(aciContext.SubnetID))
+	}
+	if len(patches) > 0 {
+		containerGroupsClient.RequestInspector = withGroupPatches(patches...)
+	}
 	groupDisplay := "Group " + *groupDefinition.Name
 	w.Event(progress.Event{
 		ID:         groupDisplay,
@@ -121,7 +134,7 @@ func createOrUpdateACIContainers(ctx context.Context, aciContext store.AciContex
 }
 
 func getACIContainerGroup(ctx context.Context, aciContext store.AciContext, containerGroupName string) (containerinstance.ContainerGroup, error) {
-	containerGroupsClient, err := login.NewContainerGroupsClient(aciContext.SubscriptionID)
+	containerGroupsClient, err := login.NewContainerGroupsClient(aciContext.SubscriptionID, aciContext.Operations())
 	if err != nil {
 		return containerinstance.ContainerGroup{}, fmt.Errorf("cannot get container group client: %v", err)
 	}
@@ -129,11 +142,12 @@ func getACIContainerGroup(ctx context.Context, aciContext store.AciContext, cont
 	return containerGroupsClient.Get(ctx, aciContext.ResourceGroup, containerGroupName)
 }
 
-func getACIContainerGroups(ctx context.Context, subscriptionID string, resourceGroup string) ([]containerinstance.ContainerGroup, error) {
-	groupsClient, err := login.NewContainerGroupsClient(subscriptionID)
+func getACIContainerGroups(ctx context.Context, aciContext store.AciContext) ([]containerinstance.ContainerGroup, error) {
+	groupsClient, err := login.NewContainerGroupsClient(aciContext.SubscriptionID, aciContext.Operations())
 	if err != nil {
 		return nil, err
 	}
+	resourceGroup := aciContext.ResourceGroup
 	var containerGroups []containerinstance.ContainerGroup
 	result, err := groupsClient.ListByResourceGroup(ctx, resourceGroup)
 	if err != nil {
@@ -146,19 +160,11 @@ func getACIContainerGroups(ctx context.Context, subscriptionID string, resourceG
 			return nil, err
 		}
 	}
-	var groups []containerinstance.ContainerGroup
-	for _, group := range containerGroups {
-		group, err := groupsClient.Get(ctx, resourceGroup, *group.Name)
-		if err != nil {
-			return nil, err
-		}
-		groups = append(groups, group)
-	}
-	return groups, nil
+	return getGroupsDetails(ctx, aciContext, containerGroups)
 }
 
 func deleteACIContainerGroup(ctx context.Context, aciContext store.AciContext, containerGroupName string) (containerinstance.ContainerGroup, error) {
-	containerGroupsClient, err := login.NewContainerGroupsClient(aciContext.SubscriptionID)
+	containerGroupsClient, err := login.NewContainerGroupsClient(aciContext.SubscriptionID, aciContext.Operations())
 	if err != nil {
 		return containerinstance.ContainerGroup{}, fmt.Errorf("cannot get container group client: %v", err)
 	}
@@ -167,7 +173,7 @@ func deleteACIContainerGroup(ctx context.Context, aciContext store.AciContext, c
 }
 
 func stopACIContainerGroup(ctx context.Context, aciContext store.AciContext, containerGroupName string) error {
-	containerGroupsClient, err := login.NewContainerGroupsClient(aciContext.SubscriptionID)
+	containerGroupsClient, err := login.NewContainerGroupsClient(aciContext.SubscriptionID, aciContext.Operations())
 	if err != nil {
 		return fmt.Errorf("cannot get container group client: %v", err)
 	}
@@ -179,8 +185,21 @@ func stopACIContainerGroup(ctx context.Context, aciContext store.AciContext, con
 	return err
 }
 
+func restartACIContainerGroup(ctx context.Context, aciContext store.AciContext, containerGroupName string) error {
+	containerGroupsClient, err := login.NewContainerGroupsClient(aciContext.SubscriptionID, aciContext.Operations())
+	if err != nil {
+		return fmt.Errorf("cannot get container group client: %v", err)
+	}
+
+	future, err := containerGroupsClient.Restart(ctx, aciContext.ResourceGroup, containerGroupName)
+	if err != nil {
+		return err
+	}
+	return future.WaitForCompletionRef(ctx, containerGroupsClient.Client)
+}
+
 func execACIContainer(ctx context.Context, aciContext store.AciContext, command, containerGroup string, containerName string) (c containerinstance.ContainerExecResponse, err error) {
-	containerClient, err := login.NewContainerClient(aciContext.SubscriptionID)
+	containerClient, err := login.NewContainerClient(aciContext.SubscriptionID, aciContext.Operations())
 	if err != nil {
 		return c, errors.Wrapf(err, "cannot get container client")
 	}
@@ -273,7 +292,7 @@ func exec(ctx context.Context, address string, password string, request containe
 }
 
 func getACIContainerLogs(ctx context.Context, aciContext store.AciContext, containerGroupName, containerName string, tail *int32) (string, error) {
-	containerClient, err := login.NewContainerClient(aciContext.SubscriptionID)
+	containerClient, err := login.NewContainerClient(aciContext.SubscriptionID, aciContext.Operations())
 	if err != nil {
 		return "", errors.Wrapf(err, "cannot get container client")
 	}
@@ -288,7 +307,7 @@ func getACIContainerLogs(ctx context.Context, aciContext store.AciContext, conta
 func streamLogs(ctx context.Context, aciContext store.AciContext, containerGroupName, containerName string, req containers.LogsRequest) error {
 	numLines := 0
 	previousLogLines := ""
-	firstDisplay := true // optimization to exit sooner in cases like docker run hello-world, do not wait another 2 secs.
+	firstDisplay := true // optimization to exit sooner in cases like docker run hello-world, do not wait another polling interval.
 	for {
 		select {
 		case <-ctx.Done():
@@ -305,9 +324,7 @@ func streamLogs(ctx context.Context, aciContext store.AciContext, containerGroup
 			// streaming over gRPC but this is the only thing we can do with
 			// the kind of logs ACI is giving us. Hopefully Azue will give us
 			// a real logs streaming api soon.
-			b := aec.EmptyBuilder
-			b = b.Up(uint(numLines))
-			fmt.Fprint(req.Writer, b.Column(0).ANSI)
+			fmt.Fprint(req.Writer, output.MoveCursor(numLines, 0))
 
 			numLines = getBacktrackLines(logLines, req.Width)
 
@@ -324,7 +341,7 @@ func streamLogs(ctx context.Context, aciContext store.AciContext, containerGroup
 			select {
 			case <-ctx.Done():
 				return nil
-			case <-time.After(2 * time.Second):
+			case <-time.After(aciContext.Operations().PollingInterval):
 			}
 		}
 	}
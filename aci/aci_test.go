@@ -19,10 +19,33 @@ package aci
 import (
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/Azure/go-autorest/autorest/to"
 	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/aci/convert"
 )
 
 func TestGetLinesWritten(t *testing.T) {
 	assert.Equal(t, 0, getBacktrackLines([]string{"Hello"}, 10))
 	assert.Equal(t, 3, getBacktrackLines([]string{"Hello", "world"}, 2))
 }
+
+func TestGroupImages(t *testing.T) {
+	group := containerinstance.ContainerGroup{
+		ContainerGroupProperties: &containerinstance.ContainerGroupProperties{
+			Containers: &[]containerinstance.Container{
+				{
+					Name:                to.StringPtr("web"),
+					ContainerProperties: &containerinstance.ContainerProperties{Image: to.StringPtr("nginx")},
+				},
+				{
+					Name:                to.StringPtr(convert.ComposeDNSSidecarName),
+					ContainerProperties: &containerinstance.ContainerProperties{Image: to.StringPtr("busybox")},
+				},
+			},
+		},
+	}
+	assert.DeepEqual(t, groupImages(group), []string{"nginx"})
+	assert.Assert(t, len(groupImages(containerinstance.ContainerGroup{})) == 0)
+}
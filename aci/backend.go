@@ -28,6 +28,7 @@ import (
 	"github.com/docker/compose-cli/aci/login"
 	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/api/containers"
+	"github.com/docker/compose-cli/api/images"
 	"github.com/docker/compose-cli/api/secrets"
 	"github.com/docker/compose-cli/api/volumes"
 	"github.com/docker/compose-cli/backend"
@@ -121,6 +122,10 @@ func (a *aciAPIService) VolumeService() volumes.Service {
 	return a.aciVolumeService
 }
 
+func (a *aciAPIService) ImageService() images.Service {
+	return nil
+}
+
 func getContainerID(group containerinstance.ContainerGroup, container containerinstance.Container) string {
 	containerID := *group.Name + composeContainerSeparator + *container.Name
 	if _, ok := group.Tags[singleContainerTag]; ok {
@@ -140,6 +145,16 @@ func addTag(groupDefinition *containerinstance.ContainerGroup, tagName string) {
 	groupDefinition.Tags[tagName] = to.StringPtr(tagName)
 }
 
+// addTags sets tags on the group, keeping the ones set on conversion
+func addTags(groupDefinition *containerinstance.ContainerGroup, tags map[string]*string) {
+	if groupDefinition.Tags == nil {
+		groupDefinition.Tags = make(map[string]*string, len(tags))
+	}
+	for k, v := range tags {
+		groupDefinition.Tags[k] = v
+	}
+}
+
 func getGroupAndContainerName(containerID string) (string, string) {
 	tokens := strings.Split(containerID, composeContainerSeparator)
 	groupName := tokens[0]
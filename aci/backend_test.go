@@ -20,10 +20,15 @@ import (
 	"context"
 	"testing"
 
+	"github.com/compose-spec/compose-go/types"
 	"github.com/stretchr/testify/mock"
 	"gotest.tools/v3/assert"
 
+	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/api/containers"
+	"github.com/docker/compose-cli/config"
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/errdefs"
 )
 
 func TestGetContainerName(t *testing.T) {
@@ -142,3 +147,16 @@ func (s *mockLoginService) Logout(ctx context.Context) error {
 	args := s.Called(ctx)
 	return args.Error(0)
 }
+
+func TestUpFailsFastOffline(t *testing.T) {
+	defer config.SetOffline(false) // nolint:errcheck
+	assert.NilError(t, config.SetOffline(true))
+	cs := newComposeService(store.AciContext{SubscriptionID: "subscription", ResourceGroup: "rg", Location: "eastus"})
+	err := cs.Up(context.TODO(), &types.Project{
+		Name: "shop",
+		Services: []types.ServiceConfig{
+			{Name: "web", Image: "nginx"},
+		},
+	}, compose.UpOptions{})
+	assert.Assert(t, errdefs.IsErrOffline(err), err)
+}
@@ -0,0 +1,107 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Azure/azure-sdk-for-go/services/authorization/mgmt/2015-07-01/authorization"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/aci/login"
+	"github.com/docker/compose-cli/context/store"
+)
+
+// BootstrapDescription describes the custom role created by ApplyBootstrapRole
+const BootstrapDescription = "Least privileges for developers to deploy Compose applications to Azure Container Instances"
+
+// RoleDefinition is an Azure custom role definition, in the format of `az role definition create`
+type RoleDefinition struct {
+	Name             string
+	IsCustom         bool
+	Description      string
+	Actions          []string
+	NotActions       []string
+	AssignableScopes []string
+}
+
+// BootstrapRole returns the custom role definition granting the actions compose operations rely on in a
+// resource group, for administrators to grant developers least-privilege access. The role is assignable to
+// the whole subscription when resourceGroup is empty.
+func BootstrapRole(name string, subscriptionID string, resourceGroup string) RoleDefinition {
+	return RoleDefinition{
+		Name:             name,
+		IsCustom:         true,
+		Description:      BootstrapDescription,
+		Actions:          bootstrapActions(),
+		NotActions:       []string{},
+		AssignableScopes: []string{bootstrapScope(subscriptionID, resourceGroup)},
+	}
+}
+
+// ApplyBootstrapRole creates or updates the bootstrap role in the context subscription and returns its ID
+func ApplyBootstrapRole(ctx context.Context, aciContext store.AciContext, name string) (string, error) {
+	role := BootstrapRole(name, aciContext.SubscriptionID, aciContext.ResourceGroup)
+	client, err := login.NewRoleDefinitionsClient(aciContext.SubscriptionID, aciContext.Operations())
+	if err != nil {
+		return "", err
+	}
+	scope := role.AssignableScopes[0]
+	// a stable GUID so that bootstrapping again updates the role
+	id := uuid.NewSHA1(uuid.NameSpaceURL, []byte(scope+"/"+name)).String()
+	result, err := client.CreateOrUpdate(ctx, scope, id, authorization.RoleDefinition{
+		RoleDefinitionProperties: &authorization.RoleDefinitionProperties{
+			RoleName:    to.StringPtr(role.Name),
+			Description: to.StringPtr(role.Description),
+			RoleType:    to.StringPtr("CustomRole"),
+			Permissions: &[]authorization.Permission{
+				{Actions: &role.Actions, NotActions: &role.NotActions},
+			},
+			AssignableScopes: &role.AssignableScopes,
+		},
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot create role %q", name)
+	}
+	return to.String(result.ID), nil
+}
+
+func bootstrapScope(subscriptionID string, resourceGroup string) string {
+	if resourceGroup == "" {
+		return fmt.Sprintf("/subscriptions/%s", subscriptionID)
+	}
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s", subscriptionID, resourceGroup)
+}
+
+func bootstrapActions() []string {
+	seen := map[string]bool{}
+	var actions []string
+	for _, perm := range permissions {
+		for _, action := range perm.actions {
+			if !seen[action] {
+				seen[action] = true
+				actions = append(actions, action)
+			}
+		}
+	}
+	sort.Strings(actions)
+	return actions
+}
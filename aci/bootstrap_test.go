@@ -0,0 +1,34 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestBootstrapRole(t *testing.T) {
+	role := BootstrapRole("developers", "123", "rg")
+	assert.DeepEqual(t, role.AssignableScopes, []string{"/subscriptions/123/resourceGroups/rg"})
+	assert.Assert(t, is.Contains(role.Actions, "Microsoft.ContainerInstance/containerGroups/write"))
+	assert.Assert(t, is.Contains(role.Actions, "Microsoft.ContainerInstance/containerGroups/delete"))
+
+	role = BootstrapRole("developers", "123", "")
+	assert.DeepEqual(t, role.AssignableScopes, []string{"/subscriptions/123"})
+}
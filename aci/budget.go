@@ -0,0 +1,54 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+
+	"github.com/compose-spec/compose-go/types"
+
+	"github.com/docker/compose-cli/aci/convert"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/context/store"
+)
+
+// checkBudget refuses projects requesting more resources than the context budget allows
+func checkBudget(ctx context.Context, aciContext store.AciContext, project types.Project) error {
+	budget := aciContext.Budget
+	if !budget.IsSet() {
+		return nil
+	}
+	var cpus, memory float64
+	for _, service := range project.Services {
+		c, m, err := convert.ServiceResources(service)
+		if err != nil {
+			return err
+		}
+		cpus += c
+		memory += m
+	}
+	var monthly float64
+	// only query prices when a cost limit is set
+	if budget.MaxMonthlyCost > 0 {
+		cpuPrice, memoryPrice, _, err := containerInstancesPrices(ctx, aciContext.Location, aciContext.Operations())
+		if err != nil {
+			return err
+		}
+		monthly = (cpus*cpuPrice + memory*memoryPrice) * compose.HoursPerMonth
+	}
+	return budget.Check(cpus, memory, monthly)
+}
@@ -0,0 +1,300 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerregistry/mgmt/2019-05-01/containerregistry"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/containerd/containerd/platforms"
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/builder/dockerignore"
+	"github.com/docker/docker/pkg/archive"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/aci/login"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/progress"
+)
+
+const (
+	// BuilderACR selects remote builds with ACR Tasks
+	BuilderACR = "acr"
+
+	// targetPlatform is the platform ACI runs linux containers on
+	targetPlatform = "linux/amd64"
+)
+
+// buildWithACR builds services declaring a build section using ACR Tasks, and pins the service image
+// to the digest of the image pushed into the registry
+func (cs *aciComposeService) buildWithACR(ctx context.Context, project *types.Project) error {
+	w := progress.ContextWriter(ctx)
+	return compose.InDependencyOrder(ctx, project, func(ctx context.Context, service *types.ServiceConfig) error {
+		if service.Build == nil {
+			return nil
+		}
+		named, err := reference.ParseNormalizedNamed(service.Image)
+		if err != nil || !strings.HasSuffix(reference.Domain(named), ".azurecr.io") {
+			return fmt.Errorf("service %q must declare an Azure Container Registry image to be built with ACR", service.Name)
+		}
+		w.Event(progress.Event{
+			ID:         service.Name,
+			Status:     progress.Working,
+			StatusText: "Building with ACR",
+		})
+		platform := service.Platform
+		if platform == "" {
+			platform = targetPlatform
+		}
+		p, err := platforms.Parse(platform)
+		if err != nil {
+			return errors.Wrapf(err, "invalid platform %q for service %q", platform, service.Name)
+		}
+		image, err := cs.acrBuild(ctx, reference.Domain(named), reference.TagNameOnly(named).String(), *service.Build, p)
+		if err != nil {
+			w.Event(progress.Event{
+				ID:         service.Name,
+				Status:     progress.Error,
+				StatusText: "Building with ACR",
+			})
+			return err
+		}
+		service.Image = image
+		service.Build = nil
+		w.Event(progress.Event{
+			ID:         service.Name,
+			Status:     progress.Done,
+			StatusText: "Built",
+		})
+		return nil
+	})
+}
+
+func (cs *aciComposeService) acrBuild(ctx context.Context, loginServer string, image string, build types.BuildConfig, platform specs.Platform) (string, error) {
+	buildPlatform, err := acrPlatform(platform)
+	if err != nil {
+		return "", err
+	}
+
+	registriesClient, err := login.NewRegistriesClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
+	if err != nil {
+		return "", err
+	}
+	registryName, resourceGroup, err := findRegistry(ctx, registriesClient, loginServer)
+	if err != nil {
+		return "", err
+	}
+
+	upload, err := registriesClient.GetBuildSourceUploadURL(ctx, resourceGroup, registryName)
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot get build source upload URL for registry %s", registryName)
+	}
+	if err := uploadBuildContext(ctx, *upload.UploadURL, build.Context); err != nil {
+		return "", err
+	}
+
+	dockerfile := build.Dockerfile
+	if dockerfile == "" {
+		dockerfile = "Dockerfile"
+	}
+	if filepath.IsAbs(dockerfile) {
+		if dockerfile, err = filepath.Rel(build.Context, dockerfile); err != nil {
+			return "", err
+		}
+	}
+	request := containerregistry.DockerBuildRequest{
+		ImageNames:     &[]string{strings.TrimPrefix(image, loginServer+"/")},
+		IsPushEnabled:  to.BoolPtr(true),
+		DockerFilePath: to.StringPtr(filepath.ToSlash(dockerfile)),
+		SourceLocation: upload.RelativePath,
+		Arguments:      buildArguments(build.Args),
+		Platform:       buildPlatform,
+	}
+	if build.Target != "" {
+		request.Target = to.StringPtr(build.Target)
+	}
+	future, err := registriesClient.ScheduleRun(ctx, resourceGroup, registryName, request)
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot schedule ACR build for %s", image)
+	}
+	if err := future.WaitForCompletionRef(ctx, registriesClient.Client); err != nil {
+		return "", err
+	}
+	run, err := future.Result(registriesClient)
+	if err != nil {
+		return "", err
+	}
+
+	runsClient, err := login.NewRunsClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
+	if err != nil {
+		return "", err
+	}
+	for {
+		run, err = runsClient.Get(ctx, resourceGroup, registryName, *run.RunID)
+		if err != nil {
+			return "", err
+		}
+		switch run.Status {
+		case containerregistry.RunStatusSucceeded:
+			return builtImage(run, image)
+		case containerregistry.RunStatusFailed, containerregistry.RunStatusCanceled, containerregistry.RunStatusError, containerregistry.RunStatusTimeout:
+			return "", fmt.Errorf("ACR build %s for %s completed with status %s", *run.RunID, image, run.Status)
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(cs.ctx.Operations().PollingInterval):
+		}
+	}
+}
+
+func acrPlatform(platform specs.Platform) (*containerregistry.PlatformProperties, error) {
+	if platform.OS != "linux" {
+		return nil, fmt.Errorf("ACR builds are not supported for platform %s", platforms.Format(platform))
+	}
+	properties := &containerregistry.PlatformProperties{Os: containerregistry.Linux}
+	switch platform.Architecture {
+	case "amd64":
+		properties.Architecture = containerregistry.Amd64
+	case "386":
+		properties.Architecture = containerregistry.X86
+	case "arm":
+		properties.Architecture = containerregistry.Arm
+		properties.Variant = containerregistry.Variant(platform.Variant)
+	case "arm64":
+		// ACR builds arm64 images as the v8 variant of arm
+		properties.Architecture = containerregistry.Arm
+		properties.Variant = containerregistry.V8
+	default:
+		return nil, fmt.Errorf("ACR builds are not supported for platform %s", platforms.Format(platform))
+	}
+	return properties, nil
+}
+
+func findRegistry(ctx context.Context, client containerregistry.RegistriesClient, loginServer string) (string, string, error) {
+	registries, err := client.ListComplete(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	for registries.NotDone() {
+		r := registries.Value()
+		if r.RegistryProperties != nil && strings.EqualFold(to.String(r.LoginServer), loginServer) {
+			// resource ID is /subscriptions/<id>/resourceGroups/<group>/providers/...
+			parts := strings.Split(to.String(r.ID), "/")
+			for i := 0; i < len(parts)-1; i++ {
+				if strings.EqualFold(parts[i], "resourceGroups") {
+					return to.String(r.Name), parts[i+1], nil
+				}
+			}
+		}
+		if err := registries.NextWithContext(ctx); err != nil {
+			return "", "", err
+		}
+	}
+	return "", "", fmt.Errorf("registry %s not found in subscription %s", loginServer, client.SubscriptionID)
+}
+
+func uploadBuildContext(ctx context.Context, uploadURL string, buildContext string) error {
+	var excludes []string
+	if f, err := os.Open(filepath.Join(buildContext, ".dockerignore")); err == nil {
+		excludes, err = dockerignore.ReadAll(f)
+		f.Close() // nolint:errcheck
+		if err != nil {
+			return err
+		}
+	}
+	tar, err := archive.TarWithOptions(buildContext, &archive.TarOptions{
+		Compression:     archive.Gzip,
+		ExcludePatterns: excludes,
+	})
+	if err != nil {
+		return err
+	}
+	defer tar.Close() // nolint:errcheck
+
+	// Put Blob requires the content length, so the archive is written to a file rather than streamed
+	f, err := ioutil.TempFile("", "build-context-*.tar.gz")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name()) // nolint:errcheck
+	defer f.Close()           // nolint:errcheck
+	size, err := io.Copy(f, tar)
+	if err != nil {
+		return errors.Wrap(err, "cannot archive build context")
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, uploadURL, f)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("x-ms-blob-type", "BlockBlob")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "cannot upload build context")
+	}
+	defer res.Body.Close() // nolint:errcheck
+	if res.StatusCode != http.StatusCreated {
+		return fmt.Errorf("cannot upload build context, status: %s", res.Status)
+	}
+	return nil
+}
+
+func buildArguments(args types.MappingWithEquals) *[]containerregistry.Argument {
+	var keys []string
+	for k, v := range args {
+		if v != nil {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	arguments := []containerregistry.Argument{}
+	for _, k := range keys {
+		arguments = append(arguments, containerregistry.Argument{
+			Name:  to.StringPtr(k),
+			Value: args[k],
+		})
+	}
+	return &arguments
+}
+
+func builtImage(run containerregistry.Run, image string) (string, error) {
+	if run.RunProperties != nil && run.OutputImages != nil {
+		for _, output := range *run.OutputImages {
+			if output.Digest != nil {
+				return fmt.Sprintf("%s/%s@%s", to.String(output.Registry), to.String(output.Repository), *output.Digest), nil
+			}
+		}
+	}
+	return image, nil
+}
@@ -0,0 +1,111 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerregistry/mgmt/2019-05-01/containerregistry"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/compose-spec/compose-go/types"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func TestBuildArguments(t *testing.T) {
+	version := "1.2"
+	env := "prod"
+	arguments := buildArguments(types.MappingWithEquals{"VERSION": &version, "TOKEN": nil, "ENV": &env})
+	assert.DeepEqual(t, *arguments, []containerregistry.Argument{
+		{Name: to.StringPtr("ENV"), Value: &env},
+		{Name: to.StringPtr("VERSION"), Value: &version},
+	})
+
+	assert.DeepEqual(t, *buildArguments(nil), []containerregistry.Argument{})
+}
+
+func TestBuiltImage(t *testing.T) {
+	image := "myregistry.azurecr.io/shop/web:latest"
+	run := containerregistry.Run{RunProperties: &containerregistry.RunProperties{OutputImages: &[]containerregistry.ImageDescriptor{
+		{Registry: to.StringPtr("myregistry.azurecr.io"), Repository: to.StringPtr("shop/base"), Tag: to.StringPtr("latest")},
+		{Registry: to.StringPtr("myregistry.azurecr.io"), Repository: to.StringPtr("shop/web"), Tag: to.StringPtr("latest"), Digest: to.StringPtr("sha256:abc")},
+	}}}
+	built, err := builtImage(run, image)
+	assert.NilError(t, err)
+	assert.Equal(t, built, "myregistry.azurecr.io/shop/web@sha256:abc")
+
+	// runs without output images keep the tagged image
+	built, err = builtImage(containerregistry.Run{RunProperties: &containerregistry.RunProperties{}}, image)
+	assert.NilError(t, err)
+	assert.Equal(t, built, image)
+	built, err = builtImage(containerregistry.Run{}, image)
+	assert.NilError(t, err)
+	assert.Equal(t, built, image)
+}
+
+func TestAcrPlatform(t *testing.T) {
+	cases := map[string]containerregistry.PlatformProperties{
+		"amd64":  {Os: containerregistry.Linux, Architecture: containerregistry.Amd64},
+		"386":    {Os: containerregistry.Linux, Architecture: containerregistry.X86},
+		"arm":    {Os: containerregistry.Linux, Architecture: containerregistry.Arm},
+		"arm/v7": {Os: containerregistry.Linux, Architecture: containerregistry.Arm, Variant: containerregistry.V7},
+		"arm64":  {Os: containerregistry.Linux, Architecture: containerregistry.Arm, Variant: containerregistry.V8},
+	}
+	for architecture, expected := range cases {
+		parts := strings.SplitN(architecture, "/", 2)
+		platform := specs.Platform{OS: "linux", Architecture: parts[0]}
+		if len(parts) > 1 {
+			platform.Variant = parts[1]
+		}
+		actual, err := acrPlatform(platform)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, *actual, expected)
+	}
+
+	_, err := acrPlatform(specs.Platform{OS: "windows", Architecture: "amd64"})
+	assert.ErrorContains(t, err, "ACR builds are not supported for platform windows/amd64")
+	_, err = acrPlatform(specs.Platform{OS: "linux", Architecture: "s390x"})
+	assert.ErrorContains(t, err, "ACR builds are not supported for platform linux/s390x")
+}
+
+func TestUploadBuildContext(t *testing.T) {
+	dir := fs.NewDir(t, "context", fs.WithFile("Dockerfile", "FROM nginx\n"))
+	defer dir.Remove()
+
+	var uploaded int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodPut)
+		assert.Equal(t, r.Header.Get("x-ms-blob-type"), "BlockBlob")
+		assert.Assert(t, len(r.TransferEncoding) == 0, "build context must not be uploaded chunked")
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NilError(t, err)
+		assert.Equal(t, r.ContentLength, int64(len(body)))
+		uploaded = r.ContentLength
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	err := uploadBuildContext(context.TODO(), server.URL+"/source.tar.gz", dir.Path())
+	assert.NilError(t, err)
+	assert.Assert(t, uploaded > 0)
+}
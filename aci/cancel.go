@@ -0,0 +1,53 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/go-autorest/autorest/to"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/progress"
+)
+
+// canceledDeployment handles a deployment canceled by the user while Azure was creating or updating the container group.
+// ctx must be detached from the canceled command context.
+func (cs *aciComposeService) canceledDeployment(ctx context.Context, name string, created bool, cleanup bool) error {
+	if cleanup && created {
+		w := progress.ContextWriter(ctx)
+		w.Event(progress.Event{ID: "Group " + name, Status: progress.Working, StatusText: "Deleting canceled group"})
+		if _, err := deleteACIContainerGroup(ctx, cs.ctx, name); err != nil {
+			return err
+		}
+		w.Event(progress.Event{ID: "Group " + name, Status: progress.Done, StatusText: "Cleaned up"})
+		return compose.CanceledError{Operation: "deployment", CleanedUp: true}
+	}
+
+	state := "unknown state"
+	if group, err := getACIContainerGroup(ctx, cs.ctx, name); err == nil {
+		state = to.String(group.ProvisioningState)
+	}
+	resource := fmt.Sprintf("container group %q (%s), run `docker compose up --resume` to resume its deployment "+
+		"or `docker compose down` to delete it", name, state)
+	if cleanup {
+		// Azure doesn't roll back container group updates
+		resource = fmt.Sprintf("container group %q (%s), updates can't be rolled back", name, state)
+	}
+	return compose.CanceledError{Operation: "deployment", Resources: []string{resource}}
+}
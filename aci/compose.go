@@ -19,10 +19,14 @@ package aci
 import (
 	"context"
 	"fmt"
-	"io"
 	"net/http"
+	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
 	"github.com/docker/compose-cli/aci/convert"
@@ -30,47 +34,291 @@ import (
 	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/context/store"
 	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/progress"
+	"github.com/docker/compose-cli/registry"
+	"github.com/docker/compose-cli/utils/dockercli"
 )
 
 type aciComposeService struct {
 	ctx          store.AciContext
 	storageLogin login.StorageLoginImpl
+	groups       *groupPoller
 }
 
 func newComposeService(ctx store.AciContext) aciComposeService {
 	return aciComposeService{
 		ctx:          ctx,
 		storageLogin: login.StorageLoginImpl{AciContext: ctx},
+		groups:       newGroupPoller(ctx),
 	}
 }
 
-func (cs *aciComposeService) Up(ctx context.Context, project *types.Project) error {
+func (cs *aciComposeService) Up(ctx context.Context, project *types.Project, options compose.UpOptions) error {
+	return permissionError("up", cs.up(ctx, project, options))
+}
+
+func (cs *aciComposeService) up(ctx context.Context, project *types.Project, options compose.UpOptions) error {
 	logrus.Debugf("Up on project with name %q", project.Name)
-	groupDefinition, err := convert.ToContainerGroup(ctx, cs.ctx, *project, cs.storageLogin)
+	detectWindowsImages(ctx, project)
+	if err := checkJobDependencies(project); err != nil {
+		return err
+	}
+	if err := cs.preflight(ctx, project, options); err != nil {
+		return err
+	}
+	if err := compose.ApplyEnvFrom(ctx, project, appConfigScheme, pullAppConfig); err != nil {
+		return err
+	}
+	tags, err := cs.ctx.TagPolicy.Apply(options.Tags)
+	if err != nil {
+		return err
+	}
+	if !options.OverrideBudget {
+		if err := checkBudget(ctx, cs.ctx, *project); err != nil {
+			return err
+		}
+	}
+	switch options.Builder {
+	case "":
+	case BuilderACR:
+		if err := cs.buildWithACR(ctx, project); err != nil {
+			return err
+		}
+	default:
+		return errors.Wrapf(errdefs.ErrNotImplemented, "builder %q", options.Builder)
+	}
+	if err := registry.CheckPlatforms(ctx, project, targetPlatform); err != nil {
+		return err
+	}
+	if options.ResolveImageDigests || cs.ctx.ResolveImageDigests {
+		if err := registry.PinImages(ctx, project); err != nil {
+			return err
+		}
+	}
+	// jobs and scheduled services run in their own container group
+	group := groupProject(project)
+	if err := cs.deploySchedules(ctx, project, tags); err != nil {
+		return err
+	}
+	if len(group.Services) == 0 {
+		return nil
+	}
+	policy, err := groupUpdatePolicy(group)
+	if err != nil {
+		return err
+	}
+	domains, err := projectDomains(group, cs.ctx.ResourceGroup)
+	if err != nil {
+		return err
+	}
+	if err := cs.addDomainCertificates(ctx, group, domains); err != nil {
+		return err
+	}
+	diagnostics, err := cs.groupDiagnostics(ctx, project.Name, *group)
+	if err != nil {
+		return err
+	}
+	groupDefinition, err := convert.ToContainerGroup(ctx, cs.ctx, *group, cs.storageLogin)
 	addTag(&groupDefinition, composeContainerTag)
 
 	if err != nil {
 		return err
 	}
-	return createOrUpdateACIContainers(ctx, cs.ctx, groupDefinition)
+	groupDefinition.Diagnostics = diagnostics
+	groupDefinition.Tags[compose.ProjectTag] = to.StringPtr(project.Name)
+	tagDomains(&groupDefinition, domains)
+	for k, v := range tags {
+		groupDefinition.Tags[k] = to.StringPtr(v)
+	}
+	// hashes of jobs are kept so that a job change redeploys the group, running the job again
+	hashes := map[string]string{}
+	digests := registry.ResolveImageDigests(ctx, project)
+	for _, service := range project.Services {
+		hash, err := compose.ServiceHash(service, digests)
+		if err != nil {
+			return err
+		}
+		hashes[service.Name] = hash
+		groupDefinition.Tags[configHashTag(service.Name)] = to.StringPtr(hash)
+	}
+
+	if err := cs.labelProjectVolumes(ctx, project); err != nil {
+		return err
+	}
+
+	existing, err := cs.reconcileContainerGroup(ctx, project.Name, options.Resume)
+	if err == nil {
+		switch options.Recreate {
+		case compose.RecreateNever:
+			for service := range hashes {
+				if _, ok := existing.Tags[configHashTag(service)]; !ok {
+					logrus.Warnf("service %q can't be added to existing container group %q without recreating it", service, project.Name)
+				}
+			}
+			upToDate(ctx, group, "Running")
+			return nil
+		case compose.RecreateForce:
+		default:
+			if sameHashes(existing, hashes) {
+				upToDate(ctx, group, "Up to date")
+				return nil
+			}
+		}
+	} else if !isNotFound(err) {
+		return err
+	}
+
+	if len(compose.Jobs(project)) > 0 {
+		if err := cs.runJobs(ctx, project, tags); err != nil {
+			return err
+		}
+	}
+	reason, err := cs.deployContainerGroup(ctx, existing, groupDefinition)
+	if err == nil && existing.ID != nil && policy.monitor > 0 {
+		err = cs.monitorGroup(ctx, project.Name, policy.monitor)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return cs.canceledDeployment(compose.Detach(ctx), project.Name, existing.ID == nil, options.CancelCleanup)
+		}
+		if policy.rollback && existing.ID != nil {
+			keepWorkspaceKey(&existing, groupDefinition)
+			return cs.rollbackGroup(ctx, existing, err)
+		}
+		return err
+	}
+	reportDeployment(ctx, groupDefinition, existing, reason)
+	if err := cs.createDomainRecords(ctx, group, domains, existing); err != nil {
+		return err
+	}
+	if err := cs.updatePrivateEndpoints(ctx, project.Name); err != nil {
+		return err
+	}
+	if options.Recreate == compose.RecreateForce && existing.ID != nil && reason == "" {
+		// unchanged containers are kept running by an update, restart them all
+		return restartACIContainerGroup(ctx, cs.ctx, project.Name)
+	}
+	return nil
+}
+
+// reportDeployment tells whether the container group was created, updated in place or recreated
+func reportDeployment(ctx context.Context, definition, existing containerinstance.ContainerGroup, reason string) {
+	status := "Created"
+	switch {
+	case reason != "":
+		status = "Recreated, " + reason
+	case existing.ID != nil:
+		status = "Updated in place"
+	}
+	progress.ContextWriter(ctx).Event(progress.Event{
+		ID:         "Group " + to.String(definition.Name),
+		Status:     progress.Done,
+		StatusText: status,
+	})
 }
 
-func (cs *aciComposeService) Down(ctx context.Context, project string) error {
+func configHashTag(service string) string {
+	return compose.ConfigHashTag + "." + service
+}
+
+// sameHashes checks the deployed container group runs exactly the project services, with the same configuration
+func sameHashes(group containerinstance.ContainerGroup, hashes map[string]string) bool {
+	deployed := 0
+	for tag := range group.Tags {
+		if strings.HasPrefix(tag, compose.ConfigHashTag+".") {
+			deployed++
+		}
+	}
+	if deployed != len(hashes) {
+		return false
+	}
+	for service, hash := range hashes {
+		if to.String(group.Tags[configHashTag(service)]) != hash {
+			return false
+		}
+	}
+	return true
+}
+
+func upToDate(ctx context.Context, project *types.Project, status string) {
+	w := progress.ContextWriter(ctx)
+	for _, service := range project.Services {
+		w.Event(progress.Event{
+			ID:         service.Name,
+			Status:     progress.Done,
+			StatusText: status,
+		})
+	}
+}
+
+func isNotFound(err error) bool {
+	if detailed, ok := err.(autorest.DetailedError); ok {
+		return detailed.StatusCode == http.StatusNotFound
+	}
+	return false
+}
+
+func (cs *aciComposeService) Down(ctx context.Context, project string, options compose.DownOptions) error {
+	return permissionError("down", cs.down(ctx, project, options))
+}
+
+func (cs *aciComposeService) down(ctx context.Context, project string, options compose.DownOptions) error {
 	logrus.Debugf("Down on project with name %q", project)
 
+	var images []string
+	if options.RemoveImages == compose.RemoveImagesAll {
+		group, err := getACIContainerGroup(ctx, cs.ctx, project)
+		if err != nil && !isNotFound(err) {
+			return err
+		}
+		images = groupImages(group)
+	}
+
+	schedules, err := cs.removeSchedules(ctx, project, nil)
+	if err != nil {
+		return err
+	}
 	cg, err := deleteACIContainerGroup(ctx, cs.ctx, project)
 	if err != nil {
 		return err
 	}
-	if cg.StatusCode == http.StatusNoContent {
+	if err := cs.removeDomainRecords(ctx, cg, nil); err != nil {
+		return err
+	}
+	if err := cs.removePrivateEndpoints(ctx, project); err != nil {
+		return err
+	}
+	if cg.StatusCode == http.StatusNoContent && schedules == 0 && !options.RemoveOrphans && !options.RemoveVolumes && options.RemoveImages == "" {
 		return errdefs.ErrNotFound
 	}
 
-	return err
+	if options.RemoveOrphans {
+		if err := cs.removeOrphans(ctx, project); err != nil {
+			return err
+		}
+	}
+	if options.RemoveVolumes {
+		if err := cs.removeVolumes(ctx, project); err != nil {
+			return err
+		}
+	}
+	if options.RemoveImages != "" {
+		built, err := dockercli.ProjectImages(ctx, project)
+		if err != nil {
+			return err
+		}
+		return dockercli.RemoveImages(ctx, append(built, images...))
+	}
+	return nil
 }
 
 func (cs *aciComposeService) Ps(ctx context.Context, project string) ([]compose.ServiceStatus, error) {
-	groupsClient, err := login.NewContainerGroupsClient(cs.ctx.SubscriptionID)
+	status, err := cs.ps(ctx, project)
+	return status, permissionError("ps", err)
+}
+
+func (cs *aciComposeService) ps(ctx context.Context, project string) ([]compose.ServiceStatus, error) {
+	groupsClient, err := login.NewContainerGroupsClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
 	if err != nil {
 		return nil, err
 	}
@@ -86,7 +334,8 @@ func (cs *aciComposeService) Ps(ctx context.Context, project string) ([]compose.
 
 	res := []compose.ServiceStatus{}
 	for _, container := range *group.Containers {
-		if isContainerVisible(container, group, false) {
+		// containers which aren't running are listed too, with no replica, so that restarting services show up
+		if isContainerVisible(container, group, true) {
 			continue
 		}
 		res = append(res, convert.ContainerGroupToServiceStatus(getContainerID(group, container), group, container, cs.ctx.Location))
@@ -95,7 +344,7 @@ func (cs *aciComposeService) Ps(ctx context.Context, project string) ([]compose.
 }
 
 func (cs *aciComposeService) List(ctx context.Context, project string) ([]compose.Stack, error) {
-	containerGroups, err := getACIContainerGroups(ctx, cs.ctx.SubscriptionID, cs.ctx.ResourceGroup)
+	containerGroups, err := getACIContainerGroups(ctx, cs.ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -125,8 +374,49 @@ func (cs *aciComposeService) List(ctx context.Context, project string) ([]compos
 	return stacks, nil
 }
 
-func (cs *aciComposeService) Logs(ctx context.Context, project string, w io.Writer) error {
-	return errdefs.ErrNotImplemented
+// Logs returns the logs of the project containers up to now, ACI keeping the logs of the current container instances
+// only. The logs of groups sending them to Log Analytics are queried from the workspace, including previous containers.
+func (cs *aciComposeService) Logs(ctx context.Context, project string, consumer compose.LogConsumer, options compose.LogOptions) error {
+	if options.Export != "" || options.Follow() {
+		return errors.Wrap(errdefs.ErrNotImplemented, "ACI only returns the logs of current containers, up to now with --until")
+	}
+	groupsClient, err := login.NewContainerGroupsClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
+	if err != nil {
+		return err
+	}
+	group, err := groupsClient.Get(ctx, cs.ctx.ResourceGroup, project)
+	if err != nil {
+		return permissionError("logs", err)
+	}
+	if workspace, _ := groupWorkspace(group); workspace != "" {
+		return permissionError("logs", cs.queryLogs(ctx, group, consumer, options))
+	}
+	if options.Previous {
+		return errors.Wrapf(errdefs.ErrNotImplemented, "ACI only returns the logs of current containers, unless logs are sent to Log Analytics with the %s logging driver", convert.LogAnalyticsDriver)
+	}
+	if group.Containers == nil {
+		return nil
+	}
+	for _, container := range *group.Containers {
+		if isContainerVisible(container, group, true) {
+			continue
+		}
+		logs, err := getACIContainerLogs(ctx, cs.ctx, project, *container.Name, nil)
+		if err != nil {
+			return permissionError("logs", err)
+		}
+		if logs == "" {
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimRight(logs, "\n"), "\n") {
+			consumer.Log(compose.LogEvent{
+				Service:   *container.Name,
+				Container: getContainerID(group, container),
+				Line:      line,
+			})
+		}
+	}
+	return nil
 }
 
 func (cs *aciComposeService) Convert(ctx context.Context, project *types.Project) ([]byte, error) {
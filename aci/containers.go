@@ -49,7 +49,7 @@ func newContainerService(ctx store.AciContext) aciContainerService {
 }
 
 func (cs *aciContainerService) List(ctx context.Context, all bool) ([]containers.Container, error) {
-	containerGroups, err := getACIContainerGroups(ctx, cs.ctx.SubscriptionID, cs.ctx.ResourceGroup)
+	containerGroups, err := getACIContainerGroups(ctx, cs.ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -79,6 +79,16 @@ func (cs *aciContainerService) Run(ctx context.Context, r containers.ContainerCo
 	if err != nil {
 		return err
 	}
+	detectWindowsImages(ctx, &project)
+	if r.Interactive && convert.IsWindowsPlatform(project.Services[0].Platform) {
+		return errors.Wrap(errdefs.ErrNotImplemented, "interactive sessions aren't supported on ACI Windows containers")
+	}
+
+	if !r.OverrideBudget {
+		if err := checkBudget(ctx, cs.ctx, project); err != nil {
+			return err
+		}
+	}
 
 	logrus.Debugf("Running container %q with name %q", r.Image, r.ID)
 	groupDefinition, err := convert.ToContainerGroup(ctx, cs.ctx, project, cs.storageLogin)
@@ -97,7 +107,7 @@ func (cs *aciContainerService) Start(ctx context.Context, containerID string) er
 		return fmt.Errorf(msg, containerName, groupName, groupName)
 	}
 
-	containerGroupsClient, err := login.NewContainerGroupsClient(cs.ctx.SubscriptionID)
+	containerGroupsClient, err := login.NewContainerGroupsClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
 	if err != nil {
 		return err
 	}
@@ -143,6 +153,13 @@ func (cs *aciContainerService) Exec(ctx context.Context, name string, request co
 		return err
 	}
 	groupName, containerAciName := getGroupAndContainerName(name)
+	group, err := getACIContainerGroup(ctx, cs.ctx, groupName)
+	if err != nil {
+		return err
+	}
+	if convert.IsWindowsGroup(group) {
+		return errors.Wrapf(errdefs.ErrNotImplemented, "exec isn't supported on ACI Windows container %q", name)
+	}
 	containerExecResponse, err := execACIContainer(ctx, cs.ctx, request.Command, groupName, containerAciName)
 	if err != nil {
 		return err
@@ -199,7 +216,7 @@ func (cs *aciContainerService) Delete(ctx context.Context, containerID string, r
 	}
 
 	if !request.Force {
-		containerGroupsClient, err := login.NewContainerGroupsClient(cs.ctx.SubscriptionID)
+		containerGroupsClient, err := login.NewContainerGroupsClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
 		if err != nil {
 			return err
 		}
@@ -38,6 +38,16 @@ type ContextParams struct {
 	Location       string
 	SubscriptionID string
 	ResourceGroup  string
+
+	ResolveImageDigests bool
+	TracingEndpoint     string
+	ScanSeverity        string
+	Operations          store.OperationSettings
+	Budget              store.Budget
+	TagPolicy           store.TagPolicy
+	Annotations         store.DeploymentAnnotations
+	Notifications       store.Notifications
+	PrivateNetwork      store.AciPrivateNetwork
 }
 
 // ErrSubscriptionNotFound is returned when a required subscription is not found
@@ -110,6 +120,16 @@ func (helper contextCreateACIHelper) createContextData(ctx context.Context, opts
 		SubscriptionID: subscriptionID,
 		Location:       location,
 		ResourceGroup:  *group.Name,
+
+		ResolveImageDigests:   opts.ResolveImageDigests,
+		TracingEndpoint:       opts.TracingEndpoint,
+		ScanSeverity:          opts.ScanSeverity,
+		Budget:                opts.Budget,
+		TagPolicy:             opts.TagPolicy,
+		DeploymentAnnotations: opts.Annotations,
+		Notifications:         opts.Notifications,
+		AciPrivateNetwork:     opts.PrivateNetwork,
+		OperationSettings:     opts.Operations,
 	}, description, nil
 }
 
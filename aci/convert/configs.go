@@ -0,0 +1,108 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package convert
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"sort"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// getAciConfigVolumes creates a secret volume per folder configs are mounted in by a service, holding the content of
+// these configs under their target file names
+func (p projectAciHelper) getAciConfigVolumes() ([]containerinstance.Volume, error) {
+	var configVolumes []containerinstance.Volume
+	for _, service := range p.Services {
+		folders, err := configFolders(service)
+		if err != nil {
+			return nil, err
+		}
+		for i, folder := range sortedFolders(folders) {
+			files := map[string]*string{}
+			for _, config := range folders[folder] {
+				configObj := p.Configs[config.Source]
+				if configObj.External.External {
+					return nil, errors.Wrapf(errdefs.ErrNotImplemented, "config %q: external configs are not supported by ACI", config.Source)
+				}
+				data, err := ioutil.ReadFile(configObj.File)
+				if err != nil {
+					return nil, err
+				}
+				target, _ := compose.ConfigTarget(config)
+				files[path.Base(target)] = to.StringPtr(base64.StdEncoding.EncodeToString(data))
+			}
+			configVolumes = append(configVolumes, containerinstance.Volume{
+				Name:   to.StringPtr(configVolume(service.Name, i)),
+				Secret: files,
+			})
+		}
+	}
+	return configVolumes, nil
+}
+
+func (s serviceConfigAciHelper) getAciConfigVolumeMounts() ([]containerinstance.VolumeMount, error) {
+	folders, err := configFolders(types.ServiceConfig(s))
+	if err != nil {
+		return nil, err
+	}
+	var mounts []containerinstance.VolumeMount
+	for i, folder := range sortedFolders(folders) {
+		mounts = append(mounts, containerinstance.VolumeMount{
+			Name:      to.StringPtr(configVolume(s.Name, i)),
+			MountPath: to.StringPtr(folder),
+			ReadOnly:  to.BoolPtr(true),
+		})
+	}
+	return mounts, nil
+}
+
+// configFolders groups the configs of a service by the folder they are mounted in
+func configFolders(service types.ServiceConfig) (map[string][]types.ServiceConfigObjConfig, error) {
+	folders := map[string][]types.ServiceConfigObjConfig{}
+	for _, config := range service.Configs {
+		target, err := compose.ConfigTarget(config)
+		if err != nil {
+			return nil, err
+		}
+		folder := path.Dir(target)
+		folders[folder] = append(folders[folder], config)
+	}
+	return folders, nil
+}
+
+func sortedFolders(folders map[string][]types.ServiceConfigObjConfig) []string {
+	var sorted []string
+	for folder := range folders {
+		sorted = append(sorted, folder)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+func configVolume(service string, index int) string {
+	return fmt.Sprintf("%s-configs-%d", service, index)
+}
@@ -25,6 +25,13 @@ import (
 	"github.com/docker/compose-cli/api/containers"
 )
 
+// keepAliveCommand keeps interactive containers running until a session is attached, as ACI can't allocate a TTY
+var keepAliveCommand = types.ShellCommand{"/bin/sh", "-c", "while true; do sleep 3600; done"}
+
+// commandExtension marks the services generated by the CLI which command is sent to ACI. An ACI command replaces
+// the image entrypoint, which the command of a compose service doesn't, so it is not sent for compose services
+const commandExtension = "x-aci-command"
+
 // ContainerToComposeProject convert container config to compose project
 func ContainerToComposeProject(r containers.ContainerConfig) (types.Project, error) {
 	var ports []types.ServicePortConfig
@@ -50,6 +57,7 @@ func ContainerToComposeProject(r containers.ContainerConfig) (types.Project, err
 				Labels:      r.Labels,
 				Volumes:     serviceConfigVolumes,
 				DomainName:  r.DomainName,
+				Platform:    r.Platform,
 				Environment: toComposeEnvs(r.Environment),
 				Deploy: &types.DeployConfig{
 					Resources: types.Resources{
@@ -66,6 +74,10 @@ func ContainerToComposeProject(r containers.ContainerConfig) (types.Project, err
 		},
 		Volumes: projectVolumes,
 	}
+	if r.Interactive {
+		project.Services[0].Command = keepAliveCommand
+		project.Services[0].Extensions = map[string]interface{}{commandExtension: true}
+	}
 	return project, nil
 }
 
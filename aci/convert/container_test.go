@@ -66,6 +66,16 @@ func TestConvertDomainName(t *testing.T) {
 	assert.Equal(t, service1.DomainName, "myapp")
 }
 
+func TestConvertInteractive(t *testing.T) {
+	container := containers.ContainerConfig{
+		ID:          "container1",
+		Interactive: true,
+	}
+	project, err := ContainerToComposeProject(container)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, project.Services[0].Command, keepAliveCommand)
+}
+
 func TestConvertEnvVariables(t *testing.T) {
 	container := containers.ContainerConfig{
 		ID: "container1",
@@ -23,8 +23,10 @@ import (
 	"io/ioutil"
 	"math"
 	"os"
+	"path"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
 	"github.com/Azure/go-autorest/autorest/to"
@@ -35,6 +37,7 @@ import (
 	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/api/containers"
 	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/errdefs"
 	"github.com/docker/compose-cli/utils/formatter"
 )
 
@@ -64,7 +67,19 @@ func ToContainerGroup(ctx context.Context, aciContext store.AciContext, p types.
 	if err != nil {
 		return containerinstance.ContainerGroup{}, err
 	}
+	configVolumes, err := project.getAciConfigVolumes()
+	if err != nil {
+		return containerinstance.ContainerGroup{}, err
+	}
 	allVolumes := append(volumesSlice, secretVolumes...)
+	allVolumes = append(allVolumes, configVolumes...)
+	daprVolume, err := daprComponentsVolumeOf(p)
+	if err != nil {
+		return containerinstance.ContainerGroup{}, err
+	}
+	if daprVolume != nil {
+		allVolumes = append(allVolumes, *daprVolume)
+	}
 	var volumes *[]containerinstance.Volume
 	if len(allVolumes) > 0 {
 		volumes = &allVolumes
@@ -80,18 +95,37 @@ func ToContainerGroup(ctx context.Context, aciContext store.AciContext, p types.
 	if err != nil {
 		return containerinstance.ContainerGroup{}, err
 	}
+	osType, err := GroupOSType(p)
+	if err != nil {
+		return containerinstance.ContainerGroup{}, err
+	}
+	zone, err := GroupZone(ctx, p)
+	if err != nil {
+		return containerinstance.ContainerGroup{}, err
+	}
 	groupDefinition := containerinstance.ContainerGroup{
 		Name:     &containerGroupName,
 		Location: &aciContext.Location,
 		ContainerGroupProperties: &containerinstance.ContainerGroupProperties{
-			OsType:                   containerinstance.Linux,
+			OsType:                   osType,
 			Containers:               &containers,
 			Volumes:                  volumes,
 			ImageRegistryCredentials: &registryCreds,
 			RestartPolicy:            restartPolicy,
 		},
 	}
+	if zone != "" {
+		groupDefinition.Tags = map[string]*string{ZoneTag: &zone}
+	}
+	if aciContext.Private() {
+		if groupDefinition.Tags == nil {
+			groupDefinition.Tags = map[string]*string{}
+		}
+		groupDefinition.Tags[SubnetTag] = to.StringPtr(aciContext.SubnetID)
+	}
 
+	warnNetworkIsolation(ctx, p)
+	warnStopSettings(ctx, p)
 	var groupPorts []containerinstance.Port
 	var dnsLabelName *string
 	for _, s := range project.Services {
@@ -100,6 +134,15 @@ func ToContainerGroup(ctx context.Context, aciContext store.AciContext, p types.
 		if err != nil {
 			return containerinstance.ContainerGroup{}, err
 		}
+		secretVariables, err := project.getAciSecretEnvironment(s)
+		if err != nil {
+			return containerinstance.ContainerGroup{}, err
+		}
+		secretVariables = append(secretVariables, daprEnvironment(p, s)...)
+		if len(secretVariables) > 0 {
+			variables := append(*containerDefinition.EnvironmentVariables, secretVariables...)
+			containerDefinition.EnvironmentVariables = &variables
+		}
 		if service.Labels != nil && len(service.Labels) > 0 {
 			return containerinstance.ContainerGroup{}, errors.New("ACI integration does not support labels in compose applications")
 		}
@@ -109,7 +152,15 @@ func ToContainerGroup(ctx context.Context, aciContext store.AciContext, p types.
 			return groupDefinition, err
 		}
 		containerDefinition.ContainerProperties.Ports = &containerPorts
-		groupPorts = append(groupPorts, serviceGroupPorts...)
+		if isInternal(p, s) {
+			serviceGroupPorts = nil
+		}
+		// only ports published by services are opened on the group public IP, once even if several services publish them
+		for _, port := range serviceGroupPorts {
+			if !hasGroupPort(groupPorts, port) {
+				groupPorts = append(groupPorts, port)
+			}
+		}
 		if serviceDomainName != nil {
 			if dnsLabelName != nil && *serviceDomainName != *dnsLabelName {
 				return containerinstance.ContainerGroup{}, fmt.Errorf("ACI integration does not support specifying different domain names on services in the same compose application")
@@ -118,10 +169,23 @@ func ToContainerGroup(ctx context.Context, aciContext store.AciContext, p types.
 		}
 
 		containers = append(containers, containerDefinition)
+
+		sidecars, err := project.getAciSidecars(s, volumesCache)
+		if err != nil {
+			return containerinstance.ContainerGroup{}, err
+		}
+		containers = append(containers, sidecars...)
 	}
 	if len(groupPorts) > 0 {
+		ipType := containerinstance.Public
+		if aciContext.Private() {
+			if dnsLabelName != nil {
+				return containerinstance.ContainerGroup{}, errors.New("ACI integration does not support domain names on private deployments, use a private DNS zone instead")
+			}
+			ipType = containerinstance.Private
+		}
 		groupDefinition.ContainerGroupProperties.IPAddress = &containerinstance.IPAddress{
-			Type:         containerinstance.Public,
+			Type:         ipType,
 			Ports:        &groupPorts,
 			DNSNameLabel: dnsLabelName,
 		}
@@ -135,6 +199,27 @@ func ToContainerGroup(ctx context.Context, aciContext store.AciContext, p types.
 	return groupDefinition, nil
 }
 
+// warnStopSettings warns about the stop settings of services ACI can't honor, rather than silently ignoring them
+func warnStopSettings(ctx context.Context, project types.Project) {
+	for _, service := range project.Services {
+		if service.StopSignal != "" {
+			compose.Warn(ctx, "service %s stop_signal %s is ignored, ACI doesn't support custom stop signals", service.Name, service.StopSignal)
+		}
+		if service.StopGracePeriod != nil {
+			compose.Warn(ctx, "service %s stop_grace_period is ignored, ACI doesn't support stop timeouts", service.Name)
+		}
+	}
+}
+
+func hasGroupPort(ports []containerinstance.Port, port containerinstance.Port) bool {
+	for _, p := range ports {
+		if *p.Port == *port.Port && p.Protocol == port.Protocol {
+			return true
+		}
+	}
+	return false
+}
+
 func convertPortsToAci(service serviceConfigAciHelper) ([]containerinstance.ContainerPort, []containerinstance.Port, *string, error) {
 	var groupPorts []containerinstance.Port
 	var containerPorts []containerinstance.ContainerPort
@@ -144,13 +229,18 @@ func convertPortsToAci(service serviceConfigAciHelper) ([]containerinstance.Cont
 				portConfig.Published, portConfig.Target, service.Name)
 			return nil, nil, nil, errors.New(msg)
 		}
+		protocol := containerinstance.TCP
+		if strings.EqualFold(portConfig.Protocol, "udp") {
+			protocol = containerinstance.UDP
+		}
 		portNumber := int32(portConfig.Target)
 		containerPorts = append(containerPorts, containerinstance.ContainerPort{
-			Port: to.Int32Ptr(portNumber),
+			Port:     to.Int32Ptr(portNumber),
+			Protocol: containerinstance.ContainerNetworkProtocol(protocol),
 		})
 		groupPorts = append(groupPorts, containerinstance.Port{
 			Port:     to.Int32Ptr(portNumber),
-			Protocol: containerinstance.TCP,
+			Protocol: protocol,
 		})
 	}
 	var dnsLabelName *string = nil
@@ -191,36 +281,121 @@ func getDNSSidecar(containers []containerinstance.Container) containerinstance.C
 
 type projectAciHelper types.Project
 
+// getAciSecretVolumes creates a secret volume for each secret mounted as a file by a service. Secrets targeting an
+// absolute path get a dedicated volume exposing the secret under the target file name
 func (p projectAciHelper) getAciSecretVolumes() ([]containerinstance.Volume, error) {
 	var secretVolumes []containerinstance.Volume
-	for secretName, filepathToRead := range p.Secrets {
-		var data []byte
-		if strings.HasPrefix(filepathToRead.File, secretInlineMark) {
-			data = []byte(filepathToRead.File[len(secretInlineMark):])
-		} else {
-			var err error
-			data, err = ioutil.ReadFile(filepathToRead.File)
+	created := map[string]bool{}
+	for _, service := range p.Services {
+		injection, err := compose.SecretInjection(service)
+		if err != nil {
+			return nil, err
+		}
+		if injection != compose.SecretInjectionFile {
+			continue
+		}
+		for _, secret := range service.Secrets {
+			name, file := secretVolume(service.Name, secret)
+			if created[name] {
+				continue
+			}
+			data, err := readSecret(p.Secrets[secret.Source])
 			if err != nil {
 				return secretVolumes, err
 			}
+			if len(data) == 0 {
+				continue
+			}
+			created[name] = true
+			dataStr := base64.StdEncoding.EncodeToString(data)
+			secretVolumes = append(secretVolumes, containerinstance.Volume{
+				Name: to.StringPtr(name),
+				Secret: map[string]*string{
+					file: &dataStr,
+				},
+			})
 		}
-		if len(data) == 0 {
-			continue
+	}
+	return secretVolumes, nil
+}
+
+// getAciSidecars converts the x-sidecars auxiliary containers and Dapr sidecar injected into a service, named after
+// the service as they share the container group with other services and their sidecars
+func (p projectAciHelper) getAciSidecars(service types.ServiceConfig, volumesCache map[string]bool) ([]containerinstance.Container, error) {
+	sidecars, err := groupSidecars(types.Project(p), service)
+	if err != nil {
+		return nil, err
+	}
+	var containers []containerinstance.Container
+	for _, sidecar := range sidecars {
+		dapr := sidecar.Name == daprSidecarName
+		sidecar.Name = fmt.Sprintf("%s-%s", service.Name, sidecar.Name)
+		container, err := serviceConfigAciHelper(sidecar).getAciContainer(volumesCache)
+		if err != nil {
+			return nil, err
 		}
-		dataStr := base64.StdEncoding.EncodeToString(data)
-		secretVolumes = append(secretVolumes, containerinstance.Volume{
-			Name: to.StringPtr(secretName),
-			Secret: map[string]*string{
-				secretName: &dataStr,
-			},
+		if _, ok := p.Extensions[DaprExtension]; ok && dapr {
+			container.VolumeMounts = &[]containerinstance.VolumeMount{{
+				Name:      to.StringPtr(daprComponentsVolume),
+				MountPath: to.StringPtr(daprComponentsPath),
+				ReadOnly:  to.BoolPtr(true),
+			}}
+		}
+		containers = append(containers, container)
+	}
+	return containers, nil
+}
+
+// getAciSecretEnvironment returns the secrets of a service injected as secure environment variables
+func (p projectAciHelper) getAciSecretEnvironment(service types.ServiceConfig) ([]containerinstance.EnvironmentVariable, error) {
+	injection, err := compose.SecretInjection(service)
+	if err != nil {
+		return nil, err
+	}
+	if injection != compose.SecretInjectionEnv {
+		return nil, nil
+	}
+	var variables []containerinstance.EnvironmentVariable
+	for _, secret := range service.Secrets {
+		data, err := readSecret(p.Secrets[secret.Source])
+		if err != nil {
+			return nil, err
+		}
+		variables = append(variables, containerinstance.EnvironmentVariable{
+			Name:        to.StringPtr(compose.SecretEnvName(secret)),
+			SecureValue: to.StringPtr(string(data)),
 		})
 	}
-	return secretVolumes, nil
+	return variables, nil
+}
+
+// InlineSecret returns a secret holding its data, to mount content which isn't read from a file into containers
+func InlineSecret(data []byte) types.SecretConfig {
+	return types.SecretConfig{File: secretInlineMark + string(data)}
+}
+
+func readSecret(secret types.SecretConfig) ([]byte, error) {
+	if strings.HasPrefix(secret.File, secretInlineMark) {
+		return []byte(secret.File[len(secretInlineMark):]), nil
+	}
+	return ioutil.ReadFile(secret.File)
+}
+
+// secretVolume returns the name of the volume a service secret is mounted from, and the secret file name in this volume
+func secretVolume(service string, secret types.ServiceSecretConfig) (string, string) {
+	if path.IsAbs(secret.Target) {
+		return fmt.Sprintf("%s-%s", service, secret.Source), path.Base(secret.Target)
+	}
+	return secret.Source, secret.Source
 }
 
 func (p projectAciHelper) getAciFileVolumes(ctx context.Context, helper login.StorageLogin) (map[string]bool, []containerinstance.Volume, error) {
 	azureFileVolumesMap := make(map[string]bool, len(p.Volumes))
 	var azureFileVolumesSlice []containerinstance.Volume
+	accountKeys, err := p.getStorageAccountKeys(ctx, helper)
+	if err != nil {
+		return nil, nil, err
+	}
 	for name, v := range p.Volumes {
 		if v.Driver == azureFileDriverName {
 			shareName, ok := v.DriverOpts[volumeDriveroptsShareNameKey]
@@ -239,10 +414,7 @@ func (p projectAciHelper) getAciFileVolumes(ctx context.Context, helper login.St
 			if err != nil {
 				return nil, nil, fmt.Errorf("invalid mode %q for volume", readOnly)
 			}
-			accountKey, err := helper.GetAzureStorageAccountKey(ctx, accountName)
-			if err != nil {
-				return nil, nil, err
-			}
+			accountKey := accountKeys[accountName]
 			aciVolume := containerinstance.Volume{
 				Name: to.StringPtr(name),
 				AzureFile: &containerinstance.AzureFileVolume{
@@ -259,12 +431,54 @@ func (p projectAciHelper) getAciFileVolumes(ctx context.Context, helper login.St
 	return azureFileVolumesMap, azureFileVolumesSlice, nil
 }
 
+// AzureFileShare returns the storage account and file share an Azure file volume is bound to
+func AzureFileShare(v types.VolumeConfig) (string, string, bool) {
+	if v.Driver != azureFileDriverName {
+		return "", "", false
+	}
+	accountName, ok := v.DriverOpts[volumeDriveroptsAccountNameKey]
+	if !ok {
+		return "", "", false
+	}
+	shareName, ok := v.DriverOpts[volumeDriveroptsShareNameKey]
+	return accountName, shareName, ok
+}
+
+// getStorageAccountKeys concurrently retrieves the keys of storage accounts used by Azure file volumes
+func (p projectAciHelper) getStorageAccountKeys(ctx context.Context, helper login.StorageLogin) (map[string]string, error) {
+	var mu sync.Mutex
+	keys := map[string]string{}
+	g := compose.NewGraph()
+	for _, v := range p.Volumes {
+		accountName, ok := v.DriverOpts[volumeDriveroptsAccountNameKey]
+		if v.Driver != azureFileDriverName || !ok {
+			continue
+		}
+		g.Add(accountName, func(ctx context.Context) error {
+			key, err := helper.GetAzureStorageAccountKey(ctx, accountName)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			keys[accountName] = key
+			return nil
+		})
+	}
+	return keys, g.Run(ctx, compose.MaxConcurrency())
+}
+
+// getRestartPolicy returns the container group restart policy, shared by all services. ACI restarts containers
+// immediately and without limit, so delay, max_attempts and window are only honored by jobs.
 func (p projectAciHelper) getRestartPolicy() (containerinstance.ContainerGroupRestartPolicy, error) {
 	var restartPolicyCondition containerinstance.ContainerGroupRestartPolicy
 	if len(p.Services) >= 1 {
 		alreadySpecified := false
 		restartPolicyCondition = containerinstance.Always
 		for _, service := range p.Services {
+			if _, err := compose.ServiceRestartPolicy(service); err != nil {
+				return "", err
+			}
 			if service.Deploy != nil &&
 				service.Deploy.RestartPolicy != nil {
 				if !alreadySpecified {
@@ -323,16 +537,29 @@ func (s serviceConfigAciHelper) getAciFileVolumeMounts(volumesCache map[string]b
 	return aciServiceVolumes, nil
 }
 
-func (s serviceConfigAciHelper) getAciSecretsVolumeMounts() []containerinstance.VolumeMount {
+func (s serviceConfigAciHelper) getAciSecretsVolumeMounts() ([]containerinstance.VolumeMount, error) {
+	injection, err := compose.SecretInjection(types.ServiceConfig(s))
+	if err != nil {
+		return nil, err
+	}
+	if injection != compose.SecretInjectionFile {
+		return nil, nil
+	}
 	var secretVolumeMounts []containerinstance.VolumeMount
 	for _, secret := range s.Secrets {
+		if err := compose.CheckSecretTarget(secret); err != nil {
+			return nil, err
+		}
 		secretsMountPath := "/run/secrets"
 		if secret.Target == "" {
 			secret.Target = secret.Source
 		}
 		// Specifically use "/" here and not filepath.Join() to avoid windows path being sent and used inside containers
 		secretsMountPath = secretsMountPath + "/" + secret.Target
-		vmName := strings.Split(secret.Source, "=")[0]
+		if path.IsAbs(secret.Target) {
+			secretsMountPath = path.Dir(secret.Target)
+		}
+		vmName, _ := secretVolume(s.Name, secret)
 		vm := containerinstance.VolumeMount{
 			Name:      to.StringPtr(vmName),
 			MountPath: to.StringPtr(secretsMountPath),
@@ -340,16 +567,27 @@ func (s serviceConfigAciHelper) getAciSecretsVolumeMounts() []containerinstance.
 		}
 		secretVolumeMounts = append(secretVolumeMounts, vm)
 	}
-	return secretVolumeMounts
+	return secretVolumeMounts, nil
 }
 
 func (s serviceConfigAciHelper) getAciContainer(volumesCache map[string]bool) (containerinstance.Container, error) {
-	secretVolumeMounts := s.getAciSecretsVolumeMounts()
+	if err := s.checkKernelSettings(); err != nil {
+		return containerinstance.Container{}, err
+	}
+	secretVolumeMounts, err := s.getAciSecretsVolumeMounts()
+	if err != nil {
+		return containerinstance.Container{}, err
+	}
 	aciServiceVolumes, err := s.getAciFileVolumeMounts(volumesCache)
 	if err != nil {
 		return containerinstance.Container{}, err
 	}
+	configVolumeMounts, err := s.getAciConfigVolumeMounts()
+	if err != nil {
+		return containerinstance.Container{}, err
+	}
 	allVolumes := append(aciServiceVolumes, secretVolumeMounts...)
+	allVolumes = append(allVolumes, configVolumeMounts...)
 	var volumes *[]containerinstance.VolumeMount
 	if len(allVolumes) == 0 {
 		volumes = nil
@@ -357,23 +595,20 @@ func (s serviceConfigAciHelper) getAciContainer(volumesCache map[string]bool) (c
 		volumes = &allVolumes
 	}
 
-	memLimit := 1. // Default 1 Gb
-	var cpuLimit float64 = 1
-	if s.Deploy != nil && s.Deploy.Resources.Limits != nil {
-		if s.Deploy.Resources.Limits.MemoryBytes != 0 {
-			memLimit = bytesToGb(s.Deploy.Resources.Limits.MemoryBytes)
-		}
-		if s.Deploy.Resources.Limits.NanoCPUs != "" {
-			cpuLimit, err = strconv.ParseFloat(s.Deploy.Resources.Limits.NanoCPUs, 0)
-			if err != nil {
-				return containerinstance.Container{}, err
-			}
-		}
+	cpuLimit, memLimit, err := ServiceResources(types.ServiceConfig(s))
+	if err != nil {
+		return containerinstance.Container{}, err
+	}
+	var command *[]string
+	if _, ok := s.Extensions[commandExtension]; ok && len(s.Command) > 0 {
+		cmd := []string(s.Command)
+		command = &cmd
 	}
 	return containerinstance.Container{
 		Name: to.StringPtr(s.Name),
 		ContainerProperties: &containerinstance.ContainerProperties{
 			Image:                to.StringPtr(s.Image),
+			Command:              command,
 			EnvironmentVariables: getEnvVariables(s.Environment),
 			Resources: &containerinstance.ResourceRequirements{
 				Limits: &containerinstance.ResourceLimits{
@@ -390,6 +625,49 @@ func (s serviceConfigAciHelper) getAciContainer(volumesCache map[string]bool) (c
 	}, nil
 }
 
+// checkKernelSettings rejects the settings ACI doesn't let containers change, rather than ignoring them
+func (s serviceConfigAciHelper) checkKernelSettings() error {
+	var unsupported []string
+	if len(s.Ulimits) > 0 {
+		unsupported = append(unsupported, "ulimits")
+	}
+	if len(s.Sysctls) > 0 {
+		unsupported = append(unsupported, "sysctls")
+	}
+	if len(s.CapAdd) > 0 {
+		unsupported = append(unsupported, "cap_add")
+	}
+	if len(s.CapDrop) > 0 {
+		unsupported = append(unsupported, "cap_drop")
+	}
+	if s.ReadOnly {
+		unsupported = append(unsupported, "read_only")
+	}
+	if len(unsupported) > 0 {
+		return errors.Wrapf(errdefs.ErrNotImplemented, "ACI integration does not support %s, set on service %s", strings.Join(unsupported, ", "), s.Name)
+	}
+	return nil
+}
+
+// ServiceResources returns the CPUs and memory in GB allocated to the service container
+func ServiceResources(service types.ServiceConfig) (float64, float64, error) {
+	memLimit := 1. // Default 1 Gb
+	var cpuLimit float64 = 1
+	if service.Deploy != nil && service.Deploy.Resources.Limits != nil {
+		if service.Deploy.Resources.Limits.MemoryBytes != 0 {
+			memLimit = bytesToGb(service.Deploy.Resources.Limits.MemoryBytes)
+		}
+		if service.Deploy.Resources.Limits.NanoCPUs != "" {
+			var err error
+			cpuLimit, err = strconv.ParseFloat(service.Deploy.Resources.Limits.NanoCPUs, 0)
+			if err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+	return cpuLimit, memLimit, nil
+}
+
 func getEnvVariables(composeEnv types.MappingWithEquals) *[]containerinstance.EnvironmentVariable {
 	result := []containerinstance.EnvironmentVariable{}
 	for key, value := range composeEnv {
@@ -418,12 +696,25 @@ func ContainerGroupToServiceStatus(containerID string, group containerinstance.C
 	if GetStatus(container, group) != StatusRunning {
 		replicas = 0
 	}
+	var (
+		restarts int
+		exitCode *int
+	)
+	if container.InstanceView != nil && container.InstanceView.RestartCount != nil {
+		restarts = int(*container.InstanceView.RestartCount)
+	}
+	if container.InstanceView != nil && container.InstanceView.PreviousState != nil && container.InstanceView.PreviousState.ExitCode != nil {
+		code := int(*container.InstanceView.PreviousState.ExitCode)
+		exitCode = &code
+	}
 	return compose.ServiceStatus{
 		ID:       containerID,
 		Name:     *container.Name,
 		Ports:    formatter.PortsToStrings(ToPorts(group.IPAddress, *container.Ports), fqdn(group, region)),
 		Replicas: replicas,
 		Desired:  1,
+		Restarts: restarts,
+		ExitCode: exitCode,
 	}
 }
 
@@ -463,7 +754,10 @@ func ContainerGroupToContainer(containerID string, cg containerinstance.Containe
 	if cc.EnvironmentVariables != nil && len(*cc.EnvironmentVariables) != 0 {
 		envVars = map[string]string{}
 		for _, envVar := range *cc.EnvironmentVariables {
-			envVars[*envVar.Name] = *envVar.Value
+			// secure values, such as secrets, are not returned by ACI
+			if envVar.Value != nil {
+				envVars[*envVar.Name] = *envVar.Value
+			}
 		}
 	}
 
@@ -488,6 +782,9 @@ func ContainerGroupToContainer(containerID string, cg containerinstance.Containe
 		RestartPolicyCondition: toContainerRestartPolicy(cg.RestartPolicy),
 		Config:                 config,
 	}
+	if cc.InstanceView != nil && cc.InstanceView.CurrentState != nil && cc.InstanceView.CurrentState.ExitCode != nil {
+		c.ExitCode = int(*cc.InstanceView.CurrentState.ExitCode)
+	}
 
 	return c
 }
@@ -20,6 +20,7 @@ import (
 	"context"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 
@@ -28,10 +29,12 @@ import (
 	"github.com/compose-spec/compose-go/types"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
 
 	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/api/containers"
 	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/errdefs"
 )
 
 var (
@@ -198,10 +201,31 @@ func TestComposeSingleContainerGroupToContainerNoDnsSideCarSide(t *testing.T) {
 	assert.Equal(t, *(*group.Containers)[0].Image, "image1")
 }
 
+func TestComposeCommandKeepsEntrypoint(t *testing.T) {
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:    "service1",
+				Image:   "image1",
+				Command: types.ShellCommand{"--verbose"},
+			},
+		},
+	}
+	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	assert.NilError(t, err)
+	assert.Assert(t, (*group.Containers)[0].Command == nil)
+
+	interactive, err := ContainerToComposeProject(containers.ContainerConfig{ID: "container1", Image: "image1", Interactive: true})
+	assert.NilError(t, err)
+	group, err = ToContainerGroup(context.TODO(), convertCtx, interactive, mockStorageHelper)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, *(*group.Containers)[0].Command, []string(keepAliveCommand))
+}
+
 func TestComposeVolumes(t *testing.T) {
 	ctx := context.TODO()
 	accountName := "myAccount"
-	mockStorageHelper.On("GetAzureStorageAccountKey", ctx, accountName).Return("123456", nil)
+	mockStorageHelper.On("GetAzureStorageAccountKey", mock.Anything, accountName).Return("123456", nil)
 	project := types.Project{
 		Services: []types.ServiceConfig{
 			{
@@ -241,7 +265,7 @@ func TestComposeVolumes(t *testing.T) {
 func TestComposeVolumesRO(t *testing.T) {
 	ctx := context.TODO()
 	accountName := "myAccount"
-	mockStorageHelper.On("GetAzureStorageAccountKey", ctx, accountName).Return("123456", nil)
+	mockStorageHelper.On("GetAzureStorageAccountKey", mock.Anything, accountName).Return("123456", nil)
 	project := types.Project{
 		Services: []types.ServiceConfig{
 			{
@@ -455,6 +479,33 @@ func TestComposeContainerGroupToContainerMultiplePorts(t *testing.T) {
 	assert.Assert(t, group.IPAddress.DNSNameLabel == nil)
 }
 
+func TestComposeContainerGroupToContainerPrivate(t *testing.T) {
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:  "service1",
+				Image: "image1",
+				Ports: []types.ServicePortConfig{
+					{
+						Published: 80,
+						Target:    80,
+					},
+				},
+			},
+		},
+	}
+	privateCtx := convertCtx
+	privateCtx.SubnetID = "/subscriptions/subID/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/aci"
+
+	group, err := ToContainerGroup(context.TODO(), privateCtx, project, mockStorageHelper)
+	assert.NilError(t, err)
+	assert.Equal(t, group.IPAddress.Type, containerinstance.Private)
+
+	project.Services[0].DomainName = "myapp"
+	_, err = ToContainerGroup(context.TODO(), privateCtx, project, mockStorageHelper)
+	assert.ErrorContains(t, err, "private deployments")
+}
+
 func TestComposeContainerGroupToContainerWithDomainName(t *testing.T) {
 	project := types.Project{
 		Services: []types.ServiceConfig{
@@ -493,6 +544,37 @@ func TestComposeContainerGroupToContainerWithDomainName(t *testing.T) {
 	assert.Equal(t, *group.IPAddress.DNSNameLabel, "myApp")
 }
 
+func TestComposeContainerGroupToContainerInternalNetwork(t *testing.T) {
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:     "front",
+				Image:    "nginx",
+				Ports:    []types.ServicePortConfig{{Published: 80, Target: 80}},
+				Networks: map[string]*types.ServiceNetworkConfig{"public": nil, "private": nil},
+			},
+			{
+				Name:     "db",
+				Image:    "mysql",
+				Ports:    []types.ServicePortConfig{{Published: 3306, Target: 3306}},
+				Networks: map[string]*types.ServiceNetworkConfig{"private": nil},
+			},
+		},
+		Networks: types.Networks{
+			"public":  types.NetworkConfig{},
+			"private": types.NetworkConfig{Internal: true},
+		},
+	}
+
+	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	assert.NilError(t, err)
+	groupPorts := *group.IPAddress.Ports
+	assert.Assert(t, is.Len(groupPorts, 1))
+	assert.Equal(t, *groupPorts[0].Port, int32(80))
+	db := (*group.Containers)[1]
+	assert.Equal(t, *(*db.Ports)[0].Port, int32(3306))
+}
+
 func TestComposeContainerGroupToContainerErrorWhenSeveralDomainNames(t *testing.T) {
 	project := types.Project{
 		Services: []types.ServiceConfig{
@@ -614,6 +696,113 @@ func TestComposeContainerGroupToContainerenvVar(t *testing.T) {
 	assert.Assert(t, is.Contains(envVars, containerinstance.EnvironmentVariable{Name: to.StringPtr("key2"), Value: to.StringPtr("value2")}))
 }
 
+func TestComposeContainerGroupToContainerSecrets(t *testing.T) {
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:  "service1",
+				Image: "image1",
+				Secrets: []types.ServiceSecretConfig{
+					{Source: "token"},
+					{Source: "cert", Target: "/opt/app/cert.pem"},
+				},
+			},
+			{
+				Name:  "service2",
+				Image: "image2",
+				Secrets: []types.ServiceSecretConfig{
+					{Source: "token", Target: "API_TOKEN"},
+				},
+				Extensions: map[string]interface{}{
+					"x-secret-injection": "env",
+				},
+			},
+		},
+		Secrets: types.Secrets{
+			"token": {File: "inline:secret-token"},
+			"cert":  {File: "inline:certificate"},
+		},
+	}
+
+	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	assert.NilError(t, err)
+
+	assert.DeepEqual(t, *group.Volumes, []containerinstance.Volume{
+		{Name: to.StringPtr("token"), Secret: map[string]*string{"token": to.StringPtr("c2VjcmV0LXRva2Vu")}},
+		{Name: to.StringPtr("service1-cert"), Secret: map[string]*string{"cert.pem": to.StringPtr("Y2VydGlmaWNhdGU=")}},
+	})
+	assert.DeepEqual(t, *(*group.Containers)[0].VolumeMounts, []containerinstance.VolumeMount{
+		{Name: to.StringPtr("token"), MountPath: to.StringPtr("/run/secrets/token"), ReadOnly: to.BoolPtr(true)},
+		{Name: to.StringPtr("service1-cert"), MountPath: to.StringPtr("/opt/app"), ReadOnly: to.BoolPtr(true)},
+	})
+	assert.Assert(t, (*group.Containers)[1].VolumeMounts == nil)
+	envVars := *(*group.Containers)[1].EnvironmentVariables
+	assert.Assert(t, is.Contains(envVars, containerinstance.EnvironmentVariable{Name: to.StringPtr("API_TOKEN"), SecureValue: to.StringPtr("secret-token")}))
+}
+
+func TestComposeContainerGroupToContainerConfigs(t *testing.T) {
+	conf := fs.NewFile(t, "nginx", fs.WithContent("server {}"))
+	defer conf.Remove()
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:  "web",
+				Image: "nginx",
+				Configs: []types.ServiceConfigObjConfig{
+					{Source: "site", Target: "/etc/nginx/conf.d/site.conf"},
+					{Source: "api", Target: "/etc/nginx/conf.d/api.conf"},
+				},
+			},
+		},
+		Configs: types.Configs{
+			"site": {File: conf.Path()},
+			"api":  {File: conf.Path()},
+		},
+	}
+
+	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	assert.NilError(t, err)
+
+	assert.DeepEqual(t, *group.Volumes, []containerinstance.Volume{
+		{Name: to.StringPtr("web-configs-0"), Secret: map[string]*string{
+			"site.conf": to.StringPtr("c2VydmVyIHt9"),
+			"api.conf":  to.StringPtr("c2VydmVyIHt9"),
+		}},
+	})
+	assert.DeepEqual(t, *(*group.Containers)[0].VolumeMounts, []containerinstance.VolumeMount{
+		{Name: to.StringPtr("web-configs-0"), MountPath: to.StringPtr("/etc/nginx/conf.d"), ReadOnly: to.BoolPtr(true)},
+	})
+}
+
+func TestComposeContainerGroupToContainerSidecars(t *testing.T) {
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:  "service1",
+				Image: "image1",
+			},
+		},
+		Extensions: map[string]interface{}{
+			"x-sidecars": map[string]interface{}{
+				"logs": map[string]interface{}{
+					"image":       "fluent/fluent-bit",
+					"environment": map[string]interface{}{"TAG": "{{.Service}}"},
+				},
+			},
+		},
+	}
+
+	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	assert.NilError(t, err)
+
+	containers := *group.Containers
+	assert.Equal(t, len(containers), 3)
+	assert.Equal(t, *containers[1].Name, "service1-logs")
+	assert.Equal(t, *containers[1].Image, "fluent/fluent-bit")
+	assert.DeepEqual(t, *containers[1].EnvironmentVariables, []containerinstance.EnvironmentVariable{{Name: to.StringPtr("TAG"), Value: to.StringPtr("service1")}})
+	assert.Equal(t, *containers[2].Name, ComposeDNSSidecarName)
+}
+
 func TestConvertToAciRestartPolicyCondition(t *testing.T) {
 	assert.Equal(t, toAciRestartPolicy("none"), containerinstance.Never)
 	assert.Equal(t, toAciRestartPolicy("always"), containerinstance.Always)
@@ -667,3 +856,41 @@ func group(status *string) containerinstance.ContainerGroup {
 		},
 	}
 }
+
+func TestConvertWarnsStopSettings(t *testing.T) {
+	grace := types.Duration(30 * time.Second)
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:            "service1",
+				Image:           "image1",
+				StopSignal:      "SIGINT",
+				StopGracePeriod: &grace,
+			},
+		},
+	}
+	ctx, diagnostics := compose.WithDiagnostics(context.TODO())
+	_, err := ToContainerGroup(ctx, convertCtx, project, mockStorageHelper)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, diagnostics(), []compose.Diagnostic{
+		{Severity: compose.SeverityWarning, Message: "service service1 stop_signal SIGINT is ignored, ACI doesn't support custom stop signals"},
+		{Severity: compose.SeverityWarning, Message: "service service1 stop_grace_period is ignored, ACI doesn't support stop timeouts"},
+	})
+}
+
+func TestConvertRejectsKernelSettings(t *testing.T) {
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:     "db",
+				Image:    "postgres",
+				ReadOnly: true,
+				Ulimits:  map[string]*types.UlimitsConfig{"nofile": {Single: 65535}},
+				CapDrop:  []string{"ALL"},
+			},
+		},
+	}
+	_, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	assert.ErrorContains(t, err, "ACI integration does not support ulimits, cap_drop, read_only, set on service db")
+	assert.Assert(t, errdefs.IsErrNotImplemented(err))
+}
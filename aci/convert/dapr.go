@@ -0,0 +1,262 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package convert
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+	"github.com/sanathkr/go-yaml"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// DaprExtension runs a service with a Dapr sidecar. The service extension sets the Dapr application:
+//
+//	x-dapr:
+//	  app-id: api      # service name by default
+//	  app-port: 8080   # port the application listens on, if any
+//
+// The project extension declares the components available to all Dapr applications:
+//
+//	x-dapr:
+//	  components:
+//	    statestore:
+//	      type: state.redis
+//	      version: v1
+//	      metadata:
+//	        redisHost: redis:6379
+const DaprExtension = "x-dapr"
+
+const (
+	daprSidecarName      = "dapr"
+	daprImage            = "daprio/daprd:1.0.0"
+	daprComponentsVolume = "dapr-components"
+	daprComponentsPath   = "/components"
+	// sidecars share the container group network namespace, each one listens on its own ports
+	daprHTTPPort = 3500
+	daprGRPCPort = 50001
+)
+
+type daprApplication struct {
+	appID   string
+	appPort int
+}
+
+type daprComponent struct {
+	APIVersion string                `yaml:"apiVersion"`
+	Kind       string                `yaml:"kind"`
+	Metadata   daprComponentMetadata `yaml:"metadata"`
+	Spec       daprComponentSpec     `yaml:"spec"`
+}
+
+type daprComponentMetadata struct {
+	Name string `yaml:"name"`
+}
+
+type daprComponentSpec struct {
+	Type     string              `yaml:"type"`
+	Version  string              `yaml:"version"`
+	Metadata []daprComponentItem `yaml:"metadata,omitempty"`
+}
+
+type daprComponentItem struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+// daprApplicationOf returns the Dapr application a service runs, or nil if the service doesn't use Dapr
+func daprApplicationOf(service types.ServiceConfig) (*daprApplication, error) {
+	x, ok := service.Extensions[DaprExtension]
+	if !ok {
+		return nil, nil
+	}
+	values, ok := x.(map[string]interface{})
+	if !ok {
+		return nil, errors.Wrapf(errdefs.ErrParsingFailed, "service %q: %s must be a mapping", service.Name, DaprExtension)
+	}
+	app := daprApplication{appID: service.Name}
+	for key, value := range values {
+		var valid bool
+		switch key {
+		case "app-id":
+			app.appID, valid = value.(string)
+		case "app-port":
+			switch port := value.(type) {
+			case int:
+				app.appPort = port
+			case float64:
+				app.appPort = int(port)
+			}
+			valid = app.appPort > 0
+		default:
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "service %q: unsupported %s attribute %q", service.Name, DaprExtension, key)
+		}
+		if !valid {
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "service %q: invalid %s %s: %v", service.Name, DaprExtension, key, value)
+		}
+	}
+	return &app, nil
+}
+
+// daprPorts returns the HTTP and gRPC ports of the Dapr sidecar of a service, offset by the rank of the service
+// among the project Dapr applications
+func daprPorts(project types.Project, service types.ServiceConfig) (int, int) {
+	rank := 0
+	for _, s := range project.Services {
+		if s.Name == service.Name {
+			break
+		}
+		if _, ok := s.Extensions[DaprExtension]; ok {
+			rank++
+		}
+	}
+	return daprHTTPPort + rank, daprGRPCPort + rank
+}
+
+// daprSidecar returns the daprd sidecar of a service, nil if the service doesn't use Dapr
+func daprSidecar(project types.Project, service types.ServiceConfig) (*types.ServiceConfig, error) {
+	app, err := daprApplicationOf(service)
+	if err != nil || app == nil {
+		return nil, err
+	}
+	httpPort, grpcPort := daprPorts(project, service)
+	command := types.ShellCommand{
+		"./daprd",
+		"--app-id", app.appID,
+		"--dapr-http-port", fmt.Sprint(httpPort),
+		"--dapr-grpc-port", fmt.Sprint(grpcPort),
+		"--components-path", daprComponentsPath,
+		"--enable-metrics=false",
+	}
+	if app.appPort > 0 {
+		command = append(command, "--app-port", fmt.Sprint(app.appPort))
+	}
+	return &types.ServiceConfig{
+		Name:    daprSidecarName,
+		Image:   daprImage,
+		Command: command,
+		Extensions: map[string]interface{}{
+			commandExtension: true,
+		},
+		Deploy: &types.DeployConfig{
+			Resources: types.Resources{
+				Limits: &types.Resource{NanoCPUs: "0.25", MemoryBytes: 512 * 1024 * 1024},
+			},
+		},
+	}, nil
+}
+
+// groupSidecars returns the x-sidecars and Dapr sidecars of a service, as they are deployed in the container group
+func groupSidecars(project types.Project, service types.ServiceConfig) ([]types.ServiceConfig, error) {
+	sidecars, err := compose.Sidecars(&project, service)
+	if err != nil {
+		return nil, err
+	}
+	dapr, err := daprSidecar(project, service)
+	if err != nil {
+		return nil, err
+	}
+	if dapr != nil {
+		sidecars = append(sidecars, *dapr)
+	}
+	return sidecars, nil
+}
+
+// daprEnvironment returns the variables telling a Dapr application how to reach its sidecar
+func daprEnvironment(project types.Project, service types.ServiceConfig) []containerinstance.EnvironmentVariable {
+	if _, ok := service.Extensions[DaprExtension]; !ok {
+		return nil
+	}
+	httpPort, grpcPort := daprPorts(project, service)
+	return []containerinstance.EnvironmentVariable{
+		{Name: to.StringPtr("DAPR_HTTP_PORT"), Value: to.StringPtr(fmt.Sprint(httpPort))},
+		{Name: to.StringPtr("DAPR_GRPC_PORT"), Value: to.StringPtr(fmt.Sprint(grpcPort))},
+	}
+}
+
+// daprComponentsVolumeOf returns the volume holding the project Dapr components definitions, nil without components
+func daprComponentsVolumeOf(project types.Project) (*containerinstance.Volume, error) {
+	x, ok := project.Extensions[DaprExtension]
+	if !ok {
+		return nil, nil
+	}
+	values, ok := x.(map[string]interface{})
+	if !ok {
+		return nil, errors.Wrapf(errdefs.ErrParsingFailed, "%s must be a mapping", DaprExtension)
+	}
+	components, ok := values["components"].(map[string]interface{})
+	if !ok || len(values) > 1 {
+		return nil, errors.Wrapf(errdefs.ErrParsingFailed, "%s must declare a mapping of components", DaprExtension)
+	}
+	files := map[string]*string{}
+	for name, value := range components {
+		component, err := toDaprComponent(name, value)
+		if err != nil {
+			return nil, err
+		}
+		data, err := yaml.Marshal(component)
+		if err != nil {
+			return nil, err
+		}
+		files[name+".yaml"] = to.StringPtr(base64.StdEncoding.EncodeToString(data))
+	}
+	return &containerinstance.Volume{
+		Name:   to.StringPtr(daprComponentsVolume),
+		Secret: files,
+	}, nil
+}
+
+func toDaprComponent(name string, value interface{}) (daprComponent, error) {
+	definition, ok := value.(map[string]interface{})
+	if !ok {
+		return daprComponent{}, errors.Wrapf(errdefs.ErrParsingFailed, "dapr component %q must be a mapping", name)
+	}
+	componentType, ok := definition["type"].(string)
+	if !ok {
+		return daprComponent{}, errors.Wrapf(errdefs.ErrParsingFailed, "dapr component %q has no type", name)
+	}
+	version, ok := definition["version"].(string)
+	if !ok {
+		version = "v1"
+	}
+	var items []daprComponentItem
+	if metadata, ok := definition["metadata"].(map[string]interface{}); ok {
+		for key, v := range metadata {
+			items = append(items, daprComponentItem{Name: key, Value: fmt.Sprint(v)})
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+	return daprComponent{
+		APIVersion: "dapr.io/v1alpha1",
+		Kind:       "Component",
+		Metadata:   daprComponentMetadata{Name: name},
+		Spec: daprComponentSpec{
+			Type:     componentType,
+			Version:  version,
+			Metadata: items,
+		},
+	}, nil
+}
@@ -0,0 +1,97 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package convert
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestDaprSidecars(t *testing.T) {
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:       "api",
+				Image:      "api",
+				Extensions: map[string]interface{}{DaprExtension: map[string]interface{}{"app-port": 8080}},
+			},
+			{
+				Name:       "worker",
+				Image:      "worker",
+				Extensions: map[string]interface{}{DaprExtension: map[string]interface{}{"app-id": "jobs"}},
+			},
+		},
+		Extensions: map[string]interface{}{
+			DaprExtension: map[string]interface{}{
+				"components": map[string]interface{}{
+					"statestore": map[string]interface{}{
+						"type":     "state.redis",
+						"metadata": map[string]interface{}{"redisHost": "redis:6379"},
+					},
+				},
+			},
+		},
+	}
+
+	group, err := ToContainerGroup(context.TODO(), convertCtx, project, mockStorageHelper)
+	assert.NilError(t, err)
+
+	containers := *group.Containers
+	assert.Equal(t, len(containers), 5)
+	assert.Equal(t, *containers[1].Name, "api-dapr")
+	assert.DeepEqual(t, *containers[1].Command, []string{"./daprd", "--app-id", "api", "--dapr-http-port", "3500", "--dapr-grpc-port", "50001",
+		"--components-path", "/components", "--enable-metrics=false", "--app-port", "8080"})
+	assert.Equal(t, *(*containers[1].VolumeMounts)[0].MountPath, "/components")
+	assert.Equal(t, *containers[1].Resources.Limits.CPU, 0.25)
+	assert.Equal(t, *containers[3].Name, "worker-dapr")
+	assert.Check(t, is.Contains(*containers[3].Command, "jobs"))
+	assert.DeepEqual(t, *containers[2].EnvironmentVariables, []containerinstance.EnvironmentVariable{
+		{Name: to.StringPtr("DAPR_HTTP_PORT"), Value: to.StringPtr("3501")},
+		{Name: to.StringPtr("DAPR_GRPC_PORT"), Value: to.StringPtr("50002")},
+	})
+
+	volumes := *group.Volumes
+	assert.Equal(t, *volumes[0].Name, daprComponentsVolume)
+	data, err := base64.StdEncoding.DecodeString(*volumes[0].Secret["statestore.yaml"])
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), `apiVersion: dapr.io/v1alpha1
+kind: Component
+metadata:
+  name: statestore
+spec:
+  type: state.redis
+  version: v1
+  metadata:
+  - name: redisHost
+    value: redis:6379
+`)
+}
+
+func TestDaprInvalidExtension(t *testing.T) {
+	_, err := daprApplicationOf(types.ServiceConfig{
+		Name:       "api",
+		Extensions: map[string]interface{}{DaprExtension: map[string]interface{}{"app-port": "http"}},
+	})
+	assert.ErrorContains(t, err, "invalid x-dapr app-port")
+}
@@ -0,0 +1,158 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package convert
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/sirupsen/logrus"
+)
+
+// ContainerGroupToProject reverse-engineers a compose project from a container group, with a service per container.
+// Settings which can't be expressed in a compose file, like secure environment values or secret volumes, are left out
+// with a warning.
+func ContainerGroupToProject(name string, group containerinstance.ContainerGroup) *types.Project {
+	project := &types.Project{
+		Name:    name,
+		Volumes: types.Volumes{},
+	}
+	if group.ContainerGroupProperties == nil || group.Containers == nil {
+		return project
+	}
+
+	secretVolumes := map[string]bool{}
+	if group.Volumes != nil {
+		for _, v := range *group.Volumes {
+			switch {
+			case v.AzureFile != nil:
+				project.Volumes[to.String(v.Name)] = types.VolumeConfig{
+					Driver: azureFileDriverName,
+					DriverOpts: map[string]string{
+						volumeDriveroptsAccountNameKey: to.String(v.AzureFile.StorageAccountName),
+						volumeDriveroptsShareNameKey:   to.String(v.AzureFile.ShareName),
+					},
+				}
+			case v.Secret != nil:
+				secretVolumes[to.String(v.Name)] = true
+				logrus.Warnf("secret volume %q can't be imported, declare the matching compose secrets", to.String(v.Name))
+			default:
+				logrus.Warnf("volume %q can't be imported, only Azure file shares are supported", to.String(v.Name))
+			}
+		}
+	}
+
+	var dnsLabel string
+	if group.IPAddress != nil {
+		dnsLabel = to.String(group.IPAddress.DNSNameLabel)
+	}
+	for _, container := range *group.Containers {
+		if to.String(container.Name) == ComposeDNSSidecarName || container.ContainerProperties == nil {
+			continue
+		}
+		service := containerToService(container, project.Volumes, secretVolumes)
+		if len(service.Ports) > 0 && dnsLabel != "" {
+			service.DomainName = dnsLabel
+		}
+		if group.RestartPolicy != "" && group.RestartPolicy != containerinstance.Always {
+			if service.Deploy == nil {
+				service.Deploy = &types.DeployConfig{}
+			}
+			service.Deploy.RestartPolicy = &types.RestartPolicy{
+				Condition: toContainerRestartPolicy(group.RestartPolicy),
+			}
+		}
+		project.Services = append(project.Services, service)
+	}
+	sort.Slice(project.Services, func(i, j int) bool {
+		return project.Services[i].Name < project.Services[j].Name
+	})
+	return project
+}
+
+func containerToService(container containerinstance.Container, volumes types.Volumes, secretVolumes map[string]bool) types.ServiceConfig {
+	service := types.ServiceConfig{
+		Name:  to.String(container.Name),
+		Image: to.String(container.Image),
+	}
+	if container.Command != nil {
+		service.Command = *container.Command
+	}
+
+	if container.EnvironmentVariables != nil && len(*container.EnvironmentVariables) > 0 {
+		service.Environment = types.MappingWithEquals{}
+		for _, env := range *container.EnvironmentVariables {
+			name := to.String(env.Name)
+			if env.Value == nil {
+				// secure values are never returned by ACI, let the variable be resolved at deployment time
+				logrus.Warnf("secure value of environment variable %s of %q can't be imported, set it when deploying", name, service.Name)
+			}
+			service.Environment[name] = env.Value
+		}
+	}
+
+	if container.Ports != nil {
+		for _, port := range *container.Ports {
+			protocol := "tcp"
+			if port.Protocol == containerinstance.ContainerNetworkProtocolUDP {
+				protocol = "udp"
+			}
+			service.Ports = append(service.Ports, types.ServicePortConfig{
+				Target:    uint32(to.Int32(port.Port)),
+				Published: uint32(to.Int32(port.Port)),
+				Protocol:  protocol,
+			})
+		}
+	}
+
+	if container.VolumeMounts != nil {
+		for _, mount := range *container.VolumeMounts {
+			name := to.String(mount.Name)
+			if _, ok := volumes[name]; !ok {
+				if !secretVolumes[name] {
+					logrus.Warnf("volume mount %q of %q can't be imported", name, service.Name)
+				}
+				continue
+			}
+			service.Volumes = append(service.Volumes, types.ServiceVolumeConfig{
+				Type:   types.VolumeTypeVolume,
+				Source: name,
+				Target: to.String(mount.MountPath),
+			})
+		}
+	}
+
+	if container.Resources != nil && container.Resources.Requests != nil {
+		requests := container.Resources.Requests
+		service.Deploy = &types.DeployConfig{
+			Resources: types.Resources{
+				Limits: &types.Resource{
+					NanoCPUs:    strconv.FormatFloat(to.Float64(requests.CPU), 'f', -1, 64),
+					MemoryBytes: gbToBytes(to.Float64(requests.MemoryInGB)),
+				},
+			},
+		}
+	}
+	return service
+}
+
+func gbToBytes(gb float64) types.UnitBytes {
+	return types.UnitBytes(gb * 1024 * 1024 * 1024)
+}
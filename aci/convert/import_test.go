@@ -0,0 +1,93 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package convert
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestContainerGroupToProject(t *testing.T) {
+	group := containerinstance.ContainerGroup{
+		Name: to.StringPtr("myapp"),
+		ContainerGroupProperties: &containerinstance.ContainerGroupProperties{
+			RestartPolicy: containerinstance.OnFailure,
+			IPAddress: &containerinstance.IPAddress{
+				DNSNameLabel: to.StringPtr("myapp"),
+			},
+			Volumes: &[]containerinstance.Volume{
+				{
+					Name: to.StringPtr("data"),
+					AzureFile: &containerinstance.AzureFileVolume{
+						ShareName:          to.StringPtr("myshare"),
+						StorageAccountName: to.StringPtr("mystorage"),
+					},
+				},
+			},
+			Containers: &[]containerinstance.Container{
+				{
+					Name: to.StringPtr("web"),
+					ContainerProperties: &containerinstance.ContainerProperties{
+						Image: to.StringPtr("nginx"),
+						Ports: &[]containerinstance.ContainerPort{{Port: to.Int32Ptr(80)}},
+						EnvironmentVariables: &[]containerinstance.EnvironmentVariable{
+							{Name: to.StringPtr("MODE"), Value: to.StringPtr("production")},
+							{Name: to.StringPtr("TOKEN")},
+						},
+						VolumeMounts: &[]containerinstance.VolumeMount{
+							{Name: to.StringPtr("data"), MountPath: to.StringPtr("/data")},
+						},
+						Resources: &containerinstance.ResourceRequirements{
+							Requests: &containerinstance.ResourceRequests{CPU: to.Float64Ptr(0.5), MemoryInGB: to.Float64Ptr(1.5)},
+						},
+					},
+				},
+				{
+					Name: to.StringPtr(ComposeDNSSidecarName),
+					ContainerProperties: &containerinstance.ContainerProperties{
+						Image: to.StringPtr(dnsSidecarImage),
+					},
+				},
+			},
+		},
+	}
+
+	project := ContainerGroupToProject("myapp", group)
+	assert.Equal(t, project.Name, "myapp")
+	assert.DeepEqual(t, project.Volumes["data"].DriverOpts, map[string]string{
+		"share_name":           "myshare",
+		"storage_account_name": "mystorage",
+	})
+	assert.Equal(t, len(project.Services), 1)
+	web := project.Services[0]
+	assert.Equal(t, web.Image, "nginx")
+	assert.Equal(t, web.DomainName, "myapp")
+	assert.DeepEqual(t, web.Ports, []types.ServicePortConfig{{Target: 80, Published: 80, Protocol: "tcp"}})
+	assert.Equal(t, *web.Environment["MODE"], "production")
+	assert.Assert(t, web.Environment["TOKEN"] == nil)
+	assert.DeepEqual(t, web.Volumes, []types.ServiceVolumeConfig{{Type: "volume", Source: "data", Target: "/data"}})
+	assert.Equal(t, web.Deploy.RestartPolicy.Condition, "on-failure")
+
+	cpu, memory, err := ServiceResources(web)
+	assert.NilError(t, err)
+	assert.Equal(t, cpu, 0.5)
+	assert.Equal(t, memory, 1.5)
+}
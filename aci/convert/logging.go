@@ -0,0 +1,151 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package convert
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// LogAnalyticsDriver is the logging driver sending the logs of the container group to an Azure Log Analytics workspace:
+//
+//	logging:
+//	  driver: azure_log_analytics
+//	  options:
+//	    workspace_id: 00000000-0000-0000-0000-000000000000   # with workspace_key, or
+//	    workspace_resource_id: /subscriptions/.../workspaces/logs # or
+//	    create_workspace: "true"                             # workspace of the project, created if needed
+//	    log_type: ContainerInstanceLogs                      # or ContainerInsights
+const LogAnalyticsDriver = "azure_log_analytics"
+
+const (
+	logOptWorkspaceID         = "workspace_id"
+	logOptWorkspaceKey        = "workspace_key"
+	logOptWorkspaceResourceID = "workspace_resource_id"
+	logOptCreateWorkspace     = "create_workspace"
+	logOptLogType             = "log_type"
+)
+
+// LogAnalytics is the Log Analytics workspace the logs of a container group are sent to
+type LogAnalytics struct {
+	// WorkspaceID and WorkspaceKey are the credentials of the workspace, when set in the compose file
+	WorkspaceID  string
+	WorkspaceKey string
+	// WorkspaceResourceID is the Azure resource ID of the workspace, its credentials being read at deployment
+	WorkspaceResourceID string
+	// CreateWorkspace sends the logs to the workspace of the project, created if needed
+	CreateWorkspace bool
+	LogType         containerinstance.LogAnalyticsLogType
+}
+
+// LogAnalyticsOf returns the Log Analytics workspace of the project services logs, nil if none is configured. ACI
+// diagnostics apply to the whole container group, services sending logs to Log Analytics must all use the same options.
+func LogAnalyticsOf(ctx context.Context, project types.Project) (*LogAnalytics, error) {
+	var (
+		options *LogAnalytics
+		from    string
+	)
+	for _, service := range project.Services {
+		if service.Logging == nil || service.Logging.Driver == "" {
+			continue
+		}
+		if service.Logging.Driver != LogAnalyticsDriver {
+			compose.Warn(ctx, "service %s logging driver %s is ignored, ACI only supports %s", service.Name, service.Logging.Driver, LogAnalyticsDriver)
+			continue
+		}
+		serviceOptions, err := logAnalyticsOptions(service)
+		if err != nil {
+			return nil, err
+		}
+		if options != nil && !reflect.DeepEqual(*options, serviceOptions) {
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "services %q and %q: ACI sends the logs of all services to the same Log Analytics workspace, logging options must be the same", from, service.Name)
+		}
+		options, from = &serviceOptions, service.Name
+	}
+	if options == nil {
+		return nil, nil
+	}
+	for _, service := range project.Services {
+		if service.Logging == nil || service.Logging.Driver == "" {
+			compose.Warn(ctx, "service %s logs are sent to Log Analytics too, as ACI sends the logs of all containers of the group", service.Name)
+		}
+	}
+	return options, nil
+}
+
+func logAnalyticsOptions(service types.ServiceConfig) (LogAnalytics, error) {
+	options := LogAnalytics{LogType: containerinstance.ContainerInstanceLogs}
+	for key, value := range service.Logging.Options {
+		switch key {
+		case logOptWorkspaceID:
+			options.WorkspaceID = value
+		case logOptWorkspaceKey:
+			options.WorkspaceKey = value
+		case logOptWorkspaceResourceID:
+			options.WorkspaceResourceID = value
+		case logOptCreateWorkspace:
+			create, err := strconv.ParseBool(value)
+			if err != nil {
+				return options, errors.Wrapf(errdefs.ErrParsingFailed, "service %q: invalid logging option %s: %q", service.Name, key, value)
+			}
+			options.CreateWorkspace = create
+		case logOptLogType:
+			options.LogType = containerinstance.LogAnalyticsLogType(value)
+			if options.LogType != containerinstance.ContainerInstanceLogs && options.LogType != containerinstance.ContainerInsights {
+				return options, errors.Wrapf(errdefs.ErrParsingFailed, "service %q: invalid logging option %s: %q", service.Name, key, value)
+			}
+		default:
+			return options, errors.Wrapf(errdefs.ErrParsingFailed, "service %q: unsupported %s logging option %q", service.Name, LogAnalyticsDriver, key)
+		}
+	}
+	sources := 0
+	if options.WorkspaceID != "" || options.WorkspaceKey != "" {
+		if options.WorkspaceID == "" || options.WorkspaceKey == "" {
+			return options, errors.Wrapf(errdefs.ErrParsingFailed, "service %q: logging options %s and %s must be set together", service.Name, logOptWorkspaceID, logOptWorkspaceKey)
+		}
+		sources++
+	}
+	if options.WorkspaceResourceID != "" {
+		sources++
+	}
+	if options.CreateWorkspace {
+		sources++
+	}
+	if sources != 1 {
+		return options, errors.Wrapf(errdefs.ErrParsingFailed, "service %q: %s logging requires exactly one of %s, %s or %s", service.Name, LogAnalyticsDriver, logOptWorkspaceID, logOptWorkspaceResourceID, logOptCreateWorkspace)
+	}
+	return options, nil
+}
+
+// ToDiagnostics returns the diagnostics of a container group sending its logs to a workspace
+func ToDiagnostics(workspaceID, workspaceKey string, logType containerinstance.LogAnalyticsLogType) *containerinstance.ContainerGroupDiagnostics {
+	return &containerinstance.ContainerGroupDiagnostics{
+		LogAnalytics: &containerinstance.LogAnalytics{
+			WorkspaceID:  &workspaceID,
+			WorkspaceKey: &workspaceKey,
+			LogType:      logType,
+		},
+	}
+}
@@ -0,0 +1,107 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package convert
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func loggingService(name string, options map[string]string) types.ServiceConfig {
+	return types.ServiceConfig{
+		Name:    name,
+		Logging: &types.LoggingConfig{Driver: LogAnalyticsDriver, Options: options},
+	}
+}
+
+func TestLogAnalyticsOf(t *testing.T) {
+	ctx, diagnostics := compose.WithDiagnostics(context.TODO())
+	options, err := LogAnalyticsOf(ctx, types.Project{Services: []types.ServiceConfig{
+		loggingService("api", map[string]string{"workspace_id": "id", "workspace_key": "key"}),
+		loggingService("worker", map[string]string{"workspace_id": "id", "workspace_key": "key", "log_type": "ContainerInstanceLogs"}),
+		{Name: "db"},
+		{Name: "cache", Logging: &types.LoggingConfig{Driver: "json-file"}},
+	}})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, *options, LogAnalytics{
+		WorkspaceID:  "id",
+		WorkspaceKey: "key",
+		LogType:      containerinstance.ContainerInstanceLogs,
+	})
+	assert.DeepEqual(t, diagnostics(), []compose.Diagnostic{
+		{Severity: compose.SeverityWarning, Message: "service cache logging driver json-file is ignored, ACI only supports azure_log_analytics"},
+		{Severity: compose.SeverityWarning, Message: "service db logs are sent to Log Analytics too, as ACI sends the logs of all containers of the group"},
+	})
+}
+
+func TestLogAnalyticsOfNoLogging(t *testing.T) {
+	options, err := LogAnalyticsOf(context.TODO(), types.Project{Services: []types.ServiceConfig{{Name: "api"}}})
+	assert.NilError(t, err)
+	assert.Assert(t, options == nil)
+}
+
+func TestLogAnalyticsOfCreateWorkspace(t *testing.T) {
+	options, err := LogAnalyticsOf(context.TODO(), types.Project{Services: []types.ServiceConfig{
+		loggingService("api", map[string]string{"create_workspace": "true", "log_type": "ContainerInsights"}),
+	}})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, *options, LogAnalytics{CreateWorkspace: true, LogType: containerinstance.ContainerInsights})
+}
+
+func TestLogAnalyticsOfInvalidOptions(t *testing.T) {
+	for _, tc := range []struct {
+		services []types.ServiceConfig
+		err      string
+	}{
+		{
+			services: []types.ServiceConfig{loggingService("api", map[string]string{"workspace_id": "id"})},
+			err:      `service "api": logging options workspace_id and workspace_key must be set together: parsing failed`,
+		},
+		{
+			services: []types.ServiceConfig{loggingService("api", nil)},
+			err:      `service "api": azure_log_analytics logging requires exactly one of workspace_id, workspace_resource_id or create_workspace: parsing failed`,
+		},
+		{
+			services: []types.ServiceConfig{loggingService("api", map[string]string{"workspace_resource_id": "/id", "create_workspace": "true"})},
+			err:      `service "api": azure_log_analytics logging requires exactly one of workspace_id, workspace_resource_id or create_workspace: parsing failed`,
+		},
+		{
+			services: []types.ServiceConfig{loggingService("api", map[string]string{"create_workspace": "maybe"})},
+			err:      `service "api": invalid logging option create_workspace: "maybe": parsing failed`,
+		},
+		{
+			services: []types.ServiceConfig{loggingService("api", map[string]string{"create_workspace": "true", "tag": "api"})},
+			err:      `service "api": unsupported azure_log_analytics logging option "tag": parsing failed`,
+		},
+		{
+			services: []types.ServiceConfig{
+				loggingService("api", map[string]string{"create_workspace": "true"}),
+				loggingService("worker", map[string]string{"workspace_resource_id": "/id"}),
+			},
+			err: `services "api" and "worker": ACI sends the logs of all services to the same Log Analytics workspace, logging options must be the same: parsing failed`,
+		},
+	} {
+		_, err := LogAnalyticsOf(context.TODO(), types.Project{Services: tc.services})
+		assert.Error(t, err, tc.err)
+	}
+}
@@ -0,0 +1,63 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package convert
+
+import (
+	"context"
+
+	"github.com/compose-spec/compose-go/types"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+// isInternal returns true if all the networks a service is attached to are internal, its ports are then only reachable
+// from other services and are not opened on the container group public IP
+func isInternal(project types.Project, service types.ServiceConfig) bool {
+	if len(service.Networks) == 0 {
+		return false
+	}
+	for net := range service.Networks {
+		if !project.Networks[net].Internal {
+			return false
+		}
+	}
+	return true
+}
+
+// warnNetworkIsolation warns when services are attached to separate networks, as containers of a group share the same
+// network namespace and can always reach each other
+func warnNetworkIsolation(ctx context.Context, project types.Project) {
+	for _, service := range project.Services {
+		for _, other := range project.Services {
+			if service.Name < other.Name && !shareNetwork(service, other) {
+				compose.Warn(ctx, "services %s and %s are attached to separate networks, but can reach each other in the ACI container group", service.Name, other.Name)
+			}
+		}
+	}
+}
+
+func shareNetwork(service types.ServiceConfig, other types.ServiceConfig) bool {
+	if len(service.Networks) == 0 || len(other.Networks) == 0 {
+		return len(service.Networks) == len(other.Networks)
+	}
+	for net := range service.Networks {
+		if _, ok := other.Networks[net]; ok {
+			return true
+		}
+	}
+	return false
+}
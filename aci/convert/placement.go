@@ -0,0 +1,73 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package convert
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const (
+	// ZoneTag records on a container group the availability zone it is deployed to
+	ZoneTag = "docker-compose-zone"
+	// SubnetTag records on a private container group the virtual network subnet it is deployed to, which isn't
+	// returned by the container groups API version used to read groups
+	SubnetTag = "docker-compose-subnet"
+)
+
+// availabilityZones are the zones of Azure regions supporting them
+var availabilityZones = []string{"1", "2", "3"}
+
+// GroupZone returns the availability zone satisfying the zone constraints of all project services, none if they
+// don't set any. A container group runs in a single zone, so services can't be spread across zones.
+func GroupZone(ctx context.Context, project types.Project) (string, error) {
+	zones := availabilityZones
+	zoned := false
+	for _, service := range project.Services {
+		constraints, err := compose.PlacementConstraints(service)
+		if err != nil {
+			return "", err
+		}
+		for _, c := range constraints {
+			if c.Attribute != compose.ZoneAttribute {
+				return "", errors.Wrapf(errdefs.ErrNotImplemented, "service %q can't be placed by %s on ACI", service.Name, c.Attribute)
+			}
+			zoned = true
+		}
+		zones = compose.AllowedZones(constraints, zones)
+		spread, err := compose.SpreadZones(service)
+		if err != nil {
+			return "", err
+		}
+		if spread {
+			compose.Warn(ctx, "service %s can't be spread across availability zones, as the ACI container group runs in a single zone", service.Name)
+		}
+	}
+	if !zoned {
+		return "", nil
+	}
+	if len(zones) == 0 {
+		return "", fmt.Errorf("services placement constraints don't allow any availability zone, zones are %v", availabilityZones)
+	}
+	return zones[0], nil
+}
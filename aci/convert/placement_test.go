@@ -0,0 +1,48 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package convert
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func zonedService(name string, constraints ...string) types.ServiceConfig {
+	return types.ServiceConfig{Name: name, Deploy: &types.DeployConfig{Placement: types.Placement{Constraints: constraints}}}
+}
+
+func TestGroupZone(t *testing.T) {
+	zone, err := GroupZone(context.TODO(), types.Project{Services: []types.ServiceConfig{{Name: "web"}}})
+	assert.NilError(t, err)
+	assert.Equal(t, zone, "")
+
+	zone, err = GroupZone(context.TODO(), types.Project{Services: []types.ServiceConfig{
+		zonedService("web", "node.zone != 1"),
+		zonedService("db", "node.zone != 2"),
+	}})
+	assert.NilError(t, err)
+	assert.Equal(t, zone, "3")
+
+	_, err = GroupZone(context.TODO(), types.Project{Services: []types.ServiceConfig{
+		zonedService("web", "node.zone == 1"),
+		zonedService("db", "node.zone == 2"),
+	}})
+	assert.ErrorContains(t, err, "don't allow any availability zone")
+}
@@ -0,0 +1,92 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package convert
+
+import (
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const (
+	// LinuxPlatform is the platform of ACI Linux container groups
+	LinuxPlatform = "linux/amd64"
+	// WindowsPlatform is the platform of ACI Windows container groups
+	WindowsPlatform = "windows/amd64"
+)
+
+// GroupOSType returns the OS of the container group running the project, from the platform of its services.
+// Platforms can't be mixed in a group, ACI only runs amd64 containers, and Windows groups only run a single container
+// without volumes.
+func GroupOSType(project types.Project) (containerinstance.OperatingSystemTypes, error) {
+	platform, err := compose.SinglePlatform("ACI container group", project.Services, LinuxPlatform)
+	if err != nil {
+		return "", err
+	}
+	switch platform {
+	case LinuxPlatform:
+		return containerinstance.Linux, nil
+	case WindowsPlatform:
+	default:
+		return "", errors.Wrapf(errdefs.ErrNotImplemented, "ACI only runs %s and %s containers, services %s target %s",
+			LinuxPlatform, WindowsPlatform, strings.Join(project.ServiceNames(), ", "), platform)
+	}
+	if err := validateWindowsGroup(project); err != nil {
+		return "", err
+	}
+	return containerinstance.Windows, nil
+}
+
+func validateWindowsGroup(project types.Project) error {
+	if len(project.Services) > 1 {
+		return errors.Wrap(errdefs.ErrNotImplemented, "ACI Windows container groups run a single container, deploy Windows services as separate projects")
+	}
+	service := project.Services[0]
+	if len(service.Volumes) > 0 || len(service.Configs) > 0 {
+		return errors.Wrapf(errdefs.ErrNotImplemented, "volumes can't be mounted in ACI Windows containers, remove volumes and configs from service %q", service.Name)
+	}
+	injection, err := compose.SecretInjection(service)
+	if err != nil {
+		return err
+	}
+	if len(service.Secrets) > 0 && injection == compose.SecretInjectionFile {
+		return errors.Wrapf(errdefs.ErrNotImplemented, "secrets can't be mounted as files in ACI Windows containers, inject secrets of service %q as environment variables", service.Name)
+	}
+	sidecars, err := groupSidecars(project, service)
+	if err != nil {
+		return err
+	}
+	if len(sidecars) > 0 {
+		return errors.Wrapf(errdefs.ErrNotImplemented, "ACI Windows container groups run a single container, remove sidecars of service %q", service.Name)
+	}
+	return nil
+}
+
+// IsWindowsPlatform returns true if the platform runs Windows containers
+func IsWindowsPlatform(platform string) bool {
+	return strings.HasPrefix(strings.ToLower(platform), "windows")
+}
+
+// IsWindowsGroup returns true if the container group runs Windows containers
+func IsWindowsGroup(group containerinstance.ContainerGroup) bool {
+	return group.ContainerGroupProperties != nil && group.OsType == containerinstance.Windows
+}
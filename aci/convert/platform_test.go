@@ -0,0 +1,72 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package convert
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestGroupOSType(t *testing.T) {
+	osType, err := GroupOSType(types.Project{
+		Services: []types.ServiceConfig{{Name: "web", Image: "nginx"}, {Name: "db", Image: "mysql", Platform: "linux/amd64"}},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, osType, containerinstance.Linux)
+
+	osType, err = GroupOSType(types.Project{
+		Services: []types.ServiceConfig{{Name: "iis", Image: "mcr.microsoft.com/windows/servercore/iis", Platform: "windows/amd64"}},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, osType, containerinstance.Windows)
+}
+
+func TestGroupOSTypeMixed(t *testing.T) {
+	_, err := GroupOSType(types.Project{
+		Services: []types.ServiceConfig{{Name: "web", Image: "nginx"}, {Name: "iis", Image: "iis", Platform: "windows/amd64"}},
+	})
+	assert.Assert(t, errdefs.IsErrNotImplemented(err))
+	assert.ErrorContains(t, err, "ACI container group can't mix platforms: linux/amd64 (web), windows/amd64 (iis)")
+}
+
+func TestGroupOSTypeArm(t *testing.T) {
+	_, err := GroupOSType(types.Project{
+		Services: []types.ServiceConfig{{Name: "web", Image: "nginx", Platform: "linux/arm64"}},
+	})
+	assert.Assert(t, errdefs.IsErrNotImplemented(err))
+	assert.ErrorContains(t, err, "ACI only runs linux/amd64 and windows/amd64 containers, services web target linux/arm64")
+}
+
+func TestGroupOSTypeWindowsVolumes(t *testing.T) {
+	_, err := GroupOSType(types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:     "iis",
+				Image:    "iis",
+				Platform: "windows/amd64",
+				Volumes:  []types.ServiceVolumeConfig{{Source: "data", Target: "C:\\data"}},
+			},
+		},
+	})
+	assert.Assert(t, errdefs.IsErrNotImplemented(err))
+	assert.ErrorContains(t, err, `volumes can't be mounted in ACI Windows containers, remove volumes and configs from service "iis"`)
+}
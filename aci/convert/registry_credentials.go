@@ -122,6 +122,10 @@ func getUsedRegistries(project compose.Project) (map[string]bool, []string) {
 	acrRegistries := []string{}
 	for _, service := range project.Services {
 		imageName := service.Image
+		if imageName == "" {
+			// nothing to pull for a service which is only built
+			continue
+		}
 		tokens := strings.Split(imageName, "/")
 		registry := tokens[0]
 		if len(tokens) == 1 { // ! image names can include "." ...
@@ -72,6 +72,15 @@ func TestInvalidCredentials(t *testing.T) {
 	assert.Equal(t, len(creds), 0)
 }
 
+func TestBuildOnlyServiceNeedsNoCredentials(t *testing.T) {
+	registryHelper := &MockRegistryHelper{}
+	registryHelper.On(getAllCredentials).Return(registry("https://index.docker.io", userPwdCreds("toto", "pwd")), nil)
+
+	creds, err := getRegistryCredentials(composeServices(""), registryHelper)
+	assert.NilError(t, err)
+	assert.Equal(t, len(creds), 0)
+}
+
 func TestImageWithDotInName(t *testing.T) {
 	registryHelper := &MockRegistryHelper{}
 	registryHelper.On(getAllCredentials).Return(registry("index.docker.io", userPwdCreds("toto", "pwd")), nil)
@@ -0,0 +1,155 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package convert
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+// GroupLimits are the resources a container group can use in a region
+type GroupLimits struct {
+	CPU      float64
+	MemoryGB float64
+}
+
+// DefaultGroupLimits are the resources a container group can use in most regions, without GPU
+var DefaultGroupLimits = GroupLimits{CPU: 4, MemoryGB: 16}
+
+// groupResource is the resources requested by a container of the group
+type groupResource struct {
+	name string
+	cpu  float64
+	mem  float64
+}
+
+// ValidateResources checks ACI can allocate the resources of the containers of the project group before anything gets deployed,
+// suggesting the nearest valid configuration
+func ValidateResources(project types.Project, limits GroupLimits) error {
+	var errs *multierror.Error
+	var containers []groupResource
+	for _, service := range project.Services {
+		sidecars, err := groupSidecars(project, service)
+		if err != nil {
+			return err
+		}
+		for _, s := range append([]types.ServiceConfig{service}, sidecars...) {
+			name := service.Name
+			if s.Name != service.Name {
+				name = fmt.Sprintf("%s-%s", service.Name, s.Name)
+			}
+			cpu, mem, err := ServiceResources(s)
+			if err != nil {
+				errs = multierror.Append(errs, errors.Wrapf(err, "service %q", name))
+				continue
+			}
+			if cpu <= 0 || mem <= 0 {
+				errs = multierror.Append(errs, fmt.Errorf("service %q must request a positive amount of CPU and memory", name))
+				continue
+			}
+			if !isIncrement(mem, 0.1) {
+				errs = multierror.Append(errs, fmt.Errorf("service %q requests %gGB of memory, ACI requires increments of 0.1GB: the nearest valid configuration is memory: %gG",
+					name, mem, roundUp(mem, 0.1)))
+			}
+			if !isIncrement(cpu, 0.01) {
+				errs = multierror.Append(errs, fmt.Errorf("service %q requests %g CPUs, ACI requires increments of 0.01: the nearest valid configuration is cpus: %g",
+					name, cpu, roundUp(cpu, 0.01)))
+			}
+			containers = append(containers, groupResource{name: name, cpu: roundUp(cpu, 0.01), mem: roundUp(mem, 0.1)})
+		}
+	}
+	if len(containers) > 1 {
+		containers = append(containers, groupResource{name: ComposeDNSSidecarName, cpu: 0.01, mem: 0.1})
+	}
+	if err := validateGroupTotal(containers, limits); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	return compose.ValidationErrors(errs)
+}
+
+// GroupCPUs returns the number of CPUs requested by the containers of the project group, including sidecars
+func GroupCPUs(project types.Project) (float64, error) {
+	total := 0.
+	containers := 0
+	for _, service := range project.Services {
+		sidecars, err := groupSidecars(project, service)
+		if err != nil {
+			return 0, err
+		}
+		for _, s := range append([]types.ServiceConfig{service}, sidecars...) {
+			cpu, _, err := ServiceResources(s)
+			if err != nil {
+				return 0, err
+			}
+			total += cpu
+			containers++
+		}
+	}
+	if containers > 1 {
+		// DNS sidecar
+		total += 0.01
+	}
+	return total, nil
+}
+
+// validateGroupTotal checks the containers fit in a container group, or suggests scaling them down proportionally
+func validateGroupTotal(containers []groupResource, limits GroupLimits) error {
+	var cpu, mem float64
+	for _, c := range containers {
+		cpu += c.cpu
+		mem += c.mem
+	}
+	if cpu <= limits.CPU+1e-9 && mem <= limits.MemoryGB+1e-9 {
+		return nil
+	}
+	cpuRatio := math.Min(limits.CPU/cpu, 1)
+	memRatio := math.Min(limits.MemoryGB/mem, 1)
+	var suggestions []string
+	for _, c := range containers {
+		if c.name == ComposeDNSSidecarName {
+			continue
+		}
+		suggestions = append(suggestions, fmt.Sprintf("%s: cpus: %g, memory: %gG", c.name,
+			math.Max(roundDown(c.cpu*cpuRatio, 0.01), 0.01), math.Max(roundDown(c.mem*memRatio, 0.1), 0.1)))
+	}
+	return fmt.Errorf("the project requests %g CPUs and %gGB of memory, exceeding the %g CPUs and %gGB available to an ACI container group: "+
+		"the nearest valid configuration is\n  %s", round(cpu, 0.01), round(mem, 0.1), limits.CPU, limits.MemoryGB, strings.Join(suggestions, "\n  "))
+}
+
+func isIncrement(value, increment float64) bool {
+	return math.Abs(value-round(value, increment)) < 1e-9
+}
+
+func round(value, increment float64) float64 {
+	// divide by the number of increments per unit, so 0.3 isn't printed as 0.30000000000000004
+	return math.Round(value/increment) / math.Round(1/increment)
+}
+
+func roundUp(value, increment float64) float64 {
+	return round(math.Ceil(value/increment-1e-9)*increment, increment)
+}
+
+func roundDown(value, increment float64) float64 {
+	return round(math.Floor(value/increment+1e-9)*increment, increment)
+}
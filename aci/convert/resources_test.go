@@ -0,0 +1,69 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package convert
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func withLimits(name string, cpus string, memory types.UnitBytes) types.ServiceConfig {
+	return types.ServiceConfig{
+		Name:  name,
+		Image: "nginx",
+		Deploy: &types.DeployConfig{
+			Resources: types.Resources{
+				Limits: &types.Resource{NanoCPUs: cpus, MemoryBytes: memory},
+			},
+		},
+	}
+}
+
+func TestValidateResources(t *testing.T) {
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			{Name: "default", Image: "nginx"},
+			withLimits("web", "1.5", 2*1024*1024*1024),
+		},
+	}
+	assert.NilError(t, ValidateResources(project, DefaultGroupLimits))
+}
+
+func TestValidateResourcesIncrements(t *testing.T) {
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			withLimits("web", "0.005", 250*1024*1024),
+		},
+	}
+	err := ValidateResources(project, DefaultGroupLimits)
+	assert.ErrorContains(t, err, `service "web" requests 0.24GB of memory, ACI requires increments of 0.1GB: the nearest valid configuration is memory: 0.3G`)
+	assert.ErrorContains(t, err, `service "web" requests 0.005 CPUs, ACI requires increments of 0.01: the nearest valid configuration is cpus: 0.01`)
+}
+
+func TestValidateResourcesGroupTotal(t *testing.T) {
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			withLimits("web", "4", 8*1024*1024*1024),
+			withLimits("db", "4", 8*1024*1024*1024),
+		},
+	}
+	err := ValidateResources(project, DefaultGroupLimits)
+	assert.ErrorContains(t, err, "the project requests 8.01 CPUs and 16.1GB of memory, exceeding the 4 CPUs and 16GB available to an ACI container group")
+	assert.ErrorContains(t, err, "web: cpus: 1.99, memory: 7.9G\n  db: cpus: 1.99, memory: 7.9G")
+}
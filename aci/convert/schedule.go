@@ -0,0 +1,83 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package convert
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// Recurrence is the recurrence of a Logic App schedule trigger
+type Recurrence struct {
+	Frequency string             `json:"frequency"`
+	Interval  int                `json:"interval"`
+	TimeZone  string             `json:"timeZone"`
+	Schedule  RecurrenceSchedule `json:"schedule"`
+}
+
+// RecurrenceSchedule restricts when a Logic App recurrence runs
+type RecurrenceSchedule struct {
+	Minutes   []int    `json:"minutes"`
+	Hours     []int    `json:"hours"`
+	WeekDays  []string `json:"weekDays,omitempty"`
+	MonthDays []int    `json:"monthDays,omitempty"`
+}
+
+// ToRecurrence converts a cron schedule to a Logic App recurrence, running on UTC time.
+// Recurrences can't restrict months, nor both days of month and days of week.
+func ToRecurrence(schedule compose.Schedule) (Recurrence, error) {
+	if schedule.Fields[3] != "*" {
+		return Recurrence{}, errors.Wrapf(errdefs.ErrNotImplemented, "schedule %s: Logic Apps can't restrict months", schedule)
+	}
+	r := Recurrence{
+		Frequency: "Day",
+		Interval:  1,
+		TimeZone:  "UTC",
+		Schedule: RecurrenceSchedule{
+			Minutes: valuesOrAll(schedule.Values(0), 0, 59),
+			Hours:   valuesOrAll(schedule.Values(1), 0, 23),
+		},
+	}
+	monthDays, weekDays := schedule.Values(2), schedule.Values(4)
+	switch {
+	case monthDays != nil && weekDays != nil:
+		return Recurrence{}, errors.Wrapf(errdefs.ErrNotImplemented, "schedule %s: Logic Apps can't restrict both days of month and days of week", schedule)
+	case monthDays != nil:
+		r.Frequency = "Month"
+		r.Schedule.MonthDays = monthDays
+	case weekDays != nil:
+		r.Frequency = "Week"
+		for _, d := range weekDays {
+			r.Schedule.WeekDays = append(r.Schedule.WeekDays, time.Weekday(d).String())
+		}
+	}
+	return r, nil
+}
+
+func valuesOrAll(values []int, min, max int) []int {
+	if values != nil {
+		return values
+	}
+	for v := min; v <= max; v++ {
+		values = append(values, v)
+	}
+	return values
+}
@@ -0,0 +1,61 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package convert
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func recurrence(t *testing.T, expression string) (Recurrence, error) {
+	schedule, err := compose.ParseSchedule(expression)
+	assert.NilError(t, err)
+	return ToRecurrence(schedule)
+}
+
+func TestToRecurrence(t *testing.T) {
+	r, err := recurrence(t, "cron(30 3 * * *)")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, r, Recurrence{
+		Frequency: "Day",
+		Interval:  1,
+		TimeZone:  "UTC",
+		Schedule:  RecurrenceSchedule{Minutes: []int{30}, Hours: []int{3}},
+	})
+
+	r, err = recurrence(t, "cron(0 */12 * * 1,5)")
+	assert.NilError(t, err)
+	assert.Equal(t, r.Frequency, "Week")
+	assert.DeepEqual(t, r.Schedule.Hours, []int{0, 12})
+	assert.DeepEqual(t, r.Schedule.WeekDays, []string{"Monday", "Friday"})
+
+	r, err = recurrence(t, "cron(0 0 1,15 * *)")
+	assert.NilError(t, err)
+	assert.Equal(t, r.Frequency, "Month")
+	assert.DeepEqual(t, r.Schedule.MonthDays, []int{1, 15})
+}
+
+func TestToRecurrenceUnsupported(t *testing.T) {
+	_, err := recurrence(t, "cron(0 0 1 * 1)")
+	assert.Assert(t, errdefs.IsErrNotImplemented(err))
+	_, err = recurrence(t, "cron(0 0 * 6 *)")
+	assert.Assert(t, errdefs.IsErrNotImplemented(err))
+}
@@ -0,0 +1,152 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/aci/convert"
+	"github.com/docker/compose-cli/aci/login"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// azurePricesURL is the Azure Retail Prices API endpoint, which doesn't require authentication
+var azurePricesURL = "https://prices.azure.com/api/retail/prices"
+
+const (
+	vCPUMeter   = "Standard vCPU Duration"
+	memoryMeter = "Standard Memory Duration"
+)
+
+type retailPrices struct {
+	Items []struct {
+		CurrencyCode  string  `json:"currencyCode"`
+		RetailPrice   float64 `json:"retailPrice"`
+		MeterName     string  `json:"meterName"`
+		SkuName       string  `json:"skuName"`
+		UnitOfMeasure string  `json:"unitOfMeasure"`
+	}
+	NextPageLink string
+}
+
+func (cs *aciComposeService) Estimate(ctx context.Context, project *types.Project) ([]compose.CostEstimate, error) {
+	cpuPrice, memoryPrice, currency, err := containerInstancesPrices(ctx, cs.ctx.Location, cs.ctx.Operations())
+	if err != nil {
+		return nil, err
+	}
+	estimates := []compose.CostEstimate{}
+	for _, service := range project.Services {
+		cpus, memory, err := convert.ServiceResources(service)
+		if err != nil {
+			return nil, err
+		}
+		// ACI runs a single container per service
+		estimates = append(estimates, compose.CostEstimate{
+			Service:  service.Name,
+			Replicas: 1,
+			CPUs:     cpus,
+			MemoryGB: memory,
+			Hourly:   cpus*cpuPrice + memory*memoryPrice,
+			Currency: currency,
+		})
+	}
+	return estimates, nil
+}
+
+// containerInstancesPrices returns hourly prices for a vCPU and a GB of memory in location
+func containerInstancesPrices(ctx context.Context, location string, ops store.Operations) (float64, float64, string, error) {
+	client, err := login.NewRetailPricesClient(ops)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	filter := fmt.Sprintf("serviceName eq 'Container Instances' and armRegionName eq '%s' and priceType eq 'Consumption'", location)
+	next := azurePricesURL + "?$filter=" + url.QueryEscape(filter)
+	var cpuPrice, memoryPrice float64
+	var currency string
+	for next != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, next, nil)
+		if err != nil {
+			return 0, 0, "", err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, 0, "", errors.Wrap(err, "cannot get Azure Container Instances prices")
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			resp.Body.Close() // nolint:errcheck
+			return 0, 0, "", errors.Errorf("cannot get Azure Container Instances prices: %s returned status %d", req.URL.Host, resp.StatusCode)
+		}
+		var prices retailPrices
+		err = json.NewDecoder(resp.Body).Decode(&prices)
+		resp.Body.Close() // nolint:errcheck
+		if err != nil {
+			return 0, 0, "", errors.Wrap(err, "cannot parse Azure Container Instances prices")
+		}
+		for _, item := range prices.Items {
+			if item.SkuName != "Standard" || (item.MeterName != vCPUMeter && item.MeterName != memoryMeter) {
+				continue
+			}
+			hours, err := unitHours(item.UnitOfMeasure)
+			if err != nil {
+				return 0, 0, "", err
+			}
+			currency = item.CurrencyCode
+			if item.MeterName == vCPUMeter {
+				cpuPrice = item.RetailPrice / hours
+			} else {
+				memoryPrice = item.RetailPrice / hours
+			}
+		}
+		next = prices.NextPageLink
+	}
+	if cpuPrice == 0 || memoryPrice == 0 {
+		return 0, 0, "", errors.Wrapf(errdefs.ErrNotFound, "Azure Container Instances prices for location %q", location)
+	}
+	return cpuPrice, memoryPrice, currency, nil
+}
+
+// unitHours converts a pricing unit of measure like "1 Hour" or "100 Seconds" into hours
+func unitHours(unit string) (float64, error) {
+	tokens := strings.Fields(unit)
+	if len(tokens) != 2 {
+		return 0, fmt.Errorf("unsupported pricing unit %q", unit)
+	}
+	quantity, err := strconv.ParseFloat(tokens[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("unsupported pricing unit %q", unit)
+	}
+	switch strings.TrimSuffix(strings.ToLower(tokens[1]), "s") {
+	case "hour":
+		return quantity, nil
+	case "minute":
+		return quantity / 60, nil
+	case "second":
+		return quantity / 3600, nil
+	}
+	return 0, fmt.Errorf("unsupported pricing unit %q", unit)
+}
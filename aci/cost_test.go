@@ -0,0 +1,98 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/context/store"
+)
+
+func TestEstimate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.URL.Query().Get("$filter"), "serviceName eq 'Container Instances' and armRegionName eq 'eastus' and priceType eq 'Consumption'")
+		fmt.Fprint(w, `{"Items": [
+			{"currencyCode": "USD", "retailPrice": 0.04, "meterName": "Standard vCPU Duration", "skuName": "Standard", "unitOfMeasure": "1 Hour"},
+			{"currencyCode": "USD", "retailPrice": 0.5, "meterName": "Standard Memory Duration", "skuName": "Standard", "unitOfMeasure": "100 Hours"},
+			{"currencyCode": "USD", "retailPrice": 1, "meterName": "Windows Software Duration", "skuName": "Standard", "unitOfMeasure": "1 Second"}
+		]}`)
+	}))
+	defer server.Close()
+	defer func(u string) { azurePricesURL = u }(azurePricesURL)
+	azurePricesURL = server.URL
+
+	cs := newComposeService(store.AciContext{Location: "eastus"})
+	estimates, err := cs.Estimate(context.TODO(), &types.Project{
+		Services: []types.ServiceConfig{
+			{Name: "web"},
+		},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(estimates), 1)
+	assert.Equal(t, estimates[0].Currency, "USD")
+	assert.Equal(t, estimates[0].CPUs, 1.)
+	assert.Equal(t, estimates[0].MemoryGB, 1.)
+	assert.Equal(t, fmt.Sprintf("%.3f", estimates[0].Hourly), "0.045")
+}
+
+func TestEstimatePricesUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"Items": []}`)
+	}))
+	defer server.Close()
+	defer func(u string) { azurePricesURL = u }(azurePricesURL)
+	azurePricesURL = server.URL
+
+	cs := newComposeService(store.AciContext{Location: "eastus"})
+	_, err := cs.Estimate(context.TODO(), &types.Project{
+		Services: []types.ServiceConfig{
+			{Name: "web"},
+		},
+	})
+	assert.ErrorContains(t, err, "returned status 429")
+}
+
+func TestUnitHours(t *testing.T) {
+	hours, err := unitHours("1 Second")
+	assert.NilError(t, err)
+	assert.Equal(t, hours, 1./3600)
+	_, err = unitHours("1 GB")
+	assert.ErrorContains(t, err, "unsupported pricing unit")
+}
+
+func TestCheckBudget(t *testing.T) {
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			{Name: "web"},
+			{Name: "db"},
+		},
+	}
+	aciContext := store.AciContext{Budget: store.Budget{MaxCPUs: 4, MaxMemoryGB: 1.5}}
+	err := checkBudget(context.TODO(), aciContext, project)
+	assert.ErrorContains(t, err, "requested 2.00GB of memory exceed the context budget of 1.50GB")
+
+	aciContext.Budget.MaxMemoryGB = 2
+	assert.NilError(t, checkBudget(context.TODO(), aciContext, project))
+}
@@ -0,0 +1,201 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/aci/convert"
+	"github.com/docker/compose-cli/aci/login"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/registry"
+)
+
+// groupSpec is the user controlled part of a container group, ignoring the runtime state returned by ACI
+type groupSpec struct {
+	Containers    []containerSpec `json:"containers"`
+	Ports         []string        `json:"ports,omitempty"`
+	DNSLabel      string          `json:"dnsLabel,omitempty"`
+	RestartPolicy string          `json:"restartPolicy,omitempty"`
+	Volumes       []string        `json:"volumes,omitempty"`
+}
+
+type containerSpec struct {
+	Name         string   `json:"name"`
+	Image        string   `json:"image"`
+	Command      []string `json:"command,omitempty"`
+	Environment  []string `json:"environment,omitempty"`
+	Ports        []string `json:"ports,omitempty"`
+	CPU          float64  `json:"cpu"`
+	MemoryInGB   float64  `json:"memoryInGB"`
+	VolumeMounts []string `json:"volumeMounts,omitempty"`
+}
+
+// Diff compares the deployed container group with the one generated from the compose file. Changes made from the
+// Azure portal are applied to the group itself, so they show up in the diff.
+func (cs *aciComposeService) Diff(ctx context.Context, project *types.Project) (compose.ProjectDiff, error) {
+	groupsClient, err := login.NewContainerGroupsClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
+	if err != nil {
+		return compose.ProjectDiff{}, err
+	}
+	deployed, err := groupsClient.Get(ctx, cs.ctx.ResourceGroup, strings.ToLower(project.Name))
+	if err != nil {
+		if deployed.StatusCode == http.StatusNotFound {
+			return compose.ProjectDiff{}, errors.Wrapf(errdefs.ErrNotFound, "application %q", project.Name)
+		}
+		return compose.ProjectDiff{}, err
+	}
+
+	if cs.ctx.ResolveImageDigests {
+		if err := registry.PinImages(ctx, project); err != nil {
+			return compose.ProjectDiff{}, err
+		}
+	}
+	local, err := convert.ToContainerGroup(ctx, cs.ctx, *project, cs.storageLogin)
+	if err != nil {
+		return compose.ProjectDiff{}, err
+	}
+
+	deployedSpec, err := marshalGroupSpec(deployed)
+	if err != nil {
+		return compose.ProjectDiff{}, err
+	}
+	localSpec, err := marshalGroupSpec(local)
+	if err != nil {
+		return compose.ProjectDiff{}, err
+	}
+	return compose.ProjectDiff{
+		Deployed: deployedSpec,
+		Local:    localSpec,
+	}, nil
+}
+
+func marshalGroupSpec(group containerinstance.ContainerGroup) ([]byte, error) {
+	return json.MarshalIndent(toGroupSpec(group), "", "  ")
+}
+
+func toGroupSpec(group containerinstance.ContainerGroup) groupSpec {
+	spec := groupSpec{
+		RestartPolicy: string(group.RestartPolicy),
+	}
+	if group.IPAddress != nil {
+		if group.IPAddress.DNSNameLabel != nil {
+			spec.DNSLabel = *group.IPAddress.DNSNameLabel
+		}
+		if group.IPAddress.Ports != nil {
+			for _, port := range *group.IPAddress.Ports {
+				spec.Ports = append(spec.Ports, portSpec(port.Port, string(port.Protocol)))
+			}
+		}
+	}
+	if group.Volumes != nil {
+		for _, volume := range *group.Volumes {
+			kind := "emptyDir"
+			switch {
+			case volume.AzureFile != nil:
+				kind = fmt.Sprintf("azureFile %s/%s", stringValue(volume.AzureFile.StorageAccountName), stringValue(volume.AzureFile.ShareName))
+			case volume.Secret != nil:
+				kind = "secret"
+			}
+			spec.Volumes = append(spec.Volumes, fmt.Sprintf("%s (%s)", stringValue(volume.Name), kind))
+		}
+	}
+	if group.Containers != nil {
+		for _, container := range *group.Containers {
+			spec.Containers = append(spec.Containers, toContainerSpec(container))
+		}
+	}
+	sort.Strings(spec.Ports)
+	sort.Strings(spec.Volumes)
+	sort.Slice(spec.Containers, func(i, j int) bool {
+		return spec.Containers[i].Name < spec.Containers[j].Name
+	})
+	return spec
+}
+
+func toContainerSpec(container containerinstance.Container) containerSpec {
+	spec := containerSpec{
+		Name:  stringValue(container.Name),
+		Image: stringValue(container.Image),
+	}
+	if container.Command != nil {
+		spec.Command = *container.Command
+	}
+	if container.EnvironmentVariables != nil {
+		for _, variable := range *container.EnvironmentVariables {
+			value := "<secure>"
+			if variable.Value != nil {
+				value = *variable.Value
+			}
+			spec.Environment = append(spec.Environment, fmt.Sprintf("%s=%s", stringValue(variable.Name), value))
+		}
+	}
+	if container.Ports != nil {
+		for _, port := range *container.Ports {
+			spec.Ports = append(spec.Ports, portSpec(port.Port, string(port.Protocol)))
+		}
+	}
+	// requests are always returned by ACI, limits only when set
+	if container.Resources != nil && container.Resources.Requests != nil {
+		if container.Resources.Requests.CPU != nil {
+			spec.CPU = *container.Resources.Requests.CPU
+		}
+		if container.Resources.Requests.MemoryInGB != nil {
+			spec.MemoryInGB = *container.Resources.Requests.MemoryInGB
+		}
+	}
+	if container.VolumeMounts != nil {
+		for _, mount := range *container.VolumeMounts {
+			m := fmt.Sprintf("%s:%s", stringValue(mount.Name), stringValue(mount.MountPath))
+			if mount.ReadOnly != nil && *mount.ReadOnly {
+				m += ":ro"
+			}
+			spec.VolumeMounts = append(spec.VolumeMounts, m)
+		}
+	}
+	sort.Strings(spec.Environment)
+	sort.Strings(spec.Ports)
+	sort.Strings(spec.VolumeMounts)
+	return spec
+}
+
+func portSpec(port *int32, protocol string) string {
+	if protocol == "" {
+		protocol = "TCP"
+	}
+	if port == nil {
+		return protocol
+	}
+	return fmt.Sprintf("%d/%s", *port, strings.ToLower(protocol))
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,67 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/Azure/go-autorest/autorest/to"
+	"gotest.tools/v3/assert"
+)
+
+func TestGroupSpecIgnoresRuntimeState(t *testing.T) {
+	container := func(name string, view *containerinstance.ContainerPropertiesInstanceView, env containerinstance.EnvironmentVariable) containerinstance.Container {
+		return containerinstance.Container{
+			Name: to.StringPtr(name),
+			ContainerProperties: &containerinstance.ContainerProperties{
+				Image:                to.StringPtr("nginx"),
+				EnvironmentVariables: &[]containerinstance.EnvironmentVariable{env},
+				InstanceView:         view,
+				Resources: &containerinstance.ResourceRequirements{
+					Requests: &containerinstance.ResourceRequests{CPU: to.Float64Ptr(1), MemoryInGB: to.Float64Ptr(1.5)},
+				},
+			},
+		}
+	}
+	local := containerinstance.ContainerGroup{
+		ContainerGroupProperties: &containerinstance.ContainerGroupProperties{
+			Containers: &[]containerinstance.Container{
+				container("web", nil, containerinstance.EnvironmentVariable{Name: to.StringPtr("TOKEN"), SecureValue: to.StringPtr("secret")}),
+				container("db", nil, containerinstance.EnvironmentVariable{Name: to.StringPtr("MODE"), Value: to.StringPtr("primary")}),
+			},
+		},
+	}
+	running := &containerinstance.ContainerPropertiesInstanceView{RestartCount: to.Int32Ptr(2)}
+	deployed := containerinstance.ContainerGroup{
+		ID: to.StringPtr("/subscriptions/id/resourceGroups/rg/providers/Microsoft.ContainerInstance/containerGroups/demo"),
+		ContainerGroupProperties: &containerinstance.ContainerGroupProperties{
+			ProvisioningState: to.StringPtr("Succeeded"),
+			Containers: &[]containerinstance.Container{
+				container("db", running, containerinstance.EnvironmentVariable{Name: to.StringPtr("MODE"), Value: to.StringPtr("primary")}),
+				container("web", running, containerinstance.EnvironmentVariable{Name: to.StringPtr("TOKEN")}),
+			},
+		},
+	}
+
+	localSpec, err := marshalGroupSpec(local)
+	assert.NilError(t, err)
+	deployedSpec, err := marshalGroupSpec(deployed)
+	assert.NilError(t, err)
+	assert.Equal(t, string(deployedSpec), string(localSpec))
+	assert.DeepEqual(t, toGroupSpec(deployed).Containers[1].Environment, []string{"TOKEN=<secure>"})
+}
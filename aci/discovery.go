@@ -0,0 +1,79 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/compose-spec/compose-go/types"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+// Discovery describes how services resolve each other within the project container group: containers share the
+// group network namespace, and the DNS sidecar maps service names to localhost when there are several of them
+func (cs *aciComposeService) Discovery(ctx context.Context, project *types.Project) ([]compose.ServiceDiscovery, error) {
+	mechanism := "container group localhost"
+	if len(project.Services) > 1 {
+		mechanism = "container group localhost, /etc/hosts entries from the DNS sidecar"
+	}
+	discovery := []compose.ServiceDiscovery{}
+	for _, service := range project.Services {
+		hostnames := []string{"localhost"}
+		if len(project.Services) > 1 {
+			hostnames = append(hostnames, service.Name)
+		}
+		discovery = append(discovery, compose.ServiceDiscovery{
+			Service:   service.Name,
+			Hostnames: hostnames,
+			Address:   "127.0.0.1",
+			Ports:     compose.ServicePorts(service),
+			Mechanism: mechanism,
+		})
+	}
+	return discovery, nil
+}
+
+// Exposure describes ports opened on the container group public IP, other ports are only reachable within the group
+func (cs *aciComposeService) Exposure(ctx context.Context, project *types.Project) ([]compose.PortExposure, error) {
+	exposure := []compose.PortExposure{}
+	for _, service := range project.Services {
+		source := "public IP"
+		if service.DomainName != "" {
+			source = fmt.Sprintf("public IP, %s.%s.azurecontainer.io", service.DomainName, cs.ctx.Location)
+		}
+		for _, port := range service.Ports {
+			exposure = append(exposure, compose.PortExposure{
+				Service:  service.Name,
+				Port:     port.Target,
+				Protocol: compose.PortProtocol(port),
+				Source:   "0.0.0.0/0 on " + source,
+			})
+		}
+		for _, port := range compose.InternalPorts(service) {
+			exposure = append(exposure, compose.PortExposure{
+				Service:  service.Name,
+				Port:     port,
+				Protocol: "tcp",
+				Source:   "container group",
+			})
+		}
+	}
+	compose.SortExposure(exposure)
+	return exposure, nil
+}
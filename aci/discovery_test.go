@@ -0,0 +1,52 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/context/store"
+)
+
+func TestDiscoverySingleContainer(t *testing.T) {
+	cs := newComposeService(store.AciContext{})
+	discovery, err := cs.Discovery(context.TODO(), &types.Project{
+		Services: []types.ServiceConfig{
+			{Name: "web", Ports: []types.ServicePortConfig{{Target: 80}}},
+		},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, discovery[0].Hostnames, []string{"localhost"})
+	assert.DeepEqual(t, discovery[0].Ports, []uint32{80})
+}
+
+func TestDiscoveryWithDNSSidecar(t *testing.T) {
+	cs := newComposeService(store.AciContext{})
+	discovery, err := cs.Discovery(context.TODO(), &types.Project{
+		Services: []types.ServiceConfig{
+			{Name: "web"},
+			{Name: "db"},
+		},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, discovery[1].Hostnames, []string{"localhost", "db"})
+	assert.Equal(t, discovery[1].Address, "127.0.0.1")
+}
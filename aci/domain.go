@@ -0,0 +1,271 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/Azure/azure-sdk-for-go/services/dns/mgmt/2018-05-01/dns"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/aci/convert"
+	"github.com/docker/compose-cli/aci/login"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/progress"
+)
+
+const (
+	// extensionDomain serves a service on a custom domain, as a domain name or a mapping
+	extensionDomain = "x-azure-domain"
+	// domainTag is the prefix of the container group tags listing the DNS records created for services
+	domainTag = "docker-compose-domain"
+	// certificatesFolder is where services find the TLS certificate of their domain
+	certificatesFolder = "/run/tls"
+
+	domainRecordTTL = 300
+	pkcs12Content   = "application/x-pkcs12"
+)
+
+// serviceDomain is the custom domain of a service set by x-azure-domain
+type serviceDomain struct {
+	service       string
+	name          string
+	zone          string
+	resourceGroup string
+	certificate   string
+}
+
+// recordName is the name of the domain CNAME record relative to its DNS zone
+func (d serviceDomain) recordName() string {
+	return strings.TrimSuffix(d.name, "."+d.zone)
+}
+
+// tagValue locates the domain DNS record, so that it can be removed with the container group
+func (d serviceDomain) tagValue() string {
+	return strings.Join([]string{d.resourceGroup, d.zone, d.recordName()}, "/")
+}
+
+func projectDomains(project *types.Project, resourceGroup string) ([]serviceDomain, error) {
+	var domains []serviceDomain
+	for _, service := range project.Services {
+		x, ok := service.Extensions[extensionDomain]
+		if !ok {
+			continue
+		}
+		domain := serviceDomain{service: service.Name, resourceGroup: resourceGroup}
+		switch v := x.(type) {
+		case string:
+			domain.name = v
+		case map[string]interface{}:
+			for key, value := range v {
+				var valid bool
+				switch key {
+				case "name":
+					domain.name, valid = value.(string)
+				case "dns_zone":
+					domain.zone, valid = value.(string)
+				case "resource_group":
+					domain.resourceGroup, valid = value.(string)
+				case "certificate":
+					domain.certificate, valid = value.(string)
+				default:
+					return nil, errors.Wrapf(errdefs.ErrParsingFailed, "unsupported %s attribute %q", extensionDomain, key)
+				}
+				if !valid {
+					return nil, errors.Wrapf(errdefs.ErrParsingFailed, "invalid %s %s for service %s: %v", extensionDomain, key, service.Name, value)
+				}
+			}
+		default:
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "invalid %s for service %s: %v", extensionDomain, service.Name, x)
+		}
+		if domain.name == "" {
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "%s for service %s requires a domain name", extensionDomain, service.Name)
+		}
+		if domain.zone != "" {
+			if !strings.HasSuffix(domain.name, "."+domain.zone) {
+				return nil, errors.Wrapf(errdefs.ErrParsingFailed, "domain %s of service %s must be a subdomain of DNS zone %s", domain.name, service.Name, domain.zone)
+			}
+			if service.DomainName == "" {
+				return nil, errors.Wrapf(errdefs.ErrParsingFailed, "service %s must set domainname to get a DNS record for %s", service.Name, domain.name)
+			}
+		}
+		domains = append(domains, domain)
+	}
+	return domains, nil
+}
+
+// addDomainCertificates mounts the Key Vault certificates of service domains as a secret, at /run/tls/<domain>.pem or
+// /run/tls/<domain>.pfx depending on the certificate content type, for services to terminate TLS themselves
+func (cs *aciComposeService) addDomainCertificates(ctx context.Context, project *types.Project, domains []serviceDomain) error {
+	secrets := types.Secrets{}
+	for name, secret := range project.Secrets {
+		secrets[name] = secret
+	}
+	for _, domain := range domains {
+		if domain.certificate == "" {
+			continue
+		}
+		vault, name, version, err := parseKeyVaultSecretID(domain.certificate)
+		if err != nil {
+			return err
+		}
+		client, err := login.NewKeyVaultClient(cs.ctx.Operations())
+		if err != nil {
+			return err
+		}
+		bundle, err := client.GetSecret(ctx, vault, name, version)
+		if err != nil {
+			return errors.Wrapf(err, "cannot read certificate of domain %s", domain.name)
+		}
+		data := []byte(to.String(bundle.Value))
+		file := domain.name + ".pem"
+		if to.String(bundle.ContentType) == pkcs12Content {
+			if data, err = base64.StdEncoding.DecodeString(to.String(bundle.Value)); err != nil {
+				return errors.Wrapf(err, "invalid certificate of domain %s", domain.name)
+			}
+			file = domain.name + ".pfx"
+		}
+		service, err := project.GetService(domain.service)
+		if err != nil {
+			return err
+		}
+		injection, err := compose.SecretInjection(service)
+		if err != nil {
+			return err
+		}
+		if injection != compose.SecretInjectionFile {
+			return errors.Wrapf(errdefs.ErrNotImplemented, "service %s can't get the certificate of domain %s injected as environment variables", service.Name, domain.name)
+		}
+		secrets[file] = convert.InlineSecret(data)
+		for i := range project.Services {
+			if project.Services[i].Name == service.Name {
+				project.Services[i].Secrets = append(project.Services[i].Secrets, types.ServiceSecretConfig{
+					Source: file,
+					Target: certificatesFolder + "/" + file,
+				})
+			}
+		}
+	}
+	project.Secrets = secrets
+	return nil
+}
+
+// parseKeyVaultSecretID splits a Key Vault secret or certificate identifier like
+// https://<vault>.vault.azure.net/certificates/<name>[/<version>] into the vault URL, secret name and version
+func parseKeyVaultSecretID(id string) (string, string, string, error) {
+	u, err := url.Parse(id)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return "", "", "", errors.Wrapf(errdefs.ErrParsingFailed, "invalid Key Vault certificate %q", id)
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 || len(parts) > 3 || (parts[0] != "secrets" && parts[0] != "certificates") {
+		return "", "", "", errors.Wrapf(errdefs.ErrParsingFailed, "invalid Key Vault certificate %q", id)
+	}
+	version := ""
+	if len(parts) == 3 {
+		version = parts[2]
+	}
+	// the private key of a certificate is only readable as the secret of the same name
+	return "https://" + u.Host, parts[1], version, nil
+}
+
+// tagDomains lists the DNS records of service domains in the container group tags
+func tagDomains(group *containerinstance.ContainerGroup, domains []serviceDomain) {
+	for _, domain := range domains {
+		if domain.zone != "" {
+			group.Tags[domainTag+"."+domain.service] = to.StringPtr(domain.tagValue())
+		}
+	}
+}
+
+// createDomainRecords points service domains to the container group with CNAME records, and removes the records
+// of domains the previous deployment tagged but the project doesn't declare anymore
+func (cs *aciComposeService) createDomainRecords(ctx context.Context, project *types.Project, domains []serviceDomain, previous containerinstance.ContainerGroup) error {
+	w := progress.ContextWriter(ctx)
+	kept := map[string]bool{}
+	for _, domain := range domains {
+		if domain.zone == "" {
+			continue
+		}
+		service, err := project.GetService(domain.service)
+		if err != nil {
+			return err
+		}
+		client, err := login.NewRecordSetsClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
+		if err != nil {
+			return err
+		}
+		kept[domain.tagValue()] = true
+		target := fmt.Sprintf("%s.%s.azurecontainer.io", service.DomainName, cs.ctx.Location)
+		w.Event(progress.Event{
+			ID:         domain.name,
+			Status:     progress.Working,
+			StatusText: "Creating DNS record",
+		})
+		_, err = client.CreateOrUpdate(ctx, domain.resourceGroup, domain.zone, domain.recordName(), dns.CNAME, dns.RecordSet{
+			RecordSetProperties: &dns.RecordSetProperties{
+				TTL:         to.Int64Ptr(domainRecordTTL),
+				CnameRecord: &dns.CnameRecord{Cname: to.StringPtr(target)},
+				Metadata:    map[string]*string{compose.ProjectTag: to.StringPtr(project.Name)},
+			},
+		}, "", "")
+		if err != nil {
+			return errors.Wrapf(err, "cannot create DNS record for domain %s", domain.name)
+		}
+		w.Event(progress.Event{
+			ID:         domain.name,
+			Status:     progress.Done,
+			StatusText: "Created DNS record",
+		})
+	}
+	return cs.removeDomainRecords(ctx, previous, kept)
+}
+
+// removeDomainRecords deletes the DNS records listed in the container group tags, but the kept ones
+func (cs *aciComposeService) removeDomainRecords(ctx context.Context, group containerinstance.ContainerGroup, kept map[string]bool) error {
+	var records []string
+	for tag, value := range group.Tags {
+		if strings.HasPrefix(tag, domainTag+".") && !kept[to.String(value)] {
+			records = append(records, to.String(value))
+		}
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	client, err := login.NewRecordSetsClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		parts := strings.SplitN(record, "/", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if _, err := client.Delete(ctx, parts[0], parts[1], parts[2], dns.CNAME, ""); err != nil && !isNotFound(errors.Cause(err)) {
+			return errors.Wrapf(err, "cannot remove DNS record %s.%s", parts[2], parts[1])
+		}
+	}
+	return nil
+}
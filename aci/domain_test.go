@@ -0,0 +1,98 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestProjectDomains(t *testing.T) {
+	project := &types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:       "web",
+				DomainName: "shop-web",
+				Extensions: map[string]interface{}{
+					extensionDomain: map[string]interface{}{
+						"name":     "shop.example.com",
+						"dns_zone": "example.com",
+					},
+				},
+			},
+			{
+				Name:       "api",
+				Extensions: map[string]interface{}{extensionDomain: "api.example.com"},
+			},
+			{
+				Name: "db",
+			},
+		},
+	}
+	domains, err := projectDomains(project, "rg")
+	assert.NilError(t, err)
+	assert.Equal(t, len(domains), 2)
+	assert.Equal(t, domains[0], serviceDomain{service: "web", name: "shop.example.com", zone: "example.com", resourceGroup: "rg"})
+	assert.Equal(t, domains[1], serviceDomain{service: "api", name: "api.example.com", resourceGroup: "rg"})
+	assert.Equal(t, domains[0].recordName(), "shop")
+
+	group := containerinstance.ContainerGroup{Tags: map[string]*string{}}
+	tagDomains(&group, domains)
+	assert.DeepEqual(t, group.Tags, map[string]*string{domainTag + ".web": to.StringPtr("rg/example.com/shop")})
+}
+
+func TestProjectDomainsFailures(t *testing.T) {
+	_, err := projectDomains(&types.Project{
+		Services: []types.ServiceConfig{{
+			Name: "web",
+			Extensions: map[string]interface{}{
+				extensionDomain: map[string]interface{}{"name": "shop.example.org", "dns_zone": "example.com"},
+			},
+		}},
+	}, "rg")
+	assert.ErrorContains(t, err, "domain shop.example.org of service web must be a subdomain of DNS zone example.com")
+
+	_, err = projectDomains(&types.Project{
+		Services: []types.ServiceConfig{{
+			Name: "web",
+			Extensions: map[string]interface{}{
+				extensionDomain: map[string]interface{}{"name": "shop.example.com", "dns_zone": "example.com"},
+			},
+		}},
+	}, "rg")
+	assert.ErrorContains(t, err, "service web must set domainname to get a DNS record for shop.example.com")
+}
+
+func TestParseKeyVaultSecretID(t *testing.T) {
+	vault, name, version, err := parseKeyVaultSecretID("https://shop.vault.azure.net/certificates/web/0123")
+	assert.NilError(t, err)
+	assert.Equal(t, vault, "https://shop.vault.azure.net")
+	assert.Equal(t, name, "web")
+	assert.Equal(t, version, "0123")
+
+	_, name, version, err = parseKeyVaultSecretID("https://shop.vault.azure.net/secrets/web")
+	assert.NilError(t, err)
+	assert.Equal(t, name, "web")
+	assert.Equal(t, version, "")
+
+	_, _, _, err = parseKeyVaultSecretID("https://shop.vault.azure.net/keys/web")
+	assert.ErrorContains(t, err, "invalid Key Vault certificate")
+}
@@ -0,0 +1,106 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/aci/login"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const (
+	// appConfigScheme is the x-env-from scheme of App Configuration stores, as appconfig://<store>/<label>
+	appConfigScheme = "appconfig://"
+	// keyVaultReferenceContentType is the content type of App Configuration key-values referencing Key Vault secrets
+	keyVaultReferenceContentType = "application/vnd.microsoft.appconfig.keyvaultref+json"
+	// noLabel filters the App Configuration key-values without label
+	noLabel = "\x00"
+	// appConfigEndpoint is the App Configuration data plane endpoint of a store
+	appConfigEndpoint = "https://%s.azconfig.io"
+)
+
+type keyValues struct {
+	Items []struct {
+		Key         string `json:"key"`
+		Value       string `json:"value"`
+		ContentType string `json:"content_type"`
+	} `json:"items"`
+	NextLink string `json:"@nextLink"`
+}
+
+// pullAppConfig reads the environment of services from the key-values of an App Configuration store with a label,
+// or without label when the source doesn't set one
+func pullAppConfig(ctx context.Context, source string) (map[string]string, error) {
+	store, label := source, noLabel
+	if i := strings.Index(source, "/"); i >= 0 {
+		store, label = source[:i], source[i+1:]
+	}
+	if store == "" {
+		return nil, errors.Wrapf(errdefs.ErrParsingFailed, "App Configuration source %q has no store name", source)
+	}
+	authorizer, err := login.NewAppConfigAuthorizer()
+	if err != nil {
+		return nil, err
+	}
+	return readKeyValues(ctx, authorizer, fmt.Sprintf(appConfigEndpoint, store), label)
+}
+
+func readKeyValues(ctx context.Context, authorizer autorest.Authorizer, endpoint string, label string) (map[string]string, error) {
+	variables := map[string]string{}
+	next := "/kv?" + url.Values{"label": {label}, "api-version": {"1.0"}}.Encode()
+	for next != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+next, nil)
+		if err != nil {
+			return nil, err
+		}
+		req, err = autorest.Prepare(req, authorizer.WithAuthorization())
+		if err != nil {
+			return nil, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close() // nolint:errcheck
+			return nil, fmt.Errorf("cannot read App Configuration key-values: %s", resp.Status)
+		}
+		var page keyValues
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close() // nolint:errcheck
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot parse App Configuration key-values")
+		}
+		for _, item := range page.Items {
+			if strings.HasPrefix(item.ContentType, keyVaultReferenceContentType) {
+				return nil, errors.Errorf("key %s references a Key Vault secret, declare it as a secret", item.Key)
+			}
+			variables[item.Key] = item.Value
+		}
+		next = page.NextLink
+	}
+	return variables, nil
+}
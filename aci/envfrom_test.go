@@ -0,0 +1,54 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	"gotest.tools/v3/assert"
+)
+
+func TestReadKeyValues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.URL.Path, "/kv")
+		if r.URL.Query().Get("after") == "" {
+			assert.Equal(t, r.URL.Query().Get("label"), "prod")
+			_, _ = w.Write([]byte(`{"items":[{"key":"REGION","value":"westeurope"}],"@nextLink":"/kv?label=prod&after=1"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"items":[{"key":"DEBUG","value":"false","content_type":""}]}`))
+	}))
+	defer server.Close()
+
+	variables, err := readKeyValues(context.TODO(), autorest.NullAuthorizer{}, server.URL, "prod")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, variables, map[string]string{"REGION": "westeurope", "DEBUG": "false"})
+}
+
+func TestReadKeyValuesKeyVaultReference(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"items":[{"key":"PASSWORD","value":"{}","content_type":"application/vnd.microsoft.appconfig.keyvaultref+json;charset=utf-8"}]}`))
+	}))
+	defer server.Close()
+
+	_, err := readKeyValues(context.TODO(), autorest.NullAuthorizer{}, server.URL, noLabel)
+	assert.ErrorContains(t, err, "key PASSWORD references a Key Vault secret")
+}
@@ -0,0 +1,82 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/aci/convert"
+	"github.com/docker/compose-cli/aci/login"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// Import generates a compose project from an existing container group, and tags the group as a compose application
+// so that the next `compose up` updates it in place. ACI applications are named after their project, so the project
+// name has to match the container group name.
+func (cs *aciComposeService) Import(ctx context.Context, options compose.ImportOptions) (*types.Project, error) {
+	if len(options.Resources) != 1 {
+		return nil, errors.Wrap(errdefs.ErrParsingFailed, "a single container group can be imported as a compose application")
+	}
+	name := strings.ToLower(options.Resources[0])
+	if options.Project != "" && strings.ToLower(options.Project) != name {
+		return nil, errors.Wrapf(errdefs.ErrParsingFailed, "container group %q can only be imported as project %q", name, name)
+	}
+
+	group, err := getACIContainerGroup(ctx, cs.ctx, name)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, errors.Wrapf(errdefs.ErrNotFound, "container group %q", name)
+		}
+		return nil, err
+	}
+	if project, ok := group.Tags[compose.ProjectTag]; ok {
+		return nil, errors.Wrapf(errdefs.ErrAlreadyExists, "container group %q already belongs to compose application %q", name, to.String(project))
+	}
+
+	project := convert.ContainerGroupToProject(name, group)
+
+	tags := importedTags(group, name)
+	groupsClient, err := login.NewContainerGroupsClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := groupsClient.Update(ctx, cs.ctx.ResourceGroup, name, containerinstance.Resource{Tags: tags}); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// importedTags labels a container group as a compose application. Configuration hashes are left out, so that the
+// first `compose up` always applies the compose file.
+func importedTags(group containerinstance.ContainerGroup, project string) map[string]*string {
+	tags := map[string]*string{}
+	for k, v := range group.Tags {
+		if k != singleContainerTag {
+			tags[k] = v
+		}
+	}
+	tags[composeContainerTag] = to.StringPtr(composeContainerTag)
+	tags[compose.ProjectTag] = to.StringPtr(project)
+	return tags
+}
@@ -0,0 +1,178 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/aci/convert"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/api/containers"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// jobTag marks container groups running a job of a compose application
+const jobTag = "docker-compose-job"
+
+// runJobs runs each job in a dedicated container group before the project group gets deployed, as init containers
+// would do. The group of a successful job is deleted, a failed one is kept so that its logs can be checked.
+func (cs *aciComposeService) runJobs(ctx context.Context, project *types.Project, tags map[string]string) error {
+	return compose.RunJobs(ctx, project, func(ctx context.Context, job types.ServiceConfig) error {
+		return cs.runJob(ctx, project, job, tags)
+	})
+}
+
+// checkJobDependencies rejects jobs depending on services of the project container group: as jobs run before the group
+// is deployed, the services they depend on, like the database a migration job uses, wouldn't be running yet
+func checkJobDependencies(project *types.Project) error {
+	for _, job := range compose.Jobs(project) {
+		for dependency := range job.DependsOn {
+			service, err := project.GetService(dependency)
+			if err != nil || compose.IsJob(service) {
+				continue
+			}
+			return errors.Wrapf(errdefs.ErrNotImplemented, "job %s depends on service %s: ACI runs jobs before deploying the "+
+				"project services, jobs can only depend on other jobs", job.Name, dependency)
+		}
+	}
+	return nil
+}
+
+// RunJob runs a job of the project in a dedicated container group, like the jobs run by compose up
+func (cs *aciComposeService) RunJob(ctx context.Context, project *types.Project, name string) error {
+	job, err := project.GetService(name)
+	if err != nil {
+		return err
+	}
+	if !compose.IsJob(job) {
+		return errors.Wrapf(errdefs.ErrNotFound, "service %s is not a job", name)
+	}
+	return cs.runJob(ctx, project, job, nil)
+}
+
+func (cs *aciComposeService) runJob(ctx context.Context, project *types.Project, job types.ServiceConfig, tags map[string]string) error {
+	jobProject := standaloneProject(project, job)
+	diagnostics, err := cs.groupDiagnostics(ctx, project.Name, jobProject)
+	if err != nil {
+		return err
+	}
+	groupDefinition, err := convert.ToContainerGroup(ctx, cs.ctx, jobProject, cs.storageLogin)
+	if err != nil {
+		return err
+	}
+	groupDefinition.Diagnostics = diagnostics
+	addTags(&groupDefinition, *to.StringMapPtr(tags))
+	groupDefinition.Tags[compose.ProjectTag] = to.StringPtr(project.Name)
+	groupDefinition.Tags[jobTag] = to.StringPtr(job.Name)
+	if err := createOrUpdateACIContainers(ctx, cs.ctx, groupDefinition); err != nil {
+		return err
+	}
+	exitCode, err := cs.waitJob(ctx, jobProject.Name, job.Name)
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		return compose.JobFailedError{
+			Job:      job.Name,
+			ExitCode: exitCode,
+			Reason:   fmt.Sprintf("check its logs with docker logs %s%s%s", jobProject.Name, composeContainerSeparator, job.Name),
+		}
+	}
+	_, err = deleteACIContainerGroup(ctx, cs.ctx, jobProject.Name)
+	return err
+}
+
+// standaloneProject returns the project deploying a job or scheduled service alone in a container group, which
+// stops once the service completed
+func standaloneProject(project *types.Project, job types.ServiceConfig) types.Project {
+	job.Ports = nil
+	job.DependsOn = nil
+	deploy := types.DeployConfig{}
+	if job.Deploy != nil {
+		deploy = *job.Deploy
+	}
+	deploy.RestartPolicy = &types.RestartPolicy{Condition: containers.RestartPolicyNone}
+	job.Deploy = &deploy
+	return types.Project{
+		Name:       strings.ToLower(fmt.Sprintf("%s-%s", project.Name, job.Name)),
+		WorkingDir: project.WorkingDir,
+		Services:   []types.ServiceConfig{job},
+		Networks:   project.Networks,
+		Volumes:    project.Volumes,
+		Secrets:    project.Secrets,
+		Configs:    project.Configs,
+		Extensions: project.Extensions,
+	}
+}
+
+// waitJob waits for the job container to terminate and returns its exit code
+func (cs *aciComposeService) waitJob(ctx context.Context, groupName string, job string) (int, error) {
+	for {
+		// the jobs groups are listed at once, a group is only read once provisioned as its job may have terminated
+		state, listed, err := cs.groups.provisioningState(ctx, groupName)
+		if err != nil {
+			return 0, err
+		}
+		if listed && groupProvisioning(state) {
+			select {
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			case <-time.After(cs.ctx.Operations().PollingInterval):
+			}
+			continue
+		}
+		group, err := getACIContainerGroup(ctx, cs.ctx, groupName)
+		if err != nil {
+			return 0, err
+		}
+		if group.ContainerGroupProperties != nil && group.Containers != nil {
+			for _, container := range *group.Containers {
+				if to.String(container.Name) != job || container.InstanceView == nil || container.InstanceView.CurrentState == nil {
+					continue
+				}
+				state := container.InstanceView.CurrentState
+				if to.String(state.State) == "Terminated" {
+					return int(to.Int32(state.ExitCode)), nil
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(cs.ctx.Operations().PollingInterval):
+		}
+	}
+}
+
+// groupProject returns the project without jobs and scheduled services, which don't belong to the project container group
+func groupProject(project *types.Project) *types.Project {
+	p := *project
+	p.Services = nil
+	for _, service := range project.Services {
+		if !compose.IsJob(service) && !compose.IsScheduled(service) {
+			p.Services = append(p.Services, service)
+		}
+	}
+	return &p
+}
@@ -0,0 +1,73 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/containers"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestJobGroupProject(t *testing.T) {
+	project := &types.Project{
+		Name: "Shop",
+		Services: []types.ServiceConfig{
+			{Name: "db", Image: "mysql"},
+			{
+				Name:      "migrate",
+				Image:     "shop",
+				DependsOn: types.DependsOnConfig{"db": {}},
+				Ports:     []types.ServicePortConfig{{Target: 80}},
+				Deploy:    &types.DeployConfig{Mode: "job"},
+			},
+		},
+	}
+	job := standaloneProject(project, project.Services[1])
+	assert.Equal(t, job.Name, "shop-migrate")
+	assert.Equal(t, len(job.Services), 1)
+	assert.Equal(t, len(job.Services[0].Ports), 0)
+	assert.Equal(t, job.Services[0].Deploy.RestartPolicy.Condition, containers.RestartPolicyNone)
+	assert.Assert(t, project.Services[1].Deploy.RestartPolicy == nil)
+
+	assert.DeepEqual(t, groupProject(project).ServiceNames(), []string{"db"})
+	assert.Equal(t, len(project.Services), 2)
+}
+
+func TestCheckJobDependencies(t *testing.T) {
+	project := &types.Project{
+		Services: []types.ServiceConfig{
+			{Name: "db", Image: "mysql"},
+			{Name: "schema", Image: "shop", Deploy: &types.DeployConfig{Mode: "job"}},
+			{
+				Name:      "migrate",
+				Image:     "shop",
+				DependsOn: types.DependsOnConfig{"schema": {}},
+				Deploy:    &types.DeployConfig{Mode: "job"},
+			},
+		},
+	}
+	assert.NilError(t, checkJobDependencies(project))
+
+	project.Services[2].DependsOn["db"] = types.ServiceDependency{}
+	err := checkJobDependencies(project)
+	assert.Assert(t, errdefs.IsErrNotImplemented(err))
+	assert.ErrorContains(t, err, "job migrate depends on service db")
+}
@@ -0,0 +1,186 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/aci/login"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// dockerLockTag marks the storage account holding project locks, one per resource group
+const dockerLockTag = "docker-compose-locks"
+
+// Lock acquires an infinite lease on a blob container named after the project
+func (cs *aciComposeService) Lock(ctx context.Context, projectName string, timeout time.Duration) (func() error, error) {
+	account, err := cs.lockAccount(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	client, err := login.NewBlobContainersClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
+	if err != nil {
+		return nil, err
+	}
+	container := lockContainerName(projectName)
+	_, err = client.Create(ctx, cs.ctx.ResourceGroup, account, container, storage.BlobContainer{
+		ContainerProperties: &storage.ContainerProperties{
+			Metadata: map[string]*string{"project": to.StringPtr(projectName)},
+		},
+	})
+	if err != nil && !isStatus(err, http.StatusConflict) {
+		return nil, err
+	}
+
+	info := compose.NewLockInfo()
+	err = compose.AcquireLock(ctx, projectName, timeout, func(ctx context.Context) (*compose.LockInfo, error) {
+		_, err := client.Lease(ctx, cs.ctx.ResourceGroup, account, container, &storage.LeaseContainerRequest{
+			Action:          storage.Acquire,
+			LeaseDuration:   to.Int32Ptr(-1),
+			ProposedLeaseID: to.StringPtr(info.ID),
+		})
+		if isStatus(err, http.StatusConflict) {
+			return lockHolder(ctx, client, cs.ctx.ResourceGroup, account, container)
+		}
+		if err != nil {
+			return nil, err
+		}
+		_, err = client.Update(ctx, cs.ctx.ResourceGroup, account, container, storage.BlobContainer{
+			ContainerProperties: &storage.ContainerProperties{
+				Metadata: map[string]*string{
+					"project":  to.StringPtr(projectName),
+					"owner":    to.StringPtr(info.Owner),
+					"acquired": to.StringPtr(info.Acquired.Format(time.RFC3339)),
+				},
+			},
+		})
+		return nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return func() error {
+		// release even if the command was canceled, so the next one doesn't have to wait
+		_, err := client.Lease(context.Background(), cs.ctx.ResourceGroup, account, container, &storage.LeaseContainerRequest{
+			Action:  storage.Release,
+			LeaseID: to.StringPtr(info.ID),
+		})
+		if isStatus(err, http.StatusConflict) {
+			// lease was broken by `compose unlock`
+			return nil
+		}
+		return err
+	}, nil
+}
+
+// Unlock breaks the lease on the project blob container
+func (cs *aciComposeService) Unlock(ctx context.Context, projectName string) error {
+	account, err := cs.lockAccount(ctx, false)
+	if err != nil {
+		return err
+	}
+	client, err := login.NewBlobContainersClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
+	if err != nil {
+		return err
+	}
+	_, err = client.Lease(ctx, cs.ctx.ResourceGroup, account, lockContainerName(projectName), &storage.LeaseContainerRequest{
+		Action:      storage.Break,
+		BreakPeriod: to.Int32Ptr(0),
+	})
+	if isStatus(err, http.StatusNotFound) || isStatus(err, http.StatusConflict) {
+		return errors.Wrapf(errdefs.ErrNotFound, "project %q is not locked", projectName)
+	}
+	return err
+}
+
+// lockAccount returns the name of the storage account holding locks for the resource group, creating it if requested
+func (cs *aciComposeService) lockAccount(ctx context.Context, create bool) (string, error) {
+	name := lockAccountName(cs.ctx)
+	client, err := login.NewStorageAccountsClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
+	if err != nil {
+		return "", err
+	}
+	account, err := client.GetProperties(ctx, cs.ctx.ResourceGroup, name, "")
+	if err == nil {
+		return name, nil
+	}
+	if !account.HasHTTPStatus(http.StatusNotFound) {
+		return "", err
+	}
+	if !create {
+		return "", errors.Wrapf(errdefs.ErrNotFound, "no project lock in resource group %q", cs.ctx.ResourceGroup)
+	}
+	parameters := defaultStorageAccountParams(cs.ctx)
+	parameters.Tags = map[string]*string{dockerLockTag: to.StringPtr(dockerLockTag)}
+	future, err := client.Create(ctx, cs.ctx.ResourceGroup, name, parameters)
+	if err != nil {
+		return "", err
+	}
+	if err := future.WaitForCompletionRef(ctx, client.Client); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+func lockHolder(ctx context.Context, client storage.BlobContainersClient, resourceGroup, account, container string) (*compose.LockInfo, error) {
+	c, err := client.Get(ctx, resourceGroup, account, container)
+	if err != nil {
+		return nil, err
+	}
+	holder := compose.LockInfo{Owner: "unknown"}
+	if c.ContainerProperties == nil {
+		return &holder, nil
+	}
+	if owner, ok := c.Metadata["owner"]; ok {
+		holder.Owner = to.String(owner)
+	}
+	if acquired, ok := c.Metadata["acquired"]; ok {
+		holder.Acquired, _ = time.Parse(time.RFC3339, to.String(acquired))
+	}
+	return &holder, nil
+}
+
+// lockAccountName derives a storage account name, which must be globally unique, from the resource group
+func lockAccountName(aciContext store.AciContext) string {
+	sum := sha256.Sum256([]byte(aciContext.SubscriptionID + "/" + aciContext.ResourceGroup))
+	return fmt.Sprintf("composelock%x", sum[:6])
+}
+
+// lockContainerName derives a valid blob container name from the project name, which may contain underscores
+func lockContainerName(project string) string {
+	sum := sha256.Sum256([]byte(project))
+	return fmt.Sprintf("project-%x", sum[:8])
+}
+
+func isStatus(err error, status int) bool {
+	var detailed autorest.DetailedError
+	if errors.As(err, &detailed) {
+		return detailed.StatusCode == status
+	}
+	return false
+}
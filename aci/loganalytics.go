@@ -0,0 +1,255 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/Azure/azure-sdk-for-go/services/operationalinsights/v1/operationalinsights"
+	workspaces "github.com/Azure/azure-sdk-for-go/services/preview/operationalinsights/mgmt/2020-03-01-preview/operationalinsights"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/aci/convert"
+	"github.com/docker/compose-cli/aci/login"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/progress"
+)
+
+const (
+	// logAnalyticsTable is the table of the logs sent by container groups with the ContainerInstanceLogs log type
+	logAnalyticsTable = "ContainerInstanceLog_CL"
+	// workspaceRetentionDays is the retention of the workspaces created for projects
+	workspaceRetentionDays = 30
+)
+
+// workspaceName is the name of the workspace created for the logs of a project
+func workspaceName(project string) string {
+	return project + "-logs"
+}
+
+// groupDiagnostics returns the diagnostics of a container group of the project, sending its logs to the Log Analytics
+// workspace of the logging options, the one of the project being created first when requested
+func (cs *aciComposeService) groupDiagnostics(ctx context.Context, project string, group types.Project) (*containerinstance.ContainerGroupDiagnostics, error) {
+	options, err := convert.LogAnalyticsOf(ctx, group)
+	if err != nil || options == nil {
+		return nil, err
+	}
+	if options.WorkspaceID != "" {
+		return convert.ToDiagnostics(options.WorkspaceID, options.WorkspaceKey, options.LogType), nil
+	}
+	subscriptionID, resourceGroup, name := cs.ctx.SubscriptionID, cs.ctx.ResourceGroup, workspaceName(project)
+	if options.WorkspaceResourceID != "" {
+		resource, err := azure.ParseResourceID(options.WorkspaceResourceID)
+		if err != nil {
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "invalid Log Analytics workspace resource ID %q", options.WorkspaceResourceID)
+		}
+		subscriptionID, resourceGroup, name = resource.SubscriptionID, resource.ResourceGroup, resource.ResourceName
+	}
+	workspacesClient, err := login.NewWorkspacesClient(subscriptionID, cs.ctx.Operations())
+	if err != nil {
+		return nil, err
+	}
+	workspace, err := workspacesClient.Get(ctx, resourceGroup, name)
+	if isNotFound(err) && options.CreateWorkspace {
+		workspace, err = cs.createWorkspace(ctx, workspacesClient, project)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot get Log Analytics workspace %s", name)
+	}
+	keysClient, err := login.NewSharedKeysClient(subscriptionID, cs.ctx.Operations())
+	if err != nil {
+		return nil, err
+	}
+	keys, err := keysClient.GetSharedKeys(ctx, resourceGroup, name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot get keys of Log Analytics workspace %s", name)
+	}
+	if workspace.WorkspaceProperties == nil || workspace.CustomerID == nil || keys.PrimarySharedKey == nil {
+		return nil, errors.Errorf("Log Analytics workspace %s has no credentials", name)
+	}
+	return convert.ToDiagnostics(*workspace.CustomerID, *keys.PrimarySharedKey, options.LogType), nil
+}
+
+// createWorkspace creates the Log Analytics workspace of a project, in the context resource group. The workspace is
+// kept by compose down, so that the logs of the project remain available.
+func (cs *aciComposeService) createWorkspace(ctx context.Context, client workspaces.WorkspacesClient, project string) (workspaces.Workspace, error) {
+	w := progress.ContextWriter(ctx)
+	name := workspaceName(project)
+	w.Event(progress.Event{
+		ID:         name,
+		Status:     progress.Working,
+		StatusText: "Creating Log Analytics workspace",
+	})
+	future, err := client.CreateOrUpdate(ctx, cs.ctx.ResourceGroup, name, workspaces.Workspace{
+		Location: to.StringPtr(cs.ctx.Location),
+		Tags:     map[string]*string{compose.ProjectTag: to.StringPtr(project)},
+		WorkspaceProperties: &workspaces.WorkspaceProperties{
+			Sku:             &workspaces.WorkspaceSku{Name: workspaces.WorkspaceSkuNameEnumPerGB2018},
+			RetentionInDays: to.Int32Ptr(workspaceRetentionDays),
+		},
+	})
+	if err != nil {
+		return workspaces.Workspace{}, err
+	}
+	if err := future.WaitForCompletionRef(ctx, client.Client); err != nil {
+		return workspaces.Workspace{}, err
+	}
+	w.Event(progress.Event{
+		ID:         name,
+		Status:     progress.Done,
+		StatusText: "Created Log Analytics workspace",
+	})
+	return future.Result(client)
+}
+
+// groupWorkspace returns the Log Analytics workspace the logs of a container group are sent to, if any
+func groupWorkspace(group containerinstance.ContainerGroup) (string, containerinstance.LogAnalyticsLogType) {
+	if group.ContainerGroupProperties == nil || group.Diagnostics == nil || group.Diagnostics.LogAnalytics == nil {
+		return "", ""
+	}
+	logType := group.Diagnostics.LogAnalytics.LogType
+	if logType == "" {
+		logType = containerinstance.ContainerInstanceLogs
+	}
+	return to.String(group.Diagnostics.LogAnalytics.WorkspaceID), logType
+}
+
+// keepWorkspaceKey sets the workspace key of a deployed container group, which Azure doesn't return, from the
+// definition of the group sending logs to the same workspace, so that the deployed group can be deployed again
+func keepWorkspaceKey(deployed *containerinstance.ContainerGroup, definition containerinstance.ContainerGroup) {
+	workspace, _ := groupWorkspace(*deployed)
+	if current, _ := groupWorkspace(definition); workspace == "" || workspace != current {
+		return
+	}
+	deployed.Diagnostics.LogAnalytics.WorkspaceKey = definition.Diagnostics.LogAnalytics.WorkspaceKey
+}
+
+// queryLogs reads the logs of a container group from its Log Analytics workspace, including the ones of the containers
+// which aren't running anymore
+func (cs *aciComposeService) queryLogs(ctx context.Context, group containerinstance.ContainerGroup, consumer compose.LogConsumer, options compose.LogOptions) error {
+	workspace, logType := groupWorkspace(group)
+	if logType != containerinstance.ContainerInstanceLogs {
+		return errors.Wrapf(errdefs.ErrNotImplemented, "querying %s logs", logType)
+	}
+	client, err := login.NewLogAnalyticsQueryClient(cs.ctx.Operations())
+	if err != nil {
+		return err
+	}
+	result, err := client.Execute(ctx, workspace, operationalinsights.QueryBody{
+		Query: to.StringPtr(logsQuery(group, options)),
+	})
+	if err != nil {
+		return errors.Wrap(err, "cannot query Log Analytics logs")
+	}
+	if result.Tables == nil {
+		return nil
+	}
+	for _, table := range *result.Tables {
+		events, err := tableLogEvents(group, table)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			consumer.Log(event)
+		}
+	}
+	return nil
+}
+
+// logsQuery returns the Kusto query of the logs of a container group. Logs of previous containers are the ones
+// written before the current containers started.
+func logsQuery(group containerinstance.ContainerGroup, options compose.LogOptions) string {
+	query := []string{
+		logAnalyticsTable,
+		fmt.Sprintf("where ContainerGroup_s == %s", kustoString(to.String(group.Name))),
+		fmt.Sprintf("where ContainerName_s != %s", kustoString(convert.ComposeDNSSidecarName)),
+	}
+	if !options.Since.IsZero() {
+		query = append(query, fmt.Sprintf("where TimeGenerated >= %s", kustoTime(options.Since)))
+	}
+	if !options.Until.IsZero() {
+		query = append(query, fmt.Sprintf("where TimeGenerated < %s", kustoTime(options.Until)))
+	}
+	if options.Previous && group.Containers != nil {
+		for _, container := range *group.Containers {
+			if container.InstanceView == nil || container.InstanceView.CurrentState == nil || container.InstanceView.CurrentState.StartTime == nil {
+				continue
+			}
+			started := container.InstanceView.CurrentState.StartTime.Time
+			query = append(query, fmt.Sprintf("where not(ContainerName_s == %s and TimeGenerated >= %s)", kustoString(to.String(container.Name)), kustoTime(started)))
+		}
+	}
+	query = append(query,
+		"project TimeGenerated, ContainerName_s, Message",
+		"order by TimeGenerated asc")
+	return strings.Join(query, "\n| ")
+}
+
+func kustoString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func kustoTime(t time.Time) string {
+	return fmt.Sprintf("datetime(%s)", t.UTC().Format(time.RFC3339Nano))
+}
+
+// tableLogEvents converts the rows of a logs query to log events
+func tableLogEvents(group containerinstance.ContainerGroup, table operationalinsights.Table) ([]compose.LogEvent, error) {
+	if table.Columns == nil || table.Rows == nil {
+		return nil, nil
+	}
+	columns := map[string]int{}
+	for i, column := range *table.Columns {
+		columns[to.String(column.Name)] = i
+	}
+	for _, name := range []string{"TimeGenerated", "ContainerName_s", "Message"} {
+		if _, ok := columns[name]; !ok {
+			return nil, errors.Errorf("Log Analytics query result has no %s column", name)
+		}
+	}
+	var events []compose.LogEvent
+	for _, row := range *table.Rows {
+		value := func(column string) string {
+			i := columns[column]
+			if i >= len(row) {
+				return ""
+			}
+			s, _ := row[i].(string)
+			return s
+		}
+		timestamp, err := time.Parse(time.RFC3339Nano, value("TimeGenerated"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid log timestamp")
+		}
+		container := containerinstance.Container{Name: to.StringPtr(value("ContainerName_s"))}
+		events = append(events, compose.LogEvent{
+			Service:   *container.Name,
+			Container: getContainerID(group, container),
+			Timestamp: timestamp,
+			Line:      strings.TrimRight(value("Message"), "\n"),
+		})
+	}
+	return events, nil
+}
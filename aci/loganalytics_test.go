@@ -0,0 +1,118 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/Azure/azure-sdk-for-go/services/operationalinsights/v1/operationalinsights"
+	"github.com/Azure/go-autorest/autorest/date"
+	"github.com/Azure/go-autorest/autorest/to"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/aci/convert"
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestLogsQuery(t *testing.T) {
+	since := time.Date(2020, 10, 20, 13, 0, 0, 0, time.UTC)
+	started := time.Date(2020, 10, 20, 14, 0, 0, 0, time.UTC)
+	group := containerinstance.ContainerGroup{
+		Name: to.StringPtr("demo"),
+		ContainerGroupProperties: &containerinstance.ContainerGroupProperties{
+			Containers: &[]containerinstance.Container{
+				{
+					Name: to.StringPtr("web"),
+					ContainerProperties: &containerinstance.ContainerProperties{
+						InstanceView: &containerinstance.ContainerPropertiesInstanceView{
+							CurrentState: &containerinstance.ContainerState{StartTime: &date.Time{Time: started}},
+						},
+					},
+				},
+				{Name: to.StringPtr("db"), ContainerProperties: &containerinstance.ContainerProperties{}},
+			},
+		},
+	}
+
+	assert.Equal(t, logsQuery(group, compose.LogOptions{Since: since, Until: started}), `ContainerInstanceLog_CL
+| where ContainerGroup_s == "demo"
+| where ContainerName_s != "aci--dns--sidecar"
+| where TimeGenerated >= datetime(2020-10-20T13:00:00Z)
+| where TimeGenerated < datetime(2020-10-20T14:00:00Z)
+| project TimeGenerated, ContainerName_s, Message
+| order by TimeGenerated asc`)
+
+	assert.Equal(t, logsQuery(group, compose.LogOptions{Previous: true}), `ContainerInstanceLog_CL
+| where ContainerGroup_s == "demo"
+| where ContainerName_s != "aci--dns--sidecar"
+| where not(ContainerName_s == "web" and TimeGenerated >= datetime(2020-10-20T14:00:00Z))
+| project TimeGenerated, ContainerName_s, Message
+| order by TimeGenerated asc`)
+}
+
+func TestKustoString(t *testing.T) {
+	assert.Equal(t, kustoString(`a"b\c`), `"a\"b\\c"`)
+}
+
+func TestTableLogEvents(t *testing.T) {
+	group := containerinstance.ContainerGroup{Name: to.StringPtr("demo")}
+	events, err := tableLogEvents(group, operationalinsights.Table{
+		Columns: &[]operationalinsights.Column{
+			{Name: to.StringPtr("TimeGenerated")},
+			{Name: to.StringPtr("ContainerName_s")},
+			{Name: to.StringPtr("Message")},
+		},
+		Rows: &[][]interface{}{
+			{"2020-10-20T13:00:00.5Z", "web", "listening\n"},
+			{"2020-10-20T13:00:01Z", "db", "ready"},
+		},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, events, []compose.LogEvent{
+		{Service: "web", Container: "demo_web", Timestamp: time.Date(2020, 10, 20, 13, 0, 0, 500000000, time.UTC), Line: "listening"},
+		{Service: "db", Container: "demo_db", Timestamp: time.Date(2020, 10, 20, 13, 0, 1, 0, time.UTC), Line: "ready"},
+	})
+
+	_, err = tableLogEvents(group, operationalinsights.Table{
+		Columns: &[]operationalinsights.Column{{Name: to.StringPtr("TimeGenerated")}},
+		Rows:    &[][]interface{}{},
+	})
+	assert.Error(t, err, "Log Analytics query result has no ContainerName_s column")
+}
+
+func TestKeepWorkspaceKey(t *testing.T) {
+	deployed := containerinstance.ContainerGroup{
+		ContainerGroupProperties: &containerinstance.ContainerGroupProperties{
+			Diagnostics: &containerinstance.ContainerGroupDiagnostics{
+				LogAnalytics: &containerinstance.LogAnalytics{WorkspaceID: to.StringPtr("id")},
+			},
+		},
+	}
+	definition := containerinstance.ContainerGroup{
+		ContainerGroupProperties: &containerinstance.ContainerGroupProperties{
+			Diagnostics: convert.ToDiagnostics("other", "other-key", containerinstance.ContainerInstanceLogs),
+		},
+	}
+	keepWorkspaceKey(&deployed, definition)
+	assert.Assert(t, deployed.Diagnostics.LogAnalytics.WorkspaceKey == nil)
+
+	definition.Diagnostics = convert.ToDiagnostics("id", "key", containerinstance.ContainerInstanceLogs)
+	keepWorkspaceKey(&deployed, definition)
+	assert.Equal(t, to.String(deployed.Diagnostics.LogAnalytics.WorkspaceKey), "key")
+}
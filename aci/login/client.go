@@ -17,35 +17,276 @@
 package login
 
 import (
+	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/profiles/2019-03-01/resources/mgmt/resources"
 	"github.com/Azure/azure-sdk-for-go/profiles/preview/preview/subscription/mgmt/subscription"
+	"github.com/Azure/azure-sdk-for-go/services/authorization/mgmt/2015-07-01/authorization"
 	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/Azure/azure-sdk-for-go/services/containerregistry/mgmt/2019-05-01/containerregistry"
+	"github.com/Azure/azure-sdk-for-go/services/dns/mgmt/2018-05-01/dns"
+	"github.com/Azure/azure-sdk-for-go/services/keyvault/2016-10-01/keyvault"
+	"github.com/Azure/azure-sdk-for-go/services/logic/mgmt/2019-05-01/logic"
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-05-01/network"
+	"github.com/Azure/azure-sdk-for-go/services/operationalinsights/v1/operationalinsights"
+	workspaces "github.com/Azure/azure-sdk-for-go/services/preview/operationalinsights/mgmt/2020-03-01-preview/operationalinsights"
+	"github.com/Azure/azure-sdk-for-go/services/privatedns/mgmt/2018-09-01/privatedns"
 	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/label"
 
+	"github.com/docker/compose-cli/config"
+	"github.com/docker/compose-cli/context/store"
 	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/recorder"
+	"github.com/docker/compose-cli/tracing"
 )
 
 const userAgent = "docker-cli"
 
+// Transport sends the Azure API requests of the clients, recording or replaying them as set by the environment.
+// Tests replace it to serve requests without reaching Azure.
+var Transport = recorder.Transport(nil)
+
 // NewContainerGroupsClient get client toi manipulate containerGrouos
-func NewContainerGroupsClient(subscriptionID string) (containerinstance.ContainerGroupsClient, error) {
+func NewContainerGroupsClient(subscriptionID string, ops store.Operations) (containerinstance.ContainerGroupsClient, error) {
 	containerGroupsClient := containerinstance.NewContainerGroupsClient(subscriptionID)
 	err := setupClient(&containerGroupsClient.Client)
 	if err != nil {
 		return containerinstance.ContainerGroupsClient{}, err
 	}
-	containerGroupsClient.PollingDelay = 5 * time.Second
-	containerGroupsClient.RetryAttempts = 30
-	containerGroupsClient.RetryDuration = 1 * time.Second
+	withOperations(&containerGroupsClient.Client, ops)
 	return containerGroupsClient, nil
 }
 
+// NewContainerGroupUsageClient get client to query container instance quotas
+func NewContainerGroupUsageClient(subscriptionID string, ops store.Operations) (containerinstance.ContainerGroupUsageClient, error) {
+	usageClient := containerinstance.NewContainerGroupUsageClient(subscriptionID)
+	err := setupClient(&usageClient.Client)
+	if err != nil {
+		return containerinstance.ContainerGroupUsageClient{}, err
+	}
+	withOperations(&usageClient.Client, ops)
+	return usageClient, nil
+}
+
+// NewWorkflowsClient get client to manipulate Logic App workflows
+func NewWorkflowsClient(subscriptionID string, ops store.Operations) (logic.WorkflowsClient, error) {
+	workflowsClient := logic.NewWorkflowsClient(subscriptionID)
+	err := setupClient(&workflowsClient.Client)
+	if err != nil {
+		return logic.WorkflowsClient{}, err
+	}
+	withOperations(&workflowsClient.Client, ops)
+	return workflowsClient, nil
+}
+
+// NewRoleAssignmentsClient get client to manipulate role assignments
+func NewRoleAssignmentsClient(subscriptionID string, ops store.Operations) (authorization.RoleAssignmentsClient, error) {
+	roleAssignmentsClient := authorization.NewRoleAssignmentsClient(subscriptionID)
+	err := setupClient(&roleAssignmentsClient.Client)
+	if err != nil {
+		return authorization.RoleAssignmentsClient{}, err
+	}
+	withOperations(&roleAssignmentsClient.Client, ops)
+	return roleAssignmentsClient, nil
+}
+
+// NewRoleDefinitionsClient get client to manipulate role definitions
+func NewRoleDefinitionsClient(subscriptionID string, ops store.Operations) (authorization.RoleDefinitionsClient, error) {
+	roleDefinitionsClient := authorization.NewRoleDefinitionsClient(subscriptionID)
+	err := setupClient(&roleDefinitionsClient.Client)
+	if err != nil {
+		return authorization.RoleDefinitionsClient{}, err
+	}
+	withOperations(&roleDefinitionsClient.Client, ops)
+	return roleDefinitionsClient, nil
+}
+
+// NewSubnetsClient get client to manipulate virtual network subnets
+func NewSubnetsClient(subscriptionID string, ops store.Operations) (network.SubnetsClient, error) {
+	subnetsClient := network.NewSubnetsClient(subscriptionID)
+	err := setupClient(&subnetsClient.Client)
+	if err != nil {
+		return network.SubnetsClient{}, err
+	}
+	withOperations(&subnetsClient.Client, ops)
+	return subnetsClient, nil
+}
+
+// NewApplicationGatewaysClient get client to manipulate Application Gateways
+func NewApplicationGatewaysClient(subscriptionID string, ops store.Operations) (network.ApplicationGatewaysClient, error) {
+	gatewaysClient := network.NewApplicationGatewaysClient(subscriptionID)
+	err := setupClient(&gatewaysClient.Client)
+	if err != nil {
+		return network.ApplicationGatewaysClient{}, err
+	}
+	withOperations(&gatewaysClient.Client, ops)
+	return gatewaysClient, nil
+}
+
+// NewPrivateRecordSetsClient get client to manipulate private DNS records
+func NewPrivateRecordSetsClient(subscriptionID string, ops store.Operations) (privatedns.RecordSetsClient, error) {
+	recordSetsClient := privatedns.NewRecordSetsClient(subscriptionID)
+	err := setupClient(&recordSetsClient.Client)
+	if err != nil {
+		return privatedns.RecordSetsClient{}, err
+	}
+	withOperations(&recordSetsClient.Client, ops)
+	return recordSetsClient, nil
+}
+
+// NewRecordSetsClient get client to manipulate Azure DNS records
+func NewRecordSetsClient(subscriptionID string, ops store.Operations) (dns.RecordSetsClient, error) {
+	recordSetsClient := dns.NewRecordSetsClient(subscriptionID)
+	err := setupClient(&recordSetsClient.Client)
+	if err != nil {
+		return dns.RecordSetsClient{}, err
+	}
+	withOperations(&recordSetsClient.Client, ops)
+	return recordSetsClient, nil
+}
+
+// NewKeyVaultClient get client to read Key Vault secrets
+func NewKeyVaultClient(ops store.Operations) (keyvault.BaseClient, error) {
+	keyVaultClient := keyvault.New()
+	authorizer, err := NewKeyVaultAuthorizer()
+	if err != nil {
+		return keyvault.BaseClient{}, err
+	}
+	keyVaultClient.Authorizer = authorizer
+	keyVaultClient.UserAgent = userAgent
+	withOperations(&keyVaultClient.Client, ops)
+	return keyVaultClient, nil
+}
+
+// NewWorkspacesClient get client to manipulate Log Analytics workspaces
+func NewWorkspacesClient(subscriptionID string, ops store.Operations) (workspaces.WorkspacesClient, error) {
+	workspacesClient := workspaces.NewWorkspacesClient(subscriptionID)
+	err := setupClient(&workspacesClient.Client)
+	if err != nil {
+		return workspaces.WorkspacesClient{}, err
+	}
+	withOperations(&workspacesClient.Client, ops)
+	return workspacesClient, nil
+}
+
+// NewSharedKeysClient get client to read the keys of Log Analytics workspaces
+func NewSharedKeysClient(subscriptionID string, ops store.Operations) (workspaces.SharedKeysClient, error) {
+	sharedKeysClient := workspaces.NewSharedKeysClient(subscriptionID)
+	err := setupClient(&sharedKeysClient.Client)
+	if err != nil {
+		return workspaces.SharedKeysClient{}, err
+	}
+	withOperations(&sharedKeysClient.Client, ops)
+	return sharedKeysClient, nil
+}
+
+// NewLogAnalyticsQueryClient get client to query the logs of Log Analytics workspaces
+func NewLogAnalyticsQueryClient(ops store.Operations) (operationalinsights.QueryClient, error) {
+	queryClient := operationalinsights.NewQueryClient()
+	authorizer, err := NewLogAnalyticsAuthorizer()
+	if err != nil {
+		return operationalinsights.QueryClient{}, err
+	}
+	queryClient.Authorizer = authorizer
+	queryClient.UserAgent = userAgent
+	withOperations(&queryClient.Client, ops)
+	return queryClient, nil
+}
+
+// withOperations applies context operation settings to the client: long running operations polling,
+// per call timeout, retries with exponential backoff and jitter, and the rate limit of the subscription
+func withOperations(aciClient *autorest.Client, ops store.Operations) {
+	aciClient.PollingDelay = ops.PollingInterval
+	aciClient.RetryAttempts = ops.MaxRetries
+	aciClient.RetryDuration = ops.RetryBackoff
+	aciClient.Sender = &http.Client{Timeout: ops.Timeout, Transport: Transport}
+	aciClient.SendDecorators = []autorest.SendDecorator{
+		doRateLimit(),
+		azure.DoRetryWithRegistration(*aciClient),
+		doRetryWithJitter(ops),
+		doTrace(),
+	}
+}
+
+// doTrace records a span for each Azure API call, including retries, as a child of the operation span
+func doTrace() autorest.SendDecorator {
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+			ctx, span := tracing.Start(r.Context(), fmt.Sprintf("azure.%s %s", r.Method, r.URL.Path),
+				label.String("http.method", r.Method),
+				label.String("http.host", r.URL.Host))
+			resp, err := s.Do(r.WithContext(ctx))
+			if err == nil && resp.StatusCode >= http.StatusBadRequest {
+				tracing.End(span, errors.Errorf("%s", resp.Status))
+			} else {
+				tracing.End(span, err)
+			}
+			return resp, err
+		})
+	}
+}
+
+func doRetryWithJitter(ops store.Operations) autorest.SendDecorator {
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+			rr := autorest.NewRetriableRequest(r)
+			var (
+				resp *http.Response
+				err  error
+			)
+			for attempt := 0; ; attempt++ {
+				if err = rr.Prepare(); err != nil {
+					return resp, err
+				}
+				autorest.DrainResponseBody(resp)
+				resp, err = s.Do(rr.Request())
+				if err == nil && !autorest.ResponseHasStatusCode(resp, autorest.StatusCodesForRetry...) || autorest.IsTokenRefreshError(err) {
+					return resp, err
+				}
+				if attempt >= ops.MaxRetries {
+					return resp, err
+				}
+				if !autorest.DelayWithRetryAfter(resp, r.Context().Done()) {
+					select {
+					case <-time.After(ops.Backoff(attempt)):
+					case <-r.Context().Done():
+						return resp, r.Context().Err()
+					}
+				}
+			}
+		})
+	}
+}
+
+// checkOnline fails creating Azure clients and authorizers in offline mode, so that commands requiring Azure fail fast
+// rather than waiting for calls to time out
+func checkOnline() error {
+	if config.IsOffline() {
+		return errors.Wrap(errdefs.ErrOffline, "cannot reach Azure")
+	}
+	return nil
+}
+
+// NewRetailPricesClient get client to query the Azure retail prices API, which doesn't require authentication
+func NewRetailPricesClient(ops store.Operations) (*http.Client, error) {
+	if err := checkOnline(); err != nil {
+		return nil, err
+	}
+	return &http.Client{Timeout: ops.Timeout, Transport: Transport}, nil
+}
+
 func setupClient(aciClient *autorest.Client) error {
+	if err := checkOnline(); err != nil {
+		return err
+	}
 	aciClient.UserAgent = userAgent
+	aciClient.Sender = &http.Client{Transport: Transport}
 	auth, err := NewAuthorizerFromLogin()
 	if err != nil {
 		return err
@@ -55,31 +296,38 @@ func setupClient(aciClient *autorest.Client) error {
 }
 
 // NewStorageAccountsClient get client to manipulate storage accounts
-func NewStorageAccountsClient(subscriptionID string) (storage.AccountsClient, error) {
+func NewStorageAccountsClient(subscriptionID string, ops store.Operations) (storage.AccountsClient, error) {
 	containerGroupsClient := storage.NewAccountsClient(subscriptionID)
 	err := setupClient(&containerGroupsClient.Client)
 	if err != nil {
 		return storage.AccountsClient{}, err
 	}
-	containerGroupsClient.PollingDelay = 5 * time.Second
-	containerGroupsClient.RetryAttempts = 30
-	containerGroupsClient.RetryDuration = 1 * time.Second
+	withOperations(&containerGroupsClient.Client, ops)
 	return containerGroupsClient, nil
 }
 
 // NewFileShareClient get client to manipulate file shares
-func NewFileShareClient(subscriptionID string) (storage.FileSharesClient, error) {
+func NewFileShareClient(subscriptionID string, ops store.Operations) (storage.FileSharesClient, error) {
 	containerGroupsClient := storage.NewFileSharesClient(subscriptionID)
 	err := setupClient(&containerGroupsClient.Client)
 	if err != nil {
 		return storage.FileSharesClient{}, err
 	}
-	containerGroupsClient.PollingDelay = 5 * time.Second
-	containerGroupsClient.RetryAttempts = 30
-	containerGroupsClient.RetryDuration = 1 * time.Second
+	withOperations(&containerGroupsClient.Client, ops)
 	return containerGroupsClient, nil
 }
 
+// NewBlobContainersClient get client to manipulate blob containers
+func NewBlobContainersClient(subscriptionID string, ops store.Operations) (storage.BlobContainersClient, error) {
+	blobContainersClient := storage.NewBlobContainersClient(subscriptionID)
+	err := setupClient(&blobContainersClient.Client)
+	if err != nil {
+		return storage.BlobContainersClient{}, err
+	}
+	withOperations(&blobContainersClient.Client, ops)
+	return blobContainersClient, nil
+}
+
 // NewSubscriptionsClient get subscription client
 func NewSubscriptionsClient() (subscription.SubscriptionsClient, error) {
 	subc := subscription.NewSubscriptionsClient()
@@ -101,11 +349,34 @@ func NewGroupsClient(subscriptionID string) (resources.GroupsClient, error) {
 }
 
 // NewContainerClient get client to manipulate containers
-func NewContainerClient(subscriptionID string) (containerinstance.ContainerClient, error) {
+func NewContainerClient(subscriptionID string, ops store.Operations) (containerinstance.ContainerClient, error) {
 	containerClient := containerinstance.NewContainerClient(subscriptionID)
 	err := setupClient(&containerClient.Client)
 	if err != nil {
 		return containerinstance.ContainerClient{}, err
 	}
+	withOperations(&containerClient.Client, ops)
 	return containerClient, nil
 }
+
+// NewRegistriesClient get client to manipulate container registries
+func NewRegistriesClient(subscriptionID string, ops store.Operations) (containerregistry.RegistriesClient, error) {
+	registriesClient := containerregistry.NewRegistriesClient(subscriptionID)
+	err := setupClient(&registriesClient.Client)
+	if err != nil {
+		return containerregistry.RegistriesClient{}, err
+	}
+	withOperations(&registriesClient.Client, ops)
+	return registriesClient, nil
+}
+
+// NewRunsClient get client to follow container registry task runs
+func NewRunsClient(subscriptionID string, ops store.Operations) (containerregistry.RunsClient, error) {
+	runsClient := containerregistry.NewRunsClient(subscriptionID)
+	err := setupClient(&runsClient.Client)
+	if err != nil {
+		return containerregistry.RunsClient{}, err
+	}
+	withOperations(&runsClient.Client, ops)
+	return runsClient, nil
+}
@@ -45,6 +45,13 @@ const (
 	// v1 scope like "https://management.azure.com/.default" for ARM access
 	scopes   = "offline_access https://management.azure.com/.default"
 	clientID = "04b07795-8ddb-461a-bbee-02f9e1bf7b46" // Azure CLI client id
+
+	// keyVaultScopes are requested to read Key Vault secrets, Key Vault doesn't accept management tokens
+	keyVaultScopes = "offline_access https://vault.azure.net/.default"
+	// appConfigScopes are requested to read App Configuration key-values
+	appConfigScopes = "offline_access https://azconfig.io/.default"
+	// logAnalyticsScopes are requested to query Log Analytics workspaces
+	logAnalyticsScopes = "offline_access https://api.loganalytics.io/.default"
 )
 
 type (
@@ -297,6 +304,47 @@ func newAuthorizerFromLoginStorePath(storeTokenPath string) (autorest.Authorizer
 	return autorest.NewBearerAuthorizer(&token), nil
 }
 
+// NewKeyVaultAuthorizer creates an authorizer for Key Vault secrets, exchanging the login refresh token
+func NewKeyVaultAuthorizer() (autorest.Authorizer, error) {
+	return newScopedAuthorizer("Key Vault", keyVaultScopes)
+}
+
+// NewAppConfigAuthorizer returns an authorizer to read Azure App Configuration key-values
+func NewAppConfigAuthorizer() (autorest.Authorizer, error) {
+	return newScopedAuthorizer("App Configuration", appConfigScopes)
+}
+
+// NewLogAnalyticsAuthorizer returns an authorizer to query Azure Log Analytics workspaces
+func NewLogAnalyticsAuthorizer() (autorest.Authorizer, error) {
+	return newScopedAuthorizer("Log Analytics", logAnalyticsScopes)
+}
+
+// newScopedAuthorizer requests an access token for the scopes of a data plane service, refreshing the user login
+func newScopedAuthorizer(service string, scopes string) (autorest.Authorizer, error) {
+	if err := checkOnline(); err != nil {
+		return nil, err
+	}
+	login, err := NewAzureLoginService()
+	if err != nil {
+		return nil, err
+	}
+	loginInfo, err := login.tokenStore.readToken()
+	if err != nil {
+		return nil, errors.Wrap(err, "not logged in to azure, you need to run \"docker login azure\" first")
+	}
+	if loginInfo.Token.RefreshToken == "" {
+		return nil, errors.Wrapf(errdefs.ErrLoginRequired, "service principal logins can't access %s, you need to run \"docker login azure\"", service)
+	}
+	token, err := login.refreshTokenWithScopes(loginInfo.Token.RefreshToken, loginInfo.TenantID, scopes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s access token request failed", service)
+	}
+	return autorest.NewBearerAuthorizer(&adal.Token{
+		AccessToken: token.AccessToken,
+		Type:        token.TokenType,
+	}), nil
+}
+
 // GetTenantID returns tenantID for current login
 func (login AzureLoginService) GetTenantID() (string, error) {
 	loginInfo, err := login.tokenStore.readToken()
@@ -329,6 +377,10 @@ func (login *AzureLoginService) GetValidToken() (oauth2.Token, error) {
 }
 
 func (login *AzureLoginService) refreshToken(currentRefreshToken string, tenantID string) (oauth2.Token, error) {
+	return login.refreshTokenWithScopes(currentRefreshToken, tenantID, scopes)
+}
+
+func (login *AzureLoginService) refreshTokenWithScopes(currentRefreshToken string, tenantID string, scopes string) (oauth2.Token, error) {
 	data := url.Values{
 		"grant_type":    []string{"refresh_token"},
 		"client_id":     []string{clientID},
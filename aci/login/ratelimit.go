@@ -0,0 +1,134 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package login
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// subscriptionRequestRate keeps the API calls of a command, which polls many resources in parallel on large projects,
+	// below the Azure Resource Manager throttling of a subscription
+	subscriptionRequestRate  = rate.Limit(10)
+	subscriptionRequestBurst = 20
+)
+
+var (
+	limitersLock sync.Mutex
+	limiters     = map[string]*apiLimiter{}
+)
+
+// apiLimiter is shared by the clients calling an API for a subscription: it spaces their calls, and holds them all
+// once Azure throttled one of them until the Retry-After delay expires
+type apiLimiter struct {
+	limiter *rate.Limiter
+	lock    sync.Mutex
+	until   time.Time
+}
+
+// limiterFor returns the limiter of the subscription of a request, or of its host for APIs outside of subscriptions
+func limiterFor(r *http.Request) *apiLimiter {
+	key := r.URL.Host
+	if id := subscriptionOf(r.URL.Path); id != "" {
+		key = id
+	}
+	limitersLock.Lock()
+	defer limitersLock.Unlock()
+	l, ok := limiters[key]
+	if !ok {
+		l = &apiLimiter{limiter: rate.NewLimiter(subscriptionRequestRate, subscriptionRequestBurst)}
+		limiters[key] = l
+	}
+	return l
+}
+
+// subscriptionOf returns the subscription ID of a resource manager request path
+func subscriptionOf(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) > 1 && strings.EqualFold(segments[0], "subscriptions") {
+		return strings.ToLower(segments[1])
+	}
+	return ""
+}
+
+// wait blocks until the request can be sent
+func (l *apiLimiter) wait(r *http.Request) error {
+	l.lock.Lock()
+	delay := time.Until(l.until)
+	l.lock.Unlock()
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return r.Context().Err()
+		}
+	}
+	return l.limiter.Wait(r.Context())
+}
+
+// throttle holds the requests until the delay Azure asked for when throttling one
+func (l *apiLimiter) throttle(resp *http.Response) {
+	if resp == nil || resp.StatusCode != http.StatusTooManyRequests {
+		return
+	}
+	delay := RetryAfter(resp, time.Now())
+	if delay <= 0 {
+		return
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if until := time.Now().Add(delay); until.After(l.until) {
+		l.until = until
+	}
+}
+
+// RetryAfter parses the Retry-After header of a response, in seconds or as an HTTP date
+func RetryAfter(resp *http.Response, now time.Time) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+// doRateLimit sends each API call, including retries, through the limiter of its subscription
+func doRateLimit() autorest.SendDecorator {
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+			l := limiterFor(r)
+			if err := l.wait(r); err != nil {
+				return nil, err
+			}
+			resp, err := s.Do(r)
+			l.throttle(resp)
+			return resp, err
+		})
+	}
+}
@@ -0,0 +1,78 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package login
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"gotest.tools/v3/assert"
+)
+
+func TestSubscriptionOf(t *testing.T) {
+	assert.Equal(t, subscriptionOf("/subscriptions/ABC-123/resourceGroups/rg/providers/Microsoft.ContainerInstance/containerGroups/app"), "abc-123")
+	assert.Equal(t, subscriptionOf("/subscriptions"), "")
+	assert.Equal(t, subscriptionOf("/v1/workspaces/123/query"), "")
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC)
+	header := func(value string) *http.Response {
+		return &http.Response{Header: http.Header{"Retry-After": []string{value}}}
+	}
+	assert.Equal(t, RetryAfter(header("17"), now), 17*time.Second)
+	assert.Equal(t, RetryAfter(header(now.Add(time.Minute).Format(http.TimeFormat)), now), time.Minute)
+	assert.Equal(t, RetryAfter(header("soon"), now), time.Duration(0))
+	assert.Equal(t, RetryAfter(&http.Response{}, now), time.Duration(0))
+}
+
+func TestRateLimitSharedBySubscription(t *testing.T) {
+	request := func(path string) *http.Request {
+		return &http.Request{URL: &url.URL{Scheme: "https", Host: "management.azure.com", Path: path}}
+	}
+	a := limiterFor(request("/subscriptions/shared/resourceGroups/a"))
+	b := limiterFor(request("/subscriptions/SHARED/resourceGroups/b"))
+	other := limiterFor(request("/subscriptions/other/resourceGroups/a"))
+	assert.Assert(t, a == b)
+	assert.Assert(t, a != other)
+}
+
+func TestRateLimitHoldsThrottledSubscription(t *testing.T) {
+	calls := 0
+	sender := autorest.DecorateSender(autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		if calls == 1 {
+			return &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"1"}}}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}), doRateLimit())
+
+	r, err := http.NewRequest(http.MethodGet, "https://management.azure.com/subscriptions/throttled/resourceGroups/rg", nil)
+	assert.NilError(t, err)
+	resp, err := sender.Do(r)
+	assert.NilError(t, err)
+	assert.Equal(t, resp.StatusCode, http.StatusTooManyRequests)
+
+	start := time.Now()
+	resp, err = sender.Do(r)
+	assert.NilError(t, err)
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+	assert.Assert(t, time.Since(start) > 900*time.Millisecond)
+}
@@ -38,7 +38,7 @@ type StorageLoginImpl struct {
 
 // GetAzureStorageAccountKey retrieves the storage account ket from the current azure login
 func (helper StorageLoginImpl) GetAzureStorageAccountKey(ctx context.Context, accountName string) (string, error) {
-	client, err := NewStorageAccountsClient(helper.AciContext.SubscriptionID)
+	client, err := NewStorageAccountsClient(helper.AciContext.SubscriptionID, helper.AciContext.Operations())
 	if err != nil {
 		return "", err
 	}
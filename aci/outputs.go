@@ -0,0 +1,86 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// Outputs returns the public address of the project container group, the custom domains of its services and the
+// file shares backing its volumes
+func (cs *aciComposeService) Outputs(ctx context.Context, projectName string) ([]compose.StackOutput, error) {
+	group, err := getACIContainerGroup(ctx, cs.ctx, projectName)
+	if isNotFound(err) {
+		return nil, errors.Wrapf(errdefs.ErrNotFound, "project %q", projectName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	outputs := []compose.StackOutput{}
+	if group.IPAddress != nil {
+		if ip := to.String(group.IPAddress.IP); ip != "" {
+			outputs = append(outputs, compose.StackOutput{Name: "IPAddress", Value: ip, Description: "Public IP address of the container group"})
+		}
+		if fqdn := to.String(group.IPAddress.Fqdn); fqdn != "" {
+			outputs = append(outputs, compose.StackOutput{Name: "FQDN", Value: fqdn, Description: "DNS name of the container group"})
+		}
+	}
+	for tag, value := range group.Tags {
+		if !strings.HasPrefix(tag, domainTag+".") {
+			continue
+		}
+		service := strings.TrimPrefix(tag, domainTag+".")
+		parts := strings.SplitN(to.String(value), "/", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		domain := parts[1]
+		if parts[2] != "@" {
+			domain = parts[2] + "." + domain
+		}
+		outputs = append(outputs, compose.StackOutput{
+			Name:        service + "Domain",
+			Value:       domain,
+			Description: fmt.Sprintf("Custom domain of service %s", service),
+		})
+	}
+	if group.Volumes != nil {
+		for _, volume := range *group.Volumes {
+			if volume.AzureFile == nil {
+				continue
+			}
+			outputs = append(outputs, compose.StackOutput{
+				Name:        to.String(volume.Name) + "FileShare",
+				Value:       to.String(volume.AzureFile.StorageAccountName) + "/" + to.String(volume.AzureFile.ShareName),
+				Description: fmt.Sprintf("Azure file share of volume %s", to.String(volume.Name)),
+			})
+		}
+	}
+	sort.Slice(outputs, func(i, j int) bool {
+		return outputs[i].Name < outputs[j].Name
+	})
+	return outputs, nil
+}
@@ -0,0 +1,89 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+type permission struct {
+	actions []string
+	role    string
+}
+
+// permissions lists the Azure actions each compose operation relies on and the built-in role granting them
+var permissions = map[string]permission{
+	"up": {
+		actions: []string{
+			"Microsoft.ContainerInstance/containerGroups/write",
+			"Microsoft.ContainerInstance/containerGroups/read",
+			"Microsoft.Storage/storageAccounts/read",
+			"Microsoft.Storage/storageAccounts/write",
+			"Microsoft.Storage/storageAccounts/listKeys/action",
+			"Microsoft.Storage/storageAccounts/fileServices/shares/write",
+			"Microsoft.Network/dnsZones/read",
+			"Microsoft.Network/dnsZones/A/write",
+			"Microsoft.Logic/workflows/write",
+			"Microsoft.Authorization/roleAssignments/write",
+			"Microsoft.Network/virtualNetworks/subnets/join/action",
+			"Microsoft.Network/privateDnsZones/A/write",
+			"Microsoft.Network/applicationGateways/read",
+			"Microsoft.Network/applicationGateways/write",
+		},
+		role: `the "Contributor" role on the resource group`,
+	},
+	"down": {
+		actions: []string{
+			"Microsoft.ContainerInstance/containerGroups/delete",
+			"Microsoft.Logic/workflows/read",
+			"Microsoft.Logic/workflows/delete",
+			"Microsoft.Network/dnsZones/A/delete",
+			"Microsoft.Network/privateDnsZones/A/delete",
+		},
+		role: `the "Contributor" role on the resource group`,
+	},
+	"ps": {
+		actions: []string{
+			"Microsoft.ContainerInstance/containerGroups/read",
+		},
+		role: `the "Reader" role on the resource group`,
+	},
+}
+
+// deniedAction extracts the action from messages like "... does not have authorization to perform action 'Microsoft.ContainerInstance/containerGroups/write' over scope ..."
+var deniedAction = regexp.MustCompile(`perform action '([^']+)'`)
+
+// permissionError translates Azure authorization failures into an errdefs.PermissionError listing the missing actions
+func permissionError(operation string, err error) error {
+	if err == nil || !isStatus(err, http.StatusForbidden) && !deniedAction.MatchString(err.Error()) {
+		return err
+	}
+	perm := permissions[operation]
+	actions := perm.actions
+	if match := deniedAction.FindStringSubmatch(err.Error()); match != nil {
+		actions = []string{match[1]}
+	}
+	return errdefs.PermissionError{
+		Operation: "compose " + operation,
+		Actions:   actions,
+		Grant:     perm.role,
+		Err:       err,
+	}
+}
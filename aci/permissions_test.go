@@ -0,0 +1,56 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestPermissionErrorReportsDeniedAction(t *testing.T) {
+	denied := autorest.NewErrorWithError(errors.New("Code=\"AuthorizationFailed\" Message=\"The client 'dev@example.com' with object id '42' does not have authorization to perform action 'Microsoft.ContainerInstance/containerGroups/write' over scope '/subscriptions/123/resourceGroups/rg' or the scope is invalid.\""),
+		"containerinstance.ContainerGroupsClient", "CreateOrUpdate", &http.Response{StatusCode: http.StatusForbidden}, "Failure sending request")
+	err := permissionError("up", denied)
+	assert.Assert(t, errdefs.IsForbiddenError(err))
+
+	var perm errdefs.PermissionError
+	assert.Assert(t, errors.As(err, &perm))
+	assert.DeepEqual(t, perm.Actions, []string{"Microsoft.ContainerInstance/containerGroups/write"})
+	assert.Equal(t, perm.Grant, permissions["up"].role)
+}
+
+func TestPermissionErrorFallsBackToOperationActions(t *testing.T) {
+	denied := autorest.NewErrorWithError(errors.New("forbidden"), "containerinstance.ContainerGroupsClient", "Get", &http.Response{StatusCode: http.StatusForbidden}, "Failure responding to request")
+	err := permissionError("ps", denied)
+
+	var perm errdefs.PermissionError
+	assert.Assert(t, errors.As(err, &perm))
+	assert.DeepEqual(t, perm.Actions, permissions["ps"].actions)
+}
+
+func TestPermissionErrorKeepsOtherErrors(t *testing.T) {
+	notFound := autorest.NewErrorWithError(errors.New("not found"), "containerinstance.ContainerGroupsClient", "Get", &http.Response{StatusCode: http.StatusNotFound}, "Failure responding to request")
+	err := permissionError("down", notFound)
+	assert.Assert(t, !errdefs.IsForbiddenError(err))
+	assert.Assert(t, isNotFound(err))
+}
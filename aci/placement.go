@@ -0,0 +1,89 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// groupPatchAPIVersion is the first container instance API version supporting availability zones and subnet IDs,
+// which the SDK version we use doesn't know about
+const groupPatchAPIVersion = "2021-09-01"
+
+// groupPatch sets container group attributes the SDK doesn't know about on the JSON body of the group
+type groupPatch func(group map[string]interface{})
+
+// zonePatch deploys container groups to an availability zone
+func zonePatch(zone string) groupPatch {
+	return func(group map[string]interface{}) {
+		group["zones"] = []string{zone}
+	}
+}
+
+// subnetPatch deploys container groups to a virtual network subnet
+func subnetPatch(subnetID string) groupPatch {
+	return func(group map[string]interface{}) {
+		properties, ok := group["properties"].(map[string]interface{})
+		if !ok {
+			properties = map[string]interface{}{}
+			group["properties"] = properties
+		}
+		properties["subnetIds"] = []map[string]string{{"id": subnetID}}
+	}
+}
+
+// withGroupPatches applies patches to the container group sent by PUT requests
+func withGroupPatches(patches ...groupPatch) autorest.PrepareDecorator {
+	return func(p autorest.Preparer) autorest.Preparer {
+		return autorest.PreparerFunc(func(r *http.Request) (*http.Request, error) {
+			r, err := p.Prepare(r)
+			if err != nil || r.Method != http.MethodPut || r.Body == nil {
+				return r, err
+			}
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				return r, err
+			}
+			var group map[string]interface{}
+			if err := json.Unmarshal(body, &group); err != nil {
+				return r, err
+			}
+			for _, patch := range patches {
+				patch(group)
+			}
+			body, err = json.Marshal(group)
+			if err != nil {
+				return r, err
+			}
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+			r.ContentLength = int64(len(body))
+			r.GetBody = func() (io.ReadCloser, error) {
+				return ioutil.NopCloser(bytes.NewReader(body)), nil
+			}
+			query := r.URL.Query()
+			query.Set("api-version", groupPatchAPIVersion)
+			r.URL.RawQuery = query.Encode()
+			return r, nil
+		})
+	}
+}
@@ -0,0 +1,63 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+
+	"github.com/compose-spec/compose-go/types"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/aci/convert"
+	"github.com/docker/compose-cli/config"
+	"github.com/docker/compose-cli/registry"
+)
+
+// detectWindowsImages sets the Windows platform on services without a platform whose image is only built for Windows,
+// so that they get deployed to a Windows container group
+func detectWindowsImages(ctx context.Context, project *types.Project) {
+	if config.IsOffline() {
+		return
+	}
+	for i, service := range project.Services {
+		if service.Platform != "" || service.Image == "" {
+			continue
+		}
+		available, err := registry.ImagePlatforms(ctx, service.Image)
+		if err != nil {
+			logrus.Debugf("cannot get platforms of image %q: %v", service.Image, err)
+			continue
+		}
+		if windowsOnly(available) {
+			logrus.Debugf("image %q is only available for Windows, deploying service %q to a Windows container group", service.Image, service.Name)
+			project.Services[i].Platform = convert.WindowsPlatform
+		}
+	}
+}
+
+func windowsOnly(platforms []specs.Platform) bool {
+	if len(platforms) == 0 {
+		return false
+	}
+	for _, p := range platforms {
+		if p.OS != "windows" {
+			return false
+		}
+	}
+	return true
+}
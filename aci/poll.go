@@ -0,0 +1,147 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/docker/compose-cli/aci/login"
+	"github.com/docker/compose-cli/context/store"
+)
+
+// groupPoller batches the status checks of the container groups a command waits for, such as the groups of jobs
+// running in parallel: a single list of the resource group container groups per polling interval gives the
+// provisioning state of all of them. Listed groups don't come with their instance view, so the state of their
+// containers is unknown: a group still provisioning is left alone until the next interval, and a provisioned one,
+// or one missing from the list, has to be read.
+type groupPoller struct {
+	ctx    store.AciContext
+	client func() (groupLister, error)
+	lock   sync.Mutex
+	// next is the time of the next listing, delayed while Azure throttles the listings
+	next   time.Time
+	states map[string]string
+}
+
+// groupLister lists the container groups of a resource group
+type groupLister interface {
+	ListByResourceGroup(ctx context.Context, resourceGroupName string) (containerinstance.ContainerGroupListResultPage, error)
+}
+
+func newGroupPoller(ctx store.AciContext) *groupPoller {
+	return &groupPoller{
+		ctx: ctx,
+		client: func() (groupLister, error) {
+			return login.NewContainerGroupsClient(ctx.SubscriptionID, ctx.Operations())
+		},
+	}
+}
+
+// provisioningState returns the provisioning state of a container group, as listed during the last polling interval.
+// ok is false when the group wasn't listed, or when the listing was throttled: the group has to be read then, the
+// shared rate limiter of the subscription holding the read until the throttling delay expired.
+func (p *groupPoller) provisioningState(ctx context.Context, name string) (state string, ok bool, err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if now := time.Now(); !now.Before(p.next) {
+		states, err := p.list(ctx)
+		p.next = now.Add(p.ctx.Operations().PollingInterval)
+		switch delay, throttled := throttling(err, now); {
+		case throttled:
+			if until := now.Add(delay); until.After(p.next) {
+				p.next = until
+			}
+			p.states = nil
+		case err != nil:
+			return "", false, err
+		default:
+			p.states = states
+		}
+	}
+	state, ok = p.states[name]
+	return state, ok, nil
+}
+
+// list returns the provisioning state of the resource group container groups
+func (p *groupPoller) list(ctx context.Context) (map[string]string, error) {
+	groupsClient, err := p.client()
+	if err != nil {
+		return nil, err
+	}
+	result, err := groupsClient.ListByResourceGroup(ctx, p.ctx.ResourceGroup)
+	if err != nil {
+		return nil, err
+	}
+	states := map[string]string{}
+	for result.NotDone() {
+		for _, group := range result.Values() {
+			if group.ContainerGroupProperties != nil && group.ProvisioningState != nil {
+				states[to.String(group.Name)] = to.String(group.ProvisioningState)
+			}
+		}
+		if err := result.NextWithContext(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return states, nil
+}
+
+// throttling tells whether an API call failed being throttled by Azure, with the delay it asked to wait for
+func throttling(err error, now time.Time) (time.Duration, bool) {
+	var detailed autorest.DetailedError
+	if !errors.As(err, &detailed) || detailed.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	if detailed.Response == nil {
+		return 0, true
+	}
+	return login.RetryAfter(detailed.Response, now), true
+}
+
+// groupProvisioning tells whether a container group provisioning state is one its containers can't have run in yet
+func groupProvisioning(state string) bool {
+	return state == "Pending" || state == "Creating" || state == "Repairing"
+}
+
+// getGroupsDetails reads the container groups in parallel, the instance view of their containers not being listed.
+// The subscription rate limit of the clients spaces the reads of large projects.
+func getGroupsDetails(ctx context.Context, aciContext store.AciContext, listed []containerinstance.ContainerGroup) ([]containerinstance.ContainerGroup, error) {
+	groupsClient, err := login.NewContainerGroupsClient(aciContext.SubscriptionID, aciContext.Operations())
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]containerinstance.ContainerGroup, len(listed))
+	eg, ctx := errgroup.WithContext(ctx)
+	for i, group := range listed {
+		i, name := i, to.String(group.Name)
+		eg.Go(func() error {
+			group, err := groupsClient.Get(ctx, aciContext.ResourceGroup, name)
+			groups[i] = group
+			return err
+		})
+	}
+	return groups, eg.Wait()
+}
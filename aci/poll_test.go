@@ -0,0 +1,117 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/context/store"
+)
+
+// fakeGroupLister returns the listed groups in a single page, or fails with err
+type fakeGroupLister struct {
+	groups []containerinstance.ContainerGroup
+	err    error
+	calls  int
+}
+
+func (f *fakeGroupLister) ListByResourceGroup(ctx context.Context, resourceGroupName string) (containerinstance.ContainerGroupListResultPage, error) {
+	f.calls++
+	if f.err != nil {
+		return containerinstance.ContainerGroupListResultPage{}, f.err
+	}
+	page := containerinstance.NewContainerGroupListResultPage(func(ctx context.Context, current containerinstance.ContainerGroupListResult) (containerinstance.ContainerGroupListResult, error) {
+		if current.Value != nil {
+			return containerinstance.ContainerGroupListResult{}, nil
+		}
+		return containerinstance.ContainerGroupListResult{Value: &f.groups}, nil
+	})
+	return page, page.NextWithContext(ctx)
+}
+
+func listedGroup(name string, provisioningState string) containerinstance.ContainerGroup {
+	return containerinstance.ContainerGroup{
+		Name: to.StringPtr(name),
+		ContainerGroupProperties: &containerinstance.ContainerGroupProperties{
+			ProvisioningState: to.StringPtr(provisioningState),
+		},
+	}
+}
+
+func testPoller(lister *fakeGroupLister, pollingInterval string) *groupPoller {
+	p := newGroupPoller(store.AciContext{ResourceGroup: "rg", OperationSettings: store.OperationSettings{PollingInterval: pollingInterval}})
+	p.client = func() (groupLister, error) {
+		return lister, nil
+	}
+	return p
+}
+
+func TestGroupPollerBatchesListings(t *testing.T) {
+	lister := &fakeGroupLister{groups: []containerinstance.ContainerGroup{
+		listedGroup("shop-migrate", "Creating"),
+		listedGroup("shop-seed", "Succeeded"),
+	}}
+	p := testPoller(lister, "1h")
+	ctx := context.Background()
+
+	state, ok, err := p.provisioningState(ctx, "shop-migrate")
+	assert.NilError(t, err)
+	assert.Assert(t, ok)
+	assert.Assert(t, groupProvisioning(state))
+
+	state, ok, err = p.provisioningState(ctx, "shop-seed")
+	assert.NilError(t, err)
+	assert.Assert(t, ok)
+	assert.Assert(t, !groupProvisioning(state))
+
+	_, ok, err = p.provisioningState(ctx, "shop-report")
+	assert.NilError(t, err)
+	assert.Assert(t, !ok)
+	assert.Equal(t, lister.calls, 1)
+}
+
+func TestGroupPollerWaitsRetryAfter(t *testing.T) {
+	lister := &fakeGroupLister{err: autorest.DetailedError{
+		StatusCode: http.StatusTooManyRequests,
+		Response:   &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"3600"}}},
+	}}
+	p := testPoller(lister, "1ms")
+	ctx := context.Background()
+
+	_, ok, err := p.provisioningState(ctx, "shop-migrate")
+	assert.NilError(t, err)
+	assert.Assert(t, !ok)
+	time.Sleep(10 * time.Millisecond)
+	_, ok, err = p.provisioningState(ctx, "shop-migrate")
+	assert.NilError(t, err)
+	assert.Assert(t, !ok)
+	assert.Equal(t, lister.calls, 1)
+	assert.Assert(t, time.Until(p.next) > 59*time.Minute)
+
+	lister.err = autorest.DetailedError{StatusCode: http.StatusForbidden}
+	p.next = time.Time{}
+	_, _, err = p.provisioningState(ctx, "shop-migrate")
+	assert.Assert(t, err != nil)
+}
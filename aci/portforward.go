@@ -0,0 +1,37 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// errPortForward explains why ports can't be forwarded: ACI exec sessions are terminals limited to a single
+// binary without arguments, they can't carry raw TCP traffic to a container port
+var errPortForward = errors.Wrap(errdefs.ErrNotImplemented, "ACI exec sessions cannot tunnel TCP traffic, publish the port to reach it")
+
+func (cs *aciContainerService) PortForward(ctx context.Context, containerID string, localPort, remotePort uint32) error {
+	return errPortForward
+}
+
+func (cs *aciComposeService) PortForward(ctx context.Context, projectName string, service string, localPort, remotePort uint32) error {
+	return errPortForward
+}
@@ -0,0 +1,146 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/aci/convert"
+	"github.com/docker/compose-cli/aci/login"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/registry"
+)
+
+// preflight checks the project can be deployed before any resource is created, reporting all problems at once
+func (cs *aciComposeService) preflight(ctx context.Context, project *types.Project, options compose.UpOptions) error {
+	return compose.Preflight(ctx,
+		func(ctx context.Context) error {
+			osType, err := convert.GroupOSType(*project)
+			if err != nil {
+				return err
+			}
+			limits, err := cs.regionLimits(ctx, string(osType))
+			if err != nil {
+				return err
+			}
+			return convert.ValidateResources(*groupProject(project), limits)
+		},
+		func(ctx context.Context) error {
+			return checkAccessExtensions(project)
+		},
+		func(ctx context.Context) error {
+			return cs.checkCoreQuota(ctx, *project)
+		},
+		func(ctx context.Context) error {
+			var services []types.ServiceConfig
+			for _, service := range project.Services {
+				// images built by --builder don't exist yet
+				if options.Builder != "" && service.Build != nil {
+					continue
+				}
+				services = append(services, service)
+			}
+			return registry.CheckImages(ctx, services)
+		},
+	)
+}
+
+const (
+	extensionAllowedCIDRs = "x-azure-allowed_cidrs"
+	extensionDeniedCIDRs  = "x-azure-denied_cidrs"
+)
+
+// checkAccessExtensions rejects IP allow and deny lists, which ACI can't apply to the public IP of a container group,
+// rather than deploying services open to the world
+func checkAccessExtensions(project *types.Project) error {
+	for _, extension := range []string{extensionAllowedCIDRs, extensionDeniedCIDRs} {
+		if _, ok := project.Extensions[extension]; ok {
+			return errors.Wrapf(errdefs.ErrNotImplemented, "%s: ACI can't restrict access to the public IP address of a container group", extension)
+		}
+	}
+	return nil
+}
+
+// regionLimits returns the resources a container group of the given OS can use in the context location
+func (cs *aciComposeService) regionLimits(ctx context.Context, osType string) (convert.GroupLimits, error) {
+	client, err := login.NewContainerGroupsClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
+	if err != nil {
+		return convert.GroupLimits{}, err
+	}
+	result, err := client.ListCapabilities(ctx, cs.ctx.Location)
+	if err != nil || result.Value == nil {
+		logrus.Warnf("cannot check container instances capabilities in %s: %v", cs.ctx.Location, err)
+		return convert.DefaultGroupLimits, nil
+	}
+	var limits convert.GroupLimits
+	for _, c := range *result.Value {
+		if !strings.EqualFold(to.String(c.OsType), osType) || c.Capabilities == nil {
+			continue
+		}
+		if gpu := to.String(c.Gpu); gpu != "" && !strings.EqualFold(gpu, "None") {
+			continue
+		}
+		limits.CPU = math.Max(limits.CPU, to.Float64(c.Capabilities.MaxCPU))
+		limits.MemoryGB = math.Max(limits.MemoryGB, to.Float64(c.Capabilities.MaxMemoryInGB))
+	}
+	if limits.CPU == 0 {
+		return convert.GroupLimits{}, fmt.Errorf("location %q doesn't support %s container instances", cs.ctx.Location, osType)
+	}
+	return limits, nil
+}
+
+// checkCoreQuota verifies the subscription has enough container instances cores left in the location to create the project group
+func (cs *aciComposeService) checkCoreQuota(ctx context.Context, project types.Project) error {
+	if _, err := getACIContainerGroup(ctx, cs.ctx, project.Name); err == nil {
+		// cores of a deployed group are already counted in the usage
+		return nil
+	}
+	required, err := convert.GroupCPUs(*groupProject(&project))
+	if err != nil {
+		return err
+	}
+	client, err := login.NewContainerGroupUsageClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
+	if err != nil {
+		return err
+	}
+	result, err := client.List(ctx, cs.ctx.Location)
+	if err != nil || result.Value == nil {
+		logrus.Warnf("cannot check container instances quota in %s: %v", cs.ctx.Location, err)
+		return nil
+	}
+	for _, usage := range *result.Value {
+		if usage.Name == nil || to.String(usage.Name.Value) != "StandardCores" {
+			continue
+		}
+		limit := float64(to.Int32(usage.Limit))
+		available := limit - float64(to.Int32(usage.CurrentValue))
+		if required > available {
+			return fmt.Errorf("the project requires %g cores, but only %g are available out of the %g allowed in location %s: "+
+				"request a quota increase for Azure Container Instances standard cores", required, available, limit, cs.ctx.Location)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-05-01/network"
+	"github.com/Azure/azure-sdk-for-go/services/privatedns/mgmt/2018-09-01/privatedns"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/aci/login"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/progress"
+)
+
+// containerGroupsDelegation is the service subnets must be delegated to for container groups to be deployed in them
+const containerGroupsDelegation = "Microsoft.ContainerInstance/containerGroups"
+
+// ensureSubnetDelegation delegates the context subnet to ACI, unless it already is
+func ensureSubnetDelegation(ctx context.Context, aciContext store.AciContext) error {
+	subscriptionID, resourceGroup, vnet, name := aciContext.SubnetParts()
+	client, err := login.NewSubnetsClient(subscriptionID, aciContext.Operations())
+	if err != nil {
+		return err
+	}
+	subnet, err := client.Get(ctx, resourceGroup, vnet, name, "")
+	if err != nil {
+		return errors.Wrapf(err, "cannot get subnet %s of virtual network %s", name, vnet)
+	}
+	if subnet.SubnetPropertiesFormat == nil {
+		subnet.SubnetPropertiesFormat = &network.SubnetPropertiesFormat{}
+	}
+	var delegations []network.Delegation
+	if subnet.Delegations != nil {
+		delegations = *subnet.Delegations
+	}
+	for _, d := range delegations {
+		if d.ServiceDelegationPropertiesFormat != nil && to.String(d.ServiceName) == containerGroupsDelegation {
+			return nil
+		}
+	}
+	w := progress.ContextWriter(ctx)
+	w.Event(progress.Event{ID: "Subnet " + name, Status: progress.Working, StatusText: "Delegating to ACI"})
+	delegations = append(delegations, network.Delegation{
+		Name: to.StringPtr("aci"),
+		ServiceDelegationPropertiesFormat: &network.ServiceDelegationPropertiesFormat{
+			ServiceName: to.StringPtr(containerGroupsDelegation),
+		},
+	})
+	subnet.Delegations = &delegations
+	future, err := client.CreateOrUpdate(ctx, resourceGroup, vnet, name, subnet)
+	if err == nil {
+		err = future.WaitForCompletionRef(ctx, client.Client)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "cannot delegate subnet %s to ACI", name)
+	}
+	w.Event(progress.Event{ID: "Subnet " + name, Status: progress.Done, StatusText: "Delegated to ACI"})
+	return nil
+}
+
+// updatePrivateEndpoints points the project private DNS record and Application Gateway backend pool to the private IP
+// of the container group
+func (cs *aciComposeService) updatePrivateEndpoints(ctx context.Context, project string) error {
+	if cs.ctx.PrivateDNSZone == "" && cs.ctx.ApplicationGateway == "" {
+		return nil
+	}
+	group, err := getACIContainerGroup(ctx, cs.ctx, project)
+	if err != nil {
+		return err
+	}
+	if group.IPAddress == nil || group.IPAddress.IP == nil {
+		return fmt.Errorf("container group %q has no private IP address, services must publish ports to be reached", project)
+	}
+	ip := to.String(group.IPAddress.IP)
+	if cs.ctx.PrivateDNSZone != "" {
+		if err := cs.setPrivateRecord(ctx, project, ip); err != nil {
+			return err
+		}
+	}
+	if cs.ctx.ApplicationGateway != "" {
+		return cs.setGatewayBackend(ctx, project, []network.ApplicationGatewayBackendAddress{{IPAddress: to.StringPtr(ip)}})
+	}
+	return nil
+}
+
+// removePrivateEndpoints deletes the project private DNS record and empties its Application Gateway backend pool
+func (cs *aciComposeService) removePrivateEndpoints(ctx context.Context, project string) error {
+	if cs.ctx.PrivateDNSZone != "" {
+		client, err := login.NewPrivateRecordSetsClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
+		if err != nil {
+			return err
+		}
+		_, err = client.Delete(ctx, cs.ctx.ResourceGroup, cs.ctx.PrivateDNSZone, privatedns.A, privateRecordName(project), "")
+		if err != nil && !isNotFound(errors.Cause(err)) {
+			return errors.Wrapf(err, "cannot remove private DNS record %s.%s", privateRecordName(project), cs.ctx.PrivateDNSZone)
+		}
+	}
+	if cs.ctx.ApplicationGateway != "" {
+		return cs.setGatewayBackend(ctx, project, []network.ApplicationGatewayBackendAddress{})
+	}
+	return nil
+}
+
+func (cs *aciComposeService) setPrivateRecord(ctx context.Context, project string, ip string) error {
+	w := progress.ContextWriter(ctx)
+	record := privateRecordName(project) + "." + cs.ctx.PrivateDNSZone
+	w.Event(progress.Event{ID: record, Status: progress.Working, StatusText: "Creating private DNS record"})
+	client, err := login.NewPrivateRecordSetsClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
+	if err != nil {
+		return err
+	}
+	_, err = client.CreateOrUpdate(ctx, cs.ctx.ResourceGroup, cs.ctx.PrivateDNSZone, privatedns.A, privateRecordName(project), privatedns.RecordSet{
+		RecordSetProperties: &privatedns.RecordSetProperties{
+			TTL:      to.Int64Ptr(domainRecordTTL),
+			ARecords: &[]privatedns.ARecord{{Ipv4Address: to.StringPtr(ip)}},
+			Metadata: map[string]*string{compose.ProjectTag: to.StringPtr(project)},
+		},
+	}, "", "")
+	if err != nil {
+		return errors.Wrapf(err, "cannot create private DNS record %s", record)
+	}
+	w.Event(progress.Event{ID: record, Status: progress.Done, StatusText: "Created private DNS record"})
+	return nil
+}
+
+// setGatewayBackend sets the addresses of the Application Gateway backend pool named after the project. The pool,
+// and the listeners and rules routing traffic to it, are managed by the gateway administrators.
+func (cs *aciComposeService) setGatewayBackend(ctx context.Context, project string, addresses []network.ApplicationGatewayBackendAddress) error {
+	w := progress.ContextWriter(ctx)
+	id := "Gateway " + cs.ctx.ApplicationGateway
+	w.Event(progress.Event{ID: id, Status: progress.Working, StatusText: "Updating backend pool"})
+	client, err := login.NewApplicationGatewaysClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
+	if err != nil {
+		return err
+	}
+	gateway, err := client.Get(ctx, cs.ctx.ResourceGroup, cs.ctx.ApplicationGateway)
+	if err != nil {
+		return errors.Wrapf(err, "cannot get Application Gateway %s", cs.ctx.ApplicationGateway)
+	}
+	if !setBackendPool(&gateway, project, addresses) {
+		return fmt.Errorf("Application Gateway %s has no backend pool named %q to route traffic to the project", cs.ctx.ApplicationGateway, project)
+	}
+	future, err := client.CreateOrUpdate(ctx, cs.ctx.ResourceGroup, cs.ctx.ApplicationGateway, gateway)
+	if err == nil {
+		err = future.WaitForCompletionRef(ctx, client.Client)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "cannot update Application Gateway %s", cs.ctx.ApplicationGateway)
+	}
+	w.Event(progress.Event{ID: id, Status: progress.Done, StatusText: "Updated backend pool"})
+	return nil
+}
+
+// setBackendPool replaces the addresses of the backend pool, returns false if the gateway has no such pool
+func setBackendPool(gateway *network.ApplicationGateway, pool string, addresses []network.ApplicationGatewayBackendAddress) bool {
+	if gateway.ApplicationGatewayPropertiesFormat == nil || gateway.BackendAddressPools == nil {
+		return false
+	}
+	for i, p := range *gateway.BackendAddressPools {
+		if !strings.EqualFold(to.String(p.Name), pool) {
+			continue
+		}
+		if p.ApplicationGatewayBackendAddressPoolPropertiesFormat == nil {
+			p.ApplicationGatewayBackendAddressPoolPropertiesFormat = &network.ApplicationGatewayBackendAddressPoolPropertiesFormat{}
+		}
+		p.BackendAddresses = &addresses
+		(*gateway.BackendAddressPools)[i] = p
+		return true
+	}
+	return false
+}
+
+func privateRecordName(project string) string {
+	return strings.ToLower(project)
+}
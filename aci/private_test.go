@@ -0,0 +1,74 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-05-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"gotest.tools/v3/assert"
+)
+
+func TestGroupPatches(t *testing.T) {
+	req := &http.Request{
+		Method: http.MethodPut,
+		URL:    &url.URL{Path: "/containerGroups/demo", RawQuery: "api-version=2018-10-01"},
+		Body:   ioutil.NopCloser(bytes.NewBufferString(`{"location":"eastus","properties":{"osType":"Linux"}}`)),
+	}
+	req, err := autorest.Prepare(req, withGroupPatches(zonePatch("2"), subnetPatch("/subscriptions/1/subnets/aci")))
+	assert.NilError(t, err)
+	assert.Equal(t, req.URL.Query().Get("api-version"), groupPatchAPIVersion)
+
+	body, err := ioutil.ReadAll(req.Body)
+	assert.NilError(t, err)
+	assert.Equal(t, req.ContentLength, int64(len(body)))
+	var group map[string]interface{}
+	assert.NilError(t, json.Unmarshal(body, &group))
+	assert.DeepEqual(t, group, map[string]interface{}{
+		"location": "eastus",
+		"zones":    []interface{}{"2"},
+		"properties": map[string]interface{}{
+			"osType":    "Linux",
+			"subnetIds": []interface{}{map[string]interface{}{"id": "/subscriptions/1/subnets/aci"}},
+		},
+	})
+}
+
+func TestSetBackendPool(t *testing.T) {
+	gateway := network.ApplicationGateway{
+		ApplicationGatewayPropertiesFormat: &network.ApplicationGatewayPropertiesFormat{
+			BackendAddressPools: &[]network.ApplicationGatewayBackendAddressPool{
+				{Name: to.StringPtr("other")},
+				{Name: to.StringPtr("demo")},
+			},
+		},
+	}
+	addresses := []network.ApplicationGatewayBackendAddress{{IPAddress: to.StringPtr("10.0.0.4")}}
+	assert.Assert(t, setBackendPool(&gateway, "demo", addresses))
+	pools := *gateway.BackendAddressPools
+	assert.Assert(t, pools[0].ApplicationGatewayBackendAddressPoolPropertiesFormat == nil)
+	assert.DeepEqual(t, *pools[1].BackendAddresses, addresses)
+
+	assert.Assert(t, !setBackendPool(&gateway, "missing", addresses))
+}
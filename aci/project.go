@@ -0,0 +1,276 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/compose-spec/compose-go/types"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/docker/compose-cli/aci/convert"
+	"github.com/docker/compose-cli/aci/login"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/progress"
+)
+
+// projectMetadata is the file share metadata tracking the compose project a volume belongs to.
+// Azure metadata names must be valid C# identifiers, so compose.ProjectTag can't be used as is.
+const projectMetadata = "docker_compose_project"
+
+// groupImages returns images of the compose services deployed in a container group
+func groupImages(group containerinstance.ContainerGroup) []string {
+	var images []string
+	if group.ContainerGroupProperties == nil || group.Containers == nil {
+		return images
+	}
+	for _, container := range *group.Containers {
+		if to.String(container.Name) == convert.ComposeDNSSidecarName || container.ContainerProperties == nil {
+			continue
+		}
+		images = append(images, to.String(container.Image))
+	}
+	return images
+}
+
+// removeOrphans deletes container groups labelled with the project, other than the project container group
+func (cs *aciComposeService) removeOrphans(ctx context.Context, project string) error {
+	groupsClient, err := login.NewContainerGroupsClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
+	if err != nil {
+		return err
+	}
+	result, err := groupsClient.ListByResourceGroup(ctx, cs.ctx.ResourceGroup)
+	if err != nil {
+		return err
+	}
+	var orphans []string
+	for result.NotDone() {
+		for _, group := range result.Values() {
+			if to.String(group.Name) != project && to.String(group.Tags[compose.ProjectTag]) == project {
+				orphans = append(orphans, to.String(group.Name))
+			}
+		}
+		if err := result.NextWithContext(ctx); err != nil {
+			return err
+		}
+	}
+
+	w := progress.ContextWriter(ctx)
+	eg, ctx := errgroup.WithContext(ctx)
+	for _, name := range orphans {
+		name := name
+		eg.Go(func() error {
+			w.Event(event(name, progress.Working, "Deleting"))
+			if _, err := deleteACIContainerGroup(ctx, cs.ctx, name); err != nil {
+				w.Event(errorEvent(name))
+				return err
+			}
+			w.Event(event(name, progress.Done, "Deleted"))
+			return nil
+		})
+	}
+	return eg.Wait()
+}
+
+// labelProjectVolumes marks file shares bound to non external volumes as belonging to the project,
+// unless they already belong to another one, so they can be removed by `compose down --volumes`
+func (cs *aciComposeService) labelProjectVolumes(ctx context.Context, project *types.Project) error {
+	fileShareClient, err := login.NewFileShareClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
+	if err != nil {
+		return err
+	}
+	g := compose.NewGraph()
+	for name, v := range project.Volumes {
+		accountName, shareName, ok := convert.AzureFileShare(v)
+		if !ok || v.External.External {
+			continue
+		}
+		g.Add(name, func(ctx context.Context) error {
+			share, err := fileShareClient.Get(ctx, cs.ctx.ResourceGroup, accountName, shareName, "")
+			if err != nil {
+				return err
+			}
+			if share.FileShareProperties == nil {
+				share.FileShareProperties = &storage.FileShareProperties{}
+			}
+			if _, ok := share.Metadata[projectMetadata]; ok {
+				return nil
+			}
+			if share.Metadata == nil {
+				share.Metadata = map[string]*string{}
+			}
+			share.Metadata[projectMetadata] = to.StringPtr(project.Name)
+			_, err = fileShareClient.Update(ctx, cs.ctx.ResourceGroup, accountName, shareName, storage.FileShare{
+				FileShareProperties: &storage.FileShareProperties{
+					Metadata: share.Metadata,
+				},
+			})
+			return err
+		})
+	}
+	return g.Run(ctx, compose.MaxConcurrency())
+}
+
+// removeVolumes deletes file shares labelled with the project
+func (cs *aciComposeService) removeVolumes(ctx context.Context, project string) error {
+	volumes, err := cs.listProjectVolumes(ctx)
+	if err != nil {
+		return err
+	}
+	fileShareClient, err := login.NewFileShareClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
+	if err != nil {
+		return err
+	}
+	w := progress.ContextWriter(ctx)
+	for _, r := range volumes {
+		if r.Type != resourceFileShare || r.Project != project {
+			continue
+		}
+		w.Event(event(r.ID, progress.Working, "Deleting"))
+		tokens := strings.SplitN(r.ID, "/", 2)
+		if _, err := fileShareClient.Delete(ctx, cs.ctx.ResourceGroup, tokens[0], tokens[1]); err != nil {
+			w.Event(errorEvent(r.ID))
+			return err
+		}
+		w.Event(event(r.ID, progress.Done, "Deleted"))
+	}
+	return nil
+}
+
+const (
+	resourceContainerGroup = "container group"
+	resourceFileShare      = "file share"
+	resourceStorageAccount = "storage account"
+)
+
+func (cs *aciComposeService) Prune(ctx context.Context, options compose.PruneOptions) ([]compose.OrphanResource, error) {
+	deployed, resources, err := cs.listProjectGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	volumes, err := cs.listProjectVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	orphans := compose.Orphans(append(resources, volumes...), deployed, options)
+	if options.DryRun {
+		return orphans, nil
+	}
+
+	accountClient, err := login.NewStorageAccountsClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
+	if err != nil {
+		return nil, err
+	}
+	fileShareClient, err := login.NewFileShareClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
+	if err != nil {
+		return nil, err
+	}
+	w := progress.ContextWriter(ctx)
+	for _, r := range orphans {
+		w.Event(event(r.ID, progress.Working, "Deleting"))
+		switch r.Type {
+		case resourceContainerGroup:
+			_, err = deleteACIContainerGroup(ctx, cs.ctx, r.ID)
+		case resourceFileShare:
+			tokens := strings.SplitN(r.ID, "/", 2)
+			_, err = fileShareClient.Delete(ctx, cs.ctx.ResourceGroup, tokens[0], tokens[1])
+		case resourceStorageAccount:
+			_, err = accountClient.Delete(ctx, cs.ctx.ResourceGroup, r.ID)
+		}
+		if err != nil {
+			w.Event(errorEvent(r.ID))
+			return nil, err
+		}
+		w.Event(event(r.ID, progress.Done, "Deleted"))
+	}
+	return orphans, nil
+}
+
+// listProjectGroups returns names of the container groups and those labelled with another project
+func (cs *aciComposeService) listProjectGroups(ctx context.Context) ([]string, []compose.OrphanResource, error) {
+	groupsClient, err := login.NewContainerGroupsClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
+	if err != nil {
+		return nil, nil, err
+	}
+	groups, err := groupsClient.ListByResourceGroup(ctx, cs.ctx.ResourceGroup)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		names     []string
+		resources []compose.OrphanResource
+	)
+	for groups.NotDone() {
+		for _, group := range groups.Values() {
+			name := to.String(group.Name)
+			names = append(names, name)
+			if project := to.String(group.Tags[compose.ProjectTag]); project != "" && project != name {
+				resources = append(resources, compose.OrphanResource{ID: name, Type: resourceContainerGroup, Project: project})
+			}
+		}
+		if err := groups.NextWithContext(ctx); err != nil {
+			return nil, nil, err
+		}
+	}
+	return names, resources, nil
+}
+
+// listProjectVolumes returns file shares and storage accounts labelled with a project, storage accounts last
+// so they are deleted once their file shares are
+func (cs *aciComposeService) listProjectVolumes(ctx context.Context) ([]compose.OrphanResource, error) {
+	accountClient, err := login.NewStorageAccountsClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := accountClient.ListByResourceGroup(ctx, cs.ctx.ResourceGroup)
+	if err != nil {
+		return nil, err
+	}
+	fileShareClient, err := login.NewFileShareClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
+	if err != nil {
+		return nil, err
+	}
+	var shares, storageAccounts []compose.OrphanResource
+	for _, account := range *accounts.Value {
+		accountName := to.String(account.Name)
+		if project := to.String(account.Tags[compose.ProjectTag]); project != "" {
+			storageAccounts = append(storageAccounts, compose.OrphanResource{ID: accountName, Type: resourceStorageAccount, Project: project})
+		}
+		page, err := fileShareClient.List(ctx, cs.ctx.ResourceGroup, accountName, "", "", "")
+		if err != nil {
+			return nil, err
+		}
+		for page.NotDone() {
+			for _, share := range page.Values() {
+				if share.FileShareProperties == nil {
+					continue
+				}
+				if project := to.String(share.Metadata[projectMetadata]); project != "" {
+					shares = append(shares, compose.OrphanResource{ID: volumeID(accountName, to.String(share.Name)), Type: resourceFileShare, Project: project})
+				}
+			}
+			if err := page.NextWithContext(ctx); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return append(shares, storageAccounts...), nil
+}
@@ -0,0 +1,88 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/Azure/go-autorest/autorest/to"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/progress"
+)
+
+type reconcileAction int
+
+const (
+	reconcileDeploy reconcileAction = iota
+	reconcileWait
+	reconcileDelete
+)
+
+// reconcileContainerGroup brings the project container group back to a state it can be deployed from, after a command was interrupted.
+// It returns the container group once stable, or a not found error if it has to be created.
+func (cs *aciComposeService) reconcileContainerGroup(ctx context.Context, name string, resume bool) (containerinstance.ContainerGroup, error) {
+	w := progress.ContextWriter(ctx)
+	for {
+		group, err := getACIContainerGroup(ctx, cs.ctx, name)
+		if err != nil {
+			return group, err
+		}
+		state := to.String(group.ProvisioningState)
+		action, err := groupReconcileAction(name, state, resume)
+		if err != nil {
+			return group, err
+		}
+		switch action {
+		case reconcileDeploy:
+			return group, nil
+		case reconcileWait:
+			w.Event(progress.Event{ID: "Group " + name, Status: progress.Working, StatusText: "Waiting for " + state})
+			select {
+			case <-time.After(cs.ctx.Operations().PollingInterval):
+			case <-ctx.Done():
+				return group, ctx.Err()
+			}
+		case reconcileDelete:
+			w.Event(progress.Event{ID: "Group " + name, Status: progress.Working, StatusText: "Deleting group left " + state})
+			if _, err := deleteACIContainerGroup(ctx, cs.ctx, name); err != nil {
+				return group, err
+			}
+		}
+	}
+}
+
+// groupReconcileAction decides how to handle a container group left by a previous command depending on its provisioning state
+func groupReconcileAction(name string, state string, resume bool) (reconcileAction, error) {
+	switch state {
+	case "Succeeded":
+		return reconcileDeploy, nil
+	case "Failed":
+		// config hashes were recorded before the deployment failed, they don't describe what is running
+		return reconcileDelete, nil
+	case "Pending", "Creating", "Updating", "Repairing":
+		if !resume {
+			return 0, compose.InterruptedError(fmt.Sprintf("container group %q", name), state)
+		}
+		return reconcileWait, nil
+	default:
+		return reconcileDeploy, nil
+	}
+}
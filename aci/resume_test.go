@@ -0,0 +1,42 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestGroupReconcileAction(t *testing.T) {
+	action, err := groupReconcileAction("test", "Succeeded", false)
+	assert.NilError(t, err)
+	assert.Equal(t, action, reconcileDeploy)
+
+	action, err = groupReconcileAction("test", "Failed", false)
+	assert.NilError(t, err)
+	assert.Equal(t, action, reconcileDelete)
+
+	_, err = groupReconcileAction("test", "Creating", false)
+	assert.Assert(t, errdefs.IsErrLocked(err))
+
+	action, err = groupReconcileAction("test", "Creating", true)
+	assert.NilError(t, err)
+	assert.Equal(t, action, reconcileWait)
+}
@@ -0,0 +1,281 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/authorization/mgmt/2015-07-01/authorization"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/docker/compose-cli/aci/convert"
+	"github.com/docker/compose-cli/aci/login"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/progress"
+)
+
+// scheduleTag marks container groups and workflows running a scheduled service of a compose application
+const scheduleTag = "docker-compose-schedule"
+
+const (
+	workflowsAPIVersion = "2019-05-01"
+	// contributorRole is the built-in role allowing the workflow to start the scheduled container group
+	contributorRole = "b24988ac-6180-42a0-ab88-20f7382dd24c"
+	// roleAssignmentRetries is the number of attempts to assign a role to a workflow identity, which takes some
+	// time to be replicated once created
+	roleAssignmentRetries = 10
+)
+
+// deploySchedules deploys each scheduled service as a container group that terminates once done, started by a
+// Logic App workflow with a recurrence trigger. Schedules of services removed from the project are deleted.
+func (cs *aciComposeService) deploySchedules(ctx context.Context, project *types.Project, projectTags map[string]string) error {
+	w := progress.ContextWriter(ctx)
+	keep := map[string]bool{}
+	scheduled := &types.Project{Name: project.Name}
+	for _, service := range project.Services {
+		if compose.IsScheduled(service) {
+			keep[standaloneProject(project, service).Name] = true
+			scheduled.Services = append(scheduled.Services, service)
+		}
+	}
+	// scheduled services are independent container groups and workflows, deployed concurrently
+	err := compose.ForEachService(ctx, scheduled, func(ctx context.Context, service *types.ServiceConfig) error {
+		schedule, err := compose.ServiceSchedule(*service)
+		if err != nil {
+			return err
+		}
+		recurrence, err := convert.ToRecurrence(*schedule)
+		if err != nil {
+			return err
+		}
+		scheduledProject := standaloneProject(project, *service)
+		w.Event(progress.Event{ID: service.Name, Status: progress.Working, StatusText: "Scheduling"})
+
+		diagnostics, err := cs.groupDiagnostics(ctx, project.Name, scheduledProject)
+		if err != nil {
+			return err
+		}
+		groupDefinition, err := convert.ToContainerGroup(ctx, cs.ctx, scheduledProject, cs.storageLogin)
+		if err != nil {
+			return err
+		}
+		groupDefinition.Diagnostics = diagnostics
+		tags := *to.StringMapPtr(projectTags)
+		tags[compose.ProjectTag] = to.StringPtr(project.Name)
+		tags[scheduleTag] = to.StringPtr(service.Name)
+		addTags(&groupDefinition, tags)
+		if err := createOrUpdateACIContainers(ctx, cs.ctx, groupDefinition); err != nil {
+			return err
+		}
+		groupID := cs.containerGroupID(scheduledProject.Name)
+		principalID, err := cs.putScheduleWorkflow(ctx, scheduledProject.Name, groupID, recurrence, tags)
+		if err != nil {
+			return err
+		}
+		if err := cs.assignStartRole(ctx, groupID, principalID); err != nil {
+			return err
+		}
+		w.Event(progress.Event{ID: service.Name, Status: progress.Done, StatusText: fmt.Sprintf("Scheduled %s", schedule)})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	_, err = cs.removeSchedules(ctx, project.Name, keep)
+	return err
+}
+
+// removeSchedules deletes the workflows, role assignments and container groups of the project scheduled services
+// which are not kept, and returns the number of schedules removed
+func (cs *aciComposeService) removeSchedules(ctx context.Context, project string, keep map[string]bool) (int, error) {
+	workflowsClient, err := login.NewWorkflowsClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
+	if err != nil {
+		return 0, err
+	}
+	roleAssignmentsClient, err := login.NewRoleAssignmentsClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
+	if err != nil {
+		return 0, err
+	}
+	page, err := workflowsClient.ListByResourceGroup(ctx, cs.ctx.ResourceGroup, nil, "")
+	if isStatus(err, http.StatusConflict) {
+		// Logic Apps provider isn't registered on the subscription, nothing has been scheduled
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	var names []string
+	for page.NotDone() {
+		for _, workflow := range page.Values() {
+			name := to.String(workflow.Name)
+			if _, ok := workflow.Tags[scheduleTag]; !ok || to.String(workflow.Tags[compose.ProjectTag]) != project || keep[name] {
+				continue
+			}
+			names = append(names, name)
+		}
+		if err := page.NextWithContext(ctx); err != nil {
+			return 0, err
+		}
+	}
+	// schedules are removed in parallel, the rate limit of the clients spacing the calls of large projects
+	eg, ctx := errgroup.WithContext(ctx)
+	for _, name := range names {
+		name := name
+		eg.Go(func() error {
+			groupID := cs.containerGroupID(name)
+			if _, err := roleAssignmentsClient.Delete(ctx, groupID, roleAssignmentName(groupID)); err != nil && !isNotFound(err) {
+				return err
+			}
+			if _, err := workflowsClient.Delete(ctx, cs.ctx.ResourceGroup, name); err != nil && !isNotFound(err) {
+				return err
+			}
+			_, err := deleteACIContainerGroup(ctx, cs.ctx, name)
+			return err
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return 0, err
+	}
+	return len(names), nil
+}
+
+// putScheduleWorkflow creates or updates the workflow starting the container group on schedule, and returns the
+// principal ID of its managed identity. The workflows SDK doesn't support managed identities, so the request is
+// sent as is.
+func (cs *aciComposeService) putScheduleWorkflow(ctx context.Context, name string, groupID string, recurrence convert.Recurrence, tags map[string]*string) (string, error) {
+	workflowsClient, err := login.NewWorkflowsClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
+	if err != nil {
+		return "", err
+	}
+	body := map[string]interface{}{
+		"location": cs.ctx.Location,
+		"tags":     tags,
+		"identity": map[string]string{"type": "SystemAssigned"},
+		"properties": map[string]interface{}{
+			"state":      "Enabled",
+			"definition": workflowDefinition(groupID, recurrence),
+		},
+	}
+	req, err := autorest.Prepare((&http.Request{}).WithContext(ctx),
+		autorest.AsContentType("application/json; charset=utf-8"),
+		autorest.AsPut(),
+		autorest.WithBaseURL(workflowsClient.BaseURI),
+		autorest.WithPathParameters("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Logic/workflows/{workflowName}", map[string]interface{}{
+			"subscriptionId":    autorest.Encode("path", cs.ctx.SubscriptionID),
+			"resourceGroupName": autorest.Encode("path", cs.ctx.ResourceGroup),
+			"workflowName":      autorest.Encode("path", name),
+		}),
+		autorest.WithQueryParameters(map[string]interface{}{"api-version": workflowsAPIVersion}),
+		autorest.WithJSON(body))
+	if err != nil {
+		return "", err
+	}
+	resp, err := workflowsClient.Send(req, azure.DoRetryWithRegistration(workflowsClient.Client))
+	if err != nil {
+		return "", err
+	}
+	var result struct {
+		Identity struct {
+			PrincipalID string `json:"principalId"`
+		} `json:"identity"`
+	}
+	err = autorest.Respond(resp,
+		workflowsClient.ByInspecting(),
+		azure.WithErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
+		autorest.ByUnmarshallingJSON(&result),
+		autorest.ByClosing())
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot deploy schedule workflow %q", name)
+	}
+	return result.Identity.PrincipalID, nil
+}
+
+// workflowDefinition is a Logic App workflow starting the container group on each recurrence
+func workflowDefinition(groupID string, recurrence convert.Recurrence) map[string]interface{} {
+	return map[string]interface{}{
+		"$schema":        "https://schema.management.azure.com/providers/Microsoft.Logic/schemas/2016-06-01/workflowdefinition.json#",
+		"contentVersion": "1.0.0.0",
+		"triggers": map[string]interface{}{
+			"Recurrence": map[string]interface{}{
+				"type":       "Recurrence",
+				"recurrence": recurrence,
+			},
+		},
+		"actions": map[string]interface{}{
+			"Start": map[string]interface{}{
+				"type": "Http",
+				"inputs": map[string]interface{}{
+					"method": "POST",
+					"uri":    fmt.Sprintf("%s%s/start?api-version=2018-10-01", strings.TrimSuffix(azure.PublicCloud.ResourceManagerEndpoint, "/"), groupID),
+					"authentication": map[string]string{
+						"type":     "ManagedServiceIdentity",
+						"audience": azure.PublicCloud.ResourceManagerEndpoint,
+					},
+				},
+			},
+		},
+	}
+}
+
+// assignStartRole allows the workflow identity to start the container group
+func (cs *aciComposeService) assignStartRole(ctx context.Context, groupID string, principalID string) error {
+	roleAssignmentsClient, err := login.NewRoleAssignmentsClient(cs.ctx.SubscriptionID, cs.ctx.Operations())
+	if err != nil {
+		return err
+	}
+	parameters := authorization.RoleAssignmentCreateParameters{
+		Properties: &authorization.RoleAssignmentProperties{
+			RoleDefinitionID: to.StringPtr(fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Authorization/roleDefinitions/%s", cs.ctx.SubscriptionID, contributorRole)),
+			PrincipalID:      to.StringPtr(principalID),
+		},
+	}
+	for i := 0; ; i++ {
+		_, err = roleAssignmentsClient.Create(ctx, groupID, roleAssignmentName(groupID), parameters)
+		switch {
+		case err == nil, isStatus(err, http.StatusConflict):
+			return nil
+		case !isStatus(err, http.StatusBadRequest) || i == roleAssignmentRetries:
+			// new identities are reported as not found until they are replicated
+			return errors.Wrapf(err, "cannot allow schedule workflow to start container group %q", groupID)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(cs.ctx.Operations().PollingInterval):
+		}
+	}
+}
+
+func (cs *aciComposeService) containerGroupID(name string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ContainerInstance/containerGroups/%s", cs.ctx.SubscriptionID, cs.ctx.ResourceGroup, name)
+}
+
+// roleAssignmentName is a stable GUID for the role assignment on a container group, so that updates don't
+// duplicate it
+func roleAssignmentName(groupID string) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(groupID)).String()
+}
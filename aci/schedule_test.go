@@ -0,0 +1,41 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/aci/convert"
+)
+
+func TestWorkflowDefinition(t *testing.T) {
+	groupID := "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerInstance/containerGroups/shop-backup"
+	definition := workflowDefinition(groupID, convert.Recurrence{Frequency: "Day", Interval: 1})
+
+	trigger := definition["triggers"].(map[string]interface{})["Recurrence"].(map[string]interface{})
+	assert.Equal(t, trigger["recurrence"].(convert.Recurrence).Frequency, "Day")
+	inputs := definition["actions"].(map[string]interface{})["Start"].(map[string]interface{})["inputs"].(map[string]interface{})
+	assert.Equal(t, inputs["method"], "POST")
+	assert.Equal(t, inputs["uri"], "https://management.azure.com"+groupID+"/start?api-version=2018-10-01")
+}
+
+func TestRoleAssignmentNameIsStable(t *testing.T) {
+	assert.Equal(t, roleAssignmentName("/group/a"), roleAssignmentName("/group/a"))
+	assert.Assert(t, roleAssignmentName("/group/a") != roleAssignmentName("/group/b"))
+}
@@ -0,0 +1,219 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/aci/convert"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/progress"
+)
+
+// updatePolicy is how the container group of a project is updated, from the deploy.update_config of its services.
+// ACI replaces the containers of a group in place, the services therefore share the strictest policy.
+type updatePolicy struct {
+	rollback bool
+	monitor  time.Duration
+}
+
+func groupUpdatePolicy(project *types.Project) (updatePolicy, error) {
+	policy := updatePolicy{}
+	for _, service := range project.Services {
+		if service.Deploy == nil || service.Deploy.UpdateConfig == nil {
+			continue
+		}
+		config := service.Deploy.UpdateConfig
+		switch config.Order {
+		case "", compose.UpdateOrderStopFirst:
+		case compose.UpdateOrderStartFirst:
+			return policy, errors.Wrapf(errdefs.ErrNotImplemented, "deploy.update_config.order %s for service %s, ACI replaces containers in place", config.Order, service.Name)
+		default:
+			return policy, errors.Wrapf(errdefs.ErrParsingFailed, "unsupported deploy.update_config.order %q for service %s", config.Order, service.Name)
+		}
+		switch config.FailureAction {
+		case "", compose.UpdateFailureContinue, compose.UpdateFailurePause:
+		case compose.UpdateFailureRollback:
+			policy.rollback = true
+		default:
+			return policy, errors.Wrapf(errdefs.ErrParsingFailed, "unsupported deploy.update_config.failure_action %q for service %s", config.FailureAction, service.Name)
+		}
+		if monitor := time.Duration(config.Monitor); monitor > policy.monitor {
+			policy.monitor = monitor
+		}
+	}
+	return policy, nil
+}
+
+// monitorGroup watches the containers of an updated group for the monitor duration, failing if one restarts or exits with an error
+func (cs *aciComposeService) monitorGroup(ctx context.Context, name string, monitor time.Duration) error {
+	w := progress.ContextWriter(ctx)
+	groupDisplay := "Group " + name
+	w.Event(progress.Event{ID: groupDisplay, Status: progress.Working, StatusText: "Monitoring"})
+	restarts := map[string]int32{}
+	deadline := time.Now().Add(monitor)
+	for {
+		group, err := getACIContainerGroup(ctx, cs.ctx, name)
+		if err != nil {
+			return err
+		}
+		if err := checkContainers(group, restarts); err != nil {
+			w.Event(progress.Event{ID: groupDisplay, Status: progress.Error, StatusText: "Update failed"})
+			return err
+		}
+		if !time.Now().Before(deadline) {
+			w.Event(progress.Event{ID: groupDisplay, Status: progress.Done, StatusText: "Healthy", Type: progress.ServiceHealthy})
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(cs.ctx.Operations().PollingInterval):
+		}
+	}
+}
+
+// checkContainers returns an error for a container which exited with an error or restarted since the first check,
+// restarts records the restart count of each container on the first check
+func checkContainers(group containerinstance.ContainerGroup, restarts map[string]int32) error {
+	if group.ContainerGroupProperties == nil || group.Containers == nil {
+		return nil
+	}
+	for _, container := range *group.Containers {
+		name := to.String(container.Name)
+		if container.InstanceView == nil {
+			continue
+		}
+		count := to.Int32(container.InstanceView.RestartCount)
+		if first, ok := restarts[name]; !ok {
+			restarts[name] = count
+		} else if count > first {
+			return fmt.Errorf("container %s restarted after the update", name)
+		}
+		if state := container.InstanceView.CurrentState; state != nil && to.String(state.State) == "Terminated" && to.Int32(state.ExitCode) != 0 {
+			return fmt.Errorf("container %s exited with code %d after the update", name, to.Int32(state.ExitCode))
+		}
+	}
+	return nil
+}
+
+// rollbackGroup deploys again the container group definition running before a failed update. The group is read again,
+// as the update may have recreated it with properties the previous definition can't be applied over.
+func (cs *aciComposeService) rollbackGroup(ctx context.Context, previous containerinstance.ContainerGroup, cause error) error {
+	current, err := getACIContainerGroup(ctx, cs.ctx, to.String(previous.Name))
+	if err != nil && !isNotFound(err) {
+		return errors.Wrapf(err, "update failed (%v), rollback failed", cause)
+	}
+	previous.InstanceView = nil
+	if previous.Containers != nil {
+		for i := range *previous.Containers {
+			(*previous.Containers)[i].InstanceView = nil
+		}
+	}
+	progress.ContextWriter(ctx).Event(progress.Event{ID: "Group " + to.String(previous.Name), Status: progress.Working, StatusText: "Rolling back"})
+	if _, err := cs.deployContainerGroup(ctx, current, previous); err != nil {
+		return errors.Wrapf(err, "update failed (%v), rollback failed", cause)
+	}
+	return errors.Wrap(cause, "update rolled back")
+}
+
+// deployContainerGroup deploys a container group definition over the existing group, when it has an ID. ACI updates a
+// group in place when its containers images, commands or environment change, only restarting the updated containers,
+// the group is deleted and created again when a property ACI can't update changes. It returns why it was recreated.
+func (cs *aciComposeService) deployContainerGroup(ctx context.Context, existing, definition containerinstance.ContainerGroup) (string, error) {
+	var reason string
+	if existing.ID != nil {
+		reason = recreateReason(existing, definition)
+	}
+	if reason != "" {
+		progress.ContextWriter(ctx).Event(progress.Event{
+			ID:         "Group " + to.String(definition.Name),
+			Status:     progress.Working,
+			StatusText: "Recreating, " + reason,
+		})
+		if _, err := deleteACIContainerGroup(ctx, cs.ctx, to.String(definition.Name)); err != nil {
+			return reason, err
+		}
+	}
+	return reason, createOrUpdateACIContainers(ctx, cs.ctx, definition)
+}
+
+// recreateReason returns the properties of the existing container group ACI can't update to the definition ones, or
+// an empty string when the group can be updated in place
+func recreateReason(existing, definition containerinstance.ContainerGroup) string {
+	if existing.ContainerGroupProperties == nil || definition.ContainerGroupProperties == nil {
+		return ""
+	}
+	if existing.OsType != definition.OsType {
+		return "OS type changed"
+	}
+	if existing.RestartPolicy != definition.RestartPolicy {
+		return "restart policy changed"
+	}
+	if to.String(existing.Tags[convert.ZoneTag]) != to.String(definition.Tags[convert.ZoneTag]) {
+		return "availability zone changed"
+	}
+	if to.String(existing.Tags[convert.SubnetTag]) != to.String(definition.Tags[convert.SubnetTag]) {
+		return "subnet changed"
+	}
+	if existing.IPAddress != nil && definition.IPAddress != nil && existing.IPAddress.Type != definition.IPAddress.Type {
+		return "IP address type changed"
+	}
+	if existing.Containers == nil || definition.Containers == nil {
+		return ""
+	}
+	deployed := map[string]string{}
+	for _, container := range *existing.Containers {
+		deployed[to.String(container.Name)] = resourcesSpec(container.Resources)
+	}
+	for _, container := range *definition.Containers {
+		if resources, ok := deployed[to.String(container.Name)]; ok && resources != resourcesSpec(container.Resources) {
+			return fmt.Sprintf("container %s resources changed", to.String(container.Name))
+		}
+	}
+	return ""
+}
+
+// resourcesSpec is the CPU, memory and GPU a container requests and is limited to
+func resourcesSpec(resources *containerinstance.ResourceRequirements) string {
+	if resources == nil {
+		return ""
+	}
+	spec := func(cpu, memory *float64, gpu *containerinstance.GpuResource) string {
+		s := fmt.Sprintf("cpu=%g memory=%g", to.Float64(cpu), to.Float64(memory))
+		if gpu != nil {
+			s += fmt.Sprintf(" gpu=%d/%s", to.Int32(gpu.Count), gpu.Sku)
+		}
+		return s
+	}
+	var requests, limits string
+	if r := resources.Requests; r != nil {
+		requests = spec(r.CPU, r.MemoryInGB, r.Gpu)
+	}
+	if l := resources.Limits; l != nil {
+		limits = spec(l.CPU, l.MemoryInGB, l.Gpu)
+	}
+	return requests + " limits " + limits
+}
@@ -0,0 +1,123 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/aci/convert"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestGroupUpdatePolicy(t *testing.T) {
+	project := &types.Project{
+		Services: []types.ServiceConfig{
+			{Name: "web", Deploy: &types.DeployConfig{UpdateConfig: &types.UpdateConfig{
+				Order:   "stop-first",
+				Monitor: types.Duration(30 * time.Second),
+			}}},
+			{Name: "api", Deploy: &types.DeployConfig{UpdateConfig: &types.UpdateConfig{
+				FailureAction: "rollback",
+				Monitor:       types.Duration(time.Minute),
+			}}},
+			{Name: "db"},
+		},
+	}
+	policy, err := groupUpdatePolicy(project)
+	assert.NilError(t, err)
+	assert.Equal(t, policy, updatePolicy{rollback: true, monitor: time.Minute})
+
+	project.Services[0].Deploy.UpdateConfig.Order = "start-first"
+	_, err = groupUpdatePolicy(project)
+	assert.Assert(t, errdefs.IsErrNotImplemented(err))
+}
+
+func TestCheckContainers(t *testing.T) {
+	group := func(restarts int32, state string, exitCode int32) containerinstance.ContainerGroup {
+		return containerinstance.ContainerGroup{
+			ContainerGroupProperties: &containerinstance.ContainerGroupProperties{
+				Containers: &[]containerinstance.Container{{
+					Name: to.StringPtr("web"),
+					ContainerProperties: &containerinstance.ContainerProperties{
+						InstanceView: &containerinstance.ContainerPropertiesInstanceView{
+							RestartCount: to.Int32Ptr(restarts),
+							CurrentState: &containerinstance.ContainerState{State: to.StringPtr(state), ExitCode: to.Int32Ptr(exitCode)},
+						},
+					},
+				}},
+			},
+		}
+	}
+	restarts := map[string]int32{}
+	assert.NilError(t, checkContainers(group(2, "Running", 0), restarts))
+	assert.NilError(t, checkContainers(group(2, "Running", 0), restarts))
+	assert.ErrorContains(t, checkContainers(group(3, "Running", 0), restarts), "container web restarted after the update")
+	assert.ErrorContains(t, checkContainers(group(2, "Terminated", 1), restarts), "container web exited with code 1 after the update")
+}
+
+func TestRecreateReason(t *testing.T) {
+	group := func(cpu float64, policy containerinstance.ContainerGroupRestartPolicy, image string) containerinstance.ContainerGroup {
+		return containerinstance.ContainerGroup{
+			ContainerGroupProperties: &containerinstance.ContainerGroupProperties{
+				OsType:        containerinstance.Linux,
+				RestartPolicy: policy,
+				Containers: &[]containerinstance.Container{{
+					Name: to.StringPtr("web"),
+					ContainerProperties: &containerinstance.ContainerProperties{
+						Image: to.StringPtr(image),
+						Resources: &containerinstance.ResourceRequirements{
+							Requests: &containerinstance.ResourceRequests{CPU: to.Float64Ptr(cpu), MemoryInGB: to.Float64Ptr(1)},
+						},
+					},
+				}},
+			},
+		}
+	}
+	existing := group(1, containerinstance.Always, "nginx:1.19")
+
+	assert.Equal(t, recreateReason(existing, group(1, containerinstance.Always, "nginx:1.20")), "")
+	assert.Equal(t, recreateReason(existing, group(2, containerinstance.Always, "nginx:1.19")), "container web resources changed")
+	assert.Equal(t, recreateReason(existing, group(1, containerinstance.OnFailure, "nginx:1.19")), "restart policy changed")
+
+	zoned := group(1, containerinstance.Always, "nginx:1.19")
+	zoned.Tags = map[string]*string{convert.ZoneTag: to.StringPtr("2")}
+	assert.Equal(t, recreateReason(existing, zoned), "availability zone changed")
+
+	private := group(1, containerinstance.Always, "nginx:1.19")
+	private.Tags = map[string]*string{convert.SubnetTag: to.StringPtr("/subscriptions/123/subnets/apps")}
+	assert.Equal(t, recreateReason(existing, private), "subnet changed")
+
+	public := group(1, containerinstance.Always, "nginx:1.19")
+	public.IPAddress = &containerinstance.IPAddress{Type: containerinstance.Public}
+	internal := group(1, containerinstance.Always, "nginx:1.19")
+	internal.IPAddress = &containerinstance.IPAddress{Type: containerinstance.Private}
+	assert.Equal(t, recreateReason(public, internal), "IP address type changed")
+
+	added := group(1, containerinstance.Always, "nginx:1.19")
+	containers := append(*added.Containers, containerinstance.Container{
+		Name:                to.StringPtr("api"),
+		ContainerProperties: &containerinstance.ContainerProperties{Image: to.StringPtr("api")},
+	})
+	added.Containers = &containers
+	assert.Equal(t, recreateReason(existing, added), "")
+}
@@ -39,7 +39,7 @@ type aciVolumeService struct {
 }
 
 func (cs *aciVolumeService) List(ctx context.Context) ([]volumes.Volume, error) {
-	accountClient, err := login.NewStorageAccountsClient(cs.aciContext.SubscriptionID)
+	accountClient, err := login.NewStorageAccountsClient(cs.aciContext.SubscriptionID, cs.aciContext.Operations())
 	if err != nil {
 		return nil, err
 	}
@@ -48,7 +48,7 @@ func (cs *aciVolumeService) List(ctx context.Context) ([]volumes.Volume, error)
 		return nil, err
 	}
 	accounts := result.Value
-	fileShareClient, err := login.NewFileShareClient(cs.aciContext.SubscriptionID)
+	fileShareClient, err := login.NewFileShareClient(cs.aciContext.SubscriptionID, cs.aciContext.Operations())
 	if err != nil {
 		return nil, err
 	}
@@ -84,7 +84,7 @@ func (cs *aciVolumeService) Create(ctx context.Context, name string, options int
 	}
 	w := progress.ContextWriter(ctx)
 	w.Event(event(opts.Account, progress.Working, "Validating"))
-	accountClient, err := login.NewStorageAccountsClient(cs.aciContext.SubscriptionID)
+	accountClient, err := login.NewStorageAccountsClient(cs.aciContext.SubscriptionID, cs.aciContext.Operations())
 	if err != nil {
 		return volumes.Volume{}, err
 	}
@@ -125,7 +125,7 @@ func (cs *aciVolumeService) Create(ctx context.Context, name string, options int
 		w.Event(event(opts.Account, progress.Done, "Created"))
 	}
 	w.Event(event(name, progress.Working, "Creating"))
-	fileShareClient, err := login.NewFileShareClient(cs.aciContext.SubscriptionID)
+	fileShareClient, err := login.NewFileShareClient(cs.aciContext.SubscriptionID, cs.aciContext.Operations())
 	if err != nil {
 		return volumes.Volume{}, err
 	}
@@ -172,7 +172,7 @@ func (cs *aciVolumeService) Delete(ctx context.Context, id string, options inter
 	storageAccount := tokens[0]
 	fileshare := tokens[1]
 
-	fileShareClient, err := login.NewFileShareClient(cs.aciContext.SubscriptionID)
+	fileShareClient, err := login.NewFileShareClient(cs.aciContext.SubscriptionID, cs.aciContext.Operations())
 	if err != nil {
 		return err
 	}
@@ -182,7 +182,7 @@ func (cs *aciVolumeService) Delete(ctx context.Context, id string, options inter
 	}
 	fileshares := fileShareItemsPage.Values()
 	if len(fileshares) == 1 && *fileshares[0].Name == fileshare {
-		storageAccountsClient, err := login.NewStorageAccountsClient(cs.aciContext.SubscriptionID)
+		storageAccountsClient, err := login.NewStorageAccountsClient(cs.aciContext.SubscriptionID, cs.aciContext.Operations())
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,179 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package annotations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const (
+	// DatadogAPIKeyEnv is the environment variable holding the Datadog API key
+	DatadogAPIKeyEnv = "DD_API_KEY"
+	// GrafanaAPIKeyEnv is the environment variable holding the Grafana API token
+	GrafanaAPIKeyEnv = "GRAFANA_API_KEY"
+
+	defaultDatadogSite = "datadoghq.com"
+)
+
+var (
+	// scheme of the monitoring APIs, only changed by tests
+	scheme     = "https"
+	httpClient = &http.Client{Timeout: 10 * time.Second}
+)
+
+// Deployment describes a deployment annotating dashboards
+type Deployment struct {
+	Project string
+	Context string
+	// Version is an optional version set by the user, such as a git revision
+	Version string
+	// Images are the images deployed by each service, pinned to their digest when resolved
+	Images map[string]string
+	Time   time.Time
+}
+
+// NewDeployment describes the deployment of a project
+func NewDeployment(project *types.Project, contextName string, version string) Deployment {
+	images := map[string]string{}
+	for _, s := range project.Services {
+		images[s.Name] = s.Image
+	}
+	return Deployment{
+		Project: project.Name,
+		Context: contextName,
+		Version: version,
+		Images:  images,
+		Time:    time.Now(),
+	}
+}
+
+func (d Deployment) title() string {
+	if d.Version != "" {
+		return fmt.Sprintf("Deployed %s %s", d.Project, d.Version)
+	}
+	return fmt.Sprintf("Deployed %s", d.Project)
+}
+
+func (d Deployment) text() string {
+	var services []string
+	for name := range d.Images {
+		services = append(services, name)
+	}
+	sort.Strings(services)
+	lines := []string{fmt.Sprintf("Project %s deployed on context %s", d.Project, d.Context)}
+	for _, name := range services {
+		lines = append(lines, fmt.Sprintf("%s: %s", name, d.Images[name]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (d Deployment) tags() []string {
+	tags := []string{"compose-project:" + d.Project, "compose-context:" + d.Context}
+	if d.Version != "" {
+		tags = append(tags, "version:"+d.Version)
+	}
+	return tags
+}
+
+// Publish posts the deployment to the Datadog and Grafana targets. CloudWatch targets are published by the ECS backend,
+// which holds the AWS credentials.
+func Publish(ctx context.Context, targets []string, d Deployment) error {
+	for _, target := range targets {
+		kind, host, err := store.ParseAnnotation(target)
+		if err != nil {
+			return err
+		}
+		switch kind {
+		case store.DatadogAnnotation:
+			err = publishDatadog(ctx, host, d)
+		case store.GrafanaAnnotation:
+			err = publishGrafana(ctx, host, d)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "annotating %s", target)
+		}
+	}
+	return nil
+}
+
+func publishDatadog(ctx context.Context, site string, d Deployment) error {
+	key := os.Getenv(DatadogAPIKeyEnv)
+	if key == "" {
+		return errors.Wrapf(errdefs.ErrLoginRequired, "set the %s environment variable", DatadogAPIKeyEnv)
+	}
+	if site == "" {
+		site = defaultDatadogSite
+	}
+	return post(ctx, fmt.Sprintf("%s://api.%s/api/v1/events", scheme, site), map[string]string{"DD-API-KEY": key}, map[string]interface{}{
+		"title":         d.title(),
+		"text":          d.text(),
+		"tags":          d.tags(),
+		"alert_type":    "info",
+		"source_type":   "docker",
+		"date_happened": d.Time.Unix(),
+	})
+}
+
+func publishGrafana(ctx context.Context, host string, d Deployment) error {
+	key := os.Getenv(GrafanaAPIKeyEnv)
+	if key == "" {
+		return errors.Wrapf(errdefs.ErrLoginRequired, "set the %s environment variable", GrafanaAPIKeyEnv)
+	}
+	return post(ctx, fmt.Sprintf("%s://%s/api/annotations", scheme, host), map[string]string{"Authorization": "Bearer " + key}, map[string]interface{}{
+		"time": d.Time.UnixNano() / int64(time.Millisecond),
+		"tags": d.tags(),
+		"text": d.title() + "\n" + d.text(),
+	})
+}
+
+func post(ctx context.Context, url string, headers map[string]string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close() // nolint:errcheck
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("%s returned status %d", url, resp.StatusCode)
+	}
+	return nil
+}
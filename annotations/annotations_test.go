@@ -0,0 +1,67 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package annotations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestPublishGrafana(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.URL.Path, "/grafana/api/annotations")
+		assert.Equal(t, r.Header.Get("Authorization"), "Bearer secret")
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+	scheme = "http"
+	defer func() { scheme = "https" }()
+
+	d := Deployment{
+		Project: "shop",
+		Context: "prod",
+		Version: "31a4989",
+		Images:  map[string]string{"web": "nginx@sha256:42", "db": "postgres"},
+		Time:    time.Unix(1600000000, 0),
+	}
+	target := "grafana://" + strings.TrimPrefix(server.URL, "http://") + "/grafana"
+
+	os.Unsetenv(GrafanaAPIKeyEnv) // nolint:errcheck
+	err := Publish(context.Background(), []string{target}, d)
+	assert.Assert(t, errors.Is(err, errdefs.ErrLoginRequired))
+
+	os.Setenv(GrafanaAPIKeyEnv, "secret") // nolint:errcheck
+	defer os.Unsetenv(GrafanaAPIKeyEnv)   // nolint:errcheck
+	assert.NilError(t, Publish(context.Background(), []string{target, "cloudwatch"}, d))
+	assert.DeepEqual(t, received, map[string]interface{}{
+		"time": float64(1600000000000),
+		"tags": []interface{}{"compose-project:shop", "compose-context:prod", "version:31a4989"},
+		"text": "Deployed shop 31a4989\nProject shop deployed on context prod\ndb: postgres\nweb: nginx@sha256:42",
+	})
+}
@@ -21,12 +21,14 @@ import (
 
 	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/api/containers"
+	"github.com/docker/compose-cli/api/images"
 	"github.com/docker/compose-cli/api/secrets"
 	"github.com/docker/compose-cli/api/volumes"
 	"github.com/docker/compose-cli/backend"
 	apicontext "github.com/docker/compose-cli/context"
 	"github.com/docker/compose-cli/context/cloud"
 	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/tracing"
 )
 
 // New returns a backend client associated with current context
@@ -39,7 +41,9 @@ func New(ctx context.Context) (*Client, error) {
 		return nil, err
 	}
 
+	ctx, span := tracing.Start(ctx, "client.New", backendKey.String(cc.Type()))
 	service, err := backend.Get(ctx, cc.Type())
+	tracing.End(span, err)
 	if err != nil {
 		return nil, err
 	}
@@ -72,10 +76,31 @@ func (c *Client) ContextType() string {
 	return c.backendType
 }
 
+// Capabilities lists the services the backend of the current context implements
+func (c *Client) Capabilities() []string {
+	var capabilities []string
+	if c.bs.ComposeService() != nil {
+		capabilities = append(capabilities, "compose")
+	}
+	if c.bs.ContainerService() != nil {
+		capabilities = append(capabilities, "containers")
+	}
+	if c.bs.SecretsService() != nil {
+		capabilities = append(capabilities, "secrets")
+	}
+	if c.bs.VolumeService() != nil {
+		capabilities = append(capabilities, "volumes")
+	}
+	if c.bs.ImageService() != nil {
+		capabilities = append(capabilities, "images")
+	}
+	return capabilities
+}
+
 // ContainerService returns the backend service for the current context
 func (c *Client) ContainerService() containers.Service {
 	if cs := c.bs.ContainerService(); cs != nil {
-		return cs
+		return &tracedContainerService{backend: c.backendType, service: cs}
 	}
 
 	return &containerService{}
@@ -84,7 +109,7 @@ func (c *Client) ContainerService() containers.Service {
 // ComposeService returns the backend service for the current context
 func (c *Client) ComposeService() compose.Service {
 	if cs := c.bs.ComposeService(); cs != nil {
-		return cs
+		return &tracedComposeService{backend: c.backendType, service: cs}
 	}
 
 	return &composeService{}
@@ -107,3 +132,12 @@ func (c *Client) VolumeService() volumes.Service {
 
 	return &volumeService{}
 }
+
+// ImageService returns the backend service for the current context
+func (c *Client) ImageService() images.Service {
+	if is := c.bs.ImageService(); is != nil {
+		return is
+	}
+
+	return &imageService{}
+}
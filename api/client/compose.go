@@ -18,7 +18,7 @@ package client
 
 import (
 	"context"
-	"io"
+	"time"
 
 	"github.com/compose-spec/compose-go/types"
 
@@ -30,17 +30,17 @@ type composeService struct {
 }
 
 // Up executes the equivalent to a `compose up`
-func (c *composeService) Up(context.Context, *types.Project) error {
+func (c *composeService) Up(context.Context, *types.Project, compose.UpOptions) error {
 	return errdefs.ErrNotImplemented
 }
 
 // Down executes the equivalent to a `compose down`
-func (c *composeService) Down(context.Context, string) error {
+func (c *composeService) Down(context.Context, string, compose.DownOptions) error {
 	return errdefs.ErrNotImplemented
 }
 
 // Logs executes the equivalent to a `compose logs`
-func (c *composeService) Logs(context.Context, string, io.Writer) error {
+func (c *composeService) Logs(context.Context, string, compose.LogConsumer, compose.LogOptions) error {
 	return errdefs.ErrNotImplemented
 }
 
@@ -58,3 +58,58 @@ func (c *composeService) List(context.Context, string) ([]compose.Stack, error)
 func (c *composeService) Convert(context.Context, *types.Project) ([]byte, error) {
 	return nil, errdefs.ErrNotImplemented
 }
+
+// Prune deletes resources labelled with a project which isn't deployed anymore
+func (c *composeService) Prune(context.Context, compose.PruneOptions) ([]compose.OrphanResource, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+// Estimate computes the cost of running project services, based on the backend pricing
+func (c *composeService) Estimate(context.Context, *types.Project) ([]compose.CostEstimate, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+// PortForward tunnels a local port to a port of a service container
+func (c *composeService) PortForward(context.Context, string, string, uint32, uint32) error {
+	return errdefs.ErrNotImplemented
+}
+
+// Discovery describes how project services resolve each other on the backend
+func (c *composeService) Discovery(context.Context, *types.Project) ([]compose.ServiceDiscovery, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+// Exposure describes from where service ports can be reached once deployed on the backend
+func (c *composeService) Exposure(context.Context, *types.Project) ([]compose.PortExposure, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+// Diff compares the specification of the deployed application with the one generated from the compose file
+func (c *composeService) Diff(context.Context, *types.Project) (compose.ProjectDiff, error) {
+	return compose.ProjectDiff{}, errdefs.ErrNotImplemented
+}
+
+// Import generates a compose project from existing backend resources, and labels them to be managed by `compose up`
+func (c *composeService) Import(context.Context, compose.ImportOptions) (*types.Project, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+// Lock acquires the project lock, waiting up to timeout for another command to release it
+func (c *composeService) Lock(context.Context, string, time.Duration) (func() error, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+// Unlock forcibly releases the project lock
+func (c *composeService) Unlock(context.Context, string) error {
+	return errdefs.ErrNotImplemented
+}
+
+// Outputs returns the endpoints and resource identifiers of a deployed project
+func (c *composeService) Outputs(context.Context, string) ([]compose.StackOutput, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+// RunJob runs a job of the deployed project as a one-off task
+func (c *composeService) RunJob(context.Context, *types.Project, string) error {
+	return errdefs.ErrNotImplemented
+}
@@ -69,3 +69,8 @@ func (c *containerService) Delete(context.Context, string, containers.DeleteRequ
 func (c *containerService) Inspect(context.Context, string) (containers.Container, error) {
 	return containers.Container{}, errdefs.ErrNotImplemented
 }
+
+// PortForward tunnels a local port to a container port
+func (c *containerService) PortForward(context.Context, string, uint32, uint32) error {
+	return errdefs.ErrNotImplemented
+}
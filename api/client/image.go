@@ -0,0 +1,39 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	"github.com/docker/compose-cli/api/images"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+type imageService struct {
+}
+
+func (c *imageService) List(ctx context.Context, options images.ListOptions) ([]images.Image, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (c *imageService) Remove(ctx context.Context, image string, options images.RemoveOptions) ([]string, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (c *imageService) Pull(ctx context.Context, image string, options images.PullOptions) error {
+	return errdefs.ErrNotImplemented
+}
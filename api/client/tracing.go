@@ -0,0 +1,223 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"go.opentelemetry.io/otel/label"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/api/containers"
+	"github.com/docker/compose-cli/tracing"
+)
+
+var (
+	backendKey   = label.Key("compose.backend")
+	projectKey   = label.Key("compose.project")
+	containerKey = label.Key("container.id")
+)
+
+// tracedComposeService records a span for each call to the backend compose service
+type tracedComposeService struct {
+	backend string
+	service compose.Service
+}
+
+func (t *tracedComposeService) start(ctx context.Context, operation string, project string) (context.Context, func(error)) {
+	ctx, span := tracing.Start(ctx, "compose."+operation, backendKey.String(t.backend), projectKey.String(project))
+	return ctx, func(err error) {
+		tracing.End(span, err)
+	}
+}
+
+func (t *tracedComposeService) Up(ctx context.Context, project *types.Project, options compose.UpOptions) (err error) {
+	ctx, end := t.start(ctx, "Up", project.Name)
+	defer func() { end(err) }()
+	return t.service.Up(ctx, project, options)
+}
+
+func (t *tracedComposeService) Down(ctx context.Context, projectName string, options compose.DownOptions) (err error) {
+	ctx, end := t.start(ctx, "Down", projectName)
+	defer func() { end(err) }()
+	return t.service.Down(ctx, projectName, options)
+}
+
+func (t *tracedComposeService) Logs(ctx context.Context, projectName string, consumer compose.LogConsumer, options compose.LogOptions) (err error) {
+	ctx, end := t.start(ctx, "Logs", projectName)
+	defer func() { end(err) }()
+	return t.service.Logs(ctx, projectName, consumer, options)
+}
+
+func (t *tracedComposeService) Ps(ctx context.Context, projectName string) (status []compose.ServiceStatus, err error) {
+	ctx, end := t.start(ctx, "Ps", projectName)
+	defer func() { end(err) }()
+	return t.service.Ps(ctx, projectName)
+}
+
+func (t *tracedComposeService) List(ctx context.Context, projectName string) (stacks []compose.Stack, err error) {
+	ctx, end := t.start(ctx, "List", projectName)
+	defer func() { end(err) }()
+	return t.service.List(ctx, projectName)
+}
+
+func (t *tracedComposeService) Convert(ctx context.Context, project *types.Project) (data []byte, err error) {
+	ctx, end := t.start(ctx, "Convert", project.Name)
+	defer func() { end(err) }()
+	return t.service.Convert(ctx, project)
+}
+
+func (t *tracedComposeService) Prune(ctx context.Context, options compose.PruneOptions) (orphans []compose.OrphanResource, err error) {
+	ctx, end := t.start(ctx, "Prune", "")
+	defer func() { end(err) }()
+	return t.service.Prune(ctx, options)
+}
+
+func (t *tracedComposeService) Estimate(ctx context.Context, project *types.Project) (estimates []compose.CostEstimate, err error) {
+	ctx, end := t.start(ctx, "Estimate", project.Name)
+	defer func() { end(err) }()
+	return t.service.Estimate(ctx, project)
+}
+
+func (t *tracedComposeService) PortForward(ctx context.Context, projectName string, service string, localPort, remotePort uint32) (err error) {
+	ctx, end := t.start(ctx, "PortForward", projectName)
+	defer func() { end(err) }()
+	return t.service.PortForward(ctx, projectName, service, localPort, remotePort)
+}
+
+func (t *tracedComposeService) Discovery(ctx context.Context, project *types.Project) (discovery []compose.ServiceDiscovery, err error) {
+	ctx, end := t.start(ctx, "Discovery", project.Name)
+	defer func() { end(err) }()
+	return t.service.Discovery(ctx, project)
+}
+
+func (t *tracedComposeService) Exposure(ctx context.Context, project *types.Project) (exposure []compose.PortExposure, err error) {
+	ctx, end := t.start(ctx, "Exposure", project.Name)
+	defer func() { end(err) }()
+	return t.service.Exposure(ctx, project)
+}
+
+func (t *tracedComposeService) Diff(ctx context.Context, project *types.Project) (diff compose.ProjectDiff, err error) {
+	ctx, end := t.start(ctx, "Diff", project.Name)
+	defer func() { end(err) }()
+	return t.service.Diff(ctx, project)
+}
+
+func (t *tracedComposeService) Import(ctx context.Context, options compose.ImportOptions) (project *types.Project, err error) {
+	ctx, end := t.start(ctx, "Import", options.Project)
+	defer func() { end(err) }()
+	return t.service.Import(ctx, options)
+}
+
+func (t *tracedComposeService) Lock(ctx context.Context, projectName string, timeout time.Duration) (release func() error, err error) {
+	ctx, end := t.start(ctx, "Lock", projectName)
+	defer func() { end(err) }()
+	return t.service.Lock(ctx, projectName, timeout)
+}
+
+func (t *tracedComposeService) Unlock(ctx context.Context, projectName string) (err error) {
+	ctx, end := t.start(ctx, "Unlock", projectName)
+	defer func() { end(err) }()
+	return t.service.Unlock(ctx, projectName)
+}
+
+func (t *tracedComposeService) Outputs(ctx context.Context, projectName string) (outputs []compose.StackOutput, err error) {
+	ctx, end := t.start(ctx, "Outputs", projectName)
+	defer func() { end(err) }()
+	return t.service.Outputs(ctx, projectName)
+}
+
+func (t *tracedComposeService) RunJob(ctx context.Context, project *types.Project, job string) (err error) {
+	ctx, end := t.start(ctx, "RunJob", project.Name)
+	defer func() { end(err) }()
+	return t.service.RunJob(ctx, project, job)
+}
+
+// tracedContainerService records a span for each call to the backend container service
+type tracedContainerService struct {
+	backend string
+	service containers.Service
+}
+
+func (t *tracedContainerService) start(ctx context.Context, operation string, container string) (context.Context, func(error)) {
+	ctx, span := tracing.Start(ctx, "containers."+operation, backendKey.String(t.backend), containerKey.String(container))
+	return ctx, func(err error) {
+		tracing.End(span, err)
+	}
+}
+
+func (t *tracedContainerService) List(ctx context.Context, all bool) (list []containers.Container, err error) {
+	ctx, end := t.start(ctx, "List", "")
+	defer func() { end(err) }()
+	return t.service.List(ctx, all)
+}
+
+func (t *tracedContainerService) Start(ctx context.Context, containerID string) (err error) {
+	ctx, end := t.start(ctx, "Start", containerID)
+	defer func() { end(err) }()
+	return t.service.Start(ctx, containerID)
+}
+
+func (t *tracedContainerService) Stop(ctx context.Context, containerID string, timeout *uint32) (err error) {
+	ctx, end := t.start(ctx, "Stop", containerID)
+	defer func() { end(err) }()
+	return t.service.Stop(ctx, containerID, timeout)
+}
+
+func (t *tracedContainerService) Kill(ctx context.Context, containerID string, signal string) (err error) {
+	ctx, end := t.start(ctx, "Kill", containerID)
+	defer func() { end(err) }()
+	return t.service.Kill(ctx, containerID, signal)
+}
+
+func (t *tracedContainerService) Run(ctx context.Context, config containers.ContainerConfig) (err error) {
+	ctx, end := t.start(ctx, "Run", config.ID)
+	defer func() { end(err) }()
+	return t.service.Run(ctx, config)
+}
+
+func (t *tracedContainerService) Exec(ctx context.Context, containerName string, request containers.ExecRequest) (err error) {
+	ctx, end := t.start(ctx, "Exec", containerName)
+	defer func() { end(err) }()
+	return t.service.Exec(ctx, containerName, request)
+}
+
+func (t *tracedContainerService) Logs(ctx context.Context, containerName string, request containers.LogsRequest) (err error) {
+	ctx, end := t.start(ctx, "Logs", containerName)
+	defer func() { end(err) }()
+	return t.service.Logs(ctx, containerName, request)
+}
+
+func (t *tracedContainerService) Delete(ctx context.Context, containerID string, request containers.DeleteRequest) (err error) {
+	ctx, end := t.start(ctx, "Delete", containerID)
+	defer func() { end(err) }()
+	return t.service.Delete(ctx, containerID, request)
+}
+
+func (t *tracedContainerService) Inspect(ctx context.Context, id string) (container containers.Container, err error) {
+	ctx, end := t.start(ctx, "Inspect", id)
+	defer func() { end(err) }()
+	return t.service.Inspect(ctx, id)
+}
+
+func (t *tracedContainerService) PortForward(ctx context.Context, containerID string, localPort, remotePort uint32) (err error) {
+	ctx, end := t.start(ctx, "PortForward", containerID)
+	defer func() { end(err) }()
+	return t.service.PortForward(ctx, containerID, localPort, remotePort)
+}
@@ -18,7 +18,7 @@ package compose
 
 import (
 	"context"
-	"io"
+	"time"
 
 	"github.com/compose-spec/compose-go/types"
 )
@@ -26,17 +26,207 @@ import (
 // Service manages a compose project
 type Service interface {
 	// Up executes the equivalent to a `compose up`
-	Up(ctx context.Context, project *types.Project) error
+	Up(ctx context.Context, project *types.Project, options UpOptions) error
 	// Down executes the equivalent to a `compose down`
-	Down(ctx context.Context, projectName string) error
+	Down(ctx context.Context, projectName string, options DownOptions) error
 	// Logs executes the equivalent to a `compose logs`
-	Logs(ctx context.Context, projectName string, w io.Writer) error
+	Logs(ctx context.Context, projectName string, consumer LogConsumer, options LogOptions) error
 	// Ps executes the equivalent to a `compose ps`
 	Ps(ctx context.Context, projectName string) ([]ServiceStatus, error)
 	// List executes the equivalent to a `docker stack ls`
 	List(ctx context.Context, projectName string) ([]Stack, error)
 	// Convert translate compose model into backend's native format
 	Convert(ctx context.Context, project *types.Project) ([]byte, error)
+	// Prune deletes resources labelled with a project which isn't deployed anymore
+	Prune(ctx context.Context, options PruneOptions) ([]OrphanResource, error)
+	// Estimate computes the cost of running project services, based on the backend pricing
+	Estimate(ctx context.Context, project *types.Project) ([]CostEstimate, error)
+	// PortForward tunnels a local port to a port of a service container until the context is done
+	PortForward(ctx context.Context, projectName string, service string, localPort, remotePort uint32) error
+	// Discovery describes how project services resolve each other on the backend
+	Discovery(ctx context.Context, project *types.Project) ([]ServiceDiscovery, error)
+	// Exposure describes from where service ports can be reached once deployed on the backend
+	Exposure(ctx context.Context, project *types.Project) ([]PortExposure, error)
+	// Diff compares the specification of the deployed application with the one generated from the compose file
+	Diff(ctx context.Context, project *types.Project) (ProjectDiff, error)
+	// Import generates a compose project from existing backend resources, and labels them to be managed by `compose up`
+	Import(ctx context.Context, options ImportOptions) (*types.Project, error)
+	// Lock acquires the project lock, waiting up to timeout for another command to release it. The returned function releases the lock
+	Lock(ctx context.Context, projectName string, timeout time.Duration) (func() error, error)
+	// Unlock forcibly releases the project lock, when the command holding it was interrupted
+	Unlock(ctx context.Context, projectName string) error
+	// Outputs returns the endpoints and resource identifiers of a deployed project
+	Outputs(ctx context.Context, projectName string) ([]StackOutput, error)
+	// RunJob runs a job of the deployed project as a one-off task and waits for it to complete
+	RunJob(ctx context.Context, project *types.Project, job string) error
+}
+
+// UpOptions group options of the Up API
+type UpOptions struct {
+	// ResolveImageDigests pins service images to the digest their tag resolves to at deployment time
+	ResolveImageDigests bool
+	// Push builds service images and pushes them to the backend's registry before deployment
+	Push bool
+	// Builder selects the builder used for services declaring a build section
+	Builder string
+	// Recreate sets the policy for services already deployed: RecreateDiverged, RecreateForce or RecreateNever
+	Recreate string
+	// OverrideBudget deploys the project even if it exceeds the context budget
+	OverrideBudget bool
+	// Tags are recorded on the deployed application, in addition to the ones set by the backend and the context tag
+	// policy
+	Tags map[string]string
+	// Resume waits for a deployment left in progress by an interrupted command to complete, and carries on from there
+	Resume bool
+	// CancelCleanup deletes or rolls back resources being deployed when the user cancels the command
+	CancelCleanup bool
+	// Regions deploys the project to each of these regions concurrently, instead of the context region
+	Regions []string
+}
+
+const (
+	// RecreateDiverged only redeploys services whose configuration changed since last deployment
+	RecreateDiverged = "diverged"
+	// RecreateForce redeploys all services, even if their configuration didn't change
+	RecreateForce = "force"
+	// RecreateNever keeps already deployed services as is, even if their configuration changed
+	RecreateNever = "never"
+)
+
+// DownOptions group options of the Down API
+type DownOptions struct {
+	// RemoveVolumes deletes volumes labelled with the project
+	RemoveVolumes bool
+	// RemoveImages removes images from the local engine: RemoveImagesLocal or RemoveImagesAll
+	RemoveImages string
+	// RemoveOrphans deletes resources labelled with the project which don't belong to the deployed application
+	RemoveOrphans bool
+}
+
+const (
+	// RemoveImagesLocal removes images built for the project
+	RemoveImagesLocal = "local"
+	// RemoveImagesAll removes images built for the project and images used by its services
+	RemoveImagesAll = "all"
+)
+
+// LogOptions group options of the Logs API
+type LogOptions struct {
+	// Export exports the project logs to the given destination, such as s3://bucket/prefix, instead of streaming them
+	Export string
+	// Previous only returns the logs of stopped containers, such as the ones of a crash looping service
+	Previous bool
+	// Since only returns the logs written after this time, if set
+	Since time.Time
+	// Until only returns the logs written before this time, if set, and stops following logs
+	Until time.Time
+}
+
+// Follow tells whether logs are streamed as they are written, rather than only returned up to now
+func (o LogOptions) Follow() bool {
+	return !o.Previous && o.Until.IsZero()
+}
+
+// LogConsumer processes the log lines of the project services
+type LogConsumer interface {
+	Log(event LogEvent)
+}
+
+// LogEvent is a line logged by a service container
+type LogEvent struct {
+	Service   string    `json:"service"`
+	Container string    `json:"container"`
+	Stream    string    `json:"stream,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Line      string    `json:"line"`
+}
+
+// PruneOptions group options of the Prune API
+type PruneOptions struct {
+	// DryRun only lists orphan resources, without deleting them
+	DryRun bool
+	// Resources restricts deletion to the orphan resources with these IDs, as listed by a dry run
+	Resources []string
+}
+
+// ImportOptions group options of the Import API
+type ImportOptions struct {
+	// Project is the name of the project resources are imported in, defaulting to a name derived from the resources
+	Project string
+	// Resources identify the backend resources to import, in the backend's own format
+	Resources []string
+}
+
+// OrphanResource is a backend resource labelled with a project which isn't deployed anymore
+type OrphanResource struct {
+	ID      string
+	Type    string
+	Project string
+}
+
+// HoursPerMonth is the average number of hours in a month, as used by cloud providers for monthly pricing
+const HoursPerMonth = 730
+
+// CostEstimate is the estimated cost of running a service
+type CostEstimate struct {
+	Service  string
+	Replicas int
+	CPUs     float64
+	MemoryGB float64
+	// Hourly is the cost of running all service replicas for an hour
+	Hourly   float64
+	Currency string
+}
+
+// Monthly returns the cost of running all service replicas for a month
+func (c CostEstimate) Monthly() float64 {
+	return c.Hourly * HoursPerMonth
+}
+
+// ServiceDiscovery describes how a service is reached by the other services of the project
+type ServiceDiscovery struct {
+	Service string
+	// Hostnames resolve to the service from the other containers of the project
+	Hostnames []string
+	// Address is what hostnames resolve to
+	Address string
+	// Ports are the ports the service can be reached on
+	Ports []uint32
+	// Mechanism is the backend feature providing name resolution
+	Mechanism string
+}
+
+// PortExposure describes from where a service port can be reached
+type PortExposure struct {
+	Service  string
+	Port     uint32
+	Protocol string
+	// Source is the origin of the traffic allowed to reach the port
+	Source string
+}
+
+// StackOutput is a value of a deployed application automation can rely on, such as an endpoint or a resource ID
+type StackOutput struct {
+	Name        string
+	Value       string
+	Description string `json:",omitempty"`
+}
+
+// ProjectDiff holds the deployed and local specifications of an application, in the backend's native format
+type ProjectDiff struct {
+	// Deployed is the specification of the application currently deployed
+	Deployed []byte
+	// Local is the specification generated from the compose file
+	Local []byte
+	// Drifts are the deployed resources modified outside of the CLI, as detected by the backend
+	Drifts []ResourceDrift
+}
+
+// ResourceDrift is a deployed resource which configuration doesn't match its specification anymore
+type ResourceDrift struct {
+	Resource string
+	Type     string
+	Status   string
 }
 
 // PortPublisher hold status about published port
@@ -55,6 +245,12 @@ type ServiceStatus struct {
 	Desired    int
 	Ports      []string
 	Publishers []PortPublisher
+	// Region is set by backends deploying projects to multiple regions
+	Region string
+	// Restarts counts the service containers restarted after they exited
+	Restarts int
+	// ExitCode is the exit code of the service container which exited last, if any
+	ExitCode *int
 }
 
 const (
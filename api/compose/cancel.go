@@ -0,0 +1,74 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// CanceledError is returned by backend operations interrupted by the user, describing what they left behind
+type CanceledError struct {
+	// Operation is the interrupted operation, e.g. "deployment"
+	Operation string
+	// Resources describe the resources left behind, which keep being created, updated or deleted by the cloud provider
+	Resources []string
+	// CleanedUp reports in-flight resources were deleted or rolled back
+	CleanedUp bool
+}
+
+func (e CanceledError) Error() string {
+	switch {
+	case e.CleanedUp:
+		return fmt.Sprintf("%s canceled, in-flight resources have been cleaned up", e.Operation)
+	case len(e.Resources) == 0:
+		return fmt.Sprintf("%s canceled", e.Operation)
+	default:
+		return fmt.Sprintf("%s canceled, resources left behind:\n  %s", e.Operation, strings.Join(e.Resources, "\n  "))
+	}
+}
+
+// Unwrap lets errdefs.IsErrCanceled recognize a CanceledError
+func (e CanceledError) Unwrap() error {
+	return errdefs.ErrCanceled
+}
+
+// Detach returns a context carrying the values of ctx, like the progress writer, but not its cancellation.
+// It allows cleaning up resources once the user canceled the command.
+func Detach(ctx context.Context) context.Context {
+	return detachedContext{ctx}
+}
+
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
@@ -0,0 +1,52 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestCanceledError(t *testing.T) {
+	var err error = CanceledError{Operation: "deployment", Resources: []string{`stack "test" (CREATE_IN_PROGRESS)`}}
+	assert.Assert(t, errdefs.IsErrCanceled(errors.Wrap(err, "up")))
+	assert.Equal(t, err.Error(), "deployment canceled, resources left behind:\n  stack \"test\" (CREATE_IN_PROGRESS)")
+
+	err = CanceledError{Operation: "deployment", CleanedUp: true}
+	assert.Equal(t, err.Error(), "deployment canceled, in-flight resources have been cleaned up")
+}
+
+type testKey struct{}
+
+func TestDetach(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), testKey{}, "value"))
+	cancel()
+
+	detached := Detach(ctx)
+	assert.NilError(t, detached.Err())
+	assert.Equal(t, detached.Value(testKey{}), "value")
+	select {
+	case <-detached.Done():
+		t.Fatal("detached context should not be done")
+	default:
+	}
+}
@@ -0,0 +1,39 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"path"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// ConfigTarget returns the path a config is mounted at in service containers, defaulting to /<config name>. As
+// configs are mounted by sharing their folder, targets must be absolute paths in a folder other than the root one.
+func ConfigTarget(config types.ServiceConfigObjConfig) (string, error) {
+	target := config.Target
+	if target == "" {
+		target = "/" + config.Source
+	}
+	if !path.IsAbs(target) || path.Dir(target) == "/" {
+		return "", errors.Wrapf(errdefs.ErrNotImplemented, "config %q: target %q must be an absolute path in a folder other than /", config.Source, target)
+	}
+	return target, nil
+}
@@ -0,0 +1,36 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestConfigTarget(t *testing.T) {
+	target, err := ConfigTarget(types.ServiceConfigObjConfig{Source: "nginx", Target: "/etc/nginx/nginx.conf"})
+	assert.NilError(t, err)
+	assert.Equal(t, target, "/etc/nginx/nginx.conf")
+
+	_, err = ConfigTarget(types.ServiceConfigObjConfig{Source: "nginx"})
+	assert.ErrorContains(t, err, `target "/nginx" must be an absolute path in a folder other than /`)
+
+	_, err = ConfigTarget(types.ServiceConfigObjConfig{Source: "nginx", Target: "conf/nginx.conf"})
+	assert.ErrorContains(t, err, "must be an absolute path")
+}
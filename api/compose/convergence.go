@@ -0,0 +1,224 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/docker/compose-cli/utils"
+)
+
+const (
+	// DefaultMaxConcurrency is the number of deployment steps run concurrently when none is set
+	DefaultMaxConcurrency = 8
+	// ParallelismEnvVar is the environment variable setting the number of deployment steps run concurrently
+	ParallelismEnvVar = "COMPOSE_CLI_PARALLELISM"
+)
+
+// MaxConcurrency returns the number of deployment steps run concurrently, set with ParallelismEnvVar.
+// These steps are the image digest pinning, registry credentials, image pushes, builds and storage keys lookups, and
+// the resources the backends manage one by one: ACI scheduled container groups and file shares, ECS forced
+// redeployments. The ECS stack and the ACI container group are each a single deployment, CloudFormation and Azure
+// creating their independent resources concurrently.
+func MaxConcurrency() int {
+	parallelism, err := strconv.Atoi(os.Getenv(ParallelismEnvVar))
+	if err != nil || parallelism <= 0 {
+		return DefaultMaxConcurrency
+	}
+	return parallelism
+}
+
+// Task is a single deployment step
+type Task func(ctx context.Context) error
+
+// Graph is a set of tasks with dependencies, run concurrently as soon as their dependencies completed
+type Graph struct {
+	vertices map[string]*vertex
+}
+
+type vertex struct {
+	task      Task
+	dependsOn []string
+}
+
+// NewGraph returns an empty graph
+func NewGraph() *Graph {
+	return &Graph{
+		vertices: map[string]*vertex{},
+	}
+}
+
+// Add declares a task, to be run once all the tasks it depends on completed
+func (g *Graph) Add(id string, task Task, dependsOn ...string) {
+	g.vertices[id] = &vertex{
+		task:      task,
+		dependsOn: dependsOn,
+	}
+}
+
+// Run executes the graph tasks with at most maxConcurrency tasks running at the same time, MaxConcurrency when not set.
+// It stops scheduling tasks on the first error, and returns it once running tasks completed.
+func (g *Graph) Run(ctx context.Context, maxConcurrency int) error {
+	if err := g.validate(); err != nil {
+		return err
+	}
+	if maxConcurrency <= 0 {
+		maxConcurrency = MaxConcurrency()
+	}
+
+	pending := map[string]int{}
+	dependents := map[string][]string{}
+	for id, v := range g.vertices {
+		pending[id] = len(v.dependsOn)
+		for _, dep := range v.dependsOn {
+			dependents[dep] = append(dependents[dep], id)
+		}
+	}
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	tokens := make(chan struct{}, maxConcurrency)
+	done := make(chan string, len(g.vertices))
+	start := func(id string) {
+		task := g.vertices[id].task
+		eg.Go(func() error {
+			select {
+			case tokens <- struct{}{}:
+			case <-egCtx.Done():
+				return egCtx.Err()
+			}
+			err := task(egCtx)
+			<-tokens
+			if err != nil {
+				return err
+			}
+			done <- id
+			return nil
+		})
+	}
+
+	for _, id := range g.roots() {
+		start(id)
+	}
+	for remaining := len(g.vertices); remaining > 0; remaining-- {
+		select {
+		case id := <-done:
+			for _, dependent := range dependents[id] {
+				pending[dependent]--
+				if pending[dependent] == 0 {
+					start(dependent)
+				}
+			}
+		case <-egCtx.Done():
+			if err := eg.Wait(); err != nil {
+				return err
+			}
+			return ctx.Err()
+		}
+	}
+	return eg.Wait()
+}
+
+// roots returns the tasks without dependencies, sorted for a predictable scheduling order
+func (g *Graph) roots() []string {
+	var roots []string
+	for id, v := range g.vertices {
+		if len(v.dependsOn) == 0 {
+			roots = append(roots, id)
+		}
+	}
+	sort.Strings(roots)
+	return roots
+}
+
+// validate checks all dependencies are declared and the graph has no cycle
+func (g *Graph) validate() error {
+	pending := map[string]int{}
+	for id, v := range g.vertices {
+		for _, dep := range v.dependsOn {
+			if _, ok := g.vertices[dep]; !ok {
+				return errors.Errorf("%q depends on undefined %q", id, dep)
+			}
+		}
+		pending[id] = len(v.dependsOn)
+	}
+	queue := g.roots()
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		delete(pending, id)
+		for other, v := range g.vertices {
+			for _, dep := range v.dependsOn {
+				if dep == id {
+					pending[other]--
+					if pending[other] == 0 {
+						queue = append(queue, other)
+					}
+				}
+			}
+		}
+	}
+	if len(pending) > 0 {
+		var cycle []string
+		for id := range pending {
+			cycle = append(cycle, id)
+		}
+		sort.Strings(cycle)
+		return errors.Errorf("dependency cycle between %s", strings.Join(cycle, ", "))
+	}
+	return nil
+}
+
+// InDependencyOrder runs fn concurrently on project services, a service being processed once all services it depends on are.
+// fn receives a pointer to the project service, so it can update its own definition.
+func InDependencyOrder(ctx context.Context, project *types.Project, fn func(context.Context, *types.ServiceConfig) error) error {
+	names := project.ServiceNames()
+	g := NewGraph()
+	for i := range project.Services {
+		service := &project.Services[i]
+		var dependsOn []string
+		for _, dep := range service.GetDependencies() {
+			// dependencies might have been filtered out of the project
+			if utils.StringContains(names, dep) {
+				dependsOn = append(dependsOn, dep)
+			}
+		}
+		g.Add(service.Name, func(ctx context.Context) error {
+			return fn(ctx, service)
+		}, dependsOn...)
+	}
+	return g.Run(ctx, MaxConcurrency())
+}
+
+// ForEachService runs fn concurrently on all project services, regardless of their dependencies
+func ForEachService(ctx context.Context, project *types.Project, fn func(context.Context, *types.ServiceConfig) error) error {
+	g := NewGraph()
+	for i := range project.Services {
+		service := &project.Services[i]
+		g.Add(service.Name, func(ctx context.Context) error {
+			return fn(ctx, service)
+		})
+	}
+	return g.Run(ctx, MaxConcurrency())
+}
@@ -0,0 +1,97 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestInDependencyOrder(t *testing.T) {
+	project := &types.Project{
+		Services: []types.ServiceConfig{
+			{Name: "front", DependsOn: types.DependsOnConfig{"back": {}}},
+			{Name: "back", DependsOn: types.DependsOnConfig{"db": {}, "cache": {}}},
+			{Name: "db"},
+			{Name: "cache"},
+		},
+	}
+	var mu sync.Mutex
+	var order []string
+	err := InDependencyOrder(context.TODO(), project, func(ctx context.Context, service *types.ServiceConfig) error {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, service.Name)
+		service.Image = service.Name + ":latest"
+		return nil
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(order), 4)
+	assert.DeepEqual(t, order[2:], []string{"back", "front"})
+	assert.Equal(t, project.Services[0].Image, "front:latest")
+}
+
+func TestGraphRunConcurrently(t *testing.T) {
+	started := make(chan struct{})
+	g := NewGraph()
+	// both tasks can only complete if they run at the same time
+	g.Add("a", func(ctx context.Context) error {
+		started <- struct{}{}
+		return nil
+	})
+	g.Add("b", func(ctx context.Context) error {
+		<-started
+		return nil
+	})
+	assert.NilError(t, g.Run(context.TODO(), 2))
+}
+
+func TestGraphStopsOnError(t *testing.T) {
+	failure := errors.New("failure")
+	ran := false
+	g := NewGraph()
+	g.Add("a", func(ctx context.Context) error {
+		return failure
+	})
+	g.Add("b", func(ctx context.Context) error {
+		ran = true
+		return nil
+	}, "a")
+	err := g.Run(context.TODO(), 1)
+	assert.Assert(t, errors.Is(err, failure))
+	assert.Assert(t, !ran)
+}
+
+func TestGraphCycle(t *testing.T) {
+	noop := func(ctx context.Context) error { return nil }
+	g := NewGraph()
+	g.Add("a", noop, "b")
+	g.Add("b", noop, "a")
+	g.Add("c", noop)
+	err := g.Run(context.TODO(), 1)
+	assert.Error(t, err, "dependency cycle between a, b")
+
+	g = NewGraph()
+	g.Add("a", noop, "missing")
+	err = g.Run(context.TODO(), 1)
+	assert.Error(t, err, `"a" depends on undefined "missing"`)
+}
@@ -0,0 +1,213 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// DevelopExtension declares how a service is updated while developing, as the compose `develop` section which
+// compose-go doesn't load yet: a `watch` list of rules, and a `post_sync` command
+const DevelopExtension = "x-develop"
+
+// Watch actions, run when files matching a watch rule change
+const (
+	// WatchActionSync copies the changed files into the service containers
+	WatchActionSync = "sync"
+	// WatchActionRebuild builds the service image again and recreates its containers
+	WatchActionRebuild = "rebuild"
+	// WatchActionSyncRestart copies the changed files into the service containers and restarts them
+	WatchActionSyncRestart = "sync+restart"
+)
+
+// WatchRule is the action to run when files under a path change
+type WatchRule struct {
+	Action string
+	// Path is the absolute path of the watched files on the host
+	Path string
+	// Target is the path the files are synced to in the service containers
+	Target string
+	// Ignore are patterns of files not to watch, relative to Path. Patterns ending with / only match directories.
+	Ignore []string
+}
+
+// DevelopConfig is the development configuration of a service, the contract of the watch subsystem
+type DevelopConfig struct {
+	Watch []WatchRule
+	// PostSync is a command run in the service containers once files have been synced
+	PostSync []string
+}
+
+// ServiceDevelopConfig returns the development configuration of a service, nil if it has none
+func ServiceDevelopConfig(project *types.Project, service types.ServiceConfig) (*DevelopConfig, error) {
+	x, ok := service.Extensions[DevelopExtension]
+	if !ok {
+		return nil, nil
+	}
+	develop, ok := x.(map[string]interface{})
+	if !ok {
+		return nil, errors.Wrapf(errdefs.ErrParsingFailed, "%s of service %s must be a mapping", DevelopExtension, service.Name)
+	}
+	config := &DevelopConfig{}
+	for key, value := range develop {
+		var err error
+		switch key {
+		case "watch":
+			config.Watch, err = parseWatchRules(project, service, value)
+		case "post_sync":
+			config.PostSync, err = parseCommand(value)
+		default:
+			err = fmt.Errorf("unsupported attribute %q", key)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "invalid %s for service %s: %v", DevelopExtension, service.Name, err)
+		}
+	}
+	return config, nil
+}
+
+func parseWatchRules(project *types.Project, service types.ServiceConfig, value interface{}) ([]WatchRule, error) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("watch must be a list of rules")
+	}
+	rules := make([]WatchRule, 0, len(items))
+	for i, item := range items {
+		attributes, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("watch rule %d must be a mapping", i)
+		}
+		var rule WatchRule
+		for key, v := range attributes {
+			switch key {
+			case "action":
+				rule.Action, ok = v.(string)
+			case "path":
+				rule.Path, ok = v.(string)
+			case "target":
+				rule.Target, ok = v.(string)
+			case "ignore":
+				rule.Ignore, ok = stringList(v)
+			default:
+				return nil, fmt.Errorf("unsupported watch rule attribute %q", key)
+			}
+			if !ok {
+				return nil, fmt.Errorf("invalid watch rule %s %v", key, v)
+			}
+		}
+		if err := validateWatchRule(service, &rule); err != nil {
+			return nil, fmt.Errorf("watch rule %d: %v", i, err)
+		}
+		if !filepath.IsAbs(rule.Path) {
+			rule.Path = filepath.Join(project.WorkingDir, rule.Path)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func validateWatchRule(service types.ServiceConfig, rule *WatchRule) error {
+	if rule.Path == "" {
+		return fmt.Errorf("path is required")
+	}
+	switch rule.Action {
+	case WatchActionSync, WatchActionSyncRestart:
+		if !path.IsAbs(rule.Target) {
+			return fmt.Errorf("%s action requires an absolute target path", rule.Action)
+		}
+	case WatchActionRebuild:
+		if service.Build == nil {
+			return fmt.Errorf("%s action requires the service to be built", rule.Action)
+		}
+	default:
+		return fmt.Errorf("unsupported action %q, use %s, %s or %s", rule.Action, WatchActionSync, WatchActionRebuild, WatchActionSyncRestart)
+	}
+	for _, pattern := range rule.Ignore {
+		if _, err := filepath.Match(strings.TrimSuffix(pattern, "/"), ""); err != nil {
+			return fmt.Errorf("invalid ignore pattern %q", pattern)
+		}
+	}
+	return nil
+}
+
+func stringList(value interface{}) ([]string, bool) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	list := make([]string, len(items))
+	for i, item := range items {
+		if list[i], ok = item.(string); !ok {
+			return nil, false
+		}
+	}
+	return list, true
+}
+
+// parseCommand parses a command as a list, or as a string run by a shell
+func parseCommand(value interface{}) ([]string, error) {
+	if command, ok := value.(string); ok {
+		return []string{"/bin/sh", "-c", command}, nil
+	}
+	command, ok := stringList(value)
+	if !ok || len(command) == 0 {
+		return nil, fmt.Errorf("post_sync must be a command string or list")
+	}
+	return command, nil
+}
+
+// Matches returns true if the file is under the rule path and not ignored
+func (r WatchRule) Matches(file string) bool {
+	rel, err := filepath.Rel(r.Path, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+	if rel == "." {
+		return true
+	}
+	parts := strings.Split(rel, string(filepath.Separator))
+	for _, pattern := range r.Ignore {
+		directory := strings.HasSuffix(pattern, "/")
+		pattern = filepath.FromSlash(strings.TrimSuffix(pattern, "/"))
+		for i := range parts {
+			if directory && i == len(parts)-1 {
+				break
+			}
+			if matched, _ := filepath.Match(pattern, filepath.Join(parts[:i+1]...)); matched {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// TargetPath returns the path a file matching the rule is synced to in the service containers
+func (r WatchRule) TargetPath(file string) (string, error) {
+	rel, err := filepath.Rel(r.Path, file)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(r.Target, filepath.ToSlash(rel)), nil
+}
@@ -0,0 +1,84 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestServiceDevelopConfig(t *testing.T) {
+	project := &types.Project{WorkingDir: filepath.FromSlash("/src/app")}
+	service := types.ServiceConfig{
+		Name:  "web",
+		Build: &types.BuildConfig{Context: "."},
+		Extensions: map[string]interface{}{
+			DevelopExtension: map[string]interface{}{
+				"watch": []interface{}{
+					map[string]interface{}{"action": "sync", "path": "./web", "target": "/app", "ignore": []interface{}{"node_modules/", "*.tmp"}},
+					map[string]interface{}{"action": "rebuild", "path": "package.json"},
+				},
+				"post_sync": "npm run reload",
+			},
+		},
+	}
+	config, err := ServiceDevelopConfig(project, service)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, config, &DevelopConfig{
+		Watch: []WatchRule{
+			{Action: WatchActionSync, Path: filepath.FromSlash("/src/app/web"), Target: "/app", Ignore: []string{"node_modules/", "*.tmp"}},
+			{Action: WatchActionRebuild, Path: filepath.FromSlash("/src/app/package.json")},
+		},
+		PostSync: []string{"/bin/sh", "-c", "npm run reload"},
+	})
+
+	service.Build = nil
+	_, err = ServiceDevelopConfig(project, service)
+	assert.Assert(t, errdefs.IsErrParsingFailed(err))
+	assert.ErrorContains(t, err, "rebuild action requires the service to be built")
+}
+
+func TestWatchRuleMatches(t *testing.T) {
+	rule := WatchRule{
+		Action: WatchActionSync,
+		Path:   filepath.FromSlash("/src/web"),
+		Target: "/app",
+		Ignore: []string{"node_modules/", "*.tmp", "build"},
+	}
+	for file, expected := range map[string]bool{
+		"/src/web/index.js":                true,
+		"/src/web/lib/util.js":             true,
+		"/src/web/node_modules/a/index.js": false,
+		"/src/web/lib/cache.tmp":           true,
+		"/src/web/cache.tmp":               false,
+		"/src/web/build":                   false,
+		"/src/web/build/main.js":           false,
+		"/src/other/index.js":              false,
+		"/src/webapp/index.js":             false,
+	} {
+		assert.Equal(t, rule.Matches(filepath.FromSlash(file)), expected, file)
+	}
+
+	target, err := rule.TargetPath(filepath.FromSlash("/src/web/lib/util.js"))
+	assert.NilError(t, err)
+	assert.Equal(t, target, "/app/lib/util.js")
+}
@@ -0,0 +1,84 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// SeverityError is the severity of a problem preventing the project from being deployed
+	SeverityError = "error"
+	// SeverityWarning is the severity of a problem the project can be deployed with, like an unsupported attribute
+	SeverityWarning = "warning"
+)
+
+// Diagnostic is a problem found converting a project for a backend
+type Diagnostic struct {
+	Severity string
+	Message  string
+}
+
+type diagnosticsKey struct{}
+
+type diagnostics struct {
+	mu    sync.Mutex
+	items []Diagnostic
+}
+
+func (d *diagnostics) add(severity string, message string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.items = append(d.items, Diagnostic{Severity: severity, Message: message})
+}
+
+func (d *diagnostics) list() []Diagnostic {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]Diagnostic{}, d.items...)
+}
+
+// WithDiagnostics returns a context collecting the warnings reported with Warn, and a function returning them
+func WithDiagnostics(ctx context.Context) (context.Context, func() []Diagnostic) {
+	d := &diagnostics{}
+	return context.WithValue(ctx, diagnosticsKey{}, d), d.list
+}
+
+// Warn reports a warning to the diagnostics collector of the context, or logs it when there is none
+func Warn(ctx context.Context, format string, args ...interface{}) {
+	if d, ok := ctx.Value(diagnosticsKey{}).(*diagnostics); ok {
+		d.add(SeverityWarning, fmt.Sprintf(format, args...))
+		return
+	}
+	logrus.Warnf(format, args...)
+}
+
+// Validate converts the project for the backend without deploying it and returns the problems found
+func Validate(ctx context.Context, service Service, project *types.Project) []Diagnostic {
+	ctx, collected := WithDiagnostics(ctx)
+	_, err := service.Convert(ctx, project)
+	result := collected()
+	if err != nil {
+		result = append(result, Diagnostic{Severity: SeverityError, Message: err.Error()})
+	}
+	return result
+}
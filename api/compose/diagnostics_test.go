@@ -0,0 +1,51 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+)
+
+type convertService struct {
+	Service
+	err error
+}
+
+func (s convertService) Convert(ctx context.Context, project *types.Project) ([]byte, error) {
+	Warn(ctx, "service %q: unsupported attribute", project.Services[0].Name)
+	return nil, s.err
+}
+
+func TestValidate(t *testing.T) {
+	project := &types.Project{Services: types.Services{{Name: "web"}}}
+
+	diagnostics := Validate(context.Background(), convertService{}, project)
+	assert.DeepEqual(t, diagnostics, []Diagnostic{
+		{Severity: SeverityWarning, Message: `service "web": unsupported attribute`},
+	})
+
+	diagnostics = Validate(context.Background(), convertService{err: errors.New("incompatible")}, project)
+	assert.DeepEqual(t, diagnostics, []Diagnostic{
+		{Severity: SeverityWarning, Message: `service "web": unsupported attribute`},
+		{Severity: SeverityError, Message: "incompatible"},
+	})
+}
@@ -0,0 +1,45 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// UnifiedDiff returns the changes from the deployed specification to the local one, empty when they are identical
+func UnifiedDiff(diff ProjectDiff) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        lines(diff.Deployed),
+		B:        lines(diff.Local),
+		FromFile: "deployed",
+		ToFile:   "local",
+		Context:  3,
+	})
+}
+
+func lines(spec []byte) []string {
+	lines := strings.SplitAfter(string(spec), "\n")
+	last := len(lines) - 1
+	if lines[last] == "" {
+		return lines[:last]
+	}
+	// specifications not ending with a new line, such as indented JSON documents
+	lines[last] += "\n"
+	return lines
+}
@@ -0,0 +1,42 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	diff, err := UnifiedDiff(ProjectDiff{
+		Deployed: []byte("web: nginx\ndb: postgres\n"),
+		Local:    []byte("web: nginx:1.19\ndb: postgres\n"),
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, diff, `--- deployed
++++ local
+@@ -1,2 +1,2 @@
+-web: nginx
++web: nginx:1.19
+ db: postgres
+`)
+
+	diff, err = UnifiedDiff(ProjectDiff{Deployed: []byte("web: nginx\n"), Local: []byte("web: nginx\n")})
+	assert.NilError(t, err)
+	assert.Equal(t, diff, "")
+}
@@ -0,0 +1,89 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+)
+
+// ServicePorts returns the sorted container ports a service listens on, either published or exposed
+func ServicePorts(service types.ServiceConfig) []uint32 {
+	seen := map[uint32]bool{}
+	for _, p := range service.Ports {
+		seen[p.Target] = true
+	}
+	for _, expose := range service.Expose {
+		// expose entries are either a port or a port range
+		bounds := strings.SplitN(strings.Split(expose, "/")[0], "-", 2)
+		start, err := strconv.ParseUint(bounds[0], 10, 16)
+		if err != nil {
+			continue
+		}
+		end := start
+		if len(bounds) == 2 {
+			if end, err = strconv.ParseUint(bounds[1], 10, 16); err != nil {
+				continue
+			}
+		}
+		for port := start; port <= end; port++ {
+			seen[uint32(port)] = true
+		}
+	}
+	ports := []uint32{}
+	for port := range seen {
+		ports = append(ports, port)
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+	return ports
+}
+
+// InternalPorts returns the sorted ports a service exposes without publishing them
+func InternalPorts(service types.ServiceConfig) []uint32 {
+	published := map[uint32]bool{}
+	for _, p := range service.Ports {
+		published[p.Target] = true
+	}
+	ports := []uint32{}
+	for _, port := range ServicePorts(service) {
+		if !published[port] {
+			ports = append(ports, port)
+		}
+	}
+	return ports
+}
+
+// PortProtocol returns the protocol of a published port, tcp by default
+func PortProtocol(port types.ServicePortConfig) string {
+	if port.Protocol == "" {
+		return "tcp"
+	}
+	return strings.ToLower(port.Protocol)
+}
+
+// SortExposure orders a port exposure report by service name then port
+func SortExposure(exposure []PortExposure) {
+	sort.Slice(exposure, func(i, j int) bool {
+		if exposure[i].Service != exposure[j].Service {
+			return exposure[i].Service < exposure[j].Service
+		}
+		return exposure[i].Port < exposure[j].Port
+	})
+}
@@ -0,0 +1,45 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestServicePorts(t *testing.T) {
+	ports := ServicePorts(types.ServiceConfig{
+		Ports: []types.ServicePortConfig{
+			{Target: 80, Published: 8080},
+			{Target: 443},
+		},
+		Expose: types.StringOrNumberList{"80", "9000-9002/tcp", "invalid"},
+	})
+	assert.DeepEqual(t, ports, []uint32{80, 443, 9000, 9001, 9002})
+}
+
+func TestInternalPorts(t *testing.T) {
+	ports := InternalPorts(types.ServiceConfig{
+		Ports: []types.ServicePortConfig{
+			{Target: 80},
+		},
+		Expose: types.StringOrNumberList{"80", "5432"},
+	})
+	assert.DeepEqual(t, ports, []uint32{5432})
+}
@@ -0,0 +1,120 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const (
+	// EnvFromExtension pulls environment variables of a service from a cloud configuration store, as a source URL or a list
+	EnvFromExtension = "x-env-from"
+	// EnvSnapshotLabel records the version of the variables pulled with x-env-from, so that a change redeploys the service
+	EnvSnapshotLabel = "com.docker.compose.env.snapshot"
+)
+
+// EnvFromSources returns the x-env-from sources of a service, without their scheme. Sources with another scheme are
+// rejected, as the backend can't pull them.
+func EnvFromSources(service types.ServiceConfig, scheme string) ([]string, error) {
+	var values []interface{}
+	switch x := service.Extensions[EnvFromExtension].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		values = []interface{}{x}
+	case []interface{}:
+		values = x
+	default:
+		return nil, errors.Wrapf(errdefs.ErrParsingFailed, "invalid %s for service %s: must be a source URL or a list", EnvFromExtension, service.Name)
+	}
+	var sources []string
+	for _, v := range values {
+		source, ok := v.(string)
+		if !ok || !strings.HasPrefix(source, scheme) {
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "unsupported %s source %v for service %s, only %s sources are", EnvFromExtension, v, service.Name, scheme)
+		}
+		sources = append(sources, strings.TrimPrefix(source, scheme))
+	}
+	return sources, nil
+}
+
+// ApplyEnvFrom pulls the variables of the project services x-env-from sources with fetch. They are set on services
+// which don't set them in their environment, and a snapshot of their values is recorded as the EnvSnapshotLabel.
+func ApplyEnvFrom(ctx context.Context, project *types.Project, scheme string, fetch func(ctx context.Context, source string) (map[string]string, error)) error {
+	fetched := map[string]map[string]string{}
+	for i, service := range project.Services {
+		sources, err := EnvFromSources(service, scheme)
+		if err != nil {
+			return err
+		}
+		if len(sources) == 0 {
+			continue
+		}
+		pulled := map[string]string{}
+		for _, source := range sources {
+			variables, ok := fetched[source]
+			if !ok {
+				variables, err = fetch(ctx, source)
+				if err != nil {
+					return errors.Wrapf(err, "cannot pull environment of service %s from %s%s", service.Name, scheme, source)
+				}
+				fetched[source] = variables
+			}
+			for name, value := range variables {
+				pulled[name] = value
+			}
+		}
+		if service.Environment == nil {
+			service.Environment = types.MappingWithEquals{}
+		}
+		for name, value := range pulled {
+			if _, ok := service.Environment[name]; !ok {
+				v := value
+				service.Environment[name] = &v
+			}
+		}
+		if service.Labels == nil {
+			service.Labels = types.Labels{}
+		}
+		service.Labels[EnvSnapshotLabel] = envSnapshot(pulled)
+		project.Services[i] = service
+	}
+	return nil
+}
+
+func envSnapshot(variables map[string]string) string {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	digest := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(digest, "%s=%s\n", name, variables[name])
+	}
+	return hex.EncodeToString(digest.Sum(nil))[:12]
+}
@@ -0,0 +1,77 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestApplyEnvFrom(t *testing.T) {
+	debug := "true"
+	project := &types.Project{
+		Services: types.Services{
+			{
+				Name:        "web",
+				Environment: types.MappingWithEquals{"DEBUG": &debug},
+				Extensions:  map[string]interface{}{EnvFromExtension: []interface{}{"ssm://app/", "ssm://app/web/"}},
+			},
+			{
+				Name:       "worker",
+				Extensions: map[string]interface{}{EnvFromExtension: "ssm://app/"},
+			},
+			{Name: "db"},
+		},
+	}
+	calls := 0
+	err := ApplyEnvFrom(context.Background(), project, "ssm://", func(ctx context.Context, source string) (map[string]string, error) {
+		calls++
+		if source == "app/" {
+			return map[string]string{"DEBUG": "false", "REGION": "eu"}, nil
+		}
+		return map[string]string{"PORT": "80"}, nil
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, calls, 2)
+
+	web := project.Services[0]
+	assert.Equal(t, *web.Environment["DEBUG"], "true")
+	assert.Equal(t, *web.Environment["REGION"], "eu")
+	assert.Equal(t, *web.Environment["PORT"], "80")
+	worker := project.Services[1]
+	assert.Equal(t, *worker.Environment["REGION"], "eu")
+	assert.Assert(t, web.Labels[EnvSnapshotLabel] != worker.Labels[EnvSnapshotLabel])
+	assert.Equal(t, len(project.Services[2].Labels), 0)
+}
+
+func TestEnvFromSourcesScheme(t *testing.T) {
+	service := types.ServiceConfig{
+		Name:       "web",
+		Extensions: map[string]interface{}{EnvFromExtension: "appconfig://store/prod"},
+	}
+	_, err := EnvFromSources(service, "ssm://")
+	assert.Assert(t, errdefs.IsErrParsingFailed(err))
+
+	sources, err := EnvFromSources(service, "appconfig://")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, sources, []string{"store/prod"})
+}
@@ -0,0 +1,150 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/envfile"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const (
+	// EnvSourceShell is the source of variables declared without a value, set from the shell environment
+	EnvSourceShell = "shell"
+	// EnvSourceOverride is the source of variables set by a --set override
+	EnvSourceOverride = "--set"
+	// EnvSourceUnknown is the source of variables which origin couldn't be found
+	EnvSourceUnknown = "unknown"
+)
+
+// EnvironmentVariable is a variable of a service environment, with the source its value comes from
+type EnvironmentVariable struct {
+	Name   string
+	Value  string
+	Source string
+}
+
+// EnvironmentSources returns the final environment of a service with the source of each variable: the compose file
+// or env_file declaring it, the shell for variables declared without a value, a --set override, or a secret injected
+// as an environment variable. Files are the compose files the project was loaded from, before interpolation.
+func EnvironmentSources(project *types.Project, name string, files []types.ConfigFile, overrides []string) ([]EnvironmentVariable, error) {
+	service, err := project.GetService(name)
+	if err != nil {
+		return nil, errors.Wrapf(errdefs.ErrNotFound, "service %q", name)
+	}
+	fragment, err := ParseOverrides(overrides)
+	if err != nil {
+		return nil, err
+	}
+	overridden := serviceEnvironment(fragment, name)
+
+	declared := map[string]string{}
+	for _, file := range files {
+		for variable, value := range serviceEnvironment(file.Config, name) {
+			if value == nil {
+				declared[variable] = EnvSourceShell
+			} else {
+				declared[variable] = file.Filename
+			}
+		}
+	}
+
+	fromFiles := map[string]string{}
+	for _, file := range service.EnvFile {
+		path := file
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(project.WorkingDir, path)
+		}
+		vars, err := envfile.Parse(path)
+		if err != nil {
+			return nil, err
+		}
+		for variable := range vars {
+			fromFiles[variable] = "env_file " + file
+		}
+	}
+
+	env := []EnvironmentVariable{}
+	for variable, value := range service.Environment {
+		source := EnvSourceUnknown
+		if _, ok := overridden[variable]; ok {
+			source = EnvSourceOverride
+		} else if s, ok := declared[variable]; ok {
+			source = s
+		} else if s, ok := fromFiles[variable]; ok {
+			source = s
+		}
+		v := ""
+		if value != nil {
+			v = *value
+		}
+		env = append(env, EnvironmentVariable{Name: variable, Value: v, Source: source})
+	}
+
+	injection, err := SecretInjection(service)
+	if err != nil {
+		return nil, err
+	}
+	if injection == SecretInjectionEnv {
+		for _, secret := range service.Secrets {
+			env = append(env, EnvironmentVariable{
+				Name:   SecretEnvName(secret),
+				Value:  "********",
+				Source: fmt.Sprintf("secret %s", secret.Source),
+			})
+		}
+	}
+	sort.Slice(env, func(i, j int) bool {
+		return env[i].Name < env[j].Name
+	})
+	return env, nil
+}
+
+// serviceEnvironment returns the environment declared by a service in a compose file, as a list or a mapping
+func serviceEnvironment(file map[string]interface{}, name string) map[string]*string {
+	services, _ := file["services"].(map[string]interface{})
+	service, _ := services[name].(map[string]interface{})
+	env := map[string]*string{}
+	switch environment := service["environment"].(type) {
+	case []interface{}:
+		for _, entry := range environment {
+			parts := strings.SplitN(fmt.Sprint(entry), "=", 2)
+			if len(parts) == 2 {
+				env[parts[0]] = &parts[1]
+			} else {
+				env[parts[0]] = nil
+			}
+		}
+	case map[string]interface{}:
+		for variable, value := range environment {
+			if value == nil {
+				env[variable] = nil
+				continue
+			}
+			v := fmt.Sprint(value)
+			env[variable] = &v
+		}
+	}
+	return env
+}
@@ -0,0 +1,74 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func TestEnvironmentSources(t *testing.T) {
+	dir := fs.NewDir(t, "env", fs.WithFile("web.env", "DB_HOST=db\nDB_PORT=5432\n"))
+	defer dir.Remove()
+
+	str := func(s string) *string { return &s }
+	project := &types.Project{
+		WorkingDir: dir.Path(),
+		Services: []types.ServiceConfig{
+			{
+				Name:    "web",
+				EnvFile: []string{"web.env"},
+				Environment: types.MappingWithEquals{
+					"DB_HOST":  str("prod-db"),
+					"DB_PORT":  str("5432"),
+					"DEBUG":    str("1"),
+					"REPLICAS": str("3"),
+				},
+				Secrets:    []types.ServiceSecretConfig{{Source: "db_password", Target: "DB_PASSWORD"}},
+				Extensions: map[string]interface{}{SecretInjectionExtension: SecretInjectionEnv},
+			},
+		},
+	}
+	files := []types.ConfigFile{
+		{Filename: "compose.yaml", Config: map[string]interface{}{
+			"services": map[string]interface{}{
+				"web": map[string]interface{}{"environment": []interface{}{"DB_HOST=db", "DEBUG"}},
+			},
+		}},
+		{Filename: "compose.prod.yaml", Config: map[string]interface{}{
+			"services": map[string]interface{}{
+				"web": map[string]interface{}{"environment": map[string]interface{}{"DB_HOST": "prod-db"}},
+			},
+		}},
+	}
+
+	env, err := EnvironmentSources(project, "web", files, []string{"services.web.environment.REPLICAS=3"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, env, []EnvironmentVariable{
+		{Name: "DB_HOST", Value: "prod-db", Source: "compose.prod.yaml"},
+		{Name: "DB_PASSWORD", Value: "********", Source: "secret db_password"},
+		{Name: "DB_PORT", Value: "5432", Source: "env_file web.env"},
+		{Name: "DEBUG", Value: "1", Source: EnvSourceShell},
+		{Name: "REPLICAS", Value: "3", Source: EnvSourceOverride},
+	})
+
+	_, err = EnvironmentSources(project, "db", files, nil)
+	assert.ErrorContains(t, err, "not found")
+}
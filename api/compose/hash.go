@@ -0,0 +1,43 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"github.com/compose-spec/compose-go/types"
+)
+
+// ImageDigests maps image references to the reference pinned to the digest their tag resolves to
+type ImageDigests map[string]string
+
+// ServiceHash computes a digest of the service configuration, used to detect services which changed since last deployment.
+// The image is hashed with the digest it resolves to in digests, so that a tag pushed again changes the hash.
+func ServiceHash(service types.ServiceConfig, digests ImageDigests) (string, error) {
+	// build section has been replaced by the resulting image when deploying
+	service.Build = nil
+	if pinned, ok := digests[service.Image]; ok {
+		service.Image = pinned
+	}
+	data, err := json.Marshal(service)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
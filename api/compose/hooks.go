@@ -0,0 +1,263 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/progress"
+)
+
+// HooksExtension declares the commands and jobs run at each phase of compose up and down, as lists of hooks by phase
+const HooksExtension = "x-hooks"
+
+const (
+	// PreUpHook runs before the project is deployed
+	PreUpHook = "pre-up"
+	// PostUpHook runs once the project is deployed, before compose up waits for services and runs smoke tests
+	PostUpHook = "post-up"
+	// PreDownHook runs before the project is removed
+	PreDownHook = "pre-down"
+)
+
+const (
+	// HookFailureAbort stops the command when the hook fails, leaving the application as it is. This is the default
+	HookFailureAbort = "abort"
+	// HookFailureContinue warns about the failed hook and carries on
+	HookFailureContinue = "continue"
+	// HookFailureRollback removes the application created by compose up when a post-up hook fails
+	HookFailureRollback = "rollback"
+)
+
+// hookOutputLines is the number of output lines of a failed command reported in the error
+const hookOutputLines = 20
+
+// Hook is a local command or a job of the project run at a phase of compose up or down
+type Hook struct {
+	// Command is run locally with sh -c (cmd /C on Windows), from the project working directory
+	Command string
+	// Service is the name of a job of the project, run as a one-off task by the backend
+	Service string
+	// OnFailure is one of HookFailureAbort, HookFailureContinue or HookFailureRollback
+	OnFailure string
+}
+
+func (h Hook) String() string {
+	if h.Service != "" {
+		return "job " + h.Service
+	}
+	return h.Command
+}
+
+// HookFailedError is returned when a hook fails and its failure policy doesn't let the command continue
+type HookFailedError struct {
+	Phase string
+	Hook  Hook
+	Err   error
+}
+
+func (e HookFailedError) Error() string {
+	return fmt.Sprintf("%s hook %q failed: %s", e.Phase, e.Hook, e.Err)
+}
+
+// Unwrap returns the error of the hook
+func (e HookFailedError) Unwrap() error {
+	return e.Err
+}
+
+// Rollback returns true if the application deployed by compose up must be removed
+func (e HookFailedError) Rollback() bool {
+	return e.Hook.OnFailure == HookFailureRollback
+}
+
+// ProjectHooks returns the hooks of the project by phase, declared with x-hooks
+func ProjectHooks(project *types.Project) (map[string][]Hook, error) {
+	x, ok := project.Extensions[HooksExtension]
+	if !ok {
+		return nil, nil
+	}
+	phases, ok := x.(map[string]interface{})
+	if !ok {
+		return nil, errors.Wrapf(errdefs.ErrParsingFailed, "%s must map phases to lists of hooks", HooksExtension)
+	}
+	hooks := map[string][]Hook{}
+	for phase, v := range phases {
+		switch phase {
+		case PreUpHook, PostUpHook, PreDownHook:
+		default:
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "unknown %s phase %q, must be %s, %s or %s", HooksExtension, phase, PreUpHook, PostUpHook, PreDownHook)
+		}
+		items, ok := v.([]interface{})
+		if !ok {
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "%s %s must be a list of hooks", HooksExtension, phase)
+		}
+		for _, item := range items {
+			hook, err := parseHook(item)
+			if err != nil {
+				return nil, errors.Wrapf(err, "%s %s", HooksExtension, phase)
+			}
+			if err := checkHook(project, phase, hook); err != nil {
+				return nil, errors.Wrapf(err, "%s %s", HooksExtension, phase)
+			}
+			hooks[phase] = append(hooks[phase], hook)
+		}
+	}
+	return hooks, nil
+}
+
+func parseHook(item interface{}) (Hook, error) {
+	hook := Hook{OnFailure: HookFailureAbort}
+	switch v := item.(type) {
+	case string:
+		hook.Command = v
+	case map[string]interface{}:
+		for k, value := range v {
+			switch k {
+			case "command":
+				hook.Command = fmt.Sprint(value)
+			case "service":
+				hook.Service = fmt.Sprint(value)
+			case "on-failure":
+				hook.OnFailure = fmt.Sprint(value)
+			default:
+				return hook, errors.Wrapf(errdefs.ErrParsingFailed, "unknown hook attribute %q", k)
+			}
+		}
+	default:
+		return hook, errors.Wrapf(errdefs.ErrParsingFailed, "invalid hook: %v", item)
+	}
+	if (hook.Command == "") == (hook.Service == "") {
+		return hook, errors.Wrap(errdefs.ErrParsingFailed, "a hook must set either command or service")
+	}
+	switch hook.OnFailure {
+	case HookFailureAbort, HookFailureContinue, HookFailureRollback:
+	default:
+		return hook, errors.Wrapf(errdefs.ErrParsingFailed, "invalid on-failure %q, must be %s, %s or %s", hook.OnFailure, HookFailureAbort, HookFailureContinue, HookFailureRollback)
+	}
+	return hook, nil
+}
+
+func checkHook(project *types.Project, phase string, hook Hook) error {
+	if hook.OnFailure == HookFailureRollback && phase != PostUpHook {
+		return errors.Wrapf(errdefs.ErrParsingFailed, "on-failure %s is only supported by %s hooks", HookFailureRollback, PostUpHook)
+	}
+	if hook.Service == "" {
+		return nil
+	}
+	if phase == PreUpHook {
+		return errors.Wrapf(errdefs.ErrParsingFailed, "job %s can't run before the project is deployed, use a command", hook.Service)
+	}
+	job, err := project.GetService(hook.Service)
+	if err != nil {
+		return errors.Wrapf(errdefs.ErrParsingFailed, "no service %q", hook.Service)
+	}
+	if !IsJob(job) {
+		return errors.Wrapf(errdefs.ErrParsingFailed, "service %s must be a job to run as a hook", hook.Service)
+	}
+	for _, service := range project.Services {
+		for _, dependency := range service.GetDependencies() {
+			if dependency == hook.Service {
+				return errors.Wrapf(errdefs.ErrParsingFailed, "service %s can't depend on job %s which runs as a hook", service.Name, hook.Service)
+			}
+		}
+	}
+	return nil
+}
+
+// HookJobs returns the jobs run by hooks, which the backends deploy but don't run with the other jobs
+func HookJobs(project *types.Project) map[string]bool {
+	hooks, err := ProjectHooks(project)
+	if err != nil {
+		return nil
+	}
+	jobs := map[string]bool{}
+	for _, phase := range hooks {
+		for _, hook := range phase {
+			if hook.Service != "" {
+				jobs[hook.Service] = true
+			}
+		}
+	}
+	return jobs
+}
+
+// RunHooks runs the hooks of a phase in order, reporting their progress. Jobs are run with runJob.
+// A failed hook returns a HookFailedError, unless its failure policy is HookFailureContinue.
+func RunHooks(ctx context.Context, project *types.Project, phase string, hooks []Hook, runJob func(ctx context.Context, job string) error) error {
+	w := progress.ContextWriter(ctx)
+	for i, hook := range hooks {
+		id := fmt.Sprintf("%s hook %d", phase, i+1)
+		w.Event(progress.Event{ID: id, Status: progress.Working, StatusText: hook.String()})
+		var err error
+		if hook.Service != "" {
+			err = runJob(ctx, hook.Service)
+		} else {
+			err = runHookCommand(ctx, project, phase, hook.Command)
+		}
+		if err == nil {
+			w.Event(progress.Event{ID: id, Status: progress.Done, StatusText: hook.String()})
+			continue
+		}
+		w.Event(progress.Event{ID: id, Status: progress.Error, StatusText: hook.String()})
+		if hook.OnFailure == HookFailureContinue {
+			Warn(ctx, "%s hook %q failed: %s", phase, hook, err)
+			continue
+		}
+		return HookFailedError{Phase: phase, Hook: hook, Err: err}
+	}
+	return nil
+}
+
+// runHookCommand runs a command hook, its output is only reported when the command fails so that it doesn't break
+// the progress display
+func runHookCommand(ctx context.Context, project *types.Project, phase string, command string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Dir = project.WorkingDir
+	cmd.Env = append(os.Environ(),
+		"COMPOSE_PROJECT="+project.Name,
+		"COMPOSE_HOOK="+phase,
+	)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+		if len(lines) > hookOutputLines {
+			lines = lines[len(lines)-hookOutputLines:]
+		}
+		if len(lines) == 1 && lines[0] == "" {
+			return err
+		}
+		return errors.Errorf("%s, output:\n%s", err, strings.Join(lines, "\n"))
+	}
+	return nil
+}
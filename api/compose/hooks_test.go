@@ -0,0 +1,116 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func hooksProject(hooks map[string]interface{}) *types.Project {
+	return &types.Project{
+		Name: "app",
+		Services: []types.ServiceConfig{
+			{Name: "web"},
+			{Name: "migrate", Deploy: &types.DeployConfig{Mode: "job"}},
+			{Name: "init", Extensions: map[string]interface{}{"x-job": true}},
+		},
+		Extensions: map[string]interface{}{HooksExtension: hooks},
+	}
+}
+
+func TestProjectHooks(t *testing.T) {
+	hooks, err := ProjectHooks(hooksProject(map[string]interface{}{
+		"pre-up": []interface{}{"./check.sh"},
+		"post-up": []interface{}{
+			map[string]interface{}{"service": "migrate", "on-failure": "rollback"},
+			map[string]interface{}{"command": "curl https://example.com", "on-failure": "continue"},
+		},
+	}))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, hooks, map[string][]Hook{
+		PreUpHook: {{Command: "./check.sh", OnFailure: HookFailureAbort}},
+		PostUpHook: {
+			{Service: "migrate", OnFailure: HookFailureRollback},
+			{Command: "curl https://example.com", OnFailure: HookFailureContinue},
+		},
+	})
+}
+
+func TestProjectHooksErrors(t *testing.T) {
+	for name, hooks := range map[string]map[string]interface{}{
+		"unknown phase":        {"post-down": []interface{}{"true"}},
+		"command and service":  {"post-up": []interface{}{map[string]interface{}{"command": "true", "service": "migrate"}}},
+		"invalid on-failure":   {"post-up": []interface{}{map[string]interface{}{"command": "true", "on-failure": "retry"}}},
+		"rollback before down": {"pre-down": []interface{}{map[string]interface{}{"command": "true", "on-failure": "rollback"}}},
+		"job before up":        {"pre-up": []interface{}{map[string]interface{}{"service": "migrate"}}},
+		"not a job":            {"post-up": []interface{}{map[string]interface{}{"service": "web"}}},
+		"unknown service":      {"post-up": []interface{}{map[string]interface{}{"service": "worker"}}},
+	} {
+		_, err := ProjectHooks(hooksProject(hooks))
+		assert.Assert(t, err != nil, name)
+	}
+}
+
+func TestHookJobsAreNotRunOnDeployment(t *testing.T) {
+	project := hooksProject(map[string]interface{}{
+		"post-up": []interface{}{map[string]interface{}{"service": "migrate"}},
+	})
+	jobs := Jobs(project)
+	assert.Equal(t, len(jobs), 1)
+	assert.Equal(t, jobs[0].Name, "init")
+}
+
+func TestRunHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook commands run with sh")
+	}
+	dir := fs.NewDir(t, "hooks")
+	defer dir.Remove()
+	project := &types.Project{Name: "app", WorkingDir: dir.Path()}
+	var ran []string
+	runJob := func(ctx context.Context, job string) error {
+		ran = append(ran, job)
+		return errors.New("exit code 1")
+	}
+
+	err := RunHooks(context.Background(), project, PostUpHook, []Hook{
+		{Command: "echo $COMPOSE_PROJECT $COMPOSE_HOOK > hook.txt", OnFailure: HookFailureAbort},
+		{Service: "seed", OnFailure: HookFailureContinue},
+		{Service: "migrate", OnFailure: HookFailureRollback},
+		{Service: "never", OnFailure: HookFailureAbort},
+	}, runJob)
+	var failed HookFailedError
+	assert.Assert(t, errors.As(err, &failed))
+	assert.Equal(t, failed.Hook.Service, "migrate")
+	assert.Assert(t, failed.Rollback())
+	assert.DeepEqual(t, ran, []string{"seed", "migrate"})
+	written, err := ioutil.ReadFile(filepath.Join(dir.Path(), "hook.txt"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(written), "app post-up\n")
+
+	err = RunHooks(context.Background(), project, PreUpHook, []Hook{{Command: "echo migration failed; exit 3"}}, runJob)
+	assert.ErrorContains(t, err, `pre-up hook "echo migration failed; exit 3" failed: exit status 3, output:`+"\nmigration failed")
+}
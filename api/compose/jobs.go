@@ -0,0 +1,149 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/compose-spec/compose-go/types"
+
+	"github.com/docker/compose-cli/progress"
+)
+
+const (
+	// JobMode is the deploy mode of services running to completion before their dependents start
+	JobMode = "job"
+	// JobExtension marks a service as a job, as an alternative to `deploy.mode: job`
+	JobExtension = "x-job"
+)
+
+// IsJob returns true if the service runs to completion when the project is deployed, instead of being kept running.
+// Scheduled services aren't jobs, as they only run on their schedule.
+func IsJob(service types.ServiceConfig) bool {
+	if IsScheduled(service) {
+		return false
+	}
+	if service.Deploy != nil && service.Deploy.Mode == JobMode {
+		return true
+	}
+	job, ok := service.Extensions[JobExtension].(bool)
+	return ok && job
+}
+
+// Jobs returns the job services of the project run when it is deployed, jobs run by hooks are left out
+func Jobs(project *types.Project) []types.ServiceConfig {
+	hookJobs := HookJobs(project)
+	var jobs []types.ServiceConfig
+	for _, service := range project.Services {
+		if IsJob(service) && !hookJobs[service.Name] {
+			jobs = append(jobs, service)
+		}
+	}
+	return jobs
+}
+
+// JobDependents returns the names of the services which can only start once a job completed,
+// as they depend on it directly or through other services
+func JobDependents(project *types.Project) []string {
+	waiting := map[string]bool{}
+	var waitsForJob func(service types.ServiceConfig, visited map[string]bool) bool
+	waitsForJob = func(service types.ServiceConfig, visited map[string]bool) bool {
+		for _, name := range service.GetDependencies() {
+			if visited[name] {
+				continue
+			}
+			visited[name] = true
+			dependency, err := project.GetService(name)
+			if err != nil {
+				continue
+			}
+			if IsJob(dependency) || waitsForJob(dependency, visited) {
+				return true
+			}
+		}
+		return false
+	}
+	var dependents []string
+	for _, service := range project.Services {
+		if IsJob(service) || waiting[service.Name] {
+			continue
+		}
+		if waitsForJob(service, map[string]bool{}) {
+			waiting[service.Name] = true
+			dependents = append(dependents, service.Name)
+		}
+	}
+	return dependents
+}
+
+// RunJobs runs the project jobs, a job starting once the jobs it depends on completed, and reports their progress.
+// Failed jobs are restarted according to their deploy.restart_policy.
+func RunJobs(ctx context.Context, project *types.Project, run func(ctx context.Context, job types.ServiceConfig) error) error {
+	jobs := &types.Project{Name: project.Name, Services: Jobs(project)}
+	w := progress.ContextWriter(ctx)
+	return InDependencyOrder(ctx, jobs, func(ctx context.Context, job *types.ServiceConfig) error {
+		w.Event(progress.Event{
+			ID:         job.Name,
+			Status:     progress.Working,
+			StatusText: "Running job",
+		})
+		policy, err := ServiceRestartPolicy(*job)
+		if err != nil {
+			return err
+		}
+		err = RunWithRestarts(ctx, policy, func(ctx context.Context, restarts int) error {
+			if restarts > 0 {
+				w.Event(progress.Event{
+					ID:         job.Name,
+					Status:     progress.Working,
+					StatusText: fmt.Sprintf("Restarting (%d)", restarts),
+				})
+			}
+			return run(ctx, *job)
+		})
+		if err != nil {
+			w.Event(progress.Event{
+				ID:         job.Name,
+				Status:     progress.Error,
+				StatusText: "Failed",
+			})
+			return err
+		}
+		w.Event(progress.Event{
+			ID:         job.Name,
+			Status:     progress.Done,
+			StatusText: "Completed",
+		})
+		return nil
+	})
+}
+
+// JobFailedError is returned when a job container exits with a non zero status
+type JobFailedError struct {
+	Job      string
+	ExitCode int
+	Reason   string
+}
+
+func (e JobFailedError) Error() string {
+	msg := fmt.Sprintf("job %q failed with exit code %d", e.Job, e.ExitCode)
+	if e.Reason != "" {
+		msg += ": " + e.Reason
+	}
+	return msg
+}
@@ -0,0 +1,65 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestIsJob(t *testing.T) {
+	assert.Assert(t, IsJob(types.ServiceConfig{Deploy: &types.DeployConfig{Mode: "job"}}))
+	assert.Assert(t, IsJob(types.ServiceConfig{Extensions: map[string]interface{}{"x-job": true}}))
+	assert.Assert(t, !IsJob(types.ServiceConfig{Deploy: &types.DeployConfig{Mode: "replicated"}}))
+	assert.Assert(t, !IsJob(types.ServiceConfig{}))
+}
+
+func jobsProject() *types.Project {
+	return &types.Project{
+		Services: []types.ServiceConfig{
+			{Name: "db"},
+			{Name: "migrate", DependsOn: types.DependsOnConfig{"db": {}}, Deploy: &types.DeployConfig{Mode: "job"}},
+			{Name: "seed", DependsOn: types.DependsOnConfig{"migrate": {}}, Extensions: map[string]interface{}{"x-job": true}},
+			{Name: "api", DependsOn: types.DependsOnConfig{"seed": {}, "db": {}}},
+			{Name: "front", DependsOn: types.DependsOnConfig{"api": {}}},
+			{Name: "admin", DependsOn: types.DependsOnConfig{"db": {}}},
+		},
+	}
+}
+
+func TestJobDependents(t *testing.T) {
+	assert.DeepEqual(t, JobDependents(jobsProject()), []string{"api", "front"})
+}
+
+func TestRunJobs(t *testing.T) {
+	var (
+		mu  sync.Mutex
+		ran []string
+	)
+	err := RunJobs(context.Background(), jobsProject(), func(ctx context.Context, job types.ServiceConfig) error {
+		mu.Lock()
+		defer mu.Unlock()
+		ran = append(ran, job.Name)
+		return nil
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, ran, []string{"migrate", "seed"})
+}
@@ -0,0 +1,182 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	apicontext "github.com/docker/compose-cli/context"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/progress"
+)
+
+// LockInfo identifies the command holding a project lock
+type LockInfo struct {
+	ID       string    `json:"id"`
+	Owner    string    `json:"owner"`
+	Acquired time.Time `json:"acquired"`
+}
+
+// NewLockInfo creates the lock information recorded by the current command
+func NewLockInfo() LockInfo {
+	owner := "unknown"
+	if u, err := user.Current(); err == nil {
+		owner = u.Username
+	}
+	if host, err := os.Hostname(); err == nil {
+		owner = owner + "@" + host
+	}
+	return LockInfo{
+		ID:       uuid.New().String(),
+		Owner:    fmt.Sprintf("%s (pid %d)", owner, os.Getpid()),
+		Acquired: time.Now().UTC(),
+	}
+}
+
+func (l LockInfo) String() string {
+	return fmt.Sprintf("%s since %s", l.Owner, l.Acquired.Format(time.RFC3339))
+}
+
+// LockPollingInterval is the delay between two attempts to acquire a lock held by another command
+var LockPollingInterval = time.Second
+
+// AcquireLock calls try until it obtains the project lock or timeout expires.
+// try returns the current holder of the lock when it is held by another command, nil once acquired.
+func AcquireLock(ctx context.Context, project string, timeout time.Duration, try func(context.Context) (*LockInfo, error)) error {
+	w := progress.ContextWriter(ctx)
+	deadline := time.Now().Add(timeout)
+	for {
+		holder, err := try(ctx)
+		if err != nil {
+			return err
+		}
+		if holder == nil {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			return errors.Wrapf(errdefs.ErrLocked, "project %q is locked by %s, run `docker compose unlock` if that command was interrupted", project, holder)
+		}
+		w.Event(progress.Event{
+			ID:         "Lock " + project,
+			Status:     progress.Working,
+			StatusText: "Waiting for " + holder.Owner,
+		})
+		select {
+		case <-time.After(LockPollingInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// FileLock implements project locks with lock files, for backends running on the local machine
+type FileLock struct {
+	Dir string
+}
+
+// ContextLocks returns the file locks, under dir, of the projects of the current context. Projects with the same name
+// in other contexts have their own locks, so that commands against them don't wait for each other.
+func ContextLocks(ctx context.Context, dir string) FileLock {
+	return FileLock{Dir: filepath.Join(dir, apicontext.CurrentContext(ctx))}
+}
+
+// Lock acquires the project lock, waiting up to timeout for another command to release it
+func (l FileLock) Lock(ctx context.Context, project string, timeout time.Duration) (func() error, error) {
+	if err := os.MkdirAll(l.Dir, 0700); err != nil {
+		return nil, err
+	}
+	info := NewLockInfo()
+	content, err := json.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+	path := l.path(project)
+	err = AcquireLock(ctx, project, timeout, func(context.Context) (*LockInfo, error) {
+		for {
+			f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+			if os.IsExist(err) {
+				holder, err := l.holder(project)
+				if os.IsNotExist(err) {
+					// released in the meantime, try again right away
+					continue
+				}
+				return holder, err
+			}
+			if err != nil {
+				return nil, err
+			}
+			_, err = f.Write(content)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			return nil, err
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return func() error {
+		holder, err := l.holder(project)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if holder.ID != info.ID {
+			// lock was forcibly released and acquired by another command
+			return nil
+		}
+		return os.Remove(path)
+	}, nil
+}
+
+// Unlock forcibly releases the project lock
+func (l FileLock) Unlock(project string) error {
+	err := os.Remove(l.path(project))
+	if os.IsNotExist(err) {
+		return errors.Wrapf(errdefs.ErrNotFound, "project %q is not locked", project)
+	}
+	return err
+}
+
+func (l FileLock) holder(project string) (*LockInfo, error) {
+	content, err := ioutil.ReadFile(l.path(project))
+	if err != nil {
+		return nil, err
+	}
+	var holder LockInfo
+	if err := json.Unmarshal(content, &holder); err != nil {
+		// lock file is being written
+		return &LockInfo{Owner: "unknown"}, nil
+	}
+	return &holder, nil
+}
+
+func (l FileLock) path(project string) string {
+	return filepath.Join(l.Dir, project+".lock")
+}
@@ -0,0 +1,108 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	apicontext "github.com/docker/compose-cli/context"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestFileLockIsExclusive(t *testing.T) {
+	dir := fs.NewDir(t, "locks")
+	defer dir.Remove()
+	locks := FileLock{Dir: dir.Path()}
+	ctx := context.Background()
+
+	release, err := locks.Lock(ctx, "test", 0)
+	assert.NilError(t, err)
+
+	_, err = locks.Lock(ctx, "test", 0)
+	assert.Assert(t, errdefs.IsErrLocked(err))
+
+	other, err := locks.Lock(ctx, "other", 0)
+	assert.NilError(t, err)
+	assert.NilError(t, other())
+
+	assert.NilError(t, release())
+	release, err = locks.Lock(ctx, "test", 0)
+	assert.NilError(t, err)
+	assert.NilError(t, release())
+}
+
+func TestFileLockWaitsForRelease(t *testing.T) {
+	dir := fs.NewDir(t, "locks")
+	defer dir.Remove()
+	locks := FileLock{Dir: dir.Path()}
+	ctx := context.Background()
+	LockPollingInterval = 10 * time.Millisecond
+
+	release, err := locks.Lock(ctx, "test", 0)
+	assert.NilError(t, err)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = release()
+	}()
+
+	release, err = locks.Lock(ctx, "test", time.Minute)
+	assert.NilError(t, err)
+	assert.NilError(t, release())
+}
+
+func TestFileUnlock(t *testing.T) {
+	dir := fs.NewDir(t, "locks")
+	defer dir.Remove()
+	locks := FileLock{Dir: dir.Path()}
+	ctx := context.Background()
+
+	err := locks.Unlock("test")
+	assert.Assert(t, errdefs.IsNotFoundError(err))
+
+	release, err := locks.Lock(ctx, "test", 0)
+	assert.NilError(t, err)
+	assert.NilError(t, locks.Unlock("test"))
+
+	next, err := locks.Lock(ctx, "test", 0)
+	assert.NilError(t, err)
+	// releasing a lock forcibly unlocked doesn't release the next holder
+	assert.NilError(t, release())
+	_, err = locks.Lock(ctx, "test", 0)
+	assert.Assert(t, errdefs.IsErrLocked(err))
+	assert.NilError(t, next())
+}
+
+func TestContextLocks(t *testing.T) {
+	dir := fs.NewDir(t, "locks")
+	defer dir.Remove()
+
+	ctx := apicontext.WithCurrentContext(context.Background(), "one")
+	release, err := ContextLocks(ctx, dir.Path()).Lock(ctx, "test", 0)
+	assert.NilError(t, err)
+	defer release() // nolint:errcheck
+
+	// same project name in another context
+	other := apicontext.WithCurrentContext(context.Background(), "two")
+	next, err := ContextLocks(other, dir.Path()).Lock(other, "test", 0)
+	assert.NilError(t, err)
+	assert.NilError(t, next())
+}
@@ -0,0 +1,124 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"strings"
+
+	"github.com/compose-spec/compose-go/loader"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+	"github.com/sanathkr/go-yaml"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// ComposeFile returns the attributes of a project which belong to the compose file format, leaving out the project
+// name and working directory
+func ComposeFile(project *types.Project) map[string]interface{} {
+	file := map[string]interface{}{
+		"services": project.Services,
+	}
+	if len(project.Networks) > 0 {
+		file["networks"] = project.Networks
+	}
+	if len(project.Volumes) > 0 {
+		file["volumes"] = project.Volumes
+	}
+	if len(project.Secrets) > 0 {
+		file["secrets"] = project.Secrets
+	}
+	if len(project.Configs) > 0 {
+		file["configs"] = project.Configs
+	}
+	for name, extension := range project.Extensions {
+		file[name] = extension
+	}
+	return file
+}
+
+// ParseOverrides converts `path=value` overrides into a compose file fragment. Paths are dot separated attributes from
+// the top-level of the compose file, values are parsed as YAML so that numbers, booleans and lists get their type.
+func ParseOverrides(overrides []string) (map[string]interface{}, error) {
+	fragment := map[string]interface{}{}
+	for _, override := range overrides {
+		parts := strings.SplitN(override, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "override %q doesn't match path=value", override)
+		}
+		value, err := loader.ParseYAML([]byte("value: " + parts[1]))
+		if err != nil {
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "override %q: %s", override, err)
+		}
+
+		path := strings.Split(parts[0], ".")
+		current := fragment
+		for i, key := range path {
+			if key == "" {
+				return nil, errors.Wrapf(errdefs.ErrParsingFailed, "override %q has an empty path element", override)
+			}
+			if i == len(path)-1 {
+				current[key] = value["value"]
+				break
+			}
+			next, ok := current[key].(map[string]interface{})
+			if !ok {
+				next = map[string]interface{}{}
+				current[key] = next
+			}
+			current = next
+		}
+	}
+	return fragment, nil
+}
+
+// ApplyOverrides loads the project again with `path=value` overrides merged on top of it, the way an additional
+// compose file would be. Overrides can only target services declared by the project.
+func ApplyOverrides(project *types.Project, overrides []string, environment map[string]string) (*types.Project, error) {
+	fragment, err := ParseOverrides(overrides)
+	if err != nil {
+		return nil, err
+	}
+	if services, ok := fragment["services"].(map[string]interface{}); ok {
+		for name := range services {
+			if _, err := project.GetService(name); err != nil {
+				return nil, errors.Wrapf(errdefs.ErrNotFound, "override of service %q", name)
+			}
+		}
+	}
+
+	content, err := yaml.Marshal(ComposeFile(project))
+	if err != nil {
+		return nil, err
+	}
+	base, err := loader.ParseYAML(content)
+	if err != nil {
+		return nil, err
+	}
+	return loader.Load(types.ConfigDetails{
+		WorkingDir: project.WorkingDir,
+		ConfigFiles: []types.ConfigFile{
+			{Filename: "compose.yaml", Config: base},
+			{Filename: "overrides", Config: fragment},
+		},
+		Environment: environment,
+	}, func(options *loader.Options) {
+		options.Name = project.Name
+		// values have been interpolated when the project was first loaded
+		options.SkipInterpolation = true
+	})
+}
@@ -0,0 +1,105 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/loader"
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func loadProject(t *testing.T, yaml string) *types.Project {
+	dict, err := loader.ParseYAML([]byte(yaml))
+	assert.NilError(t, err)
+	project, err := loader.Load(types.ConfigDetails{
+		WorkingDir:  "/src",
+		ConfigFiles: []types.ConfigFile{{Filename: "compose.yaml", Config: dict}},
+	}, func(options *loader.Options) {
+		options.Name = "demo"
+	})
+	assert.NilError(t, err)
+	return project
+}
+
+func TestApplyOverrides(t *testing.T) {
+	project := loadProject(t, `
+services:
+  web:
+    image: nginx
+    ports:
+      - 80:80
+    environment:
+      MODE: production
+    deploy:
+      replicas: 1
+    healthcheck:
+      test: ["CMD", "true"]
+      interval: 30s
+    x-aws-pull_credentials: secret
+  api:
+    image: foo:1.0
+    volumes:
+      - data:/data
+volumes:
+  data: {}
+x-aws-cluster: production
+`)
+	overridden, err := ApplyOverrides(project, []string{
+		"services.web.deploy.replicas=5",
+		"services.api.image=foo:1.2",
+		`services.api.environment.DEBUG="true"`,
+	}, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, overridden.Name, "demo")
+	assert.Equal(t, overridden.WorkingDir, "/src")
+	assert.Equal(t, overridden.Extensions["x-aws-cluster"], "production")
+
+	web, err := overridden.GetService("web")
+	assert.NilError(t, err)
+	assert.Equal(t, *web.Deploy.Replicas, uint64(5))
+	assert.Equal(t, web.Image, "nginx")
+	assert.Equal(t, web.Ports[0].Published, uint32(80))
+	assert.Equal(t, *web.Environment["MODE"], "production")
+	assert.Equal(t, web.HealthCheck.Interval.String(), "30s")
+	assert.Equal(t, web.Extensions["x-aws-pull_credentials"], "secret")
+
+	api, err := overridden.GetService("api")
+	assert.NilError(t, err)
+	assert.Equal(t, api.Image, "foo:1.2")
+	assert.Equal(t, *api.Environment["DEBUG"], "true")
+	assert.Equal(t, api.Volumes[0].Source, "data")
+}
+
+func TestApplyOverridesErrors(t *testing.T) {
+	project := loadProject(t, `
+services:
+  web:
+    image: nginx
+`)
+	_, err := ApplyOverrides(project, []string{"services.db.image=postgres"}, nil)
+	assert.Assert(t, errdefs.IsNotFoundError(err))
+
+	_, err = ApplyOverrides(project, []string{"services.web.image"}, nil)
+	assert.Assert(t, errdefs.IsErrParsingFailed(err))
+
+	_, err = ApplyOverrides(project, []string{"services..image=nginx"}, nil)
+	assert.Assert(t, errdefs.IsErrParsingFailed(err))
+}
@@ -0,0 +1,118 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// Node attributes deploy.placement constraints and preferences can refer to
+const (
+	// ZoneAttribute is the availability zone service tasks run in
+	ZoneAttribute = "node.zone"
+	// InstanceTypeAttribute is the machine type service tasks run on
+	InstanceTypeAttribute = "node.instance_type"
+)
+
+// PlacementConstraint restricts the nodes a service runs on to the ones whose attribute is, or isn't, equal to a value
+type PlacementConstraint struct {
+	Attribute string
+	Equal     bool
+	Value     string
+}
+
+// Matches returns true if the value satisfies the constraint
+func (c PlacementConstraint) Matches(value string) bool {
+	return (value == c.Value) == c.Equal
+}
+
+// PlacementConstraints parses the service deploy.placement.constraints, as `attribute == value` or `attribute != value`
+func PlacementConstraints(service types.ServiceConfig) ([]PlacementConstraint, error) {
+	if service.Deploy == nil {
+		return nil, nil
+	}
+	var constraints []PlacementConstraint
+	for _, expression := range service.Deploy.Placement.Constraints {
+		constraint, err := parsePlacementConstraint(expression)
+		if err != nil {
+			return nil, errors.Wrapf(err, "service %q", service.Name)
+		}
+		constraints = append(constraints, constraint)
+	}
+	return constraints, nil
+}
+
+func parsePlacementConstraint(expression string) (PlacementConstraint, error) {
+	for _, op := range []string{"==", "!="} {
+		parts := strings.SplitN(expression, op, 2)
+		if len(parts) != 2 {
+			continue
+		}
+		constraint := PlacementConstraint{
+			Attribute: strings.TrimSpace(parts[0]),
+			Equal:     op == "==",
+			Value:     strings.TrimSpace(parts[1]),
+		}
+		switch constraint.Attribute {
+		case ZoneAttribute, InstanceTypeAttribute:
+		default:
+			return PlacementConstraint{}, errors.Wrapf(errdefs.ErrNotImplemented, "placement constraint on %q, only %s and %s are supported", constraint.Attribute, ZoneAttribute, InstanceTypeAttribute)
+		}
+		if constraint.Value == "" {
+			break
+		}
+		return constraint, nil
+	}
+	return PlacementConstraint{}, errors.Wrapf(errdefs.ErrParsingFailed, "invalid placement constraint %q, expected attribute == value or attribute != value", expression)
+}
+
+// SpreadZones returns true if the service deploy.placement.preferences spread its tasks across availability zones
+func SpreadZones(service types.ServiceConfig) (bool, error) {
+	if service.Deploy == nil {
+		return false, nil
+	}
+	spread := false
+	for _, preference := range service.Deploy.Placement.Preferences {
+		if preference.Spread != ZoneAttribute {
+			return false, errors.Wrapf(errdefs.ErrNotImplemented, "service %q spreads tasks by %q, only %s is supported", service.Name, preference.Spread, ZoneAttribute)
+		}
+		spread = true
+	}
+	return spread, nil
+}
+
+// AllowedZones returns the zones satisfying the zone constraints, all of them when there are none
+func AllowedZones(constraints []PlacementConstraint, zones []string) []string {
+	var allowed []string
+	for _, zone := range zones {
+		ok := true
+		for _, c := range constraints {
+			if c.Attribute == ZoneAttribute && !c.Matches(zone) {
+				ok = false
+			}
+		}
+		if ok {
+			allowed = append(allowed, zone)
+		}
+	}
+	return allowed
+}
@@ -0,0 +1,65 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func placedService(constraints []string, spread ...string) types.ServiceConfig {
+	placement := types.Placement{Constraints: constraints}
+	for _, s := range spread {
+		placement.Preferences = append(placement.Preferences, types.PlacementPreferences{Spread: s})
+	}
+	return types.ServiceConfig{Name: "db", Deploy: &types.DeployConfig{Placement: placement}}
+}
+
+func TestPlacementConstraints(t *testing.T) {
+	constraints, err := PlacementConstraints(placedService([]string{"node.zone == eu-west-3a", "node.instance_type!=t3.micro"}))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, constraints, []PlacementConstraint{
+		{Attribute: ZoneAttribute, Equal: true, Value: "eu-west-3a"},
+		{Attribute: InstanceTypeAttribute, Equal: false, Value: "t3.micro"},
+	})
+
+	_, err = PlacementConstraints(placedService([]string{"node.labels.disk == ssd"}))
+	assert.Assert(t, errdefs.IsErrNotImplemented(err))
+	_, err = PlacementConstraints(placedService([]string{"node.zone"}))
+	assert.Assert(t, errdefs.IsErrParsingFailed(err))
+}
+
+func TestAllowedZones(t *testing.T) {
+	zones := []string{"1", "2", "3"}
+	constraints, err := PlacementConstraints(placedService([]string{"node.zone != 2"}))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, AllowedZones(constraints, zones), []string{"1", "3"})
+	assert.DeepEqual(t, AllowedZones(nil, zones), zones)
+}
+
+func TestSpreadZones(t *testing.T) {
+	spread, err := SpreadZones(placedService(nil, "node.zone"))
+	assert.NilError(t, err)
+	assert.Assert(t, spread)
+
+	_, err = SpreadZones(placedService(nil, "node.labels.rack"))
+	assert.Assert(t, errdefs.IsErrNotImplemented(err))
+}
@@ -0,0 +1,69 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/containerd/containerd/platforms"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// ServicePlatform returns the normalized platform of a service, the default one if the service doesn't set any
+func ServicePlatform(service types.ServiceConfig, defaultPlatform string) (string, error) {
+	platform := service.Platform
+	if platform == "" {
+		platform = defaultPlatform
+	}
+	p, err := platforms.Parse(platform)
+	if err != nil {
+		return "", errors.Wrapf(errdefs.ErrParsingFailed, "invalid platform %q for service %q", platform, service.Name)
+	}
+	return platforms.Format(platforms.Normalize(p)), nil
+}
+
+// SinglePlatform returns the platform services running together, as a container group or a task, all run on.
+// When services don't share the same platform, the error lists the services of each platform.
+func SinglePlatform(group string, services []types.ServiceConfig, defaultPlatform string) (string, error) {
+	byPlatform := map[string][]string{}
+	for _, service := range services {
+		platform, err := ServicePlatform(service, defaultPlatform)
+		if err != nil {
+			return "", err
+		}
+		byPlatform[platform] = append(byPlatform[platform], service.Name)
+	}
+	if len(byPlatform) == 1 {
+		for platform := range byPlatform {
+			return platform, nil
+		}
+	}
+	if len(byPlatform) == 0 {
+		return defaultPlatform, nil
+	}
+	var mixed []string
+	for platform, names := range byPlatform {
+		mixed = append(mixed, fmt.Sprintf("%s (%s)", platform, strings.Join(names, ", ")))
+	}
+	sort.Strings(mixed)
+	return "", errors.Wrapf(errdefs.ErrNotImplemented, "%s can't mix platforms: %s", group, strings.Join(mixed, ", "))
+}
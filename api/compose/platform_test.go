@@ -0,0 +1,50 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestSinglePlatform(t *testing.T) {
+	platform, err := SinglePlatform("task", []types.ServiceConfig{
+		{Name: "web"},
+		{Name: "logs", Platform: "linux/x86_64"},
+	}, "linux/amd64")
+	assert.NilError(t, err)
+	assert.Equal(t, platform, "linux/amd64")
+
+	platform, err = SinglePlatform("task", []types.ServiceConfig{{Name: "web", Platform: "linux/aarch64"}}, "linux/amd64")
+	assert.NilError(t, err)
+	assert.Equal(t, platform, "linux/arm64")
+
+	_, err = SinglePlatform("container group", []types.ServiceConfig{
+		{Name: "web", Platform: "linux/arm64"},
+		{Name: "api", Platform: "linux/arm64"},
+		{Name: "db"},
+	}, "linux/amd64")
+	assert.Assert(t, errdefs.IsErrNotImplemented(err))
+	assert.ErrorContains(t, err, "container group can't mix platforms: linux/amd64 (db), linux/arm64 (web, api)")
+
+	_, err = SinglePlatform("task", []types.ServiceConfig{{Name: "web", Platform: "linux/"}}, "linux/amd64")
+	assert.ErrorContains(t, err, `invalid platform "linux/" for service "web"`)
+}
@@ -0,0 +1,61 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/docker/compose-cli/progress"
+)
+
+// PreflightCheck verifies a requirement of the deployment before any resource is created
+type PreflightCheck func(ctx context.Context) error
+
+// Preflight runs checks concurrently, and reports all the problems they found at once
+func Preflight(ctx context.Context, checks ...PreflightCheck) error {
+	w := progress.ContextWriter(ctx)
+	w.Event(progress.Event{ID: "Pre-flight checks", Status: progress.Working})
+
+	results := make([]error, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		i, check := i, check
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = check(ctx)
+		}()
+	}
+	wg.Wait()
+
+	var errs *multierror.Error
+	for _, err := range results {
+		if err != nil {
+			// errors of checks reporting several problems are flattened
+			errs = multierror.Append(errs, err)
+		}
+	}
+	if errs != nil {
+		w.Event(progress.Event{ID: "Pre-flight checks", Status: progress.Error, StatusText: "Failed"})
+		return ValidationErrors(errs)
+	}
+	w.Event(progress.Event{ID: "Pre-flight checks", Status: progress.Done, StatusText: "Passed"})
+	return nil
+}
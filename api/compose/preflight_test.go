@@ -0,0 +1,48 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+)
+
+func TestPreflightReportsAllProblems(t *testing.T) {
+	err := Preflight(context.Background(),
+		func(context.Context) error {
+			return ValidationErrors(multierror.Append(nil, errors.New("first"), errors.New("second")))
+		},
+		func(context.Context) error {
+			return nil
+		},
+		func(context.Context) error {
+			return errors.New("third")
+		},
+	)
+	assert.Error(t, err, "first\nsecond\nthird")
+}
+
+func TestPreflightPasses(t *testing.T) {
+	err := Preflight(context.Background(), func(context.Context) error {
+		return nil
+	})
+	assert.NilError(t, err)
+}
@@ -0,0 +1,37 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"github.com/docker/compose-cli/utils"
+)
+
+// Orphans returns the resources labelled with a project which isn't part of the deployed projects.
+// When options set a list of resources, only these are returned.
+func Orphans(resources []OrphanResource, deployed []string, options PruneOptions) []OrphanResource {
+	orphans := []OrphanResource{}
+	for _, r := range resources {
+		if utils.StringContains(deployed, r.Project) {
+			continue
+		}
+		if len(options.Resources) > 0 && !utils.StringContains(options.Resources, r.ID) {
+			continue
+		}
+		orphans = append(orphans, r)
+	}
+	return orphans
+}
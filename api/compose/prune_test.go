@@ -0,0 +1,36 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestOrphans(t *testing.T) {
+	resources := []OrphanResource{
+		{ID: "lb1", Type: "load balancer", Project: "deployed"},
+		{ID: "lb2", Type: "load balancer", Project: "removed"},
+		{ID: "logs", Type: "log group", Project: "removed"},
+	}
+	orphans := Orphans(resources, []string{"deployed"}, PruneOptions{})
+	assert.DeepEqual(t, orphans, resources[1:])
+
+	orphans = Orphans(resources, []string{"deployed"}, PruneOptions{Resources: []string{"logs", "lb1"}})
+	assert.DeepEqual(t, orphans, resources[2:])
+}
@@ -0,0 +1,81 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"io/ioutil"
+	"path"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+	"github.com/sanathkr/go-yaml"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// PublishableFile renders the compose file of a project distributed to other users, with the files it refers to
+// indexed by their path relative to the compose file. Environment variables are already resolved, so env files
+// aren't needed anymore. Images can't be built nor secrets read from local files where the application gets deployed.
+func PublishableFile(project *types.Project) ([]byte, map[string][]byte, error) {
+	published := *project
+	published.Services = make(types.Services, len(project.Services))
+	for i, service := range project.Services {
+		if service.Build != nil {
+			if service.Image == "" {
+				return nil, nil, errors.Wrapf(errdefs.ErrParsingFailed, "service %q builds its image, push it and set the service image to publish the application", service.Name)
+			}
+			service.Build = nil
+		}
+		service.EnvFile = nil
+		for _, volume := range service.Volumes {
+			if volume.Type == types.VolumeTypeBind {
+				logrus.Warnf("service %q bind mounts %s, which won't exist where the application is deployed", service.Name, volume.Source)
+			}
+		}
+		published.Services[i] = service
+	}
+
+	for name, secret := range project.Secrets {
+		if secret.File != "" {
+			return nil, nil, errors.Wrapf(errdefs.ErrParsingFailed, "secret %q is read from a local file, declare it as external to publish the application", name)
+		}
+	}
+
+	attachments := map[string][]byte{}
+	if len(project.Configs) > 0 {
+		published.Configs = types.Configs{}
+		for name, config := range project.Configs {
+			if config.File != "" {
+				content, err := ioutil.ReadFile(config.File)
+				if err != nil {
+					return nil, nil, err
+				}
+				attachment := path.Join("configs", name)
+				attachments[attachment] = content
+				config.File = "./" + attachment
+			}
+			published.Configs[name] = config
+		}
+	}
+
+	content, err := yaml.Marshal(ComposeFile(&published))
+	if err != nil {
+		return nil, nil, err
+	}
+	return content, attachments, nil
+}
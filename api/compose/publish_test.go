@@ -0,0 +1,74 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestPublishableFile(t *testing.T) {
+	dir := fs.NewDir(t, "app", fs.WithFile("nginx.conf", "server {}\n"))
+	defer dir.Remove()
+	project := &types.Project{
+		Name: "app",
+		Services: types.Services{
+			{
+				Name:    "web",
+				Image:   "vendor/web:1.0",
+				Build:   &types.BuildConfig{Context: dir.Path()},
+				EnvFile: types.StringList{filepath.Join(dir.Path(), ".env")},
+			},
+		},
+		Configs: types.Configs{
+			"nginx": types.ConfigObjConfig{File: filepath.Join(dir.Path(), "nginx.conf")},
+		},
+	}
+
+	content, attachments, err := PublishableFile(project)
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), `configs:
+  nginx:
+    file: ./configs/nginx
+services:
+  web:
+    image: vendor/web:1.0
+`)
+	assert.DeepEqual(t, attachments, map[string][]byte{"configs/nginx": []byte("server {}\n")})
+	// the published project is a copy
+	assert.Assert(t, project.Services[0].Build != nil)
+}
+
+func TestPublishableFileErrors(t *testing.T) {
+	_, _, err := PublishableFile(&types.Project{
+		Services: types.Services{{Name: "web", Build: &types.BuildConfig{Context: "."}}},
+	})
+	assert.Assert(t, errdefs.IsErrParsingFailed(err))
+	assert.ErrorContains(t, err, "builds its image")
+
+	_, _, err = PublishableFile(&types.Project{
+		Services: types.Services{{Name: "web", Image: "nginx"}},
+		Secrets:  types.Secrets{"token": types.SecretConfig{File: "./token.txt"}},
+	})
+	assert.ErrorContains(t, err, "declare it as external")
+}
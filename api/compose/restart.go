@@ -0,0 +1,115 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// restart conditions, the same as the containers API ones which can't be imported here
+const (
+	restartAny       = "any"
+	restartNone      = "none"
+	restartOnFailure = "on-failure"
+)
+
+// RestartPolicy is the deploy.restart_policy of a service, with compose defaults applied
+type RestartPolicy struct {
+	// Condition is one of any, none or on-failure
+	Condition string
+	// Delay is the time waited between restarts
+	Delay time.Duration
+	// MaxAttempts is the number of restarts allowed, unlimited when zero
+	MaxAttempts int
+	// Window is how long a run must last to be considered successful, its failure then isn't counted as an attempt
+	Window time.Duration
+}
+
+// ServiceRestartPolicy returns the restart policy of a service. Services restart on any condition by default, while
+// jobs run once unless their restart policy is set.
+func ServiceRestartPolicy(service types.ServiceConfig) (RestartPolicy, error) {
+	policy := RestartPolicy{Condition: restartAny}
+	if IsJob(service) || IsScheduled(service) {
+		policy.Condition = restartNone
+	}
+	if service.Deploy == nil || service.Deploy.RestartPolicy == nil {
+		return policy, nil
+	}
+	restart := service.Deploy.RestartPolicy
+	switch restart.Condition {
+	case "":
+	case restartNone, restartAny, restartOnFailure:
+		policy.Condition = restart.Condition
+	default:
+		return policy, errors.Wrapf(errdefs.ErrParsingFailed, "service %q: unsupported deploy.restart_policy.condition %q", service.Name, restart.Condition)
+	}
+	if restart.Condition == "" && (IsJob(service) || IsScheduled(service)) {
+		// a job with a restart policy but no condition retries on failure
+		policy.Condition = restartOnFailure
+	}
+	if restart.Delay != nil {
+		policy.Delay = time.Duration(*restart.Delay)
+	}
+	if restart.MaxAttempts != nil {
+		policy.MaxAttempts = int(*restart.MaxAttempts)
+	}
+	if restart.Window != nil {
+		policy.Window = time.Duration(*restart.Window)
+	}
+	return policy, nil
+}
+
+// Retries returns true when a run which failed after the given number of restarts can be restarted again
+func (p RestartPolicy) Retries(attempts int) bool {
+	if p.Condition == restartNone {
+		return false
+	}
+	return p.MaxAttempts == 0 || attempts < p.MaxAttempts
+}
+
+// RunWithRestarts runs a job until it succeeds or its restart policy gives up, returning the last failure. Jobs run to
+// completion, so both the `any` and `on-failure` conditions restart them on failure only. Errors other than
+// JobFailedError aren't retried, as they don't come from the job itself.
+func RunWithRestarts(ctx context.Context, policy RestartPolicy, run func(ctx context.Context, attempt int) error) error {
+	attempts := 0
+	for {
+		started := time.Now()
+		err := run(ctx, attempts)
+		var failed JobFailedError
+		if err == nil || !errors.As(err, &failed) {
+			return err
+		}
+		if policy.Window > 0 && time.Since(started) > policy.Window {
+			attempts = 0
+		}
+		if !policy.Retries(attempts) {
+			return err
+		}
+		attempts++
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(policy.Delay):
+		}
+	}
+}
@@ -0,0 +1,81 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestServiceRestartPolicy(t *testing.T) {
+	policy, err := ServiceRestartPolicy(types.ServiceConfig{Name: "web"})
+	assert.NilError(t, err)
+	assert.Equal(t, policy.Condition, "any")
+
+	policy, err = ServiceRestartPolicy(types.ServiceConfig{Name: "migrate", Deploy: &types.DeployConfig{Mode: JobMode}})
+	assert.NilError(t, err)
+	assert.Equal(t, policy.Condition, "none")
+
+	attempts := uint64(3)
+	delay := types.Duration(time.Second)
+	policy, err = ServiceRestartPolicy(types.ServiceConfig{Name: "migrate", Deploy: &types.DeployConfig{
+		Mode:          JobMode,
+		RestartPolicy: &types.RestartPolicy{MaxAttempts: &attempts, Delay: &delay},
+	}})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, policy, RestartPolicy{Condition: "on-failure", MaxAttempts: 3, Delay: time.Second})
+
+	_, err = ServiceRestartPolicy(types.ServiceConfig{Name: "web", Deploy: &types.DeployConfig{
+		RestartPolicy: &types.RestartPolicy{Condition: "always"},
+	}})
+	assert.ErrorContains(t, err, `unsupported deploy.restart_policy.condition "always"`)
+}
+
+func TestRunWithRestarts(t *testing.T) {
+	runs := 0
+	err := RunWithRestarts(context.Background(), RestartPolicy{Condition: "on-failure", MaxAttempts: 2}, func(ctx context.Context, restarts int) error {
+		assert.Equal(t, restarts, runs)
+		runs++
+		return JobFailedError{Job: "migrate", ExitCode: 1}
+	})
+	assert.ErrorContains(t, err, "exit code 1")
+	assert.Equal(t, runs, 3)
+
+	runs = 0
+	err = RunWithRestarts(context.Background(), RestartPolicy{Condition: "any"}, func(ctx context.Context, restarts int) error {
+		runs++
+		if runs < 4 {
+			return JobFailedError{Job: "migrate", ExitCode: 1}
+		}
+		return nil
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, runs, 4)
+
+	runs = 0
+	err = RunWithRestarts(context.Background(), RestartPolicy{Condition: "any"}, func(ctx context.Context, restarts int) error {
+		runs++
+		return errors.New("no such cluster")
+	})
+	assert.ErrorContains(t, err, "no such cluster")
+	assert.Equal(t, runs, 1)
+}
@@ -0,0 +1,29 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// InterruptedError is returned by Up when a deployment left in progress by a previous command is still running
+func InterruptedError(resource string, state string) error {
+	return errors.Wrapf(errdefs.ErrLocked, "%s is %s, a previous command may have been interrupted: "+
+		"run `docker compose up --resume` to wait for it to complete and resume the deployment", resource, state)
+}
@@ -0,0 +1,193 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const (
+	// ScheduleExtension deploys a service as a job run on a cron schedule, e.g. x-schedule: "cron(0 3 * * *)"
+	ScheduleExtension = "x-schedule"
+	// ScheduleLabel declares the schedule of containers started by the local scheduler of `docker serve`
+	ScheduleLabel = "com.docker.compose.schedule"
+)
+
+// Schedule is a cron schedule, with minute, hour, day of month, month and day of week fields
+type Schedule struct {
+	// Fields are the five cron fields, as written in the expression
+	Fields [5]string
+	sets   [5]map[int]bool
+}
+
+var scheduleRanges = [5]struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 6},
+}
+
+var cronExpression = regexp.MustCompile(`^cron\((.*)\)$`)
+
+// ParseSchedule parses a cron expression, as `cron(0 3 * * *)` or `0 3 * * *`. Fields accept `*`, values,
+// ranges, lists and steps, days of week going from 0 (Sunday) to 6.
+func ParseSchedule(expression string) (Schedule, error) {
+	expr := strings.TrimSpace(expression)
+	if m := cronExpression.FindStringSubmatch(expr); m != nil {
+		expr = m[1]
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return Schedule{}, errors.Wrapf(errdefs.ErrParsingFailed, "invalid schedule %q: expected 5 fields (minute hour day-of-month month day-of-week)", expression)
+	}
+	var s Schedule
+	for i, field := range fields {
+		s.Fields[i] = field
+		set, err := parseScheduleField(field, scheduleRanges[i].min, scheduleRanges[i].max)
+		if err != nil {
+			return Schedule{}, errors.Wrapf(errdefs.ErrParsingFailed, "invalid schedule %q: %s %s", expression, scheduleRanges[i].name, err)
+		}
+		s.sets[i] = set
+	}
+	return s, nil
+}
+
+// parseScheduleField returns the values a field matches, or nil if it matches any value
+func parseScheduleField(field string, min, max int) (map[int]bool, error) {
+	if field == "*" {
+		return nil, nil
+	}
+	set := map[int]bool{}
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			s, err := strconv.Atoi(part[i+1:])
+			if err != nil || s <= 0 {
+				return nil, fmt.Errorf("has invalid step %q", part[i+1:])
+			}
+			step = s
+			part = part[:i]
+		}
+		from, to := min, max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			v, err := strconv.Atoi(bounds[0])
+			if err != nil {
+				return nil, fmt.Errorf("has invalid value %q", bounds[0])
+			}
+			from, to = v, v
+			if len(bounds) == 2 {
+				if to, err = strconv.Atoi(bounds[1]); err != nil {
+					return nil, fmt.Errorf("has invalid value %q", bounds[1])
+				}
+			} else if step > 1 {
+				to = max
+			}
+		}
+		if from < min || to > max || from > to {
+			return nil, fmt.Errorf("%q is out of range %d-%d", part, min, max)
+		}
+		for v := from; v <= to; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+// Values returns the values the field at index matches, or nil if it matches any value
+func (s Schedule) Values(index int) []int {
+	set := s.sets[index]
+	if set == nil {
+		return nil
+	}
+	var values []int
+	for v := scheduleRanges[index].min; v <= scheduleRanges[index].max; v++ {
+		if set[v] {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// Matches returns true if the schedule runs at the minute of t. As with cron, when both days of month and
+// days of week are restricted, a day matching either of them matches.
+func (s Schedule) Matches(t time.Time) bool {
+	match := func(i, v int) bool {
+		return s.sets[i] == nil || s.sets[i][v]
+	}
+	if !match(0, t.Minute()) || !match(1, t.Hour()) || !match(3, int(t.Month())) {
+		return false
+	}
+	dom, dow := match(2, t.Day()), match(4, int(t.Weekday()))
+	if s.sets[2] != nil && s.sets[4] != nil {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+// Next returns the next time after t the schedule runs, or the zero time if it never does
+func (s Schedule) Next(t time.Time) time.Time {
+	next := t.Truncate(time.Minute).Add(time.Minute)
+	// a schedule runs at least once every leap year
+	for limit := next.AddDate(5, 0, 0); next.Before(limit); next = next.Add(time.Minute) {
+		if s.Matches(next) {
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// String returns the schedule as a cron expression
+func (s Schedule) String() string {
+	return fmt.Sprintf("cron(%s)", strings.Join(s.Fields[:], " "))
+}
+
+// ServiceSchedule returns the schedule of a service declared by the x-schedule extension, or nil if it isn't scheduled
+func ServiceSchedule(service types.ServiceConfig) (*Schedule, error) {
+	x, ok := service.Extensions[ScheduleExtension]
+	if !ok {
+		return nil, nil
+	}
+	expression, ok := x.(string)
+	if !ok {
+		return nil, errors.Wrapf(errdefs.ErrParsingFailed, "%s of service %q must be a cron expression", ScheduleExtension, service.Name)
+	}
+	schedule, err := ParseSchedule(expression)
+	if err != nil {
+		return nil, errors.Wrapf(err, "service %q", service.Name)
+	}
+	return &schedule, nil
+}
+
+// IsScheduled returns true if the service runs on a schedule instead of being kept running
+func IsScheduled(service types.ServiceConfig) bool {
+	_, ok := service.Extensions[ScheduleExtension]
+	return ok
+}
@@ -0,0 +1,73 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestParseSchedule(t *testing.T) {
+	s, err := ParseSchedule("cron(*/15 3,4 1-7 * 1-5)")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, s.Values(0), []int{0, 15, 30, 45})
+	assert.DeepEqual(t, s.Values(1), []int{3, 4})
+	assert.DeepEqual(t, s.Values(2), []int{1, 2, 3, 4, 5, 6, 7})
+	assert.Assert(t, s.Values(3) == nil)
+	assert.DeepEqual(t, s.Values(4), []int{1, 2, 3, 4, 5})
+	assert.Equal(t, s.String(), "cron(*/15 3,4 1-7 * 1-5)")
+}
+
+func TestParseScheduleErrors(t *testing.T) {
+	_, err := ParseSchedule("0 3 * *")
+	assert.Assert(t, errdefs.IsErrParsingFailed(err))
+	_, err = ParseSchedule("cron(0 25 * * *)")
+	assert.ErrorContains(t, err, `hour "25" is out of range 0-23`)
+	_, err = ParseSchedule("cron(0 3 * * */0)")
+	assert.ErrorContains(t, err, `day of week has invalid step "0"`)
+}
+
+func TestScheduleNext(t *testing.T) {
+	s, err := ParseSchedule("cron(0 3 * * *)")
+	assert.NilError(t, err)
+	now := time.Date(2020, 10, 14, 3, 0, 30, 0, time.UTC)
+	assert.Equal(t, s.Next(now), time.Date(2020, 10, 15, 3, 0, 0, 0, time.UTC))
+
+	// Sundays or the first of the month
+	s, err = ParseSchedule("0 0 1 * 0")
+	assert.NilError(t, err)
+	assert.Equal(t, s.Next(now), time.Date(2020, 10, 18, 0, 0, 0, 0, time.UTC))
+	assert.Assert(t, s.Matches(time.Date(2020, 11, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestServiceSchedule(t *testing.T) {
+	s, err := ServiceSchedule(types.ServiceConfig{Name: "backup"})
+	assert.NilError(t, err)
+	assert.Assert(t, s == nil)
+
+	s, err = ServiceSchedule(types.ServiceConfig{Name: "backup", Extensions: map[string]interface{}{"x-schedule": "cron(0 3 * * *)"}})
+	assert.NilError(t, err)
+	assert.Equal(t, s.Fields[1], "3")
+
+	_, err = ServiceSchedule(types.ServiceConfig{Name: "backup", Extensions: map[string]interface{}{"x-schedule": 3}})
+	assert.Assert(t, errdefs.IsErrParsingFailed(err))
+}
@@ -0,0 +1,80 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"path"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const (
+	// SecretInjectionExtension is the service extension selecting how its secrets are provided to containers
+	SecretInjectionExtension = "x-secret-injection"
+	// SecretInjectionFile mounts secrets as files, under /run/secrets or at their absolute target path
+	SecretInjectionFile = "file"
+	// SecretInjectionEnv injects secrets as environment variables named after their target
+	SecretInjectionEnv = "env"
+)
+
+// SecretInjection returns how the secrets of a service are provided to its containers, as files unless overridden with x-secret-injection
+func SecretInjection(service types.ServiceConfig) (string, error) {
+	value, ok := service.Extensions[SecretInjectionExtension]
+	if !ok {
+		return SecretInjectionFile, nil
+	}
+	switch value {
+	case SecretInjectionFile, SecretInjectionEnv:
+		return value.(string), nil
+	}
+	return "", errors.Wrapf(errdefs.ErrParsingFailed, "service %q: %s must be %q or %q, got %v", service.Name, SecretInjectionExtension, SecretInjectionFile, SecretInjectionEnv, value)
+}
+
+// SecretEnvName returns the environment variable a secret is injected as: its target, or its source when target is unset or a path
+func SecretEnvName(secret types.ServiceSecretConfig) string {
+	if secret.Target == "" || strings.Contains(secret.Target, "/") {
+		return secret.Source
+	}
+	return secret.Target
+}
+
+// systemFolders hold image content which is hidden by a volume mounted over them, or below them
+var systemFolders = []string{"/bin", "/boot", "/dev", "/etc", "/lib", "/lib64", "/proc", "/sbin", "/sys", "/usr"}
+
+// CheckSecretTarget rejects secrets which absolute target is in the root folder or a system folder. A secret with an
+// absolute target is mounted by sharing its whole folder with service containers, hiding the image content of this
+// folder, which must then be dedicated to secrets.
+func CheckSecretTarget(secret types.ServiceSecretConfig) error {
+	if !path.IsAbs(secret.Target) {
+		return nil
+	}
+	folder := path.Dir(secret.Target)
+	if folder == "/" {
+		return errors.Wrapf(errdefs.ErrNotImplemented, "secret %q: target %q must be in a folder other than /", secret.Source, secret.Target)
+	}
+	for _, system := range systemFolders {
+		if folder == system || strings.HasPrefix(folder, system+"/") {
+			return errors.Wrapf(errdefs.ErrNotImplemented, "secret %q: target %q would mount a volume over %s, hiding its image content. "+
+				"Use a target under /run/secrets or in a folder dedicated to secrets", secret.Source, secret.Target, folder)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestSecretInjection(t *testing.T) {
+	injection, err := SecretInjection(types.ServiceConfig{Name: "test"})
+	assert.NilError(t, err)
+	assert.Equal(t, injection, SecretInjectionFile)
+
+	injection, err = SecretInjection(types.ServiceConfig{Name: "test", Extensions: map[string]interface{}{SecretInjectionExtension: "env"}})
+	assert.NilError(t, err)
+	assert.Equal(t, injection, SecretInjectionEnv)
+
+	_, err = SecretInjection(types.ServiceConfig{Name: "test", Extensions: map[string]interface{}{SecretInjectionExtension: "volume"}})
+	assert.ErrorContains(t, err, `service "test": x-secret-injection must be "file" or "env", got volume`)
+}
+
+func TestCheckSecretTarget(t *testing.T) {
+	assert.NilError(t, CheckSecretTarget(types.ServiceSecretConfig{Source: "token"}))
+	assert.NilError(t, CheckSecretTarget(types.ServiceSecretConfig{Source: "token", Target: "token.txt"}))
+	assert.NilError(t, CheckSecretTarget(types.ServiceSecretConfig{Source: "token", Target: "/app/secrets/token"}))
+	assert.NilError(t, CheckSecretTarget(types.ServiceSecretConfig{Source: "token", Target: "/etcd/token"}))
+
+	err := CheckSecretTarget(types.ServiceSecretConfig{Source: "ca", Target: "/etc/ssl/certs/ca.pem"})
+	assert.ErrorContains(t, err, `secret "ca": target "/etc/ssl/certs/ca.pem" would mount a volume over /etc/ssl/certs, hiding its image content`)
+	err = CheckSecretTarget(types.ServiceSecretConfig{Source: "token", Target: "/usr/token"})
+	assert.ErrorContains(t, err, "would mount a volume over /usr")
+	err = CheckSecretTarget(types.ServiceSecretConfig{Source: "token", Target: "/token"})
+	assert.ErrorContains(t, err, `target "/token" must be in a folder other than /`)
+}
+
+func TestSecretTargets(t *testing.T) {
+	assert.Equal(t, SecretEnvName(types.ServiceSecretConfig{Source: "token"}), "token")
+	assert.Equal(t, SecretEnvName(types.ServiceSecretConfig{Source: "token", Target: "API_TOKEN"}), "API_TOKEN")
+	assert.Equal(t, SecretEnvName(types.ServiceSecretConfig{Source: "token", Target: "/etc/token"}), "token")
+}
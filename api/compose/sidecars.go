@@ -0,0 +1,120 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"os"
+	"sort"
+	"text/template"
+
+	"github.com/compose-spec/compose-go/loader"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// SidecarsExtension is the project extension declaring auxiliary containers injected into every service, such as log
+// forwarders or service-mesh proxies. Each sidecar is a compose service definition, which string values are templates
+// rendered with the service name as {{.Service}} and the project name as {{.Project}}. A service can opt-out declaring
+// `x-sidecars: false`.
+const SidecarsExtension = "x-sidecars"
+
+type sidecarTemplateData struct {
+	Project string
+	Service string
+}
+
+// Sidecars returns the auxiliary containers to inject into a service, sorted by name
+func Sidecars(project *types.Project, service types.ServiceConfig) ([]types.ServiceConfig, error) {
+	if enabled, ok := service.Extensions[SidecarsExtension]; ok && enabled == false {
+		return nil, nil
+	}
+	ext, ok := project.Extensions[SidecarsExtension]
+	if !ok {
+		return nil, nil
+	}
+	definitions, ok := ext.(map[string]interface{})
+	if !ok {
+		return nil, errors.Wrapf(errdefs.ErrParsingFailed, "%s must be a mapping of sidecar names to service definitions", SidecarsExtension)
+	}
+	names := make([]string, 0, len(definitions))
+	for name := range definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	data := sidecarTemplateData{Project: project.Name, Service: service.Name}
+	var sidecars []types.ServiceConfig
+	for _, name := range names {
+		rendered, err := renderSidecar(definitions[name], data)
+		if err != nil {
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "sidecar %q: %s", name, err)
+		}
+		dict, ok := rendered.(map[string]interface{})
+		if !ok {
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "sidecar %q must be a service definition", name)
+		}
+		sidecar, err := loader.LoadService(name, dict, project.WorkingDir, os.LookupEnv)
+		if err != nil {
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "sidecar %q: %s", name, err)
+		}
+		if sidecar.Image == "" {
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "sidecar %q has no image", name)
+		}
+		sidecars = append(sidecars, *sidecar)
+	}
+	return sidecars, nil
+}
+
+// renderSidecar executes string values of a sidecar definition as templates
+func renderSidecar(value interface{}, data sidecarTemplateData) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		tmpl, err := template.New("sidecar").Option("missingkey=error").Parse(v)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, err
+		}
+		return buf.String(), nil
+	case map[string]interface{}:
+		rendered := map[string]interface{}{}
+		for key, item := range v {
+			r, err := renderSidecar(item, data)
+			if err != nil {
+				return nil, err
+			}
+			rendered[key] = r
+		}
+		return rendered, nil
+	case []interface{}:
+		rendered := make([]interface{}, len(v))
+		for i, item := range v {
+			r, err := renderSidecar(item, data)
+			if err != nil {
+				return nil, err
+			}
+			rendered[i] = r
+		}
+		return rendered, nil
+	}
+	return value, nil
+}
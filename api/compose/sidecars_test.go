@@ -0,0 +1,76 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestSidecars(t *testing.T) {
+	project := &types.Project{
+		Name: "demo",
+		Extensions: map[string]interface{}{
+			SidecarsExtension: map[string]interface{}{
+				"proxy": map[string]interface{}{
+					"image":       "envoyproxy/envoy",
+					"command":     []interface{}{"--service-node", "{{.Project}}-{{.Service}}"},
+					"environment": map[string]interface{}{"SERVICE": "{{.Service}}"},
+				},
+				"logs": map[string]interface{}{
+					"image": "fluent/fluent-bit",
+				},
+			},
+		},
+	}
+	sidecars, err := Sidecars(project, types.ServiceConfig{Name: "web"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(sidecars), 2)
+	assert.Equal(t, sidecars[0].Name, "logs")
+	assert.Equal(t, sidecars[0].Image, "fluent/fluent-bit")
+	assert.Equal(t, sidecars[1].Name, "proxy")
+	assert.DeepEqual(t, []string(sidecars[1].Command), []string{"--service-node", "demo-web"})
+	assert.Equal(t, *sidecars[1].Environment["SERVICE"], "web")
+
+	sidecars, err = Sidecars(project, types.ServiceConfig{Name: "db", Extensions: map[string]interface{}{SidecarsExtension: false}})
+	assert.NilError(t, err)
+	assert.Equal(t, len(sidecars), 0)
+}
+
+func TestSidecarsInvalid(t *testing.T) {
+	project := &types.Project{
+		Extensions: map[string]interface{}{
+			SidecarsExtension: map[string]interface{}{
+				"proxy": map[string]interface{}{
+					"command": "run",
+				},
+			},
+		},
+	}
+	_, err := Sidecars(project, types.ServiceConfig{Name: "web"})
+	assert.ErrorContains(t, err, `sidecar "proxy" has no image`)
+
+	project.Extensions[SidecarsExtension] = map[string]interface{}{
+		"proxy": map[string]interface{}{
+			"image": "envoy:{{.Unknown}}",
+		},
+	}
+	_, err = Sidecars(project, types.ServiceConfig{Name: "web"})
+	assert.ErrorContains(t, err, `sidecar "proxy"`)
+}
@@ -0,0 +1,161 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/progress"
+)
+
+// SmokeTestExtension sets the URLs of a service polled by compose up once deployed, as a string or a list
+const SmokeTestExtension = "x-healthcheck-url"
+
+// DefaultSmokeTestTimeout is the time endpoints have to return a 2xx status once the application is deployed
+const DefaultSmokeTestTimeout = 2 * time.Minute
+
+var (
+	// smokeTestInterval is the delay between two requests to an endpoint which didn't succeed yet
+	smokeTestInterval = 2 * time.Second
+	// smokeTestRequestTimeout bounds a single request, so that a stalled endpoint is retried
+	smokeTestRequestTimeout = 10 * time.Second
+)
+
+// SmokeTest is an endpoint checked once a project is deployed
+type SmokeTest struct {
+	// Service declaring the endpoint with x-healthcheck-url, empty for ones set on the command line
+	Service string
+	URL     string
+}
+
+// SmokeTests returns the endpoints to check after deployment: the given URLs and the ones declared by services
+func SmokeTests(project *types.Project, urls []string) ([]SmokeTest, error) {
+	var tests []SmokeTest
+	for _, u := range urls {
+		if err := checkSmokeTestURL(u); err != nil {
+			return nil, err
+		}
+		tests = append(tests, SmokeTest{URL: u})
+	}
+	for _, service := range project.Services {
+		x, ok := service.Extensions[SmokeTestExtension]
+		if !ok {
+			continue
+		}
+		var values []string
+		switch v := x.(type) {
+		case string:
+			values = []string{v}
+		case []interface{}:
+			for _, item := range v {
+				s, ok := item.(string)
+				if !ok {
+					return nil, errors.Wrapf(errdefs.ErrParsingFailed, "invalid %s for service %s: %v", SmokeTestExtension, service.Name, item)
+				}
+				values = append(values, s)
+			}
+		default:
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "invalid %s for service %s: %v", SmokeTestExtension, service.Name, x)
+		}
+		for _, u := range values {
+			if err := checkSmokeTestURL(u); err != nil {
+				return nil, errors.Wrapf(err, "service %s", service.Name)
+			}
+			tests = append(tests, SmokeTest{Service: service.Name, URL: u})
+		}
+	}
+	return tests, nil
+}
+
+func checkSmokeTestURL(u string) error {
+	parsed, err := url.Parse(u)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.Wrapf(errdefs.ErrParsingFailed, "invalid smoke test URL %q: must be an absolute http(s) URL", u)
+	}
+	return nil
+}
+
+// RunSmokeTests polls the endpoints concurrently until they all return a 2xx status, and fails if one doesn't within
+// timeout
+func RunSmokeTests(ctx context.Context, tests []SmokeTest, timeout time.Duration) error {
+	if len(tests) == 0 {
+		return nil
+	}
+	if timeout <= 0 {
+		timeout = DefaultSmokeTestTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	client := &http.Client{Timeout: smokeTestRequestTimeout}
+	eg, ctx := errgroup.WithContext(ctx)
+	for _, test := range tests {
+		test := test
+		eg.Go(func() error {
+			return smokeTest(ctx, client, test, timeout)
+		})
+	}
+	return eg.Wait()
+}
+
+func smokeTest(ctx context.Context, client *http.Client, test SmokeTest, timeout time.Duration) error {
+	w := progress.ContextWriter(ctx)
+	w.Event(progress.Event{ID: test.URL, Status: progress.Working, StatusText: "Smoke testing"})
+	var last string
+	for {
+		status, err := probe(ctx, client, test.URL)
+		if err == nil && status >= 200 && status < 300 {
+			w.Event(progress.Event{ID: test.URL, Status: progress.Done, StatusText: fmt.Sprintf("Healthy (%d)", status), Type: progress.ServiceHealthy})
+			return nil
+		}
+		if err != nil {
+			last = err.Error()
+		} else {
+			last = fmt.Sprintf("status %d", status)
+		}
+		select {
+		case <-ctx.Done():
+			w.Event(progress.Event{ID: test.URL, Status: progress.Error, StatusText: last})
+			if test.Service != "" {
+				return errors.Errorf("smoke test of service %s failed: %s did not return a 2xx status within %s, last result: %s", test.Service, test.URL, timeout, last)
+			}
+			return errors.Errorf("smoke test failed: %s did not return a 2xx status within %s, last result: %s", test.URL, timeout, last)
+		case <-time.After(smokeTestInterval):
+		}
+	}
+}
+
+func probe(ctx context.Context, client *http.Client, u string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close() // nolint:errcheck
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,73 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestSmokeTests(t *testing.T) {
+	project := &types.Project{Services: []types.ServiceConfig{
+		{Name: "web", Extensions: map[string]interface{}{SmokeTestExtension: "https://web.example.com/health"}},
+		{Name: "api", Extensions: map[string]interface{}{SmokeTestExtension: []interface{}{"http://api.example.com"}}},
+		{Name: "db"},
+	}}
+	tests, err := SmokeTests(project, []string{"https://example.com"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, tests, []SmokeTest{
+		{URL: "https://example.com"},
+		{Service: "web", URL: "https://web.example.com/health"},
+		{Service: "api", URL: "http://api.example.com"},
+	})
+
+	_, err = SmokeTests(&types.Project{}, []string{"/health"})
+	assert.Assert(t, errdefs.IsErrParsingFailed(err))
+}
+
+func TestRunSmokeTests(t *testing.T) {
+	smokeTestInterval = 10 * time.Millisecond
+	defer func() { smokeTestInterval = 2 * time.Second }()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	err := RunSmokeTests(context.Background(), []SmokeTest{{URL: server.URL}}, time.Second)
+	assert.NilError(t, err)
+	assert.Equal(t, atomic.LoadInt32(&calls), int32(3))
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	err = RunSmokeTests(context.Background(), []SmokeTest{{Service: "web", URL: failing.URL}}, 100*time.Millisecond)
+	assert.ErrorContains(t, err, "smoke test of service web failed")
+	assert.ErrorContains(t, err, "last result: status 500")
+}
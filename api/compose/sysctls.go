@@ -0,0 +1,49 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import "strings"
+
+// namespacedSysctls are the kernel parameters containers can set, as they are isolated by the container namespaces
+var namespacedSysctls = []string{
+	"kernel.msgmax",
+	"kernel.msgmnb",
+	"kernel.msgmni",
+	"kernel.sem",
+	"kernel.shmall",
+	"kernel.shmmax",
+	"kernel.shmmni",
+	"kernel.shm_rmid_forced",
+}
+
+// namespacedSysctlPrefixes are the prefixes of the kernel parameter families containers can set
+var namespacedSysctlPrefixes = []string{"fs.mqueue.", "net."}
+
+// IsNamespacedSysctl returns true if a container can set the kernel parameter without affecting its host
+func IsNamespacedSysctl(name string) bool {
+	for _, sysctl := range namespacedSysctls {
+		if name == sysctl {
+			return true
+		}
+	}
+	for _, prefix := range namespacedSysctlPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -23,4 +23,12 @@ const (
 	NetworkTag = "com.docker.compose.network"
 	// ServiceTag allow to track resource related to a compose service
 	ServiceTag = "com.docker.compose.service"
+	// ConfigHashTag allow to track the configuration a compose service has been deployed with
+	ConfigHashTag = "com.docker.compose.config-hash"
+	// ImportedTag marks existing resources imported in a compose project, to be replaced by the next deployment
+	ImportedTag = "com.docker.compose.imported"
+	// ProvenanceTag records the signed artifact a deployment was verified against
+	ProvenanceTag = "com.docker.compose.provenance"
+	// ComposeFileDigestTag records the digest of the compose file of a verified deployment
+	ComposeFileDigestTag = "com.docker.compose.file-digest"
 )
@@ -0,0 +1,31 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+// Values of deploy.update_config order and failure_action
+const (
+	// UpdateOrderStartFirst starts the new tasks before stopping the tasks they replace
+	UpdateOrderStartFirst = "start-first"
+	// UpdateOrderStopFirst stops the tasks before starting their replacement
+	UpdateOrderStopFirst = "stop-first"
+	// UpdateFailureContinue keeps updating when a task fails to start
+	UpdateFailureContinue = "continue"
+	// UpdateFailurePause stops the update when a task fails to start
+	UpdateFailurePause = "pause"
+	// UpdateFailureRollback reverts to the previous configuration when a task fails to start
+	UpdateFailureRollback = "rollback"
+)
@@ -0,0 +1,240 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/progress"
+	"github.com/docker/compose-cli/utils"
+)
+
+const (
+	// DefaultWaitTimeout is the time services have to run their desired replicas with compose up --wait
+	DefaultWaitTimeout = 5 * time.Minute
+	// CrashLoopRestarts is the number of restarts while waiting after which a service is reported as crash looping
+	CrashLoopRestarts = 3
+	// CrashLoopLogLines is the number of log lines of a crash looping service included in the report
+	CrashLoopLogLines = 20
+)
+
+// waitInterval is the delay between two polls of the project services status
+var waitInterval = 5 * time.Second
+
+// CrashLoopError reports a service which containers repeatedly exited while waiting for it to run
+type CrashLoopError struct {
+	Service  string
+	Restarts int
+	ExitCode *int
+	// Logs are the last lines logged by the service, when the backend returns them
+	Logs []string
+}
+
+func (e CrashLoopError) Error() string {
+	msg := fmt.Sprintf("service %s is crash looping: restarted %d times", e.Service, e.Restarts)
+	if e.ExitCode != nil {
+		msg += fmt.Sprintf(", last exit code %d", *e.ExitCode)
+	}
+	if len(e.Logs) > 0 {
+		msg += fmt.Sprintf("\nlast %d log lines:\n  %s", len(e.Logs), strings.Join(e.Logs, "\n  "))
+	}
+	return msg
+}
+
+// WaitForServices polls the project services status until they all run their desired replicas. Services restarting
+// repeatedly are reported with their last exit code and log lines, rather than waiting for the timeout.
+func WaitForServices(ctx context.Context, service Service, project *types.Project, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = DefaultWaitTimeout
+	}
+	w := progress.ContextWriter(ctx)
+	start := time.Now()
+	deadline := start.Add(timeout)
+
+	waiting := map[string]bool{}
+	for _, s := range project.Services {
+		if IsJob(s) || IsScheduled(s) {
+			continue
+		}
+		waiting[s.Name] = true
+		w.Event(progress.Event{ID: s.Name, Status: progress.Working, StatusText: "Waiting"})
+	}
+	// restarts counted before waiting, backends reporting restarts over a period which may precede this deployment
+	baseline := map[string]int{}
+	for first := true; len(waiting) > 0; first = false {
+		status, err := serviceStatus(ctx, service, project.Name)
+		if err != nil {
+			return err
+		}
+		for _, name := range sortedNames(waiting) {
+			s := status[name]
+			if first {
+				baseline[name] = s.Restarts
+			}
+			restarts := s.Restarts - baseline[name]
+			switch {
+			case s.Desired > 0 && s.Replicas >= s.Desired:
+				delete(waiting, name)
+				w.Event(progress.Event{ID: name, Status: progress.Done, StatusText: "Running", Type: progress.ServiceHealthy})
+			case restarts >= CrashLoopRestarts || (restarts > 0 && !time.Now().Before(deadline)):
+				w.Event(progress.Event{ID: name, Status: progress.Error, StatusText: "Crash looping"})
+				return CrashLoopError{
+					Service:  name,
+					Restarts: restarts,
+					ExitCode: s.ExitCode,
+					Logs:     lastLogLines(ctx, service, project.Name, name, start),
+				}
+			case restarts > 0:
+				w.Event(progress.Event{ID: name, Status: progress.Working, StatusText: fmt.Sprintf("Restarting (%d)", restarts)})
+			}
+		}
+		if len(waiting) == 0 {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			names := sortedNames(waiting)
+			for _, name := range names {
+				w.Event(progress.Event{ID: name, Status: progress.Error, StatusText: "Timeout"})
+			}
+			return errors.Errorf("services %s did not run their desired replicas within %s", strings.Join(names, ", "), timeout)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(waitInterval):
+		}
+	}
+	return nil
+}
+
+// ContainerExit is a container of a service which exited while the application was running
+type ContainerExit struct {
+	Service string
+	// ExitCode is the status the container exited with, 1 when the backend doesn't report it
+	ExitCode int
+}
+
+// WaitForContainerExit polls the project services status until a container of a service running continuously exits,
+// which the backends report as a restart, and returns its service and exit code. Only the named services are waited
+// for when some are given. Restarts counted before waiting are ignored.
+func WaitForContainerExit(ctx context.Context, service Service, project *types.Project, names ...string) (ContainerExit, error) {
+	w := progress.ContextWriter(ctx)
+	running := map[string]bool{}
+	for _, s := range project.Services {
+		if IsJob(s) || IsScheduled(s) {
+			continue
+		}
+		if len(names) > 0 && !utils.StringContains(names, s.Name) {
+			continue
+		}
+		running[s.Name] = true
+		w.Event(progress.Event{ID: s.Name, Status: progress.Working, StatusText: "Running"})
+	}
+	if len(running) == 0 {
+		return ContainerExit{}, errors.New("the project has no service running continuously")
+	}
+	baseline := map[string]int{}
+	for first := true; ; first = false {
+		status, err := serviceStatus(ctx, service, project.Name)
+		if err != nil {
+			return ContainerExit{}, err
+		}
+		for _, name := range sortedNames(running) {
+			s := status[name]
+			if first {
+				baseline[name] = s.Restarts
+				continue
+			}
+			if s.Restarts <= baseline[name] {
+				continue
+			}
+			exit := ContainerExit{Service: name, ExitCode: 1}
+			if s.ExitCode != nil {
+				exit.ExitCode = *s.ExitCode
+			}
+			w.Event(progress.Event{ID: name, Status: progress.Done, StatusText: fmt.Sprintf("Exited (%d)", exit.ExitCode)})
+			return exit, nil
+		}
+		select {
+		case <-ctx.Done():
+			return ContainerExit{}, ctx.Err()
+		case <-time.After(waitInterval):
+		}
+	}
+}
+
+// serviceStatus returns the status of the project services by name, summing the ones deployed to several regions
+func serviceStatus(ctx context.Context, service Service, projectName string) (map[string]ServiceStatus, error) {
+	list, err := service.Ps(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+	status := map[string]ServiceStatus{}
+	for _, s := range list {
+		current := status[s.Name]
+		current.Name = s.Name
+		current.Replicas += s.Replicas
+		current.Desired += s.Desired
+		current.Restarts += s.Restarts
+		if s.ExitCode != nil {
+			current.ExitCode = s.ExitCode
+		}
+		status[s.Name] = current
+	}
+	return status, nil
+}
+
+// lastLogLines returns the last lines logged by a service since some time, none if the backend can't return them
+func lastLogLines(ctx context.Context, service Service, projectName string, name string, since time.Time) []string {
+	lines := &tailLogConsumer{service: name, size: CrashLoopLogLines}
+	if err := service.Logs(ctx, projectName, lines, LogOptions{Since: since, Until: time.Now()}); err != nil {
+		return nil
+	}
+	return lines.lines
+}
+
+type tailLogConsumer struct {
+	service string
+	size    int
+	lines   []string
+}
+
+func (t *tailLogConsumer) Log(event LogEvent) {
+	if event.Service != t.service {
+		return
+	}
+	t.lines = append(t.lines, event.Line)
+	if len(t.lines) > t.size {
+		t.lines = t.lines[len(t.lines)-t.size:]
+	}
+}
+
+func sortedNames(set map[string]bool) []string {
+	var names []string
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
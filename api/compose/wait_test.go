@@ -0,0 +1,118 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+type fakeStatusService struct {
+	Service
+	polls [][]ServiceStatus
+	logs  []LogEvent
+}
+
+func (f *fakeStatusService) Ps(ctx context.Context, projectName string) ([]ServiceStatus, error) {
+	status := f.polls[0]
+	if len(f.polls) > 1 {
+		f.polls = f.polls[1:]
+	}
+	return status, nil
+}
+
+func (f *fakeStatusService) Logs(ctx context.Context, projectName string, consumer LogConsumer, options LogOptions) error {
+	for _, event := range f.logs {
+		consumer.Log(event)
+	}
+	return nil
+}
+
+func TestWaitForServices(t *testing.T) {
+	waitInterval = time.Millisecond
+	defer func() { waitInterval = 5 * time.Second }()
+
+	project := &types.Project{Name: "demo", Services: []types.ServiceConfig{
+		{Name: "web"},
+		{Name: "migrate", Extensions: map[string]interface{}{JobExtension: true}},
+	}}
+	service := &fakeStatusService{polls: [][]ServiceStatus{
+		{{Name: "web", Desired: 2, Replicas: 0, Restarts: 4}},
+		{{Name: "web", Desired: 2, Replicas: 1, Restarts: 4}},
+		{{Name: "web", Desired: 2, Replicas: 2, Restarts: 5}},
+	}}
+	err := WaitForServices(context.Background(), service, project, time.Minute)
+	assert.NilError(t, err)
+
+	exitCode := 137
+	service = &fakeStatusService{
+		polls: [][]ServiceStatus{
+			{{Name: "web", Desired: 1, Restarts: 0}},
+			{{Name: "web", Desired: 1, Restarts: 1}},
+			{{Name: "web", Desired: 1, Restarts: 3, ExitCode: &exitCode}},
+		},
+		logs: []LogEvent{
+			{Service: "db", Line: "ready"},
+			{Service: "web", Line: "starting"},
+			{Service: "web", Line: "out of memory"},
+		},
+	}
+	err = WaitForServices(context.Background(), service, project, time.Minute)
+	assert.DeepEqual(t, err, CrashLoopError{
+		Service:  "web",
+		Restarts: 3,
+		ExitCode: &exitCode,
+		Logs:     []string{"starting", "out of memory"},
+	})
+	assert.Error(t, err, "service web is crash looping: restarted 3 times, last exit code 137\nlast 2 log lines:\n  starting\n  out of memory")
+
+	service = &fakeStatusService{polls: [][]ServiceStatus{{{Name: "web", Desired: 1}}}}
+	err = WaitForServices(context.Background(), service, project, time.Millisecond)
+	assert.Error(t, err, "services web did not run their desired replicas within 1ms")
+}
+
+func TestWaitForContainerExit(t *testing.T) {
+	waitInterval = time.Millisecond
+	defer func() { waitInterval = 5 * time.Second }()
+
+	project := &types.Project{Name: "demo", Services: []types.ServiceConfig{{Name: "web"}, {Name: "tests"}}}
+	exitCode := 2
+	service := &fakeStatusService{polls: [][]ServiceStatus{
+		{{Name: "web", Desired: 1, Replicas: 1, Restarts: 1}, {Name: "tests", Desired: 1}},
+		{{Name: "web", Desired: 1, Replicas: 1, Restarts: 1}, {Name: "tests", Desired: 1, Replicas: 1}},
+		{{Name: "web", Desired: 1, Replicas: 1, Restarts: 1}, {Name: "tests", Desired: 1, Restarts: 1, ExitCode: &exitCode}},
+	}}
+	exit, err := WaitForContainerExit(context.Background(), service, project)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, exit, ContainerExit{Service: "tests", ExitCode: 2})
+
+	service = &fakeStatusService{polls: [][]ServiceStatus{
+		{{Name: "web", Desired: 1, Replicas: 1}, {Name: "tests", Desired: 1, Replicas: 1}},
+		{{Name: "web", Desired: 1, Replicas: 1, Restarts: 1}, {Name: "tests", Desired: 1, Replicas: 1}},
+		{{Name: "web", Desired: 1, Replicas: 1, Restarts: 1}, {Name: "tests", Desired: 1, Restarts: 1, ExitCode: &exitCode}},
+	}}
+	exit, err = WaitForContainerExit(context.Background(), service, project, "tests")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, exit, ContainerExit{Service: "tests", ExitCode: 2})
+
+	_, err = WaitForContainerExit(context.Background(), service, &types.Project{Name: "demo"})
+	assert.Error(t, err, "the project has no service running continuously")
+}
@@ -52,6 +52,8 @@ type Container struct {
 	Ports                  []Port         `json:",omitempty"`
 	Platform               string
 	RestartPolicyCondition string
+	// ExitCode is the exit code of a terminated container
+	ExitCode int `json:",omitempty"`
 }
 
 // RuntimeConfig config of a created container
@@ -95,6 +97,50 @@ type ContainerConfig struct {
 	RestartPolicyCondition string
 	// DomainName Container NIS domain name
 	DomainName string
+	// OverrideBudget runs the container even if it exceeds the context budget
+	OverrideBudget bool
+	// Interactive keeps the container running so that an interactive session can be attached to it
+	Interactive bool
+	// Platform the container runs on, e.g. windows/amd64
+	Platform string
+	// Isolation technology of Windows containers: process or hyperv
+	Isolation string
+	// Runtime the engine runs the container with, e.g. runsc to sandbox it with gVisor
+	Runtime string
+	// StopSignal is the signal sent to stop the container, SIGTERM by default
+	StopSignal string
+	// StopTimeout is the number of seconds the container has to exit once stopped before being killed
+	StopTimeout *int
+	// Ulimits are resource limits, as name=soft[:hard]
+	Ulimits []string
+	// Sysctls are the namespaced kernel parameters set in the container
+	Sysctls map[string]string
+	// CapAdd are the Linux capabilities added to the container
+	CapAdd []string
+	// CapDrop are the Linux capabilities dropped from the container
+	CapDrop []string
+	// ReadOnly mounts the container root filesystem as read only
+	ReadOnly bool
+	// Tmpfs are the tmpfs mounts of the container, as path[:options]
+	Tmpfs []string
+	// ShmSize is the size in bytes of /dev/shm, 0 for the engine default
+	ShmSize int64
+	// Devices are the host devices mapped in the container, as host[:container][:permissions]
+	Devices []string
+	// DeviceRequests are the devices, such as GPUs, requested from the engine device drivers
+	DeviceRequests []DeviceRequest
+}
+
+// DeviceRequest requests devices with some capabilities from a device driver
+type DeviceRequest struct {
+	// Driver is the device driver, e.g. nvidia, the engine picks one from the capabilities if empty
+	Driver string
+	// Count is the number of devices requested, -1 for all devices
+	Count int
+	// DeviceIDs are the IDs of the requested devices, instead of a count
+	DeviceIDs []string
+	// Capabilities the devices must all have, e.g. gpu, compute or utility
+	Capabilities []string
 }
 
 // ExecRequest contaiens configuration about an exec request
@@ -140,4 +186,6 @@ type Service interface {
 	Delete(ctx context.Context, containerID string, request DeleteRequest) error
 	// Inspect get a specific container
 	Inspect(ctx context.Context, id string) (Container, error)
+	// PortForward tunnels a local port to a container port until the context is done
+	PortForward(ctx context.Context, containerID string, localPort, remotePort uint32) error
 }
@@ -0,0 +1,62 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package images
+
+import (
+	"context"
+	"time"
+)
+
+// Image is an image stored by the backend
+type Image struct {
+	ID         string
+	Repository string
+	Tag        string
+	Created    time.Time
+	// Size is the size of the image, in bytes
+	Size int64
+	// Dangling images have no repository nor tag left
+	Dangling bool
+}
+
+// ListOptions group options of the List API
+type ListOptions struct {
+	// Dangling only lists dangling images when true, or only tagged images when false
+	Dangling *bool
+}
+
+// RemoveOptions group options of the Remove API
+type RemoveOptions struct {
+	// Force removes the image even if containers use it
+	Force bool
+}
+
+// PullOptions group options of the Pull API
+type PullOptions struct {
+	// Platform pulls the image variant for this platform, as os[/arch[/variant]]
+	Platform string
+}
+
+// Service interacts with the images stored by the backend
+type Service interface {
+	// List returns the stored images
+	List(ctx context.Context, options ListOptions) ([]Image, error)
+	// Remove untags an image, and deletes it once it has no tag left. It returns the untagged and deleted references.
+	Remove(ctx context.Context, image string, options RemoveOptions) ([]string, error)
+	// Pull downloads an image from its registry
+	Pull(ctx context.Context, image string, options PullOptions) error
+}
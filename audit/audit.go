@@ -0,0 +1,171 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// SuccessStatus is the status of a successful operation
+	SuccessStatus = "success"
+	// FailureStatus is the status of a failed operation
+	FailureStatus = "failure"
+	// CanceledStatus is the status of an operation canceled by the user
+	CanceledStatus = "canceled"
+
+	logFileName = "audit.log"
+
+	// MutatingAnnotation marks the commands creating, updating or deleting resources, recorded in the audit log. Its
+	// value is the flag a command is only mutating with, if any.
+	MutatingAnnotation = "audit.mutating"
+)
+
+// Mutating returns the annotations of a command creating, updating or deleting resources
+func Mutating() map[string]string {
+	return map[string]string{MutatingAnnotation: ""}
+}
+
+// MutatingWithFlag returns the annotations of a command creating, updating or deleting resources when a flag is set
+func MutatingWithFlag(flag string) map[string]string {
+	return map[string]string{MutatingAnnotation: flag}
+}
+
+// Entry is a record of the audit log
+type Entry struct {
+	Timestamp   time.Time `json:"timestamp"`
+	Context     string    `json:"context"`
+	ContextType string    `json:"contextType"`
+	Command     string    `json:"command"`
+	Resources   []string  `json:"resources,omitempty"`
+	Status      string    `json:"status"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// LogPath returns the path of the audit log in the config directory
+func LogPath(configDir string) string {
+	return filepath.Join(configDir, logFileName)
+}
+
+// Track records a mutating command in the audit log once it has been executed. Only positional arguments and the
+// project name are recorded as affected resources, flag values may hold credentials.
+func Track(configDir string, contextName string, contextType string, cmd *cobra.Command, status string, err error) error {
+	if cmd == nil || !IsMutating(cmd) {
+		return nil
+	}
+	return TrackAction(configDir, contextName, contextType, commandName(cmd), resources(cmd), status, err)
+}
+
+// TrackAction records an operation mutating resources that is not a command on its own, such as the actions of an
+// interactive command, in the audit log
+func TrackAction(configDir string, contextName string, contextType string, command string, resources []string, status string, err error) error {
+	entry := Entry{
+		Timestamp:   time.Now().UTC(),
+		Context:     contextName,
+		ContextType: contextType,
+		Command:     command,
+		Resources:   resources,
+		Status:      status,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	return Record(configDir, entry)
+}
+
+// IsMutating returns true for commands annotated as creating, updating or deleting resources, with the flag they
+// are mutating with set if any
+func IsMutating(cmd *cobra.Command) bool {
+	flagName, ok := cmd.Annotations[MutatingAnnotation]
+	if !ok {
+		return false
+	}
+	if flagName == "" {
+		return true
+	}
+	flag := cmd.Flags().Lookup(flagName)
+	return flag != nil && flag.Changed && flag.Value.String() != "false"
+}
+
+func commandName(cmd *cobra.Command) string {
+	path := strings.Fields(cmd.CommandPath())
+	if len(path) > 0 {
+		path = path[1:]
+	}
+	return strings.Join(path, " ")
+}
+
+func resources(cmd *cobra.Command) []string {
+	resources := append([]string{}, cmd.Flags().Args()...)
+	if flag := cmd.Flags().Lookup("project-name"); flag != nil && flag.Value.String() != "" {
+		resources = append(resources, "project:"+flag.Value.String())
+	}
+	return resources
+}
+
+// Record appends an entry to the audit log
+func Record(configDir string, entry Entry) error {
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(LogPath(configDir), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	// nolint errcheck
+	defer f.Close()
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// Read returns the audit log entries recorded since a given time, oldest first
+func Read(configDir string, since time.Time) ([]Entry, error) {
+	f, err := os.Open(LogPath(configDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// nolint errcheck
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if entry.Timestamp.Before(since) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
@@ -0,0 +1,98 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package audit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func commandTree(t *testing.T, args ...string) *cobra.Command {
+	root := &cobra.Command{Use: "docker"}
+	compose := &cobra.Command{Use: "compose"}
+	var executed *cobra.Command
+	run := func(cmd *cobra.Command, args []string) { executed = cmd }
+	up := &cobra.Command{Use: "up", Annotations: Mutating(), Run: run}
+	up.Flags().StringP("project-name", "p", "", "")
+	compose.AddCommand(up, &cobra.Command{Use: "ps", Run: run})
+	bootstrap := &cobra.Command{Use: "bootstrap", Annotations: MutatingWithFlag("apply"), Run: run}
+	bootstrap.Flags().Bool("apply", false, "")
+	root.AddCommand(compose, bootstrap, &cobra.Command{Use: "rm", Annotations: Mutating(), Run: run})
+	root.SetArgs(args)
+	assert.NilError(t, root.Execute())
+	return executed
+}
+
+func TestTrackMutatingCommands(t *testing.T) {
+	dir := fs.NewDir(t, "audit").Path()
+
+	assert.NilError(t, Track(dir, "myaci", "aci", commandTree(t, "compose", "ps"), SuccessStatus, nil))
+	assert.NilError(t, Track(dir, "myaci", "aci", commandTree(t, "compose", "up", "-p", "demo"), SuccessStatus, nil))
+	assert.NilError(t, Track(dir, "myecs", "ecs", commandTree(t, "rm", "web", "db"), FailureStatus, errors.New("not found")))
+
+	entries, err := Read(dir, time.Time{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 2)
+	assert.Equal(t, entries[0].Command, "compose up")
+	assert.Equal(t, entries[0].Context, "myaci")
+	assert.DeepEqual(t, entries[0].Resources, []string{"project:demo"})
+	assert.Equal(t, entries[0].Status, SuccessStatus)
+	assert.Equal(t, entries[1].Command, "rm")
+	assert.Equal(t, entries[1].ContextType, "ecs")
+	assert.DeepEqual(t, entries[1].Resources, []string{"web", "db"})
+	assert.Equal(t, entries[1].Error, "not found")
+}
+
+func TestIsMutating(t *testing.T) {
+	assert.Assert(t, IsMutating(commandTree(t, "compose", "up")))
+	assert.Assert(t, !IsMutating(commandTree(t, "compose", "ps")))
+	assert.Assert(t, IsMutating(commandTree(t, "bootstrap", "--apply")))
+	assert.Assert(t, !IsMutating(commandTree(t, "bootstrap")))
+	assert.Assert(t, !IsMutating(commandTree(t, "bootstrap", "--apply=false")))
+}
+
+func TestTrackAction(t *testing.T) {
+	dir := fs.NewDir(t, "audit").Path()
+	assert.NilError(t, TrackAction(dir, "myaci", "aci", "compose ui scale", []string{"web", "project:demo"}, SuccessStatus, nil))
+
+	entries, err := Read(dir, time.Time{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 1)
+	assert.Equal(t, entries[0].Command, "compose ui scale")
+	assert.DeepEqual(t, entries[0].Resources, []string{"web", "project:demo"})
+}
+
+func TestReadSince(t *testing.T) {
+	dir := fs.NewDir(t, "audit").Path()
+	now := time.Now().UTC()
+	assert.NilError(t, Record(dir, Entry{Timestamp: now.Add(-48 * time.Hour), Command: "compose up"}))
+	assert.NilError(t, Record(dir, Entry{Timestamp: now, Command: "compose down"}))
+
+	entries, err := Read(dir, now.Add(-24*time.Hour))
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 1)
+	assert.Equal(t, entries[0].Command, "compose down")
+
+	entries, err = Read(fs.NewDir(t, "empty").Path(), time.Time{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 0)
+}
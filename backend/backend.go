@@ -25,6 +25,7 @@ import (
 
 	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/api/containers"
+	"github.com/docker/compose-cli/api/images"
 	"github.com/docker/compose-cli/api/secrets"
 	"github.com/docker/compose-cli/api/volumes"
 	"github.com/docker/compose-cli/context/cloud"
@@ -57,6 +58,7 @@ type Service interface {
 	ComposeService() compose.Service
 	SecretsService() secrets.Service
 	VolumeService() volumes.Service
+	ImageService() images.Service
 }
 
 // Register adds a typed backend to the registry
@@ -0,0 +1,176 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/formatter"
+)
+
+// ComposeOptions configure the compose suite
+type ComposeOptions struct {
+	// Project is deployed and removed by the suite. Its services must start within Timeout and log at least a line
+	Project *types.Project
+	// Timeout is the time services have to run their replicas once deployed, DefaultTimeout if not set
+	Timeout time.Duration
+	// Golden is the name of a golden file of the testdata directory the ps and logs output is compared to, for
+	// backends returning deterministic IDs and logs. Run tests with -test.update-golden to write it.
+	Golden string
+}
+
+// Compose deploys the project, checks the backend reports its services and logs, redeploys it and removes it
+func Compose(ctx context.Context, t *testing.T, service compose.Service, options ComposeOptions) {
+	if service == nil {
+		t.Skip("the backend has no compose service")
+	}
+	project := options.Project
+	assert.Assert(t, project != nil, "the compose suite requires a project")
+	timeout := options.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	var output bytes.Buffer
+	run(t, []step{
+		{"up", func(t *testing.T) {
+			assert.NilError(t, service.Up(ctx, project, compose.UpOptions{}))
+		}},
+		{"list", func(t *testing.T) {
+			stacks, err := service.List(ctx, project.Name)
+			skipNotImplemented(t, err)
+			assert.NilError(t, err)
+			assert.Assert(t, findStack(stacks, project.Name) != nil, "project %s isn't listed in %v", project.Name, stacks)
+		}},
+		{"ps", func(t *testing.T) {
+			assert.NilError(t, compose.WaitForServices(ctx, service, project, timeout))
+			status, err := service.Ps(ctx, project.Name)
+			assert.NilError(t, err)
+			var names []string
+			for _, s := range status {
+				assert.Equal(t, s.Replicas, s.Desired, "service %s", s.Name)
+				names = append(names, s.Name)
+			}
+			assert.DeepEqual(t, distinct(names), runningServices(project))
+			assert.NilError(t, formatter.PrintServiceStatus(&output, status))
+		}},
+		{"logs", func(t *testing.T) {
+			events := &logEvents{}
+			err := service.Logs(ctx, project.Name, events, compose.LogOptions{Until: time.Now()})
+			skipNotImplemented(t, err)
+			assert.NilError(t, err)
+			assert.Assert(t, len(events.events) > 0, "no log line returned")
+			for _, event := range events.events {
+				_, err := project.GetService(event.Service)
+				assert.NilError(t, err, "log line of an unknown service")
+			}
+			output.WriteString("\n")
+			events.print(&output)
+		}},
+		{"golden", func(t *testing.T) {
+			if options.Golden == "" {
+				t.Skip("no golden file")
+			}
+			golden.Assert(t, output.String(), options.Golden)
+		}},
+		{"up again", func(t *testing.T) {
+			assert.NilError(t, service.Up(ctx, project, compose.UpOptions{}))
+		}},
+		{"down", func(t *testing.T) {
+			assert.NilError(t, service.Down(ctx, project.Name, compose.DownOptions{}))
+		}},
+		{"removed", func(t *testing.T) {
+			stacks, err := service.List(ctx, project.Name)
+			skipNotImplemented(t, err)
+			assert.NilError(t, err)
+			if stack := findStack(stacks, project.Name); stack != nil {
+				assert.Equal(t, stack.Status, compose.REMOVING, "project %s is still deployed", project.Name)
+			}
+			status, err := service.Ps(ctx, project.Name)
+			if err != nil {
+				assertNotFound(t, err)
+			} else {
+				assert.Equal(t, len(status), 0, "removed project has services")
+			}
+		}},
+	})
+}
+
+func findStack(stacks []compose.Stack, name string) *compose.Stack {
+	for _, stack := range stacks {
+		if stack.Name == name {
+			return &stack
+		}
+	}
+	return nil
+}
+
+// runningServices returns the sorted names of the services running continuously, for which ps reports a status
+func runningServices(project *types.Project) []string {
+	var names []string
+	for _, service := range project.Services {
+		if !compose.IsJob(service) && !compose.IsScheduled(service) {
+			names = append(names, service.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// distinct returns the sorted names, services deployed to several regions being reported once per region
+func distinct(names []string) []string {
+	set := map[string]bool{}
+	var result []string
+	for _, name := range names {
+		if !set[name] {
+			set[name] = true
+			result = append(result, name)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+type logEvents struct {
+	events []compose.LogEvent
+}
+
+func (l *logEvents) Log(event compose.LogEvent) {
+	l.events = append(l.events, event)
+}
+
+// print writes the lines as compose logs does, sorted by timestamp then service for a deterministic output
+func (l *logEvents) print(out *bytes.Buffer) {
+	sort.SliceStable(l.events, func(i, j int) bool {
+		if !l.events[i].Timestamp.Equal(l.events[j].Timestamp) {
+			return l.events[i].Timestamp.Before(l.events[j].Timestamp)
+		}
+		return l.events[i].Service < l.events[j].Service
+	})
+	consumer := formatter.NewLogConsumer(out, false)
+	for _, event := range l.events {
+		consumer.Log(event)
+	}
+}
@@ -0,0 +1,76 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package conformance verifies that a backend implements the semantics the CLI relies on. Backend implementers call
+// its suites from their own tests, against a test account or recorded calls:
+//
+//	func TestConformance(t *testing.T) {
+//		ctx := context.Background()
+//		service := newTestBackend(t)
+//		conformance.Compose(ctx, t, service.ComposeService(), conformance.ComposeOptions{Project: project, Golden: "conformance.golden"})
+//		conformance.Volumes(ctx, t, service.VolumeService(), conformance.VolumesOptions{})
+//	}
+//
+// Suites are skipped for nil services, and steps calling operations the backend reports as errdefs.ErrNotImplemented
+// are skipped.
+package conformance
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// DefaultTimeout is the time services and containers have to run when the options of a suite don't set it
+const DefaultTimeout = 5 * time.Minute
+
+// step is a stage of a suite, depending on the previous ones
+type step struct {
+	name string
+	run  func(t *testing.T)
+}
+
+// run runs the steps in order as subtests, stopping at the first failing one
+func run(t *testing.T, steps []step) {
+	for _, s := range steps {
+		if !t.Run(s.name, s.run) {
+			return
+		}
+	}
+}
+
+// skipNotImplemented skips the step when the backend doesn't implement the operation
+func skipNotImplemented(t *testing.T, err error) {
+	if errdefs.IsErrNotImplemented(err) {
+		t.Skipf("not implemented: %s", err)
+	}
+}
+
+// assertNotFound checks the backend returns errdefs.ErrNotFound for a resource which doesn't exist
+func assertNotFound(t *testing.T, err error) {
+	assert.Assert(t, errdefs.IsNotFoundError(err), "expected a not found error, got %v", err)
+}
+
+func uniqueName(name string) string {
+	if name != "" {
+		return name
+	}
+	return fmt.Sprintf("conformance-%d", time.Now().UnixNano())
+}
@@ -0,0 +1,172 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package conformance
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/containers"
+	"github.com/docker/compose-cli/api/secrets"
+	"github.com/docker/compose-cli/api/volumes"
+)
+
+// ContainersOptions configure the containers suite
+type ContainersOptions struct {
+	// ID of the container run by the suite, a unique name if not set
+	ID string
+	// Image of the container, which must keep running, nginx if not set
+	Image string
+}
+
+// Containers runs a container, checks the backend lists and inspects it, and deletes it
+func Containers(ctx context.Context, t *testing.T, service containers.Service, options ContainersOptions) {
+	if service == nil {
+		t.Skip("the backend has no container service")
+	}
+	id := uniqueName(options.ID)
+	image := options.Image
+	if image == "" {
+		image = "nginx"
+	}
+	run(t, []step{
+		{"run", func(t *testing.T) {
+			assert.NilError(t, service.Run(ctx, containers.ContainerConfig{ID: id, Image: image}))
+		}},
+		{"list", func(t *testing.T) {
+			list, err := service.List(ctx, false)
+			assert.NilError(t, err)
+			found := false
+			for _, c := range list {
+				found = found || c.ID == id
+			}
+			assert.Assert(t, found, "container %s isn't listed", id)
+		}},
+		{"inspect", func(t *testing.T) {
+			container, err := service.Inspect(ctx, id)
+			assert.NilError(t, err)
+			assert.Equal(t, container.ID, id)
+			// backends may return the image reference normalized
+			assert.Assert(t, strings.Contains(container.Image, image), "container image %s, expected %s", container.Image, image)
+		}},
+		{"delete", func(t *testing.T) {
+			assert.NilError(t, service.Delete(ctx, id, containers.DeleteRequest{Force: true}))
+		}},
+		{"deleted", func(t *testing.T) {
+			_, err := service.Inspect(ctx, id)
+			assertNotFound(t, err)
+			assertNotFound(t, service.Delete(ctx, id, containers.DeleteRequest{Force: true}))
+		}},
+	})
+}
+
+// VolumesOptions configure the volumes suite
+type VolumesOptions struct {
+	// Name of the volume created by the suite, a unique name if not set
+	Name string
+	// Options are the backend specific options the volume is created with
+	Options interface{}
+}
+
+// Volumes creates a volume, checks the backend lists it, and deletes it
+func Volumes(ctx context.Context, t *testing.T, service volumes.Service, options VolumesOptions) {
+	if service == nil {
+		t.Skip("the backend has no volume service")
+	}
+	name := uniqueName(options.Name)
+	var volume volumes.Volume
+	run(t, []step{
+		{"create", func(t *testing.T) {
+			var err error
+			volume, err = service.Create(ctx, name, options.Options)
+			skipNotImplemented(t, err)
+			assert.NilError(t, err)
+			assert.Assert(t, volume.ID != "", "created volume has no ID")
+		}},
+		{"list", func(t *testing.T) {
+			list, err := service.List(ctx)
+			assert.NilError(t, err)
+			assert.Assert(t, hasVolume(list, volume.ID), "volume %s isn't listed", volume.ID)
+		}},
+		{"delete", func(t *testing.T) {
+			assert.NilError(t, service.Delete(ctx, volume.ID, options.Options))
+		}},
+		{"deleted", func(t *testing.T) {
+			list, err := service.List(ctx)
+			assert.NilError(t, err)
+			assert.Assert(t, !hasVolume(list, volume.ID), "deleted volume %s is still listed", volume.ID)
+			assertNotFound(t, service.Delete(ctx, volume.ID, options.Options))
+		}},
+	})
+}
+
+func hasVolume(list []volumes.Volume, id string) bool {
+	for _, v := range list {
+		if v.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// SecretsOptions configure the secrets suite
+type SecretsOptions struct {
+	// Name of the secret created by the suite, a unique name if not set
+	Name string
+}
+
+// Secrets creates a registry credentials secret, checks the backend inspects and lists it, and deletes it
+func Secrets(ctx context.Context, t *testing.T, service secrets.Service, options SecretsOptions) {
+	if service == nil {
+		t.Skip("the backend has no secrets service")
+	}
+	name := uniqueName(options.Name)
+	var id string
+	run(t, []step{
+		{"create", func(t *testing.T) {
+			var err error
+			id, err = service.CreateSecret(ctx, secrets.NewSecret(name, "user", "password", "conformance test"))
+			skipNotImplemented(t, err)
+			assert.NilError(t, err)
+			assert.Assert(t, id != "", "created secret has no ID")
+		}},
+		{"inspect", func(t *testing.T) {
+			secret, err := service.InspectSecret(ctx, id)
+			assert.NilError(t, err)
+			assert.Equal(t, secret.Name, name)
+		}},
+		{"list", func(t *testing.T) {
+			list, err := service.ListSecrets(ctx)
+			assert.NilError(t, err)
+			found := false
+			for _, s := range list {
+				found = found || s.ID == id
+			}
+			assert.Assert(t, found, "secret %s isn't listed", id)
+		}},
+		{"delete", func(t *testing.T) {
+			assert.NilError(t, service.DeleteSecret(ctx, id, false))
+		}},
+		{"deleted", func(t *testing.T) {
+			_, err := service.InspectSecret(ctx, id)
+			assertNotFound(t, err)
+		}},
+	})
+}
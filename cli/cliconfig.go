@@ -0,0 +1,159 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	apicompose "github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/config"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/output"
+	"github.com/docker/compose-cli/progress"
+)
+
+// applyCLIConfig exports the user defaults to the environment, so that they also apply to delegated commands.
+// Variables already set take precedence.
+func applyCLIConfig(c *config.CLIConfig) {
+	env := map[string]string{}
+	if c.Progress != "" {
+		env[progress.ModeEnvVar] = c.Progress
+	}
+	if c.Theme != "" {
+		env[output.ThemeEnvVar] = c.Theme
+	}
+	if c.Parallelism != 0 {
+		env[apicompose.ParallelismEnvVar] = strconv.Itoa(c.Parallelism)
+	}
+	if c.Telemetry != nil {
+		env[config.TelemetryEnvVar] = strconv.FormatBool(*c.Telemetry)
+	}
+	for name, value := range env {
+		if _, ok := os.LookupEnv(name); !ok {
+			_ = os.Setenv(name, value)
+		}
+	}
+}
+
+// aliasAnnotation holds the command an alias runs, with its arguments
+const aliasAnnotation = "alias"
+
+// addAliasCommands registers the aliases as commands, except those named after an existing command
+func addAliasCommands(root *cobra.Command, aliases map[string]string, defaults func(command string) []string) {
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if command, _, err := root.Find([]string{name}); err == nil && command != root {
+			logrus.Debugf("alias %q ignored, a command has the same name", name)
+			continue
+		}
+		root.AddCommand(aliasCommand(root, name, aliases[name], defaults))
+	}
+}
+
+// aliasCommand runs the aliased command from the root, passing it the arguments given to the alias
+func aliasCommand(root *cobra.Command, name string, alias string, defaults func(command string) []string) *cobra.Command {
+	return &cobra.Command{
+		Use:                name,
+		Short:              fmt.Sprintf("Alias for %q", "docker "+alias),
+		Annotations:        map[string]string{aliasAnnotation: alias},
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fields := strings.Fields(alias)
+			if len(fields) == 0 || strings.HasPrefix(fields[0], "-") {
+				return errors.Wrapf(errdefs.ErrParsingFailed, "alias %q must start with a command", name)
+			}
+			if target, _, err := root.Find(fields); err == nil {
+				if _, ok := target.Annotations[aliasAnnotation]; ok {
+					return errors.Wrapf(errdefs.ErrParsingFailed, "alias %q can't run alias %q", name, target.Name())
+				}
+			}
+			args = withDefaultFlags(append(fields, args...), root, defaults)
+			// commands delegated to the Docker CLI and metrics use the command line of the aliased command
+			os.Args = append(os.Args[:1], args...)
+			root.SetArgs(args)
+			return root.ExecuteContext(cmd.Context())
+		},
+	}
+}
+
+// withDefaultFlags adds the default flags of the command right after its name, so that flags set on the command line
+// override them
+func withDefaultFlags(args []string, root *cobra.Command, defaults func(command string) []string) []string {
+	command, _, err := root.Find(args)
+	if err != nil || command == root {
+		return args
+	}
+	words := strings.Fields(strings.TrimPrefix(command.CommandPath(), root.Name()))
+	flags := defaults(strings.Join(words, " "))
+	if len(flags) == 0 {
+		return args
+	}
+	matched := 0
+	for i := 0; i < len(args) && matched < len(words); i++ {
+		next := firstPositional(args[i:], root.PersistentFlags())
+		if next < 0 {
+			break
+		}
+		i += next
+		if args[i] != words[matched] {
+			continue
+		}
+		matched++
+		if matched == len(words) {
+			result := append([]string{}, args[:i+1]...)
+			result = append(result, flags...)
+			return append(result, args[i+1:]...)
+		}
+	}
+	return args
+}
+
+// firstPositional returns the index of the first argument which is neither a global flag nor a flag value, -1 if none
+func firstPositional(args []string, flags *pflag.FlagSet) int {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return -1
+		case strings.HasPrefix(arg, "--"):
+			if f := flags.Lookup(strings.TrimPrefix(arg, "--")); f != nil && f.NoOptDefVal == "" {
+				i++
+			}
+		case strings.HasPrefix(arg, "-") && len(arg) == 2:
+			if f := flags.ShorthandLookup(arg[1:]); f != nil && f.NoOptDefVal == "" {
+				i++
+			}
+		case strings.HasPrefix(arg, "-"):
+		default:
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,110 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/audit"
+	"github.com/docker/compose-cli/config"
+	"github.com/docker/compose-cli/errdefs"
+	formatter2 "github.com/docker/compose-cli/formatter"
+	"github.com/docker/compose-cli/i18n"
+)
+
+type auditShowOpts struct {
+	since string
+	json  bool
+}
+
+// AuditCommand reviews the audit log of mutating operations
+func AuditCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Review the log of operations changing cloud resources",
+	}
+	cmd.AddCommand(auditShowCommand())
+	return cmd
+}
+
+func auditShowCommand() *cobra.Command {
+	var opts auditShowOpts
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show recorded operations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAuditShow(cmd.Context(), os.Stdout, opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.since, "since", "", "Show operations since a timestamp (e.g. 2020-10-01T10:00:00Z) or relative duration (e.g. 24h)")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Format output as JSON")
+	return cmd
+}
+
+func runAuditShow(ctx context.Context, out io.Writer, opts auditShowOpts) error {
+	since, err := parseSince(opts.since, time.Now())
+	if err != nil {
+		return err
+	}
+	entries, err := audit.Read(config.Dir(ctx), since)
+	if err != nil {
+		return err
+	}
+
+	if opts.json {
+		j, err := formatter2.ToStandardJSON(entries)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, j)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 20, 1, 3, ' ', 0)
+	fmt.Fprintf(w, "TIMESTAMP\tCONTEXT\tCOMMAND\tRESOURCES\tSTATUS\n")
+	for _, entry := range entries {
+		status := entry.Status
+		if entry.Error != "" {
+			status = fmt.Sprintf("%s: %s", status, entry.Error)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", entry.Timestamp.Local().Format(time.RFC3339), entry.Context, entry.Command, strings.Join(entry.Resources, ", "), status)
+	}
+	return w.Flush()
+}
+
+// parseSince accepts RFC3339 timestamps and durations relative to now, an empty value selecting all operations
+func parseSince(since string, now time.Time) (time.Time, error) {
+	if since == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(since); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, since); err == nil {
+		return t, nil
+	}
+	return time.Time{}, i18n.Wrap(errdefs.ErrParsingFailed, "audit.since", since)
+}
@@ -0,0 +1,38 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+
+	"github.com/compose-spec/compose-go/types"
+
+	"github.com/docker/compose-cli/annotations"
+	"github.com/docker/compose-cli/api/compose"
+)
+
+// annotateDeployment posts the deployment to the monitoring tools set on the context, warning when it fails as the
+// application is already deployed
+func annotateDeployment(ctx context.Context, project *types.Project, version string) {
+	settings := currentContextSettings(ctx)
+	if len(settings.Annotations) == 0 {
+		return
+	}
+	if err := annotations.Publish(ctx, settings.Annotations, annotations.NewDeployment(project, settings.Name, version)); err != nil {
+		compose.Warn(ctx, "failed to annotate the deployment: %s", err)
+	}
+}
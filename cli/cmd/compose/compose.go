@@ -18,13 +18,21 @@ package compose
 
 import (
 	"context"
+	"path/filepath"
+	"time"
 
 	"github.com/compose-spec/compose-go/cli"
+	"github.com/compose-spec/compose-go/types"
 
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/config"
 	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/remote"
 )
 
 type composeOptions struct {
@@ -33,14 +41,16 @@ type composeOptions struct {
 	WorkingDir  string
 	ConfigPaths []string
 	Environment []string
+	Overrides   []string
+	LockTimeout time.Duration
 }
 
-func (o *composeOptions) toProjectName() (string, error) {
+func (o *composeOptions) toProjectName(ctx context.Context) (string, error) {
 	if o.Name != "" {
 		return o.Name, nil
 	}
 
-	options, err := o.toProjectOptions()
+	options, err := o.toProjectOptions(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -52,14 +62,53 @@ func (o *composeOptions) toProjectName() (string, error) {
 	return project.Name, nil
 }
 
-func (o *composeOptions) toProjectOptions() (*cli.ProjectOptions, error) {
-	return cli.NewProjectOptions(o.ConfigPaths,
+// toProjectOptions fetches remote compose files referenced as git repositories, OCI artifacts or https URLs
+func (o *composeOptions) toProjectOptions(ctx context.Context) (*cli.ProjectOptions, error) {
+	configPaths, err := remote.Resolve(ctx, o.ConfigPaths, filepath.Join(config.Dir(ctx), "compose-files"))
+	if err != nil {
+		return nil, err
+	}
+	return cli.NewProjectOptions(configPaths,
 		cli.WithOsEnv,
 		cli.WithEnv(o.Environment),
 		cli.WithWorkingDirectory(o.WorkingDir),
 		cli.WithName(o.Name))
 }
 
+// toProject loads the project from the compose files, and applies --set overrides on top of it
+func (o *composeOptions) toProject(ctx context.Context) (*types.Project, error) {
+	options, err := o.toProjectOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	project, err := cli.ProjectFromOptions(options)
+	if err != nil || len(o.Overrides) == 0 {
+		return project, err
+	}
+	return compose.ApplyOverrides(project, o.Overrides, options.Environment)
+}
+
+func (o *composeOptions) addLockFlag(flags *pflag.FlagSet) {
+	flags.DurationVar(&o.LockTimeout, "lock-timeout", 0, "Time to wait for another command holding the project lock, e.g. 2m")
+}
+
+// withLock runs fn holding the project lock, so concurrent commands don't interleave their changes
+func withLock(ctx context.Context, service compose.Service, projectName string, timeout time.Duration, fn func() error) error {
+	release, err := service.Lock(ctx, projectName, timeout)
+	if errdefs.IsErrNotImplemented(err) {
+		return fn()
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := release(); err != nil {
+			logrus.Warnf("failed to release lock on project %q, run `docker compose unlock -p %s`: %v", projectName, projectName, err)
+		}
+	}()
+	return fn()
+}
+
 // Command returns the compose command with its child commands
 func Command(contextType string) *cobra.Command {
 	command := &cobra.Command{
@@ -77,6 +126,19 @@ func Command(contextType string) *cobra.Command {
 		listCommand(),
 		logsCommand(),
 		convertCommand(),
+		pruneCommand(),
+		costCommand(),
+		portForwardCommand(),
+		networkCommand(),
+		configCommand(),
+		diffCommand(),
+		importCommand(),
+		generateCommand(contextType),
+		publishCommand(),
+		unlockCommand(),
+		inspectCommand(),
+		sbomCommand(),
+		uiCommand(),
 	)
 
 	return command
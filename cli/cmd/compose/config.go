@@ -0,0 +1,173 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/compose-spec/compose-go/cli"
+	"github.com/compose-spec/compose-go/loader"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/sanathkr/go-yaml"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/i18n"
+)
+
+type configOptions struct {
+	composeOptions
+	ports              bool
+	environmentSources string
+}
+
+func configCommand() *cobra.Command {
+	opts := configOptions{}
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Validate and view the compose file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfig(cmd.Context(), opts)
+		},
+	}
+	configCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	configCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	configCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	configCmd.Flags().StringArrayVarP(&opts.Environment, "environment", "e", []string{}, "Environment variables")
+	configCmd.Flags().StringArrayVar(&opts.Overrides, "set", []string{}, "Override a compose file attribute, as path=value (e.g. services.web.deploy.replicas=2)")
+	configCmd.Flags().BoolVar(&opts.ports, "ports", false, "Print from where service ports can be reached once deployed")
+	configCmd.Flags().StringVar(&opts.environmentSources, "environment-sources", "", "Print the environment of a service with the source of each variable")
+
+	return configCmd
+}
+
+func runConfig(ctx context.Context, opts configOptions) error {
+	project, err := opts.toProject(ctx)
+	if err != nil {
+		return err
+	}
+
+	if opts.environmentSources != "" {
+		return printEnvironmentSources(ctx, opts, project)
+	}
+	if !opts.ports {
+		content, err := yaml.Marshal(project)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(content))
+		return nil
+	}
+
+	c, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+	exposure, err := c.ComposeService().Exposure(ctx, project)
+	if err != nil {
+		return err
+	}
+	return printSection(os.Stdout, func(w io.Writer) {
+		for _, e := range exposure {
+			fmt.Fprintf(w, "%s\t%d/%s\t%s\n", e.Service, e.Port, e.Protocol, e.Source)
+		}
+	}, "SERVICE", "PORT", "REACHABLE FROM")
+}
+
+func printEnvironmentSources(ctx context.Context, opts configOptions, project *types.Project) error {
+	options, err := opts.toProjectOptions(ctx)
+	if err != nil {
+		return err
+	}
+	files, err := configFiles(options)
+	if err != nil {
+		return err
+	}
+	env, err := compose.EnvironmentSources(project, opts.environmentSources, files, opts.Overrides)
+	if err != nil {
+		return err
+	}
+	return printSection(os.Stdout, func(w io.Writer) {
+		for _, v := range env {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", v.Name, v.Value, v.Source)
+		}
+	}, "NAME", "VALUE", "SOURCE")
+}
+
+// configFiles parses the compose files a project is loaded from, found the same way the project loader does
+func configFiles(options *cli.ProjectOptions) ([]types.ConfigFile, error) {
+	paths := options.ConfigPaths
+	if len(paths) == 0 {
+		if f := os.Getenv(cli.ComposeFilePath); f != "" {
+			paths = strings.Split(f, string(os.PathListSeparator))
+		} else {
+			path, err := defaultConfigFile(options.WorkingDir)
+			if err != nil {
+				return nil, err
+			}
+			paths = []string{path}
+		}
+	}
+	files := []types.ConfigFile{}
+	for _, path := range paths {
+		if path == "-" {
+			// standard input has been consumed when loading the project
+			continue
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		config, err := loader.ParseYAML(b)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, types.ConfigFile{Filename: path, Config: config})
+	}
+	return files, nil
+}
+
+func defaultConfigFile(dir string) (string, error) {
+	if dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		dir = wd
+	}
+	for {
+		for _, name := range cli.DefaultFileNames {
+			path := filepath.Join(dir, name)
+			if _, err := os.Stat(path); err == nil {
+				return path, nil
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", i18n.Wrap(errdefs.ErrNotFound, "compose.config-not-found")
+		}
+		dir = parent
+	}
+}
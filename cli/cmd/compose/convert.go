@@ -20,7 +20,6 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/compose-spec/compose-go/cli"
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose-cli/api/client"
@@ -39,6 +38,7 @@ func convertCommand() *cobra.Command {
 	convertCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
 	convertCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
 	convertCmd.Flags().StringArrayVarP(&opts.Environment, "environment", "e", []string{}, "Environment variables")
+	convertCmd.Flags().StringArrayVar(&opts.Overrides, "set", []string{}, "Override a compose file attribute, as path=value (e.g. services.web.deploy.replicas=2)")
 
 	return convertCmd
 }
@@ -50,12 +50,7 @@ func runConvert(ctx context.Context, opts composeOptions) error {
 		return err
 	}
 
-	options, err := opts.toProjectOptions()
-	if err != nil {
-		return err
-	}
-
-	project, err := cli.ProjectFromOptions(options)
+	project, err := opts.toProject(ctx)
 	if err != nil {
 		return err
 	}
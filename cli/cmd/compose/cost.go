@@ -0,0 +1,81 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func costCommand() *cobra.Command {
+	opts := composeOptions{}
+	costCmd := &cobra.Command{
+		Use:   "cost",
+		Short: "Estimate the monthly cost of running the application",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCost(cmd.Context(), opts)
+		},
+	}
+	costCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	costCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	costCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	costCmd.Flags().StringArrayVarP(&opts.Environment, "environment", "e", []string{}, "Environment variables")
+	costCmd.Flags().StringArrayVar(&opts.Overrides, "set", []string{}, "Override a compose file attribute, as path=value (e.g. services.web.deploy.replicas=2)")
+
+	return costCmd
+}
+
+func runCost(ctx context.Context, opts composeOptions) error {
+	c, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+
+	project, err := opts.toProject(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = printCostEstimate(ctx, c.ComposeService(), project)
+	return err
+}
+
+// printCostEstimate prints the estimated cost of each service and the project total, which it returns
+func printCostEstimate(ctx context.Context, service compose.Service, project *types.Project) (float64, error) {
+	estimates, err := service.Estimate(ctx, project)
+	if err != nil {
+		return 0, err
+	}
+	var hourly float64
+	currency := ""
+	err = printSection(os.Stdout, func(w io.Writer) {
+		for _, e := range estimates {
+			hourly += e.Hourly
+			currency = e.Currency
+			fmt.Fprintf(w, "%s\t%d\t%.2f\t%.2fGB\t%.4f %s\t%.2f %s\n", e.Service, e.Replicas, e.CPUs, e.MemoryGB, e.Hourly, e.Currency, e.Monthly(), e.Currency)
+		}
+		fmt.Fprintf(w, "TOTAL\t\t\t\t%.4f %s\t%.2f %s\n", hourly, currency, hourly*compose.HoursPerMonth, currency)
+	}, "SERVICE", "REPLICAS", "CPUS", "MEMORY", "HOURLY", "MONTHLY")
+	return hourly * compose.HoursPerMonth, err
+}
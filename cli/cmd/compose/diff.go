@@ -0,0 +1,104 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/i18n"
+)
+
+type diffOptions struct {
+	composeOptions
+	exitCode bool
+}
+
+func diffCommand() *cobra.Command {
+	opts := diffOptions{}
+	diffCmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show differences between the deployed application and the compose file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDiff(cmd.Context(), os.Stdout, opts)
+		},
+	}
+	diffCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	diffCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	diffCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	diffCmd.Flags().StringArrayVarP(&opts.Environment, "environment", "e", []string{}, "Environment variables")
+	diffCmd.Flags().StringArrayVar(&opts.Overrides, "set", []string{}, "Override a compose file attribute, as path=value (e.g. services.web.deploy.replicas=2)")
+	diffCmd.Flags().BoolVar(&opts.exitCode, "exit-code", false, "Exit with status 1 when there are differences")
+
+	return diffCmd
+}
+
+func runDiff(ctx context.Context, out io.Writer, opts diffOptions) error {
+	c, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+
+	project, err := opts.toProject(ctx)
+	if err != nil {
+		return err
+	}
+
+	diff, err := c.ComposeService().Diff(ctx, project)
+	if err != nil {
+		return err
+	}
+	return printDiff(out, diff, opts.exitCode)
+}
+
+func printDiff(out io.Writer, diff compose.ProjectDiff, exitCode bool) error {
+	unified, err := compose.UnifiedDiff(diff)
+	if err != nil {
+		return err
+	}
+	if unified == "" && len(diff.Drifts) == 0 {
+		fmt.Fprintln(out, i18n.T("compose.diff.none"))
+		return nil
+	}
+
+	fmt.Fprint(out, unified)
+	if len(diff.Drifts) > 0 {
+		if unified != "" {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintln(out, i18n.T("compose.diff.drift"))
+		err = printSection(out, func(w io.Writer) {
+			for _, drift := range diff.Drifts {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", drift.Resource, drift.Type, drift.Status)
+			}
+		}, "RESOURCE", "TYPE", "DRIFT")
+		if err != nil {
+			return err
+		}
+	}
+	if exitCode {
+		return errdefs.ExitCodeError{Code: 1}
+	}
+	return nil
+}
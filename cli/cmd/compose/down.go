@@ -18,40 +18,87 @@ package compose
 
 import (
 	"context"
+	"os"
 
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/audit"
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/i18n"
 	"github.com/docker/compose-cli/progress"
 )
 
 func downCommand() *cobra.Command {
 	opts := composeOptions{}
+	downOpts := compose.DownOptions{}
+	var jsonEvents bool
 	downCmd := &cobra.Command{
-		Use: "down",
+		Use:         "down",
+		Annotations: audit.Mutating(),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runDown(cmd.Context(), opts)
+			switch downOpts.RemoveImages {
+			case "", compose.RemoveImagesLocal, compose.RemoveImagesAll:
+			default:
+				return i18n.Error("compose.down.rmi", downOpts.RemoveImages, compose.RemoveImagesLocal, compose.RemoveImagesAll)
+			}
+			ctx := cmd.Context()
+			if jsonEvents {
+				ctx = progress.WithJSONEvents(ctx, os.Stdout, progress.Removal)
+			}
+			return runDown(ctx, opts, downOpts)
 		},
 	}
 	downCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
 	downCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
 	downCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	downCmd.Flags().BoolVarP(&downOpts.RemoveVolumes, "volumes", "v", false, "Remove volumes labelled with the project")
+	downCmd.Flags().StringVar(&downOpts.RemoveImages, "rmi", "", `Remove images from the local engine: "local" for images built for the project, "all" to also remove images used by services`)
+	downCmd.Flags().BoolVar(&jsonEvents, "json-events", false, "Write progress as a stream of JSON events on the standard output, for programs")
+	opts.addLockFlag(downCmd.Flags())
+	downCmd.Flags().BoolVar(&downOpts.RemoveOrphans, "remove-orphans", false, "Remove resources labelled with the project which are not part of the deployed application")
 
 	return downCmd
 }
 
-func runDown(ctx context.Context, opts composeOptions) error {
+func runDown(ctx context.Context, opts composeOptions, downOpts compose.DownOptions) error {
 	c, err := client.New(ctx)
 	if err != nil {
 		return err
 	}
 
+	// the compose file is optional to run down, only its x-hooks and x-notifications are read when it's there
+	project, err := opts.toProject(ctx)
+	if err != nil {
+		project = nil
+	}
+	var hooks map[string][]compose.Hook
+	if project != nil {
+		hooks, err = compose.ProjectHooks(project)
+		if err != nil {
+			return err
+		}
+	}
+	var projectName string
 	_, err = progress.Run(ctx, func(ctx context.Context) (string, error) {
-		projectName, err := opts.toProjectName()
+		var err error
+		projectName, err = opts.toProjectName(ctx)
 		if err != nil {
 			return "", err
 		}
-		return projectName, c.ComposeService().Down(ctx, projectName)
+		return projectName, withLock(ctx, c.ComposeService(), projectName, opts.LockTimeout, func() error {
+			if project != nil {
+				if err := runHooks(ctx, c.ComposeService(), project, hooks, compose.PreDownHook); err != nil {
+					return err
+				}
+			}
+			return c.ComposeService().Down(ctx, projectName, downOpts)
+		})
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	notifyEvent(ctx, store.DownEvent, projectName, project, nil)
+	return nil
 }
@@ -0,0 +1,182 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/generate"
+	"github.com/docker/compose-cli/i18n"
+	"github.com/docker/compose-cli/prompt"
+)
+
+type generateOptions struct {
+	workingDir  string
+	language    string
+	service     string
+	port        int
+	cpus        string
+	memory      string
+	interactive bool
+	force       bool
+}
+
+func generateCommand(contextType string) *cobra.Command {
+	opts := generateOptions{}
+	generateCmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate a Dockerfile and a compose file for the Go, Node or Python project in the working directory",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGenerate(opts, prompt.User{})
+		},
+	}
+	cpus, memory := backendResources(contextType)
+	generateCmd.Flags().StringVar(&opts.workingDir, "workdir", "", "Work dir")
+	generateCmd.Flags().StringVar(&opts.language, "language", "", "Project language: go, node or python (default: detected)")
+	generateCmd.Flags().StringVar(&opts.service, "service", "", "Service name (default: working directory name)")
+	generateCmd.Flags().IntVar(&opts.port, "port", 0, "Port the application listens on (default: language usual port)")
+	generateCmd.Flags().StringVar(&opts.cpus, "cpus", cpus, "CPU limit of the service")
+	generateCmd.Flags().StringVar(&opts.memory, "memory", memory, "Memory limit of the service")
+	generateCmd.Flags().BoolVarP(&opts.interactive, "interactive", "i", false, "Prompt for generation options")
+	generateCmd.Flags().BoolVar(&opts.force, "force", false, "Overwrite existing Dockerfile and compose file")
+
+	return generateCmd
+}
+
+// backendResources returns the default resources of a service deployed on the backend
+func backendResources(contextType string) (string, string) {
+	switch contextType {
+	case store.AciContextType:
+		return "1", "1G"
+	case store.EcsContextType:
+		return "0.25", "512M"
+	default:
+		return "", ""
+	}
+}
+
+func runGenerate(opts generateOptions, ui prompt.UI) error {
+	dir := opts.workingDir
+	if dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		dir = wd
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	options, err := generateFromFlags(dir, opts)
+	if err != nil {
+		return err
+	}
+	if opts.interactive {
+		options, err = generateFromPrompt(options, ui)
+		if err != nil {
+			return err
+		}
+	}
+
+	paths, err := generate.Write(dir, options, opts.force)
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		fmt.Println(i18n.T("compose.generate.done", path))
+	}
+	return nil
+}
+
+func generateFromFlags(dir string, opts generateOptions) (generate.Options, error) {
+	options := generate.Options{
+		Service: opts.service,
+		Port:    opts.port,
+		CPUs:    opts.cpus,
+		Memory:  opts.memory,
+	}
+	if options.Service == "" {
+		options.Service = filepath.Base(dir)
+	}
+	var err error
+	if opts.language != "" {
+		options.Language, err = generate.ParseLanguage(opts.language)
+	} else {
+		options.Language, err = generate.Detect(dir)
+		if err != nil && opts.interactive {
+			// let the user pick the language
+			err = nil
+		}
+	}
+	if err != nil {
+		return generate.Options{}, err
+	}
+	if options.Port == 0 {
+		options.Port = generate.DefaultPort(options.Language)
+	}
+	return options, nil
+}
+
+func generateFromPrompt(options generate.Options, ui prompt.UI) (generate.Options, error) {
+	// the detected language is offered first
+	choices := []generate.Language{}
+	if options.Language != "" {
+		choices = append(choices, options.Language)
+	}
+	for _, language := range generate.Languages {
+		if language != options.Language {
+			choices = append(choices, language)
+		}
+	}
+	names := make([]string, len(choices))
+	for i, language := range choices {
+		names[i] = string(language)
+	}
+	selected, err := ui.Select(i18n.T("compose.generate.language"), names)
+	if err != nil {
+		return options, err
+	}
+	language := choices[selected]
+	if language != options.Language {
+		options.Language = language
+		options.Port = generate.DefaultPort(language)
+	}
+
+	if options.Service, err = ui.Input(i18n.T("compose.generate.service"), options.Service); err != nil {
+		return options, err
+	}
+	port, err := ui.Input(i18n.T("compose.generate.port"), strconv.Itoa(options.Port))
+	if err != nil {
+		return options, err
+	}
+	if options.Port, err = strconv.Atoi(port); err != nil {
+		return options, err
+	}
+	if options.CPUs, err = ui.Input(i18n.T("compose.generate.cpus"), options.CPUs); err != nil {
+		return options, err
+	}
+	options.Memory, err = ui.Input(i18n.T("compose.generate.memory"), options.Memory)
+	return options, err
+}
@@ -0,0 +1,69 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/i18n"
+)
+
+// runHooks runs the project hooks of a phase, their jobs being run by the backend
+func runHooks(ctx context.Context, service compose.Service, project *types.Project, hooks map[string][]compose.Hook, phase string) error {
+	return compose.RunHooks(ctx, project, phase, hooks[phase], func(ctx context.Context, job string) error {
+		return service.RunJob(ctx, project, job)
+	})
+}
+
+// upWithHooks deploys the project between its pre-up and post-up hooks. When a post-up hook set to roll back fails,
+// the application is removed if this deployment created it
+func upWithHooks(ctx context.Context, service compose.Service, project *types.Project, options compose.UpOptions, hooks map[string][]compose.Hook) error {
+	if err := runHooks(ctx, service, project, hooks, compose.PreUpHook); err != nil {
+		return err
+	}
+	created := false
+	for _, hook := range hooks[compose.PostUpHook] {
+		if hook.OnFailure != compose.HookFailureRollback {
+			continue
+		}
+		stacks, err := service.List(ctx, project.Name)
+		if err != nil {
+			return err
+		}
+		created = len(stacks) == 0
+		break
+	}
+	if err := service.Up(ctx, project, options); err != nil {
+		return err
+	}
+	err := runHooks(ctx, service, project, hooks, compose.PostUpHook)
+	var failed compose.HookFailedError
+	if !errors.As(err, &failed) || !failed.Rollback() {
+		return err
+	}
+	if !created {
+		return i18n.Wrap(err, "compose.hooks.no-rollback")
+	}
+	if downErr := service.Down(ctx, project.Name, compose.DownOptions{}); downErr != nil {
+		return i18n.Wrap(err, "compose.hooks.down-failed", downErr)
+	}
+	return i18n.Wrap(err, "compose.hooks.removed")
+}
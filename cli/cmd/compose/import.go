@@ -0,0 +1,84 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/sanathkr/go-yaml"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/audit"
+	"github.com/docker/compose-cli/i18n"
+)
+
+type importOptions struct {
+	project string
+	output  string
+}
+
+func importCommand() *cobra.Command {
+	opts := importOptions{}
+	importCmd := &cobra.Command{
+		Use:         "import RESOURCE [RESOURCE...]",
+		Annotations: audit.Mutating(),
+		Short:       "Generate a compose file from existing resources, and label them to be managed by compose up",
+		Long: `Generate a compose file from existing resources, and label them to be managed by compose up.
+Resources are an ACI container group name, or ECS services as ARNs or cluster/service references.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runImport(cmd.Context(), opts, args)
+		},
+	}
+	importCmd.Flags().StringVarP(&opts.project, "project-name", "p", "", "Project name")
+	importCmd.Flags().StringVarP(&opts.output, "output", "o", "", "Write the compose file to this path instead of the standard output")
+
+	return importCmd
+}
+
+func runImport(ctx context.Context, opts importOptions, resources []string) error {
+	c, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+
+	project, err := c.ComposeService().Import(ctx, compose.ImportOptions{
+		Project:   opts.project,
+		Resources: resources,
+	})
+	if err != nil {
+		return err
+	}
+	content, err := yaml.Marshal(compose.ComposeFile(project))
+	if err != nil {
+		return err
+	}
+	if opts.output == "" {
+		fmt.Print(string(content))
+		return nil
+	}
+	if err := ioutil.WriteFile(opts.output, content, 0644); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, i18n.T("compose.import.done", len(project.Services), project.Name, project.Name, opts.output))
+	return nil
+}
@@ -0,0 +1,98 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/i18n"
+)
+
+type inspectOptions struct {
+	composeOptions
+	outputs bool
+}
+
+type projectInspect struct {
+	Name    string
+	Status  string
+	Outputs []compose.StackOutput
+}
+
+func inspectCommand() *cobra.Command {
+	opts := inspectOptions{}
+	inspectCmd := &cobra.Command{
+		Use:   "inspect [PROJECT]",
+		Short: "Display the status and outputs of a deployed project in JSON",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				opts.Name = args[0]
+			}
+			return runInspect(cmd.Context(), opts)
+		},
+	}
+	inspectCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	inspectCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	inspectCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	inspectCmd.Flags().BoolVar(&opts.outputs, "outputs", false, "Only display the outputs of the project: endpoints, volume IDs, log groups...")
+
+	return inspectCmd
+}
+
+func runInspect(ctx context.Context, opts inspectOptions) error {
+	c, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+	projectName, err := opts.toProjectName(ctx)
+	if err != nil {
+		return err
+	}
+	outputs, err := c.ComposeService().Outputs(ctx, projectName)
+	if err != nil {
+		return err
+	}
+	var view interface{} = outputs
+	if !opts.outputs {
+		stacks, err := c.ComposeService().List(ctx, projectName)
+		if err != nil {
+			return err
+		}
+		if len(stacks) == 0 {
+			return i18n.Wrap(errdefs.ErrNotFound, "compose.project", projectName)
+		}
+		view = projectInspect{
+			Name:    stacks[0].Name,
+			Status:  stacks[0].Status,
+			Outputs: outputs,
+		}
+	}
+	out, err := json.MarshalIndent(view, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
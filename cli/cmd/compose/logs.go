@@ -18,15 +18,43 @@ package compose
 
 import (
 	"context"
+	"io"
 	"os"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/formatter"
+	"github.com/docker/compose-cli/i18n"
+	"github.com/docker/compose-cli/output"
+	"github.com/docker/compose-cli/progress"
 )
 
+type logsOptions struct {
+	composeOptions
+	format  string
+	output  string
+	export  string
+	filters []string
+	grep    string
+	level   string
+	since   string
+	until   string
+
+	previous bool
+
+	timestamps bool
+	utc        bool
+	local      bool
+}
+
 func logsCommand() *cobra.Command {
-	opts := composeOptions{}
+	opts := logsOptions{}
 	logsCmd := &cobra.Command{
 		Use: "logs",
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -36,19 +64,147 @@ func logsCommand() *cobra.Command {
 	logsCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
 	logsCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
 	logsCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	logsCmd.Flags().StringVar(&opts.format, "format", "", "Format the output. Values: [\"\" | json]")
+	logsCmd.Flags().StringVarP(&opts.output, "output", "o", "", "Write logs to a file instead of the standard output")
+	logsCmd.Flags().StringVar(&opts.export, "export", "", "Export logs to a destination, such as s3://bucket/prefix on ECS")
+	logsCmd.Flags().StringArrayVar(&opts.filters, "filter", []string{}, "Filter logs, e.g. service=web")
+	logsCmd.Flags().StringVar(&opts.grep, "grep", "", "Only show lines matching a regular expression")
+	logsCmd.Flags().StringVar(&opts.level, "level", "", "Only show JSON lines with a level field of at least this level, e.g. warn")
+	logsCmd.Flags().BoolVar(&opts.previous, "previous", false, "Show the logs of stopped containers, e.g. of a crash looping service")
+	logsCmd.Flags().StringVar(&opts.since, "since", "", "Show logs since a timestamp (e.g. 2020-10-20T13:23:37Z) or relative time (e.g. 42m)")
+	logsCmd.Flags().StringVar(&opts.until, "until", "", "Show logs before a timestamp (e.g. 2020-10-20T13:23:37Z) or relative time (e.g. 42m)")
+	logsCmd.Flags().BoolVarP(&opts.timestamps, "timestamps", "t", false, "Show timestamps")
+	logsCmd.Flags().BoolVar(&opts.utc, "utc", false, "Show timestamps in UTC (default)")
+	logsCmd.Flags().BoolVar(&opts.local, "local", false, "Show timestamps in the local timezone")
 
 	return logsCmd
 }
 
-func runLogs(ctx context.Context, opts composeOptions) error {
+func runLogs(ctx context.Context, opts logsOptions) error {
 	c, err := client.New(ctx)
 	if err != nil {
 		return err
 	}
 
-	projectName, err := opts.toProjectName()
+	projectName, err := opts.toProjectName(ctx)
+	if err != nil {
+		return err
+	}
+	if opts.export != "" {
+		_, err := progress.Run(ctx, func(ctx context.Context) (string, error) {
+			return "", c.ComposeService().Logs(ctx, projectName, nil, compose.LogOptions{Export: opts.export})
+		})
+		return err
+	}
+
+	filter, err := opts.logFilter()
 	if err != nil {
 		return err
 	}
-	return c.ComposeService().Logs(ctx, projectName, os.Stdout)
+	options, err := opts.logOptions(time.Now())
+	if err != nil {
+		return err
+	}
+	location, err := opts.timestampLocation()
+	if err != nil {
+		return err
+	}
+	var w io.Writer = os.Stdout
+	color := output.ColorEnabled(os.Stdout)
+	if opts.output != "" {
+		f, err := os.Create(opts.output)
+		if err != nil {
+			return err
+		}
+		defer f.Close() // nolint:errcheck
+		w = f
+		color = false
+	}
+	var consumer compose.LogConsumer
+	switch opts.format {
+	case "":
+		consumer = formatter.NewLogConsumer(w, color)
+	case "json":
+		consumer = formatter.NewJSONLogConsumer(w)
+	default:
+		return i18n.Wrap(errdefs.ErrParsingFailed, "compose.logs.format", opts.format)
+	}
+	if location != nil {
+		consumer = formatter.WithTimestamps(consumer, location)
+	}
+	// lines are sorted by timestamp, as backends may interleave lines of services logging to different sources
+	ordered := formatter.NewOrderedLogConsumer(consumer, formatter.LogOrderingDelay)
+	defer ordered.Close()
+	return c.ComposeService().Logs(ctx, projectName, formatter.FilterLogs(ordered, filter), options)
+}
+
+func (opts logsOptions) logOptions(now time.Time) (compose.LogOptions, error) {
+	options := compose.LogOptions{
+		Previous: opts.previous,
+	}
+	var err error
+	if opts.since != "" {
+		if options.Since, err = parseLogsTime(opts.since, now); err != nil {
+			return options, err
+		}
+	}
+	if opts.until != "" {
+		if options.Until, err = parseLogsTime(opts.until, now); err != nil {
+			return options, err
+		}
+	}
+	if !options.Since.IsZero() && !options.Until.IsZero() && !options.Since.Before(options.Until) {
+		return options, i18n.Wrap(errdefs.ErrParsingFailed, "compose.logs.since-until", opts.since, opts.until)
+	}
+	return options, nil
+}
+
+// parseLogsTime parses a RFC 3339 timestamp or a duration relative to now
+func parseLogsTime(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return t, i18n.Wrap(errdefs.ErrParsingFailed, "compose.logs.time", value)
+	}
+	return t, nil
+}
+
+// timestampLocation returns the location log timestamps are displayed in, nil when they are not displayed
+func (opts logsOptions) timestampLocation() (*time.Location, error) {
+	switch {
+	case opts.utc && opts.local:
+		return nil, i18n.Error("compose.logs.utc-local")
+	case (opts.utc || opts.local) && !opts.timestamps && opts.format == "":
+		return nil, i18n.Error("compose.logs.timestamps")
+	case opts.local:
+		return time.Local, nil
+	case opts.utc || opts.timestamps || opts.format == "json":
+		return time.UTC, nil
+	}
+	return nil, nil
+}
+
+func (opts logsOptions) logFilter() (formatter.LogFilter, error) {
+	filter := formatter.LogFilter{}
+	for _, f := range opts.filters {
+		parts := strings.SplitN(f, "=", 2)
+		if len(parts) != 2 || parts[0] != "service" {
+			return filter, i18n.Wrap(errdefs.ErrParsingFailed, "compose.logs.filter", f)
+		}
+		filter.Services = append(filter.Services, parts[1])
+	}
+	if opts.grep != "" {
+		pattern, err := regexp.Compile(opts.grep)
+		if err != nil {
+			return filter, i18n.Wrap(errdefs.ErrParsingFailed, "compose.logs.grep", opts.grep, err)
+		}
+		filter.Pattern = pattern
+	}
+	if opts.level != "" && !formatter.IsLogLevel(opts.level) {
+		return filter, i18n.Wrap(errdefs.ErrParsingFailed, "compose.logs.level", opts.level)
+	}
+	filter.Level = opts.level
+	return filter, nil
 }
@@ -0,0 +1,86 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/compose-spec/compose-go/cli"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+)
+
+func networkCommand() *cobra.Command {
+	networkCmd := &cobra.Command{
+		Use:   "network",
+		Short: "Inspect how services reach each other",
+	}
+	networkCmd.AddCommand(networkInspectCommand())
+	return networkCmd
+}
+
+func networkInspectCommand() *cobra.Command {
+	opts := composeOptions{}
+	inspectCmd := &cobra.Command{
+		Use:   "inspect",
+		Short: "Show how services resolve each other on the current backend",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runNetworkInspect(cmd.Context(), opts)
+		},
+	}
+	inspectCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	inspectCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	inspectCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	inspectCmd.Flags().StringArrayVarP(&opts.Environment, "environment", "e", []string{}, "Environment variables")
+
+	return inspectCmd
+}
+
+func runNetworkInspect(ctx context.Context, opts composeOptions) error {
+	c, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+
+	options, err := opts.toProjectOptions(ctx)
+	if err != nil {
+		return err
+	}
+	project, err := cli.ProjectFromOptions(options)
+	if err != nil {
+		return err
+	}
+
+	discovery, err := c.ComposeService().Discovery(ctx, project)
+	if err != nil {
+		return err
+	}
+	return printSection(os.Stdout, func(w io.Writer) {
+		for _, d := range discovery {
+			ports := make([]string, len(d.Ports))
+			for i, p := range d.Ports {
+				ports[i] = fmt.Sprint(p)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", d.Service, strings.Join(d.Hostnames, ","), d.Address, strings.Join(ports, ","), d.Mechanism)
+		}
+	}, "SERVICE", "HOSTNAMES", "ADDRESS", "PORTS", "RESOLUTION")
+}
@@ -0,0 +1,46 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+
+	"github.com/compose-spec/compose-go/types"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/notify"
+)
+
+// notifyEvent fires the hooks of the context and the project on a lifecycle event, warning when they fail as the
+// event already happened. project may be nil when the command didn't load the compose file.
+func notifyEvent(ctx context.Context, event string, projectName string, project *types.Project, err error) {
+	settings := currentContextSettings(ctx)
+	hooks := settings.NotificationHooks
+	if project != nil {
+		projectHooks, hooksErr := notify.ProjectHooks(project)
+		if hooksErr != nil {
+			compose.Warn(ctx, "ignoring project notifications: %s", hooksErr)
+		}
+		hooks = append(hooks, projectHooks...)
+	}
+	if len(hooks) == 0 {
+		return
+	}
+	if err := notify.Notify(ctx, hooks, notify.NewEvent(event, projectName, settings.Name, err)); err != nil {
+		compose.Warn(ctx, "failed to notify %s: %s", event, err)
+	}
+}
@@ -0,0 +1,63 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/cli/options/portforward"
+	"github.com/docker/compose-cli/i18n"
+)
+
+func portForwardCommand() *cobra.Command {
+	opts := composeOptions{}
+	portForwardCmd := &cobra.Command{
+		Use:   "port-forward SERVICE LOCAL:REMOTE",
+		Short: "Forward a local port to a port of a service container",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPortForward(cmd.Context(), opts, args[0], args[1])
+		},
+	}
+	portForwardCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	portForwardCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	portForwardCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+
+	return portForwardCmd
+}
+
+func runPortForward(ctx context.Context, opts composeOptions, service string, spec string) error {
+	localPort, remotePort, err := portforward.Parse(spec)
+	if err != nil {
+		return err
+	}
+	c, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+	projectName, err := opts.toProjectName(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(i18n.T("compose.portforward", localPort, remotePort, service))
+	return c.ComposeService().PortForward(ctx, projectName, service, localPort, remotePort)
+}
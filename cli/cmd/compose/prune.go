@@ -0,0 +1,92 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/audit"
+	"github.com/docker/compose-cli/i18n"
+	"github.com/docker/compose-cli/progress"
+	"github.com/docker/compose-cli/prompt"
+)
+
+type pruneOpts struct {
+	force  bool
+	dryRun bool
+}
+
+func pruneCommand() *cobra.Command {
+	opts := pruneOpts{}
+	pruneCmd := &cobra.Command{
+		Use:         "prune",
+		Annotations: audit.Mutating(),
+		Short:       "Remove resources labelled with projects which are not deployed anymore",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPrune(cmd.Context(), opts)
+		},
+	}
+	pruneCmd.Flags().BoolVarP(&opts.force, "force", "f", false, "Do not prompt for confirmation")
+	pruneCmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Only list orphan resources")
+	return pruneCmd
+}
+
+func runPrune(ctx context.Context, opts pruneOpts) error {
+	c, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+	orphans, err := c.ComposeService().Prune(ctx, compose.PruneOptions{DryRun: true})
+	if err != nil {
+		return err
+	}
+	if len(orphans) == 0 {
+		fmt.Println(i18n.T("compose.prune.none"))
+		return nil
+	}
+	err = printSection(os.Stdout, func(w io.Writer) {
+		for _, r := range orphans {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", r.Type, r.ID, r.Project)
+		}
+	}, "TYPE", "ID", "PROJECT")
+	if err != nil || opts.dryRun {
+		return err
+	}
+
+	if !opts.force {
+		confirm, err := prompt.User{}.Confirm(i18n.T("compose.prune.confirm", len(orphans)), false)
+		if err != nil || !confirm {
+			return err
+		}
+	}
+	ids := make([]string, len(orphans))
+	for i, r := range orphans {
+		ids[i] = r.ID
+	}
+	_, err = progress.Run(ctx, func(ctx context.Context) (string, error) {
+		_, err := c.ComposeService().Prune(ctx, compose.PruneOptions{Resources: ids})
+		return "", err
+	})
+	return err
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/formatter"
 )
 
 func psCommand() *cobra.Command {
@@ -50,7 +51,7 @@ func runPs(ctx context.Context, opts composeOptions) error {
 		return err
 	}
 
-	projectName, err := opts.toProjectName()
+	projectName, err := opts.toProjectName(ctx)
 	if err != nil {
 		return err
 	}
@@ -59,12 +60,7 @@ func runPs(ctx context.Context, opts composeOptions) error {
 		return err
 	}
 
-	err = printSection(os.Stdout, func(w io.Writer) {
-		for _, service := range serviceList {
-			fmt.Fprintf(w, "%s\t%s\t%d/%d\t%s\n", service.ID, service.Name, service.Replicas, service.Desired, strings.Join(service.Ports, ", "))
-		}
-	}, "ID", "NAME", "REPLICAS", "PORTS")
-	return err
+	return formatter.PrintServiceStatus(os.Stdout, serviceList)
 }
 
 func printSection(out io.Writer, printer func(io.Writer), headers ...string) error {
@@ -0,0 +1,88 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/audit"
+	"github.com/docker/compose-cli/provenance"
+	"github.com/docker/compose-cli/registry"
+)
+
+type publishOptions struct {
+	composeOptions
+	sign bool
+	key  string
+}
+
+func publishCommand() *cobra.Command {
+	opts := publishOptions{}
+	publishCmd := &cobra.Command{
+		Use:         "publish REPOSITORY[:TAG]",
+		Annotations: audit.Mutating(),
+		Short:       "Publish the resolved compose file as an OCI artifact, to be deployed with compose up -f oci://REPOSITORY[:TAG]",
+		Args:        cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPublish(cmd.Context(), opts, args[0])
+		},
+	}
+	publishCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	publishCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	publishCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	publishCmd.Flags().StringArrayVarP(&opts.Environment, "environment", "e", []string{}, "Environment variables")
+	publishCmd.Flags().StringArrayVar(&opts.Overrides, "set", []string{}, "Override a compose file attribute, as path=value (e.g. services.web.deploy.replicas=2)")
+	publishCmd.Flags().BoolVar(&opts.sign, "sign", false, "Pin images, then sign the artifact and attest its provenance with cosign")
+	publishCmd.Flags().StringVar(&opts.key, "key", "", "Cosign private key signing the artifact (default: keyless signing)")
+
+	return publishCmd
+}
+
+func runPublish(ctx context.Context, opts publishOptions, repository string) error {
+	project, err := opts.toProject(ctx)
+	if err != nil {
+		return err
+	}
+	if opts.sign {
+		// attested images must not change once published
+		if err := registry.PinImages(ctx, project); err != nil {
+			return err
+		}
+	}
+	content, attachments, err := compose.PublishableFile(project)
+	if err != nil {
+		return err
+	}
+	pushed, err := registry.PushComposeArtifact(ctx, repository, registry.ComposeArtifact{
+		ComposeFile: content,
+		Attachments: attachments,
+	})
+	if err != nil {
+		return err
+	}
+	if opts.sign {
+		if err := provenance.Sign(ctx, pushed, opts.key, provenance.NewPredicate(content, attachments, project)); err != nil {
+			return err
+		}
+	}
+	fmt.Println(pushed)
+	return nil
+}
@@ -0,0 +1,83 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/progress"
+	"github.com/docker/compose-cli/sbom"
+)
+
+type sbomOptions struct {
+	composeOptions
+	format string
+	output string
+}
+
+func sbomCommand() *cobra.Command {
+	opts := sbomOptions{}
+	sbomCmd := &cobra.Command{
+		Use:   "sbom",
+		Short: "Generate a software bill of materials covering the images of the application services",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSbom(cmd.Context(), opts)
+		},
+	}
+	sbomCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	sbomCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	sbomCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	sbomCmd.Flags().StringArrayVarP(&opts.Environment, "environment", "e", []string{}, "Environment variables")
+	sbomCmd.Flags().StringVar(&opts.format, "format", sbom.FormatCycloneDX, "Document format. Values: [cyclonedx | spdx]")
+	sbomCmd.Flags().StringVarP(&opts.output, "output", "o", "", "Write the document to a file instead of the standard output")
+
+	return sbomCmd
+}
+
+func runSbom(ctx context.Context, opts sbomOptions) error {
+	c, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+	project, err := opts.toProject(ctx)
+	if err != nil {
+		return err
+	}
+	deployment := sbom.Deployment{Project: project.Name}
+	// the document is annotated with the deployed application, when there is one
+	if stacks, err := c.ComposeService().List(ctx, project.Name); err == nil && len(stacks) > 0 {
+		deployment.ID = stacks[0].ID
+	}
+
+	document, err := progress.Run(ctx, func(ctx context.Context) (string, error) {
+		document, err := sbom.Generate(ctx, project, opts.format, deployment)
+		return string(document), err
+	})
+	if err != nil {
+		return err
+	}
+	if opts.output != "" {
+		return ioutil.WriteFile(opts.output, []byte(document), 0644)
+	}
+	fmt.Println(document)
+	return nil
+}
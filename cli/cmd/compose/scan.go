@@ -0,0 +1,150 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/compose-spec/compose-go/types"
+
+	apicontext "github.com/docker/compose-cli/context"
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/i18n"
+	"github.com/docker/compose-cli/progress"
+	"github.com/docker/compose-cli/scan"
+)
+
+// defaultScanSeverity is the threshold of compose up --scan when the context sets none
+const defaultScanSeverity = "HIGH"
+
+type scanOptions struct {
+	enabled  bool
+	severity string
+	report   string
+}
+
+// threshold returns the severity deployment is blocked from, and whether images should be scanned at all, as set
+// by the flags or the context policy
+func (opts scanOptions) threshold(ctx context.Context) (scan.Severity, bool, error) {
+	severity := opts.severity
+	if severity == "" {
+		severity = contextScanSeverity(ctx)
+	}
+	if !opts.enabled && severity == "" {
+		return scan.Unknown, false, nil
+	}
+	if severity == "" {
+		severity = defaultScanSeverity
+	}
+	threshold, err := scan.ParseSeverity(severity)
+	return threshold, true, err
+}
+
+func contextScanSeverity(ctx context.Context) string {
+	return currentContextSettings(ctx).ScanSeverity
+}
+
+// contextSettings are the compose settings of cloud contexts
+type contextSettings struct {
+	Name         string
+	ScanSeverity string
+	store.DeploymentAnnotations
+	store.Notifications
+}
+
+// currentContextSettings returns the compose settings of the current context, empty for contexts other than cloud ones
+func currentContextSettings(ctx context.Context) contextSettings {
+	s := store.ContextStore(ctx)
+	name := apicontext.CurrentContext(ctx)
+	settings := contextSettings{Name: name}
+	cc, err := s.Get(name)
+	if err != nil {
+		return settings
+	}
+	switch cc.Type() {
+	case store.AciContextType:
+		var aciContext store.AciContext
+		if err := s.GetEndpoint(name, &aciContext); err == nil {
+			settings.ScanSeverity = aciContext.ScanSeverity
+			settings.DeploymentAnnotations = aciContext.DeploymentAnnotations
+			settings.Notifications = aciContext.Notifications
+		}
+	case store.EcsContextType:
+		var ecsContext store.EcsContext
+		if err := s.GetEndpoint(name, &ecsContext); err == nil {
+			settings.ScanSeverity = ecsContext.ScanSeverity
+			settings.DeploymentAnnotations = ecsContext.DeploymentAnnotations
+			settings.Notifications = ecsContext.Notifications
+		}
+	}
+	return settings
+}
+
+// scanProject scans the service images and prints the report, then fails if vulnerabilities reach the threshold
+func scanProject(ctx context.Context, project *types.Project, opts scanOptions, threshold scan.Severity) error {
+	var report scan.Report
+	_, err := progress.Run(ctx, func(ctx context.Context) (string, error) {
+		var err error
+		report, err = scan.Project(ctx, project)
+		return "", err
+	})
+	if err != nil {
+		return err
+	}
+	switch opts.report {
+	case "":
+		if err := printScanReport(os.Stdout, report, threshold); err != nil {
+			return err
+		}
+	case "json":
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+	default:
+		return i18n.Wrap(errdefs.ErrParsingFailed, "compose.scan.report-format", opts.report)
+	}
+	return report.Check(threshold)
+}
+
+// printScanReport lists the vulnerabilities of at least the threshold severity
+func printScanReport(out io.Writer, report scan.Report, threshold scan.Severity) error {
+	found := false
+	for _, image := range report.Images {
+		for _, v := range image.Vulnerabilities {
+			found = found || v.Severity >= threshold
+		}
+	}
+	if !found {
+		return nil
+	}
+	return printSection(out, func(w io.Writer) {
+		for _, image := range report.Images {
+			for _, v := range image.Vulnerabilities {
+				if v.Severity >= threshold {
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", image.Service, image.Image, v.ID, v.Severity, v.Package, v.InstalledVersion, v.FixedVersion)
+				}
+			}
+		}
+	}, "SERVICE", "IMAGE", "VULNERABILITY", "SEVERITY", "PACKAGE", "VERSION", "FIXED IN")
+}
@@ -0,0 +1,105 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"os"
+
+	"github.com/containerd/console"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/audit"
+	"github.com/docker/compose-cli/config"
+	apicontext "github.com/docker/compose-cli/context"
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/dashboard"
+	"github.com/docker/compose-cli/i18n"
+)
+
+func uiCommand() *cobra.Command {
+	opts := composeOptions{}
+	uiCmd := &cobra.Command{
+		Use:   "ui",
+		Short: "Display an interactive dashboard of the project services, logs and events",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUI(cmd.Context(), opts)
+		},
+	}
+	uiCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	uiCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	uiCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+
+	return uiCmd
+}
+
+func runUI(ctx context.Context, opts composeOptions) error {
+	c, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+	project, err := opts.toProject(ctx)
+	if err != nil {
+		return err
+	}
+	con, err := console.ConsoleFromFile(os.Stdin)
+	if err != nil {
+		return i18n.Error("compose.ui.terminal")
+	}
+	if err := con.SetRaw(); err != nil {
+		return err
+	}
+	defer con.Reset() // nolint:errcheck
+
+	return dashboard.Run(ctx, c.ComposeService(), project, dashboard.Terminal{
+		In:  con,
+		Out: os.Stdout,
+		Size: func() (int, int) {
+			size, err := con.Size()
+			if err != nil {
+				return 80, 24
+			}
+			return int(size.Width), int(size.Height)
+		},
+	}, auditAction(ctx, project.Name))
+}
+
+// auditAction records the restart and scale actions of the dashboard in the audit log, as the ui command itself only
+// displays the project
+func auditAction(ctx context.Context, projectName string) func(dashboard.Action, error) {
+	contextName := apicontext.CurrentContext(ctx)
+	contextType := store.DefaultContextType
+	if cc, err := store.ContextStore(ctx).Get(contextName); err == nil {
+		contextType = cc.Type()
+	}
+	return func(action dashboard.Action, err error) {
+		command := "compose ui restart"
+		resources := []string{"project:" + projectName}
+		if action.Kind == dashboard.ScaleAction {
+			command = "compose ui scale"
+			resources = append([]string{action.Service}, resources...)
+		}
+		status := audit.SuccessStatus
+		if err != nil {
+			status = audit.FailureStatus
+		}
+		// the dashboard owns the terminal, audit log failures are not reported
+		_ = audit.TrackAction(config.Dir(ctx), contextName, contextType, command, resources, status, err)
+	}
+}
@@ -0,0 +1,62 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/audit"
+	"github.com/docker/compose-cli/i18n"
+)
+
+func unlockCommand() *cobra.Command {
+	opts := composeOptions{}
+	unlockCmd := &cobra.Command{
+		Use:         "unlock",
+		Annotations: audit.Mutating(),
+		Short:       "Release the project lock left over by an interrupted command",
+		Long:        "Forcibly release the project lock. A command still running while its lock is released may interleave its changes with the next one.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUnlock(cmd.Context(), opts)
+		},
+	}
+	unlockCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	unlockCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	unlockCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+
+	return unlockCmd
+}
+
+func runUnlock(ctx context.Context, opts composeOptions) error {
+	c, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+	projectName, err := opts.toProjectName(ctx)
+	if err != nil {
+		return err
+	}
+	if err := c.ComposeService().Unlock(ctx, projectName); err != nil {
+		return err
+	}
+	fmt.Println(i18n.T("compose.unlock.done", projectName))
+	return nil
+}
@@ -18,58 +18,279 @@ package compose
 
 import (
 	"context"
+	"errors"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/compose-spec/compose-go/types"
 	"github.com/spf13/cobra"
 
-	"github.com/compose-spec/compose-go/cli"
-
 	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/audit"
 	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/i18n"
 	"github.com/docker/compose-cli/progress"
+	"github.com/docker/compose-cli/prompt"
+	"github.com/docker/compose-cli/provenance"
 )
 
 func upCommand(contextType string) *cobra.Command {
 	opts := composeOptions{}
+	upOpts := compose.UpOptions{}
+	var forceRecreate, noRecreate, estimateCost, jsonEvents bool
+	var deploymentVersion string
+	verify := verifyOptions{}
+	smoke := smokeTestOptions{}
+	wait := waitOptions{}
+	abort := abortOptions{}
+	scanOpts := scanOptions{}
 	upCmd := &cobra.Command{
-		Use: "up",
+		Use:         "up",
+		Annotations: audit.Mutating(),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runUp(cmd.Context(), opts)
+			switch {
+			case forceRecreate && noRecreate:
+				return i18n.Error("compose.up.recreate-flags")
+			case forceRecreate:
+				upOpts.Recreate = compose.RecreateForce
+			case noRecreate:
+				upOpts.Recreate = compose.RecreateNever
+			default:
+				upOpts.Recreate = compose.RecreateDiverged
+			}
+			if abort.exitCodeFrom != "" {
+				abort.enabled = true
+			}
+			if abort.enabled && (wait.enabled || len(smoke.urls) > 0) {
+				return i18n.Error("compose.up.abort-flags")
+			}
+			ctx := cmd.Context()
+			if jsonEvents {
+				ctx = progress.WithJSONEvents(ctx, os.Stdout, progress.Creation)
+			}
+			return runUp(ctx, opts, upOpts, estimateCost, verify, smoke, wait, abort, scanOpts, deploymentVersion)
 		},
 	}
 	upCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
 	upCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
 	upCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
 	upCmd.Flags().StringArrayVarP(&opts.Environment, "environment", "e", []string{}, "Environment variables")
+	upCmd.Flags().StringArrayVar(&opts.Overrides, "set", []string{}, "Override a compose file attribute, as path=value (e.g. services.web.deploy.replicas=2)")
 	upCmd.Flags().BoolP("detach", "d", true, " Detached mode: Run containers in the background")
+	upCmd.Flags().BoolVar(&upOpts.ResolveImageDigests, "resolve-image-digests", false, "Pin service images to the digest their tag currently resolves to")
+	upCmd.Flags().StringSliceVar(&smoke.urls, "smoke-test", nil, "URLs polled once deployed, failing the command if they don't return a 2xx status")
+	upCmd.Flags().DurationVar(&smoke.timeout, "smoke-test-timeout", compose.DefaultSmokeTestTimeout, "Time endpoints have to pass the smoke test")
+	upCmd.Flags().BoolVar(&wait.enabled, "wait", false, "Wait for services to run their desired replicas, reporting crash looping services")
+	upCmd.Flags().DurationVar(&wait.timeout, "wait-timeout", compose.DefaultWaitTimeout, "Time services have to run with --wait")
+	upCmd.Flags().BoolVar(&abort.enabled, "abort-on-container-exit", false, "Remove the application once a container exits, after jobs completed if the project has some")
+	upCmd.Flags().StringVar(&abort.exitCodeFrom, "exit-code-from", "", "Exit with the status of this job or service, removing the application once it exited. Implies --abort-on-container-exit")
+	upCmd.Flags().BoolVar(&jsonEvents, "json-events", false, "Write progress as a stream of JSON events on the standard output, for programs")
+	opts.addLockFlag(upCmd.Flags())
 
+	if contextType == store.AciContextType || contextType == store.EcsContextType {
+		upCmd.Flags().BoolVar(&forceRecreate, "force-recreate", false, "Recreate services even if their configuration hasn't changed")
+		upCmd.Flags().BoolVar(&noRecreate, "no-recreate", false, "If services already exist, don't recreate them")
+		upCmd.Flags().BoolVar(&upOpts.OverrideBudget, "override-budget", false, "Deploy even if the application exceeds the context budget")
+		upCmd.Flags().BoolVar(&estimateCost, "estimate-cost", false, "Print the estimated monthly cost and ask for confirmation before deploying")
+		upCmd.Flags().BoolVar(&verify.enabled, "verify", false, "Verify the signature and provenance of the compose file published with compose publish --sign")
+		upCmd.Flags().StringVar(&verify.trust.Key, "verify-key", "", "Cosign public key verifying the application signature (default: keyless verification)")
+		upCmd.Flags().StringVar(&verify.trust.Identity, "verify-identity", "", "Identity of the keyless signer of the application, e.g. an email or a CI workflow URL")
+		upCmd.Flags().StringVar(&verify.trust.Issuer, "verify-issuer", "", "OIDC issuer certifying the keyless signer identity, e.g. https://token.actions.githubusercontent.com")
+		upCmd.Flags().BoolVar(&upOpts.Resume, "resume", false, "Wait for a deployment left in progress by an interrupted command, and resume from there")
+		upCmd.Flags().BoolVar(&upOpts.CancelCleanup, "cancel-cleanup", false, "Delete or roll back resources being deployed when the command is canceled")
+		upCmd.Flags().StringVar(&deploymentVersion, "deployment-version", "", "Version the deployment is annotated with on the monitoring tools set on the context, e.g. a git revision")
+		upCmd.Flags().StringToStringVar(&upOpts.Tags, "label", nil, "Tag applied to the cloud resources of the application, as KEY=VALUE")
+		upCmd.Flags().BoolVar(&scanOpts.enabled, "scan", false, "Scan service images for vulnerabilities before deploying them")
+		upCmd.Flags().StringVar(&scanOpts.severity, "scan-severity", "", "Refuse to deploy images with vulnerabilities of this severity or above (default: context policy, or HIGH)")
+		upCmd.Flags().StringVar(&scanOpts.report, "scan-report", "", "Format of the scan report. Values: [\"\" | json]")
+	}
 	if contextType == store.AciContextType {
 		upCmd.Flags().StringVar(&opts.DomainName, "domainname", "", "Container NIS domain name")
+		upCmd.Flags().StringVar(&upOpts.Builder, "builder", "", "Build service images remotely with the given builder (acr)")
+	}
+	if contextType == store.EcsContextType {
+		upCmd.Flags().BoolVar(&upOpts.Push, "push", false, "Build service images and push them to Amazon ECR before deployment")
+		upCmd.Flags().StringSliceVar(&upOpts.Regions, "regions", nil, "Deploy the project to each of these regions concurrently, instead of the context region")
 	}
 
 	return upCmd
 }
 
-func runUp(ctx context.Context, opts composeOptions) error {
-	c, err := client.New(ctx)
+type verifyOptions struct {
+	enabled bool
+	trust   provenance.Trust
+}
+
+type smokeTestOptions struct {
+	urls    []string
+	timeout time.Duration
+}
+
+type waitOptions struct {
+	enabled bool
+	timeout time.Duration
+}
+
+type abortOptions struct {
+	enabled      bool
+	exitCodeFrom string
+}
+
+func (a abortOptions) check(project *types.Project) error {
+	if a.exitCodeFrom == "" {
+		return nil
+	}
+	service, err := project.GetService(a.exitCodeFrom)
 	if err != nil {
 		return err
 	}
+	if compose.IsScheduled(service) || compose.HookJobs(project)[service.Name] {
+		return i18n.Error("compose.up.exit-code-from", service.Name)
+	}
+	return nil
+}
 
-	_, err = progress.Run(ctx, func(ctx context.Context) (string, error) {
-		options, err := opts.toProjectOptions()
+// run waits for a container to exit once up returned, removes the application and returns the exit code of the
+// command. Jobs exit during up, so without --exit-code-from naming a service running continuously, the application is
+// removed as soon as it is deployed when the project has some.
+func (a abortOptions) run(ctx context.Context, service compose.Service, project *types.Project, upErr error) (int, error) {
+	exitCode := 0
+	var failed compose.JobFailedError
+	switch {
+	case a.exitCodeFrom != "" && errors.As(upErr, &failed) && failed.Job == a.exitCodeFrom:
+		exitCode = failed.ExitCode
+	case upErr != nil:
+		return 0, upErr
+	case a.exitCodeFrom != "" && !isJob(project, a.exitCodeFrom):
+		exit, err := compose.WaitForContainerExit(ctx, service, project, a.exitCodeFrom)
 		if err != nil {
-			return "", err
+			return 0, err
 		}
-		project, err := cli.ProjectFromOptions(options)
-		if opts.DomainName != "" {
-			//arbitrarily set the domain name on the first service ; ACI backend will expose the entire project
-			project.Services[0].DomainName = opts.DomainName
+		exitCode = exit.ExitCode
+	case a.exitCodeFrom == "" && len(compose.Jobs(project)) == 0:
+		exit, err := compose.WaitForContainerExit(ctx, service, project)
+		if err != nil {
+			return 0, err
+		}
+		exitCode = exit.ExitCode
+	}
+	return exitCode, service.Down(ctx, project.Name, compose.DownOptions{})
+}
+
+func isJob(project *types.Project, name string) bool {
+	service, err := project.GetService(name)
+	return err == nil && compose.IsJob(service)
+}
+
+func runUp(ctx context.Context, opts composeOptions, upOpts compose.UpOptions, estimateCost bool, verify verifyOptions, smoke smokeTestOptions, wait waitOptions, abort abortOptions, scanOpts scanOptions, deploymentVersion string) error {
+	c, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		artifact  string
+		predicate provenance.Predicate
+	)
+	if verify.enabled {
+		if len(opts.ConfigPaths) != 1 || !strings.HasPrefix(opts.ConfigPaths[0], "oci://") {
+			return i18n.Error("compose.up.verify-oci")
 		}
+		if verify.trust.Check() != nil {
+			return i18n.Error("compose.up.verify-keyless")
+		}
+		artifact, predicate, err = provenance.VerifyArtifact(ctx, strings.TrimPrefix(opts.ConfigPaths[0], "oci://"), verify.trust)
 		if err != nil {
-			return "", err
+			return err
+		}
+		// deploy the verified content, even if the tag has moved since
+		opts.ConfigPaths = []string{"oci://" + artifact}
+	}
+
+	project, err := opts.toProject(ctx)
+	if err != nil {
+		return err
+	}
+	if verify.enabled {
+		if err := predicate.CheckProject(project); err != nil {
+			return err
+		}
+		if upOpts.Tags == nil {
+			upOpts.Tags = map[string]string{}
 		}
+		upOpts.Tags[compose.ProvenanceTag] = artifact
+		upOpts.Tags[compose.ComposeFileDigestTag] = predicate.ComposeFile
+	}
+	smokeTests, err := compose.SmokeTests(project, smoke.urls)
+	if err != nil {
+		return err
+	}
+	hooks, err := compose.ProjectHooks(project)
+	if err != nil {
+		return err
+	}
+	if err := abort.check(project); err != nil {
+		return err
+	}
+	threshold, scanImages, err := scanOpts.threshold(ctx)
+	if err != nil {
+		return err
+	}
+	if scanImages {
+		if err := scanProject(ctx, project, scanOpts, threshold); err != nil {
+			return err
+		}
+	}
+	if opts.DomainName != "" {
+		//arbitrarily set the domain name on the first service ; ACI backend will expose the entire project
+		project.Services[0].DomainName = opts.DomainName
+	}
+
+	if estimateCost {
+		if _, err := printCostEstimate(ctx, c.ComposeService(), project); err != nil {
+			return err
+		}
+		confirm, err := prompt.User{}.Confirm(i18n.T("compose.up.confirm"), false)
+		if err != nil || !confirm {
+			return err
+		}
+	}
 
-		return "", c.ComposeService().Up(ctx, project)
+	exitCode := 0
+	_, err = progress.Run(ctx, func(ctx context.Context) (string, error) {
+		err := withLock(ctx, c.ComposeService(), project.Name, opts.LockTimeout, func() error {
+			err := upWithHooks(ctx, c.ComposeService(), project, upOpts, hooks)
+			if !abort.enabled {
+				return err
+			}
+			exitCode, err = abort.run(ctx, c.ComposeService(), project, err)
+			return err
+		})
+		if err != nil || abort.enabled {
+			return "", err
+		}
+		if wait.enabled {
+			if err := compose.WaitForServices(ctx, c.ComposeService(), project, wait.timeout); err != nil {
+				return "", err
+			}
+		}
+		return "", compose.RunSmokeTests(ctx, smokeTests, smoke.timeout)
 	})
-	return err
+	if err != nil {
+		notifyEvent(ctx, store.UpFailedEvent, project.Name, project, err)
+		return err
+	}
+	if abort.enabled {
+		notifyEvent(ctx, store.DownEvent, project.Name, project, nil)
+		if exitCode != 0 {
+			return errdefs.ExitCodeError{Code: exitCode}
+		}
+		return nil
+	}
+	annotateDeployment(ctx, project, deploymentVersion)
+	notifyEvent(ctx, store.UpSucceededEvent, project.Name, project, nil)
+	return nil
 }
@@ -0,0 +1,92 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/audit"
+	"github.com/docker/compose-cli/config"
+)
+
+// ConfigCLICommand reads and changes the user defaults of the CLI
+func ConfigCLICommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config-cli",
+		Short: "Manage the CLI defaults stored in " + config.CLIConfigFileName,
+	}
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "get [KEY]",
+			Short: "Print a setting, or all settings when no key is given",
+			Args:  cobra.MaximumNArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return runConfigCLIGet(cmd.Context(), os.Stdout, args)
+			},
+		},
+		&cobra.Command{
+			Use:         "set KEY VALUE",
+			Annotations: audit.Mutating(),
+			Short:       "Change a setting, an empty value removing it",
+			Args:        cobra.ExactArgs(2),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return runConfigCLISet(cmd.Context(), args[0], args[1])
+			},
+		},
+	)
+	return cmd
+}
+
+func runConfigCLIGet(ctx context.Context, out io.Writer, args []string) error {
+	c, err := config.LoadCLIConfig(config.Dir(ctx))
+	if err != nil {
+		return err
+	}
+	if len(args) == 1 {
+		value, err := c.Get(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, value)
+		return nil
+	}
+	for _, key := range c.Keys() {
+		value, err := c.Get(key)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s=%s\n", key, value)
+	}
+	return nil
+}
+
+func runConfigCLISet(ctx context.Context, key string, value string) error {
+	dir := config.Dir(ctx)
+	c, err := config.LoadCLIConfig(dir)
+	if err != nil {
+		return err
+	}
+	if err := c.Set(key, value); err != nil {
+		return err
+	}
+	return config.WriteCLIConfig(dir, c)
+}
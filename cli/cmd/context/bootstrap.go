@@ -0,0 +1,115 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package context
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/aci"
+	"github.com/docker/compose-cli/audit"
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/ecs"
+	"github.com/docker/compose-cli/formatter"
+	"github.com/docker/compose-cli/i18n"
+)
+
+type bootstrapOpts struct {
+	name  string
+	apply bool
+}
+
+func bootstrapCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bootstrap",
+		Short: "Generate the least-privilege role or policy developers need with a cloud context",
+	}
+	cmd.AddCommand(
+		bootstrapEcsCommand(),
+		bootstrapAciCommand(),
+	)
+	return cmd
+}
+
+func bootstrapEcsCommand() *cobra.Command {
+	var opts bootstrapOpts
+	var ecsContext store.EcsContext
+	cmd := &cobra.Command{
+		Use:         "ecs [flags]",
+		Annotations: audit.MutatingWithFlag("apply"),
+		Short:       "Generate the IAM policy needed by Amazon ECS contexts",
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !opts.apply {
+				document, err := ecs.BootstrapPolicy()
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(document))
+				return nil
+			}
+			arn, err := ecs.ApplyBootstrapPolicy(cmd.Context(), ecsContext, opts.name)
+			if err != nil {
+				return err
+			}
+			fmt.Println(i18n.T("context.bootstrap.policy", arn))
+			return nil
+		},
+	}
+	addBootstrapFlags(cmd, &opts)
+	cmd.Flags().StringVar(&ecsContext.Profile, "profile", "", "AWS profile used to create the policy with --apply")
+	cmd.Flags().StringVar(&ecsContext.Region, "region", "us-east-1", "AWS region used to create the policy with --apply")
+	return cmd
+}
+
+func bootstrapAciCommand() *cobra.Command {
+	var opts bootstrapOpts
+	var aciContext store.AciContext
+	cmd := &cobra.Command{
+		Use:         "aci [flags]",
+		Annotations: audit.MutatingWithFlag("apply"),
+		Short:       "Generate the Azure custom role needed by ACI contexts",
+		Args:        cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !opts.apply {
+				j, err := formatter.ToStandardJSON(aci.BootstrapRole(opts.name, aciContext.SubscriptionID, aciContext.ResourceGroup))
+				if err != nil {
+					return err
+				}
+				fmt.Println(j)
+				return nil
+			}
+			id, err := aci.ApplyBootstrapRole(cmd.Context(), aciContext, opts.name)
+			if err != nil {
+				return err
+			}
+			fmt.Println(i18n.T("context.bootstrap.role", id))
+			return nil
+		},
+	}
+	addBootstrapFlags(cmd, &opts)
+	cmd.Flags().StringVar(&aciContext.SubscriptionID, "subscription-id", "", "Subscription the role is defined in")
+	cmd.Flags().StringVar(&aciContext.ResourceGroup, "resource-group", "", "Resource group the role is assignable to, the whole subscription if empty")
+	_ = cmd.MarkFlagRequired("subscription-id")
+	return cmd
+}
+
+func addBootstrapFlags(cmd *cobra.Command, opts *bootstrapOpts) {
+	cmd.Flags().StringVar(&opts.name, "name", "compose-cli-developer", "Name of the role or policy")
+	cmd.Flags().BoolVar(&opts.apply, "apply", false, "Create the role or policy in the cloud account instead of printing it")
+}
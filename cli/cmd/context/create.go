@@ -19,12 +19,16 @@ package context
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/docker/compose-cli/audit"
 	"github.com/docker/compose-cli/cli/mobycli"
 	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/i18n"
 )
 
 type descriptionCreateOpts struct {
@@ -70,7 +74,7 @@ $ docker context create my-context --description "some description" --docker "ho
 		Use:   "create CONTEXT",
 		Short: "Create new context",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			mobycli.Exec(cmd.Root())
+			mobycli.Exec(cmd.Context(), cmd.Root())
 			return nil
 		},
 		Long: longHelp,
@@ -96,34 +100,33 @@ $ docker context create my-context --description "some description" --docker "ho
 	return cmd
 }
 
-func createLocalCommand() *cobra.Command {
-	var opts descriptionCreateOpts
-	cmd := &cobra.Command{
-		Use:    "local CONTEXT",
-		Short:  "Create a context for accessing local engine",
-		Args:   cobra.ExactArgs(1),
-		Hidden: true,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			return createDockerContext(cmd.Context(), args[0], store.LocalContextType, opts.description, store.LocalContext{})
-		},
-	}
-	addDescriptionFlag(cmd, &opts.description)
-	return cmd
-}
-
 func createExampleCommand() *cobra.Command {
 	var opts descriptionCreateOpts
+	var scenario string
 	cmd := &cobra.Command{
-		Use:    "example CONTEXT",
-		Short:  "Create a test context returning fixed output",
-		Args:   cobra.ExactArgs(1),
-		Hidden: true,
+		Use:         "example CONTEXT",
+		Annotations: audit.Mutating(),
+		Short:       "Create a test context returning fixed output, or simulating a cloud backend as described by a scenario",
+		Args:        cobra.ExactArgs(1),
+		Hidden:      true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return createDockerContext(cmd.Context(), args[0], store.ExampleContextType, opts.description, store.ExampleContext{})
+			data := store.ExampleContext{}
+			if scenario != "" {
+				path, err := filepath.Abs(scenario)
+				if err != nil {
+					return err
+				}
+				if _, err := os.Stat(path); err != nil {
+					return i18n.Wrap(err, "context.scenario")
+				}
+				data.Scenario = path
+			}
+			return createDockerContext(cmd.Context(), args[0], store.ExampleContextType, opts.description, data)
 		},
 	}
 
 	addDescriptionFlag(cmd, &opts.description)
+	cmd.Flags().StringVar(&scenario, "scenario", "", "YAML file setting the latencies, failures and service states the backend simulates")
 	return cmd
 }
 
@@ -135,7 +138,7 @@ func createDockerContext(ctx context.Context, name string, contextType string, d
 		description,
 		data,
 	)
-	fmt.Printf("Successfully created %s context %q\n", contextType, name)
+	fmt.Println(i18n.T("context.created", contextType, name))
 	return result
 }
 
@@ -147,3 +150,63 @@ func contextExists(ctx context.Context, name string) bool {
 func addDescriptionFlag(cmd *cobra.Command, descriptionOpt *string) {
 	cmd.Flags().StringVar(descriptionOpt, "description", "", "Description of the context")
 }
+
+const maxRetriesFlag = "max-retries"
+
+func addOperationFlags(cmd *cobra.Command, opts *store.OperationSettings) *int {
+	var maxRetries int
+	cmd.Flags().StringVar(&opts.Timeout, "timeout", "", "Timeout of cloud API calls (e.g. 30s, 2m)")
+	cmd.Flags().StringVar(&opts.PollingInterval, "polling-interval", "", "Interval between two status checks of long running operations")
+	cmd.Flags().IntVar(&maxRetries, maxRetriesFlag, 0, "Maximum number of retries of failed cloud API calls")
+	cmd.Flags().StringVar(&opts.RetryBackoff, "retry-backoff", "", "Initial delay between two retries, doubled on each attempt with jitter")
+	return &maxRetries
+}
+
+func addBudgetFlags(cmd *cobra.Command, budget *store.Budget) {
+	cmd.Flags().Float64Var(&budget.MaxCPUs, "max-cpus", 0, "Maximum number of CPUs an application can request")
+	cmd.Flags().Float64Var(&budget.MaxMemoryGB, "max-memory", 0, "Maximum memory in GB an application can request")
+	cmd.Flags().Float64Var(&budget.MaxMonthlyCost, "max-monthly-cost", 0, "Maximum estimated monthly cost of an application")
+}
+
+func addTagPolicyFlags(cmd *cobra.Command, policy *store.TagPolicy) {
+	cmd.Flags().StringToStringVar(&policy.Tags, "tag", nil, "Tag applied to every cloud resource created for a project, as KEY=VALUE")
+	cmd.Flags().StringSliceVar(&policy.RequiredTags, "require-tag", nil, "Tag key projects must be deployed with, using compose up --label")
+}
+
+func addAnnotationFlags(cmd *cobra.Command, annotations *store.DeploymentAnnotations) {
+	cmd.Flags().StringSliceVar(&annotations.Annotations, "annotate", nil, "Annotate monitoring dashboards on compose up: datadog[://SITE], grafana://HOST[/PATH] or cloudwatch (ECS only)")
+}
+
+type notificationFlags struct {
+	slack    []string
+	urls     []string
+	commands []string
+}
+
+func addNotificationFlags(cmd *cobra.Command, flags *notificationFlags) {
+	cmd.Flags().StringArrayVar(&flags.slack, "notify-slack", nil, "Slack incoming webhook notified when compose up succeeds or fails, and compose down completes")
+	cmd.Flags().StringArrayVar(&flags.urls, "notify-url", nil, "URL receiving compose up and down events as JSON POST requests")
+	cmd.Flags().StringArrayVar(&flags.commands, "notify-command", nil, "Local command run on compose up and down events, with the event in COMPOSE_EVENT")
+}
+
+// notifications returns the hooks set by the flags, fired on all events
+func (flags notificationFlags) notifications() (store.Notifications, error) {
+	var n store.Notifications
+	for _, u := range flags.slack {
+		n.NotificationHooks = append(n.NotificationHooks, store.NotificationHook{Slack: u})
+	}
+	for _, u := range flags.urls {
+		n.NotificationHooks = append(n.NotificationHooks, store.NotificationHook{URL: u})
+	}
+	for _, c := range flags.commands {
+		n.NotificationHooks = append(n.NotificationHooks, store.NotificationHook{Command: c})
+	}
+	return n, n.Validate()
+}
+
+func checkOperationFlags(cmd *cobra.Command, opts *store.OperationSettings, maxRetries *int) error {
+	if cmd.Flags().Changed(maxRetriesFlag) {
+		opts.MaxRetries = maxRetries
+	}
+	return opts.Validate()
+}
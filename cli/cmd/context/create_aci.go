@@ -19,13 +19,15 @@ package context
 import (
 	"context"
 
-	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose-cli/aci"
 	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/audit"
 	"github.com/docker/compose-cli/context/store"
 	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/i18n"
+	"github.com/docker/compose-cli/scan"
 )
 
 func init() {
@@ -39,11 +41,39 @@ $ docker context create aci CONTEXT [flags]
 
 func createAciCommand() *cobra.Command {
 	var opts aci.ContextParams
+	var notifications notificationFlags
+	var maxRetries *int
 	cmd := &cobra.Command{
-		Use:   "aci CONTEXT [flags]",
-		Short: "Create a context for Azure Container Instances",
-		Args:  cobra.ExactArgs(1),
+		Use:         "aci CONTEXT [flags]",
+		Annotations: audit.Mutating(),
+		Short:       "Create a context for Azure Container Instances",
+		Args:        cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkOperationFlags(cmd, &opts.Operations, maxRetries); err != nil {
+				return err
+			}
+			if err := opts.Budget.Validate(); err != nil {
+				return err
+			}
+			if err := opts.TagPolicy.Validate(); err != nil {
+				return err
+			}
+			if err := opts.Annotations.Validate(false); err != nil {
+				return err
+			}
+			hooks, err := notifications.notifications()
+			if err != nil {
+				return err
+			}
+			opts.Notifications = hooks
+			if err := opts.PrivateNetwork.Validate(); err != nil {
+				return err
+			}
+			if opts.ScanSeverity != "" {
+				if _, err := scan.ParseSeverity(opts.ScanSeverity); err != nil {
+					return err
+				}
+			}
 			return runCreateAci(cmd.Context(), args[0], opts)
 		},
 	}
@@ -52,18 +82,29 @@ func createAciCommand() *cobra.Command {
 	cmd.Flags().StringVar(&opts.Location, "location", "eastus", "Location")
 	cmd.Flags().StringVar(&opts.SubscriptionID, "subscription-id", "", "Location")
 	cmd.Flags().StringVar(&opts.ResourceGroup, "resource-group", "", "Resource group")
+	cmd.Flags().BoolVar(&opts.ResolveImageDigests, "resolve-image-digests", false, "Pin service images to their digest on compose up by default")
+	cmd.Flags().StringVar(&opts.TracingEndpoint, "tracing-endpoint", "", "OpenTelemetry collector endpoint CLI operations traces are exported to")
+	cmd.Flags().StringVar(&opts.ScanSeverity, "scan-severity", "", "Scan images on compose up, refusing to deploy vulnerabilities of this severity or above (LOW, MEDIUM, HIGH, CRITICAL)")
+	maxRetries = addOperationFlags(cmd, &opts.Operations)
+	addBudgetFlags(cmd, &opts.Budget)
+	addTagPolicyFlags(cmd, &opts.TagPolicy)
+	addAnnotationFlags(cmd, &opts.Annotations)
+	addNotificationFlags(cmd, &notifications)
+	cmd.Flags().StringVar(&opts.PrivateNetwork.SubnetID, "subnet", "", "Resource ID of the virtual network subnet container groups are deployed to, without public IP")
+	cmd.Flags().StringVar(&opts.PrivateNetwork.PrivateDNSZone, "private-dns-zone", "", "Private DNS zone of the resource group where container groups get an A record named after the project")
+	cmd.Flags().StringVar(&opts.PrivateNetwork.ApplicationGateway, "application-gateway", "", "Application Gateway of the resource group whose backend pool named after the project routes ingress to container groups")
 
 	return cmd
 }
 
 func runCreateAci(ctx context.Context, contextName string, opts aci.ContextParams) error {
 	if contextExists(ctx, contextName) {
-		return errors.Wrapf(errdefs.ErrAlreadyExists, "context %s", contextName)
+		return i18n.Wrap(errdefs.ErrAlreadyExists, "context.aci.exists", contextName)
 	}
 	contextData, description, err := getAciContextData(ctx, opts)
 	if err != nil {
 		if aci.IsSubscriptionNotFoundError(err) {
-			return errors.New("could not find the requested subscription from your Azure login. You might need to specify a tenant ID with docker login azure --tenant-id xxx")
+			return i18n.Error("context.aci.subscription")
 		}
 		return err
 	}
@@ -74,7 +115,7 @@ func runCreateAci(ctx context.Context, contextName string, opts aci.ContextParam
 func getAciContextData(ctx context.Context, opts aci.ContextParams) (interface{}, string, error) {
 	cs, err := client.GetCloudService(ctx, store.AciContextType)
 	if err != nil {
-		return nil, "", errors.Wrap(err, "cannot connect to ACI backend")
+		return nil, "", i18n.Wrap(err, "context.aci.connect")
 	}
 	return cs.CreateContextData(ctx, opts)
 }
@@ -18,14 +18,19 @@ package context
 
 import (
 	"context"
+	"os"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/audit"
 	"github.com/docker/compose-cli/context/store"
 	"github.com/docker/compose-cli/ecs"
 	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/i18n"
+	"github.com/docker/compose-cli/prompt"
+	"github.com/docker/compose-cli/scan"
 )
 
 func init() {
@@ -40,11 +45,47 @@ $ docker context create ecs CONTEXT [flags]
 func createEcsCommand() *cobra.Command {
 	var localSimulation bool
 	var opts ecs.ContextParams
+	var notifications notificationFlags
+	var maxRetries *int
+	var secretStdin bool
 	cmd := &cobra.Command{
-		Use:   "ecs CONTEXT [flags]",
-		Short: "Create a context for Amazon ECS",
-		Args:  cobra.ExactArgs(1),
+		Use:         "ecs CONTEXT [flags]",
+		Annotations: audit.Mutating(),
+		Short:       "Create a context for Amazon ECS",
+		Args:        cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkOperationFlags(cmd, &opts.Operations, maxRetries); err != nil {
+				return err
+			}
+			if err := opts.Budget.Validate(); err != nil {
+				return err
+			}
+			if err := opts.TagPolicy.Validate(); err != nil {
+				return err
+			}
+			if err := opts.Annotations.Validate(true); err != nil {
+				return err
+			}
+			hooks, err := notifications.notifications()
+			if err != nil {
+				return err
+			}
+			opts.Notifications = hooks
+			if opts.ScanSeverity != "" {
+				if _, err := scan.ParseSeverity(opts.ScanSeverity); err != nil {
+					return err
+				}
+			}
+			if (opts.AwsID != "" && opts.AwsSecret == "") || secretStdin {
+				secret, err := prompt.ReadSecret(prompt.User{}, os.Stdin, "AWS Secret Access Key", secretStdin)
+				if errors.Is(err, prompt.ErrNotATerminal) {
+					return i18n.Wrap(err, "context.ecs.secret-key-stdin")
+				}
+				if err != nil {
+					return err
+				}
+				opts.AwsSecret = secret
+			}
 			if localSimulation {
 				return runCreateLocalSimulation(cmd.Context(), args[0], opts)
 			}
@@ -58,16 +99,26 @@ func createEcsCommand() *cobra.Command {
 	cmd.Flags().StringVar(&opts.Region, "region", "", "Region")
 	cmd.Flags().StringVar(&opts.AwsID, "key-id", "", "AWS Access Key ID")
 	cmd.Flags().StringVar(&opts.AwsSecret, "secret-key", "", "AWS Secret Access Key")
+	cmd.Flags().BoolVar(&secretStdin, "secret-key-stdin", false, "Take the AWS Secret Access Key from stdin")
+	cmd.Flags().BoolVar(&opts.ResolveImageDigests, "resolve-image-digests", false, "Pin service images to their digest on compose up by default")
+	cmd.Flags().StringVar(&opts.TracingEndpoint, "tracing-endpoint", "", "OpenTelemetry collector endpoint CLI operations traces are exported to")
+	cmd.Flags().BoolVar(&opts.External, "external", false, "Deploy services on the ECS Anywhere instances registered in the project cluster")
+	cmd.Flags().StringVar(&opts.ScanSeverity, "scan-severity", "", "Scan images on compose up, refusing to deploy vulnerabilities of this severity or above (LOW, MEDIUM, HIGH, CRITICAL)")
+	maxRetries = addOperationFlags(cmd, &opts.Operations)
+	addBudgetFlags(cmd, &opts.Budget)
+	addTagPolicyFlags(cmd, &opts.TagPolicy)
+	addAnnotationFlags(cmd, &opts.Annotations)
+	addNotificationFlags(cmd, &notifications)
 	return cmd
 }
 
 func runCreateLocalSimulation(ctx context.Context, contextName string, opts ecs.ContextParams) error {
 	if contextExists(ctx, contextName) {
-		return errors.Wrapf(errdefs.ErrAlreadyExists, "context %q", contextName)
+		return i18n.Wrap(errdefs.ErrAlreadyExists, "context.exists", contextName)
 	}
 	cs, err := client.GetCloudService(ctx, store.EcsLocalSimulationContextType)
 	if err != nil {
-		return errors.Wrap(err, "cannot connect to ECS backend")
+		return i18n.Wrap(err, "context.ecs.connect")
 	}
 	data, description, err := cs.CreateContextData(ctx, opts)
 	if err != nil {
@@ -78,7 +129,7 @@ func runCreateLocalSimulation(ctx context.Context, contextName string, opts ecs.
 
 func runCreateEcs(ctx context.Context, contextName string, opts ecs.ContextParams) error {
 	if contextExists(ctx, contextName) {
-		return errors.Wrapf(errdefs.ErrAlreadyExists, "context %q", contextName)
+		return i18n.Wrap(errdefs.ErrAlreadyExists, "context.exists", contextName)
 	}
 	contextData, description, err := getEcsContextData(ctx, opts)
 	if err != nil {
@@ -91,7 +142,7 @@ func runCreateEcs(ctx context.Context, contextName string, opts ecs.ContextParam
 func getEcsContextData(ctx context.Context, opts ecs.ContextParams) (interface{}, string, error) {
 	cs, err := client.GetCloudService(ctx, store.EcsContextType)
 	if err != nil {
-		return nil, "", errors.Wrap(err, "cannot connect to ECS backend")
+		return nil, "", i18n.Wrap(err, "context.ecs.connect")
 	}
 	return cs.CreateContextData(ctx, opts)
 }
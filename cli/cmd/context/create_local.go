@@ -0,0 +1,119 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package context
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/audit"
+	apicontext "github.com/docker/compose-cli/context"
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/i18n"
+)
+
+type localCreateOpts struct {
+	descriptionCreateOpts
+	store.LocalContext
+}
+
+func createLocalCommand() *cobra.Command {
+	var opts localCreateOpts
+	cmd := &cobra.Command{
+		Use:         "local CONTEXT",
+		Annotations: audit.Mutating(),
+		Short:       "Create a context for accessing local engine",
+		Args:        cobra.ExactArgs(1),
+		Hidden:      true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCreateLocal(cmd.Context(), args[0], opts)
+		},
+	}
+	addDescriptionFlag(cmd, &opts.description)
+	cmd.Flags().StringVar(&opts.Host, "host", "", "Engine to connect to, e.g. tcp://myserver:2376, instead of the one set by the environment")
+	cmd.Flags().StringVar(&opts.CAFile, "tlscacert", "", "Trust certs signed only by this CA")
+	cmd.Flags().StringVar(&opts.CertFile, "tlscert", "", "Path to TLS certificate file")
+	cmd.Flags().StringVar(&opts.KeyFile, "tlskey", "", "Path to TLS key file")
+	cmd.Flags().BoolVar(&opts.SkipTLSVerify, "skip-tls-verify", false, "Skip TLS certificate validation")
+	return cmd
+}
+
+func runCreateLocal(ctx context.Context, contextName string, opts localCreateOpts) error {
+	if contextExists(ctx, contextName) {
+		return i18n.Wrap(errdefs.ErrAlreadyExists, "context.exists", contextName)
+	}
+	data, err := localContextData(opts.LocalContext)
+	if err != nil {
+		return err
+	}
+	s := store.ContextStore(ctx)
+	if err := s.Create(contextName, store.LocalContextType, opts.description, data); err != nil {
+		return err
+	}
+	if data.Host != "" {
+		if err := checkEngine(ctx, contextName); err != nil {
+			_ = s.Remove(contextName)
+			return i18n.Wrap(err, "context.local.connect", data.Host, contextName)
+		}
+	}
+	fmt.Println(i18n.T("context.created", store.LocalContextType, contextName))
+	return nil
+}
+
+// localContextData checks the TLS material is only set with a host, and records absolute paths of its files
+func localContextData(data store.LocalContext) (store.LocalContext, error) {
+	if data.Host == "" {
+		if data.CAFile != "" || data.CertFile != "" || data.KeyFile != "" || data.SkipTLSVerify {
+			return data, i18n.Wrap(errdefs.ErrParsingFailed, "context.local.tls-host")
+		}
+		return data, nil
+	}
+	if !strings.Contains(data.Host, "://") {
+		return data, i18n.Wrap(errdefs.ErrParsingFailed, "context.local.invalid-host", data.Host)
+	}
+	if (data.CertFile == "") != (data.KeyFile == "") {
+		return data, i18n.Wrap(errdefs.ErrParsingFailed, "context.local.tls-pair")
+	}
+	for _, file := range []*string{&data.CAFile, &data.CertFile, &data.KeyFile} {
+		if *file == "" {
+			continue
+		}
+		abs, err := filepath.Abs(*file)
+		if err != nil {
+			return data, err
+		}
+		*file = abs
+	}
+	return data, nil
+}
+
+// checkEngine connects to the engine of a local context
+func checkEngine(ctx context.Context, contextName string) error {
+	ctx = apicontext.WithCurrentContext(ctx, contextName)
+	c, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = c.ContainerService().List(ctx, false)
+	return err
+}
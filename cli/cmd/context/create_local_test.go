@@ -0,0 +1,96 @@
+// +build local
+
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package context
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	"github.com/docker/compose-cli/context/store"
+	_ "github.com/docker/compose-cli/local"
+	"github.com/docker/compose-cli/tests/framework"
+)
+
+// fakeEngine answers the engine API calls of the connectivity check
+func fakeEngine(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("API-Version", "1.40")
+	switch {
+	case r.URL.Path == "/_ping":
+		fmt.Fprint(w, "OK")
+	case strings.HasSuffix(r.URL.Path, "/containers/json"):
+		fmt.Fprint(w, "[]")
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestCreateLocalTLSHost(t *testing.T) {
+	engine := httptest.NewTLSServer(http.HandlerFunc(fakeEngine))
+	defer engine.Close()
+	dir := fs.NewDir(t, "tls", fs.WithFile("ca.pem", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: engine.Certificate().Raw}))))
+	defer dir.Remove()
+
+	c := framework.NewTestCLI(t)
+	host := strings.Replace(engine.URL, "https://", "tcp://", 1)
+	opts := localCreateOpts{LocalContext: store.LocalContext{Host: host, CAFile: dir.Join("ca.pem")}}
+	assert.NilError(t, runCreateLocal(c.Context(), "remote", opts))
+
+	s := store.ContextStore(c.Context())
+	cc, err := s.Get("remote")
+	assert.NilError(t, err)
+	assert.Equal(t, cc.Type(), store.LocalContextType)
+	var stored store.LocalContext
+	assert.NilError(t, s.GetEndpoint("remote", &stored))
+	assert.DeepEqual(t, stored, store.LocalContext{Host: host, CAFile: dir.Join("ca.pem")})
+}
+
+func TestCreateLocalUnreachableHost(t *testing.T) {
+	engine := httptest.NewServer(http.HandlerFunc(fakeEngine))
+	host := strings.Replace(engine.URL, "http://", "tcp://", 1)
+	engine.Close()
+
+	c := framework.NewTestCLI(t)
+	err := runCreateLocal(c.Context(), "remote", localCreateOpts{LocalContext: store.LocalContext{Host: host}})
+	assert.ErrorContains(t, err, fmt.Sprintf("cannot connect to %s, context \"remote\" not created", host))
+	_, err = store.ContextStore(c.Context()).Get("remote")
+	assert.Assert(t, err != nil)
+}
+
+func TestLocalContextData(t *testing.T) {
+	data, err := localContextData(store.LocalContext{Host: "tcp://myserver:2376", CAFile: "ca.pem", CertFile: "cert.pem", KeyFile: "key.pem"})
+	assert.NilError(t, err)
+	assert.Assert(t, filepath.IsAbs(data.CAFile))
+	assert.Equal(t, filepath.Base(data.CertFile), "cert.pem")
+	assert.Assert(t, filepath.IsAbs(data.KeyFile))
+
+	_, err = localContextData(store.LocalContext{CAFile: "ca.pem"})
+	assert.ErrorContains(t, err, "TLS flags require --host")
+	_, err = localContextData(store.LocalContext{Host: "myserver:2376"})
+	assert.ErrorContains(t, err, `invalid host "myserver:2376"`)
+	_, err = localContextData(store.LocalContext{Host: "tcp://myserver:2376", CertFile: "cert.pem"})
+	assert.ErrorContains(t, err, "--tlscert and --tlskey must be set together")
+}
@@ -17,22 +17,77 @@
 package context
 
 import (
-	"github.com/docker/compose-cli/cli/mobycli"
+	"fmt"
 
 	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/cli/mobycli"
+	apicontext "github.com/docker/compose-cli/context"
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/formatter"
 )
 
+type inspectOpts struct {
+	format string
+}
+
 func inspectCommand() *cobra.Command {
+	var opts inspectOpts
 	cmd := &cobra.Command{
 		Use:   "inspect",
 		Short: "Display detailed information on one or more contexts",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			mobycli.Exec(cmd.Root())
-			return nil
+			return runInspect(cmd, args, opts)
 		},
 	}
 	// flags matching delegated command in moby cli
 	flags := cmd.Flags()
-	flags.StringP("format", "f", "", "Format the output using the given Go template")
+	flags.StringVarP(&opts.format, "format", "f", "", "Format the output using the given Go template")
 	return cmd
 }
+
+func runInspect(cmd *cobra.Command, args []string, opts inspectOpts) error {
+	if opts.format != "" {
+		mobycli.Exec(cmd.Context(), cmd.Root())
+		return nil
+	}
+	ctx := cmd.Context()
+	if len(args) == 0 {
+		args = []string{apicontext.CurrentContext(ctx)}
+	}
+	s := store.ContextStore(ctx)
+	var contexts []*store.DockerContext
+	for _, name := range args {
+		c, err := s.Get(name)
+		if err != nil || !withEffectiveOperations(c) {
+			// let the classic CLI inspect docker contexts and report errors
+			mobycli.Exec(cmd.Context(), cmd.Root())
+			return nil
+		}
+		contexts = append(contexts, c)
+	}
+	j, err := formatter.ToStandardJSON(contexts)
+	if err != nil {
+		return err
+	}
+	fmt.Println(j)
+	return nil
+}
+
+// withEffectiveOperations replaces operation settings of cloud endpoints by their effective values,
+// returns false if the context isn't a cloud context
+func withEffectiveOperations(c *store.DockerContext) bool {
+	switch c.Type() {
+	case store.AciContextType:
+		if endpoint, ok := c.Endpoints[store.AciContextType].(*store.AciContext); ok {
+			endpoint.OperationSettings = endpoint.Operations().Settings()
+			return true
+		}
+	case store.EcsContextType:
+		if endpoint, ok := c.Endpoints[store.EcsContextType].(*store.EcsContext); ok {
+			endpoint.OperationSettings = endpoint.Operations().Settings()
+			return true
+		}
+	}
+	return false
+}
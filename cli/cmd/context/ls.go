@@ -17,7 +17,6 @@
 package context
 
 import (
-	"errors"
 	"fmt"
 	"os"
 	"sort"
@@ -30,6 +29,7 @@ import (
 	apicontext "github.com/docker/compose-cli/context"
 	"github.com/docker/compose-cli/context/store"
 	"github.com/docker/compose-cli/formatter"
+	"github.com/docker/compose-cli/i18n"
 )
 
 type lsOpts struct {
@@ -40,7 +40,7 @@ type lsOpts struct {
 
 func (o lsOpts) validate() error {
 	if o.quiet && o.json {
-		return errors.New(`cannot combine "quiet" and "json" options`)
+		return i18n.Error("options.quiet-json")
 	}
 	return nil
 }
@@ -69,7 +69,7 @@ func runList(cmd *cobra.Command, opts lsOpts) error {
 		return err
 	}
 	if opts.format != "" {
-		mobycli.Exec(cmd.Root())
+		mobycli.Exec(cmd.Context(), cmd.Root())
 		return nil
 	}
 
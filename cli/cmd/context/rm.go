@@ -18,15 +18,16 @@ package context
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/spf13/cobra"
 
+	"github.com/docker/compose-cli/audit"
 	"github.com/docker/compose-cli/cli/formatter"
 	apicontext "github.com/docker/compose-cli/context"
 	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/i18n"
 )
 
 type removeOpts struct {
@@ -36,10 +37,11 @@ type removeOpts struct {
 func removeCommand() *cobra.Command {
 	var opts removeOpts
 	cmd := &cobra.Command{
-		Use:     "rm CONTEXT [CONTEXT...]",
-		Short:   "Remove one or more contexts",
-		Aliases: []string{"remove"},
-		Args:    cobra.MinimumNArgs(1),
+		Use:         "rm CONTEXT [CONTEXT...]",
+		Annotations: audit.Mutating(),
+		Short:       "Remove one or more contexts",
+		Aliases:     []string{"remove"},
+		Args:        cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runRemove(cmd.Context(), args, opts.force)
 		},
@@ -58,12 +60,12 @@ func runRemove(ctx context.Context, args []string, force bool) error {
 		if currentContext == contextName {
 			if force {
 				if err := runUse(ctx, "default"); err != nil {
-					errs = multierror.Append(errs, errors.New("cannot delete current context"))
+					errs = multierror.Append(errs, i18n.Error("context.rm-current"))
 				} else {
 					errs = removeContext(s, contextName, errs)
 				}
 			} else {
-				errs = multierror.Append(errs, errors.New("cannot delete current context"))
+				errs = multierror.Append(errs, i18n.Error("context.rm-current"))
 			}
 		} else {
 			errs = removeContext(s, contextName, errs)
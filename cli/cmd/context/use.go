@@ -19,11 +19,14 @@ package context
 import (
 	"context"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
+	"github.com/docker/compose-cli/cli/mobycli"
 	"github.com/docker/compose-cli/config"
 	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/i18n"
 )
 
 func useCommand() *cobra.Command {
@@ -39,15 +42,21 @@ func useCommand() *cobra.Command {
 
 func runUse(ctx context.Context, name string) error {
 	s := store.ContextStore(ctx)
+	contextType := store.DefaultContextType
 	// Match behavior of existing CLI
 	if name != store.DefaultContextName {
-		if _, err := s.Get(name); err != nil {
+		dc, err := s.Get(name)
+		if err != nil {
 			return err
 		}
+		contextType = dc.Type()
 	}
 	if err := config.WriteCurrentContext(config.Dir(ctx), name); err != nil {
 		return err
 	}
 	fmt.Println(name)
+	if contextType != store.DefaultContextType {
+		fmt.Fprintln(os.Stderr, i18n.T("context.routed", contextType, mobycli.RequireBackendFlag))
+	}
 	return nil
 }
@@ -17,9 +17,9 @@
 package cmd
 
 import (
-	"errors"
-
 	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/i18n"
 )
 
 // EcsCommand is a placeholder to drive early users to the integrated form of ecs support instead of its early plugin form
@@ -27,7 +27,7 @@ func EcsCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use: "ecs",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return errors.New("The ECS integration is now part of the CLI. Use `docker compose` with an ECS context.") // nolint
+			return i18n.Error("ecs.moved")
 		},
 	}
 
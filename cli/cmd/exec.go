@@ -23,11 +23,11 @@ import (
 	"strings"
 
 	"github.com/containerd/console"
-	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose-cli/api/client"
 	"github.com/docker/compose-cli/api/containers"
+	"github.com/docker/compose-cli/i18n"
 )
 
 type execOpts struct {
@@ -56,7 +56,7 @@ func ExecCommand() *cobra.Command {
 func runExec(ctx context.Context, opts execOpts, name string, command string) error {
 	c, err := client.New(ctx)
 	if err != nil {
-		return errors.Wrap(err, "cannot connect to backend")
+		return i18n.Wrap(err, "backend.connect")
 	}
 
 	request := containers.ExecRequest{
@@ -75,7 +75,7 @@ func runExec(ctx context.Context, opts execOpts, name string, command string) er
 		}
 		defer func() {
 			if err := con.Reset(); err != nil {
-				fmt.Println("Unable to close the console")
+				fmt.Println(i18n.T("console.close-failed"))
 			}
 		}()
 
@@ -0,0 +1,171 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/hashicorp/go-multierror"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/images"
+	"github.com/docker/compose-cli/audit"
+	"github.com/docker/compose-cli/cli/formatter"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/i18n"
+	"github.com/docker/compose-cli/progress"
+)
+
+// Command manages the images of the local backend
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "image",
+		Short: "Manage images",
+	}
+
+	cmd.AddCommand(
+		listImages(),
+		rmImages(),
+		pullImage(),
+	)
+	return cmd
+}
+
+type listOptions struct {
+	filters []string
+	quiet   bool
+}
+
+func listImages() *cobra.Command {
+	var opts listOptions
+	cmd := &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "List images",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options, err := opts.listOptions()
+			if err != nil {
+				return err
+			}
+			c, err := client.New(cmd.Context())
+			if err != nil {
+				return err
+			}
+			list, err := c.ImageService().List(cmd.Context(), options)
+			if err != nil {
+				return err
+			}
+			if opts.quiet {
+				for _, image := range list {
+					fmt.Println(image.ID)
+				}
+				return nil
+			}
+			printList(os.Stdout, list, time.Now())
+			return nil
+		},
+	}
+	cmd.Flags().StringArrayVarP(&opts.filters, "filter", "f", nil, "Filter images, e.g. dangling=true")
+	cmd.Flags().BoolVarP(&opts.quiet, "quiet", "q", false, "Only display image IDs")
+	return cmd
+}
+
+func (opts listOptions) listOptions() (images.ListOptions, error) {
+	var options images.ListOptions
+	for _, f := range opts.filters {
+		parts := strings.SplitN(f, "=", 2)
+		if len(parts) != 2 || parts[0] != "dangling" || (parts[1] != "true" && parts[1] != "false") {
+			return options, i18n.Wrap(errdefs.ErrParsingFailed, "image.filter", f)
+		}
+		dangling := parts[1] == "true"
+		options.Dangling = &dangling
+	}
+	return options, nil
+}
+
+func rmImages() *cobra.Command {
+	var opts images.RemoveOptions
+	cmd := &cobra.Command{
+		Use:         "rm [OPTIONS] IMAGE [IMAGE...]",
+		Annotations: audit.Mutating(),
+		Aliases:     []string{"remove"},
+		Short:       "Remove one or more images",
+		Args:        cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := client.New(cmd.Context())
+			if err != nil {
+				return err
+			}
+			var errs *multierror.Error
+			for _, image := range args {
+				removed, err := c.ImageService().Remove(cmd.Context(), image, opts)
+				if err != nil {
+					errs = multierror.Append(errs, err)
+					continue
+				}
+				for _, r := range removed {
+					fmt.Println(r)
+				}
+			}
+			formatter.SetMultiErrorFormat(errs)
+			return errs.ErrorOrNil()
+		},
+	}
+	cmd.Flags().BoolVarP(&opts.Force, "force", "f", false, "Force removal of images used by containers")
+	return cmd
+}
+
+func pullImage() *cobra.Command {
+	var opts images.PullOptions
+	cmd := &cobra.Command{
+		Use:         "pull [OPTIONS] IMAGE",
+		Annotations: audit.Mutating(),
+		Short:       "Pull an image from a registry",
+		Args:        cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := client.New(cmd.Context())
+			if err != nil {
+				return err
+			}
+			_, err = progress.Run(cmd.Context(), func(ctx context.Context) (string, error) {
+				return "", c.ImageService().Pull(ctx, args[0], opts)
+			})
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&opts.Platform, "platform", "", "Pull the image for this platform, e.g. linux/arm64")
+	return cmd
+}
+
+func printList(out io.Writer, list []images.Image, now time.Time) {
+	w := tabwriter.NewWriter(out, 20, 1, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "REPOSITORY\tTAG\tIMAGE ID\tCREATED\tSIZE")
+	for _, image := range list {
+		created := units.HumanDuration(now.Sub(image.Created)) + " ago"
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", image.Repository, image.Tag, image.ID, created, units.HumanSizeWithPrecision(float64(image.Size), 3))
+	}
+	_ = w.Flush()
+}
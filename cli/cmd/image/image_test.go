@@ -0,0 +1,49 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+
+	"github.com/docker/compose-cli/api/images"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestPrintList(t *testing.T) {
+	now := time.Date(2020, 11, 2, 3, 0, 0, 0, time.UTC)
+	list := []images.Image{
+		{ID: "0d120b6ccaa8", Repository: "nginx", Tag: "latest", Created: now.Add(-72 * time.Hour), Size: 133000000},
+		{ID: "5f1f270d5e4a", Repository: "<none>", Tag: "<none>", Created: now.Add(-2 * time.Hour), Size: 5600000, Dangling: true},
+	}
+	out := &bytes.Buffer{}
+	printList(out, list, now)
+	golden.Assert(t, out.String(), "images-out.golden")
+}
+
+func TestListOptions(t *testing.T) {
+	options, err := listOptions{filters: []string{"dangling=true"}}.listOptions()
+	assert.NilError(t, err)
+	assert.Assert(t, *options.Dangling)
+
+	_, err = listOptions{filters: []string{"label=x"}}.listOptions()
+	assert.Assert(t, errdefs.IsErrParsingFailed(err))
+}
@@ -0,0 +1,183 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	apicontext "github.com/docker/compose-cli/context"
+	"github.com/docker/compose-cli/context/store"
+)
+
+// backendSDKs are the modules backends use to reach their platform, by context type
+var backendSDKs = map[string][]string{
+	store.AciContextType:                {"github.com/Azure/azure-sdk-for-go"},
+	store.EcsContextType:                {"github.com/aws/aws-sdk-go"},
+	store.EcsLocalSimulationContextType: {"github.com/aws/aws-sdk-go", "github.com/docker/docker"},
+	store.LocalContextType:              {"github.com/docker/docker"},
+}
+
+// environment describes the CLI and the backend of the current context, as needed in support tickets
+type environment struct {
+	CloudIntegration string            `json:"cloudIntegration"`
+	GoVersion        string            `json:"goVersion"`
+	OS               string            `json:"os"`
+	Arch             string            `json:"arch"`
+	Context          string            `json:"context"`
+	ContextType      string            `json:"contextType"`
+	Account          []accountField    `json:"-"`
+	AccountFields    map[string]string `json:"account,omitempty"`
+	SDKs             map[string]string `json:"sdks,omitempty"`
+	Capabilities     []string          `json:"capabilities,omitempty"`
+}
+
+type accountField struct {
+	key   string
+	label string
+	value string
+}
+
+// InfoCommand displays the backend of the current context
+func InfoCommand(version string) *cobra.Command {
+	var format string
+	cmd := &cobra.Command{
+		Use:   "info",
+		Short: "Display information about the current context backend",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			env := currentEnvironment(cmd.Context(), version)
+			if format == "json" {
+				return printEnvironmentJSON(os.Stdout, env)
+			}
+			printEnvironment(os.Stdout, env)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&format, "format", "f", "", `Format the output: "json", or a table by default`)
+	return cmd
+}
+
+func currentEnvironment(ctx context.Context, version string) environment {
+	env := environment{
+		CloudIntegration: strings.TrimPrefix(version, "v"),
+		GoVersion:        runtime.Version(),
+		OS:               runtime.GOOS,
+		Arch:             runtime.GOARCH,
+		Context:          apicontext.CurrentContext(ctx),
+		ContextType:      store.DefaultContextType,
+	}
+	s := store.ContextStore(ctx)
+	if cc, err := s.Get(env.Context); err == nil {
+		env.ContextType = cc.Type()
+	}
+	switch env.ContextType {
+	case store.AciContextType:
+		var aciContext store.AciContext
+		if err := s.GetEndpoint(env.Context, &aciContext); err == nil {
+			env.Account = []accountField{
+				{key: "subscriptionID", label: "Subscription", value: aciContext.SubscriptionID},
+				{key: "resourceGroup", label: "Resource group", value: aciContext.ResourceGroup},
+				{key: "location", label: "Location", value: aciContext.Location},
+			}
+		}
+	case store.EcsContextType:
+		var ecsContext store.EcsContext
+		if err := s.GetEndpoint(env.Context, &ecsContext); err == nil {
+			env.Account = []accountField{
+				{key: "profile", label: "Profile", value: ecsContext.Profile},
+				{key: "region", label: "Region", value: ecsContext.Region},
+			}
+		}
+	}
+	env.SDKs = sdkVersions(backendSDKs[env.ContextType])
+	if env.ContextType != store.DefaultContextType {
+		// backends which can't be initialized, e.g. without login, don't report capabilities
+		if c, err := client.New(ctx); err == nil {
+			env.Capabilities = c.Capabilities()
+		}
+	}
+	return env
+}
+
+// sdkVersions returns the version of the modules compiled in the binary
+func sdkVersions(modules []string) map[string]string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok || len(modules) == 0 {
+		return nil
+	}
+	versions := map[string]string{}
+	for _, dep := range info.Deps {
+		for _, module := range modules {
+			if dep.Path == module {
+				versions[module] = dep.Version
+			}
+		}
+	}
+	return versions
+}
+
+func printEnvironmentJSON(out io.Writer, env environment) error {
+	if len(env.Account) > 0 {
+		env.AccountFields = map[string]string{}
+		for _, field := range env.Account {
+			env.AccountFields[field.key] = field.value
+		}
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(env)
+}
+
+func printEnvironment(out io.Writer, env environment) {
+	w := tabwriter.NewWriter(out, 0, 0, 1, ' ', 0)
+	fmt.Fprintln(w, "Client:")
+	fmt.Fprintf(w, " Cloud integration:\t%s\n", env.CloudIntegration)
+	fmt.Fprintf(w, " Go version:\t%s\n", env.GoVersion)
+	fmt.Fprintf(w, " OS/Arch:\t%s/%s\n", env.OS, env.Arch)
+	fmt.Fprintln(w)
+	printContext(w, env)
+	_ = w.Flush()
+}
+
+// printContext writes the context section of the environment, also appended to `docker version`
+func printContext(w io.Writer, env environment) {
+	fmt.Fprintf(w, "Context: %s\n", env.Context)
+	fmt.Fprintf(w, " Type:\t%s\n", env.ContextType)
+	for _, field := range env.Account {
+		fmt.Fprintf(w, " %s:\t%s\n", field.label, field.value)
+	}
+	for _, module := range backendSDKs[env.ContextType] {
+		if version, ok := env.SDKs[module]; ok {
+			fmt.Fprintf(w, " %s:\t%s\n", path.Base(module), version)
+		}
+	}
+	if len(env.Capabilities) > 0 {
+		fmt.Fprintf(w, " Capabilities:\t%s\n", strings.Join(env.Capabilities, ", "))
+	}
+}
@@ -0,0 +1,52 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/v3/golden"
+)
+
+var testEnvironment = environment{
+	CloudIntegration: "1.0.0",
+	GoVersion:        "go1.15.2",
+	OS:               "linux",
+	Arch:             "amd64",
+	Context:          "aci",
+	ContextType:      "aci",
+	Account: []accountField{
+		{key: "subscriptionID", label: "Subscription", value: "1234"},
+		{key: "resourceGroup", label: "Resource group", value: "myResourceGroup"},
+		{key: "location", label: "Location", value: "eastus"},
+	},
+	SDKs:         map[string]string{"github.com/Azure/azure-sdk-for-go": "v48.2.0+incompatible"},
+	Capabilities: []string{"compose", "containers", "volumes"},
+}
+
+func TestPrintEnvironment(t *testing.T) {
+	out := &bytes.Buffer{}
+	printEnvironment(out, testEnvironment)
+	golden.Assert(t, out.String(), "info-out.golden")
+}
+
+func TestPrintEnvironmentJSON(t *testing.T) {
+	out := &bytes.Buffer{}
+	_ = printEnvironmentJSON(out, testEnvironment)
+	golden.Assert(t, out.String(), "info-out-json.golden")
+}
@@ -20,11 +20,11 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose-cli/api/client"
 	"github.com/docker/compose-cli/formatter"
+	"github.com/docker/compose-cli/i18n"
 )
 
 // InspectCommand inspects into containers
@@ -44,7 +44,7 @@ func InspectCommand() *cobra.Command {
 func runInspect(ctx context.Context, id string) error {
 	c, err := client.New(ctx)
 	if err != nil {
-		return errors.Wrap(err, "cannot connect to backend")
+		return i18n.Wrap(err, "backend.connect")
 	}
 
 	container, err := c.ContainerService().Inspect(ctx, id)
@@ -21,12 +21,13 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/go-multierror"
-	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/audit"
 	"github.com/docker/compose-cli/cli/formatter"
 	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/i18n"
 )
 
 type killOpts struct {
@@ -37,9 +38,10 @@ type killOpts struct {
 func KillCommand() *cobra.Command {
 	var opts killOpts
 	cmd := &cobra.Command{
-		Use:   "kill",
-		Short: "Kill one or more running containers",
-		Args:  cobra.MinimumNArgs(1),
+		Use:         "kill",
+		Annotations: audit.Mutating(),
+		Short:       "Kill one or more running containers",
+		Args:        cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runKill(cmd.Context(), args, opts)
 		},
@@ -53,7 +55,7 @@ func KillCommand() *cobra.Command {
 func runKill(ctx context.Context, args []string, opts killOpts) error {
 	c, err := client.New(ctx)
 	if err != nil {
-		return errors.Wrap(err, "cannot connect to backend")
+		return i18n.Wrap(err, "backend.connect")
 	}
 
 	var errs *multierror.Error
@@ -61,7 +63,7 @@ func runKill(ctx context.Context, args []string, opts killOpts) error {
 		err := c.ContainerService().Kill(ctx, id, opts.signal)
 		if err != nil {
 			if errdefs.IsNotFoundError(err) {
-				errs = multierror.Append(errs, fmt.Errorf("container %s not found", id))
+				errs = multierror.Append(errs, i18n.Error("container.not-found", id))
 			} else {
 				errs = multierror.Append(errs, err)
 			}
@@ -17,19 +17,35 @@
 package login
 
 import (
+	"os"
+
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose-cli/aci"
+	"github.com/docker/compose-cli/i18n"
+	"github.com/docker/compose-cli/prompt"
 )
 
 // AzureLoginCommand returns the azure login command
 func AzureLoginCommand() *cobra.Command {
 	opts := aci.LoginParams{}
+	var secretStdin bool
 	cmd := &cobra.Command{
 		Use:   "azure",
 		Short: "Log in to azure",
 		Args:  cobra.MaximumNArgs(0),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if (opts.ClientID != "" && opts.ClientSecret == "") || secretStdin {
+				secret, err := prompt.ReadSecret(prompt.User{}, os.Stdin, "Client secret", secretStdin)
+				if errors.Is(err, prompt.ErrNotATerminal) {
+					return i18n.Wrap(err, "login.client-secret-stdin")
+				}
+				if err != nil {
+					return err
+				}
+				opts.ClientSecret = secret
+			}
 			if err := opts.Validate(); err != nil {
 				return err
 			}
@@ -40,6 +56,7 @@ func AzureLoginCommand() *cobra.Command {
 	flags.StringVar(&opts.TenantID, "tenant-id", "", "Specify tenant ID to use")
 	flags.StringVar(&opts.ClientID, "client-id", "", "Client ID for Service principal login")
 	flags.StringVar(&opts.ClientSecret, "client-secret", "", "Client secret for Service principal login")
+	flags.BoolVar(&secretStdin, "client-secret-stdin", false, "Take the client secret from stdin")
 
 	return cmd
 }
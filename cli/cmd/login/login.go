@@ -29,6 +29,7 @@ import (
 	"github.com/docker/compose-cli/api/client"
 	"github.com/docker/compose-cli/cli/mobycli"
 	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/i18n"
 )
 
 // Command returns the login command
@@ -54,9 +55,9 @@ func Command() *cobra.Command {
 func runLogin(cmd *cobra.Command, args []string) error {
 	if len(args) == 1 && !strings.Contains(args[0], ".") {
 		backend := args[0]
-		return errors.New("unknown backend type for cloud login: " + backend)
+		return i18n.Error("login.unknown-backend", backend)
 	}
-	mobycli.Exec(cmd.Root())
+	mobycli.Exec(cmd.Context(), cmd.Root())
 	return nil
 }
 
@@ -64,15 +65,15 @@ func cloudLogin(cmd *cobra.Command, backendType string, params interface{}) erro
 	ctx := cmd.Context()
 	cs, err := client.GetCloudService(ctx, backendType)
 	if err != nil {
-		return errors.Wrap(errdefs.ErrLoginFailed, "cannot connect to backend")
+		return i18n.Wrap(errdefs.ErrLoginFailed, "backend.connect")
 	}
 	err = cs.Login(ctx, params)
 	if errors.Is(err, context.Canceled) {
-		return errors.New("login canceled")
+		return i18n.Error("login.canceled")
 	}
 	if err != nil {
 		return err
 	}
-	fmt.Println("login succeeded")
+	fmt.Println(i18n.T("login.succeeded"))
 	return nil
 }
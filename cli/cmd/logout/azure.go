@@ -25,6 +25,7 @@ import (
 
 	"github.com/docker/compose-cli/api/client"
 	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/i18n"
 )
 
 // AzureLogoutCommand returns the azure logout command
@@ -44,15 +45,15 @@ func cloudLogout(cmd *cobra.Command, backendType string) error {
 	ctx := cmd.Context()
 	cs, err := client.GetCloudService(ctx, backendType)
 	if err != nil {
-		return errors.Wrap(errdefs.ErrLoginFailed, "cannot connect to backend")
+		return i18n.Wrap(errdefs.ErrLoginFailed, "backend.connect")
 	}
 	err = cs.Logout(ctx)
 	if errors.Is(err, context.Canceled) {
-		return errors.New("logout canceled")
+		return i18n.Error("logout.canceled")
 	}
 	if err != nil {
 		return err
 	}
-	fmt.Println("Removing login credentials for Azure")
+	fmt.Println(i18n.T("logout.azure"))
 	return nil
 }
@@ -37,6 +37,6 @@ func Command() *cobra.Command {
 }
 
 func runLogout(cmd *cobra.Command, args []string) error {
-	mobycli.Exec(cmd.Root())
+	mobycli.Exec(cmd.Context(), cmd.Root())
 	return nil
 }
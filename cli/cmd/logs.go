@@ -22,11 +22,11 @@ import (
 	"os"
 
 	"github.com/containerd/console"
-	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose-cli/api/client"
 	"github.com/docker/compose-cli/api/containers"
+	"github.com/docker/compose-cli/i18n"
 )
 
 type logsOpts struct {
@@ -55,7 +55,7 @@ func LogsCommand() *cobra.Command {
 func runLogs(ctx context.Context, containerName string, opts logsOpts) error {
 	c, err := client.New(ctx)
 	if err != nil {
-		return errors.Wrap(err, "cannot connect to backend")
+		return i18n.Wrap(err, "backend.connect")
 	}
 
 	req := containers.LogsRequest{
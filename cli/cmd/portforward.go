@@ -0,0 +1,56 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/cli/options/portforward"
+	"github.com/docker/compose-cli/i18n"
+)
+
+// PortForwardCommand forwards a local port to a container port
+func PortForwardCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "port-forward CONTAINER LOCAL:REMOTE",
+		Short: "Forward a local port to a container port",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPortForward(cmd.Context(), args[0], args[1])
+		},
+	}
+
+	return cmd
+}
+
+func runPortForward(ctx context.Context, containerID string, spec string) error {
+	localPort, remotePort, err := portforward.Parse(spec)
+	if err != nil {
+		return err
+	}
+	c, err := client.New(ctx)
+	if err != nil {
+		return i18n.Wrap(err, "backend.connect")
+	}
+
+	fmt.Println(i18n.T("portforward.container", localPort, remotePort, containerID))
+	return c.ContainerService().PortForward(ctx, containerID, localPort, remotePort)
+}
@@ -23,12 +23,12 @@ import (
 	"strings"
 	"text/tabwriter"
 
-	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose-cli/api/client"
 	"github.com/docker/compose-cli/api/containers"
 	formatter2 "github.com/docker/compose-cli/formatter"
+	"github.com/docker/compose-cli/i18n"
 	"github.com/docker/compose-cli/utils/formatter"
 )
 
@@ -40,7 +40,7 @@ type psOpts struct {
 
 func (o psOpts) validate() error {
 	if o.quiet && o.json {
-		return errors.New(`cannot combine "quiet" and "json" options`)
+		return i18n.Error("options.quiet-json")
 	}
 	return nil
 }
@@ -71,12 +71,12 @@ func runPs(ctx context.Context, opts psOpts) error {
 
 	c, err := client.New(ctx)
 	if err != nil {
-		return errors.Wrap(err, "cannot connect to backend")
+		return i18n.Wrap(err, "backend.connect")
 	}
 
 	containers, err := c.ContainerService().List(ctx, opts.all)
 	if err != nil {
-		return errors.Wrap(err, "fetch containers")
+		return i18n.Wrap(err, "ps.fetch")
 	}
 
 	if opts.quiet {
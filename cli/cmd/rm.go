@@ -21,13 +21,14 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/go-multierror"
-	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose-cli/api/client"
 	"github.com/docker/compose-cli/api/containers"
+	"github.com/docker/compose-cli/audit"
 	"github.com/docker/compose-cli/cli/formatter"
 	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/i18n"
 )
 
 type rmOpts struct {
@@ -38,9 +39,10 @@ type rmOpts struct {
 func RmCommand() *cobra.Command {
 	var opts rmOpts
 	cmd := &cobra.Command{
-		Use:   "rm",
-		Short: "Remove containers",
-		Args:  cobra.MinimumNArgs(1),
+		Use:         "rm",
+		Annotations: audit.Mutating(),
+		Short:       "Remove containers",
+		Args:        cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runRm(cmd.Context(), args, opts)
 		},
@@ -54,7 +56,7 @@ func RmCommand() *cobra.Command {
 func runRm(ctx context.Context, args []string, opts rmOpts) error {
 	c, err := client.New(ctx)
 	if err != nil {
-		return errors.Wrap(err, "cannot connect to backend")
+		return i18n.Wrap(err, "backend.connect")
 	}
 
 	var errs *multierror.Error
@@ -64,9 +66,9 @@ func runRm(ctx context.Context, args []string, opts rmOpts) error {
 		})
 		if err != nil {
 			if errdefs.IsForbiddenError(err) {
-				errs = multierror.Append(errs, fmt.Errorf("you cannot remove a running container %s. Stop the container before attempting removal or force remove", id))
+				errs = multierror.Append(errs, i18n.Error("rm.running", id))
 			} else if errdefs.IsNotFoundError(err) {
-				errs = multierror.Append(errs, fmt.Errorf("container %s not found", id))
+				errs = multierror.Append(errs, i18n.Error("container.not-found", id))
 			} else {
 				errs = multierror.Append(errs, err)
 			}
@@ -27,8 +27,11 @@ import (
 
 	"github.com/docker/compose-cli/api/client"
 	"github.com/docker/compose-cli/api/containers"
+	"github.com/docker/compose-cli/audit"
 	"github.com/docker/compose-cli/cli/options/run"
 	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/i18n"
 	"github.com/docker/compose-cli/progress"
 )
 
@@ -36,32 +39,63 @@ import (
 func Command(contextType string) *cobra.Command {
 	var opts run.Opts
 	cmd := &cobra.Command{
-		Use:   "run",
-		Short: "Run a container",
-		Args:  cobra.ExactArgs(1),
+		Use:         "run",
+		Annotations: audit.Mutating(),
+		Short:       "Run a container",
+		Args:        cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runRun(cmd.Context(), args[0], opts)
+			command := args[1:]
+			if len(command) > 0 && !opts.Interactive {
+				return i18n.Error("run.command-interactive")
+			}
+			// sessions are opened with an exec, which only runs a binary on ACI
+			if len(command) > 1 {
+				return i18n.Error("run.command-arguments")
+			}
+			return runRun(cmd.Context(), args[0], command, opts)
 		},
 	}
 
+	// flags after the image belong to the command
+	cmd.Flags().SetInterspersed(false)
 	cmd.Flags().StringArrayVarP(&opts.Publish, "publish", "p", []string{}, "Publish a container's port(s). [HOST_PORT:]CONTAINER_PORT")
 	cmd.Flags().StringVar(&opts.Name, "name", "", "Assign a name to the container")
 	cmd.Flags().StringArrayVarP(&opts.Labels, "label", "l", []string{}, "Set meta data on a container")
 	cmd.Flags().StringArrayVarP(&opts.Volumes, "volume", "v", []string{}, "Volume. Ex: storageaccount/my_share[:/absolute/path/to/target][:ro]")
 	cmd.Flags().BoolVarP(&opts.Detach, "detach", "d", false, "Run container in background and print container ID")
+	cmd.Flags().BoolVar(&opts.SigProxy, "sig-proxy", true, "Stop the container when the attached command is interrupted")
 	cmd.Flags().Float64Var(&opts.Cpus, "cpus", 1., "Number of CPUs")
 	cmd.Flags().VarP(&opts.Memory, "memory", "m", "Memory limit")
 	cmd.Flags().StringArrayVarP(&opts.Environment, "env", "e", []string{}, "Set environment variables")
 	cmd.Flags().StringVarP(&opts.RestartPolicyCondition, "restart", "", containers.RestartPolicyNone, "Restart policy to apply when a container exits")
+	cmd.Flags().StringVar(&opts.Platform, "platform", "", "Set platform if the image is multi-platform, e.g. windows/amd64")
 
 	if contextType == store.AciContextType {
 		cmd.Flags().StringVar(&opts.DomainName, "domainname", "", "Container NIS domain name")
+		cmd.Flags().BoolVarP(&opts.Interactive, "interactive", "i", false, "Keep the container running and attach an interactive session to it")
+		cmd.Flags().BoolVarP(&opts.Tty, "tty", "t", false, "Allocate a pseudo-TTY for the interactive session")
+		cmd.Flags().BoolVar(&opts.OverrideBudget, "override-budget", false, "Run the container even if it exceeds the context budget")
+	}
+	if contextType == store.LocalContextType {
+		cmd.Flags().StringVar(&opts.Isolation, "isolation", "", `Isolation technology of Windows containers: "process" or "hyperv"`)
+		cmd.Flags().StringVar(&opts.Runtime, "runtime", "", "Runtime to use for this container, e.g. runsc to sandbox untrusted images with gVisor")
+		cmd.Flags().StringVar(&opts.StopSignal, "stop-signal", "", "Signal to stop the container")
+		cmd.Flags().IntVar(&opts.StopTimeout, "stop-timeout", 0, "Timeout (in seconds) to stop the container before killing it")
+		cmd.Flags().StringArrayVar(&opts.Ulimits, "ulimit", []string{}, "Ulimit options, as name=soft[:hard]")
+		cmd.Flags().StringArrayVar(&opts.Sysctls, "sysctl", []string{}, "Sysctl options, as name=value")
+		cmd.Flags().StringArrayVar(&opts.CapAdd, "cap-add", []string{}, "Add Linux capabilities")
+		cmd.Flags().StringArrayVar(&opts.CapDrop, "cap-drop", []string{}, "Drop Linux capabilities")
+		cmd.Flags().BoolVar(&opts.ReadOnly, "read-only", false, "Mount the container's root filesystem as read only")
+		cmd.Flags().StringArrayVar(&opts.Tmpfs, "tmpfs", []string{}, "Mount a tmpfs directory, as path[:options]")
+		cmd.Flags().Var(&opts.ShmSize, "shm-size", "Size of /dev/shm")
+		cmd.Flags().StringArrayVar(&opts.Devices, "device", []string{}, "Add a host device to the container, as host[:container][:permissions]")
+		cmd.Flags().Var(&opts.GPUs, "gpus", `GPU devices to add to the container ("all" to pass all GPUs)`)
 	}
 
 	return cmd
 }
 
-func runRun(ctx context.Context, image string, opts run.Opts) error {
+func runRun(ctx context.Context, image string, command []string, opts run.Opts) error {
 	c, err := client.New(ctx)
 	if err != nil {
 		return err
@@ -79,25 +113,92 @@ func runRun(ctx context.Context, image string, opts run.Opts) error {
 		return err
 	}
 
-	if !opts.Detach {
-		var con io.Writer = os.Stdout
-		req := containers.LogsRequest{
-			Follow: true,
+	if opts.Detach {
+		fmt.Println(result)
+		return nil
+	}
+	if opts.Interactive {
+		return session(ctx, c.ContainerService(), containerConfig.ID, command, opts.Tty)
+	}
+	return attach(ctx, c.ContainerService(), containerConfig.ID, opts.SigProxy)
+}
+
+const defaultShell = "/bin/sh"
+
+// session opens an interactive exec session in the container and stops the container once it is closed
+func session(ctx context.Context, cs containers.Service, containerID string, command []string, tty bool) error {
+	if len(command) == 0 {
+		command = []string{defaultShell}
+	}
+	request := containers.ExecRequest{
+		Command:     command[0],
+		Tty:         tty,
+		Interactive: true,
+		Stdin:       os.Stdin,
+		Stdout:      os.Stdout,
+		Stderr:      os.Stderr,
+	}
+	if tty {
+		con := console.Current()
+		if err := con.SetRaw(); err != nil {
+			return err
 		}
-		if c, err := console.ConsoleFromFile(os.Stdout); err == nil {
-			size, err := c.Size()
-			if err != nil {
-				return err
+		defer func() {
+			if err := con.Reset(); err != nil {
+				fmt.Println(i18n.T("console.close-failed"))
 			}
-			req.Width = int(size.Width)
-			con = c
+		}()
+
+		request.Stdin = con
+		request.Stdout = con
+		request.Stderr = con
+	}
+
+	err := cs.Exec(ctx, containerID, request)
+	// the container only runs to host the session, as it would exit with its main process locally
+	if stopErr := cs.Stop(context.Background(), containerID, nil); err == nil {
+		err = stopErr
+	}
+	return err
+}
+
+// attach streams the container logs until it exits and returns its exit code as an error if not zero
+func attach(ctx context.Context, cs containers.Service, containerID string, sigProxy bool) error {
+	var con io.Writer = os.Stdout
+	req := containers.LogsRequest{
+		Follow: true,
+	}
+	if c, err := console.ConsoleFromFile(os.Stdout); err == nil {
+		size, err := c.Size()
+		if err != nil {
+			return err
 		}
+		req.Width = int(size.Width)
+		con = c
+	}
 
-		req.Writer = con
+	req.Writer = con
 
-		return c.ContainerService().Logs(ctx, opts.Name, req)
+	err := cs.Logs(ctx, containerID, req)
+	if ctx.Err() != nil {
+		// interrupted by the user, the context can't be used anymore to call the backend
+		if sigProxy {
+			if err := cs.Stop(context.Background(), containerID, nil); err != nil {
+				return err
+			}
+		}
+		return errdefs.ErrCanceled
+	}
+	if err != nil {
+		return err
 	}
 
-	fmt.Println(result)
+	container, err := cs.Inspect(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	if container.ExitCode != 0 {
+		return errdefs.ExitCodeError{Code: container.ExitCode}
+	}
 	return nil
 }
@@ -18,11 +18,15 @@ package run
 
 import (
 	"bytes"
+	"context"
 	"strings"
 	"testing"
 
 	"gotest.tools/v3/assert"
 	"gotest.tools/v3/golden"
+
+	"github.com/docker/compose-cli/api/containers"
+	"github.com/docker/compose-cli/errdefs"
 )
 
 func TestHelp(t *testing.T) {
@@ -40,3 +44,74 @@ func TestHelpNoDomainFlag(t *testing.T) {
 	_ = c.Help()
 	assert.Assert(t, !strings.Contains(b.String(), "domainname"))
 }
+
+type fakeContainers struct {
+	containers.Service
+	exitCode int
+	stopped  bool
+	command  string
+}
+
+func (f *fakeContainers) Exec(ctx context.Context, containerName string, request containers.ExecRequest) error {
+	f.command = request.Command
+	return nil
+}
+
+func (f *fakeContainers) Logs(ctx context.Context, containerName string, request containers.LogsRequest) error {
+	return nil
+}
+
+func (f *fakeContainers) Inspect(ctx context.Context, id string) (containers.Container, error) {
+	return containers.Container{ID: id, ExitCode: f.exitCode}, nil
+}
+
+func (f *fakeContainers) Stop(ctx context.Context, containerID string, timeout *uint32) error {
+	f.stopped = true
+	return nil
+}
+
+func TestAttachExitCode(t *testing.T) {
+	cs := &fakeContainers{exitCode: 3}
+	err := attach(context.TODO(), cs, "test", true)
+	assert.Equal(t, err, errdefs.ExitCodeError{Code: 3})
+
+	cs.exitCode = 0
+	assert.NilError(t, attach(context.TODO(), cs, "test", true))
+	assert.Assert(t, !cs.stopped)
+}
+
+func TestAttachInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	cs := &fakeContainers{}
+	err := attach(ctx, cs, "test", true)
+	assert.Assert(t, errdefs.IsErrCanceled(err))
+	assert.Assert(t, cs.stopped)
+
+	cs = &fakeContainers{}
+	_ = attach(ctx, cs, "test", false)
+	assert.Assert(t, !cs.stopped)
+}
+
+func TestSessionStopsContainer(t *testing.T) {
+	cs := &fakeContainers{}
+	assert.NilError(t, session(context.TODO(), cs, "test", nil, false))
+	assert.Equal(t, cs.command, "/bin/sh")
+	assert.Assert(t, cs.stopped)
+}
+
+func TestSessionCommand(t *testing.T) {
+	cs := &fakeContainers{}
+	assert.NilError(t, session(context.TODO(), cs, "test", []string{"bash"}, false))
+	assert.Equal(t, cs.command, "bash")
+
+	c := Command("aci")
+	c.SetArgs([]string{"--interactive", "alpine", "sh", "-c", "ls"})
+	c.SetOutput(&bytes.Buffer{})
+	assert.ErrorContains(t, c.Execute(), "only the binary can be specified")
+
+	c = Command("aci")
+	c.SetArgs([]string{"alpine", "sh"})
+	c.SetOutput(&bytes.Buffer{})
+	assert.ErrorContains(t, c.Execute(), "a command can only be specified with --interactive")
+}
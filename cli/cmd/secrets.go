@@ -23,16 +23,21 @@ import (
 	"strings"
 	"text/tabwriter"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose-cli/api/client"
 	"github.com/docker/compose-cli/api/secrets"
+	"github.com/docker/compose-cli/audit"
+	"github.com/docker/compose-cli/i18n"
+	"github.com/docker/compose-cli/prompt"
 )
 
 type createSecretOptions struct {
 	Label       string
 	Username    string
 	Password    string
+	StdIn       bool
 	Description string
 }
 
@@ -55,15 +60,19 @@ func SecretCommand() *cobra.Command {
 func createSecret() *cobra.Command {
 	opts := createSecretOptions{}
 	cmd := &cobra.Command{
-		Use:   "create NAME",
-		Short: "Creates a secret.",
-		Args:  cobra.ExactArgs(1),
+		Use:         "create NAME",
+		Annotations: audit.Mutating(),
+		Short:       "Creates a secret.",
+		Args:        cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			c, err := client.New(cmd.Context())
 			if err != nil {
 				return err
 			}
 			name := args[0]
+			if err := opts.readPassword(); err != nil {
+				return err
+			}
 			secret := secrets.NewSecret(name, opts.Username, opts.Password, opts.Description)
 			id, err := c.SecretsService().CreateSecret(cmd.Context(), secret)
 			if err != nil {
@@ -76,10 +85,34 @@ func createSecret() *cobra.Command {
 
 	cmd.Flags().StringVarP(&opts.Username, "username", "u", "", "username")
 	cmd.Flags().StringVarP(&opts.Password, "password", "p", "", "password")
+	cmd.Flags().BoolVar(&opts.StdIn, "password-stdin", false, "Take the password from stdin")
 	cmd.Flags().StringVarP(&opts.Description, "description", "d", "", "Secret description")
 	return cmd
 }
 
+// readPassword asks for the password of a secret with a username when it isn't set by flag, as docker login does
+func (opts *createSecretOptions) readPassword() error {
+	if opts.StdIn && opts.Password != "" {
+		return i18n.Error("secret.password-exclusive")
+	}
+	if opts.Password != "" {
+		fmt.Fprintln(os.Stderr, i18n.T("secret.password-insecure"))
+		return nil
+	}
+	if opts.Username == "" && !opts.StdIn {
+		return nil
+	}
+	password, err := prompt.ReadSecret(prompt.User{}, os.Stdin, "Password", opts.StdIn)
+	if errors.Is(err, prompt.ErrNotATerminal) {
+		return i18n.Wrap(err, "secret.password-stdin")
+	}
+	if err != nil {
+		return err
+	}
+	opts.Password = password
+	return nil
+}
+
 func inspectSecret() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "inspect ID",
@@ -133,10 +166,11 @@ type deleteSecretOptions struct {
 func deleteSecret() *cobra.Command {
 	opts := deleteSecretOptions{}
 	cmd := &cobra.Command{
-		Use:     "delete NAME",
-		Aliases: []string{"rm", "remove"},
-		Short:   "Removes a secret.",
-		Args:    cobra.ExactArgs(1),
+		Use:         "delete NAME",
+		Annotations: audit.Mutating(),
+		Aliases:     []string{"rm", "remove"},
+		Short:       "Removes a secret.",
+		Args:        cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			c, err := client.New(cmd.Context())
 			if err != nil {
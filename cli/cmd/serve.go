@@ -19,10 +19,12 @@ package cmd
 import (
 	"context"
 
-	"github.com/pkg/errors"
+	"github.com/docker/docker/client"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	"github.com/docker/compose-cli/i18n"
+	composev1 "github.com/docker/compose-cli/protos/compose/v1"
 	containersv1 "github.com/docker/compose-cli/protos/containers/v1"
 	contextsv1 "github.com/docker/compose-cli/protos/contexts/v1"
 	streamsv1 "github.com/docker/compose-cli/protos/streams/v1"
@@ -32,7 +34,8 @@ import (
 )
 
 type serveOpts struct {
-	address string
+	address        string
+	metricsAddress string
 }
 
 // ServeCommand returns the command to serve the API
@@ -48,6 +51,7 @@ func ServeCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&opts.address, "address", "", "The address to listen to")
+	cmd.Flags().StringVar(&opts.metricsAddress, "metrics-address", "", "The address to serve Prometheus metrics on /metrics, e.g. :9090")
 
 	return cmd
 }
@@ -57,18 +61,30 @@ func runServe(ctx context.Context, opts serveOpts) error {
 
 	listener, err := server.CreateListener(opts.address)
 	if err != nil {
-		return errors.Wrap(err, "listen address "+opts.address)
+		return i18n.Wrap(err, "serve.listen", opts.address)
 	}
 	// nolint errcheck
 	defer listener.Close()
 
 	p := proxy.New(ctx)
 
+	composev1.RegisterComposeServer(s, p)
 	containersv1.RegisterContainersServer(s, p)
 	contextsv1.RegisterContextsServer(s, p.ContextsProxy())
 	streamsv1.RegisterStreamingServer(s, p)
 	volumesv1.RegisterVolumesServer(s, p)
 
+	if opts.metricsAddress != "" {
+		go func() {
+			if err := server.ServeMetrics(ctx, s, opts.metricsAddress); err != nil {
+				logrus.WithError(err).Error("cannot serve metrics")
+			}
+		}()
+		logrus.WithField("address", opts.metricsAddress).Info("serving Prometheus metrics on /metrics")
+	}
+
+	startScheduler(ctx)
+
 	go func() {
 		<-ctx.Done()
 		logrus.Info("stopping server")
@@ -80,3 +96,18 @@ func runServe(ctx context.Context, opts serveOpts) error {
 	// start the GRPC server to serve on the listener
 	return s.Serve(listener)
 }
+
+// startScheduler starts the containers of the local engine scheduled with x-schedule, if an engine is reachable
+func startScheduler(ctx context.Context) {
+	apiClient, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		logrus.WithError(err).Warn("cannot schedule local containers")
+		return
+	}
+	if _, err := apiClient.Ping(ctx); err != nil {
+		logrus.WithError(err).Debug("no local engine, not scheduling local containers")
+		return
+	}
+	go server.NewScheduler(apiClient).Run(ctx)
+	logrus.Info("scheduling local containers")
+}
@@ -21,20 +21,22 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/go-multierror"
-	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/audit"
 	"github.com/docker/compose-cli/cli/formatter"
 	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/i18n"
 )
 
 // StartCommand starts containers
 func StartCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "start",
-		Short: "Start one or more stopped containers",
-		Args:  cobra.MinimumNArgs(1),
+		Use:         "start",
+		Annotations: audit.Mutating(),
+		Short:       "Start one or more stopped containers",
+		Args:        cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runStart(cmd.Context(), args)
 		},
@@ -46,7 +48,7 @@ func StartCommand() *cobra.Command {
 func runStart(ctx context.Context, args []string) error {
 	c, err := client.New(ctx)
 	if err != nil {
-		return errors.Wrap(err, "cannot connect to backend")
+		return i18n.Wrap(err, "backend.connect")
 	}
 
 	var errs *multierror.Error
@@ -54,7 +56,7 @@ func runStart(ctx context.Context, args []string) error {
 		err := c.ContainerService().Start(ctx, id)
 		if err != nil {
 			if errdefs.IsNotFoundError(err) {
-				errs = multierror.Append(errs, fmt.Errorf("container %s not found", id))
+				errs = multierror.Append(errs, i18n.Error("container.not-found", id))
 			} else {
 				errs = multierror.Append(errs, err)
 			}
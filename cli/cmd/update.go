@@ -0,0 +1,88 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/i18n"
+	"github.com/docker/compose-cli/update"
+)
+
+type updateOptions struct {
+	channel string
+	check   bool
+}
+
+// SelfUpdateCommand manages the installation of the cloud integration
+func SelfUpdateCommand(version string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compose-cli",
+		Short: "Manage the Docker cloud integration installation",
+	}
+	cmd.AddCommand(updateCommand(version))
+	return cmd
+}
+
+func updateCommand(version string) *cobra.Command {
+	opts := updateOptions{}
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update the Docker cloud integration to the latest release",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUpdate(cmd, version, opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.channel, "channel", update.StableChannel, `Release channel: "stable" or "edge"`)
+	cmd.Flags().BoolVar(&opts.check, "check", false, "Only check for an update, exit with status 1 if one is available")
+	return cmd
+}
+
+func runUpdate(cmd *cobra.Command, version string, opts updateOptions) error {
+	ctx := cmd.Context()
+	release, err := update.Latest(ctx, opts.channel)
+	if err != nil {
+		return err
+	}
+	if !release.Newer(version) {
+		fmt.Println(i18n.T("update.up-to-date", version, opts.channel))
+		return nil
+	}
+	if opts.check {
+		fmt.Println(i18n.T("update.available", release.Version, opts.channel, version))
+		return errdefs.ExitCodeError{Code: 1}
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	fmt.Println(i18n.T("update.download", release.Version))
+	data, err := update.Download(ctx, release, version)
+	if err != nil {
+		return err
+	}
+	if err := update.Replace(executable, data); err != nil {
+		return err
+	}
+	fmt.Println(i18n.T("update.done", version, release.Version))
+	return nil
+}
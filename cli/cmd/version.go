@@ -18,12 +18,15 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose-cli/cli/cmd/mobyflags"
 	"github.com/docker/compose-cli/cli/mobycli"
+	"github.com/docker/compose-cli/context/store"
 )
 
 // VersionCommand command to display version
@@ -38,7 +41,7 @@ func VersionCommand(version string) *cobra.Command {
 	}
 	// define flags for backward compatibility with com.docker.cli
 	flags := cmd.Flags()
-	flags.StringP("format", "f", "", "Format the output using the given Go template")
+	flags.StringP("format", "f", "", `Format the output using the given Go template, or "json" to include the current context backend`)
 	flags.String("kubeconfig", "", "Kubernetes config file")
 	mobyflags.AddMobyFlagsForRetrocompatibility(flags)
 
@@ -46,15 +49,27 @@ func VersionCommand(version string) *cobra.Command {
 }
 
 func runVersion(cmd *cobra.Command, version string) error {
+	if format, _ := cmd.Flags().GetString("format"); format == "json" {
+		return printEnvironmentJSON(os.Stdout, currentEnvironment(cmd.Context(), version))
+	}
 	displayedVersion := strings.TrimPrefix(version, "v")
 	versionResult, _ := mobycli.ExecSilent(cmd.Context())
 	// we don't want to fail on error, there is an error if the engine is not available but it displays client version info
 	// Still, technically the [] byte versionResult could be nil, just let the original command display what it has to display
 	if versionResult == nil {
-		mobycli.Exec(cmd.Root())
+		mobycli.Exec(cmd.Context(), cmd.Root())
 		return nil
 	}
 	var s string = string(versionResult)
 	fmt.Print(strings.Replace(s, "\n Version:", "\n Cloud integration  "+displayedVersion+"\n Version:", 1))
+	if format, _ := cmd.Flags().GetString("format"); format != "" {
+		return nil
+	}
+	if env := currentEnvironment(cmd.Context(), version); env.ContextType != store.DefaultContextType {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+		fmt.Fprintln(w)
+		printContext(w, env)
+		return w.Flush()
+	}
 	return nil
 }
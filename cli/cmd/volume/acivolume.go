@@ -25,6 +25,7 @@ import (
 
 	"github.com/docker/compose-cli/aci"
 	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/audit"
 	"github.com/docker/compose-cli/cli/formatter"
 	"github.com/docker/compose-cli/progress"
 )
@@ -47,9 +48,10 @@ func ACICommand() *cobra.Command {
 func createVolume() *cobra.Command {
 	aciOpts := aci.VolumeCreateOptions{}
 	cmd := &cobra.Command{
-		Use:   "create --storage-account ACCOUNT VOLUME",
-		Short: "Creates an Azure file share to use as ACI volume.",
-		Args:  cobra.ExactArgs(1),
+		Use:         "create --storage-account ACCOUNT VOLUME",
+		Annotations: audit.Mutating(),
+		Short:       "Creates an Azure file share to use as ACI volume.",
+		Args:        cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			c, err := client.New(ctx)
@@ -78,9 +80,10 @@ func createVolume() *cobra.Command {
 
 func rmVolume() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "rm [OPTIONS] VOLUME [VOLUME...]",
-		Short: "Remove one or more volumes.",
-		Args:  cobra.MinimumNArgs(1),
+		Use:         "rm [OPTIONS] VOLUME [VOLUME...]",
+		Annotations: audit.Mutating(),
+		Short:       "Remove one or more volumes.",
+		Args:        cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			c, err := client.New(cmd.Context())
 			if err != nil {
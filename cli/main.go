@@ -24,16 +24,21 @@ import (
 	"os/signal"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"syscall"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/label"
 
+	apicompose "github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/audit"
 	"github.com/docker/compose-cli/cli/cmd"
 	"github.com/docker/compose-cli/cli/cmd/compose"
 	contextcmd "github.com/docker/compose-cli/cli/cmd/context"
+	"github.com/docker/compose-cli/cli/cmd/image"
 	"github.com/docker/compose-cli/cli/cmd/login"
 	"github.com/docker/compose-cli/cli/cmd/logout"
 	"github.com/docker/compose-cli/cli/cmd/run"
@@ -44,7 +49,10 @@ import (
 	apicontext "github.com/docker/compose-cli/context"
 	"github.com/docker/compose-cli/context/store"
 	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/i18n"
 	"github.com/docker/compose-cli/metrics"
+	"github.com/docker/compose-cli/output"
+	"github.com/docker/compose-cli/tracing"
 
 	// Backend registrations
 	_ "github.com/docker/compose-cli/aci"
@@ -60,12 +68,15 @@ var (
 
 var (
 	contextAgnosticCommands = map[string]struct{}{
-		"compose": {},
-		"context": {},
-		"login":   {},
-		"logout":  {},
-		"serve":   {},
-		"version": {},
+		"audit":       {},
+		"compose":     {},
+		"compose-cli": {},
+		"config-cli":  {},
+		"context":     {},
+		"login":       {},
+		"logout":      {},
+		"serve":       {},
+		"version":     {},
 	}
 	unknownCommandRegexp = regexp.MustCompile(`unknown command "([^"]*)"`)
 )
@@ -91,6 +102,10 @@ func isContextAgnosticCommand(cmd *cobra.Command) bool {
 	if _, ok := contextAgnosticCommands[cmd.Name()]; ok {
 		return true
 	}
+	// aliases are dispatched to the command they run
+	if _, ok := cmd.Annotations[aliasAnnotation]; ok {
+		return true
+	}
 	return isContextAgnosticCommand(cmd.Parent())
 }
 
@@ -123,9 +138,14 @@ func main() {
 		login.Command(),
 		logout.Command(),
 		cmd.VersionCommand(version),
+		cmd.InfoCommand(version),
+		cmd.SelfUpdateCommand(version),
 		cmd.StopCommand(),
 		cmd.KillCommand(),
 		cmd.SecretCommand(),
+		cmd.PortForwardCommand(),
+		cmd.AuditCommand(),
+		cmd.ConfigCLICommand(),
 
 		// Place holders
 		cmd.EcsCommand(),
@@ -141,6 +161,11 @@ func main() {
 
 	root.PersistentFlags().BoolVarP(&opts.Debug, "debug", "D", false, "enable debug output in the logs")
 	root.PersistentFlags().StringVarP(&opts.Host, "host", "H", "", "Daemon socket(s) to connect to")
+	root.PersistentFlags().BoolVar(&opts.RequireBackend, mobycli.RequireBackendFlag, false, "Fail commands the current context backend doesn't support rather than routing them to the Docker engine")
+	offline, _ := strconv.ParseBool(os.Getenv(config.OfflineEnvVar))
+	root.PersistentFlags().BoolVar(&opts.Offline, config.OfflineFlagName, offline, "Skip telemetry and update checks, and fail fast with an offline error on commands reaching cloud backends")
+	output.AddNoColorFlag(root.PersistentFlags())
+	i18n.AddLanguageFlag(root.PersistentFlags())
 	opts.AddConfigFlags(root.PersistentFlags())
 	opts.AddContextFlags(root.PersistentFlags())
 	root.Flags().BoolVarP(&opts.Version, "version", "v", false, "Print version information and quit")
@@ -150,13 +175,19 @@ func main() {
 	if opts.Debug {
 		logrus.SetLevel(logrus.DebugLevel)
 	}
+	if opts.Offline {
+		if err := config.SetOffline(true); err != nil {
+			fatal(err)
+		}
+	}
 
 	ctx, cancel := newSigContext()
 	defer cancel()
+	ctx = mobycli.WithRequireBackend(ctx, opts.RequireBackend)
 
 	// --host and --version should immediately be forwarded to the original cli
 	if opts.Host != "" || opts.Version {
-		mobycli.Exec(root)
+		mobycli.Exec(ctx, root)
 	}
 
 	if opts.Config == "" {
@@ -165,11 +196,17 @@ func main() {
 	configDir := opts.Config
 	ctx = config.WithDir(ctx, configDir)
 
+	cliConfig, err := config.LoadCLIConfig(configDir)
+	if err != nil {
+		fatal(err)
+	}
+	applyCLIConfig(cliConfig)
+
 	currentContext := determineCurrentContext(opts.Context, configDir)
 
-	s, err := store.New(configDir)
+	s, err := store.NewFromEnv(ctx, configDir)
 	if err != nil {
-		mobycli.Exec(root)
+		mobycli.Exec(ctx, root)
 	}
 
 	ctype := store.DefaultContextType
@@ -187,13 +224,36 @@ func main() {
 		// we can also pass ctype as a parameter to the volume command and customize subcommands, flags, etc. when we have other backend implementations
 		root.AddCommand(volume.ACICommand())
 	}
+	if ctype == store.LocalContextType {
+		root.AddCommand(image.Command())
+	}
+	defaultFlags := func(command string) []string {
+		return cliConfig.DefaultFlags(ctype, command)
+	}
+	addAliasCommands(root, cliConfig.Aliases, defaultFlags)
+	os.Args = append(os.Args[:1], withDefaultFlags(os.Args[1:], root, defaultFlags)...)
 
 	ctx = apicontext.WithCurrentContext(ctx, currentContext)
 	ctx = store.WithContextStore(ctx, s)
 
-	if err = root.ExecuteContext(ctx); err != nil {
+	flushTraces, err := tracing.Init(tracingEndpoint(s, currentContext, ctype), version)
+	if err != nil {
+		logrus.Warnf("cannot export traces: %v", err)
+		flushTraces = func() {}
+	}
+	ctx, span := tracing.Start(ctx, commandSpanName(os.Args[1:], root), label.String("compose.context", currentContext))
+	err = root.ExecuteContext(ctx)
+	tracing.End(span, err)
+	flushTraces()
+	trackAudit(root, configDir, currentContext, ctype, err)
+
+	if err != nil {
 		// if user canceled request, simply exit without any error message
 		if errdefs.IsErrCanceled(err) || errors.Is(ctx.Err(), context.Canceled) {
+			var canceled apicompose.CanceledError
+			if errors.As(err, &canceled) {
+				fmt.Fprintln(os.Stderr, canceled.Error())
+			}
 			metrics.Track(ctype, os.Args[1:], root.PersistentFlags(), metrics.CanceledStatus)
 			os.Exit(130)
 		}
@@ -223,6 +283,10 @@ func exit(root *cobra.Command, ctx string, err error, ctype string) {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(errdefs.ExitCodeLoginRequired)
 	}
+	var exitCodeErr errdefs.ExitCodeError
+	if errors.As(err, &exitCodeErr) {
+		os.Exit(exitCodeErr.Code)
+	}
 	if errors.Is(err, errdefs.ErrNotImplemented) {
 		name := metrics.GetCommand(os.Args[1:], root.PersistentFlags())
 		fmt.Fprintf(os.Stderr, "Command %q not available in current context (%s)\n", name, ctx)
@@ -251,6 +315,50 @@ func checkIfUnknownCommandExistInDefaultContext(err error, currentContext string
 	}
 }
 
+// trackAudit records mutating commands handled by the CLI in the audit log
+func trackAudit(root *cobra.Command, configDir string, currentContext string, ctype string, err error) {
+	command, _, findErr := root.Find(os.Args[1:])
+	if findErr != nil {
+		return
+	}
+	status := audit.SuccessStatus
+	switch {
+	case errdefs.IsErrCanceled(err) || errors.Is(err, context.Canceled):
+		status = audit.CanceledStatus
+	case err != nil:
+		status = audit.FailureStatus
+	}
+	if trackErr := audit.Track(configDir, currentContext, ctype, command, status, err); trackErr != nil {
+		logrus.Warnf("cannot write audit log: %v", trackErr)
+	}
+}
+
+// tracingEndpoint returns the OpenTelemetry collector endpoint configured on cloud contexts
+func tracingEndpoint(s store.Store, currentContext string, ctype string) string {
+	switch ctype {
+	case store.AciContextType:
+		var aciContext store.AciContext
+		if err := s.GetEndpoint(currentContext, &aciContext); err == nil {
+			return aciContext.TracingEndpoint
+		}
+	case store.EcsContextType:
+		var ecsContext store.EcsContext
+		if err := s.GetEndpoint(currentContext, &ecsContext); err == nil {
+			return ecsContext.TracingEndpoint
+		}
+	}
+	return ""
+}
+
+// commandSpanName names the root span after the command being run, without its arguments
+func commandSpanName(args []string, root *cobra.Command) string {
+	command, _, err := root.Find(args)
+	if err != nil || command == nil {
+		return root.Name()
+	}
+	return command.CommandPath()
+}
+
 func newSigContext() (context.Context, func()) {
 	ctx, cancel := context.WithCancel(context.Background())
 	s := make(chan os.Signal, 1)
@@ -258,6 +366,9 @@ func newSigContext() (context.Context, func()) {
 	go func() {
 		<-s
 		cancel()
+		// commands may clean up in-flight resources once canceled, a second signal aborts them
+		<-s
+		os.Exit(130)
 	}()
 	return ctx, cancel
 }
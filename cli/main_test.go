@@ -22,9 +22,11 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/spf13/cobra"
 	"gotest.tools/v3/assert"
 
 	"github.com/docker/compose-cli/cli/cmd"
+	"github.com/docker/compose-cli/cli/cmd/compose"
 	"github.com/docker/compose-cli/cli/cmd/context"
 	"github.com/docker/compose-cli/cli/cmd/login"
 	"github.com/docker/compose-cli/cli/cmd/run"
@@ -64,8 +66,78 @@ func TestCheckOwnCommand(t *testing.T) {
 	assert.Assert(t, isContextAgnosticCommand(login.Command()))
 	assert.Assert(t, isContextAgnosticCommand(context.Command()))
 	assert.Assert(t, isContextAgnosticCommand(cmd.ServeCommand()))
+	assert.Assert(t, isContextAgnosticCommand(cmd.ConfigCLICommand()))
 	assert.Assert(t, !isContextAgnosticCommand(run.Command("default")))
 	assert.Assert(t, !isContextAgnosticCommand(cmd.ExecCommand()))
 	assert.Assert(t, !isContextAgnosticCommand(cmd.LogsCommand()))
 	assert.Assert(t, !isContextAgnosticCommand(cmd.PsCommand()))
 }
+
+func testRoot() *cobra.Command {
+	root := &cobra.Command{Use: "docker"}
+	root.PersistentFlags().String("context", "", "")
+	root.PersistentFlags().BoolP("debug", "D", false, "")
+	root.AddCommand(cmd.PsCommand(), compose.Command("aci"))
+	return root
+}
+
+func TestAliasCommands(t *testing.T) {
+	defer func(args []string) {
+		os.Args = args
+	}(os.Args)
+	root := testRoot()
+	var upArgs []string
+	up := &cobra.Command{
+		Use: "up",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			upArgs = args
+			return nil
+		},
+	}
+	up.Flags().Bool("wait", false, "")
+	up.Flags().StringP("file", "f", "", "")
+	root.AddCommand(&cobra.Command{Use: "stack"})
+	root.Commands()[len(root.Commands())-1].AddCommand(up)
+	addAliasCommands(root, map[string]string{
+		"deploy": "stack up --wait",
+		"ps":     "compose ps",
+		"redo":   "deploy",
+	}, func(command string) []string {
+		if command == "stack up" {
+			return []string{"--file", "default.yaml"}
+		}
+		return nil
+	})
+
+	deploy, _, err := root.Find([]string{"deploy"})
+	assert.NilError(t, err)
+	assert.Equal(t, deploy.Annotations[aliasAnnotation], "stack up --wait")
+	assert.Assert(t, isContextAgnosticCommand(deploy))
+	// commands can't be overridden
+	ps, _, err := root.Find([]string{"ps"})
+	assert.NilError(t, err)
+	assert.Equal(t, ps.Annotations[aliasAnnotation], "")
+
+	root.SetArgs([]string{"deploy", "-f", "prod.yaml", "web"})
+	assert.NilError(t, root.Execute())
+	assert.DeepEqual(t, upArgs, []string{"web"})
+	assert.Equal(t, up.Flags().Lookup("file").Value.String(), "prod.yaml")
+	assert.Equal(t, up.Flags().Lookup("wait").Value.String(), "true")
+
+	root.SetArgs([]string{"redo"})
+	assert.ErrorContains(t, root.Execute(), `alias "redo" can't run alias "deploy"`)
+}
+
+func TestWithDefaultFlags(t *testing.T) {
+	root := testRoot()
+	defaults := func(command string) []string {
+		if command == "compose up" {
+			return []string{"--wait"}
+		}
+		return nil
+	}
+	assert.DeepEqual(t, withDefaultFlags([]string{"--context", "up", "compose", "up", "--wait=false"}, root, defaults),
+		[]string{"--context", "up", "compose", "up", "--wait", "--wait=false"})
+	assert.DeepEqual(t, withDefaultFlags([]string{"compose", "down"}, root, defaults), []string{"compose", "down"})
+	assert.DeepEqual(t, withDefaultFlags([]string{"ps"}, root, defaults), []string{"ps"})
+}
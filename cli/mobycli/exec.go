@@ -45,7 +45,7 @@ func ExecIfDefaultCtxType(ctx context.Context, root *cobra.Command) {
 	currentCtx, err := s.Get(currentContext)
 	// Only run original docker command if the current context is not ours.
 	if err != nil || mustDelegateToMoby(currentCtx.Type()) {
-		Exec(root)
+		Exec(ctx, root)
 	}
 }
 
@@ -58,9 +58,15 @@ func mustDelegateToMoby(ctxType string) bool {
 	return false
 }
 
-// Exec delegates to com.docker.cli if on moby context
-func Exec(root *cobra.Command) {
-	cmd := exec.Command(ComDockerCli, os.Args[1:]...)
+// Exec delegates to com.docker.cli if on moby context. Every command routed to the docker engine goes through it, so
+// that the route is checked against --require-backend, even before the context store is loaded.
+func Exec(ctx context.Context, root *cobra.Command) {
+	if err := checkRoute(ctx, root); err != nil {
+		metrics.Track(store.DefaultContextType, os.Args[1:], root.PersistentFlags(), metrics.FailureStatus)
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	cmd := exec.Command(ComDockerCli, dockerArgs(os.Args[1:])...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -113,6 +119,6 @@ func IsDefaultContextCommand(dockerCommand string) bool {
 
 // ExecSilent executes a command and do redirect output to stdOut, return output
 func ExecSilent(ctx context.Context) ([]byte, error) {
-	cmd := exec.CommandContext(ctx, ComDockerCli, os.Args[1:]...)
+	cmd := exec.CommandContext(ctx, ComDockerCli, dockerArgs(os.Args[1:])...)
 	return cmd.CombinedOutput()
 }
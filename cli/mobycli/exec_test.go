@@ -17,11 +17,14 @@
 package mobycli
 
 import (
+	"context"
 	"testing"
 
+	"github.com/spf13/cobra"
 	"gotest.tools/v3/assert"
 
 	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/errdefs"
 )
 
 func TestDelegateContextTypeToMoby(t *testing.T) {
@@ -44,3 +47,20 @@ func TestDelegateContextTypeToMoby(t *testing.T) {
 		assert.Assert(t, !mustDelegateToMoby(ctx))
 	}
 }
+
+func TestDockerArgsRemoveCliOnlyFlags(t *testing.T) {
+	args := dockerArgs([]string{"--context", "aci", "--require-backend", "login", "--require-backend=true", "myregistry"})
+	assert.DeepEqual(t, args, []string{"--context", "aci", "login", "myregistry"})
+
+	args = dockerArgs([]string{"--lang", "fr", "--no-color", "build", "--lang=fr", "."})
+	assert.DeepEqual(t, args, []string{"build", "."})
+}
+
+func TestCheckRouteWithoutContextStore(t *testing.T) {
+	root := &cobra.Command{Use: "docker"}
+	assert.NilError(t, checkRoute(context.Background(), root))
+
+	err := checkRoute(WithRequireBackend(context.Background(), true), root)
+	assert.Assert(t, errdefs.IsErrNotImplemented(err))
+	assert.ErrorContains(t, err, "is routed to the Docker engine")
+}
@@ -0,0 +1,151 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package mobycli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/config"
+	apicontext "github.com/docker/compose-cli/context"
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/i18n"
+	"github.com/docker/compose-cli/metrics"
+	"github.com/docker/compose-cli/output"
+	"github.com/docker/compose-cli/utils"
+)
+
+// RequireBackendFlag is the global flag making commands fail rather than being routed to the docker engine
+const RequireBackendFlag = "require-backend"
+
+var (
+	// cliOnlyFlags are global flags which are not known by the classic docker CLI
+	cliOnlyFlags = []string{RequireBackendFlag, config.OfflineFlagName, output.NoColorFlagName}
+	// cliOnlyValueFlags are global flags with a value, which are not known by the classic docker CLI
+	cliOnlyValueFlags = []string{i18n.LanguageFlagName}
+)
+
+type requireBackendKey struct{}
+
+// WithRequireBackend sets whether commands must be handled by the current context backend
+func WithRequireBackend(ctx context.Context, require bool) context.Context {
+	return context.WithValue(ctx, requireBackendKey{}, require)
+}
+
+func requireBackend(ctx context.Context) bool {
+	require, _ := ctx.Value(requireBackendKey{}).(bool)
+	return require
+}
+
+// Route is the routing decision for a command delegated to the classic docker CLI
+type Route struct {
+	Command     string
+	Context     string
+	ContextType string
+}
+
+// ToEngine returns true when the command is handled by the docker engine rather than by the current context backend
+func (r Route) ToEngine() bool {
+	return !mustDelegateToMoby(r.ContextType)
+}
+
+// String describes the routing decision to the user
+func (r Route) String() string {
+	if !r.ToEngine() {
+		return fmt.Sprintf("Command %q is handled by context %q", r.Command, r.Context)
+	}
+	return fmt.Sprintf("Command %q is not supported by the %s backend of context %q, it is routed to the Docker engine", r.Command, r.ContextType, r.Context)
+}
+
+// GetRoute returns the routing decision for a command delegated to the classic docker CLI
+func GetRoute(ctx context.Context, root *cobra.Command) (Route, bool) {
+	if ctx == nil {
+		return Route{}, false
+	}
+	s := store.ContextStore(ctx)
+	if s == nil {
+		return Route{}, false
+	}
+	currentContext := apicontext.CurrentContext(ctx)
+	cc, err := s.Get(currentContext)
+	if err != nil {
+		return Route{}, false
+	}
+	return Route{
+		Command:     metrics.GetCommand(os.Args[1:], root.PersistentFlags()),
+		Context:     currentContext,
+		ContextType: cc.Type(),
+	}, true
+}
+
+// checkRoute warns when a command is routed to the docker engine while current context targets another backend,
+// or fails if the user required the command to be handled by the backend. Commands routed before the current context
+// is known, as with --host or when the context store can't be read, are handled by the engine too.
+func checkRoute(ctx context.Context, root *cobra.Command) error {
+	r, ok := GetRoute(ctx, root)
+	if ok && !r.ToEngine() {
+		return nil
+	}
+	if !requireBackend(ctx) {
+		if ok {
+			fmt.Fprintln(os.Stderr, r.String())
+		}
+		return nil
+	}
+	if !ok {
+		return errors.Wrapf(errdefs.ErrNotImplemented, "command %q is routed to the Docker engine", metrics.GetCommand(os.Args[1:], root.PersistentFlags()))
+	}
+	return errors.Wrapf(errdefs.ErrNotImplemented, "command %q is not supported by the %s backend of context %q", r.Command, r.ContextType, r.Context)
+}
+
+// dockerArgs removes flags the classic docker CLI doesn't know about from command line arguments
+func dockerArgs(args []string) []string {
+	res := []string{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if isCliOnlyFlag(arg) {
+			continue
+		}
+		if strings.HasPrefix(arg, "--") && utils.StringContains(cliOnlyValueFlags, arg[2:]) {
+			// skip the flag value
+			i++
+			continue
+		}
+		res = append(res, arg)
+	}
+	return res
+}
+
+func isCliOnlyFlag(arg string) bool {
+	for _, flag := range cliOnlyFlags {
+		if arg == "--"+flag || strings.HasPrefix(arg, "--"+flag+"=") {
+			return true
+		}
+	}
+	for _, flag := range cliOnlyValueFlags {
+		if strings.HasPrefix(arg, "--"+flag+"=") {
+			return true
+		}
+	}
+	return false
+}
@@ -25,7 +25,9 @@ import (
 type GlobalOpts struct {
 	apicontext.ContextFlags
 	cliconfig.ConfigFlags
-	Debug   bool
-	Version bool
-	Host    string
+	Debug          bool
+	Version        bool
+	Host           string
+	RequireBackend bool
+	Offline        bool
 }
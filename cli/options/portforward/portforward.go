@@ -0,0 +1,46 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package portforward
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// Parse parses a port forwarding specification, either LOCAL:REMOTE or PORT to use the same port on both ends
+func Parse(spec string) (uint32, uint32, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) > 2 {
+		return 0, 0, errors.Wrapf(errdefs.ErrParsingFailed, "invalid port forwarding %q, expected LOCAL:REMOTE", spec)
+	}
+	ports := make([]uint32, len(parts))
+	for i, part := range parts {
+		port, err := strconv.ParseUint(part, 10, 16)
+		if err != nil || port == 0 {
+			return 0, 0, errors.Wrapf(errdefs.ErrParsingFailed, "invalid port %q", part)
+		}
+		ports[i] = uint32(port)
+	}
+	if len(ports) == 1 {
+		return ports[0], ports[0], nil
+	}
+	return ports[0], ports[1], nil
+}
@@ -0,0 +1,44 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package portforward
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestParse(t *testing.T) {
+	local, remote, err := Parse("8080:80")
+	assert.NilError(t, err)
+	assert.Equal(t, local, uint32(8080))
+	assert.Equal(t, remote, uint32(80))
+
+	local, remote, err = Parse("5432")
+	assert.NilError(t, err)
+	assert.Equal(t, local, uint32(5432))
+	assert.Equal(t, remote, uint32(5432))
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, spec := range []string{"", "80:", "a:80", "1:2:3", "70000", "0"} {
+		_, _, err := Parse(spec)
+		assert.Assert(t, errdefs.IsErrParsingFailed(err), spec)
+	}
+}
@@ -23,6 +23,8 @@ import (
 
 	"github.com/docker/compose-cli/utils"
 
+	cliopts "github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/pkg/namesgenerator"
 	"github.com/docker/go-connections/nat"
 
@@ -39,9 +41,27 @@ type Opts struct {
 	Cpus                   float64
 	Memory                 formatter.MemBytes
 	Detach                 bool
+	SigProxy               bool
+	Interactive            bool
+	Tty                    bool
 	Environment            []string
 	RestartPolicyCondition string
 	DomainName             string
+	OverrideBudget         bool
+	Platform               string
+	Isolation              string
+	Runtime                string
+	StopSignal             string
+	StopTimeout            int
+	Ulimits                []string
+	Sysctls                []string
+	CapAdd                 []string
+	CapDrop                []string
+	ReadOnly               bool
+	Tmpfs                  []string
+	ShmSize                formatter.MemBytes
+	Devices                []string
+	GPUs                   cliopts.GpuOpts
 }
 
 // ToContainerConfig convert run options to a container configuration
@@ -65,6 +85,11 @@ func (r *Opts) ToContainerConfig(image string) (containers.ContainerConfig, erro
 		return containers.ContainerConfig{}, err
 	}
 
+	sysctls, err := toSysctls(r.Sysctls)
+	if err != nil {
+		return containers.ContainerConfig{}, err
+	}
+
 	return containers.ContainerConfig{
 		ID:                     r.Name,
 		Image:                  image,
@@ -76,9 +101,34 @@ func (r *Opts) ToContainerConfig(image string) (containers.ContainerConfig, erro
 		Environment:            r.Environment,
 		RestartPolicyCondition: restartPolicy,
 		DomainName:             r.DomainName,
+		OverrideBudget:         r.OverrideBudget,
+		Interactive:            r.Interactive,
+		Platform:               r.Platform,
+		Isolation:              r.Isolation,
+		Runtime:                r.Runtime,
+		StopSignal:             r.StopSignal,
+		StopTimeout:            r.stopTimeout(),
+		Ulimits:                r.Ulimits,
+		Sysctls:                sysctls,
+		CapAdd:                 r.CapAdd,
+		CapDrop:                r.CapDrop,
+		ReadOnly:               r.ReadOnly,
+		Tmpfs:                  r.Tmpfs,
+		ShmSize:                r.ShmSize.Value(),
+		Devices:                r.Devices,
+		DeviceRequests:         toDeviceRequests(r.GPUs.Value()),
 	}, nil
 }
 
+// stopTimeout returns the stop timeout, unset to use the engine default unless positive
+func (r *Opts) stopTimeout() *int {
+	if r.StopTimeout <= 0 {
+		return nil
+	}
+	timeout := r.StopTimeout
+	return &timeout
+}
+
 func toRestartPolicy(value string) (string, error) {
 	if value == "" {
 		return containers.RestartPolicyNone, nil
@@ -135,6 +185,38 @@ func toLabels(labels []string) (map[string]string, error) {
 	return result, nil
 }
 
+func toSysctls(sysctls []string) (map[string]string, error) {
+	if len(sysctls) == 0 {
+		return nil, nil
+	}
+	result := map[string]string{}
+	for _, sysctl := range sysctls {
+		parts := strings.SplitN(sysctl, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("wrong sysctl format %q", sysctl)
+		}
+		result[parts[0]] = parts[1]
+	}
+	return result, nil
+}
+
+func toDeviceRequests(requests []container.DeviceRequest) []containers.DeviceRequest {
+	var result []containers.DeviceRequest
+	for _, r := range requests {
+		var capabilities []string
+		if len(r.Capabilities) > 0 {
+			capabilities = r.Capabilities[0]
+		}
+		result = append(result, containers.DeviceRequest{
+			Driver:       r.Driver,
+			Count:        r.Count,
+			DeviceIDs:    r.DeviceIDs,
+			Capabilities: capabilities,
+		})
+	}
+	return result
+}
+
 func getRandomName() string {
 	// Azure supports hyphen but not underscore in names
 	return strings.Replace(namesgenerator.GetRandomName(0), "_", "-", -1)
@@ -174,6 +174,30 @@ func TestLabels(t *testing.T) {
 	}
 }
 
+func TestSysctls(t *testing.T) {
+	result, err := toSysctls([]string{"net.core.somaxconn=1024", "kernel.shmmax=68719476736"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, result, map[string]string{
+		"net.core.somaxconn": "1024",
+		"kernel.shmmax":      "68719476736",
+	})
+
+	_, err = toSysctls([]string{"net.core.somaxconn"})
+	assert.Error(t, err, `wrong sysctl format "net.core.somaxconn"`)
+}
+
+func TestGPUs(t *testing.T) {
+	opts := Opts{}
+	assert.NilError(t, opts.GPUs.Set("all"))
+	assert.NilError(t, opts.GPUs.Set(`"device=0,1",capabilities=compute`))
+	config, err := opts.ToContainerConfig("pytorch")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, config.DeviceRequests, []containers.DeviceRequest{
+		{Count: -1, Capabilities: []string{"gpu"}},
+		{DeviceIDs: []string{"0", "1"}, Capabilities: []string{"compute", "gpu"}},
+	})
+}
+
 func TestValidateRestartPolicy(t *testing.T) {
 	testCases := []struct {
 		in            string
@@ -0,0 +1,239 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sanathkr/go-yaml"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const (
+	// CLIConfigFileName is the name of the file, in the config directory, holding the user defaults of the CLI
+	CLIConfigFileName = "compose-cli.yaml"
+	// TelemetryEnvVar is the environment variable enabling or disabling the usage metrics sent to Docker Desktop
+	TelemetryEnvVar = "COMPOSE_CLI_TELEMETRY"
+)
+
+// CLIConfig holds the user defaults of the CLI. Environment variables set for the same settings take precedence.
+type CLIConfig struct {
+	// Progress is the progress display: "auto", "tty" or "plain"
+	Progress string `yaml:"progress,omitempty"`
+	// Theme is the color theme: "auto", "dark" or "light"
+	Theme string `yaml:"theme,omitempty"`
+	// Parallelism is the number of pre-deploy steps run concurrently
+	Parallelism int `yaml:"parallelism,omitempty"`
+	// Telemetry enables the usage metrics, when set
+	Telemetry *bool `yaml:"telemetry,omitempty"`
+	// Defaults are flags added to commands, by context type then command path such as "compose up"
+	Defaults map[string]map[string][]string `yaml:"defaults,omitempty"`
+	// Aliases are commands with their arguments, run in place of the alias name
+	Aliases map[string]string `yaml:"aliases,omitempty"`
+}
+
+// LoadCLIConfig loads the user defaults of the CLI, an empty configuration being returned when there are none
+func LoadCLIConfig(dir string) (*CLIConfig, error) {
+	c := &CLIConfig{}
+	path := filepath.Join(dir, CLIConfigFileName)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return nil, errors.Wrap(err, "unable to read CLI config file")
+	}
+	if err := yaml.UnmarshalStrict(data, c); err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal CLI config file "+path)
+	}
+	return c, nil
+}
+
+// WriteCLIConfig writes the user defaults of the CLI
+func WriteCLIConfig(dir string, c *CLIConfig) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal CLI config")
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, CLIConfigFileName), data, 0644)
+	return errors.Wrap(err, "unable to write CLI config file")
+}
+
+// TelemetryEnabled returns false when the usage metrics are disabled
+func TelemetryEnabled() bool {
+	enabled, err := strconv.ParseBool(os.Getenv(TelemetryEnvVar))
+	return err != nil || enabled
+}
+
+// DefaultFlags returns the flags added to a command run with a context type
+func (c *CLIConfig) DefaultFlags(contextType string, command string) []string {
+	return c.Defaults[contextType][command]
+}
+
+// Get returns the value of a setting: "progress", "theme", "parallelism", "telemetry", "aliases.NAME" or
+// "defaults.CONTEXT_TYPE.COMMAND", flags being separated by spaces
+func (c *CLIConfig) Get(key string) (string, error) {
+	switch {
+	case key == "progress":
+		return c.Progress, nil
+	case key == "theme":
+		return c.Theme, nil
+	case key == "parallelism":
+		if c.Parallelism == 0 {
+			return "", nil
+		}
+		return strconv.Itoa(c.Parallelism), nil
+	case key == "telemetry":
+		if c.Telemetry == nil {
+			return "", nil
+		}
+		return strconv.FormatBool(*c.Telemetry), nil
+	case strings.HasPrefix(key, "aliases."):
+		return c.Aliases[strings.TrimPrefix(key, "aliases.")], nil
+	case strings.HasPrefix(key, "defaults."):
+		contextType, command, err := defaultsKey(key)
+		if err != nil {
+			return "", err
+		}
+		return strings.Join(c.DefaultFlags(contextType, command), " "), nil
+	}
+	return "", unknownKey(key)
+}
+
+// Set changes the value of a setting, an empty value removing it
+func (c *CLIConfig) Set(key string, value string) error {
+	switch {
+	case key == "progress":
+		switch value {
+		case "", "auto", "tty", "plain":
+			c.Progress = value
+			return nil
+		}
+		return errors.Wrapf(errdefs.ErrParsingFailed, "invalid progress %q, must be one of auto, tty or plain", value)
+	case key == "theme":
+		switch value {
+		case "", "auto", "dark", "light":
+			c.Theme = value
+			return nil
+		}
+		return errors.Wrapf(errdefs.ErrParsingFailed, "invalid theme %q, must be one of auto, dark or light", value)
+	case key == "parallelism":
+		if value == "" {
+			c.Parallelism = 0
+			return nil
+		}
+		parallelism, err := strconv.Atoi(value)
+		if err != nil || parallelism <= 0 {
+			return errors.Wrapf(errdefs.ErrParsingFailed, "invalid parallelism %q, must be a positive number", value)
+		}
+		c.Parallelism = parallelism
+		return nil
+	case key == "telemetry":
+		if value == "" {
+			c.Telemetry = nil
+			return nil
+		}
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.Wrapf(errdefs.ErrParsingFailed, "invalid telemetry %q, must be true or false", value)
+		}
+		c.Telemetry = &enabled
+		return nil
+	case strings.HasPrefix(key, "aliases."):
+		name := strings.TrimPrefix(key, "aliases.")
+		if name == "" || strings.ContainsAny(name, " \t") {
+			return errors.Wrapf(errdefs.ErrParsingFailed, "invalid alias name %q", name)
+		}
+		if value == "" {
+			delete(c.Aliases, name)
+			return nil
+		}
+		if c.Aliases == nil {
+			c.Aliases = map[string]string{}
+		}
+		c.Aliases[name] = value
+		return nil
+	case strings.HasPrefix(key, "defaults."):
+		contextType, command, err := defaultsKey(key)
+		if err != nil {
+			return err
+		}
+		if value == "" {
+			delete(c.Defaults[contextType], command)
+			if len(c.Defaults[contextType]) == 0 {
+				delete(c.Defaults, contextType)
+			}
+			return nil
+		}
+		if c.Defaults == nil {
+			c.Defaults = map[string]map[string][]string{}
+		}
+		if c.Defaults[contextType] == nil {
+			c.Defaults[contextType] = map[string][]string{}
+		}
+		c.Defaults[contextType][command] = strings.Fields(value)
+		return nil
+	}
+	return unknownKey(key)
+}
+
+// Keys lists the settings set, sorted
+func (c *CLIConfig) Keys() []string {
+	var keys []string
+	if c.Progress != "" {
+		keys = append(keys, "progress")
+	}
+	if c.Theme != "" {
+		keys = append(keys, "theme")
+	}
+	if c.Parallelism != 0 {
+		keys = append(keys, "parallelism")
+	}
+	if c.Telemetry != nil {
+		keys = append(keys, "telemetry")
+	}
+	for name := range c.Aliases {
+		keys = append(keys, "aliases."+name)
+	}
+	for contextType, commands := range c.Defaults {
+		for command := range commands {
+			keys = append(keys, fmt.Sprintf("defaults.%s.%s", contextType, command))
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func defaultsKey(key string) (string, string, error) {
+	parts := strings.SplitN(strings.TrimPrefix(key, "defaults."), ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.Wrapf(errdefs.ErrParsingFailed, "invalid key %q, must be defaults.CONTEXT_TYPE.COMMAND", key)
+	}
+	return parts[0], parts[1], nil
+}
+
+func unknownKey(key string) error {
+	return errors.Wrapf(errdefs.ErrNotFound, "unknown setting %q, must be progress, theme, parallelism, telemetry, aliases.NAME or defaults.CONTEXT_TYPE.COMMAND", key)
+}
@@ -0,0 +1,114 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestLoadCLIConfig(t *testing.T) {
+	d := testConfigDir(t)
+	c, err := LoadCLIConfig(d)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, c, &CLIConfig{})
+
+	err = ioutil.WriteFile(filepath.Join(d, CLIConfigFileName), []byte(`
+progress: plain
+parallelism: 4
+telemetry: false
+defaults:
+  aci:
+    compose up: [--wait]
+aliases:
+  deploy: compose up --wait
+`), 0644)
+	assert.NilError(t, err)
+	c, err = LoadCLIConfig(d)
+	assert.NilError(t, err)
+	assert.Equal(t, c.Progress, "plain")
+	assert.Equal(t, c.Parallelism, 4)
+	assert.Equal(t, *c.Telemetry, false)
+	assert.DeepEqual(t, c.DefaultFlags("aci", "compose up"), []string{"--wait"})
+	assert.Assert(t, c.DefaultFlags("ecs", "compose up") == nil)
+	assert.Equal(t, c.Aliases["deploy"], "compose up --wait")
+
+	err = ioutil.WriteFile(filepath.Join(d, CLIConfigFileName), []byte("paralelism: 4\n"), 0644)
+	assert.NilError(t, err)
+	_, err = LoadCLIConfig(d)
+	assert.ErrorContains(t, err, "unable to unmarshal CLI config file")
+}
+
+func TestCLIConfigSetGet(t *testing.T) {
+	d := testConfigDir(t)
+	c := &CLIConfig{}
+	assert.NilError(t, c.Set("progress", "tty"))
+	assert.NilError(t, c.Set("theme", "light"))
+	assert.NilError(t, c.Set("parallelism", "2"))
+	assert.NilError(t, c.Set("telemetry", "false"))
+	assert.NilError(t, c.Set("aliases.deploy", "compose up --wait"))
+	assert.NilError(t, c.Set("defaults.ecs.compose up", "--wait --wait-timeout 5m"))
+	assert.NilError(t, WriteCLIConfig(d, c))
+
+	loaded, err := LoadCLIConfig(d)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, loaded.Keys(), []string{"aliases.deploy", "defaults.ecs.compose up", "parallelism", "progress", "telemetry", "theme"})
+	for key, expected := range map[string]string{
+		"progress":                "tty",
+		"theme":                   "light",
+		"parallelism":             "2",
+		"telemetry":               "false",
+		"aliases.deploy":          "compose up --wait",
+		"defaults.ecs.compose up": "--wait --wait-timeout 5m",
+		"aliases.missing":         "",
+	} {
+		value, err := loaded.Get(key)
+		assert.NilError(t, err)
+		assert.Equal(t, value, expected, key)
+	}
+
+	assert.NilError(t, loaded.Set("defaults.ecs.compose up", ""))
+	assert.NilError(t, loaded.Set("telemetry", ""))
+	assert.DeepEqual(t, loaded.Keys(), []string{"aliases.deploy", "parallelism", "progress", "theme"})
+}
+
+func TestCLIConfigInvalidSettings(t *testing.T) {
+	c := &CLIConfig{}
+	assert.Assert(t, errdefs.IsErrParsingFailed(c.Set("progress", "fancy")))
+	assert.Assert(t, errdefs.IsErrParsingFailed(c.Set("theme", "solarized")))
+	assert.Assert(t, errdefs.IsErrParsingFailed(c.Set("parallelism", "0")))
+	assert.Assert(t, errdefs.IsErrParsingFailed(c.Set("telemetry", "maybe")))
+	assert.Assert(t, errdefs.IsErrParsingFailed(c.Set("defaults.aci", "--wait")))
+	assert.Assert(t, errdefs.IsNotFoundError(c.Set("colour", "auto")))
+	_, err := c.Get("colour")
+	assert.Assert(t, errdefs.IsNotFoundError(err))
+}
+
+func TestTelemetryEnabled(t *testing.T) {
+	defer os.Unsetenv(TelemetryEnvVar) // nolint:errcheck
+	assert.Assert(t, TelemetryEnabled())
+	os.Setenv(TelemetryEnvVar, "false") // nolint:errcheck
+	assert.Assert(t, !TelemetryEnabled())
+	os.Setenv(TelemetryEnvVar, "1") // nolint:errcheck
+	assert.Assert(t, TelemetryEnabled())
+}
@@ -0,0 +1,41 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+const (
+	// OfflineFlagName is the name of the global flag enabling offline mode
+	OfflineFlagName = "offline"
+	// OfflineEnvVar is the environment variable enabling offline mode
+	OfflineEnvVar = "DOCKER_OFFLINE"
+)
+
+// IsOffline returns true when the CLI runs in offline mode, so that network calls are not attempted.
+// Offline mode is set by environment so that it also applies to delegated commands.
+func IsOffline() bool {
+	offline, _ := strconv.ParseBool(os.Getenv(OfflineEnvVar))
+	return offline
+}
+
+// SetOffline enables or disables offline mode
+func SetOffline(offline bool) error {
+	return os.Setenv(OfflineEnvVar, strconv.FormatBool(offline))
+}
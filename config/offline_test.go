@@ -0,0 +1,43 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package config
+
+import (
+	"os"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSetOffline(t *testing.T) {
+	defer os.Unsetenv(OfflineEnvVar) // nolint:errcheck
+	assert.NilError(t, os.Unsetenv(OfflineEnvVar))
+	assert.Assert(t, !IsOffline())
+
+	assert.NilError(t, SetOffline(true))
+	assert.Assert(t, IsOffline())
+	assert.Equal(t, os.Getenv(OfflineEnvVar), "true")
+
+	assert.NilError(t, SetOffline(false))
+	assert.Assert(t, !IsOffline())
+
+	// delegated commands and users may set the variable in other forms
+	assert.NilError(t, os.Setenv(OfflineEnvVar, "1"))
+	assert.Assert(t, IsOffline())
+	assert.NilError(t, os.Setenv(OfflineEnvVar, "yes"))
+	assert.Assert(t, !IsOffline())
+}
@@ -0,0 +1,71 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package store
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const (
+	// DatadogAnnotation posts deployment events to Datadog, as datadog or datadog://SITE, with the DD_API_KEY API key
+	DatadogAnnotation = "datadog"
+	// GrafanaAnnotation posts deployment annotations to Grafana, as grafana://HOST[/PATH], with the GRAFANA_API_KEY token
+	GrafanaAnnotation = "grafana"
+	// CloudWatchAnnotation publishes a deployment metric to CloudWatch, on ECS contexts
+	CloudWatchAnnotation = "cloudwatch"
+)
+
+// DeploymentAnnotations sets the monitoring tools compose up annotates with the deployments of a context, so that
+// dashboards can correlate regressions with deployments
+type DeploymentAnnotations struct {
+	Annotations []string `json:",omitempty"`
+}
+
+// Validate checks annotation targets are datadog, grafana or, when allowed, cloudwatch ones
+func (a DeploymentAnnotations) Validate(allowCloudWatch bool) error {
+	for _, target := range a.Annotations {
+		kind, _, err := ParseAnnotation(target)
+		if err != nil {
+			return err
+		}
+		if kind == CloudWatchAnnotation && !allowCloudWatch {
+			return errors.Wrapf(errdefs.ErrParsingFailed, "annotation %q is only supported by ECS contexts", target)
+		}
+	}
+	return nil
+}
+
+// ParseAnnotation returns the kind of an annotation target and its host, empty for the default one
+func ParseAnnotation(target string) (string, string, error) {
+	if !strings.Contains(target, "://") {
+		switch target {
+		case DatadogAnnotation, CloudWatchAnnotation:
+			return target, "", nil
+		}
+		return "", "", errors.Wrapf(errdefs.ErrParsingFailed, "annotation %q, expected datadog[://SITE], grafana://HOST[/PATH] or cloudwatch", target)
+	}
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" || (u.Scheme != DatadogAnnotation && u.Scheme != GrafanaAnnotation) {
+		return "", "", errors.Wrapf(errdefs.ErrParsingFailed, "annotation %q, expected datadog[://SITE], grafana://HOST[/PATH] or cloudwatch", target)
+	}
+	return u.Scheme, u.Host + strings.TrimSuffix(u.Path, "/"), nil
+}
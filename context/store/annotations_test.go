@@ -0,0 +1,43 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestParseAnnotation(t *testing.T) {
+	kind, host, err := ParseAnnotation("datadog")
+	assert.NilError(t, err)
+	assert.Equal(t, kind, DatadogAnnotation)
+	assert.Equal(t, host, "")
+
+	kind, host, err = ParseAnnotation("grafana://monitoring.example.com/grafana/")
+	assert.NilError(t, err)
+	assert.Equal(t, kind, GrafanaAnnotation)
+	assert.Equal(t, host, "monitoring.example.com/grafana")
+
+	_, _, err = ParseAnnotation("https://grafana.example.com")
+	assert.Assert(t, errdefs.IsErrParsingFailed(err))
+
+	assert.NilError(t, DeploymentAnnotations{Annotations: []string{"cloudwatch", "datadog://datadoghq.eu"}}.Validate(true))
+	assert.ErrorContains(t, DeploymentAnnotations{Annotations: []string{"cloudwatch"}}.Validate(false), "only supported by ECS contexts")
+}
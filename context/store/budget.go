@@ -0,0 +1,62 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package store
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// Budget limits the resources an application can request on a context. Zero values are not limited.
+type Budget struct {
+	MaxCPUs        float64 `json:",omitempty"`
+	MaxMemoryGB    float64 `json:",omitempty"`
+	MaxMonthlyCost float64 `json:",omitempty"`
+}
+
+// IsSet returns true when at least one limit is configured
+func (b Budget) IsSet() bool {
+	return b.MaxCPUs > 0 || b.MaxMemoryGB > 0 || b.MaxMonthlyCost > 0
+}
+
+// Validate checks budget limits are not negative
+func (b Budget) Validate() error {
+	for name, value := range map[string]float64{
+		"max CPUs":         b.MaxCPUs,
+		"max memory":       b.MaxMemoryGB,
+		"max monthly cost": b.MaxMonthlyCost,
+	} {
+		if value < 0 {
+			return errors.Wrapf(errdefs.ErrParsingFailed, "invalid %s %g: must not be negative", name, value)
+		}
+	}
+	return nil
+}
+
+// Check returns a forbidden error when the requested resources exceed the budget
+func (b Budget) Check(cpus, memoryGB, monthlyCost float64) error {
+	switch {
+	case b.MaxCPUs > 0 && cpus > b.MaxCPUs:
+		return errors.Wrapf(errdefs.ErrForbidden, "requested %.2f CPUs exceed the context budget of %.2f CPUs", cpus, b.MaxCPUs)
+	case b.MaxMemoryGB > 0 && memoryGB > b.MaxMemoryGB:
+		return errors.Wrapf(errdefs.ErrForbidden, "requested %.2fGB of memory exceed the context budget of %.2fGB", memoryGB, b.MaxMemoryGB)
+	case b.MaxMonthlyCost > 0 && monthlyCost > b.MaxMonthlyCost:
+		return errors.Wrapf(errdefs.ErrForbidden, "estimated monthly cost of %.2f exceeds the context budget of %.2f", monthlyCost, b.MaxMonthlyCost)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestBudgetCheck(t *testing.T) {
+	budget := Budget{MaxCPUs: 2, MaxMemoryGB: 4, MaxMonthlyCost: 100}
+	assert.NilError(t, budget.Check(2, 4, 100))
+
+	err := budget.Check(2.5, 1, 10)
+	assert.Assert(t, errdefs.IsForbiddenError(err))
+	assert.ErrorContains(t, err, "requested 2.50 CPUs exceed the context budget of 2.00 CPUs")
+
+	err = budget.Check(1, 8, 10)
+	assert.ErrorContains(t, err, "requested 8.00GB of memory")
+
+	err = budget.Check(1, 1, 150)
+	assert.ErrorContains(t, err, "estimated monthly cost of 150.00 exceeds the context budget of 100.00")
+}
+
+func TestBudgetUnset(t *testing.T) {
+	budget := Budget{}
+	assert.Assert(t, !budget.IsSet())
+	assert.NilError(t, budget.Check(64, 256, 10000))
+}
+
+func TestBudgetValidate(t *testing.T) {
+	assert.NilError(t, Budget{MaxCPUs: 1}.Validate())
+	err := Budget{MaxMemoryGB: -1}.Validate()
+	assert.Assert(t, errdefs.IsErrParsingFailed(err))
+}
@@ -47,22 +47,58 @@ type AciContext struct {
 	SubscriptionID string `json:",omitempty"`
 	Location       string `json:",omitempty"`
 	ResourceGroup  string `json:",omitempty"`
+
+	ResolveImageDigests bool   `json:",omitempty"`
+	TracingEndpoint     string `json:",omitempty"`
+	// ScanSeverity makes compose up scan service images, and refuse to deploy them with vulnerabilities of this
+	// severity or above
+	ScanSeverity string `json:",omitempty"`
+	Budget
+	TagPolicy
+	DeploymentAnnotations
+	Notifications
+	AciPrivateNetwork
+	OperationSettings
 }
 
 // EcsContext is the context for the AWS backend
 type EcsContext struct {
 	Profile string `json:",omitempty"`
 	Region  string `json:",omitempty"`
+
+	ResolveImageDigests bool   `json:",omitempty"`
+	TracingEndpoint     string `json:",omitempty"`
+	// ScanSeverity makes compose up scan service images, and refuse to deploy them with vulnerabilities of this
+	// severity or above
+	ScanSeverity string `json:",omitempty"`
+	// External deploys services on the ECS Anywhere instances registered in the project cluster, rather than on Fargate
+	External bool `json:",omitempty"`
+	Budget
+	TagPolicy
+	DeploymentAnnotations
+	Notifications
+	OperationSettings
 }
 
 // AwsContext is the context for the ecs plugin
 type AwsContext EcsContext
 
-// LocalContext is the context for the local backend
-type LocalContext struct{}
+// LocalContext is the context for the local backend, connecting to the engine set by the environment unless Host is set
+type LocalContext struct {
+	Host string `json:",omitempty"`
+	// CAFile, CertFile and KeyFile are the paths of the TLS material used to connect to a remote engine
+	CAFile        string `json:",omitempty"`
+	CertFile      string `json:",omitempty"`
+	KeyFile       string `json:",omitempty"`
+	SkipTLSVerify bool   `json:",omitempty"`
+}
 
 // ExampleContext is the context for the example backend
-type ExampleContext struct{}
+type ExampleContext struct {
+	// Scenario is the path of the file describing the latencies, failures and states simulated by the backend, the
+	// backend returns fixed output when it's not set
+	Scenario string `json:",omitempty"`
+}
 
 // MarshalJSON implements custom JSON marshalling
 func (dc ContextMetadata) MarshalJSON() ([]byte, error) {
@@ -0,0 +1,62 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package store
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// AciPrivateNetwork deploys container groups without public IP, in a virtual network subnet
+type AciPrivateNetwork struct {
+	// SubnetID is the resource ID of the subnet container groups are deployed to, which is delegated to ACI if needed
+	SubnetID string `json:",omitempty"`
+	// PrivateDNSZone is a private DNS zone of the context resource group, where container groups get an A record
+	// named after the project
+	PrivateDNSZone string `json:",omitempty"`
+	// ApplicationGateway is an Application Gateway of the context resource group, whose backend pool named after the
+	// project is pointed to the container group, for ingress traffic
+	ApplicationGateway string `json:",omitempty"`
+}
+
+// Private returns true if container groups are deployed in a virtual network, without public IP
+func (n AciPrivateNetwork) Private() bool {
+	return n.SubnetID != ""
+}
+
+// Validate checks private DNS records and the Application Gateway are only set for private deployments
+func (n AciPrivateNetwork) Validate() error {
+	if !n.Private() && (n.PrivateDNSZone != "" || n.ApplicationGateway != "") {
+		return errors.Wrap(errdefs.ErrParsingFailed, "a private DNS zone or an Application Gateway requires a subnet")
+	}
+	if n.Private() && len(strings.Split(strings.Trim(n.SubnetID, "/"), "/")) != 10 {
+		return errors.Wrapf(errdefs.ErrParsingFailed, "invalid subnet resource ID %q, expected /subscriptions/ID/resourceGroups/GROUP/providers/Microsoft.Network/virtualNetworks/VNET/subnets/SUBNET", n.SubnetID)
+	}
+	return nil
+}
+
+// SubnetParts returns the subscription, resource group, virtual network and name of the subnet
+func (n AciPrivateNetwork) SubnetParts() (string, string, string, string) {
+	parts := strings.Split(strings.Trim(n.SubnetID, "/"), "/")
+	if len(parts) != 10 {
+		return "", "", "", ""
+	}
+	return parts[1], parts[3], parts[7], parts[9]
+}
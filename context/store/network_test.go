@@ -0,0 +1,38 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestAciPrivateNetwork(t *testing.T) {
+	network := AciPrivateNetwork{SubnetID: "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/aci"}
+	assert.NilError(t, network.Validate())
+	assert.Assert(t, network.Private())
+	subscription, group, vnet, subnet := network.SubnetParts()
+	assert.Equal(t, subscription+" "+group+" "+vnet+" "+subnet, "sub rg vnet aci")
+
+	err := AciPrivateNetwork{SubnetID: "aci"}.Validate()
+	assert.Assert(t, errdefs.IsErrParsingFailed(err))
+	err = AciPrivateNetwork{PrivateDNSZone: "internal.example.com"}.Validate()
+	assert.Assert(t, errdefs.IsErrParsingFailed(err))
+}
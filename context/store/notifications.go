@@ -0,0 +1,91 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package store
+
+import (
+	"net/url"
+
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const (
+	// UpSucceededEvent is fired when compose up deployed the project
+	UpSucceededEvent = "up.success"
+	// UpFailedEvent is fired when compose up failed to deploy the project
+	UpFailedEvent = "up.failure"
+	// DownEvent is fired when compose down removed the project
+	DownEvent = "down"
+)
+
+// NotificationHook is fired on the lifecycle events of projects, set on a context or by a project
+type NotificationHook struct {
+	// Slack is the URL of a Slack incoming webhook
+	Slack string `json:",omitempty"`
+	// URL receives events as JSON POST requests
+	URL string `json:",omitempty"`
+	// Command is run by the local shell, with the event in the COMPOSE_EVENT, COMPOSE_PROJECT, COMPOSE_CONTEXT and COMPOSE_ERROR
+	// variables
+	Command string `json:",omitempty"`
+	// Events restricts the hook to these events, all of them by default
+	Events []string `json:",omitempty"`
+}
+
+// Notifications are the hooks fired on the lifecycle events of the projects of a context
+type Notifications struct {
+	NotificationHooks []NotificationHook `json:",omitempty"`
+}
+
+// Validate checks the hook has a single valid target and only lists known events
+func (h NotificationHook) Validate() error {
+	targets := 0
+	for _, u := range []string{h.Slack, h.URL} {
+		if u == "" {
+			continue
+		}
+		targets++
+		parsed, err := url.Parse(u)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return errors.Wrapf(errdefs.ErrParsingFailed, "invalid notification URL %q: must be an absolute http(s) URL", u)
+		}
+	}
+	if h.Command != "" {
+		targets++
+	}
+	if targets != 1 {
+		return errors.Wrap(errdefs.ErrParsingFailed, "a notification hook must set one of slack, url or command")
+	}
+	for _, e := range h.Events {
+		switch e {
+		case UpSucceededEvent, UpFailedEvent, DownEvent:
+		default:
+			return errors.Wrapf(errdefs.ErrParsingFailed, "unknown notification event %q, expected %s, %s or %s", e, UpSucceededEvent, UpFailedEvent, DownEvent)
+		}
+	}
+	return nil
+}
+
+// Validate checks all hooks
+func (n Notifications) Validate() error {
+	for _, h := range n.NotificationHooks {
+		if err := h.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package store
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// OperationSettings configures timeouts, polling and retries of cloud API calls for a context.
+// Durations are expressed as Go durations ("30s", "1m"), unset values fall back to the backend defaults.
+type OperationSettings struct {
+	Timeout         string `json:",omitempty"`
+	PollingInterval string `json:",omitempty"`
+	MaxRetries      *int   `json:",omitempty"`
+	RetryBackoff    string `json:",omitempty"`
+}
+
+// Operations are the effective settings for cloud API calls
+type Operations struct {
+	// Timeout is the maximum duration of a single API call
+	Timeout time.Duration
+	// PollingInterval is the delay between two status checks of a long running operation
+	PollingInterval time.Duration
+	// MaxRetries is the number of times a failed API call is retried
+	MaxRetries int
+	// RetryBackoff is the base delay before retrying a failed API call, doubled on each attempt
+	RetryBackoff time.Duration
+}
+
+const maxRetryBackoff = 30 * time.Second
+
+var (
+	// AciDefaultOperations are the operation settings used by ACI contexts
+	AciDefaultOperations = Operations{
+		Timeout:         time.Minute,
+		PollingInterval: 2 * time.Second,
+		MaxRetries:      30,
+		RetryBackoff:    time.Second,
+	}
+	// EcsDefaultOperations are the operation settings used by ECS contexts
+	EcsDefaultOperations = Operations{
+		Timeout:         time.Minute,
+		PollingInterval: time.Second,
+		MaxRetries:      3,
+		RetryBackoff:    30 * time.Millisecond,
+	}
+)
+
+// Validate checks operation settings can be parsed
+func (s OperationSettings) Validate() error {
+	for name, value := range map[string]string{
+		"timeout":          s.Timeout,
+		"polling interval": s.PollingInterval,
+		"retry backoff":    s.RetryBackoff,
+	} {
+		if value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return errors.Wrapf(errdefs.ErrParsingFailed, "invalid %s %q: %s", name, value, err)
+		}
+		if d <= 0 {
+			return errors.Wrapf(errdefs.ErrParsingFailed, "invalid %s %q: must be positive", name, value)
+		}
+	}
+	if s.MaxRetries != nil && *s.MaxRetries < 0 {
+		return errors.Wrapf(errdefs.ErrParsingFailed, "invalid max retries %d: must not be negative", *s.MaxRetries)
+	}
+	return nil
+}
+
+// Resolve returns the effective operation settings, using defaults for unset or invalid values
+func (s OperationSettings) Resolve(defaults Operations) Operations {
+	ops := defaults
+	if d, err := time.ParseDuration(s.Timeout); err == nil && d > 0 {
+		ops.Timeout = d
+	}
+	if d, err := time.ParseDuration(s.PollingInterval); err == nil && d > 0 {
+		ops.PollingInterval = d
+	}
+	if d, err := time.ParseDuration(s.RetryBackoff); err == nil && d > 0 {
+		ops.RetryBackoff = d
+	}
+	if s.MaxRetries != nil && *s.MaxRetries >= 0 {
+		ops.MaxRetries = *s.MaxRetries
+	}
+	return ops
+}
+
+// Settings returns operation settings with all values set
+func (o Operations) Settings() OperationSettings {
+	maxRetries := o.MaxRetries
+	return OperationSettings{
+		Timeout:         o.Timeout.String(),
+		PollingInterval: o.PollingInterval.String(),
+		MaxRetries:      &maxRetries,
+		RetryBackoff:    o.RetryBackoff.String(),
+	}
+}
+
+// Backoff returns the delay before retry attempt, growing exponentially with a random jitter
+// so that concurrent clients don't retry in lockstep
+func (o Operations) Backoff(attempt int) time.Duration {
+	delay := o.RetryBackoff
+	for i := 0; i < attempt && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryBackoff {
+		delay = maxRetryBackoff
+	}
+	// equal jitter: half of the delay, plus a random part of the other half, in [delay/2, delay)
+	half := int64(delay / 2)
+	if half <= 0 {
+		return delay
+	}
+	return time.Duration(half + rand.Int63n(half))
+}
+
+// Operations returns the effective operation settings of the ACI context
+func (c AciContext) Operations() Operations {
+	return c.OperationSettings.Resolve(AciDefaultOperations)
+}
+
+// Operations returns the effective operation settings of the ECS context
+func (c EcsContext) Operations() Operations {
+	return c.OperationSettings.Resolve(EcsDefaultOperations)
+}
@@ -0,0 +1,63 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestResolveOperations(t *testing.T) {
+	zero := 0
+	ops := OperationSettings{
+		PollingInterval: "5s",
+		MaxRetries:      &zero,
+	}.Resolve(AciDefaultOperations)
+	assert.DeepEqual(t, ops, Operations{
+		Timeout:         AciDefaultOperations.Timeout,
+		PollingInterval: 5 * time.Second,
+		MaxRetries:      0,
+		RetryBackoff:    AciDefaultOperations.RetryBackoff,
+	})
+
+	assert.DeepEqual(t, EcsContext{}.Operations(), EcsDefaultOperations)
+}
+
+func TestValidateOperationSettings(t *testing.T) {
+	assert.NilError(t, OperationSettings{Timeout: "2m", RetryBackoff: "100ms"}.Validate())
+
+	err := OperationSettings{Timeout: "two minutes"}.Validate()
+	assert.Assert(t, errdefs.IsErrParsingFailed(err))
+
+	negative := -1
+	err = OperationSettings{MaxRetries: &negative}.Validate()
+	assert.Assert(t, errdefs.IsErrParsingFailed(err))
+}
+
+func TestBackoff(t *testing.T) {
+	ops := Operations{RetryBackoff: time.Second}
+	for attempt, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		delay := ops.Backoff(attempt)
+		assert.Assert(t, delay >= expected/2 && delay < expected, "attempt %d: %s", attempt, delay)
+	}
+	delay := ops.Backoff(20)
+	assert.Assert(t, delay <= maxRetryBackoff)
+}
@@ -0,0 +1,125 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// syncS3 copies the context metadata files under prefix in bucket to dir, replacing its previous content once they
+// have all been downloaded
+func syncS3(ctx context.Context, bucket string, prefix string, dir string) error {
+	sess, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return err
+	}
+	region, err := s3manager.GetBucketRegion(ctx, sess, bucket, "us-east-1")
+	if err != nil {
+		return err
+	}
+	client := s3.New(sess, aws.NewConfig().WithRegion(region))
+	downloader := s3manager.NewDownloaderWithClient(client)
+
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempDir(filepath.Dir(dir), filepath.Base(dir)+".new")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp) // nolint:errcheck
+
+	metaPrefix := strings.TrimPrefix(prefix+"/"+contextsDir+"/"+metadataDir+"/", "/")
+	var downloadErr error
+	err = client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(metaPrefix),
+	}, func(page *s3.ListObjectsV2Output, last bool) bool {
+		for _, object := range page.Contents {
+			key := aws.StringValue(object.Key)
+			if !strings.HasSuffix(key, "/"+metaFile) {
+				continue
+			}
+			target, err := downloadTarget(tmp, strings.TrimPrefix(key, metaPrefix))
+			if err != nil {
+				downloadErr = err
+				return false
+			}
+			if downloadErr = download(ctx, downloader, bucket, key, target); downloadErr != nil {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if downloadErr != nil {
+		return downloadErr
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dir)
+}
+
+// downloadTarget returns the path a context metadata file is downloaded to, rejecting object keys which would be
+// written outside of the download directory
+func downloadTarget(dir string, key string) (string, error) {
+	for _, segment := range strings.Split(key, "/") {
+		if segment == ".." {
+			return "", errors.Wrapf(errdefs.ErrForbidden, "invalid shared context object key %q", key)
+		}
+	}
+	target := filepath.Join(dir, contextsDir, metadataDir, filepath.FromSlash(key))
+	if !strings.HasPrefix(target, filepath.Clean(dir)+string(filepath.Separator)) {
+		return "", errors.Wrapf(errdefs.ErrForbidden, "invalid shared context object key %q", key)
+	}
+	return target, nil
+}
+
+func download(ctx context.Context, downloader *s3manager.Downloader, bucket string, key string, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	_, err = downloader.DownloadWithContext(ctx, f, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
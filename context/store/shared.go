@@ -0,0 +1,162 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const (
+	// SharedStoreEnvVar is the environment variable pointing at contexts distributed by a platform team, as a
+	// directory or an s3://bucket/prefix URL laid out as a docker configuration directory. Shared contexts are
+	// read-only and take precedence over the user contexts with the same name.
+	SharedStoreEnvVar = "DOCKER_CONTEXTS_STORE"
+
+	s3Scheme = "s3://"
+	// offlineEnvVar is config.OfflineEnvVar, the config package depending on the store
+	offlineEnvVar = "DOCKER_OFFLINE"
+	// sharedCacheRefresh is the age from which the local copy of contexts shared in S3 is refreshed
+	sharedCacheRefresh = 15 * time.Minute
+)
+
+// NewFromEnv returns the context store rooted at rootDir, with the shared contexts of SharedStoreEnvVar when set
+func NewFromEnv(ctx context.Context, rootDir string) (Store, error) {
+	s, err := New(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	location := os.Getenv(SharedStoreEnvVar)
+	if location == "" {
+		return s, nil
+	}
+	shared, err := sharedRoot(ctx, location, filepath.Join(rootDir, "shared-contexts"))
+	if errdefs.IsErrOffline(err) {
+		logrus.Warnf("shared contexts from %s are not available offline until they have been downloaded once", location)
+		return s, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot load shared contexts from %s", location)
+	}
+	return &sharedStore{Store: s, shared: &store{root: shared}}, nil
+}
+
+// sharedRoot returns the directory of the shared contexts, a local copy for contexts shared in S3
+func sharedRoot(ctx context.Context, location string, cacheDir string) (string, error) {
+	if !strings.HasPrefix(location, s3Scheme) {
+		return location, nil
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return "", errors.Wrapf(errdefs.ErrParsingFailed, "invalid %s %q", SharedStoreEnvVar, location)
+	}
+	sum := sha256.Sum256([]byte(location))
+	dir := filepath.Join(cacheDir, hex.EncodeToString(sum[:])[:16])
+	stat, err := os.Stat(dir)
+	if err == nil && time.Since(stat.ModTime()) < sharedCacheRefresh {
+		return dir, nil
+	}
+	if offline, _ := strconv.ParseBool(os.Getenv(offlineEnvVar)); offline {
+		if err != nil {
+			return "", errors.Wrap(errdefs.ErrOffline, "cannot download shared contexts")
+		}
+		return dir, nil
+	}
+	if err := syncS3(ctx, u.Host, strings.Trim(u.Path, "/"), dir); err != nil {
+		if _, statErr := os.Stat(dir); statErr != nil {
+			return "", err
+		}
+		logrus.Warnf("cannot refresh shared contexts, using the local copy: %v", err)
+	}
+	return dir, nil
+}
+
+// sharedStore overlays read-only shared contexts over the user store
+type sharedStore struct {
+	Store
+	shared *store
+}
+
+func (s *sharedStore) isShared(name string) bool {
+	return name != DefaultContextName && s.shared.ContextExists(name)
+}
+
+func (s *sharedStore) Get(name string) (*DockerContext, error) {
+	if s.isShared(name) {
+		return s.shared.Get(name)
+	}
+	return s.Store.Get(name)
+}
+
+func (s *sharedStore) GetEndpoint(name string, v interface{}) error {
+	if s.isShared(name) {
+		return s.shared.GetEndpoint(name, v)
+	}
+	return s.Store.GetEndpoint(name, v)
+}
+
+func (s *sharedStore) Create(name string, contextType string, description string, data interface{}) error {
+	if s.isShared(name) {
+		return errors.Wrapf(errdefs.ErrAlreadyExists, "shared %s", objectName(name))
+	}
+	return s.Store.Create(name, contextType, description, data)
+}
+
+func (s *sharedStore) List() ([]*DockerContext, error) {
+	shared, err := s.shared.stored()
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	names := map[string]bool{}
+	for _, c := range shared {
+		names[c.Name] = true
+	}
+	contexts, err := s.Store.List()
+	if err != nil {
+		return nil, err
+	}
+	result := shared
+	for _, c := range contexts {
+		if !names[c.Name] {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+func (s *sharedStore) Remove(name string) error {
+	if s.isShared(name) {
+		return errors.Wrapf(errdefs.ErrForbidden, "shared %s is read-only", objectName(name))
+	}
+	return s.Store.Remove(name)
+}
+
+func (s *sharedStore) ContextExists(name string) bool {
+	return s.isShared(name) || s.Store.ContextExists(name)
+}
@@ -0,0 +1,93 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestSharedStore(t *testing.T) {
+	sharedDir, err := ioutil.TempDir("", "shared")
+	assert.NilError(t, err)
+	t.Cleanup(func() {
+		_ = os.RemoveAll(sharedDir)
+	})
+	shared, err := New(sharedDir)
+	assert.NilError(t, err)
+	assert.NilError(t, shared.Create("prod", "ecs", "approved", EcsContext{Profile: "prod"}))
+
+	userDir, err := ioutil.TempDir("", "store")
+	assert.NilError(t, err)
+	t.Cleanup(func() {
+		_ = os.RemoveAll(userDir)
+	})
+	os.Setenv(SharedStoreEnvVar, sharedDir) // nolint:errcheck
+	defer os.Unsetenv(SharedStoreEnvVar)    // nolint:errcheck
+	s, err := NewFromEnv(context.Background(), userDir)
+	assert.NilError(t, err)
+
+	err = s.Create("prod", "ecs", "mine", EcsContext{Profile: "mine"})
+	assert.Assert(t, errdefs.IsAlreadyExistsError(err))
+	assert.NilError(t, s.Create("dev", "ecs", "mine", EcsContext{Profile: "dev"}))
+
+	c, err := s.Get("prod")
+	assert.NilError(t, err)
+	assert.Equal(t, c.Metadata.Description, "approved")
+	var endpoint EcsContext
+	assert.NilError(t, s.GetEndpoint("prod", &endpoint))
+	assert.Equal(t, endpoint.Profile, "prod")
+
+	err = s.Remove("prod")
+	assert.Assert(t, errdefs.IsForbiddenError(err))
+	assert.NilError(t, s.Remove("dev"))
+}
+
+func TestSharedRootOffline(t *testing.T) {
+	cache, err := ioutil.TempDir("", "shared")
+	assert.NilError(t, err)
+	defer os.RemoveAll(cache)        // nolint:errcheck
+	defer os.Unsetenv(offlineEnvVar) // nolint:errcheck
+	os.Setenv(offlineEnvVar, "true") // nolint:errcheck
+
+	_, err = sharedRoot(context.TODO(), "s3://contexts/platform", cache)
+	assert.Assert(t, errdefs.IsErrOffline(err))
+
+	defer os.Unsetenv(SharedStoreEnvVar)                   // nolint:errcheck
+	os.Setenv(SharedStoreEnvVar, "s3://contexts/platform") // nolint:errcheck
+	s, err := NewFromEnv(context.TODO(), cache)
+	assert.NilError(t, err)
+	_, shared := s.(*sharedStore)
+	assert.Assert(t, !shared)
+}
+
+func TestDownloadTarget(t *testing.T) {
+	target, err := downloadTarget("/tmp/shared", "0123/meta.json")
+	assert.NilError(t, err)
+	assert.Equal(t, target, "/tmp/shared/contexts/meta/0123/meta.json")
+
+	for _, key := range []string{"../../../../etc/meta.json", "0123/../../../../meta.json", "a/../../../../../meta.json"} {
+		_, err := downloadTarget("/tmp/shared", key)
+		assert.Assert(t, errdefs.IsForbiddenError(err), key)
+	}
+}
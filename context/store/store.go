@@ -256,6 +256,24 @@ func (s *store) Create(name string, contextType string, description string, data
 }
 
 func (s *store) List() ([]*DockerContext, error) {
+	result, err := s.stored()
+	if err != nil {
+		return nil, err
+	}
+
+	// The default context is not stored in the store, it is in-memory only
+	// so we need a special case for it.
+	dockerDefault, err := dockerDefaultContext()
+	if err != nil {
+		return nil, err
+	}
+
+	result = append(result, dockerDefault)
+	return result, nil
+}
+
+// stored returns the contexts saved in the store
+func (s *store) stored() ([]*DockerContext, error) {
 	root := filepath.Join(s.root, contextsDir, metadataDir)
 	c, err := ioutil.ReadDir(root)
 	if err != nil {
@@ -273,15 +291,6 @@ func (s *store) List() ([]*DockerContext, error) {
 			result = append(result, r)
 		}
 	}
-
-	// The default context is not stored in the store, it is in-memory only
-	// so we need a special case for it.
-	dockerDefault, err := dockerDefaultContext()
-	if err != nil {
-		return nil, err
-	}
-
-	result = append(result, dockerDefault)
 	return result, nil
 }
 
@@ -0,0 +1,75 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package store
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// TagPolicy sets the tags applied to every cloud resource created for a project on a context
+type TagPolicy struct {
+	// Tags are applied by default, labels set on the command line take precedence
+	Tags map[string]string `json:",omitempty"`
+	// RequiredTags are the tag keys a project must be deployed with
+	RequiredTags []string `json:",omitempty"`
+}
+
+// Validate checks tag keys are not empty
+func (p TagPolicy) Validate() error {
+	for key := range p.Tags {
+		if strings.TrimSpace(key) == "" {
+			return errors.Wrap(errdefs.ErrParsingFailed, "invalid tag: key must not be empty")
+		}
+	}
+	for _, key := range p.RequiredTags {
+		if strings.TrimSpace(key) == "" {
+			return errors.Wrap(errdefs.ErrParsingFailed, "invalid required tag: key must not be empty")
+		}
+	}
+	return nil
+}
+
+// Apply returns the given tags completed with the policy defaults, or a forbidden error when a required tag is
+// missing
+func (p TagPolicy) Apply(tags map[string]string) (map[string]string, error) {
+	if len(p.Tags) == 0 && len(p.RequiredTags) == 0 {
+		return tags, nil
+	}
+	result := map[string]string{}
+	for k, v := range p.Tags {
+		result[k] = v
+	}
+	for k, v := range tags {
+		result[k] = v
+	}
+	var missing []string
+	for _, key := range p.RequiredTags {
+		if result[key] == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, errors.Wrapf(errdefs.ErrForbidden, "missing required tags %s, set them with --label KEY=VALUE", strings.Join(missing, ", "))
+	}
+	return result, nil
+}
@@ -0,0 +1,48 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestTagPolicyApply(t *testing.T) {
+	policy := TagPolicy{
+		Tags:         map[string]string{"owner": "platform", "cost-center": "42"},
+		RequiredTags: []string{"owner", "git-sha"},
+	}
+	_, err := policy.Apply(nil)
+	assert.Assert(t, errdefs.IsForbiddenError(err))
+	assert.ErrorContains(t, err, "missing required tags git-sha")
+
+	tags, err := policy.Apply(map[string]string{"git-sha": "31a4989", "owner": "me"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, tags, map[string]string{"owner": "me", "cost-center": "42", "git-sha": "31a4989"})
+
+	tags, err = TagPolicy{}.Apply(map[string]string{"a": "b"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, tags, map[string]string{"a": "b"})
+}
+
+func TestTagPolicyValidate(t *testing.T) {
+	assert.NilError(t, TagPolicy{Tags: map[string]string{"owner": ""}}.Validate())
+	assert.Assert(t, errdefs.IsErrParsingFailed(TagPolicy{RequiredTags: []string{" "}}.Validate()))
+}
@@ -0,0 +1,269 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/output"
+	"github.com/docker/compose-cli/progress"
+)
+
+const (
+	// RefreshInterval is how often the status of the services is refreshed
+	RefreshInterval = 2 * time.Second
+	// logsSince is how far back logs are displayed when the dashboard opens
+	logsSince = 5 * time.Minute
+)
+
+// Terminal is where the dashboard is displayed, in raw mode, and keys are read from
+type Terminal struct {
+	In   io.Reader
+	Out  io.Writer
+	Size func() (width int, height int)
+}
+
+type dashboard struct {
+	service  compose.Service
+	project  *types.Project
+	terminal Terminal
+	mtx      sync.Mutex
+	model    *Model
+	changed  chan struct{}
+	// executed is called once an action completed
+	executed func(action Action, err error)
+	// refreshing is set while the status of the services is being fetched
+	refreshing bool
+}
+
+// Run displays the dashboard of a deployed project until the user quits or the context is done. It only relies on the
+// compose service API, so it works the same with all backends. executed, when set, is called once each restart or scale
+// action completed.
+func Run(ctx context.Context, service compose.Service, project *types.Project, terminal Terminal, executed func(action Action, err error)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	d := &dashboard{
+		service:  service,
+		project:  project,
+		terminal: terminal,
+		model:    NewModel(project.Name),
+		changed:  make(chan struct{}, 1),
+		executed: executed,
+	}
+
+	keys := make(chan string)
+	go readKeys(terminal.In, keys)
+	go d.followLogs(ctx)
+	d.refresh(ctx)
+
+	fmt.Fprint(terminal.Out, output.HideCursor())
+	defer fmt.Fprint(terminal.Out, output.ClearScreen()+output.ShowCursor())
+	ticker := time.NewTicker(RefreshInterval)
+	defer ticker.Stop()
+	for {
+		d.draw()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			d.refresh(ctx)
+		case <-d.changed:
+		case key, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			d.mtx.Lock()
+			action := d.model.Key(key)
+			d.mtx.Unlock()
+			if action.Kind == QuitAction {
+				return nil
+			}
+			d.run(ctx, action)
+		}
+	}
+}
+
+// update changes the model and redraws the dashboard
+func (d *dashboard) update(fn func(m *Model)) {
+	d.mtx.Lock()
+	fn(d.model)
+	d.mtx.Unlock()
+	select {
+	case d.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (d *dashboard) draw() {
+	width, height := d.terminal.Size()
+	d.mtx.Lock()
+	lines := d.model.Render(width, height)
+	d.mtx.Unlock()
+	fmt.Fprint(d.terminal.Out, output.ClearScreen()+strings.Join(lines, "\r\n"))
+}
+
+// refresh fetches the status of the services in the background, unless it's already being fetched
+func (d *dashboard) refresh(ctx context.Context) {
+	d.mtx.Lock()
+	if d.refreshing {
+		d.mtx.Unlock()
+		return
+	}
+	d.refreshing = true
+	d.mtx.Unlock()
+	go func() {
+		services, err := d.service.Ps(ctx, d.project.Name)
+		d.update(func(m *Model) {
+			d.refreshing = false
+			m.SetServices(services, err)
+		})
+	}()
+}
+
+func (d *dashboard) followLogs(ctx context.Context) {
+	err := d.service.Logs(ctx, d.project.Name, d, compose.LogOptions{Since: time.Now().Add(-logsSince)})
+	if err != nil && ctx.Err() == nil {
+		d.update(func(m *Model) {
+			m.AddEvent("logs unavailable: %s", err)
+		})
+	}
+}
+
+// Log implements compose.LogConsumer
+func (d *dashboard) Log(event compose.LogEvent) {
+	d.update(func(m *Model) {
+		m.AddLog(event)
+	})
+}
+
+// run executes an action in the background, its progress and warnings being reported as events
+func (d *dashboard) run(ctx context.Context, action Action) {
+	var (
+		description string
+		project     *types.Project
+		options     compose.UpOptions
+	)
+	switch action.Kind {
+	case RestartAction:
+		description = "restarting services"
+		project = d.project
+		options.Recreate = compose.RecreateForce
+	case ScaleAction:
+		description = fmt.Sprintf("scaling %s to %d", action.Service, action.Replicas)
+		project = scale(d.project, action.Service, action.Replicas)
+		options.Recreate = compose.RecreateDiverged
+	default:
+		return
+	}
+	d.update(func(m *Model) {
+		m.SetBusy(description)
+		m.AddEvent("%s", description)
+	})
+	go func() {
+		ctx, warnings := compose.WithDiagnostics(ctx)
+		ctx = progress.WithContextWriter(ctx, progress.NewEventWriter(progress.Creation, func(e progress.JSONEvent) {
+			d.update(func(m *Model) {
+				m.AddEvent("%s %s %s", e.ID, e.Type, e.Status)
+			})
+		}))
+		err := d.service.Up(ctx, project, options)
+		if d.executed != nil {
+			d.executed(action, err)
+		}
+		d.update(func(m *Model) {
+			m.SetBusy("")
+			for _, warning := range warnings() {
+				m.AddEvent("warning: %s", warning.Message)
+			}
+			if err != nil {
+				m.AddEvent("%s failed: %s", description, err)
+				return
+			}
+			m.AddEvent("%s done", description)
+			d.project = project
+		})
+		d.refresh(ctx)
+	}()
+}
+
+// scale returns a copy of the project with the replicas of a service changed
+func scale(project *types.Project, service string, replicas int) *types.Project {
+	scaled := *project
+	scaled.Services = append(types.Services{}, project.Services...)
+	for i, s := range scaled.Services {
+		if s.Name != service {
+			continue
+		}
+		deploy := types.DeployConfig{}
+		if s.Deploy != nil {
+			deploy = *s.Deploy
+		}
+		count := uint64(replicas)
+		deploy.Replicas = &count
+		scaled.Services[i].Deploy = &deploy
+	}
+	return &scaled
+}
+
+// readKeys sends the keys pressed by the user, closing the channel once the input is closed
+func readKeys(in io.Reader, keys chan<- string) {
+	defer close(keys)
+	buf := make([]byte, 16)
+	for {
+		n, err := in.Read(buf)
+		if n > 0 {
+			for _, key := range parseKeys(buf[:n]) {
+				keys <- key
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func parseKeys(b []byte) []string {
+	var keys []string
+	for len(b) > 0 {
+		switch {
+		case len(b) >= 3 && b[0] == 0x1b && b[1] == '[' && b[2] == 'A':
+			keys = append(keys, KeyUp)
+			b = b[3:]
+			continue
+		case len(b) >= 3 && b[0] == 0x1b && b[1] == '[' && b[2] == 'B':
+			keys = append(keys, KeyDown)
+			b = b[3:]
+			continue
+		case b[0] == 0x1b:
+			keys = append(keys, KeyEscape)
+		case b[0] == 0x03:
+			keys = append(keys, KeyCtrlC)
+		default:
+			keys = append(keys, string(b[0]))
+		}
+		b = b[1:]
+	}
+	return keys
+}
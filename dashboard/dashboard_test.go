@@ -0,0 +1,111 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package dashboard
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/poll"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+type fakeService struct {
+	compose.Service
+	up chan *types.Project
+}
+
+func (s *fakeService) Ps(ctx context.Context, projectName string) ([]compose.ServiceStatus, error) {
+	return []compose.ServiceStatus{{Name: "web", Replicas: 1, Desired: 1}}, nil
+}
+
+func (s *fakeService) Logs(ctx context.Context, projectName string, consumer compose.LogConsumer, options compose.LogOptions) error {
+	consumer.Log(compose.LogEvent{Service: "web", Line: "started"})
+	<-ctx.Done()
+	return nil
+}
+
+func (s *fakeService) Up(ctx context.Context, project *types.Project, options compose.UpOptions) error {
+	s.up <- project
+	return nil
+}
+
+type syncBuffer struct {
+	mtx sync.Mutex
+	bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.Buffer.Write(p)
+}
+
+func (b *syncBuffer) Bytes() []byte {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return append([]byte{}, b.Buffer.Bytes()...)
+}
+
+func TestRunScalesService(t *testing.T) {
+	in, keys := io.Pipe()
+	service := &fakeService{up: make(chan *types.Project)}
+	project := &types.Project{Name: "demo", Services: types.Services{{Name: "web"}}}
+	out := &syncBuffer{}
+	done := make(chan error)
+	executed := make(chan Action, 1)
+	go func() {
+		done <- Run(context.Background(), service, project, Terminal{
+			In:  in,
+			Out: out,
+			Size: func() (int, int) {
+				return 80, 24
+			},
+		}, func(action Action, err error) {
+			assert.Check(t, err)
+			executed <- action
+		})
+	}()
+
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		if bytes.Contains(out.Bytes(), []byte("1/1")) {
+			return poll.Success()
+		}
+		return poll.Continue("services not displayed")
+	})
+	_, err := keys.Write([]byte("+"))
+	assert.NilError(t, err)
+	scaled := <-service.up
+	assert.Equal(t, *scaled.Services[0].Deploy.Replicas, uint64(2))
+	assert.Assert(t, project.Services[0].Deploy == nil)
+	assert.DeepEqual(t, <-executed, Action{Kind: ScaleAction, Service: "web", Replicas: 2})
+
+	_, err = keys.Write([]byte("q"))
+	assert.NilError(t, err)
+	assert.NilError(t, <-done)
+	assert.Assert(t, bytes.Contains(out.Bytes(), []byte("web | started")))
+}
+
+func TestParseKeys(t *testing.T) {
+	assert.DeepEqual(t, parseKeys([]byte("\x1b[Aj\x1b[B\x03\x1bq")), []string{KeyUp, "j", KeyDown, KeyCtrlC, KeyEscape, "q"})
+}
@@ -0,0 +1,268 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package dashboard
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+const (
+	maxLogs   = 500
+	maxEvents = 50
+	// shownEvents is the number of recent events displayed
+	shownEvents = 5
+)
+
+// Keys read from the terminal, other than printable characters
+const (
+	KeyUp     = "up"
+	KeyDown   = "down"
+	KeyCtrlC  = "ctrl-c"
+	KeyEscape = "escape"
+)
+
+// ActionKind is an operation requested by the user on the project
+type ActionKind int
+
+const (
+	// NoAction means the key only changed the display, or was ignored
+	NoAction ActionKind = iota
+	// QuitAction closes the dashboard
+	QuitAction
+	// RestartAction redeploys the project services, recreating their containers
+	RestartAction
+	// ScaleAction changes the number of replicas of a service
+	ScaleAction
+)
+
+// Action is an operation requested by the user
+type Action struct {
+	Kind     ActionKind
+	Service  string
+	Replicas int
+}
+
+// Model is the state of the dashboard, rendered to the terminal on each change
+type Model struct {
+	project  string
+	services []compose.ServiceStatus
+	selected int
+	// tail is the service whose logs are displayed, all services when empty
+	tail   string
+	logs   []compose.LogEvent
+	events []string
+	// busy describes the action in progress, if any
+	busy string
+	err  error
+	now  func() time.Time
+}
+
+// NewModel returns the state of the dashboard of a project
+func NewModel(project string) *Model {
+	return &Model{project: project, now: time.Now}
+}
+
+// SetServices updates the status of the project services, or the error getting it
+func (m *Model) SetServices(services []compose.ServiceStatus, err error) {
+	m.err = err
+	if err != nil {
+		return
+	}
+	m.services = services
+	if m.selected >= len(services) {
+		m.selected = len(services) - 1
+	}
+	if m.selected < 0 {
+		m.selected = 0
+	}
+}
+
+// AddLog appends a log line of a service container, dropping the oldest ones
+func (m *Model) AddLog(event compose.LogEvent) {
+	m.logs = append(m.logs, event)
+	if len(m.logs) > maxLogs {
+		m.logs = m.logs[len(m.logs)-maxLogs:]
+	}
+}
+
+// AddEvent appends a line to the recent events
+func (m *Model) AddEvent(format string, args ...interface{}) {
+	m.events = append(m.events, m.now().Format("15:04:05")+" "+fmt.Sprintf(format, args...))
+	if len(m.events) > maxEvents {
+		m.events = m.events[len(m.events)-maxEvents:]
+	}
+}
+
+// SetBusy sets the description of the action in progress, empty once it completed
+func (m *Model) SetBusy(busy string) {
+	m.busy = busy
+}
+
+// Busy tells whether an action is in progress
+func (m *Model) Busy() bool {
+	return m.busy != ""
+}
+
+// Key updates the display for a key pressed by the user, and returns the action it requests
+func (m *Model) Key(key string) Action {
+	switch key {
+	case "q", KeyCtrlC:
+		return Action{Kind: QuitAction}
+	case "k", KeyUp:
+		if m.selected > 0 {
+			m.selected--
+		}
+	case "j", KeyDown:
+		if m.selected < len(m.services)-1 {
+			m.selected++
+		}
+	case "t":
+		service := m.selectedService()
+		if service == nil || m.tail == service.Name {
+			m.tail = ""
+		} else {
+			m.tail = service.Name
+		}
+	case KeyEscape:
+		m.tail = ""
+	case "r":
+		if !m.Busy() {
+			return Action{Kind: RestartAction}
+		}
+	case "+", "-":
+		service := m.selectedService()
+		if service == nil || m.Busy() {
+			return Action{}
+		}
+		replicas := service.Desired + 1
+		if key == "-" {
+			replicas = service.Desired - 1
+		}
+		if replicas >= 0 {
+			return Action{Kind: ScaleAction, Service: service.Name, Replicas: replicas}
+		}
+	}
+	return Action{}
+}
+
+func (m *Model) selectedService() *compose.ServiceStatus {
+	if m.selected < len(m.services) {
+		return &m.services[m.selected]
+	}
+	return nil
+}
+
+// Render returns the lines displaying the dashboard, fitting the terminal size
+func (m *Model) Render(width, height int) []string {
+	lines := []string{fmt.Sprintf("Project %s", m.project)}
+	if m.err != nil {
+		lines = append(lines, "Error: "+m.err.Error())
+	}
+	lines = append(lines, m.renderServices()...)
+
+	lines = append(lines, "", "EVENTS")
+	events := m.events
+	if len(events) > shownEvents {
+		events = events[len(events)-shownEvents:]
+	}
+	lines = append(lines, events...)
+
+	title := "LOGS"
+	if m.tail != "" {
+		title = fmt.Sprintf("LOGS (%s)", m.tail)
+	}
+	lines = append(lines, "", title)
+	footer := "[j/k] select  [t] tail  [r] restart  [+/-] scale  [q] quit"
+	if m.busy != "" {
+		footer += "  " + m.busy + "..."
+	}
+	available := height - len(lines) - 1
+	logs := m.tailedLogs()
+	if available < 0 {
+		available = 0
+	}
+	if len(logs) > available {
+		logs = logs[len(logs)-available:]
+	}
+	lines = append(lines, logs...)
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, footer)
+
+	for i, line := range lines {
+		lines[i] = truncate(line, width)
+	}
+	return lines
+}
+
+func (m *Model) renderServices() []string {
+	b := &bytes.Buffer{}
+	w := tabwriter.NewWriter(b, 0, 1, 3, ' ', 0)
+	fmt.Fprintln(w, "  SERVICE\tREPLICAS\tHEALTH\tRESTARTS\tPORTS")
+	for i, service := range m.services {
+		marker := " "
+		if i == m.selected {
+			marker = ">"
+		}
+		fmt.Fprintf(w, "%s %s\t%d/%d\t%s\t%d\t%s\n", marker, service.Name, service.Replicas, service.Desired, health(service),
+			service.Restarts, strings.Join(service.Ports, ", "))
+	}
+	_ = w.Flush()
+	return strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+}
+
+func (m *Model) tailedLogs() []string {
+	var lines []string
+	for _, event := range m.logs {
+		if m.tail != "" && event.Service != m.tail {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s | %s", event.Service, strings.TrimRight(event.Line, "\r\n")))
+	}
+	return lines
+}
+
+// health summarizes the status of the service replicas
+func health(service compose.ServiceStatus) string {
+	switch {
+	case service.ExitCode != nil && service.Replicas < service.Desired:
+		return fmt.Sprintf("exited (%d)", *service.ExitCode)
+	case service.Desired == 0:
+		return "stopped"
+	case service.Replicas >= service.Desired:
+		return "healthy"
+	case service.Replicas == 0:
+		return "starting"
+	default:
+		return "degraded"
+	}
+}
+
+func truncate(line string, width int) string {
+	runes := []rune(line)
+	if width <= 0 || len(runes) <= width {
+		return line
+	}
+	return string(runes[:width])
+}
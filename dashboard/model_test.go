@@ -0,0 +1,102 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package dashboard
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func testModel() *Model {
+	m := NewModel("demo")
+	m.now = func() time.Time {
+		return time.Date(2021, 3, 2, 10, 0, 0, 0, time.UTC)
+	}
+	exitCode := 137
+	m.SetServices([]compose.ServiceStatus{
+		{Name: "web", Replicas: 2, Desired: 2, Ports: []string{"0.0.0.0:80->80/tcp"}},
+		{Name: "worker", Replicas: 0, Desired: 1, Restarts: 3, ExitCode: &exitCode},
+	}, nil)
+	return m
+}
+
+func TestRender(t *testing.T) {
+	m := testModel()
+	m.AddEvent("web %s", "ResourceCreated")
+	m.AddLog(compose.LogEvent{Service: "web", Line: "GET /\n"})
+	m.AddLog(compose.LogEvent{Service: "worker", Line: "out of memory"})
+
+	lines := m.Render(80, 16)
+	assert.Equal(t, len(lines), 16)
+	assert.DeepEqual(t, lines[:10], []string{
+		"Project demo",
+		"  SERVICE   REPLICAS   HEALTH         RESTARTS   PORTS",
+		"> web       2/2        healthy        0          0.0.0.0:80->80/tcp",
+		"  worker    0/1        exited (137)   3          ",
+		"",
+		"EVENTS",
+		"10:00:00 web ResourceCreated",
+		"",
+		"LOGS",
+		"web | GET /",
+	})
+	assert.Equal(t, lines[10], "worker | out of memory")
+	assert.Assert(t, strings.HasPrefix(lines[15], "[j/k] select"))
+
+	assert.Equal(t, m.Key("j"), Action{})
+	assert.Equal(t, m.Key("t"), Action{})
+	lines = m.Render(20, 12)
+	assert.Equal(t, lines[3], "> worker    0/1     ")
+	assert.Equal(t, lines[8], "LOGS (worker)")
+	assert.Equal(t, lines[9], "worker | out of memo")
+}
+
+func TestRenderKeepsLatestLogs(t *testing.T) {
+	m := testModel()
+	for _, line := range []string{"one", "two", "three"} {
+		m.AddLog(compose.LogEvent{Service: "web", Line: line})
+	}
+	lines := m.Render(80, 11)
+	assert.DeepEqual(t, lines[8:], []string{"web | two", "web | three", lines[10]})
+}
+
+func TestKeys(t *testing.T) {
+	m := testModel()
+	assert.Equal(t, m.Key("q"), Action{Kind: QuitAction})
+	assert.Equal(t, m.Key(KeyCtrlC), Action{Kind: QuitAction})
+	assert.Equal(t, m.Key("r"), Action{Kind: RestartAction})
+	assert.Equal(t, m.Key("+"), Action{Kind: ScaleAction, Service: "web", Replicas: 3})
+	assert.Equal(t, m.Key(KeyDown), Action{})
+	assert.Equal(t, m.Key(KeyDown), Action{})
+	assert.Equal(t, m.Key("-"), Action{Kind: ScaleAction, Service: "worker", Replicas: 0})
+
+	m.SetBusy("restarting services")
+	assert.Equal(t, m.Key("r"), Action{})
+	assert.Equal(t, m.Key("+"), Action{})
+}
+
+func TestHealth(t *testing.T) {
+	assert.Equal(t, health(compose.ServiceStatus{Replicas: 1, Desired: 1}), "healthy")
+	assert.Equal(t, health(compose.ServiceStatus{Replicas: 1, Desired: 2}), "degraded")
+	assert.Equal(t, health(compose.ServiceStatus{Replicas: 0, Desired: 2}), "starting")
+	assert.Equal(t, health(compose.ServiceStatus{}), "stopped")
+}
@@ -0,0 +1,138 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/wafv2"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// anywhere is the CIDR exposed ports are open to when the project doesn't set x-aws-allowed_cidrs
+const anywhere = "0.0.0.0/0"
+
+// allowedCIDRs returns the CIDRs exposed ports are open to
+func allowedCIDRs(project *types.Project) ([]string, error) {
+	cidrs, err := parseCIDRsExtension(project, extensionAllowedCIDRs)
+	if err != nil || len(cidrs) == 0 {
+		return []string{anywhere}, err
+	}
+	return cidrs, nil
+}
+
+func parseCIDRsExtension(project *types.Project, extension string) ([]string, error) {
+	x, ok := project.Extensions[extension]
+	if !ok {
+		return nil, nil
+	}
+	values, ok := x.([]interface{})
+	if !ok {
+		return nil, errors.Wrapf(errdefs.ErrParsingFailed, "%s must be a list of CIDRs", extension)
+	}
+	cidrs := make([]string, len(values))
+	for i, value := range values {
+		cidr, ok := value.(string)
+		if !ok {
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "invalid %s CIDR: %v", extension, value)
+		}
+		ip, _, err := net.ParseCIDR(cidr)
+		if err != nil || ip.To4() == nil {
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "invalid %s CIDR %q, only IPv4 CIDRs are supported", extension, cidr)
+		}
+		cidrs[i] = cidr
+	}
+	return cidrs, nil
+}
+
+// createWebACL attaches the x-aws-waf WebACL to the load balancer, or creates one blocking the x-aws-denied_cidrs
+func (b *ecsAPIService) createWebACL(project *types.Project, template *cloudformation.Template, resources awsResources) error {
+	denied, err := parseCIDRsExtension(project, extensionDeniedCIDRs)
+	if err != nil {
+		return err
+	}
+	x, ok := project.Extensions[extensionWAF]
+	if !ok && len(denied) == 0 {
+		return nil
+	}
+	if resources.loadBalancer == "" {
+		logrus.Debug("Application does not expose any public port, so no WebACL is attached")
+		return nil
+	}
+	if resources.loadBalancerType != elbv2.LoadBalancerTypeEnumApplication {
+		return errors.Wrapf(errdefs.ErrNotImplemented, "%s and %s require an application load balancer", extensionWAF, extensionDeniedCIDRs)
+	}
+
+	var webACL string
+	if ok {
+		if len(denied) > 0 {
+			return errors.Wrapf(errdefs.ErrParsingFailed, "%s can't be set with an existing %s, add the denied CIDRs to the WebACL rules", extensionDeniedCIDRs, extensionWAF)
+		}
+		if webACL, ok = x.(string); !ok {
+			return errors.Wrapf(errdefs.ErrParsingFailed, "invalid %s: %v", extensionWAF, x)
+		}
+	} else {
+		template.Resources["DeniedIPSet"] = &wafv2.IPSet{
+			Addresses:        denied,
+			Description:      fmt.Sprintf("CIDRs denied access to Docker Compose project %s", project.Name),
+			IPAddressVersion: "IPV4",
+			Scope:            "REGIONAL",
+			Tags:             projectTags(project),
+		}
+		template.Resources["WebACL"] = &wafv2.WebACL{
+			DefaultAction: &wafv2.WebACL_DefaultAction{Allow: map[string]interface{}{}},
+			Description:   fmt.Sprintf("Docker Compose project %s access rules", project.Name),
+			Rules: []wafv2.WebACL_Rule{
+				{
+					Action:   &wafv2.WebACL_RuleAction{Block: map[string]interface{}{}},
+					Name:     "DeniedCIDRs",
+					Priority: 0,
+					Statement: &wafv2.WebACL_StatementOne{
+						IPSetReferenceStatement: &wafv2.WebACL_IPSetReferenceStatement{
+							Arn: cloudformation.GetAtt("DeniedIPSet", "Arn"),
+						},
+					},
+					VisibilityConfig: webACLVisibility(project.Name + "-denied"),
+				},
+			},
+			Scope:            "REGIONAL",
+			Tags:             projectTags(project),
+			VisibilityConfig: webACLVisibility(project.Name),
+		}
+		webACL = cloudformation.GetAtt("WebACL", "Arn")
+	}
+	template.Resources["LoadBalancerWebACLAssociation"] = &wafv2.WebACLAssociation{
+		ResourceArn: resources.loadBalancer,
+		WebACLArn:   webACL,
+	}
+	return nil
+}
+
+func webACLVisibility(metric string) *wafv2.WebACL_VisibilityConfig {
+	return &wafv2.WebACL_VisibilityConfig{
+		CloudWatchMetricsEnabled: true,
+		MetricName:               normalizeResourceName(metric),
+		SampledRequestsEnabled:   true,
+	}
+}
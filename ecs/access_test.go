@@ -0,0 +1,103 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/ec2"
+	"github.com/awslabs/goformation/v4/cloudformation/wafv2"
+	"gotest.tools/v3/assert"
+)
+
+func TestAllowedCIDRs(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  test:
+    image: nginx
+    ports:
+      - 80:80
+x-aws-allowed_cidrs:
+  - 10.0.0.0/8
+  - 192.168.1.0/24
+`)
+	ingress := template.Resources["Default80Ingress"].(*ec2.SecurityGroupIngress)
+	assert.Equal(t, ingress.CidrIp, "10.0.0.0/8")
+	ingress = template.Resources["Default80Ingress1"].(*ec2.SecurityGroupIngress)
+	assert.Equal(t, ingress.CidrIp, "192.168.1.0/24")
+}
+
+func TestDeniedCIDRs(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  test:
+    image: nginx
+    ports:
+      - 80:80
+x-aws-denied_cidrs:
+  - 203.0.113.0/24
+`)
+	ipSet := template.Resources["DeniedIPSet"].(*wafv2.IPSet)
+	assert.DeepEqual(t, ipSet.Addresses, []string{"203.0.113.0/24"})
+	webACL := template.Resources["WebACL"].(*wafv2.WebACL)
+	assert.Equal(t, webACL.Rules[0].Statement.IPSetReferenceStatement.Arn, cloudformation.GetAtt("DeniedIPSet", "Arn"))
+	association := template.Resources["LoadBalancerWebACLAssociation"].(*wafv2.WebACLAssociation)
+	assert.Equal(t, association.ResourceArn, cloudformation.Ref("LoadBalancer"))
+	assert.Equal(t, association.WebACLArn, cloudformation.GetAtt("WebACL", "Arn"))
+}
+
+func TestExistingWebACL(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  test:
+    image: nginx
+    ports:
+      - 80:80
+x-aws-waf: arn:aws:wafv2:eu-west-3:123456789012:regional/webacl/shop/abc
+`)
+	_, ok := template.Resources["WebACL"]
+	assert.Check(t, !ok)
+	association := template.Resources["LoadBalancerWebACLAssociation"].(*wafv2.WebACLAssociation)
+	assert.Equal(t, association.WebACLArn, "arn:aws:wafv2:eu-west-3:123456789012:regional/webacl/shop/abc")
+}
+
+func TestAccessFailures(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  test:
+    image: mysql
+    ports:
+      - 3306:3306
+x-aws-denied_cidrs:
+  - 203.0.113.0/24
+`)
+	_, err := (&ecsAPIService{}).convert(project, awsResources{})
+	assert.ErrorContains(t, err, "x-aws-waf and x-aws-denied_cidrs require an application load balancer")
+
+	project = loadConfig(t, `
+services:
+  test:
+    image: nginx
+    ports:
+      - 80:80
+x-aws-allowed_cidrs:
+  - 2001:db8::/32
+`)
+	_, err = (&ecsAPIService{}).convert(project, awsResources{})
+	assert.ErrorContains(t, err, `invalid x-aws-allowed_cidrs CIDR "2001:db8::/32", only IPv4 CIDRs are supported`)
+}
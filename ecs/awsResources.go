@@ -19,6 +19,8 @@ package ecs
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/awslabs/goformation/v4/cloudformation/ec2"
@@ -27,7 +29,11 @@ import (
 	"github.com/awslabs/goformation/v4/cloudformation"
 	"github.com/awslabs/goformation/v4/cloudformation/ecs"
 	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
 )
 
 // awsResources hold the AWS component being used or created to support services definition
@@ -38,6 +44,17 @@ type awsResources struct {
 	loadBalancer     string
 	loadBalancerType string
 	securityGroups   map[string]string
+	cloudMap         cloudMapConfig
+	// serviceExtraSecurityGroups are existing security groups attached to services by x-aws-security-group
+	serviceExtraSecurityGroups map[string][]string
+	// networkSubnets are the subnets networks set by x-aws-subnets place their services in
+	networkSubnets map[string][]string
+	// subnetZones are the availability zones of the subnets, looked up when services are pinned to zones
+	subnetZones map[string]string
+	// external runs services on ECS Anywhere instances, which don't use the VPC resources
+	external bool
+	// imageDigests are the digests service images resolve to, hashed with the service configuration
+	imageDigests compose.ImageDigests
 }
 
 func (r *awsResources) serviceSecurityGroups(service types.ServiceConfig) []string {
@@ -45,7 +62,17 @@ func (r *awsResources) serviceSecurityGroups(service types.ServiceConfig) []stri
 	for net := range service.Networks {
 		groups = append(groups, r.securityGroups[net])
 	}
-	return groups
+	return append(groups, r.serviceExtraSecurityGroups[service.Name]...)
+}
+
+// serviceSubnets returns the subnets a service runs in, the ones of its networks or the VPC ones
+func (r *awsResources) serviceSubnets(service types.ServiceConfig) []string {
+	for net := range service.Networks {
+		if subnets, ok := r.networkSubnets[net]; ok {
+			return subnets
+		}
+	}
+	return r.subnets
 }
 
 func (r *awsResources) allSecurityGroups() []string {
@@ -58,12 +85,20 @@ func (r *awsResources) allSecurityGroups() []string {
 
 // parse look into compose project for configured resource to use, and check they are valid
 func (b *ecsAPIService) parse(ctx context.Context, project *types.Project) (awsResources, error) {
-	r := awsResources{}
+	r := awsResources{external: b.ctx.External}
+	if r.external {
+		if err := checkExternalProject(project); err != nil {
+			return r, err
+		}
+	}
 	var err error
 	r.cluster, err = b.parseClusterExtension(ctx, project)
 	if err != nil {
 		return r, err
 	}
+	if r.external {
+		return r, nil
+	}
 	r.vpc, r.subnets, err = b.parseVPCExtension(ctx, project)
 	if err != nil {
 		return r, err
@@ -76,6 +111,22 @@ func (b *ecsAPIService) parse(ctx context.Context, project *types.Project) (awsR
 	if err != nil {
 		return r, err
 	}
+	r.cloudMap, err = b.parseCloudMapExtension(ctx, project)
+	if err != nil {
+		return r, err
+	}
+	r.serviceExtraSecurityGroups, err = b.parseServiceSecurityGroupExtension(ctx, project)
+	if err != nil {
+		return r, err
+	}
+	r.networkSubnets, err = parseNetworkSubnetsExtension(project, r.subnets)
+	if err != nil {
+		return r, err
+	}
+	r.subnetZones, err = b.parseSubnetZones(ctx, project, r)
+	if err != nil {
+		return r, err
+	}
 	return r, nil
 }
 
@@ -163,11 +214,118 @@ func (b *ecsAPIService) parseSecurityGroupExtension(ctx context.Context, project
 	return securityGroups, nil
 }
 
+func (b *ecsAPIService) parseServiceSecurityGroupExtension(ctx context.Context, project *types.Project) (map[string][]string, error) {
+	securityGroups := map[string][]string{}
+	for _, service := range project.Services {
+		groups, err := serviceSecurityGroupExtension(service)
+		if err != nil {
+			return nil, err
+		}
+		for _, sg := range groups {
+			exists, err := b.SDK.SecurityGroupExists(ctx, sg)
+			if err != nil {
+				return nil, err
+			}
+			if !exists {
+				return nil, fmt.Errorf("security group %s doesn't exist", sg)
+			}
+		}
+		if len(groups) > 0 {
+			securityGroups[service.Name] = groups
+		}
+	}
+	return securityGroups, nil
+}
+
+func serviceSecurityGroupExtension(service types.ServiceConfig) ([]string, error) {
+	x, ok := service.Extensions[extensionServiceSecurityGroup]
+	if !ok {
+		return nil, nil
+	}
+	switch v := x.(type) {
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		groups := make([]string, len(v))
+		for i, sg := range v {
+			if groups[i], ok = sg.(string); !ok {
+				return nil, errors.Wrapf(errdefs.ErrParsingFailed, "invalid %s for service %s: %v", extensionServiceSecurityGroup, service.Name, sg)
+			}
+		}
+		return groups, nil
+	}
+	return nil, errors.Wrapf(errdefs.ErrParsingFailed, "invalid %s for service %s: %v", extensionServiceSecurityGroup, service.Name, x)
+}
+
+// parseNetworkSubnetsExtension checks the subnets set by x-aws-subnets belong to the VPC, and services aren't attached to
+// networks placing them in different subnets, as an ECS task runs in a single set of subnets
+func parseNetworkSubnetsExtension(project *types.Project, vpcSubnets []string) (map[string][]string, error) {
+	inVPC := map[string]bool{}
+	for _, subnet := range vpcSubnets {
+		inVPC[subnet] = true
+	}
+	networkSubnets := map[string][]string{}
+	for name, net := range project.Networks {
+		x, ok := net.Extensions[extensionSubnets]
+		if !ok {
+			continue
+		}
+		values, ok := x.([]interface{})
+		if !ok || len(values) == 0 {
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "%s of network %s must be a list of subnets", extensionSubnets, name)
+		}
+		var subnets []string
+		for _, value := range values {
+			subnet, ok := value.(string)
+			if !ok {
+				return nil, errors.Wrapf(errdefs.ErrParsingFailed, "invalid %s of network %s: %v", extensionSubnets, name, value)
+			}
+			if !inVPC[subnet] {
+				return nil, errors.Wrapf(errdefs.ErrNotFound, "subnet %s of network %s doesn't belong to the VPC", subnet, name)
+			}
+			subnets = append(subnets, subnet)
+		}
+		sort.Strings(subnets)
+		networkSubnets[name] = subnets
+	}
+
+	for _, service := range project.Services {
+		var (
+			subnets []string
+			from    string
+		)
+		for _, net := range sortedNetworks(service) {
+			s, ok := networkSubnets[net]
+			if !ok {
+				continue
+			}
+			if subnets != nil && strings.Join(s, ",") != strings.Join(subnets, ",") {
+				return nil, errors.Wrapf(errdefs.ErrNotImplemented, "service %s can't be attached to networks %s and %s which use different subnets", service.Name, from, net)
+			}
+			subnets, from = s, net
+		}
+	}
+	return networkSubnets, nil
+}
+
+func sortedNetworks(service types.ServiceConfig) []string {
+	var networks []string
+	for net := range service.Networks {
+		networks = append(networks, net)
+	}
+	sort.Strings(networks)
+	return networks
+}
+
 // ensureResources create required resources in template if not yet defined
 func (b *ecsAPIService) ensureResources(resources *awsResources, project *types.Project, template *cloudformation.Template) {
 	b.ensureCluster(resources, project, template)
+	if resources.external {
+		return
+	}
 	b.ensureNetworks(resources, project, template)
 	b.ensureLoadBalancer(resources, project, template)
+	b.ensureCloudMap(resources, project, template)
 }
 
 func (b *ecsAPIService) ensureCluster(r *awsResources, project *types.Project, template *cloudformation.Template) {
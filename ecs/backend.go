@@ -18,19 +18,25 @@ package ecs
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/pkg/errors"
 
 	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/api/containers"
+	"github.com/docker/compose-cli/api/images"
 	"github.com/docker/compose-cli/api/secrets"
 	"github.com/docker/compose-cli/api/volumes"
 	"github.com/docker/compose-cli/backend"
+	"github.com/docker/compose-cli/config"
 	apicontext "github.com/docker/compose-cli/context"
 	"github.com/docker/compose-cli/context/cloud"
 	"github.com/docker/compose-cli/context/store"
 	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/recorder"
 )
 
 const backendType = store.EcsContextType
@@ -43,6 +49,16 @@ type ContextParams struct {
 
 	AwsID     string
 	AwsSecret string
+
+	ResolveImageDigests bool
+	TracingEndpoint     string
+	ScanSeverity        string
+	External            bool
+	Operations          store.OperationSettings
+	Budget              store.Budget
+	TagPolicy           store.TagPolicy
+	Annotations         store.DeploymentAnnotations
+	Notifications       store.Notifications
 }
 
 func init() {
@@ -62,18 +78,35 @@ func service(ctx context.Context) (backend.Service, error) {
 }
 
 func getEcsAPIService(ecsCtx store.EcsContext) (*ecsAPIService, error) {
+	return newEcsAPIService(ecsCtx, recorder.Transport(nil))
+}
+
+// newEcsAPIService creates the service sending AWS API requests with transport, nil for the default transport
+func newEcsAPIService(ecsCtx store.EcsContext, transport http.RoundTripper) (*ecsAPIService, error) {
+	// fail fast in offline mode rather than waiting for AWS calls to time out
+	if config.IsOffline() {
+		return nil, errors.Wrap(errdefs.ErrOffline, "cannot reach AWS")
+	}
+	ops := ecsCtx.Operations()
 	sess, err := session.NewSessionWithOptions(session.Options{
 		Profile:           ecsCtx.Profile,
 		SharedConfigState: session.SharedConfigEnable,
 		Config: aws.Config{
-			Region: aws.String(ecsCtx.Region),
+			Region:     aws.String(ecsCtx.Region),
+			HTTPClient: &http.Client{Timeout: ops.Timeout, Transport: transport},
+			// DefaultRetryer applies exponential backoff with jitter
+			Retryer: client.DefaultRetryer{
+				NumMaxRetries:    ops.MaxRetries,
+				MinRetryDelay:    ops.RetryBackoff,
+				MinThrottleDelay: ops.RetryBackoff,
+			},
 		},
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	sdk := newSDK(sess)
+	sdk := newSDK(sess, ops)
 	return &ecsAPIService{
 		ctx:    ecsCtx,
 		Region: ecsCtx.Region,
@@ -103,6 +136,10 @@ func (a *ecsAPIService) VolumeService() volumes.Service {
 	return nil
 }
 
+func (a *ecsAPIService) ImageService() images.Service {
+	return nil
+}
+
 func getCloudService() (cloud.Service, error) {
 	return ecsCloudService{}, nil
 }
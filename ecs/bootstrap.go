@@ -0,0 +1,83 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/docker/compose-cli/context/store"
+)
+
+// BootstrapDescription describes the IAM policy created by ApplyBootstrapPolicy
+const BootstrapDescription = "Least privileges for developers to deploy Compose applications to Amazon ECS"
+
+// BootstrapPolicy returns the IAM policy document granting the actions compose operations rely on, for
+// administrators to grant developers least-privilege access
+func BootstrapPolicy() ([]byte, error) {
+	return json.MarshalIndent(bootstrapPolicy{
+		Version: "2012-10-17",
+		Statement: []bootstrapStatement{
+			{
+				Effect:   "Allow",
+				Action:   bootstrapActions(),
+				Resource: []string{"*"},
+			},
+		},
+	}, "", "  ")
+}
+
+// ApplyBootstrapPolicy creates the bootstrap policy as a customer managed IAM policy and returns its ARN
+func ApplyBootstrapPolicy(ctx context.Context, ecsCtx store.EcsContext, name string) (string, error) {
+	document, err := BootstrapPolicy()
+	if err != nil {
+		return "", err
+	}
+	b, err := getEcsAPIService(ecsCtx)
+	if err != nil {
+		return "", err
+	}
+	return b.SDK.CreatePolicy(ctx, name, BootstrapDescription, document)
+}
+
+// bootstrapPolicy is an identity-based policy document, which must not declare a principal
+type bootstrapPolicy struct {
+	Version   string
+	Statement []bootstrapStatement
+}
+
+type bootstrapStatement struct {
+	Effect   string
+	Action   []string
+	Resource []string
+}
+
+func bootstrapActions() []string {
+	seen := map[string]bool{}
+	var actions []string
+	for _, perm := range permissions {
+		for _, action := range perm.actions {
+			if !seen[action] {
+				seen[action] = true
+				actions = append(actions, action)
+			}
+		}
+	}
+	sort.Strings(actions)
+	return actions
+}
@@ -0,0 +1,43 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestBootstrapPolicy(t *testing.T) {
+	document, err := BootstrapPolicy()
+	assert.NilError(t, err)
+	assert.Assert(t, !is.Contains(string(document), "Principal")().Success())
+
+	var policy bootstrapPolicy
+	assert.NilError(t, json.Unmarshal(document, &policy))
+	assert.Equal(t, len(policy.Statement), 1)
+	actions := policy.Statement[0].Action
+	assert.Assert(t, sort.StringsAreSorted(actions))
+	for _, perm := range permissions {
+		for _, action := range perm.actions {
+			assert.Assert(t, is.Contains(actions, action))
+		}
+	}
+}
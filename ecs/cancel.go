@@ -0,0 +1,77 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/progress"
+)
+
+// canceledDeployment handles a deployment canceled by the user while CloudFormation was applying it.
+// ctx must be detached from the canceled command context.
+func (b *ecsAPIService) canceledDeployment(ctx context.Context, name string, operation int, cleanup bool) error {
+	if !cleanup {
+		status, err := b.SDK.GetStackStatus(ctx, name)
+		if err != nil {
+			status = "unknown status"
+		}
+		return compose.CanceledError{
+			Operation: "deployment",
+			Resources: []string{fmt.Sprintf("CloudFormation stack %q (%s), run `docker compose up --resume` to resume its deployment "+
+				"or `docker compose down` to delete it", name, status)},
+		}
+	}
+
+	w := progress.ContextWriter(ctx)
+	if operation == stackCreate {
+		w.Event(progress.Event{ID: name, Status: progress.Working, StatusText: "Deleting canceled stack"})
+		if err := b.SDK.DeleteStack(ctx, name); err != nil {
+			return err
+		}
+		if err := b.WaitStackCompletion(ctx, name, stackDelete); err != nil {
+			return err
+		}
+	} else {
+		w.Event(progress.Event{ID: name, Status: progress.Working, StatusText: "Rolling back canceled update"})
+		if err := b.SDK.CancelUpdateStack(ctx, name); err != nil {
+			return err
+		}
+		if err := b.waitStackStable(ctx, name); err != nil {
+			return err
+		}
+	}
+	w.Event(progress.Event{ID: name, Status: progress.Done, StatusText: "Cleaned up"})
+	return compose.CanceledError{Operation: "deployment", CleanedUp: true}
+}
+
+// canceledDeletion describes the stack left behind when the user canceled compose down
+func (b *ecsAPIService) canceledDeletion(ctx context.Context, name string) error {
+	status, err := b.SDK.GetStackStatus(ctx, name)
+	if err != nil {
+		status = "unknown status"
+	}
+	if status == "" {
+		return compose.CanceledError{Operation: "deletion"}
+	}
+	return compose.CanceledError{
+		Operation: "deletion",
+		Resources: []string{fmt.Sprintf("CloudFormation stack %q (%s), deleted in the background", name, status)},
+	}
+}
@@ -35,18 +35,36 @@ import (
 	"github.com/awslabs/goformation/v4/cloudformation/secretsmanager"
 	cloudmap "github.com/awslabs/goformation/v4/cloudformation/servicediscovery"
 	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/api/containers"
+	"github.com/docker/compose-cli/errdefs"
 )
 
 func (b *ecsAPIService) Convert(ctx context.Context, project *types.Project) ([]byte, error) {
-	err := b.checkCompatibility(project)
+	template, err := b.convertToTemplate(ctx, project, nil)
+	if err != nil {
+		return nil, err
+	}
+	return marshall(template)
+}
+
+func (b *ecsAPIService) convertToTemplate(ctx context.Context, project *types.Project, digests compose.ImageDigests) (*cloudformation.Template, error) {
+	err := b.checkCompatibility(ctx, project)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := compose.ApplyEnvFrom(ctx, project, parameterStoreScheme, b.pullParameters); err != nil {
+		return nil, err
+	}
+
 	resources, err := b.parse(ctx, project)
 	if err != nil {
 		return nil, err
 	}
+	resources.imageDigests = digests
 
 	template, err := b.convert(project, resources)
 	if err != nil {
@@ -69,13 +87,28 @@ func (b *ecsAPIService) Convert(ctx context.Context, project *types.Project) ([]
 		return nil, err
 	}
 
-	return marshall(template)
+	return template, nil
 }
 
 // Convert a compose project into a CloudFormation template
 func (b *ecsAPIService) convert(project *types.Project, resources awsResources) (*cloudformation.Template, error) {
+	if resources.external {
+		if err := checkExternalProject(project); err != nil {
+			return nil, err
+		}
+	}
 	template := cloudformation.NewTemplate()
 	b.ensureResources(&resources, project, template)
+	if err := setLoadBalancerIdleTimeout(project, template); err != nil {
+		return nil, err
+	}
+	if err := b.createWebACL(project, template, resources); err != nil {
+		return nil, err
+	}
+	allowed, err := allowedCIDRs(project)
+	if err != nil {
+		return nil, err
+	}
 
 	for name, secret := range project.Secrets {
 		err := b.createSecret(project, name, secret, template)
@@ -84,16 +117,30 @@ func (b *ecsAPIService) convert(project *types.Project, resources awsResources)
 		}
 	}
 
+	for name, config := range project.Configs {
+		if err := b.createConfig(project, name, config, template); err != nil {
+			return nil, err
+		}
+	}
+
 	b.createLogGroup(project, template)
 
-	// Private DNS namespace will allow DNS name for the services to be <service>.<project>.local
-	b.createCloudMap(project, template, resources.vpc)
+	if err := checkMeshExtension(project, resources.cloudMap); err != nil {
+		return nil, err
+	}
+	if _, ok := project.Extensions[extensionMesh]; ok {
+		b.createMesh(project, template, resources.cloudMap)
+	}
 
 	for _, service := range project.Services {
+		hash, err := compose.ServiceHash(service, resources.imageDigests)
+		if err != nil {
+			return nil, err
+		}
 		taskExecutionRole := b.createTaskExecutionRole(project, service, template)
-		taskRole := b.createTaskRole(service, template)
+		taskRole := b.createTaskRole(project, service, template)
 
-		definition, err := b.createTaskExecution(project, service)
+		definition, err := b.createTaskExecution(project, service, resources)
 		if err != nil {
 			return nil, err
 		}
@@ -104,26 +151,79 @@ func (b *ecsAPIService) convert(project *types.Project, resources awsResources)
 
 		taskDefinition := fmt.Sprintf("%sTaskDefinition", normalizeResourceName(service.Name))
 		template.Resources[taskDefinition] = definition
+		schedule, err := compose.ServiceSchedule(service)
+		if err != nil {
+			return nil, err
+		}
+		if schedule != nil {
+			roles := []string{taskExecutionRole}
+			if taskRole != "" {
+				roles = append(roles, taskRole)
+			}
+			if err := b.createSchedule(service, *schedule, template, resources, taskDefinition, roles); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if compose.IsJob(service) {
+			createJobOutputs(service, template, resources, taskDefinition)
+			continue
+		}
+
+		var serviceRegistries []ecs.Service_ServiceRegistry
+		if !resources.cloudMap.serviceConnect && !resources.external {
+			var healthCheck *cloudmap.Service_HealthCheckConfig
+			serviceRegistries = append(serviceRegistries, b.createServiceRegistry(service, template, healthCheck, resources.cloudMap))
+		}
 
-		var healthCheck *cloudmap.Service_HealthCheckConfig
-		serviceRegistry := b.createServiceRegistry(service, template, healthCheck)
+		targetGroupOptions, err := parseTargetGroupExtension(service, resources.loadBalancerType)
+		if err != nil {
+			return nil, err
+		}
+		domain, err := parseDomainExtension(service)
+		if err != nil {
+			return nil, err
+		}
+		var certificate string
+		if domain != nil {
+			certificate, err = b.createDomain(project, service, *domain, template, resources)
+			if err != nil {
+				return nil, err
+			}
+		}
 
 		var (
 			dependsOn []string
 			serviceLB []ecs.Service_LoadBalancer
 		)
 		for _, port := range service.Ports {
+			if resources.external {
+				// ports are published on the ECS Anywhere instances, which aren't behind the load balancer
+				continue
+			}
 			for net := range service.Networks {
-				b.createIngress(service, net, port, template, resources)
+				// internal networks are not reachable from outside, services only communicate within the network
+				if project.Networks[net].Internal {
+					continue
+				}
+				b.createIngress(service, net, port, template, resources, allowed)
 			}
 
 			protocol := strings.ToUpper(port.Protocol)
 			if resources.loadBalancerType == elbv2.LoadBalancerTypeEnumApplication {
-				// we don't set Https as a certificate must be specified for HTTPS listeners
+				// listeners only use HTTPS when x-aws-domain sets a certificate, target groups always use HTTP
 				protocol = elbv2.ProtocolEnumHttp
 			}
+			if targetGroupOptions.httpsRedirect && port.Target == 80 {
+				dependsOn = append(dependsOn, b.createRedirectListener(service, port, template, resources.loadBalancer))
+				continue
+			}
 			targetGroupName := b.createTargetGroup(project, service, port, template, protocol, resources.vpc)
+			applyTargetGroupOptions(template.Resources[targetGroupName].(*elasticloadbalancingv2.TargetGroup), targetGroupOptions, resources.loadBalancerType)
 			listenerName := b.createListener(service, port, template, targetGroupName, resources.loadBalancer, protocol)
+			if certificate != "" && port.Target == 443 {
+				setListenerCertificate(template.Resources[listenerName].(*elasticloadbalancingv2.Listener), certificate)
+			}
 			dependsOn = append(dependsOn, listenerName)
 			serviceLB = append(serviceLB, ecs.Service_LoadBalancer{
 				ContainerName:  service.Name,
@@ -138,6 +238,10 @@ func (b *ecsAPIService) convert(project *types.Project, resources awsResources)
 		}
 
 		for dependency := range service.DependsOn {
+			if dep, err := project.GetService(dependency); err == nil && (compose.IsJob(dep) || compose.IsScheduled(dep)) {
+				// jobs and scheduled services have no ECS service, services waiting for jobs are held back by upWithJobs
+				continue
+			}
 			dependsOn = append(dependsOn, serviceResourceName(dependency))
 		}
 
@@ -145,6 +249,10 @@ func (b *ecsAPIService) convert(project *types.Project, resources awsResources)
 		if err != nil {
 			return nil, err
 		}
+		circuitBreaker, err := deploymentCircuitBreaker(service)
+		if err != nil {
+			return nil, err
+		}
 
 		assignPublicIP := ecsapi.AssignPublicIpEnabled
 		launchType := ecsapi.LaunchTypeFargate
@@ -155,7 +263,16 @@ func (b *ecsAPIService) convert(project *types.Project, resources awsResources)
 			platformVersion = "" // The platform version must be null when specifying an EC2 launch type
 		}
 
-		template.Resources[serviceResourceName(service.Name)] = &ecs.Service{
+		var placement servicePlacement
+		if resources.external {
+			launchType = launchTypeExternal
+			platformVersion = ""
+			placement.constraints = externalPlacement(service)
+		} else if placement, err = resources.placeService(service); err != nil {
+			return nil, err
+		}
+
+		serviceDefinition := &ecs.Service{
 			AWSCloudFormationDependsOn: dependsOn,
 			Cluster:                    resources.cluster,
 			DesiredCount:               desiredCount,
@@ -173,35 +290,70 @@ func (b *ecsAPIService) convert(project *types.Project, resources awsResources)
 				AwsvpcConfiguration: &ecs.Service_AwsVpcConfiguration{
 					AssignPublicIp: assignPublicIP,
 					SecurityGroups: resources.serviceSecurityGroups(service),
-					Subnets:        resources.subnets,
+					Subnets:        placement.subnets,
 				},
 			},
-			PlatformVersion:    platformVersion,
-			PropagateTags:      ecsapi.PropagateTagsService,
-			SchedulingStrategy: ecsapi.SchedulingStrategyReplica,
-			ServiceRegistries:  []ecs.Service_ServiceRegistry{serviceRegistry},
-			Tags:               serviceTags(project, service),
-			TaskDefinition:     cloudformation.Ref(normalizeResourceName(taskDefinition)),
+			PlacementConstraints: placement.constraints,
+			PlacementStrategies:  placement.strategies,
+			PlatformVersion:      platformVersion,
+			PropagateTags:        ecsapi.PropagateTagsService,
+			SchedulingStrategy:   ecsapi.SchedulingStrategyReplica,
+			ServiceRegistries:    serviceRegistries,
+			Tags:                 serviceTags(project, service, hash),
+			TaskDefinition:       cloudformation.Ref(normalizeResourceName(taskDefinition)),
+		}
+		if circuitBreaker != nil {
+			// goformation doesn't support the circuit breaker yet, marshall moves it to the deployment configuration
+			serviceDefinition.AWSCloudFormationMetadata = map[string]interface{}{
+				circuitBreakerMetadata: circuitBreaker,
+			}
 		}
+		if resources.cloudMap.serviceConnect {
+			if serviceDefinition.AWSCloudFormationMetadata == nil {
+				serviceDefinition.AWSCloudFormationMetadata = map[string]interface{}{}
+			}
+			serviceDefinition.AWSCloudFormationMetadata[serviceConnectMetadata] = serviceConnectConfiguration(service, resources.cloudMap, getLogConfiguration(service, project))
+			if _, ok := template.Resources["CloudMap"]; ok {
+				// Service Connect references the namespace by name, which doesn't tell CloudFormation to create it first
+				serviceDefinition.AWSCloudFormationDependsOn = append(serviceDefinition.AWSCloudFormationDependsOn, "CloudMap")
+			}
+		}
+		if portForwarded(service) {
+			if serviceDefinition.AWSCloudFormationMetadata == nil {
+				serviceDefinition.AWSCloudFormationMetadata = map[string]interface{}{}
+			}
+			serviceDefinition.AWSCloudFormationMetadata[enableExecuteCommandMetadata] = true
+		}
+		if resources.external {
+			// tasks use the instances network, rather than their own network interface in the VPC
+			serviceDefinition.NetworkConfiguration = nil
+		}
+		template.Resources[serviceResourceName(service.Name)] = serviceDefinition
 	}
+	createProjectOutputs(project, template)
 	return template, nil
 }
 
 const allProtocols = "-1"
 
-func (b *ecsAPIService) createIngress(service types.ServiceConfig, net string, port types.ServicePortConfig, template *cloudformation.Template, resources awsResources) {
+func (b *ecsAPIService) createIngress(service types.ServiceConfig, net string, port types.ServicePortConfig, template *cloudformation.Template, resources awsResources, cidrs []string) {
 	protocol := strings.ToUpper(port.Protocol)
 	if protocol == "" {
 		protocol = allProtocols
 	}
-	ingress := fmt.Sprintf("%s%dIngress", normalizeResourceName(net), port.Target)
-	template.Resources[ingress] = &ec2.SecurityGroupIngress{
-		CidrIp:      "0.0.0.0/0",
-		Description: fmt.Sprintf("%s:%d/%s on %s nextwork", service.Name, port.Target, port.Protocol, net),
-		GroupId:     resources.securityGroups[net],
-		FromPort:    int(port.Target),
-		IpProtocol:  protocol,
-		ToPort:      int(port.Target),
+	for i, cidr := range cidrs {
+		ingress := fmt.Sprintf("%s%dIngress", normalizeResourceName(net), port.Target)
+		if i > 0 {
+			ingress = fmt.Sprintf("%s%d", ingress, i)
+		}
+		template.Resources[ingress] = &ec2.SecurityGroupIngress{
+			CidrIp:      cidr,
+			Description: fmt.Sprintf("%s:%d/%s on %s nextwork", service.Name, port.Target, port.Protocol, net),
+			GroupId:     resources.securityGroups[net],
+			FromPort:    int(port.Target),
+			IpProtocol:  protocol,
+			ToPort:      int(port.Target),
+		}
 	}
 }
 
@@ -225,12 +377,19 @@ func (b *ecsAPIService) createSecret(project *types.Project, name string, s type
 	return nil
 }
 
+// logGroupPrefix is the prefix of the log group name created for a project
+const logGroupPrefix = "/docker-compose/"
+
+// circuitBreakerMetadata is the service metadata key marshall moves to the DeploymentCircuitBreaker property of the
+// deployment configuration
+const circuitBreakerMetadata = "DeploymentCircuitBreaker"
+
 func (b *ecsAPIService) createLogGroup(project *types.Project, template *cloudformation.Template) {
 	retention := 0
 	if v, ok := project.Extensions[extensionRetention]; ok {
 		retention = v.(int)
 	}
-	logGroup := fmt.Sprintf("/docker-compose/%s", project.Name)
+	logGroup := logGroupPrefix + project.Name
 	template.Resources["LogGroup"] = &logs.LogGroup{
 		LogGroupName:    logGroup,
 		RetentionInDays: retention,
@@ -256,6 +415,21 @@ func computeRollingUpdateLimits(service types.ServiceConfig) (int, int, error) {
 		return minPercent, maxPercent, nil
 	}
 
+	switch updateConfig.Order {
+	case "", compose.UpdateOrderStartFirst:
+	case compose.UpdateOrderStopFirst:
+		// tasks are stopped before their replacement starts, all at once unless parallelism is set
+		if !okMin {
+			minPercent = 0
+		}
+		if !okMax {
+			maxPercent = 100
+		}
+	default:
+		return minPercent, maxPercent,
+			fmt.Errorf("unsupported deploy.update_config.order %q, use %s or %s", updateConfig.Order, compose.UpdateOrderStartFirst, compose.UpdateOrderStopFirst)
+	}
+
 	if updateConfig.Parallelism != nil {
 		parallelism := int(*updateConfig.Parallelism)
 		if service.Deploy.Replicas == nil {
@@ -267,16 +441,47 @@ func computeRollingUpdateLimits(service types.ServiceConfig) (int, int, error) {
 			return minPercent, maxPercent,
 				fmt.Errorf("deploy.replicas (%d) must be greater than deploy.update_config.parallelism (%d)", replicas, parallelism)
 		}
-		if !okMin {
+		if !okMin && updateConfig.Order != compose.UpdateOrderStartFirst {
 			minPercent = (replicas - parallelism) * 100 / replicas
 		}
-		if !okMax {
+		if !okMax && updateConfig.Order != compose.UpdateOrderStopFirst {
 			maxPercent = (replicas + parallelism) * 100 / replicas
 		}
 	}
 	return minPercent, maxPercent, nil
 }
 
+// deploymentCircuitBreaker maps deploy.update_config.failure_action to the ECS deployment circuit breaker, which stops
+// a deployment whose tasks fail to start and rolls it back when requested. ECS services always replace stopped tasks, so
+// a deploy.restart_policy.max_attempts enables the circuit breaker to stop replacing tasks which keep failing, and the
+// `none` condition is only supported by jobs.
+func deploymentCircuitBreaker(service types.ServiceConfig) (map[string]bool, error) {
+	restart, err := compose.ServiceRestartPolicy(service)
+	if err != nil {
+		return nil, err
+	}
+	if restart.Condition == containers.RestartPolicyNone {
+		return nil, errors.Wrapf(errdefs.ErrNotImplemented, "ECS services are always restarted, declare service %s as a job to run it once", service.Name)
+	}
+	var action string
+	if service.Deploy != nil && service.Deploy.UpdateConfig != nil {
+		action = service.Deploy.UpdateConfig.FailureAction
+	}
+	switch action {
+	case "", compose.UpdateFailureContinue:
+		if restart.MaxAttempts > 0 {
+			return map[string]bool{"Enable": true, "Rollback": false}, nil
+		}
+		return nil, nil
+	case compose.UpdateFailurePause:
+		return map[string]bool{"Enable": true, "Rollback": false}, nil
+	case compose.UpdateFailureRollback:
+		return map[string]bool{"Enable": true, "Rollback": true}, nil
+	default:
+		return nil, fmt.Errorf("unsupported deploy.update_config.failure_action %q for service %s", action, service.Name)
+	}
+}
+
 func (b *ecsAPIService) createListener(service types.ServiceConfig, port types.ServicePortConfig,
 	template *cloudformation.Template,
 	targetGroupName string, loadBalancerARN string, protocol string) string {
@@ -326,30 +531,33 @@ func (b *ecsAPIService) createTargetGroup(project *types.Project, service types.
 	return targetGroupName
 }
 
-func (b *ecsAPIService) createServiceRegistry(service types.ServiceConfig, template *cloudformation.Template, healthCheck *cloudmap.Service_HealthCheckConfig) ecs.Service_ServiceRegistry {
+func (b *ecsAPIService) createServiceRegistry(service types.ServiceConfig, template *cloudformation.Template, healthCheck *cloudmap.Service_HealthCheckConfig, cloudMap cloudMapConfig) ecs.Service_ServiceRegistry {
 	serviceRegistration := fmt.Sprintf("%sServiceDiscoveryEntry", normalizeResourceName(service.Name))
 	serviceRegistry := ecs.Service_ServiceRegistry{
 		RegistryArn: cloudformation.GetAtt(serviceRegistration, "Arn"),
 	}
 
-	template.Resources[serviceRegistration] = &cloudmap.Service{
+	registration := &cloudmap.Service{
 		Description:       fmt.Sprintf("%q service discovery entry in Cloud Map", service.Name),
 		HealthCheckConfig: healthCheck,
 		HealthCheckCustomConfig: &cloudmap.Service_HealthCheckCustomConfig{
 			FailureThreshold: 1,
 		},
 		Name:        service.Name,
-		NamespaceId: cloudformation.Ref("CloudMap"),
-		DnsConfig: &cloudmap.Service_DnsConfig{
+		NamespaceId: cloudMap.namespace,
+	}
+	if cloudMap.dns() {
+		registration.DnsConfig = &cloudmap.Service_DnsConfig{
 			DnsRecords: []cloudmap.Service_DnsRecord{
 				{
-					TTL:  60,
+					TTL:  float64(cloudMap.ttl),
 					Type: cloudmapapi.RecordTypeA,
 				},
 			},
 			RoutingPolicy: cloudmapapi.RoutingPolicyMultivalue,
-		},
+		}
 	}
+	template.Resources[serviceRegistration] = registration
 	return serviceRegistry
 }
 
@@ -367,7 +575,7 @@ func (b *ecsAPIService) createTaskExecutionRole(project *types.Project, service
 	return taskExecutionRole
 }
 
-func (b *ecsAPIService) createTaskRole(service types.ServiceConfig, template *cloudformation.Template) string {
+func (b *ecsAPIService) createTaskRole(project *types.Project, service types.ServiceConfig, template *cloudformation.Template) string {
 	taskRole := fmt.Sprintf("%sTaskRole", normalizeResourceName(service.Name))
 	rolePolicies := []iam.Role_Policy{}
 	if roles, ok := service.Extensions[extensionRole]; ok {
@@ -381,6 +589,12 @@ func (b *ecsAPIService) createTaskRole(service types.ServiceConfig, template *cl
 			managedPolicies = append(managedPolicies, s.(string))
 		}
 	}
+	if meshed(project, service) {
+		managedPolicies = append(managedPolicies, appMeshEnvoyPolicy)
+	}
+	if portForwarded(service) {
+		rolePolicies = append(rolePolicies, execCommandPolicy)
+	}
 	if len(rolePolicies) == 0 && len(managedPolicies) == 0 {
 		return ""
 	}
@@ -392,14 +606,6 @@ func (b *ecsAPIService) createTaskRole(service types.ServiceConfig, template *cl
 	return taskRole
 }
 
-func (b *ecsAPIService) createCloudMap(project *types.Project, template *cloudformation.Template, vpc string) {
-	template.Resources["CloudMap"] = &cloudmap.PrivateDnsNamespace{
-		Description: fmt.Sprintf("Service Map for Docker Compose project %s", project.Name),
-		Name:        fmt.Sprintf("%s.local", project.Name),
-		Vpc:         vpc,
-	}
-}
-
 func (b *ecsAPIService) createPolicies(project *types.Project, service types.ServiceConfig) []iam.Role_Policy {
 	var arns []string
 	if value, ok := service.Extensions[extensionPullCredentials]; ok {
@@ -408,6 +614,9 @@ func (b *ecsAPIService) createPolicies(project *types.Project, service types.Ser
 	for _, secret := range service.Secrets {
 		arns = append(arns, project.Secrets[secret.Source].Name)
 	}
+	for _, config := range service.Configs {
+		arns = append(arns, project.Configs[config.Source].Name)
+	}
 	if len(arns) > 0 {
 		return []iam.Role_Policy{
 			{
@@ -17,6 +17,8 @@
 package ecs
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"testing"
@@ -25,12 +27,14 @@ import (
 
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/appmesh"
 	"github.com/awslabs/goformation/v4/cloudformation/ec2"
 	"github.com/awslabs/goformation/v4/cloudformation/ecs"
 	"github.com/awslabs/goformation/v4/cloudformation/elasticloadbalancingv2"
 	"github.com/awslabs/goformation/v4/cloudformation/iam"
 	"github.com/awslabs/goformation/v4/cloudformation/logs"
 	"github.com/compose-spec/compose-go/cli"
+	"github.com/compose-spec/compose-go/compatibility"
 	"github.com/compose-spec/compose-go/loader"
 	"github.com/compose-spec/compose-go/types"
 	"gotest.tools/v3/assert"
@@ -135,6 +139,89 @@ services:
 	assert.Check(t, service.DeploymentConfiguration.MinimumHealthyPercent == 25)
 }
 
+func TestRollingUpdateOrder(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  foo:
+    image: hello_world
+    deploy:
+      replicas: 4
+      update_config:
+        parallelism: 1
+        order: stop-first
+  bar:
+    image: hello_world
+    deploy:
+      replicas: 4
+      update_config:
+        parallelism: 2
+        order: start-first
+`)
+	service := template.Resources["FooService"].(*ecs.Service)
+	assert.Check(t, service.DeploymentConfiguration.MaximumPercent == 100)
+	assert.Check(t, service.DeploymentConfiguration.MinimumHealthyPercent == 75)
+	service = template.Resources["BarService"].(*ecs.Service)
+	assert.Check(t, service.DeploymentConfiguration.MaximumPercent == 150)
+	assert.Check(t, service.DeploymentConfiguration.MinimumHealthyPercent == 100)
+}
+
+func TestDeploymentCircuitBreaker(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  foo:
+    image: hello_world
+    deploy:
+      update_config:
+        failure_action: rollback
+`)
+	template, err := (&ecsAPIService{}).convert(project, awsResources{})
+	assert.NilError(t, err)
+	raw, err := marshall(template)
+	assert.NilError(t, err)
+	var parsed struct {
+		Resources map[string]struct {
+			Metadata   map[string]interface{}
+			Properties struct {
+				DeploymentConfiguration struct {
+					DeploymentCircuitBreaker map[string]bool
+				}
+			}
+		}
+	}
+	assert.NilError(t, json.Unmarshal(raw, &parsed))
+	service := parsed.Resources["FooService"]
+	assert.Check(t, service.Metadata == nil)
+	assert.DeepEqual(t, service.Properties.DeploymentConfiguration.DeploymentCircuitBreaker, map[string]bool{
+		"Enable":   true,
+		"Rollback": true,
+	})
+
+	project = loadConfig(t, `
+services:
+  foo:
+    image: hello_world
+    deploy:
+      update_config:
+        failure_action: retry
+`)
+	_, err = (&ecsAPIService{}).convert(project, awsResources{})
+	assert.ErrorContains(t, err, `unsupported deploy.update_config.failure_action "retry"`)
+}
+
+func TestRestartPolicyCircuitBreaker(t *testing.T) {
+	attempts := uint64(3)
+	service := types.ServiceConfig{Name: "foo", Deploy: &types.DeployConfig{
+		RestartPolicy: &types.RestartPolicy{Condition: "on-failure", MaxAttempts: &attempts},
+	}}
+	breaker, err := deploymentCircuitBreaker(service)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, breaker, map[string]bool{"Enable": true, "Rollback": false})
+
+	service.Deploy.RestartPolicy = &types.RestartPolicy{Condition: "none"}
+	_, err = deploymentCircuitBreaker(service)
+	assert.ErrorContains(t, err, "declare service foo as a job")
+}
+
 func TestRolePolicy(t *testing.T) {
 	template := convertYaml(t, `
 services:
@@ -403,6 +490,283 @@ services:
 	}
 }
 
+func TestNoPublicIngressOnInternalNetwork(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  test:
+    image: nginx
+    ports:
+      - 80:80
+    networks:
+      - back
+networks:
+  back:
+    internal: true
+`)
+	_, ok := template.Resources["Back80Ingress"]
+	assert.Assert(t, !ok)
+}
+
+func TestServiceSecurityGroupExtension(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  test:
+    image: nginx
+    x-aws-security-group:
+      - sg-123
+      - sg-456
+`)
+	groups, err := serviceSecurityGroupExtension(project.Services[0])
+	assert.NilError(t, err)
+	assert.DeepEqual(t, groups, []string{"sg-123", "sg-456"})
+
+	template, err := (&ecsAPIService{}).convert(project, awsResources{
+		serviceExtraSecurityGroups: map[string][]string{"test": groups},
+	})
+	assert.NilError(t, err)
+	s := template.Resources["TestService"].(*ecs.Service)
+	assert.DeepEqual(t, s.NetworkConfiguration.AwsvpcConfiguration.SecurityGroups, []string{cloudformation.Ref("DefaultNetwork"), "sg-123", "sg-456"})
+}
+
+func TestExposure(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  web:
+    image: nginx
+    ports:
+      - 80:80
+  db:
+    image: postgres
+    expose:
+      - 5432
+`)
+	exposure, err := (&ecsAPIService{}).Exposure(context.TODO(), project)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, exposure, []compose.PortExposure{
+		{Service: "db", Port: 5432, Protocol: "tcp", Source: "services on networks default"},
+		{Service: "web", Port: 80, Protocol: "tcp", Source: "0.0.0.0/0 through application load balancer on networks default"},
+	})
+}
+
+func TestSecretsAsEnvironment(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  test:
+    image: nginx
+    secrets:
+      - source: token
+        target: API_TOKEN
+    x-secret-injection: env
+secrets:
+  token:
+    name: arn:aws:secretsmanager:region:account:secret:token
+    external: true
+`)
+	def := template.Resources["TestTaskDefinition"].(*ecs.TaskDefinition)
+	assert.Equal(t, len(def.ContainerDefinitions), 1)
+	assert.DeepEqual(t, def.ContainerDefinitions[0].Secrets, []ecs.TaskDefinition_Secret{
+		{Name: "API_TOKEN", ValueFrom: "arn:aws:secretsmanager:region:account:secret:token"},
+	})
+	assert.Equal(t, len(def.Volumes), 0)
+}
+
+func TestSecretsAtAbsolutePath(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  test:
+    image: nginx
+    secrets:
+      - source: cert
+        target: /opt/app/cert.pem
+secrets:
+  cert:
+    name: arn:aws:secretsmanager:region:account:secret:cert
+    external: true
+`)
+	def := template.Resources["TestTaskDefinition"].(*ecs.TaskDefinition)
+	assert.Equal(t, len(def.ContainerDefinitions), 2)
+	sidecar := def.ContainerDefinitions[0]
+	assert.DeepEqual(t, sidecar.Command, []string{`[{"Name":"cert","Keys":null,"Path":"/opt/app/cert.pem"}]`})
+	assert.DeepEqual(t, def.ContainerDefinitions[1].MountPoints, []ecs.TaskDefinition_MountPoint{
+		{ContainerPath: "/run/secrets/", ReadOnly: true, SourceVolume: "secrets"},
+		{ContainerPath: "/opt/app", ReadOnly: true, SourceVolume: "secretsOptapp"},
+	})
+}
+
+func TestSecretsInSystemFolder(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  test:
+    image: nginx
+    secrets:
+      - source: cert
+        target: /etc/ssl/certs/cert.pem
+secrets:
+  cert:
+    name: arn:aws:secretsmanager:region:account:secret:cert
+    external: true
+`)
+	_, err := (&ecsAPIService{}).convert(project, awsResources{})
+	assert.ErrorContains(t, err, "would mount a volume over /etc")
+}
+
+func TestConfigs(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  test:
+    image: nginx
+    configs:
+      - source: nginx
+        target: /etc/nginx/conf.d/default.conf
+configs:
+  nginx:
+    name: /shared/nginx
+    external: true
+`)
+	def := template.Resources["TestTaskDefinition"].(*ecs.TaskDefinition)
+	assert.Equal(t, len(def.ContainerDefinitions), 2)
+	sidecar := def.ContainerDefinitions[0]
+	assert.DeepEqual(t, sidecar.Command, []string{`[{"Name":"CONFIG_NGINX","Keys":null,"Path":"/etc/nginx/conf.d/default.conf"}]`})
+	assert.Equal(t, sidecar.Secrets[0].ValueFrom, parameterARN("/shared/nginx"))
+	assert.DeepEqual(t, def.ContainerDefinitions[1].MountPoints, []ecs.TaskDefinition_MountPoint{
+		{ContainerPath: "/run/secrets/", ReadOnly: true, SourceVolume: "secrets"},
+		{ContainerPath: "/etc/nginx/conf.d", ReadOnly: true, SourceVolume: "secretsEtcnginxconfd"},
+	})
+}
+
+func TestSidecars(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  test:
+    image: nginx
+x-sidecars:
+  proxy:
+    image: envoyproxy/envoy
+    command: ["--service-cluster", "{{.Service}}"]
+`)
+	def := template.Resources["TestTaskDefinition"].(*ecs.TaskDefinition)
+	assert.Equal(t, len(def.ContainerDefinitions), 2)
+	sidecar := def.ContainerDefinitions[1]
+	assert.Equal(t, sidecar.Name, "proxy")
+	assert.Equal(t, sidecar.Image, "envoyproxy/envoy")
+	assert.DeepEqual(t, sidecar.Command, []string{"--service-cluster", "test"})
+	assert.Check(t, !sidecar.Essential)
+}
+
+func TestArm64Platform(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  test:
+    image: nginx
+    platform: linux/arm64
+x-sidecars:
+  proxy:
+    image: envoyproxy/envoy
+`)
+	template, err := (&ecsAPIService{}).convert(project, awsResources{})
+	assert.NilError(t, err)
+	raw, err := marshall(template)
+	assert.NilError(t, err)
+	var parsed struct {
+		Resources map[string]struct {
+			Metadata   map[string]interface{}
+			Properties struct {
+				RuntimePlatform map[string]string
+			}
+		}
+	}
+	assert.NilError(t, json.Unmarshal(raw, &parsed))
+	def := parsed.Resources["TestTaskDefinition"]
+	assert.Check(t, def.Metadata == nil)
+	assert.DeepEqual(t, def.Properties.RuntimePlatform, map[string]string{
+		"CpuArchitecture":       "ARM64",
+		"OperatingSystemFamily": "LINUX",
+	})
+}
+
+func TestMixedPlatformsFailure(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  test:
+    image: nginx
+    platform: linux/arm64
+x-sidecars:
+  proxy:
+    image: envoyproxy/envoy
+    platform: linux/amd64
+`)
+	_, err := (&ecsAPIService{}).convert(project, awsResources{})
+	assert.ErrorContains(t, err, `task of service "test" can't mix platforms: linux/amd64 (proxy), linux/arm64 (test)`)
+
+	project = loadConfig(t, `
+services:
+  test:
+    image: nginx
+    platform: windows/amd64
+`)
+	_, err = (&ecsAPIService{}).convert(project, awsResources{})
+	assert.ErrorContains(t, err, "ECS doesn't run windows/amd64 tasks")
+}
+
+func TestNetworkSubnets(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  front:
+    image: nginx
+    networks:
+      - public
+      - private
+  back:
+    image: redis
+    networks:
+      - private
+networks:
+  public:
+  private:
+    internal: true
+    x-aws-subnets:
+      - subnet2
+      - subnet1
+`)
+	subnets, err := parseNetworkSubnetsExtension(project, []string{"subnet1", "subnet2", "subnet3"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, subnets, map[string][]string{"private": {"subnet1", "subnet2"}})
+
+	template, err := (&ecsAPIService{}).convert(project, awsResources{
+		subnets:        []string{"subnet1", "subnet2", "subnet3"},
+		networkSubnets: subnets,
+	})
+	assert.NilError(t, err)
+	service := template.Resources["BackService"].(*ecs.Service)
+	assert.DeepEqual(t, service.NetworkConfiguration.AwsvpcConfiguration.Subnets, []string{"subnet1", "subnet2"})
+	assert.Equal(t, len(service.NetworkConfiguration.AwsvpcConfiguration.SecurityGroups), 1)
+	service = template.Resources["FrontService"].(*ecs.Service)
+	assert.Equal(t, len(service.NetworkConfiguration.AwsvpcConfiguration.SecurityGroups), 2)
+}
+
+func TestNetworkSubnetsFailure(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  test:
+    image: nginx
+    networks:
+      - front
+      - back
+networks:
+  front:
+    x-aws-subnets:
+      - subnet1
+  back:
+    x-aws-subnets:
+      - subnet2
+`)
+	_, err := parseNetworkSubnetsExtension(project, []string{"subnet1", "subnet2"})
+	assert.ErrorContains(t, err, "service test can't be attached to networks back and front which use different subnets")
+
+	_, err = parseNetworkSubnetsExtension(project, []string{"subnet1"})
+	assert.ErrorContains(t, err, "subnet subnet2 of network back doesn't belong to the VPC")
+}
+
 func convertResultAsString(t *testing.T, project *types.Project) string {
 	backend := &ecsAPIService{}
 	template, err := backend.convert(project, awsResources{
@@ -425,6 +789,26 @@ func load(t *testing.T, paths ...string) *types.Project {
 	return project
 }
 
+func TestProjectOutputs(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  test:
+    image: nginx
+    ports:
+      - 80:80
+    volumes:
+      - data:/data
+volumes:
+  data:
+    name: fs-123456
+`)
+	assert.DeepEqual(t, template.Outputs[loadBalancerDNSOutput].Value, cloudformation.GetAtt("LoadBalancer", "DNSName"))
+	assert.Equal(t, template.Outputs[logGroupOutput].Value, cloudformation.Ref("LogGroup"))
+	assert.Equal(t, template.Outputs["DataFileSystemId"].Value, "fs-123456")
+	assert.Assert(t, isJobOutput(jobOutput("migrate", jobClusterOutput)))
+	assert.Assert(t, !isJobOutput(logGroupOutput))
+}
+
 func convertYaml(t *testing.T, yaml string) *cloudformation.Template {
 	project := loadConfig(t, yaml)
 	backend := &ecsAPIService{}
@@ -446,3 +830,180 @@ func loadConfig(t *testing.T, yaml string) *types.Project {
 	assert.NilError(t, err)
 	return model
 }
+
+func TestAppMesh(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  front:
+    image: nginx
+    ports:
+      - 80:80
+  back:
+    image: redis
+    ports:
+      - 6379:6379
+  worker:
+    image: worker
+    x-mesh: false
+x-mesh: appmesh
+`)
+	assert.Check(t, template.Resources["Mesh"] != nil)
+	assert.Check(t, template.Resources["WorkerVirtualNode"] == nil)
+
+	node := template.Resources["FrontVirtualNode"].(*appmesh.VirtualNode)
+	assert.DeepEqual(t, node.Spec.Backends, []appmesh.VirtualNode_Backend{
+		{VirtualService: &appmesh.VirtualNode_VirtualServiceBackend{VirtualServiceName: "back.Test.local"}},
+	})
+	assert.Equal(t, node.Spec.ServiceDiscovery.AWSCloudMap.ServiceName, "front")
+	service := template.Resources["BackVirtualService"].(*appmesh.VirtualService)
+	assert.Equal(t, service.Spec.Provider.VirtualNode.VirtualNodeName, "back")
+
+	def := template.Resources["FrontTaskDefinition"].(*ecs.TaskDefinition)
+	assert.Equal(t, len(def.ContainerDefinitions), 2)
+	assert.Equal(t, def.ContainerDefinitions[1].Name, "envoy")
+	assert.Equal(t, def.ContainerDefinitions[0].DependsOnProp[0].ContainerName, "envoy")
+	assert.Equal(t, def.ProxyConfiguration.Type, "APPMESH")
+	assert.Equal(t, get(def.ProxyConfiguration.ProxyConfigurationProperties, "AppPorts"), "80")
+	role := template.Resources["FrontTaskRole"].(*iam.Role)
+	assert.DeepEqual(t, role.ManagedPolicyArns, []string{appMeshEnvoyPolicy})
+
+	worker := template.Resources["WorkerTaskDefinition"].(*ecs.TaskDefinition)
+	assert.Assert(t, worker.ProxyConfiguration == nil)
+}
+
+func TestStopSettingsCompatibility(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  foo:
+    image: hello_world
+    stop_grace_period: 5m
+    stop_signal: SIGINT
+`)
+	checker := &fargateCompatibilityChecker{compatibility.AllowList{Supported: compatibleComposeAttributes}}
+	compatibility.Check(project, checker)
+	assert.Equal(t, len(checker.Errors()), 2)
+	assert.ErrorContains(t, checker.Errors()[0], "reduced to the Fargate maximum of 2m0s")
+	assert.ErrorContains(t, checker.Errors()[1], "stop_signal SIGINT of service foo is not supported")
+
+	template, err := (&ecsAPIService{}).convert(project, awsResources{})
+	assert.NilError(t, err)
+	def := template.Resources["FooTaskDefinition"].(*ecs.TaskDefinition)
+	assert.Equal(t, def.ContainerDefinitions[0].StopTimeout, 120)
+}
+
+func TestKernelSettings(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  db:
+    image: postgres
+    read_only: true
+    cap_drop:
+      - ALL
+    ulimits:
+      nproc: 65535
+      nofile:
+        soft: 20000
+        hard: 40000
+    sysctls:
+      net.core.somaxconn: 1024
+      kernel.shmmax: 68719476736
+`)
+	def := template.Resources["DbTaskDefinition"].(*ecs.TaskDefinition).ContainerDefinitions[0]
+	assert.Check(t, def.ReadonlyRootFilesystem)
+	assert.DeepEqual(t, def.LinuxParameters.Capabilities.Drop, []string{"ALL"})
+	assert.DeepEqual(t, def.Ulimits, []ecs.TaskDefinition_Ulimit{
+		{Name: "nofile", SoftLimit: 20000, HardLimit: 40000},
+		{Name: "nproc", SoftLimit: 65535, HardLimit: 65535},
+	})
+	assert.DeepEqual(t, def.SystemControls, []ecs.TaskDefinition_SystemControl{
+		{Namespace: "kernel.shmmax", Value: "68719476736"},
+		{Namespace: "net.core.somaxconn", Value: "1024"},
+	})
+
+	project := loadConfig(t, `
+services:
+  proxy:
+    image: haproxy
+    cap_add:
+      - NET_ADMIN
+    sysctls:
+      vm.overcommit_memory: 1
+    ulimits:
+      openfiles: 1024
+`)
+	checker := &fargateCompatibilityChecker{compatibility.AllowList{Supported: compatibleComposeAttributes}}
+	compatibility.Check(project, checker)
+	var messages []string
+	for _, err := range checker.Errors() {
+		messages = append(messages, err.Error())
+	}
+	assert.DeepEqual(t, messages, []string{
+		"ECS doesn't allow to add capability NET_ADMIN to service proxy running on Fargate: incompatible attribute",
+		"service proxy can't set sysctl vm.overcommit_memory, only namespaced kernel parameters can be set in ECS tasks: incompatible attribute",
+		"service proxy can't set ulimit openfiles, ECS supports core, cpu, data, fsize, locks, memlock, msgqueue, nice, nofile, nproc, rss, rtprio, rttime, sigpending, stack: incompatible attribute",
+	})
+}
+
+func TestTmpfsAndShmSize(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  trainer:
+    image: pytorch
+    shm_size: 2gb
+    tmpfs:
+      - /run
+      - /cache:noexec,size=64m
+    deploy:
+      resources:
+        reservations:
+          generic_resources:
+            - discrete_resource_spec:
+                kind: gpus
+                value: 1
+`)
+	def := template.Resources["TrainerTaskDefinition"].(*ecs.TaskDefinition).ContainerDefinitions[0]
+	assert.Equal(t, def.LinuxParameters.SharedMemorySize, 2048)
+	assert.DeepEqual(t, def.LinuxParameters.Tmpfs, []ecs.TaskDefinition_Tmpfs{
+		{ContainerPath: "/run", Size: 100},
+		{ContainerPath: "/cache", MountOptions: []string{"noexec"}, Size: 64},
+	})
+
+	template = convertYaml(t, `
+services:
+  browser:
+    image: chromium
+    shm_size: 1gb
+    volumes:
+      - type: tmpfs
+        target: /tmp
+        tmpfs:
+          size: 10000000
+`)
+	task := template.Resources["BrowserTaskDefinition"].(*ecs.TaskDefinition)
+	def = task.ContainerDefinitions[0]
+	assert.Equal(t, def.LinuxParameters.SharedMemorySize, 0)
+	assert.Check(t, def.LinuxParameters.Tmpfs == nil)
+	assert.DeepEqual(t, task.Volumes, []ecs.TaskDefinition_Volume{{Name: "tmpfs0"}, {Name: "shm"}})
+	assert.DeepEqual(t, def.MountPoints, []ecs.TaskDefinition_MountPoint{
+		{ContainerPath: "/tmp", SourceVolume: "tmpfs0"},
+		{ContainerPath: "/dev/shm", SourceVolume: "shm"},
+	})
+
+	project := loadConfig(t, `
+services:
+  browser:
+    image: chromium
+    shm_size: 1gb
+    tmpfs: /run
+`)
+	checker := &fargateCompatibilityChecker{compatibility.AllowList{Supported: compatibleComposeAttributes}}
+	compatibility.Check(project, checker)
+	var messages []string
+	for _, err := range checker.Errors() {
+		messages = append(messages, err.Error())
+	}
+	assert.DeepEqual(t, messages, []string{
+		"services.shm_size of service browser is backed by task ephemeral storage as Fargate doesn't support shared memory size: unsupported attribute",
+		"services.tmpfs of service browser are backed by task ephemeral storage as Fargate doesn't support tmpfs mounts: unsupported attribute",
+	})
+}
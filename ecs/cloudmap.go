@@ -0,0 +1,146 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+
+	cloudmapapi "github.com/aws/aws-sdk-go/service/servicediscovery"
+	"github.com/awslabs/goformation/v4/cloudformation"
+	cloudmap "github.com/awslabs/goformation/v4/cloudformation/servicediscovery"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const defaultCloudMapTTL = 60
+
+// cloudMapConfig configures the Cloud Map namespace services register in, set by the x-aws-cloudmap extension:
+//
+//	x-aws-cloudmap:
+//	  name: myapp.internal   # namespace name, <project>.local by default
+//	  ttl: 10                # TTL of DNS records in seconds
+//	  private_zone: false    # create an API-only namespace rather than a private hosted zone
+//	  namespace: ns-xxxxxxxx # register in an existing namespace, shared by multiple projects
+//	  service_connect: true  # route traffic between services with ECS Service Connect rather than DNS records
+type cloudMapConfig struct {
+	name           string
+	ttl            int64
+	httpOnly       bool
+	namespace      string
+	serviceConnect bool
+}
+
+// dns returns true when services can be resolved by DNS queries
+func (c cloudMapConfig) dns() bool {
+	return !c.httpOnly
+}
+
+func (b *ecsAPIService) parseCloudMapExtension(ctx context.Context, project *types.Project) (cloudMapConfig, error) {
+	config := cloudMapConfig{}
+	x, ok := project.Extensions[extensionCloudMap]
+	if !ok {
+		return config, nil
+	}
+	values, ok := x.(map[string]interface{})
+	if !ok {
+		return config, errors.Wrapf(errdefs.ErrParsingFailed, "%s must be a mapping", extensionCloudMap)
+	}
+	for key, value := range values {
+		var valid bool
+		switch key {
+		case "name":
+			config.name, valid = value.(string)
+		case "ttl":
+			switch ttl := value.(type) {
+			case int:
+				config.ttl = int64(ttl)
+			case float64:
+				config.ttl = int64(ttl)
+			}
+			valid = config.ttl > 0
+		case "private_zone":
+			var privateZone bool
+			privateZone, valid = value.(bool)
+			config.httpOnly = !privateZone
+		case "namespace":
+			config.namespace, valid = value.(string)
+		case "service_connect":
+			config.serviceConnect, valid = value.(bool)
+		default:
+			return config, errors.Wrapf(errdefs.ErrParsingFailed, "unsupported %s attribute %q", extensionCloudMap, key)
+		}
+		if !valid {
+			return config, errors.Wrapf(errdefs.ErrParsingFailed, "invalid %s %s: %v", extensionCloudMap, key, value)
+		}
+	}
+
+	if config.namespace != "" {
+		if config.name != "" {
+			return config, errors.Wrapf(errdefs.ErrParsingFailed, "%s name can't be set for an existing namespace", extensionCloudMap)
+		}
+		name, namespaceType, err := b.SDK.GetNamespace(ctx, config.namespace)
+		if err != nil {
+			return config, err
+		}
+		switch namespaceType {
+		case cloudmapapi.NamespaceTypeDnsPrivate:
+			config.httpOnly = false
+		case cloudmapapi.NamespaceTypeHttp:
+			config.httpOnly = true
+		default:
+			return config, fmt.Errorf("Cloud Map namespace %s of type %s can't be used for service discovery within a VPC", config.namespace, namespaceType)
+		}
+		config.name = name
+	}
+	return config, nil
+}
+
+// ensureCloudMap creates the project Cloud Map namespace unless an existing one is used
+func (b *ecsAPIService) ensureCloudMap(r *awsResources, project *types.Project, template *cloudformation.Template) {
+	if r.cloudMap.name == "" {
+		r.cloudMap.name = cloudMapNamespace(project)
+	}
+	if r.cloudMap.ttl == 0 {
+		r.cloudMap.ttl = defaultCloudMapTTL
+	}
+	if r.cloudMap.namespace != "" {
+		return
+	}
+	description := fmt.Sprintf("Service Map for Docker Compose project %s", project.Name)
+	if r.cloudMap.httpOnly {
+		template.Resources["CloudMap"] = &cloudmap.HttpNamespace{
+			Description: description,
+			Name:        r.cloudMap.name,
+		}
+	} else {
+		// Private DNS namespace will allow DNS name for the services to be <service>.<namespace>
+		template.Resources["CloudMap"] = &cloudmap.PrivateDnsNamespace{
+			Description: description,
+			Name:        r.cloudMap.name,
+			Vpc:         r.vpc,
+		}
+	}
+	r.cloudMap.namespace = cloudformation.Ref("CloudMap")
+}
+
+// cloudMapNamespace is the default namespace services are registered in
+func cloudMapNamespace(project *types.Project) string {
+	return fmt.Sprintf("%s.local", project.Name)
+}
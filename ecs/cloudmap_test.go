@@ -0,0 +1,203 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	cloudmap "github.com/awslabs/goformation/v4/cloudformation/servicediscovery"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestCloudMapDefaults(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  test:
+    image: nginx
+`)
+	namespace := template.Resources["CloudMap"].(*cloudmap.PrivateDnsNamespace)
+	assert.Equal(t, namespace.Name, "Test.local")
+	registration := template.Resources["TestServiceDiscoveryEntry"].(*cloudmap.Service)
+	assert.Equal(t, registration.DnsConfig.DnsRecords[0].TTL, float64(60))
+}
+
+func TestCloudMapExtension(t *testing.T) {
+	project := loadConfig(t, `
+x-aws-cloudmap:
+  name: myapp.internal
+  ttl: 10
+services:
+  test:
+    image: nginx
+`)
+	b := &ecsAPIService{}
+	cloudMap, err := b.parseCloudMapExtension(context.TODO(), project)
+	assert.NilError(t, err)
+	template, err := b.convert(project, awsResources{cloudMap: cloudMap})
+	assert.NilError(t, err)
+
+	namespace := template.Resources["CloudMap"].(*cloudmap.PrivateDnsNamespace)
+	assert.Equal(t, namespace.Name, "myapp.internal")
+	registration := template.Resources["TestServiceDiscoveryEntry"].(*cloudmap.Service)
+	assert.Equal(t, registration.DnsConfig.DnsRecords[0].TTL, float64(10))
+	definition := template.Resources["TestTaskDefinition"].(*ecs.TaskDefinition)
+	found := false
+	for _, env := range definition.ContainerDefinitions[0].Environment {
+		if env.Name == "LOCALDOMAIN" {
+			found = true
+		}
+	}
+	assert.Assert(t, found)
+}
+
+func TestCloudMapWithoutPrivateZone(t *testing.T) {
+	project := loadConfig(t, `
+x-aws-cloudmap:
+  private_zone: false
+services:
+  test:
+    image: nginx
+`)
+	b := &ecsAPIService{}
+	cloudMap, err := b.parseCloudMapExtension(context.TODO(), project)
+	assert.NilError(t, err)
+	template, err := b.convert(project, awsResources{cloudMap: cloudMap})
+	assert.NilError(t, err)
+
+	_, ok := template.Resources["CloudMap"].(*cloudmap.HttpNamespace)
+	assert.Assert(t, ok)
+	registration := template.Resources["TestServiceDiscoveryEntry"].(*cloudmap.Service)
+	assert.Assert(t, registration.DnsConfig == nil)
+}
+
+func TestCloudMapInvalidExtension(t *testing.T) {
+	project := loadConfig(t, `
+x-aws-cloudmap:
+  ttl: -1
+services:
+  test:
+    image: nginx
+`)
+	b := &ecsAPIService{}
+	_, err := b.parseCloudMapExtension(context.TODO(), project)
+	assert.Assert(t, errdefs.IsErrParsingFailed(err))
+}
+
+func TestServiceConnect(t *testing.T) {
+	project := loadConfig(t, `
+x-aws-cloudmap:
+  service_connect: true
+services:
+  web:
+    image: nginx
+    ports:
+      - 80:80
+  api:
+    image: api
+    expose:
+      - 8080
+  worker:
+    image: worker
+`)
+	b := &ecsAPIService{}
+	cloudMap, err := b.parseCloudMapExtension(context.TODO(), project)
+	assert.NilError(t, err)
+	template, err := b.convert(project, awsResources{cloudMap: cloudMap})
+	assert.NilError(t, err)
+
+	_, ok := template.Resources["ApiServiceDiscoveryEntry"]
+	assert.Assert(t, !ok)
+	definition := template.Resources["ApiTaskDefinition"].(*ecs.TaskDefinition)
+	for _, env := range definition.ContainerDefinitions[0].Environment {
+		assert.Assert(t, env.Name != "LOCALDOMAIN")
+	}
+
+	raw, err := marshall(template)
+	assert.NilError(t, err)
+	var parsed struct {
+		Resources map[string]struct {
+			DependsOn  []string
+			Metadata   map[string]interface{}
+			Properties struct {
+				ContainerDefinitions []struct {
+					Name         string
+					PortMappings []struct {
+						ContainerPort int
+						Name          string
+					}
+				}
+				ServiceConnectConfiguration struct {
+					Enabled   bool
+					Namespace string
+					Services  []struct {
+						PortName      string
+						DiscoveryName string
+						ClientAliases []struct {
+							Port    int
+							DNSName string `json:"DnsName"`
+						}
+					}
+				}
+			}
+		}
+	}
+	assert.NilError(t, json.Unmarshal(raw, &parsed))
+
+	api := parsed.Resources["ApiService"]
+	assert.Check(t, api.Metadata == nil)
+	assert.Check(t, api.Properties.ServiceConnectConfiguration.Enabled)
+	assert.Equal(t, api.Properties.ServiceConnectConfiguration.Namespace, "Test.local")
+	assert.Equal(t, len(api.Properties.ServiceConnectConfiguration.Services), 1)
+	server := api.Properties.ServiceConnectConfiguration.Services[0]
+	assert.Equal(t, server.PortName, "api-8080")
+	assert.Equal(t, server.ClientAliases[0].Port, 8080)
+	assert.Equal(t, server.ClientAliases[0].DNSName, "api")
+	assert.DeepEqual(t, api.DependsOn, []string{"CloudMap"})
+
+	worker := parsed.Resources["WorkerService"].Properties.ServiceConnectConfiguration
+	assert.Check(t, worker.Enabled)
+	assert.Equal(t, len(worker.Services), 0)
+
+	taskDefinition := parsed.Resources["ApiTaskDefinition"]
+	assert.Check(t, taskDefinition.Metadata == nil)
+	mappings := taskDefinition.Properties.ContainerDefinitions[0].PortMappings
+	assert.Equal(t, len(mappings), 1)
+	assert.Equal(t, mappings[0].ContainerPort, 8080)
+	assert.Equal(t, mappings[0].Name, "api-8080")
+	assert.Equal(t, parsed.Resources["WebTaskDefinition"].Properties.ContainerDefinitions[0].PortMappings[0].Name, "web-80")
+}
+
+func TestServiceConnectWithMesh(t *testing.T) {
+	project := loadConfig(t, `
+x-mesh: appmesh
+x-aws-cloudmap:
+  service_connect: true
+services:
+  test:
+    image: nginx
+`)
+	b := &ecsAPIService{}
+	cloudMap, err := b.parseCloudMapExtension(context.TODO(), project)
+	assert.NilError(t, err)
+	_, err = b.convert(project, awsResources{cloudMap: cloudMap})
+	assert.Assert(t, errdefs.IsErrParsingFailed(err))
+}
@@ -17,26 +17,39 @@
 package ecs
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/compose-spec/compose-go/compatibility"
 	"github.com/compose-spec/compose-go/errdefs"
 	"github.com/compose-spec/compose-go/types"
-	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/utils"
 )
 
-func (b *ecsAPIService) checkCompatibility(project *types.Project) error {
+func (b *ecsAPIService) checkCompatibility(ctx context.Context, project *types.Project) error {
 	var checker compatibility.Checker = &fargateCompatibilityChecker{
 		compatibility.AllowList{
 			Supported: compatibleComposeAttributes,
 		},
 	}
+	if b.ctx.External {
+		checker = &externalCompatibilityChecker{fargateCompatibilityChecker{
+			compatibility.AllowList{
+				Supported: append([]string{"services.network_mode"}, compatibleComposeAttributes...),
+			},
+		}}
+	}
 	compatibility.Check(project, checker)
 	for _, err := range checker.Errors() {
 		if errdefs.IsIncompatibleError(err) {
 			return err
 		}
-		logrus.Warn(err.Error())
+		compose.Warn(ctx, "%s", err.Error())
 	}
 	if !compatibility.IsCompatible(checker) {
 		return fmt.Errorf("compose file is incompatible with Amazon ECS")
@@ -50,6 +63,9 @@ type fargateCompatibilityChecker struct {
 
 var compatibleComposeAttributes = []string{
 	"services.command",
+	"services.configs",
+	"services.configs.source",
+	"services.configs.target",
 	"services.container_name",
 	"services.cap_drop",
 	"services.depends_on",
@@ -64,7 +80,17 @@ var compatibleComposeAttributes = []string{
 	"services.deploy.resources.reservations.generic_resources",
 	"services.deploy.resources.reservations.generic_resources.discrete_resource_spec",
 	"services.deploy.update_config",
+	"services.deploy.update_config.failure_action",
+	"services.deploy.update_config.order",
 	"services.deploy.update_config.parallelism",
+	"services.deploy.placement",
+	"services.deploy.placement.constraints",
+	"services.deploy.placement.preferences",
+	"services.deploy.restart_policy",
+	"services.deploy.restart_policy.condition",
+	"services.deploy.restart_policy.delay",
+	"services.deploy.restart_policy.max_attempts",
+	"services.deploy.restart_policy.window",
 	"services.entrypoint",
 	"services.environment",
 	"services.env_file",
@@ -83,15 +109,27 @@ var compatibleComposeAttributes = []string{
 	"services.ports.mode",
 	"services.ports.target",
 	"services.ports.protocol",
+	"services.read_only",
+	"services.platform",
 	"services.secrets",
 	"services.secrets.source",
 	"services.secrets.target",
+	"services.shm_size",
+	"services.stop_grace_period",
+	"services.sysctls",
+	"services.tmpfs",
+	"services.ulimits",
 	"services.user",
 	"services.volumes",
 	"services.volumes.read_only",
 	"services.volumes.source",
 	"services.volumes.target",
+	"services.volumes.tmpfs",
+	"services.volumes.tmpfs.size",
 	"services.working_dir",
+	"configs.external",
+	"configs.name",
+	"configs.file",
 	"secrets.external",
 	"secrets.name",
 	"secrets.file",
@@ -99,6 +137,14 @@ var compatibleComposeAttributes = []string{
 	"volumes.external",
 }
 
+func (c *fargateCompatibilityChecker) CheckDeployMode(deploy *types.DeployConfig) {
+	switch deploy.Mode {
+	case "", "replicated", compose.JobMode:
+	default:
+		c.Unsupported("services.deploy.mode %s is not supported", deploy.Mode)
+	}
+}
+
 func (c *fargateCompatibilityChecker) CheckImage(service *types.ServiceConfig) {
 	if service.Image == "" {
 		c.Incompatible("service %s doesn't define a Docker image to run", service.Name)
@@ -114,19 +160,96 @@ func (c *fargateCompatibilityChecker) CheckPortsPublished(p *types.ServicePortCo
 	}
 }
 
+// CheckCapAdd checks the capabilities added to a service, Fargate only allowing SYS_PTRACE while EC2 instances allow all
 func (c *fargateCompatibilityChecker) CheckCapAdd(service *types.ServiceConfig) {
+	if requireEC2(*service) {
+		return
+	}
 	add := []string{}
 	for _, cap := range service.CapAdd {
 		switch cap {
 		case "SYS_PTRACE":
 			add = append(add, cap)
 		default:
-			c.Incompatible("ECS doesn't allow to add capability %s", cap)
+			c.Incompatible("ECS doesn't allow to add capability %s to service %s running on Fargate", cap, service.Name)
 		}
 	}
 	service.CapAdd = add
 }
 
+func (c *fargateCompatibilityChecker) CheckSysctls(service *types.ServiceConfig) {
+	var names []string
+	for name := range service.Sysctls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !compose.IsNamespacedSysctl(name) {
+			c.Incompatible("service %s can't set sysctl %s, only namespaced kernel parameters can be set in ECS tasks", service.Name, name)
+		}
+	}
+}
+
+// ecsUlimits are the resource limits ECS container definitions can set
+var ecsUlimits = []string{
+	"core", "cpu", "data", "fsize", "locks", "memlock", "msgqueue", "nice",
+	"nofile", "nproc", "rss", "rtprio", "rttime", "sigpending", "stack",
+}
+
+func (c *fargateCompatibilityChecker) CheckUlimits(service *types.ServiceConfig) {
+	var names []string
+	for name := range service.Ulimits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !utils.StringContains(ecsUlimits, name) {
+			c.Incompatible("service %s can't set ulimit %s, ECS supports %s", service.Name, name, strings.Join(ecsUlimits, ", "))
+		}
+	}
+}
+
+// fargateMaxStopTimeout is the longest time Fargate waits for a container to exit before killing it
+const fargateMaxStopTimeout = 120 * time.Second
+
+func (c *fargateCompatibilityChecker) CheckStopGracePeriod(service *types.ServiceConfig) {
+	if service.StopGracePeriod == nil || requireEC2(*service) {
+		return
+	}
+	if time.Duration(*service.StopGracePeriod) > fargateMaxStopTimeout {
+		c.Unsupported("services.stop_grace_period of service %s is reduced to the Fargate maximum of %s", service.Name, fargateMaxStopTimeout)
+		max := types.Duration(fargateMaxStopTimeout)
+		service.StopGracePeriod = &max
+	}
+}
+
+func (c *fargateCompatibilityChecker) CheckStopSignal(service *types.ServiceConfig) {
+	if service.StopSignal != "" {
+		c.Unsupported("services.stop_signal %s of service %s is not supported, ECS stops containers with the image STOPSIGNAL, SIGTERM by default", service.StopSignal, service.Name)
+	}
+}
+
+func (c *fargateCompatibilityChecker) CheckTmpfs(service *types.ServiceConfig) {
+	if requireEC2(*service) {
+		return
+	}
+	tmpfs := len(service.Tmpfs) > 0
+	for _, v := range service.Volumes {
+		if v.Type == types.VolumeTypeTmpfs {
+			tmpfs = true
+		}
+	}
+	if tmpfs {
+		c.Unsupported("services.tmpfs of service %s are backed by task ephemeral storage as Fargate doesn't support tmpfs mounts", service.Name)
+	}
+}
+
+func (c *fargateCompatibilityChecker) CheckShmSize(service *types.ServiceConfig) {
+	if service.ShmSize != "" && !requireEC2(*service) {
+		c.Unsupported("services.shm_size of service %s is backed by task ephemeral storage as Fargate doesn't support shared memory size", service.Name)
+	}
+}
+
 func (c *fargateCompatibilityChecker) CheckLoggingDriver(config *types.LoggingConfig) {
 	if config.Driver != "" && config.Driver != "awslogs" {
 		c.Unsupported("services.logging.driver %s is not supported", config.Driver)
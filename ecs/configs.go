@@ -0,0 +1,90 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/ssm"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const (
+	// standardParameterSize is the maximum size of a Standard SSM parameter, larger configs use Advanced parameters
+	standardParameterSize = 4 * 1024
+	advancedParameterSize = 8 * 1024
+)
+
+// createConfig uploads a config file as a SSM parameter, named after the project so that it gets deleted with the
+// stack. The config name is replaced by the parameter ARN, which services containers read the config from.
+func (b *ecsAPIService) createConfig(project *types.Project, name string, c types.ConfigObjConfig, template *cloudformation.Template) error {
+	if c.External.External {
+		c.Name = parameterARN(c.Name)
+		project.Configs[name] = c
+		return nil
+	}
+	content, err := ioutil.ReadFile(c.File)
+	if err != nil {
+		return err
+	}
+	tier := "Standard"
+	switch {
+	case len(content) > advancedParameterSize:
+		return errors.Wrapf(errdefs.ErrNotImplemented, "config %q is larger than the %d bytes a SSM parameter can store", name, advancedParameterSize)
+	case len(content) > standardParameterSize:
+		tier = "Advanced"
+	}
+
+	resource := fmt.Sprintf("%sConfig", normalizeResourceName(name))
+	template.Resources[resource] = &ssm.Parameter{
+		Name:        fmt.Sprintf("/docker-compose/%s/%s", project.Name, name),
+		Description: fmt.Sprintf("Config %s", name),
+		Type:        "String",
+		Tier:        tier,
+		Value:       string(content),
+		Tags: map[string]string{
+			compose.ProjectTag: project.Name,
+		},
+	}
+	c.Name = cloudformation.Sub(fmt.Sprintf("arn:${AWS::Partition}:ssm:${AWS::Region}:${AWS::AccountId}:parameter${%s}", resource))
+	project.Configs[name] = c
+	return nil
+}
+
+// parameterARN returns the ARN of an existing SSM parameter, set by name or ARN
+func parameterARN(name string) string {
+	if strings.HasPrefix(name, "arn:") {
+		return name
+	}
+	if !strings.HasPrefix(name, "/") {
+		name = "/" + name
+	}
+	return cloudformation.Sub(fmt.Sprintf("arn:${AWS::Partition}:ssm:${AWS::Region}:${AWS::AccountId}:parameter%s", name))
+}
+
+// configEnvName is the variable the secrets init container reads a config from
+func configEnvName(config string) string {
+	return "CONFIG_" + strings.ToUpper(regexp.MustCompile("[^a-zA-Z0-9]+").ReplaceAllString(config, "_"))
+}
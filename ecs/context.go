@@ -51,6 +51,16 @@ func (h contextCreateAWSHelper) createContextData(_ context.Context, opts Contex
 	ecsCtx := store.EcsContext{
 		Profile: opts.Profile,
 		Region:  opts.Region,
+
+		ResolveImageDigests:   opts.ResolveImageDigests,
+		TracingEndpoint:       opts.TracingEndpoint,
+		ScanSeverity:          opts.ScanSeverity,
+		External:              opts.External,
+		Budget:                opts.Budget,
+		TagPolicy:             opts.TagPolicy,
+		DeploymentAnnotations: opts.Annotations,
+		Notifications:         opts.Notifications,
+		OperationSettings:     opts.Operations,
 	}
 
 	if h.missingRequiredFlags(ecsCtx) {
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"strconv"
@@ -34,13 +35,23 @@ import (
 	"github.com/compose-spec/compose-go/types"
 	"github.com/docker/cli/opts"
 	"github.com/joho/godotenv"
+	"github.com/pkg/errors"
 
+	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/ecs/secrets"
+	"github.com/docker/compose-cli/errdefs"
 )
 
-const secretsInitContainerImage = "docker/ecs-secrets-sidecar"
+// secretsInitContainerImage is built from ecs/secrets, its tag must be bumped
+// when the secrets passed to the init container change.
+const secretsInitContainerImage = "docker/ecs-secrets-sidecar:1.1.0"
 
-func (b *ecsAPIService) createTaskExecution(project *types.Project, service types.ServiceConfig) (*ecs.TaskDefinition, error) {
+func (b *ecsAPIService) createTaskExecution(project *types.Project, service types.ServiceConfig, resources awsResources) (*ecs.TaskDefinition, error) {
+	cloudMap := resources.cloudMap
+	platform, err := taskPlatform(project, service)
+	if err != nil {
+		return nil, err
+	}
 	cpu, mem, err := toLimits(service)
 	if err != nil {
 		return nil, err
@@ -48,14 +59,16 @@ func (b *ecsAPIService) createTaskExecution(project *types.Project, service type
 	_, memReservation := toContainerReservation(service)
 	credential := getRepoCredentials(service)
 
-	// override resolve.conf search directive to also search <project>.local
+	// override resolve.conf search directive to also search the Cloud Map namespace
 	// TODO remove once ECS support hostname-only service discovery
-	service.Environment["LOCALDOMAIN"] = aws.String(
-		cloudformation.Join("", []string{
-			cloudformation.Ref("AWS::Region"),
-			".compute.internal",
-			fmt.Sprintf(" %s.local", project.Name),
-		}))
+	if cloudMap.dns() && !cloudMap.serviceConnect && !resources.external {
+		service.Environment["LOCALDOMAIN"] = aws.String(
+			cloudformation.Join("", []string{
+				cloudformation.Ref("AWS::Region"),
+				".compute.internal",
+				" " + cloudMap.name,
+			}))
+	}
 
 	logConfiguration := getLogConfiguration(service, project)
 
@@ -63,15 +76,28 @@ func (b *ecsAPIService) createTaskExecution(project *types.Project, service type
 		initContainers []ecs.TaskDefinition_ContainerDefinition
 		volumes        []ecs.TaskDefinition_Volume
 		mounts         []ecs.TaskDefinition_MountPoint
+		envSecrets     []ecs.TaskDefinition_Secret
 	)
-	if len(service.Secrets) > 0 {
-		secretsVolume, secretsMount, secretsSideCar, err := createSecretsSideCar(project, service, logConfiguration)
+	injection, err := compose.SecretInjection(service)
+	if err != nil {
+		return nil, err
+	}
+	fileSecrets := service.Secrets
+	if len(service.Secrets) > 0 && injection == compose.SecretInjectionEnv {
+		envSecrets, err = createSecretsEnvironment(project, service)
+		if err != nil {
+			return nil, err
+		}
+		fileSecrets = nil
+	}
+	if len(fileSecrets) > 0 || len(service.Configs) > 0 {
+		secretsVolumes, secretsMounts, secretsSideCar, err := createSecretsSideCar(project, service, fileSecrets, logConfiguration)
 		if err != nil {
 			return nil, err
 		}
 		initContainers = append(initContainers, secretsSideCar)
-		volumes = append(volumes, secretsVolume)
-		mounts = append(mounts, secretsMount)
+		volumes = append(volumes, secretsVolumes...)
+		mounts = append(mounts, secretsMounts...)
 	}
 
 	var dependencies []ecs.TaskDefinition_ContainerDependency
@@ -82,7 +108,24 @@ func (b *ecsAPIService) createTaskExecution(project *types.Project, service type
 		})
 	}
 
+	tmpfs, err := serviceTmpfs(service)
+	if err != nil {
+		return nil, err
+	}
+	shmSize, err := serviceShmSize(service)
+	if err != nil {
+		return nil, err
+	}
+	if !requireEC2(service) {
+		ephemeralVolumes, ephemeralMounts := toEphemeralVolumes(tmpfs, shmSize)
+		volumes = append(volumes, ephemeralVolumes...)
+		mounts = append(mounts, ephemeralMounts...)
+	}
+
 	for _, v := range service.Volumes {
+		if v.Type == types.VolumeTypeTmpfs {
+			continue
+		}
 		source := project.Volumes[v.Source]
 		volumes = append(volumes, ecs.TaskDefinition_Volume{
 			EFSVolumeConfiguration: &ecs.TaskDefinition_EFSVolumeConfiguration{
@@ -125,7 +168,7 @@ func (b *ecsAPIService) createTaskExecution(project *types.Project, service type
 		Image:                  service.Image,
 		Interactive:            false,
 		Links:                  nil,
-		LinuxParameters:        toLinuxParameters(service),
+		LinuxParameters:        toLinuxParameters(service, tmpfs, shmSize),
 		LogConfiguration:       logConfiguration,
 		MemoryReservation:      memReservation,
 		MountPoints:            mounts,
@@ -136,6 +179,7 @@ func (b *ecsAPIService) createTaskExecution(project *types.Project, service type
 		ReadonlyRootFilesystem: service.ReadOnly,
 		RepositoryCredentials:  credential,
 		ResourceRequirements:   toTaskResourceRequirements(reservations),
+		Secrets:                envSecrets,
 		StartTimeout:           0,
 		StopTimeout:            durationToInt(service.StopGracePeriod),
 		SystemControls:         toSystemControls(service.Sysctls),
@@ -145,12 +189,18 @@ func (b *ecsAPIService) createTaskExecution(project *types.Project, service type
 		WorkingDirectory:       service.WorkingDir,
 	})
 
+	sidecars, err := createSideCars(project, service, logConfiguration)
+	if err != nil {
+		return nil, err
+	}
+	containers = append(containers, sidecars...)
+
 	launchType := ecsapi.LaunchTypeFargate
 	if requireEC2(service) {
 		launchType = ecsapi.LaunchTypeEc2
 	}
 
-	return &ecs.TaskDefinition{
+	definition := &ecs.TaskDefinition{
 		ContainerDefinitions: containers,
 		Cpu:                  cpu,
 		Family:               fmt.Sprintf("%s-%s", project.Name, service.Name),
@@ -158,13 +208,24 @@ func (b *ecsAPIService) createTaskExecution(project *types.Project, service type
 		Memory:               mem,
 		NetworkMode:          ecsapi.NetworkModeAwsvpc, // FIXME could be set by service.NetworkMode, Fargate only supports network mode ‘awsvpc’.
 		PidMode:              service.Pid,
-		PlacementConstraints: toPlacementConstraints(service.Deploy),
-		ProxyConfiguration:   nil,
 		RequiresCompatibilities: []string{
 			launchType,
 		},
 		Volumes: volumes,
-	}, nil
+	}
+	setRuntimePlatform(definition, platform)
+	if resources.external {
+		if err := setExternalTask(definition, service); err != nil {
+			return nil, err
+		}
+	}
+	if cloudMap.serviceConnect {
+		addServiceConnectPorts(definition, service)
+	}
+	if meshed(project, service) {
+		addEnvoyProxy(definition, service, logConfiguration)
+	}
+	return definition, nil
 }
 
 func toTaskResourceRequirements(reservations *types.Resource) []ecs.TaskDefinition_ResourceRequirement {
@@ -183,52 +244,131 @@ func toTaskResourceRequirements(reservations *types.Resource) []ecs.TaskDefiniti
 	return requirements
 }
 
-func createSecretsSideCar(project *types.Project, service types.ServiceConfig, logConfiguration *ecs.TaskDefinition_LogConfiguration) (
-	ecs.TaskDefinition_Volume,
-	ecs.TaskDefinition_MountPoint,
+// createSideCars converts the x-sidecars auxiliary containers injected into the service task. Sidecars share the
+// service logs configuration and are not essential, so the task keeps running if one exits
+func createSideCars(project *types.Project, service types.ServiceConfig, logConfiguration *ecs.TaskDefinition_LogConfiguration) ([]ecs.TaskDefinition_ContainerDefinition, error) {
+	sidecars, err := compose.Sidecars(project, service)
+	if err != nil {
+		return nil, err
+	}
+	var definitions []ecs.TaskDefinition_ContainerDefinition
+	for _, sidecar := range sidecars {
+		pairs, err := createEnvironment(project, sidecar)
+		if err != nil {
+			return nil, err
+		}
+		_, memReservation := toContainerReservation(sidecar)
+		definitions = append(definitions, ecs.TaskDefinition_ContainerDefinition{
+			Command:           sidecar.Command,
+			EntryPoint:        sidecar.Entrypoint,
+			Environment:       pairs,
+			Essential:         false,
+			HealthCheck:       toHealthCheck(sidecar.HealthCheck),
+			Image:             sidecar.Image,
+			LogConfiguration:  logConfiguration,
+			MemoryReservation: memReservation,
+			Name:              sidecar.Name,
+			PortMappings:      toPortMappings(sidecar.Ports),
+			User:              sidecar.User,
+			WorkingDirectory:  sidecar.WorkingDir,
+		})
+	}
+	return definitions, nil
+}
+
+// createSecretsSideCar creates the init container writing service secrets and configs as files. Secrets are written under
+// /run/secrets, unless their target is an absolute path: the target folder is then shared as a dedicated task volume.
+// Configs are always written at their target path.
+func createSecretsSideCar(project *types.Project, service types.ServiceConfig, fileSecrets []types.ServiceSecretConfig, logConfiguration *ecs.TaskDefinition_LogConfiguration) (
+	[]ecs.TaskDefinition_Volume,
+	[]ecs.TaskDefinition_MountPoint,
 	ecs.TaskDefinition_ContainerDefinition,
 	error) {
 	initContainerName := fmt.Sprintf("%s_Secrets_InitContainer", normalizeResourceName(service.Name))
-	secretsVolume := ecs.TaskDefinition_Volume{
-		Name: "secrets",
+	secretsVolumes := []ecs.TaskDefinition_Volume{
+		{
+			Name: "secrets",
+		},
 	}
-	secretsMount := ecs.TaskDefinition_MountPoint{
-		ContainerPath: "/run/secrets/",
-		ReadOnly:      true,
-		SourceVolume:  "secrets",
+	secretsMounts := []ecs.TaskDefinition_MountPoint{
+		{
+			ContainerPath: "/run/secrets/",
+			ReadOnly:      true,
+			SourceVolume:  "secrets",
+		},
+	}
+	sideCarMounts := []ecs.TaskDefinition_MountPoint{
+		{
+			ContainerPath: "/run/secrets/",
+			ReadOnly:      false,
+			SourceVolume:  "secrets",
+		},
 	}
 
 	var (
 		args        []secrets.Secret
 		taskSecrets []ecs.TaskDefinition_Secret
 	)
-	for _, s := range service.Secrets {
+	folders := map[string]bool{}
+	shareFolder := func(target string) {
+		folder := path.Dir(target)
+		if folders[folder] {
+			return
+		}
+		folders[folder] = true
+		volume := fmt.Sprintf("secrets%s", normalizeResourceName(folder))
+		secretsVolumes = append(secretsVolumes, ecs.TaskDefinition_Volume{
+			Name: volume,
+		})
+		secretsMounts = append(secretsMounts, ecs.TaskDefinition_MountPoint{
+			ContainerPath: folder,
+			ReadOnly:      true,
+			SourceVolume:  volume,
+		})
+		sideCarMounts = append(sideCarMounts, ecs.TaskDefinition_MountPoint{
+			ContainerPath: folder,
+			ReadOnly:      false,
+			SourceVolume:  volume,
+		})
+	}
+	for _, s := range fileSecrets {
 		secretConfig := project.Secrets[s.Source]
-		if s.Target == "" {
-			s.Target = s.Source
+		secret := secrets.Secret{
+			Name: compose.SecretEnvName(s),
+			Keys: secretKeys(secretConfig),
+		}
+		if err := compose.CheckSecretTarget(s); err != nil {
+			return nil, nil, ecs.TaskDefinition_ContainerDefinition{}, err
+		}
+		if path.IsAbs(s.Target) {
+			secret.Path = s.Target
+			shareFolder(s.Target)
 		}
 		taskSecrets = append(taskSecrets, ecs.TaskDefinition_Secret{
-			Name:      s.Target,
+			Name:      secret.Name,
 			ValueFrom: secretConfig.Name,
 		})
-		var keys []string
-		if ext, ok := secretConfig.Extensions[extensionKeys]; ok {
-			if key, ok := ext.(string); ok {
-				keys = append(keys, key)
-			} else {
-				for _, k := range ext.([]interface{}) {
-					keys = append(keys, k.(string))
-				}
-			}
+		args = append(args, secret)
+	}
+	for _, c := range service.Configs {
+		target, err := compose.ConfigTarget(c)
+		if err != nil {
+			return nil, nil, ecs.TaskDefinition_ContainerDefinition{}, err
 		}
-		args = append(args, secrets.Secret{
-			Name: s.Target,
-			Keys: keys,
+		shareFolder(target)
+		secret := secrets.Secret{
+			Name: configEnvName(c.Source),
+			Path: target,
+		}
+		taskSecrets = append(taskSecrets, ecs.TaskDefinition_Secret{
+			Name:      secret.Name,
+			ValueFrom: project.Configs[c.Source].Name,
 		})
+		args = append(args, secret)
 	}
 	command, err := json.Marshal(args)
 	if err != nil {
-		return ecs.TaskDefinition_Volume{}, ecs.TaskDefinition_MountPoint{}, ecs.TaskDefinition_ContainerDefinition{}, err
+		return nil, nil, ecs.TaskDefinition_ContainerDefinition{}, err
 	}
 	secretsSideCar := ecs.TaskDefinition_ContainerDefinition{
 		Name:             initContainerName,
@@ -236,16 +376,40 @@ func createSecretsSideCar(project *types.Project, service types.ServiceConfig, l
 		Command:          []string{string(command)},
 		Essential:        false, // FIXME this will be ignored, see https://github.com/awslabs/goformation/issues/61#issuecomment-625139607
 		LogConfiguration: logConfiguration,
-		MountPoints: []ecs.TaskDefinition_MountPoint{
-			{
-				ContainerPath: "/run/secrets/",
-				ReadOnly:      false,
-				SourceVolume:  "secrets",
-			},
-		},
-		Secrets: taskSecrets,
+		MountPoints:      sideCarMounts,
+		Secrets:          taskSecrets,
+	}
+	return secretsVolumes, secretsMounts, secretsSideCar, nil
+}
+
+// createSecretsEnvironment injects service secrets as environment variables, resolved by ECS on task startup
+func createSecretsEnvironment(project *types.Project, service types.ServiceConfig) ([]ecs.TaskDefinition_Secret, error) {
+	var taskSecrets []ecs.TaskDefinition_Secret
+	for _, s := range service.Secrets {
+		secretConfig := project.Secrets[s.Source]
+		if len(secretKeys(secretConfig)) > 0 {
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "secret %q: %s requires secrets to be mounted as files", s.Source, extensionKeys)
+		}
+		taskSecrets = append(taskSecrets, ecs.TaskDefinition_Secret{
+			Name:      compose.SecretEnvName(s),
+			ValueFrom: secretConfig.Name,
+		})
 	}
-	return secretsVolume, secretsMount, secretsSideCar, nil
+	return taskSecrets, nil
+}
+
+func secretKeys(secretConfig types.SecretConfig) []string {
+	var keys []string
+	if ext, ok := secretConfig.Extensions[extensionKeys]; ok {
+		if key, ok := ext.(string); ok {
+			keys = append(keys, key)
+		} else {
+			for _, k := range ext.([]interface{}) {
+				keys = append(keys, k.(string))
+			}
+		}
+	}
+	return keys
 }
 
 func createEnvironment(project *types.Project, service types.ServiceConfig) ([]ecs.TaskDefinition_KeyValuePair, error) {
@@ -318,6 +482,9 @@ func toSystemControls(sysctls types.Mapping) []ecs.TaskDefinition_SystemControl
 			Value:     v,
 		})
 	}
+	sort.Slice(sys, func(i, j int) bool {
+		return sys[i].Namespace < sys[j].Namespace
+	})
 	return sys
 }
 
@@ -340,18 +507,26 @@ func toLimits(service types.ServiceConfig) (string, string, error) {
 		return cpuLimit, memLimit, nil
 	}
 
-	// All possible cpu/mem values for Fargate
-	fargateCPUToMem := map[int64][]types.UnitBytes{
-		256:  {512, 1024, 2048},
-		512:  {1024, 2048, 3072, 4096},
-		1024: {2048, 3072, 4096, 5120, 6144, 7168, 8192},
-		2048: {4096, 5120, 6144, 7168, 8192, 9216, 10240, 11264, 12288, 13312, 14336, 15360, 16384},
-		4096: {8192, 9216, 10240, 11264, 12288, 13312, 14336, 15360, 16384, 17408, 18432, 19456, 20480, 21504, 22528, 23552, 24576, 25600, 26624, 27648, 28672, 29696, 30720},
+	cpuLimit, memLimit, ok := fargateLimits(cpu, mem)
+	if !ok {
+		return "", "", fmt.Errorf("the resources requested are not supported by ECS/Fargate")
 	}
-	cpuLimit := "256"
-	memLimit := "512"
+	return strconv.FormatInt(cpuLimit, 10), strconv.FormatInt(memLimit, 10), nil
+}
+
+// fargateCPUToMem lists all possible cpu/mem values for Fargate, in CPU units and MiB
+var fargateCPUToMem = map[int64][]int64{
+	256:  {512, 1024, 2048},
+	512:  {1024, 2048, 3072, 4096},
+	1024: {2048, 3072, 4096, 5120, 6144, 7168, 8192},
+	2048: {4096, 5120, 6144, 7168, 8192, 9216, 10240, 11264, 12288, 13312, 14336, 15360, 16384},
+	4096: {8192, 9216, 10240, 11264, 12288, 13312, 14336, 15360, 16384, 17408, 18432, 19456, 20480, 21504, 22528, 23552, 24576, 25600, 26624, 27648, 28672, 29696, 30720},
+}
+
+// fargateLimits returns the smallest Fargate cpu/mem combination covering the requested resources, in CPU units and MiB
+func fargateLimits(cpu int64, mem types.UnitBytes) (int64, int64, bool) {
 	if mem == 0 && cpu == 0 {
-		return cpuLimit, memLimit, nil
+		return 256, 512, true
 	}
 
 	var cpus []int64
@@ -364,15 +539,13 @@ func toLimits(service types.ServiceConfig) (string, string, error) {
 		options := fargateCPUToMem[fargateCPU]
 		if cpu <= fargateCPU {
 			for _, m := range options {
-				if mem <= m*miB {
-					cpuLimit = strconv.FormatInt(fargateCPU, 10)
-					memLimit = strconv.FormatInt(int64(m), 10)
-					return cpuLimit, memLimit, nil
+				if int64(mem) <= m*miB {
+					return fargateCPU, m, true
 				}
 			}
 		}
 	}
-	return "", "", fmt.Errorf("the resources requested are not supported by ECS/Fargate")
+	return 0, 0, false
 }
 
 func getConfiguredLimits(service types.ServiceConfig) (types.UnitBytes, int64, error) {
@@ -411,20 +584,6 @@ func toContainerReservation(service types.ServiceConfig) (string, int) {
 	return reservations.NanoCPUs, int(reservations.MemoryBytes / miB)
 }
 
-func toPlacementConstraints(deploy *types.DeployConfig) []ecs.TaskDefinition_TaskDefinitionPlacementConstraint {
-	if deploy == nil || deploy.Placement.Constraints == nil || len(deploy.Placement.Constraints) == 0 {
-		return nil
-	}
-	pl := []ecs.TaskDefinition_TaskDefinitionPlacementConstraint{}
-	for _, c := range deploy.Placement.Constraints {
-		pl = append(pl, ecs.TaskDefinition_TaskDefinitionPlacementConstraint{
-			Expression: c,
-			Type:       "",
-		})
-	}
-	return pl
-}
-
 func toPortMappings(ports []types.ServicePortConfig) []ecs.TaskDefinition_PortMapping {
 	if len(ports) == 0 {
 		return nil
@@ -446,39 +605,37 @@ func toUlimits(ulimits map[string]*types.UlimitsConfig) []ecs.TaskDefinition_Uli
 	}
 	u := []ecs.TaskDefinition_Ulimit{}
 	for k, v := range ulimits {
+		soft, hard := v.Soft, v.Hard
+		if v.Single != 0 {
+			// `nofile: 65535` sets both limits
+			soft, hard = v.Single, v.Single
+		}
 		u = append(u, ecs.TaskDefinition_Ulimit{
 			Name:      k,
-			SoftLimit: v.Soft,
-			HardLimit: v.Hard,
+			SoftLimit: soft,
+			HardLimit: hard,
 		})
 	}
+	sort.Slice(u, func(i, j int) bool {
+		return u[i].Name < u[j].Name
+	})
 	return u
 }
 
-func toLinuxParameters(service types.ServiceConfig) *ecs.TaskDefinition_LinuxParameters {
-	return &ecs.TaskDefinition_LinuxParameters{
+func toLinuxParameters(service types.ServiceConfig, tmpfs []tmpfsMount, shmSize int) *ecs.TaskDefinition_LinuxParameters {
+	parameters := &ecs.TaskDefinition_LinuxParameters{
 		Capabilities:       toKernelCapabilities(service.CapAdd, service.CapDrop),
 		Devices:            nil,
 		InitProcessEnabled: service.Init != nil && *service.Init,
 		MaxSwap:            0,
-		// FIXME SharedMemorySize:   service.ShmSize,
-		Swappiness: 0,
-		Tmpfs:      toTmpfs(service.Tmpfs),
+		Swappiness:         0,
 	}
-}
-
-func toTmpfs(tmpfs types.StringList) []ecs.TaskDefinition_Tmpfs {
-	if tmpfs == nil || len(tmpfs) == 0 {
-		return nil
-	}
-	o := []ecs.TaskDefinition_Tmpfs{}
-	for _, path := range tmpfs {
-		o = append(o, ecs.TaskDefinition_Tmpfs{
-			ContainerPath: path,
-			Size:          100, // size is required on ECS, unlimited by the compose spec
-		})
+	// Fargate doesn't support tmpfs nor shared memory size, those are backed by ephemeral volumes
+	if requireEC2(service) {
+		parameters.SharedMemorySize = shmSize
+		parameters.Tmpfs = toTmpfs(tmpfs)
 	}
-	return o
+	return parameters
 }
 
 func toKernelCapabilities(add []string, drop []string) *ecs.TaskDefinition_KernelCapabilities {
@@ -0,0 +1,108 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func (b *ecsAPIService) Estimate(ctx context.Context, project *types.Project) ([]compose.CostEstimate, error) {
+	cpuPrice, memoryPrice, err := b.SDK.GetFargatePrices(ctx, b.Region)
+	if err != nil {
+		return nil, err
+	}
+	estimates := []compose.CostEstimate{}
+	for _, service := range project.Services {
+		if requireEC2(service) {
+			logrus.Warnf("service %q runs on EC2 instances, which are not included in the estimate", service.Name)
+			continue
+		}
+		cpus, memory, replicas, err := fargateResources(service)
+		if err != nil {
+			return nil, err
+		}
+		estimates = append(estimates, compose.CostEstimate{
+			Service:  service.Name,
+			Replicas: replicas,
+			CPUs:     cpus,
+			MemoryGB: memory,
+			Hourly:   float64(replicas) * (cpus*cpuPrice + memory*memoryPrice),
+			Currency: "USD",
+		})
+	}
+	return estimates, nil
+}
+
+// checkBudget refuses projects requesting more resources than the context budget allows
+func (b *ecsAPIService) checkBudget(ctx context.Context, project *types.Project) error {
+	budget := b.ctx.Budget
+	if !budget.IsSet() {
+		return nil
+	}
+	var cpus, memory float64
+	for _, service := range project.Services {
+		c, m, replicas, err := fargateResources(service)
+		if err != nil {
+			return err
+		}
+		cpus += c * float64(replicas)
+		memory += m * float64(replicas)
+	}
+	var monthly float64
+	// only query prices when a cost limit is set
+	if budget.MaxMonthlyCost > 0 {
+		estimates, err := b.Estimate(ctx, project)
+		if err != nil {
+			return err
+		}
+		for _, e := range estimates {
+			monthly += e.Monthly()
+		}
+	}
+	return budget.Check(cpus, memory, monthly)
+}
+
+// fargateResources returns the CPUs and memory in GB of a service task, and its number of replicas
+func fargateResources(service types.ServiceConfig) (float64, float64, int, error) {
+	cpu, memory, err := toLimits(service)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	// Fargate task sizes are expressed in CPU units and MiB
+	var cpus, memoryGB float64
+	if cpu != "" {
+		if cpus, err = strconv.ParseFloat(cpu, 64); err != nil {
+			return 0, 0, 0, err
+		}
+	}
+	if memory != "" {
+		if memoryGB, err = strconv.ParseFloat(memory, 64); err != nil {
+			return 0, 0, 0, err
+		}
+	}
+	replicas := 1
+	if service.Deploy != nil && service.Deploy.Replicas != nil {
+		replicas = int(*service.Deploy.Replicas)
+	}
+	return cpus / 1024, memoryGB / 1024, replicas, nil
+}
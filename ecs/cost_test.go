@@ -0,0 +1,47 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"gotest.tools/v3/assert"
+)
+
+func TestOnDemandPrice(t *testing.T) {
+	var product aws.JSONValue
+	err := json.Unmarshal([]byte(`{
+		"product": {"attributes": {"usagetype": "EU-Fargate-vCPU-Hours:perCPU"}},
+		"terms": {"OnDemand": {"ABC.JRTCKXETXF": {"priceDimensions": {"ABC.JRTCKXETXF.6YS6EN2CT7": {"pricePerUnit": {"USD": "0.0445500000"}}}}}}
+	}`), &product)
+	assert.NilError(t, err)
+
+	usageType, price, ok := onDemandPrice(product)
+	assert.Assert(t, ok)
+	assert.Equal(t, usageType, "EU-Fargate-vCPU-Hours:perCPU")
+	assert.Equal(t, price, 0.04455)
+}
+
+func TestOnDemandPriceWithoutTerms(t *testing.T) {
+	product := aws.JSONValue{
+		"product": map[string]interface{}{"attributes": map[string]interface{}{"usagetype": "EU-Fargate-GB-Hours"}},
+	}
+	_, _, ok := onDemandPrice(product)
+	assert.Assert(t, !ok)
+}
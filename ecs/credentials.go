@@ -0,0 +1,108 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/compose-spec/compose-go/types"
+	clitypes "github.com/docker/cli/cli/config/types"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/api/secrets"
+	"github.com/docker/compose-cli/registry"
+)
+
+func registryCredentialsPrefix(project string) string {
+	return fmt.Sprintf("docker-compose/%s/", project)
+}
+
+func isEcrRegistry(domain string) bool {
+	return strings.Contains(domain, ".dkr.ecr.") && strings.HasSuffix(domain, ".amazonaws.com")
+}
+
+// createPullCredentials stores local registry credentials for private images as Secrets Manager secrets,
+// and declares them as x-aws-pull_credentials for services which don't set one explicitly
+func (b *ecsAPIService) createPullCredentials(ctx context.Context, project *types.Project) error {
+	domains := map[int]string{}
+	credentialsByDomain := map[string]clitypes.AuthConfig{}
+	for i, service := range project.Services {
+		if _, ok := service.Extensions[extensionPullCredentials]; ok {
+			continue
+		}
+		// services with only a build section have no image to pull, unless pushed to ECR beforehand
+		if service.Image == "" {
+			continue
+		}
+		domain, err := registry.Domain(service.Image)
+		if err != nil {
+			return err
+		}
+		if isEcrRegistry(domain) {
+			// ECR access is granted by the task execution role
+			continue
+		}
+		if _, ok := credentialsByDomain[domain]; !ok {
+			credentials, err := registry.Credentials(domain)
+			if err != nil {
+				return err
+			}
+			credentialsByDomain[domain] = credentials
+		}
+		if credentials := credentialsByDomain[domain]; credentials.Username == "" || credentials.Password == "" {
+			continue
+		}
+		domains[i] = domain
+	}
+
+	// secrets for distinct registries are created concurrently
+	var mu sync.Mutex
+	arns := map[string]string{}
+	g := compose.NewGraph()
+	for domain, credentials := range credentialsByDomain {
+		if credentials.Username == "" || credentials.Password == "" {
+			continue
+		}
+		domain, credentials := domain, credentials
+		g.Add(domain, func(ctx context.Context) error {
+			name := registryCredentialsPrefix(project.Name) + strings.ReplaceAll(domain, ":", "_")
+			secret := secrets.NewSecret(name, credentials.Username, credentials.Password, fmt.Sprintf("Registry credentials for %s", domain))
+			arn, err := b.SDK.PutRegistryCredentials(ctx, project.Name, secret)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			arns[domain] = arn
+			return nil
+		})
+	}
+	if err := g.Run(ctx, compose.MaxConcurrency()); err != nil {
+		return err
+	}
+
+	for i, domain := range domains {
+		if project.Services[i].Extensions == nil {
+			project.Services[i].Extensions = map[string]interface{}{}
+		}
+		project.Services[i].Extensions[extensionPullCredentials] = arns[domain]
+	}
+	return nil
+}
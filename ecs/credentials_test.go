@@ -0,0 +1,144 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"encoding/base64"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/compose-spec/compose-go/types"
+	cliconfig "github.com/docker/cli/cli/config"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+// fakeSecretsManager stores secrets by name, existing ones being updated
+type fakeSecretsManager struct {
+	secretsmanageriface.SecretsManagerAPI
+	lock    sync.Mutex
+	secrets map[string]string
+	tags    map[string][]*secretsmanager.Tag
+	updated []string
+}
+
+func (f *fakeSecretsManager) DescribeSecretWithContext(ctx aws.Context, input *secretsmanager.DescribeSecretInput, opts ...request.Option) (*secretsmanager.DescribeSecretOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if _, ok := f.secrets[aws.StringValue(input.SecretId)]; !ok {
+		return nil, awserr.New(secretsmanager.ErrCodeResourceNotFoundException, "not found", nil)
+	}
+	return &secretsmanager.DescribeSecretOutput{Name: input.SecretId}, nil
+}
+
+func (f *fakeSecretsManager) CreateSecretWithContext(ctx aws.Context, input *secretsmanager.CreateSecretInput, opts ...request.Option) (*secretsmanager.CreateSecretOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	name := aws.StringValue(input.Name)
+	f.secrets[name] = aws.StringValue(input.SecretString)
+	f.tags[name] = input.Tags
+	return &secretsmanager.CreateSecretOutput{ARN: aws.String("arn:" + name)}, nil
+}
+
+func (f *fakeSecretsManager) PutSecretValueWithContext(ctx aws.Context, input *secretsmanager.PutSecretValueInput, opts ...request.Option) (*secretsmanager.PutSecretValueOutput, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	name := aws.StringValue(input.SecretId)
+	f.secrets[name] = aws.StringValue(input.SecretString)
+	f.updated = append(f.updated, name)
+	return &secretsmanager.PutSecretValueOutput{ARN: aws.String("arn:" + name)}, nil
+}
+
+func TestCreatePullCredentials(t *testing.T) {
+	auth := func(username, password string) string {
+		return `{"auth": "` + base64.StdEncoding.EncodeToString([]byte(username+":"+password)) + `"}`
+	}
+	dir := fs.NewDir(t, "docker", fs.WithFile("config.json", `{"auths": {
+		"registry.example.com": `+auth("shop", "s3cr3t")+`,
+		"mirror.example.com:5000": `+auth("ci", "t0ken")+`,
+		"123456789012.dkr.ecr.eu-west-3.amazonaws.com": `+auth("AWS", "ecr")+`
+	}}`))
+	defer dir.Remove()
+	previous := cliconfig.Dir()
+	cliconfig.SetDir(dir.Path())
+	defer cliconfig.SetDir(previous)
+
+	cases := []struct {
+		service     types.ServiceConfig
+		credentials interface{}
+	}{
+		{
+			service:     types.ServiceConfig{Name: "web", Image: "registry.example.com/shop/web"},
+			credentials: "arn:docker-compose/shop/registry.example.com",
+		},
+		{
+			service:     types.ServiceConfig{Name: "worker", Image: "registry.example.com/shop/worker:1.2"},
+			credentials: "arn:docker-compose/shop/registry.example.com",
+		},
+		{
+			service:     types.ServiceConfig{Name: "cache", Image: "mirror.example.com:5000/redis"},
+			credentials: "arn:docker-compose/shop/mirror.example.com_5000",
+		},
+		{
+			// ECR access is granted by the task execution role
+			service: types.ServiceConfig{Name: "api", Image: "123456789012.dkr.ecr.eu-west-3.amazonaws.com/api"},
+		},
+		{
+			// no local credentials for Docker Hub
+			service: types.ServiceConfig{Name: "proxy", Image: "nginx"},
+		},
+		{
+			// nothing to pull for a service which is only built
+			service: types.ServiceConfig{Name: "builder", Build: &types.BuildConfig{Context: "."}},
+		},
+		{
+			service: types.ServiceConfig{Name: "admin", Image: "registry.example.com/shop/admin", Extensions: map[string]interface{}{
+				extensionPullCredentials: "arn:explicit",
+			}},
+			credentials: "arn:explicit",
+		},
+	}
+	project := &types.Project{Name: "shop"}
+	for _, c := range cases {
+		project.Services = append(project.Services, c.service)
+	}
+	sm := &fakeSecretsManager{
+		secrets: map[string]string{"docker-compose/shop/mirror.example.com_5000": "{}"},
+		tags:    map[string][]*secretsmanager.Tag{},
+	}
+	b := &ecsAPIService{SDK: sdk{SM: sm}}
+	assert.NilError(t, b.createPullCredentials(context.Background(), project))
+
+	for i, c := range cases {
+		service := project.Services[i]
+		assert.Equal(t, service.Extensions[extensionPullCredentials], c.credentials, service.Name)
+	}
+	assert.DeepEqual(t, sm.secrets, map[string]string{
+		"docker-compose/shop/registry.example.com":    `{"password":"s3cr3t","username":"shop"}`,
+		"docker-compose/shop/mirror.example.com_5000": `{"password":"t0ken","username":"ci"}`,
+	})
+	assert.DeepEqual(t, sm.updated, []string{"docker-compose/shop/mirror.example.com_5000"})
+	tags := sm.tags["docker-compose/shop/registry.example.com"]
+	assert.Equal(t, len(tags), 1)
+	assert.Equal(t, aws.StringValue(tags[0].Value), "shop")
+}
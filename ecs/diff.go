@@ -0,0 +1,61 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/compose-spec/compose-go/types"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+// Diff compares the CloudFormation template of the deployed stack with the one generated from the compose file, and
+// runs drift detection to report resources edited outside of CloudFormation, typically from the AWS console
+func (b *ecsAPIService) Diff(ctx context.Context, project *types.Project) (compose.ProjectDiff, error) {
+	deployed, err := b.SDK.GetTemplate(ctx, project.Name)
+	if err != nil {
+		return compose.ProjectDiff{}, err
+	}
+	local, err := b.Convert(ctx, project)
+	if err != nil {
+		return compose.ProjectDiff{}, err
+	}
+	drifts, err := b.SDK.DetectStackDrift(ctx, project.Name)
+	if err != nil {
+		return compose.ProjectDiff{}, err
+	}
+	return compose.ProjectDiff{
+		Deployed: normalizeTemplate(deployed),
+		Local:    local,
+		Drifts:   drifts,
+	}, nil
+}
+
+// normalizeTemplate indents a JSON template the way Convert does, so that only actual changes are reported
+func normalizeTemplate(template []byte) []byte {
+	var unmarshalled interface{}
+	if err := json.Unmarshal(template, &unmarshalled); err != nil {
+		return template
+	}
+	indented, err := json.MarshalIndent(unmarshalled, "", "  ")
+	if err != nil {
+		return template
+	}
+	return indented
+}
@@ -0,0 +1,128 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+// Discovery describes Cloud Map registrations: each service registers its tasks IPs as A records in the
+// project namespace, which is also a search domain of the containers so that bare service names resolve
+func (b *ecsAPIService) Discovery(ctx context.Context, project *types.Project) ([]compose.ServiceDiscovery, error) {
+	cloudMap, err := b.parseCloudMapExtension(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	namespace := cloudMap.name
+	if namespace == "" {
+		namespace = cloudMapNamespace(project)
+	}
+	if cloudMap.serviceConnect {
+		return serviceConnectDiscovery(project, namespace), nil
+	}
+	if !cloudMap.dns() {
+		return nil, errors.Errorf("services are registered in API-only Cloud Map namespace %s and can't be resolved by DNS", namespace)
+	}
+	discovery := []compose.ServiceDiscovery{}
+	for _, service := range project.Services {
+		discovery = append(discovery, compose.ServiceDiscovery{
+			Service:   service.Name,
+			Hostnames: []string{service.Name, fmt.Sprintf("%s.%s", service.Name, namespace)},
+			Address:   "task private IPs (A records)",
+			Ports:     compose.ServicePorts(service),
+			Mechanism: fmt.Sprintf("Cloud Map namespace %s", namespace),
+		})
+	}
+	return discovery, nil
+}
+
+// serviceConnectDiscovery describes Service Connect routing: the proxy of each task resolves the names of services
+// exposing ports, and load balances connections to their tasks
+func serviceConnectDiscovery(project *types.Project, namespace string) []compose.ServiceDiscovery {
+	discovery := []compose.ServiceDiscovery{}
+	for _, service := range project.Services {
+		ports := compose.ServicePorts(service)
+		if len(ports) == 0 {
+			continue
+		}
+		discovery = append(discovery, compose.ServiceDiscovery{
+			Service:   service.Name,
+			Hostnames: []string{service.Name},
+			Address:   "Service Connect proxy",
+			Ports:     ports,
+			Mechanism: fmt.Sprintf("ECS Service Connect namespace %s", namespace),
+		})
+	}
+	return discovery
+}
+
+// Exposure describes the ingress rules generated for service ports: published ports are open to the world on non
+// internal networks, other ports are reachable by services attached to the same networks
+func (b *ecsAPIService) Exposure(ctx context.Context, project *types.Project) ([]compose.PortExposure, error) {
+	loadBalancerType := getRequiredLoadBalancerType(project)
+	exposure := []compose.PortExposure{}
+	for _, service := range project.Services {
+		var networks, public []string
+		for net := range service.Networks {
+			networks = append(networks, net)
+			if !project.Networks[net].Internal {
+				public = append(public, net)
+			}
+		}
+		sort.Strings(networks)
+		sort.Strings(public)
+		internal := fmt.Sprintf("services on networks %s", strings.Join(networks, ", "))
+		extra, err := serviceSecurityGroupExtension(service)
+		if err != nil {
+			return nil, err
+		}
+		if len(extra) > 0 {
+			internal = fmt.Sprintf("%s, rules of security groups %s", internal, strings.Join(extra, ", "))
+		}
+
+		for _, port := range service.Ports {
+			source := internal
+			if len(public) > 0 {
+				source = fmt.Sprintf("0.0.0.0/0 through %s load balancer on networks %s", loadBalancerType, strings.Join(public, ", "))
+			}
+			exposure = append(exposure, compose.PortExposure{
+				Service:  service.Name,
+				Port:     port.Target,
+				Protocol: compose.PortProtocol(port),
+				Source:   source,
+			})
+		}
+		for _, port := range compose.InternalPorts(service) {
+			exposure = append(exposure, compose.PortExposure{
+				Service:  service.Name,
+				Port:     port,
+				Protocol: "tcp",
+				Source:   internal,
+			})
+		}
+	}
+	compose.SortExposure(exposure)
+	return exposure, nil
+}
@@ -0,0 +1,128 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/certificatemanager"
+	"github.com/awslabs/goformation/v4/cloudformation/elasticloadbalancingv2"
+	"github.com/awslabs/goformation/v4/cloudformation/route53"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// domainConfig is the custom domain of a service set by x-aws-domain
+type domainConfig struct {
+	name        string
+	certificate string
+	hostedZone  string
+}
+
+func parseDomainExtension(service types.ServiceConfig) (*domainConfig, error) {
+	x, ok := service.Extensions[extensionDomain]
+	if !ok {
+		return nil, nil
+	}
+	domain := &domainConfig{}
+	switch v := x.(type) {
+	case string:
+		domain.name = v
+	case map[string]interface{}:
+		for key, value := range v {
+			var valid bool
+			switch key {
+			case "name":
+				domain.name, valid = value.(string)
+			case "certificate":
+				domain.certificate, valid = value.(string)
+			case "hosted_zone":
+				domain.hostedZone, valid = value.(string)
+			default:
+				return nil, errors.Wrapf(errdefs.ErrParsingFailed, "unsupported %s attribute %q", extensionDomain, key)
+			}
+			if !valid {
+				return nil, errors.Wrapf(errdefs.ErrParsingFailed, "invalid %s %s for service %s: %v", extensionDomain, key, service.Name, value)
+			}
+		}
+	default:
+		return nil, errors.Wrapf(errdefs.ErrParsingFailed, "invalid %s for service %s: %v", extensionDomain, service.Name, x)
+	}
+	if domain.name == "" {
+		return nil, errors.Wrapf(errdefs.ErrParsingFailed, "%s for service %s requires a domain name", extensionDomain, service.Name)
+	}
+	return domain, nil
+}
+
+// createDomain returns the certificate for the service HTTPS listener, requesting a DNS validated one to ACM when the
+// service doesn't set an existing certificate, and creates the Route53 alias of the domain to the load balancer when a
+// hosted zone is set
+func (b *ecsAPIService) createDomain(project *types.Project, service types.ServiceConfig, domain domainConfig, template *cloudformation.Template, resources awsResources) (string, error) {
+	if resources.loadBalancerType != elbv2.LoadBalancerTypeEnumApplication {
+		return "", errors.Wrapf(errdefs.ErrNotImplemented, "service %s can't use %s without an application load balancer", service.Name, extensionDomain)
+	}
+	if !exposesPort(service, 443) {
+		return "", errors.Wrapf(errdefs.ErrParsingFailed, "service %s must expose port 443 to use %s", service.Name, extensionDomain)
+	}
+
+	certificate := domain.certificate
+	if certificate == "" {
+		resource := fmt.Sprintf("%sCertificate", normalizeResourceName(service.Name))
+		template.Resources[resource] = &certificatemanager.Certificate{
+			DomainName: domain.name,
+			DomainValidationOptions: []certificatemanager.Certificate_DomainValidationOption{
+				{
+					DomainName:   domain.name,
+					HostedZoneId: domain.hostedZone,
+				},
+			},
+			Tags:             projectTags(project),
+			ValidationMethod: "DNS",
+		}
+		certificate = cloudformation.Ref(resource)
+	}
+
+	if domain.hostedZone != "" {
+		if _, ok := project.Extensions[extensionLoadBalancer]; ok {
+			return "", errors.Wrapf(errdefs.ErrNotImplemented, "%s hosted_zone can't be set for an existing load balancer", extensionDomain)
+		}
+		template.Resources[fmt.Sprintf("%sDNSRecord", normalizeResourceName(service.Name))] = &route53.RecordSet{
+			AliasTarget: &route53.RecordSet_AliasTarget{
+				DNSName:      cloudformation.GetAtt("LoadBalancer", "DNSName"),
+				HostedZoneId: cloudformation.GetAtt("LoadBalancer", "CanonicalHostedZoneID"),
+			},
+			HostedZoneId: domain.hostedZone,
+			Name:         domain.name,
+			Type:         "A",
+		}
+	}
+	return certificate, nil
+}
+
+// setListenerCertificate turns a service listener into an HTTPS one terminating TLS with the certificate
+func setListenerCertificate(listener *elasticloadbalancingv2.Listener, certificate string) {
+	listener.Protocol = elbv2.ProtocolEnumHttps
+	listener.Certificates = []elasticloadbalancingv2.Listener_Certificate{
+		{
+			CertificateArn: certificate,
+		},
+	}
+}
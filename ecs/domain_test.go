@@ -0,0 +1,90 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/certificatemanager"
+	"github.com/awslabs/goformation/v4/cloudformation/elasticloadbalancingv2"
+	"github.com/awslabs/goformation/v4/cloudformation/route53"
+	"gotest.tools/v3/assert"
+)
+
+func TestDomainWithCertificate(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  test:
+    image: nginx
+    ports:
+      - 443:443
+    x-aws-domain:
+      name: app.example.com
+      certificate: arn:aws:acm:eu-west-3:123456789012:certificate/abc
+`)
+	listener := template.Resources["TestTCP443Listener"].(*elasticloadbalancingv2.Listener)
+	assert.Equal(t, listener.Protocol, elbv2.ProtocolEnumHttps)
+	assert.DeepEqual(t, listener.Certificates, []elasticloadbalancingv2.Listener_Certificate{
+		{CertificateArn: "arn:aws:acm:eu-west-3:123456789012:certificate/abc"},
+	})
+	targetGroup := template.Resources["TestTCP443TargetGroup"].(*elasticloadbalancingv2.TargetGroup)
+	assert.Equal(t, targetGroup.Protocol, elbv2.ProtocolEnumHttp)
+	_, ok := template.Resources["TestCertificate"]
+	assert.Check(t, !ok)
+	_, ok = template.Resources["TestDNSRecord"]
+	assert.Check(t, !ok)
+}
+
+func TestDomainWithHostedZone(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  test:
+    image: nginx
+    ports:
+      - 443:443
+    x-aws-domain:
+      name: app.example.com
+      hosted_zone: Z0123456789
+`)
+	certificate := template.Resources["TestCertificate"].(*certificatemanager.Certificate)
+	assert.Equal(t, certificate.DomainName, "app.example.com")
+	assert.Equal(t, certificate.ValidationMethod, "DNS")
+	assert.Equal(t, certificate.DomainValidationOptions[0].HostedZoneId, "Z0123456789")
+
+	listener := template.Resources["TestTCP443Listener"].(*elasticloadbalancingv2.Listener)
+	assert.Equal(t, listener.Certificates[0].CertificateArn, cloudformation.Ref("TestCertificate"))
+
+	record := template.Resources["TestDNSRecord"].(*route53.RecordSet)
+	assert.Equal(t, record.Name, "app.example.com")
+	assert.Equal(t, record.Type, "A")
+	assert.Equal(t, record.AliasTarget.DNSName, cloudformation.GetAtt("LoadBalancer", "DNSName"))
+}
+
+func TestDomainRequiresHTTPSPort(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  test:
+    image: nginx
+    ports:
+      - 80:80
+    x-aws-domain: app.example.com
+`)
+	_, err := (&ecsAPIService{}).convert(project, awsResources{})
+	assert.ErrorContains(t, err, "service test must expose port 443 to use x-aws-domain")
+}
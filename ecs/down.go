@@ -18,12 +18,137 @@ package ecs
 
 import (
 	"context"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/progress"
+	"github.com/docker/compose-cli/utils/dockercli"
 )
 
-func (b *ecsAPIService) Down(ctx context.Context, project string) error {
+func (b *ecsAPIService) Down(ctx context.Context, project string, options compose.DownOptions) error {
+	regions, err := b.stackRegions(ctx, project)
+	if err != nil {
+		return permissionError("down", err)
+	}
+	if len(regions) > 0 {
+		return b.downRegions(ctx, project, regions, options)
+	}
+	return permissionError("down", b.down(ctx, project, options))
+}
+
+func (b *ecsAPIService) down(ctx context.Context, project string, options compose.DownOptions) error {
+	var images []string
+	if options.RemoveImages == compose.RemoveImagesAll {
+		deployed, err := b.deployedImages(ctx, project)
+		if err != nil {
+			return err
+		}
+		images = append(images, deployed...)
+	}
+	if options.RemoveOrphans {
+		// orphan tasks must be stopped first so the stack cluster can be deleted
+		if err := b.removeOrphans(ctx, project); err != nil {
+			return err
+		}
+	}
+
 	err := b.SDK.DeleteStack(ctx, project)
 	if err != nil {
 		return err
 	}
-	return b.WaitStackCompletion(ctx, project, stackDelete)
+	err = b.WaitStackCompletion(ctx, project, stackDelete)
+	if err != nil {
+		if ctx.Err() != nil {
+			return b.canceledDeletion(compose.Detach(ctx), project)
+		}
+		return err
+	}
+	err = b.SDK.DeleteRegistryCredentials(ctx, project)
+	if err != nil {
+		return err
+	}
+
+	if options.RemoveVolumes {
+		if err := b.removeVolumes(ctx, project); err != nil {
+			return err
+		}
+	}
+	if options.RemoveImages != "" {
+		built, err := dockercli.ProjectImages(ctx, project)
+		if err != nil {
+			return err
+		}
+		return dockercli.RemoveImages(ctx, append(built, images...))
+	}
+	return nil
+}
+
+func (b *ecsAPIService) deployedImages(ctx context.Context, project string) ([]string, error) {
+	resources, err := b.SDK.ListStackResources(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	var definitions []string
+	for _, r := range resources {
+		if r.Type == "AWS::ECS::TaskDefinition" {
+			definitions = append(definitions, r.ARN)
+		}
+	}
+	return b.SDK.GetTaskDefinitionImages(ctx, definitions)
+}
+
+func (b *ecsAPIService) removeOrphans(ctx context.Context, project string) error {
+	cluster, _, err := b.deployedServices(ctx, project)
+	if err != nil {
+		return err
+	}
+	tasks, err := b.SDK.ListOrphanTasks(ctx, cluster, project)
+	if err != nil {
+		return err
+	}
+	w := progress.ContextWriter(ctx)
+	for _, task := range tasks {
+		w.Event(progress.Event{
+			ID:         task,
+			Status:     progress.Working,
+			StatusText: "Stopping",
+		})
+		if err := b.SDK.StopTask(ctx, cluster, task, "compose down --remove-orphans"); err != nil {
+			return err
+		}
+		w.Event(progress.Event{
+			ID:         task,
+			Status:     progress.Done,
+			StatusText: "Stopped",
+		})
+	}
+	return nil
+}
+
+func (b *ecsAPIService) removeVolumes(ctx context.Context, project string) error {
+	fileSystems, err := b.SDK.ListProjectFileSystems(ctx, project)
+	if err != nil {
+		return err
+	}
+	w := progress.ContextWriter(ctx)
+	for _, id := range fileSystems {
+		w.Event(progress.Event{
+			ID:         id,
+			Status:     progress.Working,
+			StatusText: "Deleting",
+		})
+		if err := b.SDK.DeleteFileSystem(ctx, id); err != nil {
+			w.Event(progress.Event{
+				ID:         id,
+				Status:     progress.Error,
+				StatusText: "Deleting",
+			})
+			return err
+		}
+		w.Event(progress.Event{
+			ID:         id,
+			Status:     progress.Done,
+			StatusText: "Deleted",
+		})
+	}
+	return nil
 }
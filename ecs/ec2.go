@@ -29,6 +29,9 @@ import (
 )
 
 func (b *ecsAPIService) createCapacityProvider(ctx context.Context, project *types.Project, template *cloudformation.Template, resources awsResources) error {
+	if resources.external {
+		return nil
+	}
 	var ec2 bool
 	for _, s := range project.Services {
 		if requireEC2(s) {
@@ -0,0 +1,31 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"strings"
+)
+
+// parameterStoreScheme is the x-env-from scheme of Parameter Store paths, as ssm://<path>/
+const parameterStoreScheme = "ssm://"
+
+// pullParameters reads the environment of services from the parameters under a Parameter Store path
+func (b *ecsAPIService) pullParameters(ctx context.Context, source string) (map[string]string, error) {
+	path := "/" + strings.Trim(source, "/") + "/"
+	return b.SDK.GetParametersByPath(ctx, path)
+}
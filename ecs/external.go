@@ -0,0 +1,131 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"fmt"
+
+	ecsapi "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// launchTypeExternal runs tasks on the ECS Anywhere instances registered in the cluster
+const launchTypeExternal = "EXTERNAL"
+
+// externalCompatibilityChecker checks compose files deployed on ECS Anywhere instances, which run containers with the
+// bridge or host network, publishing ports on the instances
+type externalCompatibilityChecker struct {
+	fargateCompatibilityChecker
+}
+
+// CheckNetworkMode accepts the network modes of ECS Anywhere tasks, bridge by default
+func (c *externalCompatibilityChecker) CheckNetworkMode(service *types.ServiceConfig) {
+	switch service.NetworkMode {
+	case "", ecsapi.NetworkModeBridge, ecsapi.NetworkModeHost:
+	default:
+		c.Incompatible("service %s network_mode %s isn't supported on ECS Anywhere, only bridge and host are", service.Name, service.NetworkMode)
+	}
+}
+
+// CheckPortsPublished accepts any published port, ports being published on the instances, on a random port when unset
+func (c *externalCompatibilityChecker) CheckPortsPublished(p *types.ServicePortConfig) {
+}
+
+// checkExternalProject rejects the features which require a VPC, a load balancer or Fargate, which ECS Anywhere
+// instances don't use. Instances are registered in an existing cluster, the project must set it.
+func checkExternalProject(project *types.Project) error {
+	if _, ok := project.Extensions[extensionCluster]; !ok {
+		return errors.Wrapf(errdefs.ErrParsingFailed, "ECS Anywhere instances are registered in an existing cluster, set it with %s", extensionCluster)
+	}
+	for _, x := range []string{extensionVPC, extensionLoadBalancer, extensionLoadBalancerIdleTimeout, extensionCloudMap, extensionMesh,
+		extensionWAF, extensionDeniedCIDRs, extensionAllowedCIDRs} {
+		if _, ok := project.Extensions[x]; ok {
+			return errors.Wrapf(errdefs.ErrNotImplemented, "%s isn't supported on ECS Anywhere", x)
+		}
+	}
+	if len(project.Volumes) > 0 {
+		return errors.Wrap(errdefs.ErrNotImplemented, "EFS volumes aren't supported on ECS Anywhere")
+	}
+	for name, network := range project.Networks {
+		if _, ok := network.Extensions[extensionSubnets]; ok {
+			return errors.Wrapf(errdefs.ErrNotImplemented, "network %s: %s isn't supported on ECS Anywhere", name, extensionSubnets)
+		}
+	}
+	for _, service := range project.Services {
+		if compose.IsJob(service) || compose.IsScheduled(service) {
+			return errors.Wrapf(errdefs.ErrNotImplemented, "service %s: jobs and scheduled services aren't supported on ECS Anywhere", service.Name)
+		}
+		for _, x := range []string{extensionDomain, extensionTargetGroup, extensionServiceSecurityGroup} {
+			if _, ok := service.Extensions[x]; ok {
+				return errors.Wrapf(errdefs.ErrNotImplemented, "service %s: %s isn't supported on ECS Anywhere", service.Name, x)
+			}
+		}
+	}
+	return nil
+}
+
+// externalNetworkMode is the network mode of a service task on ECS Anywhere instances
+func externalNetworkMode(service types.ServiceConfig) string {
+	if service.NetworkMode == ecsapi.NetworkModeHost {
+		return ecsapi.NetworkModeHost
+	}
+	return ecsapi.NetworkModeBridge
+}
+
+// setExternalTask runs a task definition on ECS Anywhere instances, with the resources the service sets rather than
+// a Fargate task size. Services publish their ports on the instances, the host network using the container ports.
+func setExternalTask(definition *ecs.TaskDefinition, service types.ServiceConfig) error {
+	definition.RequiresCompatibilities = []string{launchTypeExternal}
+	definition.NetworkMode = externalNetworkMode(service)
+	mem, cpu, err := getConfiguredLimits(service)
+	if err != nil {
+		return err
+	}
+	definition.Cpu, definition.Memory = "", ""
+	if cpu > 0 {
+		// limits are in millicpus, ECS counts 1024 units per vCPU
+		definition.Cpu = fmt.Sprint(cpu * 1024 / 1000)
+	}
+	if mem > 0 {
+		definition.Memory = fmt.Sprint(mem / miB)
+	}
+	if definition.NetworkMode != ecsapi.NetworkModeHost {
+		return nil
+	}
+	for i, container := range definition.ContainerDefinitions {
+		for j, mapping := range container.PortMappings {
+			definition.ContainerDefinitions[i].PortMappings[j].HostPort = mapping.ContainerPort
+		}
+	}
+	return nil
+}
+
+// externalPlacement spreads the tasks of services publishing ports on distinct instances, as two tasks can't publish
+// the same port on an instance
+func externalPlacement(service types.ServiceConfig) []ecs.Service_PlacementConstraint {
+	for _, port := range service.Ports {
+		if port.Published != 0 || service.NetworkMode == ecsapi.NetworkModeHost {
+			return []ecs.Service_PlacementConstraint{{Type: ecsapi.PlacementConstraintTypeDistinctInstance}}
+		}
+	}
+	return nil
+}
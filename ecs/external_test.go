@@ -0,0 +1,121 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	ecsapi "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestExternalLaunchType(t *testing.T) {
+	project := loadConfig(t, `
+x-aws-cluster: arn:aws:ecs:us-east-1:012345678912:cluster/onprem
+services:
+  web:
+    image: nginx
+    ports:
+      - 8080:80
+    deploy:
+      resources:
+        limits:
+          cpus: '0.5'
+          memory: 256M
+  agent:
+    image: agent
+    network_mode: host
+    ports:
+      - 9100:9100
+`)
+	b := &ecsAPIService{}
+	template, err := b.convert(project, awsResources{external: true, cluster: "arn:aws:ecs:us-east-1:012345678912:cluster/onprem"})
+	assert.NilError(t, err)
+
+	_, ok := template.Resources["LoadBalancer"]
+	assert.Assert(t, !ok)
+	_, ok = template.Resources["CloudMap"]
+	assert.Assert(t, !ok)
+
+	web := template.Resources["WebService"].(*ecs.Service)
+	assert.Equal(t, web.LaunchType, launchTypeExternal)
+	assert.Equal(t, web.PlatformVersion, "")
+	assert.Assert(t, web.NetworkConfiguration == nil)
+	assert.Equal(t, len(web.LoadBalancers), 0)
+	assert.Equal(t, len(web.ServiceRegistries), 0)
+	assert.Equal(t, web.PlacementConstraints[0].Type, ecsapi.PlacementConstraintTypeDistinctInstance)
+
+	definition := template.Resources["WebTaskDefinition"].(*ecs.TaskDefinition)
+	assert.DeepEqual(t, definition.RequiresCompatibilities, []string{launchTypeExternal})
+	assert.Equal(t, definition.NetworkMode, ecsapi.NetworkModeBridge)
+	assert.Equal(t, definition.Cpu, "512")
+	assert.Equal(t, definition.Memory, "256")
+	assert.Equal(t, definition.ContainerDefinitions[0].PortMappings[0].HostPort, 8080)
+	for _, env := range definition.ContainerDefinitions[0].Environment {
+		assert.Assert(t, env.Name != "LOCALDOMAIN")
+	}
+
+	agent := template.Resources["AgentTaskDefinition"].(*ecs.TaskDefinition)
+	assert.Equal(t, agent.NetworkMode, ecsapi.NetworkModeHost)
+	assert.Equal(t, agent.ContainerDefinitions[0].PortMappings[0].HostPort, 9100)
+}
+
+func TestExternalRequiresCluster(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  web:
+    image: nginx
+`)
+	err := checkExternalProject(project)
+	assert.Assert(t, errdefs.IsErrParsingFailed(err))
+}
+
+func TestExternalUnsupportedFeatures(t *testing.T) {
+	for name, yaml := range map[string]string{
+		"load balancer": `
+x-aws-cluster: onprem
+x-aws-loadbalancer: arn:aws:elasticloadbalancing:us-east-1:012345678912:loadbalancer/app/lb/123
+services:
+  web:
+    image: nginx
+`,
+		"volume": `
+x-aws-cluster: onprem
+services:
+  web:
+    image: nginx
+    volumes:
+      - data:/data
+volumes:
+  data:
+`,
+		"domain": `
+x-aws-cluster: onprem
+services:
+  web:
+    image: nginx
+    x-aws-domain: example.com
+`,
+	} {
+		project := loadConfig(t, yaml)
+		err := checkExternalProject(project)
+		assert.Assert(t, errdefs.IsErrNotImplemented(err), name)
+	}
+}
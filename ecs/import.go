@@ -0,0 +1,246 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	ecsapi "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/progress"
+)
+
+// Import generates a compose project from existing ECS services, and tags them as imported in the project. Existing
+// services can't be moved into the project CloudFormation stack, so the next `compose up` deploys the stack in the
+// same cluster then deletes the imported services it replaces.
+func (b *ecsAPIService) Import(ctx context.Context, options compose.ImportOptions) (*types.Project, error) {
+	if len(options.Resources) == 0 {
+		return nil, errors.Wrap(errdefs.ErrParsingFailed, "no service to import")
+	}
+	var (
+		cluster  string
+		services []string
+	)
+	for _, resource := range options.Resources {
+		c, s, err := parseServiceResource(resource)
+		if err != nil {
+			return nil, err
+		}
+		if cluster != "" && c != cluster {
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "services to import must run in the same cluster, got %q and %q", cluster, c)
+		}
+		cluster = c
+		services = append(services, s)
+	}
+
+	name := options.Project
+	if name == "" {
+		name = cluster
+	}
+	project := &types.Project{
+		Name: name,
+		Extensions: map[string]interface{}{
+			extensionCluster: cluster,
+		},
+	}
+
+	var arns []string
+	for _, s := range services {
+		service, definition, err := b.SDK.DescribeServiceDefinition(ctx, cluster, s)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range service.Tags {
+			if aws.StringValue(t.Key) == compose.ProjectTag {
+				return nil, errors.Wrapf(errdefs.ErrAlreadyExists, "service %q already belongs to compose application %q", s, aws.StringValue(t.Value))
+			}
+		}
+		config, err := importService(service, definition)
+		if err != nil {
+			return nil, err
+		}
+		project.Services = append(project.Services, config)
+		arns = append(arns, aws.StringValue(service.ServiceArn))
+	}
+
+	for i, serviceARN := range arns {
+		err := b.SDK.TagService(ctx, serviceARN, map[string]string{
+			compose.ProjectTag:  project.Name,
+			compose.ServiceTag:  project.Services[i].Name,
+			compose.ImportedTag: "true",
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return project, nil
+}
+
+// parseServiceResource extracts cluster and service name from a service ARN, or a `cluster/service` reference
+func parseServiceResource(resource string) (string, string, error) {
+	reference := resource
+	if arn.IsARN(resource) {
+		parsed, err := arn.Parse(resource)
+		if err != nil {
+			return "", "", err
+		}
+		reference = strings.TrimPrefix(parsed.Resource, "service/")
+	}
+	parts := strings.Split(reference, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.Wrapf(errdefs.ErrParsingFailed, "%q is not a service ARN or a cluster/service reference", resource)
+	}
+	return parts[0], parts[1], nil
+}
+
+// importService converts a service and its task definition into a compose service. Only the main container of the
+// task is imported, which is the one named after the service or the first essential one.
+func importService(service *ecsapi.Service, definition *ecsapi.TaskDefinition) (types.ServiceConfig, error) {
+	name := aws.StringValue(service.ServiceName)
+	container := mainContainer(name, definition.ContainerDefinitions)
+	if container == nil {
+		return types.ServiceConfig{}, errors.Wrapf(errdefs.ErrNotFound, "container of service %q", name)
+	}
+	for _, c := range definition.ContainerDefinitions {
+		if c != container {
+			logrus.Warnf("container %q of service %q can't be imported, only %q is", aws.StringValue(c.Name), name, aws.StringValue(container.Name))
+		}
+	}
+
+	config := types.ServiceConfig{
+		Name:       name,
+		Image:      aws.StringValue(container.Image),
+		Command:    aws.StringValueSlice(container.Command),
+		Entrypoint: aws.StringValueSlice(container.EntryPoint),
+		WorkingDir: aws.StringValue(container.WorkingDirectory),
+		User:       aws.StringValue(container.User),
+	}
+	if len(container.Environment) > 0 {
+		config.Environment = types.MappingWithEquals{}
+		for _, env := range container.Environment {
+			config.Environment[aws.StringValue(env.Name)] = env.Value
+		}
+	}
+	for _, secret := range container.Secrets {
+		logrus.Warnf("secret %s of service %q can't be imported, declare it as a compose secret", aws.StringValue(secret.Name), name)
+	}
+	if len(container.DockerLabels) > 0 {
+		config.Labels = types.Labels{}
+		for k, v := range container.DockerLabels {
+			config.Labels[k] = aws.StringValue(v)
+		}
+	}
+	for _, port := range container.PortMappings {
+		config.Ports = append(config.Ports, types.ServicePortConfig{
+			Target:    uint32(aws.Int64Value(port.ContainerPort)),
+			Published: uint32(aws.Int64Value(port.ContainerPort)),
+			Protocol:  aws.StringValue(port.Protocol),
+		})
+	}
+
+	replicas := uint64(aws.Int64Value(service.DesiredCount))
+	config.Deploy = &types.DeployConfig{
+		Replicas: &replicas,
+	}
+	limits, err := taskLimits(definition)
+	if err != nil {
+		return types.ServiceConfig{}, err
+	}
+	if limits != nil {
+		config.Deploy.Resources.Limits = limits
+	}
+	return config, nil
+}
+
+func mainContainer(service string, containers []*ecsapi.ContainerDefinition) *ecsapi.ContainerDefinition {
+	var essential *ecsapi.ContainerDefinition
+	for _, c := range containers {
+		if aws.StringValue(c.Name) == service {
+			return c
+		}
+		if essential == nil && (c.Essential == nil || aws.BoolValue(c.Essential)) {
+			essential = c
+		}
+	}
+	return essential
+}
+
+// taskLimits converts the CPU units and memory MiB of a task definition into compose resource limits
+func taskLimits(definition *ecsapi.TaskDefinition) (*types.Resource, error) {
+	if definition.Cpu == nil && definition.Memory == nil {
+		return nil, nil
+	}
+	limits := &types.Resource{}
+	if definition.Cpu != nil {
+		units, err := strconv.ParseFloat(aws.StringValue(definition.Cpu), 64)
+		if err != nil {
+			return nil, err
+		}
+		limits.NanoCPUs = strconv.FormatFloat(units/1024, 'f', -1, 64)
+	}
+	if definition.Memory != nil {
+		mib, err := strconv.ParseInt(aws.StringValue(definition.Memory), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		limits.MemoryBytes = types.UnitBytes(mib * miB)
+	}
+	return limits, nil
+}
+
+// removeImportedServices deletes the services imported in the project, once the stack deployed their replacement
+func (b *ecsAPIService) removeImportedServices(ctx context.Context, project *types.Project) error {
+	cluster, ok := project.Extensions[extensionCluster].(string)
+	if !ok {
+		return nil
+	}
+	imported, err := b.SDK.ListImportedServices(ctx, cluster, project.Name)
+	if err != nil {
+		return err
+	}
+	w := progress.ContextWriter(ctx)
+	for _, serviceARN := range imported {
+		w.Event(progress.Event{
+			ID:         serviceARN,
+			Status:     progress.Working,
+			StatusText: "Replacing imported service",
+		})
+		if err := b.SDK.DeleteService(ctx, cluster, serviceARN); err != nil {
+			w.Event(progress.Event{
+				ID:         serviceARN,
+				Status:     progress.Error,
+				StatusText: "Replacing imported service",
+			})
+			return err
+		}
+		w.Event(progress.Event{
+			ID:         serviceARN,
+			Status:     progress.Done,
+			StatusText: "Deleted",
+		})
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	ecsapi "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestParseServiceResource(t *testing.T) {
+	cluster, service, err := parseServiceResource("arn:aws:ecs:eu-west-3:012345678910:service/production/web")
+	assert.NilError(t, err)
+	assert.Equal(t, cluster, "production")
+	assert.Equal(t, service, "web")
+
+	cluster, service, err = parseServiceResource("production/web")
+	assert.NilError(t, err)
+	assert.Equal(t, cluster, "production")
+	assert.Equal(t, service, "web")
+
+	// short ARN format doesn't include the cluster
+	_, _, err = parseServiceResource("arn:aws:ecs:eu-west-3:012345678910:service/web")
+	assert.ErrorContains(t, err, "is not a service ARN or a cluster/service reference")
+}
+
+func TestImportService(t *testing.T) {
+	service := &ecsapi.Service{
+		ServiceName:  aws.String("web"),
+		DesiredCount: aws.Int64(2),
+	}
+	definition := &ecsapi.TaskDefinition{
+		Cpu:    aws.String("512"),
+		Memory: aws.String("1024"),
+		ContainerDefinitions: []*ecsapi.ContainerDefinition{
+			{
+				Name:      aws.String("log-router"),
+				Image:     aws.String("fluent-bit"),
+				Essential: aws.Bool(false),
+			},
+			{
+				Name:      aws.String("app"),
+				Image:     aws.String("nginx"),
+				Essential: aws.Bool(true),
+				Command:   aws.StringSlice([]string{"nginx", "-g", "daemon off;"}),
+				Environment: []*ecsapi.KeyValuePair{
+					{Name: aws.String("MODE"), Value: aws.String("production")},
+				},
+				PortMappings: []*ecsapi.PortMapping{
+					{ContainerPort: aws.Int64(80), Protocol: aws.String("tcp")},
+				},
+			},
+		},
+	}
+	config, err := importService(service, definition)
+	assert.NilError(t, err)
+	assert.Equal(t, config.Name, "web")
+	assert.Equal(t, config.Image, "nginx")
+	assert.DeepEqual(t, []string(config.Command), []string{"nginx", "-g", "daemon off;"})
+	assert.Equal(t, *config.Environment["MODE"], "production")
+	assert.DeepEqual(t, config.Ports, []types.ServicePortConfig{{Target: 80, Published: 80, Protocol: "tcp"}})
+	assert.Equal(t, *config.Deploy.Replicas, uint64(2))
+	assert.Equal(t, config.Deploy.Resources.Limits.NanoCPUs, "0.5")
+	assert.Equal(t, config.Deploy.Resources.Limits.MemoryBytes, types.UnitBytes(1024*miB))
+
+	// imported limits get the same Fargate size once deployed
+	cpu, mem, err := toLimits(config)
+	assert.NilError(t, err)
+	assert.Equal(t, cpu, "512")
+	assert.Equal(t, mem, "1024")
+}
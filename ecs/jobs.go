@@ -0,0 +1,185 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	ecsapi "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/awslabs/goformation/v4/cloudformation/tags"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// Jobs don't get an ECS service but run as standalone tasks once the stack is deployed. The stack exposes
+// as outputs the resources needed to run them, so that identifiers of resources it creates get resolved.
+const (
+	jobTaskDefinitionOutput = "JobTaskDefinition"
+	jobClusterOutput        = "JobCluster"
+	jobSubnetsOutput        = "JobSubnets"
+	jobSecurityGroupsOutput = "JobSecurityGroups"
+)
+
+func jobOutput(job string, output string) string {
+	return normalizeResourceName(job) + output
+}
+
+func createJobOutputs(service types.ServiceConfig, template *cloudformation.Template, resources awsResources, taskDefinition string) {
+	template.Outputs[jobOutput(service.Name, jobTaskDefinitionOutput)] = cloudformation.Output{
+		Value:       cloudformation.Ref(taskDefinition),
+		Description: fmt.Sprintf("Task definition of job %s", service.Name),
+	}
+	template.Outputs[jobOutput(service.Name, jobClusterOutput)] = cloudformation.Output{
+		Value:       resources.cluster,
+		Description: fmt.Sprintf("Cluster job %s runs in", service.Name),
+	}
+	template.Outputs[jobOutput(service.Name, jobSubnetsOutput)] = cloudformation.Output{
+		Value:       cloudformation.Join(",", resources.serviceSubnets(service)),
+		Description: fmt.Sprintf("Subnets of job %s", service.Name),
+	}
+	template.Outputs[jobOutput(service.Name, jobSecurityGroupsOutput)] = cloudformation.Output{
+		Value:       cloudformation.Join(",", resources.serviceSecurityGroups(service)),
+		Description: fmt.Sprintf("Security groups of job %s", service.Name),
+	}
+}
+
+// upWithJobs deploys the project in two steps: services waiting for jobs are held back while the stack is first deployed
+// and jobs run, then the stack is updated to deploy them
+func (b *ecsAPIService) upWithJobs(ctx context.Context, project *types.Project, stack *cloudformation.Template, template []byte,
+	update bool, deployed map[string]deployedService, options compose.UpOptions) error {
+	dependents := compose.JobDependents(project)
+	holdJobDependents(stack, dependents, deployed)
+	held, err := marshall(stack)
+	if err != nil {
+		return err
+	}
+	if err := b.deployStack(ctx, project.Name, held, update, options); err != nil {
+		return err
+	}
+	if err := b.runJobs(ctx, project); err != nil {
+		return err
+	}
+	if len(dependents) == 0 {
+		return nil
+	}
+	return b.deployStack(ctx, project.Name, template, true, options)
+}
+
+// holdJobDependents keeps services waiting for jobs in their deployed version, or removes them from the stack if
+// they have not been deployed yet
+func holdJobDependents(stack *cloudformation.Template, dependents []string, deployed map[string]deployedService) {
+	for _, name := range dependents {
+		resource, ok := stack.Resources[serviceResourceName(name)].(*ecs.Service)
+		if !ok {
+			continue
+		}
+		current, ok := deployed[name]
+		if !ok || current.TaskDefinition == "" {
+			delete(stack.Resources, serviceResourceName(name))
+			continue
+		}
+		resource.TaskDefinition = current.TaskDefinition
+		for i, tag := range resource.Tags {
+			if tag.Key == compose.ConfigHashTag {
+				resource.Tags[i] = tags.Tag{Key: compose.ConfigHashTag, Value: current.ConfigHash}
+			}
+		}
+	}
+}
+
+// runJobs runs the project jobs as standalone tasks and waits for them to complete
+func (b *ecsAPIService) runJobs(ctx context.Context, project *types.Project) error {
+	outputs, err := b.SDK.GetStackOutputs(ctx, project.Name)
+	if err != nil {
+		return err
+	}
+	return compose.RunJobs(ctx, project, func(ctx context.Context, job types.ServiceConfig) error {
+		return b.runJob(ctx, project.Name, outputs, job)
+	})
+}
+
+// RunJob runs a job of the deployed project as a standalone task, with the task definition of the stack
+func (b *ecsAPIService) RunJob(ctx context.Context, project *types.Project, name string) error {
+	job, err := project.GetService(name)
+	if err != nil {
+		return err
+	}
+	if !compose.IsJob(job) {
+		return errors.Wrapf(errdefs.ErrNotFound, "service %s is not a job", name)
+	}
+	outputs, err := b.SDK.GetStackOutputs(ctx, project.Name)
+	if err != nil {
+		return err
+	}
+	return b.runJob(ctx, project.Name, outputs, job)
+}
+
+func (b *ecsAPIService) runJob(ctx context.Context, projectName string, outputs map[string]string, job types.ServiceConfig) error {
+	taskDefinition, ok := outputs[jobOutput(job.Name, jobTaskDefinitionOutput)]
+	if !ok {
+		return errors.Errorf("stack %s has no task definition for job %q", projectName, job.Name)
+	}
+	cluster := outputs[jobOutput(job.Name, jobClusterOutput)]
+	launchType := ecsapi.LaunchTypeFargate
+	assignPublicIP := ecsapi.AssignPublicIpEnabled
+	if requireEC2(job) {
+		launchType = ecsapi.LaunchTypeEc2
+		assignPublicIP = ecsapi.AssignPublicIpDisabled
+	}
+	network := &ecsapi.AwsVpcConfiguration{
+		AssignPublicIp: &assignPublicIP,
+		SecurityGroups: splitOutput(outputs[jobOutput(job.Name, jobSecurityGroupsOutput)]),
+		Subnets:        splitOutput(outputs[jobOutput(job.Name, jobSubnetsOutput)]),
+	}
+	arn, err := b.SDK.RunTask(ctx, cluster, taskDefinition, launchType, network, map[string]string{
+		compose.ProjectTag: projectName,
+		compose.ServiceTag: job.Name,
+	})
+	if err != nil {
+		return err
+	}
+	exitCode, reason, err := b.SDK.WaitTaskStopped(ctx, cluster, arn, job.Name)
+	if err != nil {
+		if ctx.Err() != nil {
+			// don't leave the job running when the command is interrupted
+			_ = b.SDK.StopTask(compose.Detach(ctx), cluster, arn, "compose command canceled")
+		}
+		return err
+	}
+	if exitCode != 0 {
+		return compose.JobFailedError{Job: job.Name, ExitCode: exitCode, Reason: reason}
+	}
+	return nil
+}
+
+func splitOutput(value string) []*string {
+	var values []*string
+	for _, v := range strings.Split(value, ",") {
+		if v != "" {
+			v := v
+			values = append(values, &v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,73 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"gotest.tools/v3/assert"
+)
+
+const jobsYaml = `
+services:
+  db:
+    image: mysql
+  migrate:
+    image: app
+    command: ["migrate"]
+    depends_on:
+      - db
+    deploy:
+      mode: job
+  api:
+    image: app
+    depends_on:
+      - db
+      - migrate
+`
+
+func TestJobConvert(t *testing.T) {
+	template := convertYaml(t, jobsYaml)
+	_, ok := template.Resources["MigrateService"]
+	assert.Assert(t, !ok)
+	_, ok = template.Resources["MigrateTaskDefinition"].(*ecs.TaskDefinition)
+	assert.Assert(t, ok)
+	_, ok = template.Outputs["MigrateJobTaskDefinition"]
+	assert.Assert(t, ok)
+	api := template.Resources["ApiService"].(*ecs.Service)
+	assert.DeepEqual(t, api.AWSCloudFormationDependsOn, []string{"DbService"})
+
+	raw, err := marshall(template)
+	assert.NilError(t, err)
+	assert.Assert(t, !strings.Contains(string(raw), `"Export"`))
+}
+
+func TestHoldJobDependents(t *testing.T) {
+	template := convertYaml(t, jobsYaml)
+	holdJobDependents(template, []string{"api"}, map[string]deployedService{})
+	_, ok := template.Resources["ApiService"]
+	assert.Assert(t, !ok)
+
+	template = convertYaml(t, jobsYaml)
+	holdJobDependents(template, []string{"api"}, map[string]deployedService{
+		"api": {TaskDefinition: "arn:aws:ecs:region:account:task-definition/api:1", ConfigHash: "deployed"},
+	})
+	api := template.Resources["ApiService"].(*ecs.Service)
+	assert.Equal(t, api.TaskDefinition, "arn:aws:ecs:region:account:task-definition/api:1")
+}
@@ -0,0 +1,200 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/elasticloadbalancingv2"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// defaultStickiness is the duration load balancer cookies last when stickiness is enabled without a duration
+const defaultStickiness = 24 * time.Hour
+
+// targetGroupOptions are the load balancer settings of a service set by x-aws-target_group
+type targetGroupOptions struct {
+	healthCheckPath     string
+	healthCheckInterval int
+	healthCheckTimeout  int
+	healthyThreshold    int
+	unhealthyThreshold  int
+	stickiness          int
+	httpsRedirect       bool
+}
+
+func parseTargetGroupExtension(service types.ServiceConfig, loadBalancerType string) (targetGroupOptions, error) {
+	options := targetGroupOptions{}
+	x, ok := service.Extensions[extensionTargetGroup]
+	if !ok {
+		return options, nil
+	}
+	values, ok := x.(map[string]interface{})
+	if !ok {
+		return options, errors.Wrapf(errdefs.ErrParsingFailed, "%s for service %s must be a mapping", extensionTargetGroup, service.Name)
+	}
+	for key, value := range values {
+		var valid bool
+		switch key {
+		case "healthcheck_path":
+			options.healthCheckPath, valid = value.(string)
+		case "healthcheck_interval":
+			options.healthCheckInterval, valid = toSeconds(value)
+		case "healthcheck_timeout":
+			options.healthCheckTimeout, valid = toSeconds(value)
+		case "healthy_threshold":
+			options.healthyThreshold, valid = value.(int)
+		case "unhealthy_threshold":
+			options.unhealthyThreshold, valid = value.(int)
+		case "stickiness":
+			if enabled, ok := value.(bool); ok {
+				valid = true
+				if enabled {
+					options.stickiness = int(defaultStickiness.Seconds())
+				}
+			} else {
+				options.stickiness, valid = toSeconds(value)
+			}
+		case "https_redirect":
+			options.httpsRedirect, valid = value.(bool)
+		default:
+			return options, errors.Wrapf(errdefs.ErrParsingFailed, "unsupported %s attribute %q", extensionTargetGroup, key)
+		}
+		if !valid {
+			return options, errors.Wrapf(errdefs.ErrParsingFailed, "invalid %s %s for service %s: %v", extensionTargetGroup, key, service.Name, value)
+		}
+	}
+
+	if options.httpsRedirect {
+		if loadBalancerType != elbv2.LoadBalancerTypeEnumApplication {
+			return options, errors.Wrapf(errdefs.ErrNotImplemented, "service %s can't redirect HTTP to HTTPS without an application load balancer", service.Name)
+		}
+		if !exposesPort(service, 80) || !exposesPort(service, 443) {
+			return options, errors.Wrapf(errdefs.ErrParsingFailed, "service %s must expose ports 80 and 443 to redirect HTTP to HTTPS", service.Name)
+		}
+	}
+	return options, nil
+}
+
+func exposesPort(service types.ServiceConfig, port uint32) bool {
+	for _, p := range service.Ports {
+		if p.Target == port {
+			return true
+		}
+	}
+	return false
+}
+
+// toSeconds converts a duration, either as a number of seconds or a Go duration string, to seconds
+func toSeconds(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, v > 0
+	case string:
+		d, err := time.ParseDuration(v)
+		if err != nil || d < time.Second {
+			return 0, false
+		}
+		return int(d.Seconds()), true
+	}
+	return 0, false
+}
+
+// applyTargetGroupOptions sets the health check and stickiness of a service target group
+func applyTargetGroupOptions(targetGroup *elasticloadbalancingv2.TargetGroup, options targetGroupOptions, loadBalancerType string) {
+	if options.healthCheckPath != "" || options.healthCheckInterval > 0 || options.healthCheckTimeout > 0 ||
+		options.healthyThreshold > 0 || options.unhealthyThreshold > 0 {
+		targetGroup.HealthCheckEnabled = true
+		targetGroup.HealthCheckIntervalSeconds = options.healthCheckInterval
+		targetGroup.HealthCheckTimeoutSeconds = options.healthCheckTimeout
+		targetGroup.HealthyThresholdCount = options.healthyThreshold
+		targetGroup.UnhealthyThresholdCount = options.unhealthyThreshold
+	}
+	if options.healthCheckPath != "" {
+		targetGroup.HealthCheckPath = options.healthCheckPath
+		targetGroup.HealthCheckProtocol = elbv2.ProtocolEnumHttp
+	}
+	if options.stickiness > 0 {
+		targetGroup.TargetGroupAttributes = append(targetGroup.TargetGroupAttributes,
+			elasticloadbalancingv2.TargetGroup_TargetGroupAttribute{Key: "stickiness.enabled", Value: "true"})
+		if loadBalancerType == elbv2.LoadBalancerTypeEnumApplication {
+			targetGroup.TargetGroupAttributes = append(targetGroup.TargetGroupAttributes,
+				elasticloadbalancingv2.TargetGroup_TargetGroupAttribute{Key: "stickiness.type", Value: "lb_cookie"},
+				elasticloadbalancingv2.TargetGroup_TargetGroupAttribute{Key: "stickiness.lb_cookie.duration_seconds", Value: strconv.Itoa(options.stickiness)})
+		} else {
+			targetGroup.TargetGroupAttributes = append(targetGroup.TargetGroupAttributes,
+				elasticloadbalancingv2.TargetGroup_TargetGroupAttribute{Key: "stickiness.type", Value: "source_ip"})
+		}
+	}
+}
+
+// createRedirectListener creates a listener redirecting HTTP requests on a service port to HTTPS on port 443
+func (b *ecsAPIService) createRedirectListener(service types.ServiceConfig, port types.ServicePortConfig,
+	template *cloudformation.Template, loadBalancerARN string) string {
+	listenerName := fmt.Sprintf("%sHTTP%dRedirectListener", normalizeResourceName(service.Name), port.Target)
+	template.Resources[listenerName] = &elasticloadbalancingv2.Listener{
+		DefaultActions: []elasticloadbalancingv2.Listener_Action{
+			{
+				RedirectConfig: &elasticloadbalancingv2.Listener_RedirectConfig{
+					Port:       "443",
+					Protocol:   elbv2.ProtocolEnumHttps,
+					StatusCode: "HTTP_301",
+				},
+				Type: elbv2.ActionTypeEnumRedirect,
+			},
+		},
+		LoadBalancerArn: loadBalancerARN,
+		Protocol:        elbv2.ProtocolEnumHttp,
+		Port:            int(port.Target),
+	}
+	return listenerName
+}
+
+// setLoadBalancerIdleTimeout applies x-aws-loadbalancer_idle_timeout to the load balancer created for the project
+func setLoadBalancerIdleTimeout(project *types.Project, template *cloudformation.Template) error {
+	x, ok := project.Extensions[extensionLoadBalancerIdleTimeout]
+	if !ok {
+		return nil
+	}
+	seconds, valid := toSeconds(x)
+	if !valid {
+		return errors.Wrapf(errdefs.ErrParsingFailed, "invalid %s: %v", extensionLoadBalancerIdleTimeout, x)
+	}
+	if _, ok := project.Extensions[extensionLoadBalancer]; ok {
+		return errors.Wrapf(errdefs.ErrNotImplemented, "%s can't be set for an existing load balancer", extensionLoadBalancerIdleTimeout)
+	}
+	resource, ok := template.Resources["LoadBalancer"]
+	if !ok {
+		return nil
+	}
+	loadBalancer := resource.(*elasticloadbalancingv2.LoadBalancer)
+	if loadBalancer.Type != elbv2.LoadBalancerTypeEnumApplication {
+		return errors.Wrapf(errdefs.ErrNotImplemented, "%s is only supported by application load balancers", extensionLoadBalancerIdleTimeout)
+	}
+	loadBalancer.LoadBalancerAttributes = append(loadBalancer.LoadBalancerAttributes, elasticloadbalancingv2.LoadBalancer_LoadBalancerAttribute{
+		Key:   "idle_timeout.timeout_seconds",
+		Value: strconv.Itoa(seconds),
+	})
+	return nil
+}
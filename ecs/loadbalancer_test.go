@@ -0,0 +1,116 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/awslabs/goformation/v4/cloudformation/elasticloadbalancingv2"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestTargetGroupOptions(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  test:
+    image: nginx
+    ports:
+      - 80:80
+    x-aws-target_group:
+      healthcheck_path: /health
+      healthcheck_interval: 10s
+      healthy_threshold: 2
+      stickiness: 1h
+x-aws-loadbalancer_idle_timeout: 120
+`)
+	targetGroup := template.Resources["TestTCP80TargetGroup"].(*elasticloadbalancingv2.TargetGroup)
+	assert.Check(t, targetGroup.HealthCheckEnabled)
+	assert.Equal(t, targetGroup.HealthCheckPath, "/health")
+	assert.Equal(t, targetGroup.HealthCheckProtocol, elbv2.ProtocolEnumHttp)
+	assert.Equal(t, targetGroup.HealthCheckIntervalSeconds, 10)
+	assert.Equal(t, targetGroup.HealthyThresholdCount, 2)
+	assert.DeepEqual(t, targetGroup.TargetGroupAttributes, []elasticloadbalancingv2.TargetGroup_TargetGroupAttribute{
+		{Key: "stickiness.enabled", Value: "true"},
+		{Key: "stickiness.type", Value: "lb_cookie"},
+		{Key: "stickiness.lb_cookie.duration_seconds", Value: "3600"},
+	})
+
+	loadBalancer := template.Resources["LoadBalancer"].(*elasticloadbalancingv2.LoadBalancer)
+	assert.DeepEqual(t, loadBalancer.LoadBalancerAttributes, []elasticloadbalancingv2.LoadBalancer_LoadBalancerAttribute{
+		{Key: "idle_timeout.timeout_seconds", Value: "120"},
+	})
+}
+
+func TestHTTPSRedirect(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  test:
+    image: nginx
+    ports:
+      - 80:80
+      - 443:443
+    x-aws-target_group:
+      https_redirect: true
+`)
+	_, ok := template.Resources["TestTCP80TargetGroup"]
+	assert.Check(t, !ok)
+	listener := template.Resources["TestHTTP80RedirectListener"].(*elasticloadbalancingv2.Listener)
+	assert.Equal(t, listener.DefaultActions[0].Type, elbv2.ActionTypeEnumRedirect)
+	assert.Equal(t, listener.DefaultActions[0].RedirectConfig.Protocol, elbv2.ProtocolEnumHttps)
+	assert.Equal(t, listener.DefaultActions[0].RedirectConfig.Port, "443")
+	_, ok = template.Resources["TestTCP443TargetGroup"]
+	assert.Check(t, ok)
+}
+
+func TestTargetGroupOptionsFailure(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  test:
+    image: nginx
+    ports:
+      - 80:80
+    x-aws-target_group:
+      https_redirect: true
+`)
+	_, err := (&ecsAPIService{}).convert(project, awsResources{})
+	assert.ErrorContains(t, err, "service test must expose ports 80 and 443 to redirect HTTP to HTTPS")
+
+	project = loadConfig(t, `
+services:
+  test:
+    image: mysql
+    ports:
+      - 3306:3306
+x-aws-loadbalancer_idle_timeout: 2m
+`)
+	_, err = (&ecsAPIService{}).convert(project, awsResources{})
+	assert.Check(t, errdefs.IsErrNotImplemented(err))
+	assert.ErrorContains(t, err, "only supported by application load balancers")
+
+	project = loadConfig(t, `
+services:
+  test:
+    image: nginx
+    x-aws-target_group:
+      stickiness: forever
+`)
+	_, err = (&ecsAPIService{}).convert(project, awsResources{})
+	assert.ErrorContains(t, err, "invalid x-aws-target_group stickiness for service test: forever")
+}
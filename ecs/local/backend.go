@@ -23,6 +23,7 @@ import (
 
 	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/api/containers"
+	"github.com/docker/compose-cli/api/images"
 	"github.com/docker/compose-cli/api/secrets"
 	"github.com/docker/compose-cli/api/volumes"
 	"github.com/docker/compose-cli/backend"
@@ -63,6 +64,10 @@ func (e ecsLocalSimulation) VolumeService() volumes.Service {
 	return nil
 }
 
+func (e ecsLocalSimulation) ImageService() images.Service {
+	return nil
+}
+
 func (e ecsLocalSimulation) SecretsService() secrets.Service {
 	return nil
 }
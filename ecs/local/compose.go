@@ -26,12 +26,16 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	types2 "github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
 
 	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/config"
 	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/registry"
+	"github.com/docker/compose-cli/utils/dockercli"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/compose-spec/compose-go/types"
@@ -40,7 +44,7 @@ import (
 	"golang.org/x/mod/semver"
 )
 
-func (e ecsLocalSimulation) Up(ctx context.Context, project *types.Project) error {
+func (e ecsLocalSimulation) Up(ctx context.Context, project *types.Project, options compose.UpOptions) error {
 	cmd := exec.Command("docker-compose", "version", "--short")
 	b := bytes.Buffer{}
 	b.WriteString("v")
@@ -57,6 +61,21 @@ func (e ecsLocalSimulation) Up(ctx context.Context, project *types.Project) erro
 		return fmt.Errorf("ECS simulation mode require Docker-compose 1.27, found %s", version)
 	}
 
+	info, err := e.moby.Info(ctx)
+	if err != nil {
+		return err
+	}
+	if err := dockercli.CheckServiceRuntimes(info.Runtimes, project); err != nil {
+		return err
+	}
+
+	if options.ResolveImageDigests {
+		err = registry.PinImages(ctx, project)
+		if err != nil {
+			return err
+		}
+	}
+
 	converted, err := e.Convert(ctx, project)
 	if err != nil {
 		return err
@@ -70,8 +89,11 @@ func (e ecsLocalSimulation) Up(ctx context.Context, project *types.Project) erro
 }
 
 func (e ecsLocalSimulation) Convert(ctx context.Context, project *types.Project) ([]byte, error) {
+	// the credentials network is internal, so that it doesn't give services attached to internal networks only
+	// a way out, the endpoints container reaches AWS through the default network
 	project.Networks["credentials_network"] = types.NetworkConfig{
-		Driver: "bridge",
+		Driver:   "bridge",
+		Internal: true,
 		Ipam: types.IPAMConfig{
 			Config: []*types.IPAMPool{
 				{
@@ -126,6 +148,7 @@ func (e ecsLocalSimulation) Convert(ctx context.Context, project *types.Project)
 			"credentials_network": {
 				Ipv4Address: "169.254.170.2",
 			},
+			"default": nil,
 		},
 	})
 
@@ -140,8 +163,15 @@ func (e ecsLocalSimulation) Convert(ctx context.Context, project *types.Project)
 	return yaml.Marshal(config)
 }
 
-func (e ecsLocalSimulation) Down(ctx context.Context, projectName string) error {
-	cmd := exec.Command("docker-compose", "--context", "default", "--project-name", projectName, "-f", "-", "down", "--remove-orphans")
+func (e ecsLocalSimulation) Down(ctx context.Context, projectName string, options compose.DownOptions) error {
+	args := []string{"--context", "default", "--project-name", projectName, "-f", "-", "down", "--remove-orphans"}
+	if options.RemoveVolumes {
+		args = append(args, "--volumes")
+	}
+	if options.RemoveImages != "" {
+		args = append(args, "--rmi", options.RemoveImages)
+	}
+	cmd := exec.Command("docker-compose", args...)
 	cmd.Stdin = strings.NewReader(string(`
 services:
    ecs-local-endpoints:
@@ -152,7 +182,13 @@ services:
 	return cmd.Run()
 }
 
-func (e ecsLocalSimulation) Logs(ctx context.Context, projectName string, w io.Writer) error {
+func (e ecsLocalSimulation) Logs(ctx context.Context, projectName string, consumer compose.LogConsumer, options compose.LogOptions) error {
+	if options.Export != "" {
+		return errors.Wrap(errdefs.ErrNotImplemented, "logs export")
+	}
+	if !options.Follow() || !options.Since.IsZero() {
+		return errors.Wrap(errdefs.ErrNotImplemented, "logs of previous containers and time ranges")
+	}
 	list, err := e.moby.ContainerList(ctx, types2.ContainerListOptions{
 		Filters: filters.NewArgs(filters.Arg("label", "com.docker.compose.project="+projectName)),
 	})
@@ -160,10 +196,15 @@ func (e ecsLocalSimulation) Logs(ctx context.Context, projectName string, w io.W
 		return err
 	}
 	services := map[string]types.ServiceConfig{}
+	containers := map[string]string{}
 	for _, c := range list {
-		services[c.Labels["com.docker.compose.service"]] = types.ServiceConfig{
+		service := c.Labels["com.docker.compose.service"]
+		services[service] = types.ServiceConfig{
 			Image: "unused",
 		}
+		for _, name := range c.Names {
+			containers[strings.TrimPrefix(name, "/")] = service
+		}
 	}
 
 	marshal, err := yaml.Marshal(map[string]interface{}{
@@ -172,11 +213,47 @@ func (e ecsLocalSimulation) Logs(ctx context.Context, projectName string, w io.W
 	if err != nil {
 		return err
 	}
-	cmd := exec.Command("docker-compose", "--context", "default", "--project-name", projectName, "-f", "-", "logs", "-f")
+	cmd := exec.Command("docker-compose", "--context", "default", "--project-name", projectName, "-f", "-", "logs", "-f", "--no-color", "--timestamps")
 	cmd.Stdin = strings.NewReader(string(marshal))
-	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	consumeComposeLogs(stdout, containers, consumer)
+	return cmd.Wait()
+}
+
+// consumeComposeLogs parses the `container | timestamp line` output of docker-compose logs --timestamps
+func consumeComposeLogs(r io.Reader, containers map[string]string, consumer compose.LogConsumer) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), "|", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		container := strings.TrimSpace(parts[0])
+		service, ok := containers[container]
+		if !ok {
+			service = container
+		}
+		line := strings.TrimPrefix(parts[1], " ")
+		timestamp := time.Now()
+		if fields := strings.SplitN(line, " ", 2); len(fields) == 2 {
+			if t, err := time.Parse(time.RFC3339Nano, fields[0]); err == nil {
+				timestamp, line = t, fields[1]
+			}
+		}
+		consumer.Log(compose.LogEvent{
+			Service:   service,
+			Container: container,
+			Timestamp: timestamp.UTC(),
+			Line:      line,
+		})
+	}
 }
 
 func (e ecsLocalSimulation) Ps(ctx context.Context, projectName string) ([]compose.ServiceStatus, error) {
@@ -185,3 +262,42 @@ func (e ecsLocalSimulation) Ps(ctx context.Context, projectName string) ([]compo
 func (e ecsLocalSimulation) List(ctx context.Context, projectName string) ([]compose.Stack, error) {
 	return nil, errors.Wrap(errdefs.ErrNotImplemented, "use docker-compose ls")
 }
+func (e ecsLocalSimulation) Prune(ctx context.Context, options compose.PruneOptions) ([]compose.OrphanResource, error) {
+	return nil, errors.Wrap(errdefs.ErrNotImplemented, "use docker system prune")
+}
+func (e ecsLocalSimulation) Estimate(ctx context.Context, project *types.Project) ([]compose.CostEstimate, error) {
+	return nil, errors.Wrap(errdefs.ErrNotImplemented, "local simulation has no cost")
+}
+
+func (e ecsLocalSimulation) PortForward(ctx context.Context, projectName string, service string, localPort, remotePort uint32) error {
+	return errors.Wrap(errdefs.ErrNotImplemented, "use published ports to reach local simulation services")
+}
+
+func (e ecsLocalSimulation) Diff(ctx context.Context, project *types.Project) (compose.ProjectDiff, error) {
+	return compose.ProjectDiff{}, errors.Wrap(errdefs.ErrNotImplemented, "use docker-compose config")
+}
+
+func (e ecsLocalSimulation) Import(ctx context.Context, options compose.ImportOptions) (*types.Project, error) {
+	return nil, errors.Wrap(errdefs.ErrNotImplemented, "local simulation only runs compose files")
+}
+
+func (e ecsLocalSimulation) Lock(ctx context.Context, projectName string, timeout time.Duration) (func() error, error) {
+	return e.locks(ctx).Lock(ctx, projectName, timeout)
+}
+
+func (e ecsLocalSimulation) Unlock(ctx context.Context, projectName string) error {
+	return e.locks(ctx).Unlock(projectName)
+}
+
+func (e ecsLocalSimulation) Outputs(ctx context.Context, projectName string) ([]compose.StackOutput, error) {
+	return nil, errors.Wrap(errdefs.ErrNotImplemented, "ECS simulation does not create cloud resources")
+}
+
+func (e ecsLocalSimulation) RunJob(ctx context.Context, project *types.Project, job string) error {
+	return errors.Wrap(errdefs.ErrNotImplemented, "use docker-compose run")
+}
+
+// locks are kept on the local machine, like the simulated application
+func (e ecsLocalSimulation) locks(ctx context.Context) compose.FileLock {
+	return compose.ContextLocks(ctx, filepath.Join(config.Dir(ctx), "locks", "ecs-local"))
+}
@@ -0,0 +1,89 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+// Discovery describes the embedded DNS of the project bridge networks, resolving service names and network aliases
+func (e ecsLocalSimulation) Discovery(ctx context.Context, project *types.Project) ([]compose.ServiceDiscovery, error) {
+	discovery := []compose.ServiceDiscovery{}
+	for _, service := range project.Services {
+		hostnames := []string{service.Name}
+		var networks []string
+		for name, config := range service.Networks {
+			networks = append(networks, fmt.Sprintf("%s_%s", project.Name, name))
+			if config != nil {
+				hostnames = append(hostnames, config.Aliases...)
+			}
+		}
+		if len(networks) == 0 {
+			networks = []string{fmt.Sprintf("%s_default", project.Name)}
+		}
+		sort.Strings(networks)
+		sort.Strings(hostnames[1:])
+		discovery = append(discovery, compose.ServiceDiscovery{
+			Service:   service.Name,
+			Hostnames: hostnames,
+			Address:   "container IP",
+			Ports:     compose.ServicePorts(service),
+			Mechanism: fmt.Sprintf("bridge network %s", strings.Join(networks, ", ")),
+		})
+	}
+	return discovery, nil
+}
+
+// Exposure describes ports published on the host, other ports are only reachable on the project networks
+func (e ecsLocalSimulation) Exposure(ctx context.Context, project *types.Project) ([]compose.PortExposure, error) {
+	exposure := []compose.PortExposure{}
+	for _, service := range project.Services {
+		for _, port := range service.Ports {
+			hostIP := port.HostIP
+			if hostIP == "" {
+				hostIP = "0.0.0.0"
+			}
+			source := fmt.Sprintf("host %s:%d", hostIP, port.Published)
+			if port.Published == 0 {
+				source = fmt.Sprintf("host %s, random port", hostIP)
+			}
+			exposure = append(exposure, compose.PortExposure{
+				Service:  service.Name,
+				Port:     port.Target,
+				Protocol: compose.PortProtocol(port),
+				Source:   source,
+			})
+		}
+		for _, port := range compose.InternalPorts(service) {
+			exposure = append(exposure, compose.PortExposure{
+				Service:  service.Name,
+				Port:     port,
+				Protocol: "tcp",
+				Source:   "project networks",
+			})
+		}
+	}
+	compose.SortExposure(exposure)
+	return exposure, nil
+}
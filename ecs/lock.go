@@ -0,0 +1,59 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// lockTable is the DynamoDB table holding project locks, shared by all projects deployed in the region
+const lockTable = "docker-compose-locks"
+
+func (b *ecsAPIService) Lock(ctx context.Context, projectName string, timeout time.Duration) (func() error, error) {
+	if err := b.SDK.CreateLockTable(ctx, lockTable); err != nil {
+		return nil, err
+	}
+	info := compose.NewLockInfo()
+	err := compose.AcquireLock(ctx, projectName, timeout, func(ctx context.Context) (*compose.LockInfo, error) {
+		return b.SDK.PutLock(ctx, lockTable, projectName, info)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return func() error {
+		// release even if the command was canceled, so the next one doesn't have to wait
+		_, err := b.SDK.DeleteLock(context.Background(), lockTable, projectName, info.ID)
+		return err
+	}, nil
+}
+
+func (b *ecsAPIService) Unlock(ctx context.Context, projectName string) error {
+	deleted, err := b.SDK.DeleteLock(ctx, lockTable, projectName, "")
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.Wrapf(errdefs.ErrNotFound, "project %q is not locked", projectName)
+	}
+	return nil
+}
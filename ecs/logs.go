@@ -17,51 +17,59 @@
 package ecs
 
 import (
-	"bytes"
 	"context"
-	"fmt"
-	"io"
-	"strconv"
+	"net/url"
 	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/progress"
 )
 
-func (b *ecsAPIService) Logs(ctx context.Context, project string, w io.Writer) error {
-	consumer := logConsumer{
-		colors: map[string]colorFunc{},
-		width:  0,
-		writer: w,
+func (b *ecsAPIService) Logs(ctx context.Context, project string, consumer compose.LogConsumer, options compose.LogOptions) error {
+	if options.Export != "" {
+		return permissionError("logs", b.exportLogs(ctx, project, options.Export))
 	}
-	err := b.SDK.GetLogs(ctx, project, consumer.Log)
-	return err
-}
-
-func (l *logConsumer) Log(service, container, message string) {
-	cf, ok := l.colors[service]
-	if !ok {
-		cf = <-loop
-		l.colors[service] = cf
-		l.computeWidth()
+	if !options.Previous {
+		return permissionError("logs", b.SDK.GetLogs(ctx, project, consumer, options, nil))
 	}
-	prefix := fmt.Sprintf("%-"+strconv.Itoa(l.width)+"s |", service)
-
-	for _, line := range strings.Split(message, "\n") {
-		buf := bytes.NewBufferString(fmt.Sprintf("%s %s\n", cf(prefix), line))
-		l.writer.Write(buf.Bytes()) // nolint:errcheck
+	cluster, _, err := b.deployedServices(ctx, project)
+	if err != nil {
+		return permissionError("logs", err)
 	}
+	running, err := b.SDK.ListRunningTaskIDs(ctx, cluster)
+	if err != nil {
+		return permissionError("logs", err)
+	}
+	return permissionError("logs", b.SDK.GetLogs(ctx, project, consumer, options, running))
 }
 
-func (l *logConsumer) computeWidth() {
-	width := 0
-	for n := range l.colors {
-		if len(n) > width {
-			width = len(n)
-		}
+// exportLogs exports the application log group to an S3 bucket with a CloudWatch export task
+func (b *ecsAPIService) exportLogs(ctx context.Context, project string, destination string) error {
+	bucket, prefix, err := parseS3URL(destination)
+	if err != nil {
+		return err
+	}
+	w := progress.ContextWriter(ctx)
+	w.Event(progress.Event{ID: destination, Status: progress.Working, StatusText: "Exporting logs"})
+	id, err := b.SDK.ExportLogs(ctx, project, bucket, prefix)
+	if err != nil {
+		return err
 	}
-	l.width = width + 3
+	if err := b.SDK.WaitExportTask(ctx, id); err != nil {
+		return err
+	}
+	w.Event(progress.Event{ID: destination, Status: progress.Done, StatusText: "Exported"})
+	return nil
 }
 
-type logConsumer struct {
-	colors map[string]colorFunc
-	width  int
-	writer io.Writer
+// parseS3URL splits a s3://bucket/prefix URL into its bucket and prefix
+func parseS3URL(destination string) (string, string, error) {
+	u, err := url.Parse(destination)
+	if err != nil || u.Scheme != "s3" || u.Host == "" {
+		return "", "", errors.Wrapf(errdefs.ErrParsingFailed, "logs export destination %q must be a s3://bucket/prefix URL", destination)
+	}
+	return u.Host, strings.Trim(u.Path, "/"), nil
 }
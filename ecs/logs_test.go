@@ -0,0 +1,77 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestParseS3URL(t *testing.T) {
+	bucket, prefix, err := parseS3URL("s3://logs/shop/2020")
+	assert.NilError(t, err)
+	assert.Equal(t, bucket, "logs")
+	assert.Equal(t, prefix, "shop/2020")
+
+	_, _, err = parseS3URL("https://logs.s3.amazonaws.com")
+	assert.ErrorContains(t, err, "must be a s3://bucket/prefix URL")
+}
+
+type fakeCloudWatchLogs struct {
+	cloudwatchlogsiface.CloudWatchLogsAPI
+	inputs []*cloudwatchlogs.FilterLogEventsInput
+	events []*cloudwatchlogs.FilteredLogEvent
+}
+
+func (f *fakeCloudWatchLogs) FilterLogEvents(input *cloudwatchlogs.FilterLogEventsInput) (*cloudwatchlogs.FilterLogEventsOutput, error) {
+	f.inputs = append(f.inputs, input)
+	return &cloudwatchlogs.FilterLogEventsOutput{Events: f.events}, nil
+}
+
+type collectLogs []compose.LogEvent
+
+func (c *collectLogs) Log(event compose.LogEvent) {
+	*c = append(*c, event)
+}
+
+func TestGetPreviousLogs(t *testing.T) {
+	cw := &fakeCloudWatchLogs{
+		events: []*cloudwatchlogs.FilteredLogEvent{
+			{EventId: aws.String("1"), LogStreamName: aws.String("demo/web/stopped"), Timestamp: aws.Int64(1000), Message: aws.String("panic")},
+			{EventId: aws.String("2"), LogStreamName: aws.String("demo/web/running"), Timestamp: aws.Int64(2000), Message: aws.String("started")},
+		},
+	}
+	since := time.Unix(1, 0)
+	until := time.Unix(3, 0)
+	var logs collectLogs
+	err := sdk{CW: cw}.GetLogs(context.Background(), "demo", &logs, compose.LogOptions{Previous: true, Since: since, Until: until}, []string{"running"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []compose.LogEvent(logs), []compose.LogEvent{
+		{Service: "web", Container: "stopped", Timestamp: time.Unix(1, 0).UTC(), Line: "panic"},
+	})
+	assert.Equal(t, len(cw.inputs), 1)
+	assert.Equal(t, aws.Int64Value(cw.inputs[0].StartTime), int64(1000))
+	assert.Equal(t, aws.Int64Value(cw.inputs[0].EndTime), int64(3000))
+}
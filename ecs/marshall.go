@@ -36,11 +36,56 @@ func marshall(template *cloudformation.Template) ([]byte, error) {
 	}
 
 	if input, ok := unmarshalled.(map[string]interface{}); ok {
+		if outputs, ok := input["Outputs"].(map[string]interface{}); ok {
+			for _, uoutput := range outputs {
+				// goformation always serializes Export, which CloudFormation rejects without a name
+				if output, ok := uoutput.(map[string]interface{}); ok {
+					if export, ok := output["Export"].(map[string]interface{}); ok && len(export) == 0 {
+						delete(output, "Export")
+					}
+				}
+			}
+		}
 		if resources, ok := input["Resources"]; ok {
 			for _, uresource := range resources.(map[string]interface{}) {
 				if resource, ok := uresource.(map[string]interface{}); ok {
+					if resource["Type"] == "AWS::ECS::Service" {
+						if metadata, ok := resource["Metadata"].(map[string]interface{}); ok {
+							if breaker, ok := metadata[circuitBreakerMetadata]; ok {
+								properties := resource["Properties"].(map[string]interface{})
+								if deployment, ok := properties["DeploymentConfiguration"].(map[string]interface{}); ok {
+									deployment["DeploymentCircuitBreaker"] = breaker
+								}
+								delete(metadata, circuitBreakerMetadata)
+							}
+							if serviceConnect, ok := metadata[serviceConnectMetadata]; ok {
+								resource["Properties"].(map[string]interface{})["ServiceConnectConfiguration"] = serviceConnect
+								delete(metadata, serviceConnectMetadata)
+							}
+							if enable, ok := metadata[enableExecuteCommandMetadata]; ok {
+								resource["Properties"].(map[string]interface{})["EnableExecuteCommand"] = enable
+								delete(metadata, enableExecuteCommandMetadata)
+							}
+							if len(metadata) == 0 {
+								delete(resource, "Metadata")
+							}
+						}
+					}
 					if resource["Type"] == "AWS::ECS::TaskDefinition" {
 						properties := resource["Properties"].(map[string]interface{})
+						if metadata, ok := resource["Metadata"].(map[string]interface{}); ok {
+							if platform, ok := metadata[runtimePlatformMetadata]; ok {
+								properties["RuntimePlatform"] = platform
+								delete(metadata, runtimePlatformMetadata)
+							}
+							if names, ok := metadata[portNamesMetadata].(map[string]interface{}); ok {
+								setPortNames(properties, names)
+								delete(metadata, portNamesMetadata)
+							}
+							if len(metadata) == 0 {
+								delete(resource, "Metadata")
+							}
+						}
 						for _, def := range properties["ContainerDefinitions"].([]interface{}) {
 							containerDefinition := def.(map[string]interface{})
 							if strings.HasSuffix(containerDefinition["Name"].(string), "_InitContainer") {
@@ -59,3 +104,21 @@ func marshall(template *cloudformation.Template) ([]byte, error) {
 	}
 	return raw, err
 }
+
+// setPortNames names the port mappings of task definition containers, by container name then port
+func setPortNames(properties map[string]interface{}, names map[string]interface{}) {
+	for _, def := range properties["ContainerDefinitions"].([]interface{}) {
+		containerDefinition := def.(map[string]interface{})
+		containerNames, ok := names[containerDefinition["Name"].(string)].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		mappings, _ := containerDefinition["PortMappings"].([]interface{})
+		for _, m := range mappings {
+			mapping := m.(map[string]interface{})
+			if name, ok := containerNames[fmt.Sprint(mapping["ContainerPort"])]; ok {
+				mapping["Name"] = name
+			}
+		}
+	}
+}
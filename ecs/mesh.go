@@ -0,0 +1,212 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"fmt"
+	"strings"
+
+	ecsapi "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/appmesh"
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const (
+	meshAppMesh = "appmesh"
+
+	envoyContainerName = "envoy"
+	envoyImage         = "public.ecr.aws/appmesh/aws-appmesh-envoy:v1.15.1.0-prod"
+	envoyUID           = "1337"
+	envoyIngressPort   = "15000"
+	envoyEgressPort    = "15001"
+	envoyAdminPort     = "9901"
+
+	appMeshEnvoyPolicy = "arn:aws:iam::aws:policy/AWSAppMeshEnvoyAccess"
+)
+
+// checkMeshExtension validates the x-mesh project extension, App Mesh being the only supported mesh. Services are
+// registered in the mesh by their Cloud Map name, which requires a private DNS namespace
+func checkMeshExtension(project *types.Project, cloudMap cloudMapConfig) error {
+	x, ok := project.Extensions[extensionMesh]
+	if !ok {
+		return nil
+	}
+	if x != meshAppMesh {
+		return errors.Wrapf(errdefs.ErrNotImplemented, "unsupported %s %v, only %q is supported", extensionMesh, x, meshAppMesh)
+	}
+	if cloudMap.serviceConnect {
+		return errors.Wrapf(errdefs.ErrParsingFailed, "%s can't be used with Service Connect", extensionMesh)
+	}
+	if !cloudMap.dns() {
+		return errors.Wrapf(errdefs.ErrParsingFailed, "%s requires a private DNS Cloud Map namespace", extensionMesh)
+	}
+	return nil
+}
+
+// meshed returns true when the service runs behind an Envoy proxy registered in the project mesh. Services can opt-out
+// declaring `x-mesh: false`, jobs and scheduled tasks never join the mesh
+func meshed(project *types.Project, service types.ServiceConfig) bool {
+	if project.Extensions[extensionMesh] != meshAppMesh {
+		return false
+	}
+	if enabled, ok := service.Extensions[extensionMesh]; ok && enabled == false {
+		return false
+	}
+	return !compose.IsJob(service) && !compose.IsScheduled(service)
+}
+
+// createMesh creates the project mesh with a virtual node per meshed service, discovered through Cloud Map. Services
+// exposing ports are also declared as virtual services, the backends of all other virtual nodes. Listeners use TCP so
+// the mesh doesn't make assumptions about the application protocol
+func (b *ecsAPIService) createMesh(project *types.Project, template *cloudformation.Template, cloudMap cloudMapConfig) {
+	template.Resources["Mesh"] = &appmesh.Mesh{
+		MeshName: project.Name,
+		Spec: &appmesh.Mesh_MeshSpec{
+			EgressFilter: &appmesh.Mesh_EgressFilter{Type: "ALLOW_ALL"},
+		},
+	}
+
+	var providers []types.ServiceConfig
+	for _, service := range project.Services {
+		if meshed(project, service) && len(service.Ports) > 0 {
+			providers = append(providers, service)
+		}
+	}
+
+	for _, service := range project.Services {
+		if !meshed(project, service) {
+			continue
+		}
+		var listeners []appmesh.VirtualNode_Listener
+		for _, port := range service.Ports {
+			listeners = append(listeners, appmesh.VirtualNode_Listener{
+				PortMapping: &appmesh.VirtualNode_PortMapping{
+					Port:     int(port.Target),
+					Protocol: "tcp",
+				},
+			})
+		}
+		var backends []appmesh.VirtualNode_Backend
+		for _, provider := range providers {
+			if provider.Name == service.Name {
+				continue
+			}
+			backends = append(backends, appmesh.VirtualNode_Backend{
+				VirtualService: &appmesh.VirtualNode_VirtualServiceBackend{
+					VirtualServiceName: virtualServiceName(provider, cloudMap),
+				},
+			})
+		}
+		template.Resources[virtualNodeResourceName(service.Name)] = &appmesh.VirtualNode{
+			AWSCloudFormationDependsOn: []string{"Mesh"},
+			MeshName:                   project.Name,
+			VirtualNodeName:            service.Name,
+			Spec: &appmesh.VirtualNode_VirtualNodeSpec{
+				Backends:  backends,
+				Listeners: listeners,
+				ServiceDiscovery: &appmesh.VirtualNode_ServiceDiscovery{
+					AWSCloudMap: &appmesh.VirtualNode_AwsCloudMapServiceDiscovery{
+						NamespaceName: cloudMap.name,
+						ServiceName:   service.Name,
+					},
+				},
+			},
+		}
+	}
+
+	for _, provider := range providers {
+		template.Resources[fmt.Sprintf("%sVirtualService", normalizeResourceName(provider.Name))] = &appmesh.VirtualService{
+			AWSCloudFormationDependsOn: []string{virtualNodeResourceName(provider.Name)},
+			MeshName:                   project.Name,
+			VirtualServiceName:         virtualServiceName(provider, cloudMap),
+			Spec: &appmesh.VirtualService_VirtualServiceSpec{
+				Provider: &appmesh.VirtualService_VirtualServiceProvider{
+					VirtualNode: &appmesh.VirtualService_VirtualNodeServiceProvider{
+						VirtualNodeName: provider.Name,
+					},
+				},
+			},
+		}
+	}
+}
+
+// addEnvoyProxy injects the Envoy proxy into a meshed service task, and redirects the service traffic through it
+func addEnvoyProxy(definition *ecs.TaskDefinition, service types.ServiceConfig, logConfiguration *ecs.TaskDefinition_LogConfiguration) {
+	for i, container := range definition.ContainerDefinitions {
+		if container.Name == service.Name {
+			definition.ContainerDefinitions[i].DependsOnProp = append(container.DependsOnProp, ecs.TaskDefinition_ContainerDependency{
+				Condition:     ecsapi.ContainerConditionHealthy,
+				ContainerName: envoyContainerName,
+			})
+		}
+	}
+	definition.ContainerDefinitions = append(definition.ContainerDefinitions, ecs.TaskDefinition_ContainerDefinition{
+		Environment: []ecs.TaskDefinition_KeyValuePair{
+			{Name: "APPMESH_RESOURCE_ARN", Value: cloudformation.Ref(virtualNodeResourceName(service.Name))},
+		},
+		Essential: true,
+		HealthCheck: &ecs.TaskDefinition_HealthCheck{
+			Command: []string{
+				"CMD-SHELL",
+				fmt.Sprintf("curl -s http://localhost:%s/server_info | grep state | grep -q LIVE", envoyAdminPort),
+			},
+			Interval:    5,
+			Retries:     3,
+			StartPeriod: 10,
+			Timeout:     2,
+		},
+		Image:            envoyImage,
+		LogConfiguration: logConfiguration,
+		Name:             envoyContainerName,
+		User:             envoyUID,
+	})
+
+	properties := []ecs.TaskDefinition_KeyValuePair{
+		{Name: "IgnoredUID", Value: envoyUID},
+		{Name: "ProxyIngressPort", Value: envoyIngressPort},
+		{Name: "ProxyEgressPort", Value: envoyEgressPort},
+		// keep task metadata and credentials endpoints out of the mesh
+		{Name: "EgressIgnoredIPs", Value: "169.254.170.2,169.254.169.254"},
+	}
+	var appPorts []string
+	for _, port := range service.Ports {
+		appPorts = append(appPorts, fmt.Sprint(port.Target))
+	}
+	if len(appPorts) > 0 {
+		properties = append(properties, ecs.TaskDefinition_KeyValuePair{Name: "AppPorts", Value: strings.Join(appPorts, ",")})
+	}
+	definition.ProxyConfiguration = &ecs.TaskDefinition_ProxyConfiguration{
+		ContainerName:                envoyContainerName,
+		ProxyConfigurationProperties: properties,
+		Type:                         "APPMESH",
+	}
+}
+
+// virtualServiceName is the service DNS name in the Cloud Map namespace, which meshed clients address it by
+func virtualServiceName(service types.ServiceConfig, cloudMap cloudMapConfig) string {
+	return fmt.Sprintf("%s.%s", service.Name, cloudMap.name)
+}
+
+func virtualNodeResourceName(service string) string {
+	return fmt.Sprintf("%sVirtualNode", normalizeResourceName(service))
+}
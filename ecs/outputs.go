@@ -0,0 +1,86 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/compose-spec/compose-go/types"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+const (
+	loadBalancerDNSOutput = "LoadBalancerDNSName"
+	logGroupOutput        = "LogGroupName"
+	fileSystemOutput      = "FileSystemId"
+)
+
+// createProjectOutputs declares the stack outputs automation relies on to discover the application endpoints and
+// resources, with compose inspect --outputs
+func createProjectOutputs(project *types.Project, template *cloudformation.Template) {
+	if _, ok := template.Resources["LoadBalancer"]; ok {
+		template.Outputs[loadBalancerDNSOutput] = cloudformation.Output{
+			Value:       cloudformation.GetAtt("LoadBalancer", "DNSName"),
+			Description: "DNS name of the application load balancer",
+		}
+	}
+	if _, ok := template.Resources["LogGroup"]; ok {
+		template.Outputs[logGroupOutput] = cloudformation.Output{
+			Value:       cloudformation.Ref("LogGroup"),
+			Description: "Log group of the application services",
+		}
+	}
+	for name, volume := range project.Volumes {
+		template.Outputs[normalizeResourceName(name)+fileSystemOutput] = cloudformation.Output{
+			Value:       volume.Name,
+			Description: fmt.Sprintf("EFS file system of volume %s", name),
+		}
+	}
+}
+
+func (b *ecsAPIService) Outputs(ctx context.Context, projectName string) ([]compose.StackOutput, error) {
+	outputs, err := b.SDK.ListStackOutputs(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+	result := []compose.StackOutput{}
+	for _, output := range outputs {
+		if isJobOutput(output.Name) {
+			continue
+		}
+		result = append(result, output)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// isJobOutput tells the outputs used internally to run jobs apart from the project ones
+func isJobOutput(name string) bool {
+	for _, suffix := range []string{jobTaskDefinitionOutput, jobClusterOutput, jobSubnetsOutput, jobSecurityGroupsOutput} {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
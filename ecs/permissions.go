@@ -0,0 +1,139 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"regexp"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+type permission struct {
+	actions []string
+	policy  string
+}
+
+// permissions lists the IAM actions each compose operation relies on and the managed policy granting them
+var permissions = map[string]permission{
+	"up": {
+		actions: []string{
+			"cloudformation:CreateStack",
+			"cloudformation:UpdateStack",
+			"cloudformation:DescribeStacks",
+			"cloudformation:DescribeStackEvents",
+			"cloudformation:ValidateTemplate",
+			"ecs:CreateCluster",
+			"ecs:DescribeClusters",
+			"ecs:RegisterTaskDefinition",
+			"ecs:CreateService",
+			"ecs:UpdateService",
+			"iam:CreateRole",
+			"iam:AttachRolePolicy",
+			"iam:PutRolePolicy",
+			"iam:PassRole",
+			"ec2:DescribeVpcs",
+			"ec2:DescribeSubnets",
+			"ec2:CreateSecurityGroup",
+			"ec2:AuthorizeSecurityGroupIngress",
+			"elasticloadbalancing:CreateLoadBalancer",
+			"elasticloadbalancing:CreateTargetGroup",
+			"elasticloadbalancing:CreateListener",
+			"logs:CreateLogGroup",
+			"cloudwatch:PutMetricData",
+			"servicediscovery:CreatePrivateDnsNamespace",
+			"servicediscovery:CreateService",
+			"ssm:PutParameter",
+		},
+		policy: "the AmazonECS_FullAccess and AWSCloudFormationFullAccess policies",
+	},
+	"down": {
+		actions: []string{
+			"cloudformation:DeleteStack",
+			"ecs:DeleteService",
+			"ecs:DeleteCluster",
+			"ecs:DeregisterTaskDefinition",
+			"iam:DeleteRole",
+			"iam:DetachRolePolicy",
+			"iam:DeleteRolePolicy",
+			"ec2:DeleteSecurityGroup",
+			"elasticloadbalancing:DeleteLoadBalancer",
+			"elasticloadbalancing:DeleteTargetGroup",
+			"logs:DeleteLogGroup",
+			"servicediscovery:DeleteNamespace",
+			"servicediscovery:DeleteService",
+			"ssm:GetParameter",
+			"ssm:DeleteParameter",
+		},
+		policy: "the AmazonECS_FullAccess and AWSCloudFormationFullAccess policies",
+	},
+	"ps": {
+		actions: []string{
+			"cloudformation:ListStackResources",
+			"ecs:DescribeServices",
+			"ecs:ListTasks",
+			"ecs:DescribeTasks",
+			"ssm:GetParameter",
+		},
+		policy: "the AmazonECS_FullAccess policy",
+	},
+	"logs": {
+		actions: []string{
+			"logs:FilterLogEvents",
+			"logs:DescribeLogGroups",
+		},
+		policy: "the CloudWatchLogsReadOnlyAccess policy",
+	},
+}
+
+// accessDeniedCodes are the error codes AWS services use to deny a request
+var accessDeniedCodes = map[string]bool{
+	"AccessDenied":          true,
+	"AccessDeniedException": true,
+	"UnauthorizedOperation": true,
+	"UnauthorizedException": true,
+}
+
+// deniedAction extracts the action from messages like "User: arn:... is not authorized to perform: ecs:CreateService on resource: ..."
+var deniedAction = regexp.MustCompile(`not authorized to perform:? ([a-zA-Z0-9-]+:[a-zA-Z0-9*]+)`)
+
+// permissionError translates AWS access denials into an errdefs.PermissionError listing the missing actions
+func permissionError(operation string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var aerr awserr.Error
+	if !errors.As(err, &aerr) || !accessDeniedCodes[aerr.Code()] {
+		// CloudFormation reports denials of the resources it creates as stack events
+		if !deniedAction.MatchString(err.Error()) {
+			return err
+		}
+	}
+	perm := permissions[operation]
+	actions := perm.actions
+	if match := deniedAction.FindStringSubmatch(err.Error()); match != nil {
+		actions = []string{match[1]}
+	}
+	return errdefs.PermissionError{
+		Operation: "compose " + operation,
+		Actions:   actions,
+		Grant:     perm.policy,
+		Err:       err,
+	}
+}
@@ -0,0 +1,60 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestPermissionErrorReportsDeniedAction(t *testing.T) {
+	denied := awserr.New("AccessDeniedException", "User: arn:aws:iam::123456789012:user/dev is not authorized to perform: ecs:RegisterTaskDefinition on resource: *", nil)
+	err := permissionError("up", errors.Wrap(denied, "registering task"))
+	assert.Assert(t, errdefs.IsForbiddenError(err))
+
+	var perm errdefs.PermissionError
+	assert.Assert(t, errors.As(err, &perm))
+	assert.DeepEqual(t, perm.Actions, []string{"ecs:RegisterTaskDefinition"})
+	assert.Equal(t, perm.Grant, permissions["up"].policy)
+}
+
+func TestPermissionErrorFallsBackToOperationActions(t *testing.T) {
+	err := permissionError("ps", awserr.New("AccessDenied", "Access Denied", nil))
+
+	var perm errdefs.PermissionError
+	assert.Assert(t, errors.As(err, &perm))
+	assert.DeepEqual(t, perm.Actions, permissions["ps"].actions)
+}
+
+func TestPermissionErrorFromStackEvent(t *testing.T) {
+	err := permissionError("up", errors.New("resource FrontService: API: iam:PassRole User: arn:aws:iam::123456789012:user/dev is not authorized to perform: iam:PassRole on resource: arn:aws:iam::123456789012:role/task"))
+
+	var perm errdefs.PermissionError
+	assert.Assert(t, errors.As(err, &perm))
+	assert.DeepEqual(t, perm.Actions, []string{"iam:PassRole"})
+}
+
+func TestPermissionErrorKeepsOtherErrors(t *testing.T) {
+	other := awserr.New("ValidationError", "Stack does not exist", nil)
+	assert.Equal(t, permissionError("down", other), other)
+	assert.NilError(t, permissionError("down", nil))
+}
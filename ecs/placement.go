@@ -0,0 +1,122 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// placementAttributes maps compose node attributes to ECS container instance attributes
+var placementAttributes = map[string]string{
+	compose.ZoneAttribute:         "attribute:ecs.availability-zone",
+	compose.InstanceTypeAttribute: "attribute:ecs.instance-type",
+}
+
+type servicePlacement struct {
+	subnets     []string
+	constraints []ecs.Service_PlacementConstraint
+	strategies  []ecs.Service_PlacementStrategy
+}
+
+// placeService maps the service placement constraints and preferences. Services running on EC2 get ECS placement
+// constraints and strategies. Fargate supports neither, and already spreads tasks across availability zones, so
+// Fargate services are pinned to zones by running in the subnets of these zones only.
+func (r *awsResources) placeService(service types.ServiceConfig) (servicePlacement, error) {
+	placement := servicePlacement{
+		subnets: r.serviceSubnets(service),
+	}
+	constraints, err := compose.PlacementConstraints(service)
+	if err != nil {
+		return placement, err
+	}
+	spread, err := compose.SpreadZones(service)
+	if err != nil {
+		return placement, err
+	}
+	if requireEC2(service) {
+		for _, c := range constraints {
+			op := "=="
+			if !c.Equal {
+				op = "!="
+			}
+			placement.constraints = append(placement.constraints, ecs.Service_PlacementConstraint{
+				Type:       "memberOf",
+				Expression: fmt.Sprintf("%s %s %s", placementAttributes[c.Attribute], op, c.Value),
+			})
+		}
+		if spread {
+			placement.strategies = []ecs.Service_PlacementStrategy{
+				{Type: "spread", Field: placementAttributes[compose.ZoneAttribute]},
+			}
+		}
+		return placement, nil
+	}
+
+	var zoned bool
+	for _, c := range constraints {
+		if c.Attribute != compose.ZoneAttribute {
+			return placement, errors.Wrapf(errdefs.ErrNotImplemented, "service %q runs on Fargate, which can't be placed by %s", service.Name, c.Attribute)
+		}
+		zoned = true
+	}
+	if !zoned {
+		return placement, nil
+	}
+	var subnets []string
+	for _, subnet := range placement.subnets {
+		if len(compose.AllowedZones(constraints, []string{r.subnetZones[subnet]})) > 0 {
+			subnets = append(subnets, subnet)
+		}
+	}
+	if len(subnets) == 0 {
+		return placement, fmt.Errorf("service %q placement constraints exclude the availability zones of all its subnets", service.Name)
+	}
+	placement.subnets = subnets
+	return placement, nil
+}
+
+// parseSubnetZones looks up the availability zone of the project subnets, if services are pinned to zones
+func (b *ecsAPIService) parseSubnetZones(ctx context.Context, project *types.Project, r awsResources) (map[string]string, error) {
+	zoned := false
+	for _, service := range project.Services {
+		constraints, err := compose.PlacementConstraints(service)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range constraints {
+			if c.Attribute == compose.ZoneAttribute {
+				zoned = true
+			}
+		}
+	}
+	if !zoned {
+		return nil, nil
+	}
+	subnets := append([]string{}, r.subnets...)
+	for _, s := range r.networkSubnets {
+		subnets = append(subnets, s...)
+	}
+	return b.SDK.GetSubnetZones(ctx, subnets)
+}
@@ -0,0 +1,71 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestPlaceFargateServiceInZoneSubnets(t *testing.T) {
+	resources := awsResources{
+		subnets:     []string{"subnet-a", "subnet-b"},
+		subnetZones: map[string]string{"subnet-a": "eu-west-3a", "subnet-b": "eu-west-3b"},
+	}
+	service := types.ServiceConfig{Name: "db", Deploy: &types.DeployConfig{Placement: types.Placement{
+		Constraints: []string{"node.zone == eu-west-3b"},
+	}}}
+	placement, err := resources.placeService(service)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, placement.subnets, []string{"subnet-b"})
+	assert.Assert(t, placement.constraints == nil)
+
+	service.Deploy.Placement.Constraints = []string{"node.zone == eu-west-3c"}
+	_, err = resources.placeService(service)
+	assert.ErrorContains(t, err, "exclude the availability zones of all its subnets")
+
+	service.Deploy.Placement.Constraints = []string{"node.instance_type == t3.large"}
+	_, err = resources.placeService(service)
+	assert.Assert(t, errdefs.IsErrNotImplemented(err))
+}
+
+func TestPlaceEC2Service(t *testing.T) {
+	resources := awsResources{subnets: []string{"subnet-a", "subnet-b"}}
+	service := types.ServiceConfig{Name: "ml", Deploy: &types.DeployConfig{
+		Resources: types.Resources{Reservations: &types.Resource{GenericResources: []types.GenericResource{
+			{DiscreteResourceSpec: &types.DiscreteGenericResource{Kind: "gpus", Value: 1}},
+		}}},
+		Placement: types.Placement{
+			Constraints: []string{"node.instance_type == p3.2xlarge"},
+			Preferences: []types.PlacementPreferences{{Spread: "node.zone"}},
+		},
+	}}
+	placement, err := resources.placeService(service)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, placement.subnets, resources.subnets)
+	assert.DeepEqual(t, placement.constraints, []ecs.Service_PlacementConstraint{
+		{Type: "memberOf", Expression: "attribute:ecs.instance-type == p3.2xlarge"},
+	})
+	assert.DeepEqual(t, placement.strategies, []ecs.Service_PlacementStrategy{
+		{Type: "spread", Field: "attribute:ecs.availability-zone"},
+	})
+}
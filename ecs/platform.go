@@ -0,0 +1,77 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"fmt"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const (
+	arm64Platform = "linux/arm64"
+
+	// runtimePlatformMetadata is the task definition metadata key marshall moves to the RuntimePlatform property,
+	// which goformation doesn't support yet
+	runtimePlatformMetadata = "RuntimePlatform"
+)
+
+// taskPlatform returns the platform the service task runs on. Sidecars without a platform run on the service one.
+func taskPlatform(project *types.Project, service types.ServiceConfig) (string, error) {
+	platform, err := compose.ServicePlatform(service, targetPlatform)
+	if err != nil {
+		return "", err
+	}
+	sidecars, err := compose.Sidecars(project, service)
+	if err != nil {
+		return "", err
+	}
+	platform, err = compose.SinglePlatform(fmt.Sprintf("task of service %q", service.Name), append([]types.ServiceConfig{service}, sidecars...), platform)
+	if err != nil {
+		return "", err
+	}
+	switch platform {
+	case targetPlatform:
+	case arm64Platform:
+		if requireEC2(service) {
+			return "", errors.Wrapf(errdefs.ErrNotImplemented, "service %q can't run GPU tasks on %s", service.Name, platform)
+		}
+	default:
+		return "", errors.Wrapf(errdefs.ErrNotImplemented, "ECS doesn't run %s tasks, service %q must target %s or %s",
+			platform, service.Name, targetPlatform, arm64Platform)
+	}
+	return platform, nil
+}
+
+// setRuntimePlatform selects ARM64 Fargate capacity for tasks which don't run on the default platform
+func setRuntimePlatform(definition *ecs.TaskDefinition, platform string) {
+	if platform != arm64Platform {
+		return
+	}
+	if definition.AWSCloudFormationMetadata == nil {
+		definition.AWSCloudFormationMetadata = map[string]interface{}{}
+	}
+	definition.AWSCloudFormationMetadata[runtimePlatformMetadata] = map[string]string{
+		"CpuArchitecture":       "ARM64",
+		"OperatingSystemFamily": "LINUX",
+	}
+}
@@ -0,0 +1,133 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/awslabs/goformation/v4/cloudformation/iam"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const (
+	// sessionManagerPlugin is the AWS CLI plugin which runs the client side of Session Manager sessions
+	sessionManagerPlugin  = "session-manager-plugin"
+	portForwardingSession = "AWS-StartPortForwardingSession"
+
+	// enableExecuteCommandMetadata is the service metadata key marshall moves to the EnableExecuteCommand property,
+	// which goformation doesn't support yet
+	enableExecuteCommandMetadata = "EnableExecuteCommand"
+)
+
+// execCommandPolicy grants the task role the Session Manager channels ECS Exec opens with the SSM agent of the task
+var execCommandPolicy = iam.Role_Policy{
+	PolicyName: "ExecCommand",
+	PolicyDocument: &PolicyDocument{
+		Statement: []PolicyStatement{
+			{
+				Effect: "Allow",
+				Action: []string{
+					"ssmmessages:CreateControlChannel",
+					"ssmmessages:CreateDataChannel",
+					"ssmmessages:OpenControlChannel",
+					"ssmmessages:OpenDataChannel",
+				},
+				Resource: []string{"*"},
+			},
+		},
+	},
+}
+
+// portForwarded returns true when the service enables ECS Exec with x-aws-port_forward
+func portForwarded(service types.ServiceConfig) bool {
+	enabled, ok := service.Extensions[extensionPortForward].(bool)
+	return ok && enabled
+}
+
+// PortForward opens a Session Manager port forwarding session to a task of the service. This requires the
+// session manager plugin to be installed locally, and the service to enable ECS Exec with x-aws-port_forward.
+func (b *ecsAPIService) PortForward(ctx context.Context, projectName string, service string, localPort, remotePort uint32) error {
+	plugin, err := exec.LookPath(sessionManagerPlugin)
+	if err != nil {
+		return errors.Wrapf(errdefs.ErrNotFound, "port forwarding requires the AWS %s, see https://docs.aws.amazon.com/systems-manager/latest/userguide/session-manager-working-with-install-plugin.html", sessionManagerPlugin)
+	}
+
+	cluster, deployed, err := b.deployedServices(ctx, projectName)
+	if err != nil {
+		return err
+	}
+	deployedService, ok := deployed[service]
+	if !ok {
+		return errors.Wrapf(errdefs.ErrNotFound, "service %q", service)
+	}
+	task, runtimeID, err := b.SDK.GetTaskContainer(ctx, cluster, deployedService.ARN, service)
+	if err != nil {
+		return err
+	}
+
+	input := &ssm.StartSessionInput{
+		Target:       aws.String(ssmTarget(cluster, task, runtimeID)),
+		DocumentName: aws.String(portForwardingSession),
+		Parameters: map[string][]*string{
+			"portNumber":      {aws.String(fmt.Sprint(remotePort))},
+			"localPortNumber": {aws.String(fmt.Sprint(localPort))},
+		},
+	}
+	session, err := b.SDK.StartSession(ctx, input)
+	if err != nil {
+		return errors.Wrapf(err, "cannot start a session with service %q, make sure it sets %s: true", service, extensionPortForward)
+	}
+	sessionJSON, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	inputJSON, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+
+	// same arguments as the AWS CLI passes to the plugin
+	cmd := exec.CommandContext(ctx, plugin, string(sessionJSON), b.Region, "StartSession", b.ctx.Profile, string(inputJSON),
+		fmt.Sprintf("https://ssm.%s.amazonaws.com", b.Region))
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err = cmd.Run()
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// ssmTarget returns the Session Manager target of a task container: ecs:<cluster name>_<task ID>_<container runtime ID>
+func ssmTarget(cluster, task, runtimeID string) string {
+	return fmt.Sprintf("ecs:%s_%s_%s", lastSegment(cluster), lastSegment(task), runtimeID)
+}
+
+func lastSegment(arn string) string {
+	return arn[strings.LastIndex(arn, "/")+1:]
+}
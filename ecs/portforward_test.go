@@ -0,0 +1,59 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation/iam"
+	"gotest.tools/v3/assert"
+)
+
+func TestSSMTarget(t *testing.T) {
+	target := ssmTarget("arn:aws:ecs:eu-west-3:123456789012:cluster/myproject",
+		"arn:aws:ecs:eu-west-3:123456789012:task/myproject/0123456789abcdef", "0123456789abcdef-1234567890")
+	assert.Equal(t, target, "ecs:myproject_0123456789abcdef_0123456789abcdef-1234567890")
+	assert.Equal(t, ssmTarget("myproject", "0123456789abcdef", "abc"), "ecs:myproject_0123456789abcdef_abc")
+}
+
+func TestPortForwardEnablesExecCommand(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  api:
+    image: nginx
+    x-aws-port_forward: true
+  web:
+    image: nginx
+`)
+	raw, err := marshall(template)
+	assert.NilError(t, err)
+	var marshalled struct {
+		Resources map[string]struct {
+			Properties map[string]interface{}
+		}
+	}
+	assert.NilError(t, json.Unmarshal(raw, &marshalled))
+	assert.Equal(t, marshalled.Resources[serviceResourceName("api")].Properties["EnableExecuteCommand"], true)
+	_, ok := marshalled.Resources[serviceResourceName("web")].Properties["EnableExecuteCommand"]
+	assert.Assert(t, !ok)
+
+	role := template.Resources["ApiTaskRole"].(*iam.Role)
+	assert.DeepEqual(t, role.Policies, []iam.Role_Policy{execCommandPolicy})
+	_, ok = template.Resources["WebTaskRole"]
+	assert.Assert(t, !ok)
+}
@@ -0,0 +1,126 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/registry"
+)
+
+// preflight checks the project can be deployed before any resource is created, reporting all problems at once
+func (b *ecsAPIService) preflight(ctx context.Context, project *types.Project, options compose.UpOptions) error {
+	return compose.Preflight(ctx,
+		func(context.Context) error {
+			return validateResources(project)
+		},
+		func(context.Context) error {
+			return checkRegion(b.Region)
+		},
+		func(ctx context.Context) error {
+			return b.checkFargateQuota(ctx, project)
+		},
+		func(ctx context.Context) error {
+			var services []types.ServiceConfig
+			for _, service := range project.Services {
+				// images built by --push don't exist yet
+				if options.Push && service.Build != nil {
+					continue
+				}
+				services = append(services, service)
+			}
+			return registry.CheckImages(ctx, services)
+		},
+	)
+}
+
+// checkRegion verifies ECS is available in the region, as far as the AWS SDK knows
+func checkRegion(region string) error {
+	partition, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region)
+	if !ok {
+		return fmt.Errorf("unknown AWS region %q", region)
+	}
+	if _, ok := partition.Services()[ecs.EndpointsID].Regions()[region]; !ok {
+		return fmt.Errorf("ECS is not available in region %q", region)
+	}
+	return nil
+}
+
+// checkFargateQuota verifies the account has enough Fargate vCPU quota left in the region to run the project services
+func (b *ecsAPIService) checkFargateQuota(ctx context.Context, project *types.Project) error {
+	required, err := fargateVCPUs(project)
+	if err != nil || required == 0 {
+		return err
+	}
+	quota, err := b.SDK.GetFargateVCPUQuota(ctx)
+	if err != nil {
+		logrus.Warnf("cannot check Fargate vCPU quota: %v", err)
+		return nil
+	}
+	status, err := b.SDK.GetStackStatus(ctx, project.Name)
+	if err != nil {
+		return err
+	}
+	available := quota
+	if status == "" {
+		// tasks of an already deployed project are counted in the usage, only check new projects against it
+		usage, err := b.SDK.GetFargateVCPUUsage(ctx)
+		if err != nil {
+			logrus.Warnf("cannot check Fargate vCPU usage: %v", err)
+		} else {
+			available = quota - usage
+		}
+	}
+	if required > available {
+		return fmt.Errorf("the project requires %g Fargate vCPUs, but only %g are available out of the %g allowed in region %s: "+
+			"request a quota increase for %q in the Service Quotas console", required, available, quota, b.Region, fargateVCPUQuotaCode)
+	}
+	return nil
+}
+
+// fargateVCPUs returns the number of vCPUs the project services run on Fargate, with all their replicas
+func fargateVCPUs(project *types.Project) (float64, error) {
+	total := 0.
+	for _, service := range project.Services {
+		if requireEC2(service) {
+			continue
+		}
+		cpu, _, err := toLimits(service)
+		if err != nil {
+			// reported by validateResources
+			continue
+		}
+		units, err := strconv.ParseFloat(cpu, 64)
+		if err != nil {
+			return 0, err
+		}
+		replicas := 1
+		if service.Deploy != nil && service.Deploy.Replicas != nil {
+			replicas = int(*service.Deploy.Replicas)
+		}
+		total += units / 1024 * float64(replicas)
+	}
+	return total, nil
+}
@@ -0,0 +1,46 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCheckRegion(t *testing.T) {
+	assert.NilError(t, checkRegion("eu-west-3"))
+	assert.ErrorContains(t, checkRegion("moon-east-1"), "unknown AWS region")
+}
+
+func TestFargateVCPUs(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  web:
+    image: nginx
+    deploy:
+      replicas: 3
+      resources:
+        limits:
+          cpus: '1'
+  worker:
+    image: worker
+`)
+	vcpus, err := fargateVCPUs(project)
+	assert.NilError(t, err)
+	assert.Equal(t, vcpus, 3.25)
+}
@@ -0,0 +1,86 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"sort"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/progress"
+)
+
+// pruneOrder lists resource types in the order they can be deleted, load balancers using target groups
+var pruneOrder = []string{
+	resourceLoadBalancer,
+	resourceTargetGroup,
+	resourceSecret,
+	resourceRepository,
+	resourceFileSystem,
+	resourceLogGroup,
+}
+
+func (b *ecsAPIService) Prune(ctx context.Context, options compose.PruneOptions) ([]compose.OrphanResource, error) {
+	// resources of a project with a stack, even a failed or in progress one, are never pruned
+	deployed, err := b.SDK.ListDeployedProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := b.SDK.ListProjectResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	orphans := compose.Orphans(resources, deployed, options)
+	sort.SliceStable(orphans, func(i, j int) bool {
+		return typeOrder(orphans[i].Type) < typeOrder(orphans[j].Type)
+	})
+	if options.DryRun {
+		return orphans, nil
+	}
+
+	w := progress.ContextWriter(ctx)
+	for _, r := range orphans {
+		w.Event(progress.Event{
+			ID:         r.ID,
+			Status:     progress.Working,
+			StatusText: "Deleting",
+		})
+		if err := b.SDK.DeleteProjectResource(ctx, r); err != nil {
+			w.Event(progress.Event{
+				ID:         r.ID,
+				Status:     progress.Error,
+				StatusText: "Deleting",
+			})
+			return nil, err
+		}
+		w.Event(progress.Event{
+			ID:         r.ID,
+			Status:     progress.Done,
+			StatusText: "Deleted",
+		})
+	}
+	return orphans, nil
+}
+
+func typeOrder(resourceType string) int {
+	for i, t := range pruneOrder {
+		if t == resourceType {
+			return i
+		}
+	}
+	return len(pruneOrder)
+}
@@ -0,0 +1,84 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/cloudformation/cloudformationiface"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+// fakeCloudFormation returns a page of stacks per DescribeStacks call
+type fakeCloudFormation struct {
+	cloudformationiface.CloudFormationAPI
+	pages [][]*cloudformation.Stack
+}
+
+func (f *fakeCloudFormation) DescribeStacksPagesWithContext(ctx aws.Context, input *cloudformation.DescribeStacksInput, fn func(*cloudformation.DescribeStacksOutput, bool) bool, opts ...request.Option) error {
+	for i, page := range f.pages {
+		if !fn(&cloudformation.DescribeStacksOutput{Stacks: page}, i == len(f.pages)-1) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func stack(name string, status string, project string) *cloudformation.Stack {
+	s := &cloudformation.Stack{StackName: aws.String(name), StackId: aws.String("id-" + name), StackStatus: aws.String(status)}
+	if project != "" {
+		s.Tags = []*cloudformation.Tag{{Key: aws.String(compose.ProjectTag), Value: aws.String(project)}}
+	}
+	return s
+}
+
+func TestListDeployedProjectsPages(t *testing.T) {
+	cf := &fakeCloudFormation{pages: [][]*cloudformation.Stack{
+		{stack("shop", "CREATE_COMPLETE", "shop")},
+		{stack("blog", "ROLLBACK_COMPLETE", "blog"), stack("untagged", "UPDATE_IN_PROGRESS", "")},
+		{stack("gone", cloudformation.StackStatusDeleteComplete, "gone")},
+	}}
+	projects, err := sdk{CF: cf}.ListDeployedProjects(context.Background())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, projects, []string{"shop", "shop", "blog", "blog", "untagged"})
+
+	orphans := compose.Orphans([]compose.OrphanResource{
+		{ID: "lb-blog", Project: "blog"},
+		{ID: "lb-untagged", Project: "untagged"},
+		{ID: "lb-gone", Project: "gone"},
+	}, projects, compose.PruneOptions{})
+	assert.DeepEqual(t, orphans, []compose.OrphanResource{{ID: "lb-gone", Project: "gone"}})
+}
+
+func TestListStacksPages(t *testing.T) {
+	cf := &fakeCloudFormation{pages: [][]*cloudformation.Stack{
+		{stack("shop", "CREATE_COMPLETE", "shop")},
+		{stack("blog", "UPDATE_IN_PROGRESS", "blog")},
+	}}
+	stacks, err := sdk{CF: cf}.ListStacks(context.Background(), "")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, stacks, []compose.Stack{
+		{ID: "id-shop", Name: "shop", Status: compose.RUNNING},
+		{ID: "id-blog", Name: "blog", Status: compose.UPDATING},
+	})
+}
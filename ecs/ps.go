@@ -25,6 +25,18 @@ import (
 )
 
 func (b *ecsAPIService) Ps(ctx context.Context, project string) ([]compose.ServiceStatus, error) {
+	regions, err := b.stackRegions(ctx, project)
+	if err != nil {
+		return nil, permissionError("ps", err)
+	}
+	if len(regions) > 0 {
+		return b.psRegions(ctx, project, regions)
+	}
+	status, err := b.ps(ctx, project)
+	return status, permissionError("ps", err)
+}
+
+func (b *ecsAPIService) ps(ctx context.Context, project string) ([]compose.ServiceStatus, error) {
 	resources, err := b.SDK.ListStackResources(ctx, project)
 	if err != nil {
 		return nil, err
@@ -62,6 +74,7 @@ func (b *ecsAPIService) Ps(ctx context.Context, project string) ([]compose.Servi
 				strings.ToLower(lb.Protocol)))
 		}
 		state.Ports = ports
+		state.Region = b.Region
 		status[i] = state
 	}
 	return status, nil
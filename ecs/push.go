@@ -0,0 +1,166 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/containerd/containerd/platforms"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/progress"
+	"github.com/docker/compose-cli/utils/dockercli"
+)
+
+// targetPlatform is the platform Amazon ECS runs tasks on
+const targetPlatform = "linux/amd64"
+
+// pushImages builds services declaring a build section, pushes the resulting images to ECR
+// and substitutes the ECR repository URI as service image
+func (b *ecsAPIService) pushImages(ctx context.Context, project *types.Project) error {
+	w := progress.ContextWriter(ctx)
+	needsLogin := false
+	for _, service := range project.Services {
+		needsLogin = needsLogin || service.Build != nil
+	}
+	if !needsLogin {
+		return nil
+	}
+	if err := b.loginECR(ctx); err != nil {
+		return err
+	}
+	// services are built in dependency order, so an image can be based on another service image
+	return compose.InDependencyOrder(ctx, project, func(ctx context.Context, service *types.ServiceConfig) error {
+		if service.Build == nil {
+			return nil
+		}
+
+		w.Event(progress.Event{
+			ID:         service.Name,
+			Status:     progress.Working,
+			StatusText: "Creating repository",
+		})
+		repository := fmt.Sprintf("%s/%s", project.Name, service.Name)
+		uri, err := b.SDK.CreateRepository(ctx, repository, project.Name)
+		if err != nil {
+			return err
+		}
+		image := uri + ":latest"
+		args, pushed, err := buildCommand(*service, project.Name, image)
+		if err != nil {
+			return err
+		}
+
+		w.Event(progress.Event{
+			ID:         service.Name,
+			Status:     progress.Working,
+			StatusText: "Building",
+		})
+		if err := dockercli.Run(ctx, project.WorkingDir, args...); err != nil {
+			return err
+		}
+		if !pushed {
+			w.Event(progress.Event{
+				ID:         service.Name,
+				Status:     progress.Working,
+				StatusText: "Pushing",
+			})
+			if err := dockercli.Run(ctx, project.WorkingDir, "push", image); err != nil {
+				return err
+			}
+		}
+
+		// services run the pushed digest, so that the task definition changes each time a new image is pushed
+		digest, err := b.SDK.GetImageDigest(ctx, repository, "latest")
+		if err != nil {
+			return errors.Wrapf(err, "cannot get the digest of the image pushed for service %q", service.Name)
+		}
+		service.Image = uri + "@" + digest
+		service.Build = nil
+		w.Event(progress.Event{
+			ID:         service.Name,
+			Status:     progress.Done,
+			StatusText: "Pushed",
+		})
+		return nil
+	})
+}
+
+func (b *ecsAPIService) loginECR(ctx context.Context) error {
+	server, username, password, err := b.SDK.GetECRAuthorization(ctx)
+	if err != nil {
+		return errors.Wrap(err, "cannot get ECR authorization token")
+	}
+	cmd := exec.Command("docker", "--context", "default", "login", "--username", username, "--password-stdin", server)
+	cmd.Stdin = strings.NewReader(password)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return errors.Wrapf(err, "could not 'docker login' to %s :\n%s", server, string(output))
+	}
+	return nil
+}
+
+// buildCommand returns the docker command building the image of a service for its platform, and whether it also
+// pushes the image: cross-platform builds are run by buildx, which pushes the image as it completes
+func buildCommand(service types.ServiceConfig, project string, image string) ([]string, bool, error) {
+	platform := service.Platform
+	if platform == "" {
+		platform = targetPlatform
+	}
+	p, err := platforms.Parse(platform)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "invalid platform %q for service %q", platform, service.Name)
+	}
+	if platforms.Default().Match(p) {
+		return buildArgs(service, image, "--label", dockercli.ProjectLabel(project)), false, nil
+	}
+	return append([]string{"buildx"}, buildArgs(service, image, "--label", dockercli.ProjectLabel(project), "--platform", platforms.Format(p), "--push")...), true, nil
+}
+
+func buildArgs(service types.ServiceConfig, image string, flags ...string) []string {
+	args := []string{"build", "--tag", image}
+	if dockerfile := service.Build.Dockerfile; dockerfile != "" {
+		if !filepath.IsAbs(dockerfile) {
+			dockerfile = filepath.Join(service.Build.Context, dockerfile)
+		}
+		args = append(args, "--file", dockerfile)
+	}
+	if service.Build.Target != "" {
+		args = append(args, "--target", service.Build.Target)
+	}
+	var keys []string
+	for k := range service.Build.Args {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if v := service.Build.Args[k]; v != nil {
+			args = append(args, "--build-arg", fmt.Sprintf("%s=%s", k, *v))
+		} else {
+			args = append(args, "--build-arg", k)
+		}
+	}
+	args = append(args, flags...)
+	return append(args, service.Build.Context)
+}
@@ -0,0 +1,105 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecr/ecriface"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/containerd/containerd/platforms"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/utils/dockercli"
+)
+
+// fakeECR describes the images of repositories by tag
+type fakeECR struct {
+	ecriface.ECRAPI
+	digests map[string]string
+}
+
+func (f fakeECR) DescribeImagesWithContext(ctx aws.Context, input *ecr.DescribeImagesInput, opts ...request.Option) (*ecr.DescribeImagesOutput, error) {
+	output := &ecr.DescribeImagesOutput{}
+	for _, id := range input.ImageIds {
+		if digest, ok := f.digests[aws.StringValue(input.RepositoryName)+":"+aws.StringValue(id.ImageTag)]; ok {
+			output.ImageDetails = append(output.ImageDetails, &ecr.ImageDetail{ImageDigest: aws.String(digest)})
+		}
+	}
+	return output, nil
+}
+
+func TestGetImageDigest(t *testing.T) {
+	s := sdk{ECR: fakeECR{digests: map[string]string{"shop/web:latest": "sha256:abc"}}}
+	digest, err := s.GetImageDigest(context.Background(), "shop/web", "latest")
+	assert.NilError(t, err)
+	assert.Equal(t, digest, "sha256:abc")
+
+	_, err = s.GetImageDigest(context.Background(), "shop/db", "latest")
+	assert.Assert(t, errdefs.IsNotFoundError(err))
+}
+
+func TestBuildCommand(t *testing.T) {
+	version := "1.2"
+	label := dockercli.ProjectLabel("shop")
+	image := "123456789012.dkr.ecr.eu-west-3.amazonaws.com/shop/web:latest"
+	cases := []struct {
+		name    string
+		service types.ServiceConfig
+		args    []string
+		pushed  bool
+	}{
+		{
+			name: "same platform",
+			service: types.ServiceConfig{Name: "web", Platform: platforms.DefaultString(), Build: &types.BuildConfig{
+				Context:    "web",
+				Dockerfile: "Dockerfile.prod",
+				Target:     "runtime",
+				Args:       types.MappingWithEquals{"VERSION": &version, "TOKEN": nil},
+			}},
+			args: []string{"build", "--tag", image, "--file", filepath.Join("web", "Dockerfile.prod"), "--target", "runtime",
+				"--build-arg", "TOKEN", "--build-arg", "VERSION=1.2", "--label", label, "web"},
+		},
+		{
+			name: "cross platform",
+			service: types.ServiceConfig{Name: "web", Platform: "linux/s390x", Build: &types.BuildConfig{
+				Context:    "web",
+				Dockerfile: filepath.Join(string(filepath.Separator), "build", "Dockerfile"),
+			}},
+			args: []string{"buildx", "build", "--tag", image, "--file", filepath.Join(string(filepath.Separator), "build", "Dockerfile"),
+				"--label", label, "--platform", "linux/s390x", "--push", "web"},
+			pushed: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			args, pushed, err := buildCommand(c.service, "shop", image)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, args, c.args)
+			assert.Equal(t, pushed, c.pushed)
+		})
+	}
+
+	_, _, err := buildCommand(types.ServiceConfig{Name: "web", Platform: "not a platform", Build: &types.BuildConfig{Context: "."}}, "shop", image)
+	assert.ErrorContains(t, err, `invalid platform "not a platform" for service "web"`)
+}
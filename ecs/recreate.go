@@ -0,0 +1,114 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/awslabs/goformation/v4/cloudformation/tags"
+	"github.com/compose-spec/compose-go/types"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/progress"
+)
+
+// deployedServices returns the cluster and the services deployed by the project stack
+func (b *ecsAPIService) deployedServices(ctx context.Context, project string) (string, map[string]deployedService, error) {
+	resources, err := b.SDK.ListStackResources(ctx, project)
+	if err != nil {
+		return "", nil, err
+	}
+	var (
+		cluster     = project
+		servicesARN []string
+	)
+	for _, r := range resources {
+		switch r.Type {
+		case "AWS::ECS::Service":
+			servicesARN = append(servicesARN, r.ARN)
+		case "AWS::ECS::Cluster":
+			cluster = r.ARN
+		}
+	}
+	if len(servicesARN) == 0 {
+		return cluster, map[string]deployedService{}, nil
+	}
+	deployed, err := b.SDK.DescribeDeployedServices(ctx, cluster, servicesARN)
+	return cluster, deployed, err
+}
+
+// keepDeployedServices pins already deployed services to the task definition they run, so that the stack update
+// doesn't redeploy them. This applies to services which configuration didn't change, or all of them with RecreateNever.
+func keepDeployedServices(ctx context.Context, project *types.Project, template *cloudformation.Template, deployed map[string]deployedService, digests compose.ImageDigests, recreate string) error {
+	w := progress.ContextWriter(ctx)
+	for _, service := range project.Services {
+		current, ok := deployed[service.Name]
+		if !ok || current.TaskDefinition == "" {
+			continue
+		}
+		hash, err := compose.ServiceHash(service, digests)
+		if err != nil {
+			return err
+		}
+		if recreate != compose.RecreateNever && hash != current.ConfigHash {
+			continue
+		}
+		resource, ok := template.Resources[serviceResourceName(service.Name)].(*ecs.Service)
+		if !ok {
+			continue
+		}
+		resource.TaskDefinition = current.TaskDefinition
+		for i, tag := range resource.Tags {
+			if tag.Key == compose.ConfigHashTag {
+				resource.Tags[i] = tags.Tag{Key: compose.ConfigHashTag, Value: current.ConfigHash}
+			}
+		}
+		w.Event(progress.Event{
+			ID:         service.Name,
+			Status:     progress.Done,
+			StatusText: "Up to date",
+		})
+	}
+	return nil
+}
+
+// forceNewDeployments replaces running tasks of services which were already deployed before the stack update
+func (b *ecsAPIService) forceNewDeployments(ctx context.Context, project *types.Project, cluster string, deployed map[string]deployedService) error {
+	w := progress.ContextWriter(ctx)
+	return compose.ForEachService(ctx, project, func(ctx context.Context, s *types.ServiceConfig) error {
+		service, ok := deployed[s.Name]
+		if !ok {
+			return nil
+		}
+		w.Event(progress.Event{
+			ID:         s.Name,
+			Status:     progress.Working,
+			StatusText: "Recreating",
+		})
+		if err := b.SDK.ForceNewDeployment(ctx, cluster, service.ARN); err != nil {
+			return err
+		}
+		w.Event(progress.Event{
+			ID:         s.Name,
+			Status:     progress.Done,
+			StatusText: "Recreated",
+		})
+		return nil
+	})
+}
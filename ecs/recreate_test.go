@@ -0,0 +1,166 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	ecsapi "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/ecs/ecsiface"
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestKeepDeployedServices(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  foo:
+    image: hello_world
+  bar:
+    image: hello_world
+`)
+	template := convertYaml(t, `
+services:
+  foo:
+    image: hello_world
+  bar:
+    image: hello_world
+`)
+	fooHash, err := compose.ServiceHash(project.Services[0], nil)
+	assert.NilError(t, err)
+	deployed := map[string]deployedService{
+		project.Services[0].Name: {ARN: "fooARN", TaskDefinition: "fooTaskDefinition:1", ConfigHash: fooHash},
+		project.Services[1].Name: {ARN: "barARN", TaskDefinition: "barTaskDefinition:1", ConfigHash: "outdated"},
+	}
+
+	err = keepDeployedServices(context.TODO(), project, template, deployed, nil, compose.RecreateDiverged)
+	assert.NilError(t, err)
+	foo := template.Resources[serviceResourceName(project.Services[0].Name)].(*ecs.Service)
+	assert.Equal(t, foo.TaskDefinition, "fooTaskDefinition:1")
+	bar := template.Resources[serviceResourceName(project.Services[1].Name)].(*ecs.Service)
+	assert.Assert(t, bar.TaskDefinition != "barTaskDefinition:1")
+
+	err = keepDeployedServices(context.TODO(), project, template, deployed, nil, compose.RecreateNever)
+	assert.NilError(t, err)
+	assert.Equal(t, bar.TaskDefinition, "barTaskDefinition:1")
+	for _, tag := range bar.Tags {
+		if tag.Key == compose.ConfigHashTag {
+			assert.Equal(t, tag.Value, "outdated")
+		}
+	}
+}
+
+func TestKeepDeployedServicesResolvedDigest(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  foo:
+    image: nginx:latest
+`)
+	template := convertYaml(t, `
+services:
+  foo:
+    image: nginx:latest
+`)
+	previousHash, err := compose.ServiceHash(project.Services[0], compose.ImageDigests{
+		"nginx:latest": "nginx@sha256:2222222222222222222222222222222222222222222222222222222222222222",
+	})
+	assert.NilError(t, err)
+	deployed := map[string]deployedService{
+		"foo": {ARN: "fooARN", TaskDefinition: "fooTaskDefinition:1", ConfigHash: previousHash},
+	}
+
+	// the tag was pushed again, resolving to another digest
+	err = keepDeployedServices(context.TODO(), project, template, deployed, compose.ImageDigests{
+		"nginx:latest": "nginx@sha256:1111111111111111111111111111111111111111111111111111111111111111",
+	}, compose.RecreateDiverged)
+	assert.NilError(t, err)
+	foo := template.Resources[serviceResourceName("foo")].(*ecs.Service)
+	assert.Assert(t, foo.TaskDefinition != "fooTaskDefinition:1")
+}
+
+type fakeECS struct {
+	ecsiface.ECSAPI
+	calls [][]string
+	mu    sync.Mutex
+}
+
+func (f *fakeECS) UpdateServiceWithContext(ctx aws.Context, input *ecsapi.UpdateServiceInput, opts ...request.Option) (*ecsapi.UpdateServiceOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, []string{aws.StringValue(input.Cluster), aws.StringValue(input.Service)})
+	return &ecsapi.UpdateServiceOutput{}, nil
+}
+
+func (f *fakeECS) DescribeServicesWithContext(ctx aws.Context, input *ecsapi.DescribeServicesInput, opts ...request.Option) (*ecsapi.DescribeServicesOutput, error) {
+	if len(input.Services) > 10 {
+		return nil, errors.New("InvalidParameterException: services can have at most 10 items")
+	}
+	f.calls = append(f.calls, aws.StringValueSlice(input.Services))
+	var services []*ecsapi.Service
+	for _, arn := range input.Services {
+		services = append(services, &ecsapi.Service{
+			ServiceArn:     arn,
+			TaskDefinition: aws.String(aws.StringValue(arn) + ":1"),
+			Tags:           []*ecsapi.Tag{{Key: aws.String(compose.ServiceTag), Value: arn}},
+		})
+	}
+	return &ecsapi.DescribeServicesOutput{Services: services}, nil
+}
+
+func TestDescribeDeployedServicesBatches(t *testing.T) {
+	var arns []string
+	for i := 0; i < 23; i++ {
+		arns = append(arns, fmt.Sprintf("service%d", i))
+	}
+	fake := &fakeECS{}
+	deployed, err := sdk{ECS: fake}.DescribeDeployedServices(context.TODO(), "cluster", arns)
+	assert.NilError(t, err)
+	assert.Equal(t, len(deployed), 23)
+	assert.Equal(t, len(fake.calls), 3)
+	assert.Equal(t, len(fake.calls[2]), 3)
+	assert.Equal(t, deployed["service22"].TaskDefinition, "service22:1")
+}
+
+func TestForceNewDeployments(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  foo:
+    image: hello_world
+  bar:
+    image: hello_world
+  new:
+    image: hello_world
+`)
+	fake := &fakeECS{}
+	b := &ecsAPIService{SDK: sdk{ECS: fake}}
+	err := b.forceNewDeployments(context.TODO(), project, "cluster", map[string]deployedService{
+		"foo": {ARN: "fooARN"},
+		"bar": {ARN: "barARN"},
+	})
+	assert.NilError(t, err)
+	sort.Slice(fake.calls, func(i, j int) bool { return fake.calls[i][1] < fake.calls[j][1] })
+	assert.DeepEqual(t, fake.calls, [][]string{{"cluster", "barARN"}, {"cluster", "fooARN"}})
+}
@@ -0,0 +1,246 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/progress"
+	"github.com/docker/compose-cli/utils"
+	"github.com/docker/compose-cli/utils/dockercli"
+)
+
+// regionsTag records on each regional stack the regions a project was deployed to with compose up --regions
+const regionsTag = "com.docker.compose.regions"
+
+// regional returns the backend operating the context account in another region
+func (b *ecsAPIService) regional(region string) (*ecsAPIService, error) {
+	if region == b.Region {
+		return b, nil
+	}
+	ecsCtx := b.ctx
+	ecsCtx.Region = region
+	return getEcsAPIService(ecsCtx)
+}
+
+// upRegions deploys the project to each region concurrently. A region failing doesn't interrupt the others.
+// Regions the project was deployed to previously stay recorded, so that down still removes it from them.
+func (b *ecsAPIService) upRegions(ctx context.Context, project *types.Project, options compose.UpOptions) error {
+	for _, region := range options.Regions {
+		if err := checkRegion(region); err != nil {
+			return err
+		}
+	}
+	recorded, err := b.stackRegions(ctx, project.Name)
+	if err != nil {
+		return permissionError("up", err)
+	}
+	deploy := options.Regions
+	regions, dropped := mergeRegions(deploy, recorded)
+	if len(dropped) > 0 {
+		compose.Warn(ctx, "project %s is still deployed to %s, run compose down to remove it from all regions", project.Name, strings.Join(dropped, ", "))
+	}
+	tags := map[string]string{}
+	for k, v := range options.Tags {
+		tags[k] = v
+	}
+	tags[regionsTag] = strings.Join(regions, ",")
+	options.Tags = tags
+	options.Regions = nil
+	// the context region may have no stack to find the regions from, they are recorded there for down and ps
+	if err := b.SDK.PutProjectRegions(ctx, project.Name, regions); err != nil {
+		return permissionError("up", err)
+	}
+
+	return b.eachRegion(ctx, deploy, "Deploying", "Deployed", func(ctx context.Context, r *ecsAPIService) error {
+		return permissionError("up", r.up(ctx, regionalProject(project), options))
+	})
+}
+
+// mergeRegions returns the regions to record for a project deployed to requested, keeping the recorded ones it
+// was deployed to previously, and these dropped regions the project won't be updated in
+func mergeRegions(requested []string, recorded []string) ([]string, []string) {
+	regions := append([]string{}, requested...)
+	var dropped []string
+	for _, region := range recorded {
+		if !utils.StringContains(requested, region) {
+			dropped = append(dropped, region)
+			regions = append(regions, region)
+		}
+	}
+	return regions, dropped
+}
+
+// eachRegion runs fn concurrently for each region, reporting a progress event per region
+func (b *ecsAPIService) eachRegion(ctx context.Context, regions []string, working string, done string, fn func(ctx context.Context, r *ecsAPIService) error) error {
+	w := progress.ContextWriter(ctx)
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs *multierror.Error
+	)
+	for _, region := range regions {
+		region := region
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.Event(progress.Event{ID: region, Status: progress.Working, StatusText: working})
+			r, err := b.regional(region)
+			if err == nil {
+				err = fn(progress.WithPrefix(ctx, region+"/"), r)
+			}
+			if err != nil {
+				w.Event(progress.Event{ID: region, Status: progress.Error, StatusText: "Failed"})
+				mu.Lock()
+				errs = multierror.Append(errs, errors.Wrapf(err, "region %s", region))
+				mu.Unlock()
+				return
+			}
+			w.Event(progress.Event{ID: region, Status: progress.Done, StatusText: done})
+		}()
+	}
+	wg.Wait()
+	return errs.ErrorOrNil()
+}
+
+// stackRegions returns the regions the project was deployed to with compose up --regions, none if it was
+// deployed to the context region only. The regions are recorded in the context region, a stack there being
+// tagged with them only when the context region is one of them.
+func (b *ecsAPIService) stackRegions(ctx context.Context, project string) ([]string, error) {
+	regions, err := b.SDK.GetProjectRegions(ctx, project)
+	if err != nil || len(regions) > 0 {
+		return regions, err
+	}
+	tags, err := b.SDK.GetStackTags(ctx, project)
+	if errdefs.IsNotFoundError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tags[regionsTag] == "" {
+		return nil, nil
+	}
+	return strings.Split(tags[regionsTag], ","), nil
+}
+
+// downRegions removes the project from each region it was deployed to, then removes local images once
+func (b *ecsAPIService) downRegions(ctx context.Context, project string, regions []string, options compose.DownOptions) error {
+	removeImages := options.RemoveImages
+	options.RemoveImages = ""
+	var (
+		mu     sync.Mutex
+		images []string
+	)
+	err := b.eachRegion(ctx, regions, "Removing", "Removed", func(ctx context.Context, r *ecsAPIService) error {
+		if removeImages == compose.RemoveImagesAll {
+			deployed, err := r.deployedImages(ctx, project)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			images = append(images, deployed...)
+			mu.Unlock()
+		}
+		return permissionError("down", r.down(ctx, project, options))
+	})
+	if err != nil {
+		return err
+	}
+	if err := b.SDK.DeleteProjectRegions(ctx, project); err != nil {
+		return permissionError("down", err)
+	}
+	if removeImages == "" {
+		return nil
+	}
+	built, err := dockercli.ProjectImages(ctx, project)
+	if err != nil {
+		return err
+	}
+	return dockercli.RemoveImages(ctx, append(built, images...))
+}
+
+// psRegions lists the project services in each region it was deployed to
+func (b *ecsAPIService) psRegions(ctx context.Context, project string, regions []string) ([]compose.ServiceStatus, error) {
+	var (
+		mu     sync.Mutex
+		status []compose.ServiceStatus
+	)
+	err := b.eachRegion(ctx, regions, "Listing", "Listed", func(ctx context.Context, r *ecsAPIService) error {
+		regional, err := r.ps(ctx, project)
+		if err != nil {
+			return permissionError("ps", err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		status = append(status, regional...)
+		return nil
+	})
+	sort.SliceStable(status, func(i, j int) bool {
+		if status[i].Region != status[j].Region {
+			return status[i].Region < status[j].Region
+		}
+		return status[i].Name < status[j].Name
+	})
+	return status, err
+}
+
+// regionalProject copies the project maps the deployment updates, so that regions can be deployed concurrently
+func regionalProject(project *types.Project) *types.Project {
+	p := *project
+	p.Extensions = copyExtensions(project.Extensions)
+	p.Services = make(types.Services, len(project.Services))
+	for i, service := range project.Services {
+		service.Extensions = copyExtensions(service.Extensions)
+		if service.Labels != nil {
+			labels := types.Labels{}
+			for k, v := range service.Labels {
+				labels[k] = v
+			}
+			service.Labels = labels
+		}
+		if service.Environment != nil {
+			env := types.MappingWithEquals{}
+			for k, v := range service.Environment {
+				env[k] = v
+			}
+			service.Environment = env
+		}
+		p.Services[i] = service
+	}
+	return &p
+}
+
+func copyExtensions(extensions map[string]interface{}) map[string]interface{} {
+	if extensions == nil {
+		return nil
+	}
+	c := make(map[string]interface{}, len(extensions))
+	for k, v := range extensions {
+		c[k] = v
+	}
+	return c
+}
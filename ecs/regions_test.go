@@ -0,0 +1,131 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestRegionalProjectIsolatesServices(t *testing.T) {
+	value := "1"
+	project := &types.Project{
+		Name: "test",
+		Services: []types.ServiceConfig{
+			{
+				Name:        "web",
+				Image:       "nginx",
+				Environment: types.MappingWithEquals{"A": &value},
+				Labels:      types.Labels{"a": "1"},
+				Extensions:  map[string]interface{}{"x-aws-role": "role"},
+			},
+		},
+	}
+
+	regional := regionalProject(project)
+	regional.Services[0].Image = "nginx@sha256:digest"
+	regional.Services[0].Environment["B"] = &value
+	regional.Services[0].Labels["b"] = "2"
+	regional.Services[0].Extensions[extensionPullCredentials] = "arn"
+
+	service := project.Services[0]
+	assert.Equal(t, service.Image, "nginx")
+	assert.Equal(t, len(service.Environment), 1)
+	assert.DeepEqual(t, service.Labels, types.Labels{"a": "1"})
+	assert.DeepEqual(t, service.Extensions, map[string]interface{}{"x-aws-role": "role"})
+}
+
+// fakeParameters is a Parameter Store holding parameters by name
+type fakeParameters struct {
+	ssmiface.SSMAPI
+	parameters map[string]string
+}
+
+func (f *fakeParameters) PutParameterWithContext(ctx aws.Context, input *ssm.PutParameterInput, opts ...request.Option) (*ssm.PutParameterOutput, error) {
+	f.parameters[aws.StringValue(input.Name)] = aws.StringValue(input.Value)
+	return &ssm.PutParameterOutput{}, nil
+}
+
+func (f *fakeParameters) GetParameterWithContext(ctx aws.Context, input *ssm.GetParameterInput, opts ...request.Option) (*ssm.GetParameterOutput, error) {
+	value, ok := f.parameters[aws.StringValue(input.Name)]
+	if !ok {
+		return nil, awserr.New(ssm.ErrCodeParameterNotFound, "not found", nil)
+	}
+	return &ssm.GetParameterOutput{Parameter: &ssm.Parameter{Value: aws.String(value)}}, nil
+}
+
+func (f *fakeParameters) DeleteParameterWithContext(ctx aws.Context, input *ssm.DeleteParameterInput, opts ...request.Option) (*ssm.DeleteParameterOutput, error) {
+	if _, ok := f.parameters[aws.StringValue(input.Name)]; !ok {
+		return nil, awserr.New(ssm.ErrCodeParameterNotFound, "not found", nil)
+	}
+	delete(f.parameters, aws.StringValue(input.Name))
+	return &ssm.DeleteParameterOutput{}, nil
+}
+
+func TestStackRegionsFromContextRegion(t *testing.T) {
+	parameters := &fakeParameters{parameters: map[string]string{}}
+	b := &ecsAPIService{SDK: sdk{SSM: parameters}}
+
+	// deployed with compose up --regions eu-west-1,eu-central-1 from a us-east-1 context, which has no stack
+	assert.NilError(t, b.SDK.PutProjectRegions(context.TODO(), "shop", []string{"eu-west-1", "eu-central-1"}))
+	regions, err := b.stackRegions(context.TODO(), "shop")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, regions, []string{"eu-west-1", "eu-central-1"})
+
+	assert.NilError(t, b.SDK.DeleteProjectRegions(context.TODO(), "shop"))
+	assert.NilError(t, b.SDK.DeleteProjectRegions(context.TODO(), "shop"))
+	regions, err = b.SDK.GetProjectRegions(context.TODO(), "shop")
+	assert.NilError(t, err)
+	assert.Equal(t, len(regions), 0)
+}
+
+func TestMergeRegions(t *testing.T) {
+	regions, dropped := mergeRegions([]string{"eu-central-1", "us-east-1"}, []string{"eu-west-1", "eu-central-1"})
+	assert.DeepEqual(t, regions, []string{"eu-central-1", "us-east-1", "eu-west-1"})
+	assert.DeepEqual(t, dropped, []string{"eu-west-1"})
+
+	regions, dropped = mergeRegions([]string{"eu-west-1"}, nil)
+	assert.DeepEqual(t, regions, []string{"eu-west-1"})
+	assert.Equal(t, len(dropped), 0)
+}
+
+// failingParameters is a Parameter Store denying access to parameters
+type failingParameters struct {
+	ssmiface.SSMAPI
+}
+
+func (f failingParameters) GetParameterWithContext(ctx aws.Context, input *ssm.GetParameterInput, opts ...request.Option) (*ssm.GetParameterOutput, error) {
+	return nil, awserr.New("AccessDeniedException", "not authorized to perform: ssm:GetParameter", nil)
+}
+
+func TestStackRegionsErrorsAreReturned(t *testing.T) {
+	b := &ecsAPIService{SDK: sdk{SSM: failingParameters{}}}
+	err := b.Down(context.TODO(), "shop", compose.DownOptions{})
+	assert.ErrorContains(t, err, "ssm:GetParameter")
+	_, err = b.Ps(context.TODO(), "shop")
+	assert.ErrorContains(t, err, "ssm:GetParameter")
+}
@@ -0,0 +1,66 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/config"
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/recorder"
+)
+
+func TestReplayRecordedCalls(t *testing.T) {
+	os.Setenv("AWS_ACCESS_KEY_ID", "AKIAEXAMPLE") // nolint:errcheck
+	os.Setenv("AWS_SECRET_ACCESS_KEY", "secret")  // nolint:errcheck
+	defer os.Unsetenv("AWS_ACCESS_KEY_ID")        // nolint:errcheck
+	defer os.Unsetenv("AWS_SECRET_ACCESS_KEY")    // nolint:errcheck
+	// a custom CA bundle requires the default transport
+	if bundle, ok := os.LookupEnv("AWS_CA_BUNDLE"); ok {
+		os.Unsetenv("AWS_CA_BUNDLE")             // nolint:errcheck
+		defer os.Setenv("AWS_CA_BUNDLE", bundle) // nolint:errcheck
+	}
+	replayer, err := recorder.NewReplayer("testdata/replay/list")
+	assert.NilError(t, err)
+	service, err := newEcsAPIService(store.EcsContext{Region: "eu-west-1"}, replayer)
+	assert.NilError(t, err)
+
+	stacks, err := service.List(context.Background(), "demo")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, stacks, []compose.Stack{{
+		ID:     "arn:aws:cloudformation:eu-west-1:123456789012:stack/demo/1",
+		Name:   "demo",
+		Status: compose.UPDATING,
+	}})
+	assert.Equal(t, len(replayer.Unused()), 0)
+}
+
+func TestUpFailsFastOffline(t *testing.T) {
+	defer config.SetOffline(false) // nolint:errcheck
+	assert.NilError(t, config.SetOffline(true))
+	// no call is recorded, the backend must not attempt any
+	replayer, err := recorder.NewReplayer(t.TempDir())
+	assert.NilError(t, err)
+	_, err = newEcsAPIService(store.EcsContext{Region: "eu-west-1"}, replayer)
+	assert.Assert(t, errdefs.IsErrOffline(err))
+}
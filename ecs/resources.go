@@ -0,0 +1,85 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+const (
+	// fargateMaxCPU is the largest task size on Fargate, in CPU units
+	fargateMaxCPU = 4096
+	// fargateMaxMemory is the largest task memory on Fargate, in MiB
+	fargateMaxMemory = 30720
+)
+
+// validateResources checks Fargate can allocate the resources of all services before anything gets deployed,
+// suggesting the nearest valid configuration for services it can't run
+func validateResources(project *types.Project) error {
+	var errs *multierror.Error
+	for _, service := range project.Services {
+		if requireEC2(service) {
+			continue
+		}
+		mem, cpu, err := getConfiguredLimits(service)
+		if err != nil {
+			errs = multierror.Append(errs, errors.Wrapf(err, "service %q", service.Name))
+			continue
+		}
+		cpuLimit, memLimit, ok := fargateLimits(cpu, mem)
+		if !ok {
+			if cpu > fargateMaxCPU {
+				cpu = fargateMaxCPU
+			}
+			if mem > fargateMaxMemory*miB {
+				mem = fargateMaxMemory * miB
+			}
+			cpuLimit, memLimit, _ = fargateLimits(cpu, mem)
+			errs = multierror.Append(errs, fmt.Errorf("service %q requests %s, exceeding Fargate limits: the nearest valid configuration is cpus: %s, memory: %dM",
+				service.Name, describeLimits(service), formatCPUUnits(cpuLimit), memLimit))
+			continue
+		}
+		if _, reservation := toContainerReservation(service); int64(reservation) > memLimit {
+			errs = multierror.Append(errs, fmt.Errorf("service %q reserves %dM of memory, more than the %dM allocated to its task: "+
+				"set deploy.resources.limits.memory to at least %dM", service.Name, reservation, memLimit, reservation))
+		}
+	}
+	return compose.ValidationErrors(errs)
+}
+
+func describeLimits(service types.ServiceConfig) string {
+	limits := service.Deploy.Resources.Limits
+	switch {
+	case limits.NanoCPUs == "":
+		return fmt.Sprintf("%dM of memory", limits.MemoryBytes/miB)
+	case limits.MemoryBytes == 0:
+		return fmt.Sprintf("%s CPUs", limits.NanoCPUs)
+	default:
+		return fmt.Sprintf("%s CPUs and %dM of memory", limits.NanoCPUs, limits.MemoryBytes/miB)
+	}
+}
+
+func formatCPUUnits(units int64) string {
+	return strconv.FormatFloat(float64(units)/1024, 'f', -1, 64)
+}
@@ -0,0 +1,63 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestValidateResources(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  small:
+    image: nginx
+  rounded:
+    image: nginx
+    deploy:
+      resources:
+        limits:
+          cpus: '0.3'
+          memory: 3G
+`)
+	assert.NilError(t, validateResources(project))
+}
+
+func TestValidateResourcesSuggestsNearestConfiguration(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  large:
+    image: nginx
+    deploy:
+      resources:
+        limits:
+          cpus: '8'
+          memory: 16G
+  reserved:
+    image: nginx
+    deploy:
+      resources:
+        limits:
+          memory: 1G
+        reservations:
+          memory: 2G
+`)
+	err := validateResources(project)
+	assert.ErrorContains(t, err, `service "large" requests 8 CPUs and 16384M of memory, exceeding Fargate limits: the nearest valid configuration is cpus: 4, memory: 16384M`)
+	assert.ErrorContains(t, err, `service "reserved" reserves 2048M of memory, more than the 1024M allocated to its task`)
+}
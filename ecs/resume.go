@@ -0,0 +1,123 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/progress"
+)
+
+type reconcileAction int
+
+const (
+	reconcileCreate reconcileAction = iota
+	reconcileUpdate
+	reconcileWait
+	reconcileContinueRollback
+	reconcileDelete
+)
+
+// reconcileStack brings the project stack back to a state it can be deployed from, after a command was interrupted.
+// It returns whether the stack exists and must be updated, or has to be created.
+func (b *ecsAPIService) reconcileStack(ctx context.Context, name string, resume bool) (bool, error) {
+	w := progress.ContextWriter(ctx)
+	for {
+		status, err := b.SDK.GetStackStatus(ctx, name)
+		if err != nil {
+			return false, err
+		}
+		action, err := stackReconcileAction(name, status, resume)
+		if err != nil {
+			return false, err
+		}
+		switch action {
+		case reconcileCreate:
+			return false, nil
+		case reconcileUpdate:
+			return true, nil
+		case reconcileWait:
+			w.Event(progress.Event{ID: name, Status: progress.Working, StatusText: "Waiting for " + status})
+			if err := b.waitStackStable(ctx, name); err != nil {
+				return false, err
+			}
+		case reconcileContinueRollback:
+			w.Event(progress.Event{ID: name, Status: progress.Working, StatusText: "Continuing rollback"})
+			if err := b.SDK.ContinueUpdateRollback(ctx, name); err != nil {
+				return false, err
+			}
+		case reconcileDelete:
+			w.Event(progress.Event{ID: name, Status: progress.Working, StatusText: "Deleting stack left " + status})
+			if err := b.SDK.DeleteStack(ctx, name); err != nil {
+				return false, err
+			}
+			if err := b.WaitStackCompletion(ctx, name, stackDelete); err != nil {
+				return false, err
+			}
+			return false, nil
+		}
+	}
+}
+
+// stackReconcileAction decides how to handle a stack left by a previous command depending on its status
+func stackReconcileAction(name string, status string, resume bool) (reconcileAction, error) {
+	switch {
+	case status == "":
+		return reconcileCreate, nil
+	case strings.HasSuffix(status, "_IN_PROGRESS"):
+		if !resume {
+			return 0, compose.InterruptedError(fmt.Sprintf("stack %q", name), status)
+		}
+		return reconcileWait, nil
+	case status == cloudformation.StackStatusUpdateRollbackFailed:
+		if !resume {
+			return 0, compose.InterruptedError(fmt.Sprintf("stack %q", name), status)
+		}
+		return reconcileContinueRollback, nil
+	case status == cloudformation.StackStatusRollbackComplete,
+		status == cloudformation.StackStatusRollbackFailed,
+		status == cloudformation.StackStatusCreateFailed,
+		status == cloudformation.StackStatusDeleteFailed:
+		// stack creation never completed, or the stack was being deleted: it can't be updated, only recreated
+		return reconcileDelete, nil
+	default:
+		return reconcileUpdate, nil
+	}
+}
+
+func (b *ecsAPIService) waitStackStable(ctx context.Context, name string) error {
+	for {
+		status, err := b.SDK.GetStackStatus(ctx, name)
+		if err != nil {
+			return err
+		}
+		if !strings.HasSuffix(status, "_IN_PROGRESS") {
+			return nil
+		}
+		select {
+		case <-time.After(b.ctx.Operations().PollingInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,54 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestStackReconcileAction(t *testing.T) {
+	cases := []struct {
+		status string
+		resume bool
+		action reconcileAction
+	}{
+		{status: "", action: reconcileCreate},
+		{status: "CREATE_COMPLETE", action: reconcileUpdate},
+		{status: "UPDATE_ROLLBACK_COMPLETE", action: reconcileUpdate},
+		{status: "ROLLBACK_COMPLETE", action: reconcileDelete},
+		{status: "DELETE_FAILED", action: reconcileDelete},
+		{status: "UPDATE_IN_PROGRESS", resume: true, action: reconcileWait},
+		{status: "UPDATE_ROLLBACK_FAILED", resume: true, action: reconcileContinueRollback},
+	}
+	for _, c := range cases {
+		action, err := stackReconcileAction("test", c.status, c.resume)
+		assert.NilError(t, err, c.status)
+		assert.Equal(t, action, c.action, c.status)
+	}
+}
+
+func TestStackReconcileRequiresResume(t *testing.T) {
+	for _, status := range []string{"CREATE_IN_PROGRESS", "UPDATE_COMPLETE_CLEANUP_IN_PROGRESS", "UPDATE_ROLLBACK_FAILED"} {
+		_, err := stackReconcileAction("test", status, false)
+		assert.Assert(t, errdefs.IsErrLocked(err), status)
+		assert.ErrorContains(t, err, "--resume")
+	}
+}
@@ -0,0 +1,149 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	ecsapi "github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/events"
+	"github.com/awslabs/goformation/v4/cloudformation/iam"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+var eventsAssumeRolePolicyDocument = PolicyDocument{
+	Version: "2012-10-17", // https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_policies_elements_version.html
+	Statement: []PolicyStatement{
+		{
+			Effect: "Allow",
+			Principal: PolicyPrincipal{
+				Service: "events.amazonaws.com",
+			},
+			Action: []string{"sts:AssumeRole"},
+		},
+	},
+}
+
+// createSchedule declares an EventBridge rule running the service task on its schedule, with the role allowing
+// EventBridge to run it
+func (b *ecsAPIService) createSchedule(service types.ServiceConfig, schedule compose.Schedule, template *cloudformation.Template,
+	resources awsResources, taskDefinition string, roles []string) error {
+	expression, err := awsScheduleExpression(schedule)
+	if err != nil {
+		return errors.Wrapf(err, "service %q", service.Name)
+	}
+	var roleArns []string
+	for _, role := range roles {
+		roleArns = append(roleArns, cloudformation.GetAtt(role, "Arn"))
+	}
+	scheduleRole := fmt.Sprintf("%sScheduleRole", normalizeResourceName(service.Name))
+	template.Resources[scheduleRole] = &iam.Role{
+		AssumeRolePolicyDocument: eventsAssumeRolePolicyDocument,
+		Policies: []iam.Role_Policy{
+			{
+				PolicyDocument: &PolicyDocument{
+					Statement: []PolicyStatement{
+						{
+							Effect:   "Allow",
+							Action:   []string{"ecs:RunTask"},
+							Resource: []string{cloudformation.Ref(taskDefinition)},
+						},
+						{
+							Effect:   "Allow",
+							Action:   []string{"iam:PassRole"},
+							Resource: roleArns,
+						},
+					},
+				},
+				PolicyName: fmt.Sprintf("%sRunScheduledTask", normalizeResourceName(service.Name)),
+			},
+		},
+	}
+
+	launchType := ecsapi.LaunchTypeFargate
+	assignPublicIP := ecsapi.AssignPublicIpEnabled
+	platformVersion := "1.4.0"
+	if requireEC2(service) {
+		launchType = ecsapi.LaunchTypeEc2
+		assignPublicIP = ecsapi.AssignPublicIpDisabled
+		platformVersion = ""
+	}
+	template.Resources[fmt.Sprintf("%sSchedule", normalizeResourceName(service.Name))] = &events.Rule{
+		Description:        fmt.Sprintf("Run %s on %s", service.Name, schedule),
+		ScheduleExpression: expression,
+		State:              "ENABLED",
+		Targets: []events.Rule_Target{
+			{
+				Arn: clusterArn(resources.cluster),
+				EcsParameters: &events.Rule_EcsParameters{
+					LaunchType: launchType,
+					NetworkConfiguration: &events.Rule_NetworkConfiguration{
+						AwsVpcConfiguration: &events.Rule_AwsVpcConfiguration{
+							AssignPublicIp: assignPublicIP,
+							SecurityGroups: resources.serviceSecurityGroups(service),
+							Subnets:        resources.serviceSubnets(service),
+						},
+					},
+					PlatformVersion:   platformVersion,
+					TaskCount:         1,
+					TaskDefinitionArn: cloudformation.Ref(taskDefinition),
+				},
+				Id:      normalizeResourceName(service.Name),
+				RoleArn: cloudformation.GetAtt(scheduleRole, "Arn"),
+			},
+		},
+	}
+	return nil
+}
+
+// clusterArn returns the ARN EventBridge targets for the cluster, created by the stack or set by x-aws-cluster
+func clusterArn(cluster string) string {
+	if cluster == cloudformation.Ref("Cluster") {
+		return cloudformation.GetAtt("Cluster", "Arn")
+	}
+	if strings.HasPrefix(cluster, "arn:") {
+		return cluster
+	}
+	return cloudformation.Sub(fmt.Sprintf("arn:${AWS::Partition}:ecs:${AWS::Region}:${AWS::AccountId}:cluster/%s", cluster))
+}
+
+// awsScheduleExpression converts a cron schedule to the EventBridge syntax, which has a year field, requires `?` for
+// either the day of month or the day of week, and numbers days of week from 1 (Sunday) to 7
+func awsScheduleExpression(schedule compose.Schedule) (string, error) {
+	fields := schedule.Fields
+	switch {
+	case fields[4] == "*":
+		fields[4] = "?"
+	case fields[2] == "*":
+		fields[2] = "?"
+		var days []string
+		for _, d := range schedule.Values(4) {
+			days = append(days, strconv.Itoa(d+1))
+		}
+		fields[4] = strings.Join(days, ",")
+	default:
+		return "", errors.Wrapf(errdefs.ErrNotImplemented, "schedule %s: EventBridge doesn't support restricting both days of month and days of week", schedule)
+	}
+	return fmt.Sprintf("cron(%s *)", strings.Join(fields[:], " ")), nil
+}
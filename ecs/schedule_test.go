@@ -0,0 +1,62 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation/events"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestAWSScheduleExpression(t *testing.T) {
+	for expression, expected := range map[string]string{
+		"cron(0 3 * * *)":   "cron(0 3 * * ? *)",
+		"cron(0 3 * * 1-5)": "cron(0 3 ? * 2,3,4,5,6 *)",
+		"cron(0 3 1 * *)":   "cron(0 3 1 * ? *)",
+	} {
+		schedule, err := compose.ParseSchedule(expression)
+		assert.NilError(t, err)
+		actual, err := awsScheduleExpression(schedule)
+		assert.NilError(t, err)
+		assert.Equal(t, actual, expected)
+	}
+	schedule, err := compose.ParseSchedule("cron(0 3 1 * 1)")
+	assert.NilError(t, err)
+	_, err = awsScheduleExpression(schedule)
+	assert.Assert(t, errdefs.IsErrNotImplemented(err))
+}
+
+func TestScheduleConvert(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  backup:
+    image: backup
+    x-schedule: "cron(0 3 * * *)"
+`)
+	_, ok := template.Resources["BackupService"]
+	assert.Assert(t, !ok)
+	rule := template.Resources["BackupSchedule"].(*events.Rule)
+	assert.Equal(t, rule.ScheduleExpression, "cron(0 3 * * ? *)")
+	assert.Equal(t, len(rule.Targets), 1)
+	assert.Equal(t, rule.Targets[0].EcsParameters.TaskCount, 1)
+	_, ok = template.Resources["BackupScheduleRole"]
+	assert.Assert(t, ok)
+}
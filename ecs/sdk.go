@@ -18,8 +18,11 @@ package ecs
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -28,16 +31,31 @@ import (
 
 	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/api/secrets"
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/tracing"
+	"github.com/docker/compose-cli/utils"
+
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/cloudformation/cloudformationiface"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
 	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecr/ecriface"
 	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/aws/aws-sdk-go/service/ecs/ecsiface"
 	"github.com/aws/aws-sdk-go/service/efs"
@@ -46,14 +64,24 @@ import (
 	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/pricing"
+	"github.com/aws/aws-sdk-go/service/pricing/pricingiface"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi/resourcegroupstaggingapiiface"
 	"github.com/aws/aws-sdk-go/service/secretsmanager"
 	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/aws/aws-sdk-go/service/servicediscovery"
+	"github.com/aws/aws-sdk-go/service/servicediscovery/servicediscoveryiface"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/aws/aws-sdk-go/service/servicequotas/servicequotasiface"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
 type sdk struct {
 	ECS ecsiface.ECSAPI
 	EC2 ec2iface.EC2API
+	ECR ecriface.ECRAPI
 	EFS efsiface.EFSAPI
 	ELB elbv2iface.ELBV2API
 	CW  cloudwatchlogsiface.CloudWatchLogsAPI
@@ -61,15 +89,34 @@ type sdk struct {
 	CF  cloudformationiface.CloudFormationAPI
 	SM  secretsmanageriface.SecretsManagerAPI
 	SSM ssmiface.SSMAPI
+	RGT resourcegroupstaggingapiiface.ResourceGroupsTaggingAPIAPI
+	PRC pricingiface.PricingAPI
+	SD  servicediscoveryiface.ServiceDiscoveryAPI
+	DDB dynamodbiface.DynamoDBAPI
+	CWM cloudwatchiface.CloudWatchAPI
+	SQ  servicequotasiface.ServiceQuotasAPI
+
+	pollingInterval time.Duration
 }
 
-func newSDK(sess *session.Session) sdk {
+func newSDK(sess *session.Session, ops store.Operations) sdk {
 	sess.Handlers.Build.PushBack(func(r *request.Request) {
 		request.AddToUserAgent(r, "Docker CLI")
 	})
+	// trace AWS API calls, including retries, as children of the operation span
+	sess.Handlers.Validate.PushFront(func(r *request.Request) {
+		ctx, _ := tracing.Start(r.Context(), fmt.Sprintf("%s.%s", r.ClientInfo.ServiceName, r.Operation.Name),
+			label.String("aws.service", r.ClientInfo.ServiceName),
+			label.String("aws.region", aws.StringValue(r.Config.Region)))
+		r.SetContext(ctx)
+	})
+	sess.Handlers.Complete.PushBack(func(r *request.Request) {
+		tracing.End(trace.SpanFromContext(r.Context()), r.Error)
+	})
 	return sdk{
 		ECS: ecs.New(sess),
 		EC2: ec2.New(sess),
+		ECR: ecr.New(sess),
 		EFS: efs.New(sess),
 		ELB: elbv2.New(sess),
 		CW:  cloudwatchlogs.New(sess),
@@ -77,6 +124,15 @@ func newSDK(sess *session.Session) sdk {
 		CF:  cloudformation.New(sess),
 		SM:  secretsmanager.New(sess),
 		SSM: ssm.New(sess),
+		RGT: resourcegroupstaggingapi.New(sess),
+		// Pricing API is only available in a few regions, but covers them all
+		PRC: pricing.New(sess, aws.NewConfig().WithRegion("us-east-1")),
+		SD:  servicediscovery.New(sess),
+		DDB: dynamodb.New(sess),
+		CWM: cloudwatch.New(sess),
+		SQ:  servicequotas.New(sess),
+
+		pollingInterval: ops.PollingInterval,
 	}
 }
 
@@ -173,6 +229,24 @@ func (s sdk) GetSubNets(ctx context.Context, vpcID string) ([]string, error) {
 	return ids, nil
 }
 
+// GetSubnetZones returns the availability zone of each subnet
+func (s sdk) GetSubnetZones(ctx context.Context, subnets []string) (map[string]string, error) {
+	zones := map[string]string{}
+	if len(subnets) == 0 {
+		return zones, nil
+	}
+	output, err := s.EC2.DescribeSubnetsWithContext(ctx, &ec2.DescribeSubnetsInput{
+		SubnetIds: aws.StringSlice(subnets),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, subnet := range output.Subnets {
+		zones[aws.StringValue(subnet.SubnetId)] = aws.StringValue(subnet.AvailabilityZone)
+	}
+	return zones, nil
+}
+
 func (s sdk) GetRoleArn(ctx context.Context, name string) (string, error) {
 	role, err := s.IAM.GetRoleWithContext(ctx, &iam.GetRoleInput{
 		RoleName: aws.String(name),
@@ -183,20 +257,104 @@ func (s sdk) GetRoleArn(ctx context.Context, name string) (string, error) {
 	return *role.Role.Arn, nil
 }
 
-func (s sdk) StackExists(ctx context.Context, name string) (bool, error) {
-	stacks, err := s.CF.DescribeStacksWithContext(ctx, &cloudformation.DescribeStacksInput{
+// GetTemplate returns the template the stack was last deployed with
+func (s sdk) GetTemplate(ctx context.Context, name string) ([]byte, error) {
+	template, err := s.CF.GetTemplateWithContext(ctx, &cloudformation.GetTemplateInput{
+		StackName:     aws.String(name),
+		TemplateStage: aws.String(cloudformation.TemplateStageOriginal),
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			return nil, errors.Wrapf(errdefs.ErrNotFound, "stack %q", name)
+		}
+		return nil, err
+	}
+	return []byte(aws.StringValue(template.TemplateBody)), nil
+}
+
+// DetectStackDrift runs CloudFormation drift detection on the stack and returns the resources modified or deleted
+// outside of CloudFormation
+func (s sdk) DetectStackDrift(ctx context.Context, name string) ([]compose.ResourceDrift, error) {
+	detection, err := s.CF.DetectStackDriftWithContext(ctx, &cloudformation.DetectStackDriftInput{
 		StackName: aws.String(name),
 	})
 	if err != nil {
-		if strings.HasPrefix(err.Error(), fmt.Sprintf("ValidationError: Stack with ID %s does not exist", name)) {
-			return false, nil
+		return nil, err
+	}
+	for {
+		status, err := s.CF.DescribeStackDriftDetectionStatusWithContext(ctx, &cloudformation.DescribeStackDriftDetectionStatusInput{
+			StackDriftDetectionId: detection.StackDriftDetectionId,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if aws.StringValue(status.DetectionStatus) == cloudformation.StackDriftDetectionStatusDetectionFailed {
+			// resources which don't support drift detection make detection fail, others are still reported
+			logrus.Warnf("drift detection partially failed: %s", aws.StringValue(status.DetectionStatusReason))
+		}
+		if aws.StringValue(status.DetectionStatus) != cloudformation.StackDriftDetectionStatusDetectionInProgress {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(s.pollingInterval):
+		}
+	}
+
+	var (
+		drifts []compose.ResourceDrift
+		token  *string
+	)
+	for {
+		response, err := s.CF.DescribeStackResourceDriftsWithContext(ctx, &cloudformation.DescribeStackResourceDriftsInput{
+			StackName: aws.String(name),
+			StackResourceDriftStatusFilters: aws.StringSlice([]string{
+				cloudformation.StackResourceDriftStatusModified,
+				cloudformation.StackResourceDriftStatusDeleted,
+			}),
+			NextToken: token,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, drift := range response.StackResourceDrifts {
+			drifts = append(drifts, compose.ResourceDrift{
+				Resource: aws.StringValue(drift.LogicalResourceId),
+				Type:     aws.StringValue(drift.ResourceType),
+				Status:   aws.StringValue(drift.StackResourceDriftStatus),
+			})
+		}
+		if response.NextToken == nil {
+			return drifts, nil
 		}
-		return false, nil
+		token = response.NextToken
+	}
+}
+
+// stackTags returns the tags of a project stack, which CloudFormation propagates to the stack resources
+func stackTags(name string, tags map[string]string) []*cloudformation.Tag {
+	stackTags := []*cloudformation.Tag{
+		{
+			Key:   aws.String(compose.ProjectTag),
+			Value: aws.String(name),
+		},
 	}
-	return len(stacks.Stacks) > 0, nil
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		stackTags = append(stackTags, &cloudformation.Tag{
+			Key:   aws.String(k),
+			Value: aws.String(tags[k]),
+		})
+	}
+	return stackTags
 }
 
-func (s sdk) CreateStack(ctx context.Context, name string, template []byte) error {
+func (s sdk) CreateStack(ctx context.Context, name string, template []byte, tags map[string]string) error {
 	logrus.Debug("Create CloudFormation stack")
 
 	_, err := s.CF.CreateStackWithContext(ctx, &cloudformation.CreateStackInput{
@@ -207,17 +365,12 @@ func (s sdk) CreateStack(ctx context.Context, name string, template []byte) erro
 		Capabilities: []*string{
 			aws.String(cloudformation.CapabilityCapabilityIam),
 		},
-		Tags: []*cloudformation.Tag{
-			{
-				Key:   aws.String(compose.ProjectTag),
-				Value: aws.String(name),
-			},
-		},
+		Tags: stackTags(name, tags),
 	})
 	return err
 }
 
-func (s sdk) CreateChangeSet(ctx context.Context, name string, template []byte) (string, error) {
+func (s sdk) CreateChangeSet(ctx context.Context, name string, template []byte, tags map[string]string) (string, error) {
 	logrus.Debug("Create CloudFormation Changeset")
 
 	update := fmt.Sprintf("Update%s", time.Now().Format("2006-01-02-15-04-05"))
@@ -229,6 +382,7 @@ func (s sdk) CreateChangeSet(ctx context.Context, name string, template []byte)
 		Capabilities: []*string{
 			aws.String(cloudformation.CapabilityCapabilityIam),
 		},
+		Tags: stackTags(name, tags),
 	})
 	if err != nil {
 		return "", err
@@ -271,6 +425,8 @@ func (s sdk) WaitStackComplete(ctx context.Context, name string, operation int)
 	switch operation {
 	case stackCreate:
 		return s.CF.WaitUntilStackCreateCompleteWithContext(ctx, input)
+	case stackUpdate:
+		return s.CF.WaitUntilStackUpdateCompleteWithContext(ctx, input)
 	case stackDelete:
 		return s.CF.WaitUntilStackDeleteCompleteWithContext(ctx, input)
 	default:
@@ -278,6 +434,97 @@ func (s sdk) WaitStackComplete(ctx context.Context, name string, operation int)
 	}
 }
 
+// GetStackStatus returns the status of the stack, or an empty string if it doesn't exist
+func (s sdk) GetStackStatus(ctx context.Context, name string) (string, error) {
+	stacks, err := s.CF.DescribeStacksWithContext(ctx, &cloudformation.DescribeStacksInput{
+		StackName: aws.String(name),
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			return "", nil
+		}
+		return "", err
+	}
+	if len(stacks.Stacks) == 0 {
+		return "", nil
+	}
+	return aws.StringValue(stacks.Stacks[0].StackStatus), nil
+}
+
+// GetStackTags returns the tags of the stack, by key
+func (s sdk) GetStackTags(ctx context.Context, name string) (map[string]string, error) {
+	stacks, err := s.CF.DescribeStacksWithContext(ctx, &cloudformation.DescribeStacksInput{
+		StackName: aws.String(name),
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			return nil, errors.Wrapf(errdefs.ErrNotFound, "stack %q", name)
+		}
+		return nil, err
+	}
+	tags := map[string]string{}
+	for _, stack := range stacks.Stacks {
+		for _, tag := range stack.Tags {
+			tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+	}
+	return tags, nil
+}
+
+// GetStackOutputs returns the outputs of the stack, by key
+func (s sdk) GetStackOutputs(ctx context.Context, name string) (map[string]string, error) {
+	stacks, err := s.CF.DescribeStacksWithContext(ctx, &cloudformation.DescribeStacksInput{
+		StackName: aws.String(name),
+	})
+	if err != nil {
+		return nil, err
+	}
+	outputs := map[string]string{}
+	for _, stack := range stacks.Stacks {
+		for _, output := range stack.Outputs {
+			outputs[aws.StringValue(output.OutputKey)] = aws.StringValue(output.OutputValue)
+		}
+	}
+	return outputs, nil
+}
+
+// ListStackOutputs returns the outputs of the stack with their description
+func (s sdk) ListStackOutputs(ctx context.Context, name string) ([]compose.StackOutput, error) {
+	stacks, err := s.CF.DescribeStacksWithContext(ctx, &cloudformation.DescribeStacksInput{
+		StackName: aws.String(name),
+	})
+	if err != nil {
+		return nil, err
+	}
+	outputs := []compose.StackOutput{}
+	for _, stack := range stacks.Stacks {
+		for _, output := range stack.Outputs {
+			outputs = append(outputs, compose.StackOutput{
+				Name:        aws.StringValue(output.OutputKey),
+				Value:       aws.StringValue(output.OutputValue),
+				Description: aws.StringValue(output.Description),
+			})
+		}
+	}
+	return outputs, nil
+}
+
+// CancelUpdateStack stops an update in progress, and rolls the stack back to its previous configuration
+func (s sdk) CancelUpdateStack(ctx context.Context, name string) error {
+	_, err := s.CF.CancelUpdateStackWithContext(ctx, &cloudformation.CancelUpdateStackInput{
+		StackName: aws.String(name),
+	})
+	return err
+}
+
+// ContinueUpdateRollback resumes the rollback of a stack which failed to roll back an update
+func (s sdk) ContinueUpdateRollback(ctx context.Context, name string) error {
+	_, err := s.CF.ContinueUpdateRollbackWithContext(ctx, &cloudformation.ContinueUpdateRollbackInput{
+		StackName: aws.String(name),
+	})
+	return err
+}
+
 func (s sdk) GetStackID(ctx context.Context, name string) (string, error) {
 	stacks, err := s.CF.DescribeStacksWithContext(ctx, &cloudformation.DescribeStacksInput{
 		StackName: aws.String(name),
@@ -293,35 +540,58 @@ func (s sdk) ListStacks(ctx context.Context, name string) ([]compose.Stack, erro
 	if name != "" {
 		params.StackName = &name
 	}
-	cfStacks, err := s.CF.DescribeStacksWithContext(ctx, &params)
-	if err != nil {
-		return nil, err
-	}
 	stacks := []compose.Stack{}
-	for _, stack := range cfStacks.Stacks {
-		for _, t := range stack.Tags {
-			if *t.Key == compose.ProjectTag {
-				status := compose.RUNNING
-				switch aws.StringValue(stack.StackStatus) {
-				case "CREATE_IN_PROGRESS":
-					status = compose.STARTING
-				case "DELETE_IN_PROGRESS":
-					status = compose.REMOVING
-				case "UPDATE_IN_PROGRESS":
-					status = compose.UPDATING
+	err := s.CF.DescribeStacksPagesWithContext(ctx, &params, func(page *cloudformation.DescribeStacksOutput, lastPage bool) bool {
+		for _, stack := range page.Stacks {
+			for _, t := range stack.Tags {
+				if *t.Key == compose.ProjectTag {
+					status := compose.RUNNING
+					switch aws.StringValue(stack.StackStatus) {
+					case "CREATE_IN_PROGRESS":
+						status = compose.STARTING
+					case "DELETE_IN_PROGRESS":
+						status = compose.REMOVING
+					case "UPDATE_IN_PROGRESS":
+						status = compose.UPDATING
+					}
+					stacks = append(stacks, compose.Stack{
+						ID:     aws.StringValue(stack.StackId),
+						Name:   aws.StringValue(stack.StackName),
+						Status: status,
+					})
+					break
 				}
-				stacks = append(stacks, compose.Stack{
-					ID:     aws.StringValue(stack.StackId),
-					Name:   aws.StringValue(stack.StackName),
-					Status: status,
-				})
-				break
 			}
 		}
+		return true
+	})
+	if err != nil {
+		return nil, err
 	}
 	return stacks, nil
 }
 
+// ListDeployedProjects returns the projects which still have a stack, in any state but DELETE_COMPLETE: the stack
+// names, and the compose project tags of the stacks, including failed and in progress ones
+func (s sdk) ListDeployedProjects(ctx context.Context) ([]string, error) {
+	var projects []string
+	err := s.CF.DescribeStacksPagesWithContext(ctx, &cloudformation.DescribeStacksInput{}, func(page *cloudformation.DescribeStacksOutput, lastPage bool) bool {
+		for _, stack := range page.Stacks {
+			if aws.StringValue(stack.StackStatus) == cloudformation.StackStatusDeleteComplete {
+				continue
+			}
+			projects = append(projects, aws.StringValue(stack.StackName))
+			for _, t := range stack.Tags {
+				if aws.StringValue(t.Key) == compose.ProjectTag {
+					projects = append(projects, aws.StringValue(t.Value))
+				}
+			}
+		}
+		return true
+	})
+	return projects, err
+}
+
 func (s sdk) DescribeStackEvents(ctx context.Context, stackID string) ([]*cloudformation.StackEvent, error) {
 	// Fixme implement Paginator on Events and return as a chan(events)
 	events := []*cloudformation.StackEvent{}
@@ -467,9 +737,145 @@ func (s sdk) DeleteSecret(ctx context.Context, id string, recover bool) error {
 	return err
 }
 
-func (s sdk) GetLogs(ctx context.Context, name string, consumer func(service, container, message string)) error {
-	logGroup := fmt.Sprintf("/docker-compose/%s", name)
-	var startTime int64
+func (s sdk) PutRegistryCredentials(ctx context.Context, project string, secret secrets.Secret) (string, error) {
+	logrus.Debug("Put registry credentials " + secret.Name)
+	secretStr, err := secret.GetCredString()
+	if err != nil {
+		return "", err
+	}
+
+	_, err = s.SM.DescribeSecretWithContext(ctx, &secretsmanager.DescribeSecretInput{SecretId: &secret.Name})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != secretsmanager.ErrCodeResourceNotFoundException {
+			return "", err
+		}
+		response, err := s.SM.CreateSecretWithContext(ctx, &secretsmanager.CreateSecretInput{
+			Name:         &secret.Name,
+			SecretString: &secretStr,
+			Description:  &secret.Description,
+			Tags: []*secretsmanager.Tag{
+				{
+					Key:   aws.String(compose.ProjectTag),
+					Value: aws.String(project),
+				},
+			},
+		})
+		if err != nil {
+			return "", err
+		}
+		return aws.StringValue(response.ARN), nil
+	}
+
+	response, err := s.SM.PutSecretValueWithContext(ctx, &secretsmanager.PutSecretValueInput{
+		SecretId:     &secret.Name,
+		SecretString: &secretStr,
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(response.ARN), nil
+}
+
+func (s sdk) DeleteRegistryCredentials(ctx context.Context, project string) error {
+	logrus.Debug("Delete registry credentials for project " + project)
+	return s.SM.ListSecretsPagesWithContext(ctx, &secretsmanager.ListSecretsInput{}, func(page *secretsmanager.ListSecretsOutput, lastPage bool) bool {
+		for _, sec := range page.SecretList {
+			if !strings.HasPrefix(aws.StringValue(sec.Name), registryCredentialsPrefix(project)) {
+				continue
+			}
+			_, err := s.SM.DeleteSecretWithContext(ctx, &secretsmanager.DeleteSecretInput{
+				SecretId:                   sec.ARN,
+				ForceDeleteWithoutRecovery: aws.Bool(true),
+			})
+			if err != nil {
+				logrus.Warnf("failed to delete registry credentials %s: %v", aws.StringValue(sec.Name), err)
+			}
+		}
+		return true
+	})
+}
+
+func (s sdk) CreateRepository(ctx context.Context, name string, project string) (string, error) {
+	logrus.Debug("Check ECR repository " + name)
+	repositories, err := s.ECR.DescribeRepositoriesWithContext(ctx, &ecr.DescribeRepositoriesInput{
+		RepositoryNames: []*string{aws.String(name)},
+	})
+	if err == nil && len(repositories.Repositories) > 0 {
+		return aws.StringValue(repositories.Repositories[0].RepositoryUri), nil
+	}
+	if aerr, ok := err.(awserr.Error); err != nil && (!ok || aerr.Code() != ecr.ErrCodeRepositoryNotFoundException) {
+		return "", err
+	}
+
+	logrus.Debug("Create ECR repository " + name)
+	response, err := s.ECR.CreateRepositoryWithContext(ctx, &ecr.CreateRepositoryInput{
+		RepositoryName: aws.String(name),
+		Tags: []*ecr.Tag{
+			{
+				Key:   aws.String(compose.ProjectTag),
+				Value: aws.String(project),
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(response.Repository.RepositoryUri), nil
+}
+
+// GetImageDigest returns the digest of the image a tag of an ECR repository points to
+func (s sdk) GetImageDigest(ctx context.Context, repository string, tag string) (string, error) {
+	response, err := s.ECR.DescribeImagesWithContext(ctx, &ecr.DescribeImagesInput{
+		RepositoryName: aws.String(repository),
+		ImageIds:       []*ecr.ImageIdentifier{{ImageTag: aws.String(tag)}},
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(response.ImageDetails) == 0 || response.ImageDetails[0].ImageDigest == nil {
+		return "", errors.Wrapf(errdefs.ErrNotFound, "image %s:%s", repository, tag)
+	}
+	return aws.StringValue(response.ImageDetails[0].ImageDigest), nil
+}
+
+func (s sdk) GetECRAuthorization(ctx context.Context) (string, string, string, error) {
+	response, err := s.ECR.GetAuthorizationTokenWithContext(ctx, &ecr.GetAuthorizationTokenInput{})
+	if err != nil {
+		return "", "", "", err
+	}
+	if len(response.AuthorizationData) == 0 {
+		return "", "", "", fmt.Errorf("no ECR authorization data returned")
+	}
+	data := response.AuthorizationData[0]
+	token, err := base64.StdEncoding.DecodeString(aws.StringValue(data.AuthorizationToken))
+	if err != nil {
+		return "", "", "", err
+	}
+	parts := strings.SplitN(string(token), ":", 2)
+	if len(parts) != 2 {
+		return "", "", "", fmt.Errorf("invalid ECR authorization token")
+	}
+	server := strings.TrimPrefix(aws.StringValue(data.ProxyEndpoint), "https://")
+	return server, parts[0], parts[1], nil
+}
+
+// logsLookback is how far back in time logs are polled again, as events can be ingested well after their timestamp
+const logsLookback = 30 * time.Second
+
+// GetLogs reads the application log group, skipping the log streams of the excluded tasks
+func (s sdk) GetLogs(ctx context.Context, name string, consumer compose.LogConsumer, options compose.LogOptions, excludedTasks []string) error {
+	logGroup := logGroupPrefix + name
+	var startTime, newest int64
+	var endTime *int64
+	if !options.Since.IsZero() {
+		startTime = options.Since.UnixNano() / int64(time.Millisecond)
+	}
+	if !options.Until.IsZero() {
+		endTime = aws.Int64(options.Until.UnixNano() / int64(time.Millisecond))
+	}
+	since := startTime
+	// seen holds the timestamp of the events already consumed within the lookback window
+	seen := map[string]int64{}
 	for {
 		select {
 		case <-ctx.Done():
@@ -477,11 +883,13 @@ func (s sdk) GetLogs(ctx context.Context, name string, consumer func(service, co
 		default:
 			var hasMore = true
 			var token *string
+			var batch []*cloudwatchlogs.FilteredLogEvent
 			for hasMore {
 				events, err := s.CW.FilterLogEvents(&cloudwatchlogs.FilterLogEventsInput{
 					LogGroupName: aws.String(logGroup),
 					NextToken:    token,
 					StartTime:    aws.Int64(startTime),
+					EndTime:      endTime,
 				})
 				if err != nil {
 					return err
@@ -491,15 +899,95 @@ func (s sdk) GetLogs(ctx context.Context, name string, consumer func(service, co
 				} else {
 					token = events.NextToken
 				}
+				batch = append(batch, events.Events...)
+			}
 
-				for _, event := range events.Events {
-					p := strings.Split(aws.StringValue(event.LogStreamName), "/")
-					consumer(p[1], p[2], aws.StringValue(event.Message))
-					startTime = *event.IngestionTime
+			// events are only sorted by timestamp within each log stream
+			sort.SliceStable(batch, func(i, j int) bool {
+				return aws.Int64Value(batch[i].Timestamp) < aws.Int64Value(batch[j].Timestamp)
+			})
+			for _, event := range batch {
+				id := aws.StringValue(event.EventId)
+				if _, ok := seen[id]; ok {
+					continue
+				}
+				timestamp := aws.Int64Value(event.Timestamp)
+				seen[id] = timestamp
+				if timestamp > newest {
+					newest = timestamp
+				}
+				p := strings.Split(aws.StringValue(event.LogStreamName), "/")
+				if utils.StringContains(excludedTasks, p[2]) {
+					continue
+				}
+				consumer.Log(compose.LogEvent{
+					Service:   p[1],
+					Container: p[2],
+					Timestamp: time.Unix(0, timestamp*int64(time.Millisecond)).UTC(),
+					Line:      aws.StringValue(event.Message),
+				})
+			}
+			if !options.Follow() {
+				return nil
+			}
+			startTime = newest - logsLookback.Milliseconds()
+			if startTime < since {
+				startTime = since
+			}
+			for id, timestamp := range seen {
+				if timestamp < startTime {
+					delete(seen, id)
 				}
 			}
 		}
-		time.Sleep(500 * time.Millisecond)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(s.pollingInterval):
+		}
+	}
+}
+
+// ExportLogs starts a task exporting the application log group to an S3 bucket, and returns its ID
+func (s sdk) ExportLogs(ctx context.Context, name string, bucket string, prefix string) (string, error) {
+	task, err := s.CW.CreateExportTaskWithContext(ctx, &cloudwatchlogs.CreateExportTaskInput{
+		LogGroupName:      aws.String(logGroupPrefix + name),
+		Destination:       aws.String(bucket),
+		DestinationPrefix: aws.String(prefix),
+		From:              aws.Int64(0),
+		To:                aws.Int64(time.Now().UnixNano() / int64(time.Millisecond)),
+		TaskName:          aws.String(name),
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(task.TaskId), nil
+}
+
+// WaitExportTask waits for a logs export task to complete
+func (s sdk) WaitExportTask(ctx context.Context, id string) error {
+	for {
+		tasks, err := s.CW.DescribeExportTasksWithContext(ctx, &cloudwatchlogs.DescribeExportTasksInput{
+			TaskId: aws.String(id),
+		})
+		if err != nil {
+			return err
+		}
+		if len(tasks.ExportTasks) == 0 {
+			return errors.Wrapf(errdefs.ErrNotFound, "logs export task %q", id)
+		}
+		task := tasks.ExportTasks[0]
+		switch aws.StringValue(task.Status.Code) {
+		case cloudwatchlogs.ExportTaskStatusCodeCompleted:
+			return nil
+		case cloudwatchlogs.ExportTaskStatusCodeCancelled, cloudwatchlogs.ExportTaskStatusCodeFailed:
+			return fmt.Errorf("logs export task %s: %s", id, aws.StringValue(task.Status.Message))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.pollingInterval):
+		}
 	}
 }
 
@@ -534,59 +1022,257 @@ func (s sdk) DescribeServices(ctx context.Context, cluster string, arns []string
 		if err != nil {
 			return nil, err
 		}
+		restarts, exitCode, err := s.countRestartedTasks(ctx, cluster, aws.StringValue(service.ServiceName), name)
+		if err != nil {
+			return nil, err
+		}
 		status = append(status, compose.ServiceStatus{
 			ID:         aws.StringValue(service.ServiceName),
 			Name:       name,
 			Replicas:   int(aws.Int64Value(service.RunningCount)),
 			Desired:    int(aws.Int64Value(service.DesiredCount)),
 			Publishers: loadBalancers,
+			Restarts:   restarts,
+			ExitCode:   exitCode,
 		})
 	}
 	return status, nil
 }
 
-func (s sdk) getURLWithPortMapping(ctx context.Context, targetGroupArns []string) ([]compose.PortPublisher, error) {
-	if len(targetGroupArns) == 0 {
-		return nil, nil
-	}
-	groups, err := s.ELB.DescribeTargetGroups(&elbv2.DescribeTargetGroupsInput{
-		TargetGroupArns: aws.StringSlice(targetGroupArns),
+// countRestartedTasks counts the stopped tasks of a service which essential container exited, ECS replacing them with
+// new tasks, and returns the exit code of the container last stopped. ECS only keeps stopped tasks for a short while, so
+// this only counts recent restarts.
+func (s sdk) countRestartedTasks(ctx context.Context, cluster string, service string, container string) (int, *int, error) {
+	tasks, err := s.ECS.ListTasksWithContext(ctx, &ecs.ListTasksInput{
+		Cluster:       aws.String(cluster),
+		ServiceName:   aws.String(service),
+		DesiredStatus: aws.String(ecs.DesiredStatusStopped),
 	})
-	if err != nil {
-		return nil, err
-	}
-	lbarns := []*string{}
-	for _, tg := range groups.TargetGroups {
-		lbarns = append(lbarns, tg.LoadBalancerArns...)
+	if err != nil || len(tasks.TaskArns) == 0 {
+		return 0, nil, err
 	}
-
-	lbs, err := s.ELB.DescribeLoadBalancersWithContext(ctx, &elbv2.DescribeLoadBalancersInput{
-		LoadBalancerArns: lbarns,
+	stopped, err := s.ECS.DescribeTasksWithContext(ctx, &ecs.DescribeTasksInput{
+		Cluster: aws.String(cluster),
+		Tasks:   tasks.TaskArns,
 	})
-
 	if err != nil {
-		return nil, err
+		return 0, nil, err
 	}
-	filterLB := func(arn *string, lbs []*elbv2.LoadBalancer) *elbv2.LoadBalancer {
-		if aws.StringValue(arn) == "" {
-			// load balancer arn is nil/""
-			return nil
+	var (
+		restarts int
+		exitCode *int
+		last     time.Time
+	)
+	for _, task := range stopped.Tasks {
+		if aws.StringValue(task.StopCode) != ecs.TaskStopCodeEssentialContainerExited {
+			continue
 		}
-		for _, lb := range lbs {
-			if aws.StringValue(lb.LoadBalancerArn) == aws.StringValue(arn) {
-				return lb
+		restarts++
+		if !aws.TimeValue(task.StoppedAt).After(last) {
+			continue
+		}
+		for _, c := range task.Containers {
+			if aws.StringValue(c.Name) == container && c.ExitCode != nil {
+				last = aws.TimeValue(task.StoppedAt)
+				code := int(aws.Int64Value(c.ExitCode))
+				exitCode = &code
 			}
 		}
-		return nil
 	}
-	loadBalancers := []compose.PortPublisher{}
-	for _, tg := range groups.TargetGroups {
-		for _, lbarn := range tg.LoadBalancerArns {
-			lb := filterLB(lbarn, lbs.LoadBalancers)
-			if lb == nil {
-				continue
-			}
-			loadBalancers = append(loadBalancers, compose.PortPublisher{
+	return restarts, exitCode, nil
+}
+
+type deployedService struct {
+	ARN            string
+	TaskDefinition string
+	ConfigHash     string
+}
+
+// DescribeDeployedServices returns the task definition and configuration hash of deployed services, indexed by compose service name
+func (s sdk) DescribeDeployedServices(ctx context.Context, cluster string, arns []string) (map[string]deployedService, error) {
+	deployed := map[string]deployedService{}
+	// DescribeServices accepts up to 10 services
+	for len(arns) > 0 {
+		batch := arns
+		if len(batch) > 10 {
+			batch = arns[:10]
+		}
+		arns = arns[len(batch):]
+		services, err := s.ECS.DescribeServicesWithContext(ctx, &ecs.DescribeServicesInput{
+			Cluster:  aws.String(cluster),
+			Services: aws.StringSlice(batch),
+			Include:  aws.StringSlice([]string{"TAGS"}),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, service := range services.Services {
+			var name, hash string
+			for _, t := range service.Tags {
+				switch aws.StringValue(t.Key) {
+				case compose.ServiceTag:
+					name = aws.StringValue(t.Value)
+				case compose.ConfigHashTag:
+					hash = aws.StringValue(t.Value)
+				}
+			}
+			if name == "" {
+				continue
+			}
+			deployed[name] = deployedService{
+				ARN:            aws.StringValue(service.ServiceArn),
+				TaskDefinition: aws.StringValue(service.TaskDefinition),
+				ConfigHash:     hash,
+			}
+		}
+	}
+	return deployed, nil
+}
+
+// DescribeServiceDefinition returns a service and the task definition it runs
+func (s sdk) DescribeServiceDefinition(ctx context.Context, cluster string, service string) (*ecs.Service, *ecs.TaskDefinition, error) {
+	services, err := s.ECS.DescribeServicesWithContext(ctx, &ecs.DescribeServicesInput{
+		Cluster:  aws.String(cluster),
+		Services: aws.StringSlice([]string{service}),
+		Include:  aws.StringSlice([]string{"TAGS"}),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(services.Services) == 0 || aws.StringValue(services.Services[0].Status) == "INACTIVE" {
+		return nil, nil, errors.Wrapf(errdefs.ErrNotFound, "service %q in cluster %q", service, cluster)
+	}
+	definition, err := s.ECS.DescribeTaskDefinitionWithContext(ctx, &ecs.DescribeTaskDefinitionInput{
+		TaskDefinition: services.Services[0].TaskDefinition,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return services.Services[0], definition.TaskDefinition, nil
+}
+
+// TagService adds tags to a service, which must use the long ARN format
+func (s sdk) TagService(ctx context.Context, arn string, tags map[string]string) error {
+	var ecsTags []*ecs.Tag
+	for k, v := range tags {
+		ecsTags = append(ecsTags, &ecs.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	_, err := s.ECS.TagResourceWithContext(ctx, &ecs.TagResourceInput{
+		ResourceArn: aws.String(arn),
+		Tags:        ecsTags,
+	})
+	return err
+}
+
+// ListImportedServices returns the ARN of the services of a cluster imported in a project
+func (s sdk) ListImportedServices(ctx context.Context, cluster string, project string) ([]string, error) {
+	var arns []*string
+	err := s.ECS.ListServicesPagesWithContext(ctx, &ecs.ListServicesInput{
+		Cluster: aws.String(cluster),
+	}, func(page *ecs.ListServicesOutput, lastPage bool) bool {
+		arns = append(arns, page.ServiceArns...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	imported := []string{}
+	// DescribeServices accepts up to 10 services
+	for len(arns) > 0 {
+		batch := arns
+		if len(batch) > 10 {
+			batch = arns[:10]
+		}
+		arns = arns[len(batch):]
+		services, err := s.ECS.DescribeServicesWithContext(ctx, &ecs.DescribeServicesInput{
+			Cluster:  aws.String(cluster),
+			Services: batch,
+			Include:  aws.StringSlice([]string{"TAGS"}),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, service := range services.Services {
+			var inProject, isImported bool
+			for _, t := range service.Tags {
+				switch aws.StringValue(t.Key) {
+				case compose.ProjectTag:
+					inProject = aws.StringValue(t.Value) == project
+				case compose.ImportedTag:
+					isImported = true
+				}
+			}
+			if inProject && isImported {
+				imported = append(imported, aws.StringValue(service.ServiceArn))
+			}
+		}
+	}
+	return imported, nil
+}
+
+// DeleteService removes a service, stopping its running tasks
+func (s sdk) DeleteService(ctx context.Context, cluster string, arn string) error {
+	_, err := s.ECS.DeleteServiceWithContext(ctx, &ecs.DeleteServiceInput{
+		Cluster: aws.String(cluster),
+		Service: aws.String(arn),
+		Force:   aws.Bool(true),
+	})
+	return err
+}
+
+// ForceNewDeployment replaces the running tasks of a service, even if its definition didn't change
+func (s sdk) ForceNewDeployment(ctx context.Context, cluster string, arn string) error {
+	_, err := s.ECS.UpdateServiceWithContext(ctx, &ecs.UpdateServiceInput{
+		Cluster:            aws.String(cluster),
+		Service:            aws.String(arn),
+		ForceNewDeployment: aws.Bool(true),
+	})
+	return err
+}
+
+func (s sdk) getURLWithPortMapping(ctx context.Context, targetGroupArns []string) ([]compose.PortPublisher, error) {
+	if len(targetGroupArns) == 0 {
+		return nil, nil
+	}
+	groups, err := s.ELB.DescribeTargetGroups(&elbv2.DescribeTargetGroupsInput{
+		TargetGroupArns: aws.StringSlice(targetGroupArns),
+	})
+	if err != nil {
+		return nil, err
+	}
+	lbarns := []*string{}
+	for _, tg := range groups.TargetGroups {
+		lbarns = append(lbarns, tg.LoadBalancerArns...)
+	}
+
+	lbs, err := s.ELB.DescribeLoadBalancersWithContext(ctx, &elbv2.DescribeLoadBalancersInput{
+		LoadBalancerArns: lbarns,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	filterLB := func(arn *string, lbs []*elbv2.LoadBalancer) *elbv2.LoadBalancer {
+		if aws.StringValue(arn) == "" {
+			// load balancer arn is nil/""
+			return nil
+		}
+		for _, lb := range lbs {
+			if aws.StringValue(lb.LoadBalancerArn) == aws.StringValue(arn) {
+				return lb
+			}
+		}
+		return nil
+	}
+	loadBalancers := []compose.PortPublisher{}
+	for _, tg := range groups.TargetGroups {
+		for _, lbarn := range tg.LoadBalancerArns {
+			lb := filterLB(lbarn, lbs.LoadBalancers)
+			if lb == nil {
+				continue
+			}
+			loadBalancers = append(loadBalancers, compose.PortPublisher{
 				URL:           aws.StringValue(lb.DNSName),
 				TargetPort:    int(aws.Int64Value(tg.Port)),
 				PublishedPort: int(aws.Int64Value(tg.Port)),
@@ -613,6 +1299,292 @@ func (s sdk) ListTasks(ctx context.Context, cluster string, family string) ([]st
 	return arns, nil
 }
 
+// ListRunningTaskIDs returns the IDs of the tasks running in a cluster
+func (s sdk) ListRunningTaskIDs(ctx context.Context, cluster string) ([]string, error) {
+	var ids []string
+	err := s.ECS.ListTasksPagesWithContext(ctx, &ecs.ListTasksInput{
+		Cluster:       aws.String(cluster),
+		DesiredStatus: aws.String(ecs.DesiredStatusRunning),
+	}, func(page *ecs.ListTasksOutput, lastPage bool) bool {
+		for _, arn := range page.TaskArns {
+			parts := strings.Split(aws.StringValue(arn), "/")
+			ids = append(ids, parts[len(parts)-1])
+		}
+		return true
+	})
+	return ids, err
+}
+
+// deploymentMetricNamespace is the CloudWatch namespace of the metric counting project deployments
+const deploymentMetricNamespace = "DockerCompose"
+
+// PutDeploymentMetric publishes a data point of the Deployments metric of a project, for dashboards to annotate graphs
+// with deployments
+func (s sdk) PutDeploymentMetric(ctx context.Context, project string) error {
+	_, err := s.CWM.PutMetricDataWithContext(ctx, &cloudwatch.PutMetricDataInput{
+		Namespace: aws.String(deploymentMetricNamespace),
+		MetricData: []*cloudwatch.MetricDatum{
+			{
+				MetricName: aws.String("Deployments"),
+				Dimensions: []*cloudwatch.Dimension{
+					{Name: aws.String("Project"), Value: aws.String(project)},
+				},
+				Timestamp: aws.Time(time.Now()),
+				Unit:      aws.String(cloudwatch.StandardUnitCount),
+				Value:     aws.Float64(1),
+			},
+		},
+	})
+	return err
+}
+
+// GetTaskDefinitionImages returns the images of the application containers declared by task definitions
+func (s sdk) GetTaskDefinitionImages(ctx context.Context, arns []string) ([]string, error) {
+	var images []string
+	for _, arn := range arns {
+		definition, err := s.ECS.DescribeTaskDefinitionWithContext(ctx, &ecs.DescribeTaskDefinitionInput{
+			TaskDefinition: aws.String(arn),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, container := range definition.TaskDefinition.ContainerDefinitions {
+			if strings.HasSuffix(aws.StringValue(container.Name), "_InitContainer") {
+				continue
+			}
+			images = append(images, aws.StringValue(container.Image))
+		}
+	}
+	return images, nil
+}
+
+// ListOrphanTasks returns tasks labelled with the project which haven't been started by one of the project services
+func (s sdk) ListOrphanTasks(ctx context.Context, cluster string, project string) ([]string, error) {
+	var arns []*string
+	err := s.ECS.ListTasksPagesWithContext(ctx, &ecs.ListTasksInput{
+		Cluster: aws.String(cluster),
+	}, func(page *ecs.ListTasksOutput, lastPage bool) bool {
+		arns = append(arns, page.TaskArns...)
+		return true
+	})
+	if err != nil || len(arns) == 0 {
+		return nil, err
+	}
+	orphans := []string{}
+	// DescribeTasks accepts up to 100 tasks
+	for start := 0; start < len(arns); start += 100 {
+		end := start + 100
+		if end > len(arns) {
+			end = len(arns)
+		}
+		tasks, err := s.ECS.DescribeTasksWithContext(ctx, &ecs.DescribeTasksInput{
+			Cluster: aws.String(cluster),
+			Tasks:   arns[start:end],
+			Include: aws.StringSlice([]string{"TAGS"}),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range tasks.Tasks {
+			if strings.HasPrefix(aws.StringValue(task.Group), "service:") {
+				continue
+			}
+			for _, t := range task.Tags {
+				if aws.StringValue(t.Key) == compose.ProjectTag && aws.StringValue(t.Value) == project {
+					orphans = append(orphans, aws.StringValue(task.TaskArn))
+				}
+			}
+		}
+	}
+	return orphans, nil
+}
+
+// StopTask stops a running task
+func (s sdk) StopTask(ctx context.Context, cluster string, arn string, reason string) error {
+	_, err := s.ECS.StopTaskWithContext(ctx, &ecs.StopTaskInput{
+		Cluster: aws.String(cluster),
+		Task:    aws.String(arn),
+		Reason:  aws.String(reason),
+	})
+	return err
+}
+
+// RunTask starts a standalone task in the cluster and returns its ARN
+func (s sdk) RunTask(ctx context.Context, cluster string, taskDefinition string, launchType string, network *ecs.AwsVpcConfiguration, tags map[string]string) (string, error) {
+	var t []*ecs.Tag
+	for k, v := range tags {
+		t = append(t, &ecs.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	input := &ecs.RunTaskInput{
+		Cluster:        aws.String(cluster),
+		TaskDefinition: aws.String(taskDefinition),
+		LaunchType:     aws.String(launchType),
+		NetworkConfiguration: &ecs.NetworkConfiguration{
+			AwsvpcConfiguration: network,
+		},
+		PropagateTags: aws.String(ecs.PropagateTagsTaskDefinition),
+		Tags:          t,
+	}
+	if launchType == ecs.LaunchTypeFargate {
+		input.PlatformVersion = aws.String("1.4.0")
+	}
+	run, err := s.ECS.RunTaskWithContext(ctx, input)
+	if err != nil {
+		return "", err
+	}
+	for _, failure := range run.Failures {
+		return "", errors.Errorf("cannot run task %s: %s", taskDefinition, aws.StringValue(failure.Reason))
+	}
+	if len(run.Tasks) == 0 {
+		return "", errors.Errorf("cannot run task %s", taskDefinition)
+	}
+	return aws.StringValue(run.Tasks[0].TaskArn), nil
+}
+
+// WaitTaskStopped waits for a task to stop and returns the exit code of a container, with the reason it stopped
+func (s sdk) WaitTaskStopped(ctx context.Context, cluster string, arn string, container string) (int, string, error) {
+	input := &ecs.DescribeTasksInput{
+		Cluster: aws.String(cluster),
+		Tasks:   []*string{aws.String(arn)},
+	}
+	// jobs can run longer than the default 10 minutes of the waiter
+	err := s.ECS.WaitUntilTasksStoppedWithContext(ctx, input, request.WithWaiterMaxAttempts(0), request.WithWaiterDelay(request.ConstantWaiterDelay(s.pollingInterval)))
+	if err != nil {
+		return 0, "", err
+	}
+	described, err := s.ECS.DescribeTasksWithContext(ctx, input)
+	if err != nil {
+		return 0, "", err
+	}
+	for _, task := range described.Tasks {
+		for _, c := range task.Containers {
+			if aws.StringValue(c.Name) != container {
+				continue
+			}
+			reason := aws.StringValue(c.Reason)
+			if reason == "" {
+				reason = aws.StringValue(task.StoppedReason)
+			}
+			if c.ExitCode == nil {
+				// the container didn't run, e.g. the image couldn't be pulled
+				return -1, reason, nil
+			}
+			return int(aws.Int64Value(c.ExitCode)), reason, nil
+		}
+	}
+	return 0, "", errors.Wrapf(errdefs.ErrNotFound, "container %q of task %s", container, arn)
+}
+
+// GetNamespace returns the name and type of a Cloud Map namespace
+func (s sdk) GetNamespace(ctx context.Context, id string) (string, string, error) {
+	namespace, err := s.SD.GetNamespaceWithContext(ctx, &servicediscovery.GetNamespaceInput{
+		Id: aws.String(id),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == servicediscovery.ErrCodeNamespaceNotFound {
+			return "", "", errors.Wrapf(errdefs.ErrNotFound, "Cloud Map namespace %s", id)
+		}
+		return "", "", err
+	}
+	return aws.StringValue(namespace.Namespace.Name), aws.StringValue(namespace.Namespace.Type), nil
+}
+
+// GetTaskContainer returns the ARN of a running task of the service and the runtime ID of its container
+func (s sdk) GetTaskContainer(ctx context.Context, cluster string, service string, container string) (string, string, error) {
+	tasks, err := s.ECS.ListTasksWithContext(ctx, &ecs.ListTasksInput{
+		Cluster:       aws.String(cluster),
+		ServiceName:   aws.String(service),
+		DesiredStatus: aws.String(ecs.DesiredStatusRunning),
+	})
+	if err != nil {
+		return "", "", err
+	}
+	if len(tasks.TaskArns) == 0 {
+		return "", "", errors.Wrapf(errdefs.ErrNotFound, "no running task for service %q", container)
+	}
+	described, err := s.ECS.DescribeTasksWithContext(ctx, &ecs.DescribeTasksInput{
+		Cluster: aws.String(cluster),
+		Tasks:   tasks.TaskArns,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	for _, task := range described.Tasks {
+		for _, c := range task.Containers {
+			if aws.StringValue(c.Name) == container && c.RuntimeId != nil {
+				return aws.StringValue(task.TaskArn), *c.RuntimeId, nil
+			}
+		}
+	}
+	return "", "", errors.Wrapf(errdefs.ErrNotFound, "no running container for service %q", container)
+}
+
+// StartSession opens a Session Manager session, returning the session to be handed to the session manager plugin
+func (s sdk) StartSession(ctx context.Context, input *ssm.StartSessionInput) (*ssm.StartSessionOutput, error) {
+	return s.SSM.StartSessionWithContext(ctx, input)
+}
+
+// ListProjectFileSystems returns IDs of the EFS file systems labelled with the project
+func (s sdk) ListProjectFileSystems(ctx context.Context, project string) ([]string, error) {
+	ids := []string{}
+	var marker *string
+	for {
+		fileSystems, err := s.EFS.DescribeFileSystemsWithContext(ctx, &efs.DescribeFileSystemsInput{
+			Marker: marker,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, fs := range fileSystems.FileSystems {
+			for _, t := range fs.Tags {
+				if aws.StringValue(t.Key) == compose.ProjectTag && aws.StringValue(t.Value) == project {
+					ids = append(ids, aws.StringValue(fs.FileSystemId))
+				}
+			}
+		}
+		if fileSystems.NextMarker == nil {
+			return ids, nil
+		}
+		marker = fileSystems.NextMarker
+	}
+}
+
+// DeleteFileSystem deletes an EFS file system and its mount targets
+func (s sdk) DeleteFileSystem(ctx context.Context, id string) error {
+	mounts, err := s.EFS.DescribeMountTargetsWithContext(ctx, &efs.DescribeMountTargetsInput{
+		FileSystemId: aws.String(id),
+	})
+	if err != nil {
+		return err
+	}
+	for _, mount := range mounts.MountTargets {
+		_, err := s.EFS.DeleteMountTargetWithContext(ctx, &efs.DeleteMountTargetInput{
+			MountTargetId: mount.MountTargetId,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	// mount targets are deleted asynchronously, and file system can't be deleted until they all are
+	for len(mounts.MountTargets) > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.pollingInterval):
+		}
+		mounts, err = s.EFS.DescribeMountTargetsWithContext(ctx, &efs.DescribeMountTargetsInput{
+			FileSystemId: aws.String(id),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	_, err = s.EFS.DeleteFileSystemWithContext(ctx, &efs.DeleteFileSystemInput{
+		FileSystemId: aws.String(id),
+	})
+	return err
+}
+
 func (s sdk) GetPublicIPs(ctx context.Context, interfaces ...string) (map[string]string, error) {
 	desc, err := s.EC2.DescribeNetworkInterfaces(&ec2.DescribeNetworkInterfacesInput{
 		NetworkInterfaceIds: aws.StringSlice(interfaces),
@@ -705,6 +1677,75 @@ func (s sdk) GetParameter(ctx context.Context, name string) (string, error) {
 	return ami.ImageID, nil
 }
 
+// GetParametersByPath returns the parameters directly under path, by name relative to the path. SecureString
+// parameters are rejected, as their value would be set in clear text on the task definition.
+func (s sdk) GetParametersByPath(ctx context.Context, path string) (map[string]string, error) {
+	parameters := map[string]string{}
+	var token *string
+	for {
+		out, err := s.SSM.GetParametersByPathWithContext(ctx, &ssm.GetParametersByPathInput{
+			Path:      aws.String(path),
+			NextToken: token,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, parameter := range out.Parameters {
+			if aws.StringValue(parameter.Type) == ssm.ParameterTypeSecureString {
+				return nil, errors.Errorf("parameter %s is a SecureString, declare it as a secret", aws.StringValue(parameter.Name))
+			}
+			parameters[strings.TrimPrefix(aws.StringValue(parameter.Name), path)] = aws.StringValue(parameter.Value)
+		}
+		if out.NextToken == nil {
+			return parameters, nil
+		}
+		token = out.NextToken
+	}
+}
+
+// regionsParameter is the SSM parameter recording in the context region the regions of a project deployed with
+// compose up --regions, which may not include the context region
+func regionsParameter(project string) string {
+	return fmt.Sprintf("/docker/compose/%s/regions", project)
+}
+
+// PutProjectRegions records the regions a project is deployed to
+func (s sdk) PutProjectRegions(ctx context.Context, project string, regions []string) error {
+	_, err := s.SSM.PutParameterWithContext(ctx, &ssm.PutParameterInput{
+		Name:      aws.String(regionsParameter(project)),
+		Type:      aws.String(ssm.ParameterTypeStringList),
+		Value:     aws.String(strings.Join(regions, ",")),
+		Overwrite: aws.Bool(true),
+	})
+	return err
+}
+
+// GetProjectRegions returns the regions recorded by PutProjectRegions, none if the project wasn't deployed with
+// compose up --regions
+func (s sdk) GetProjectRegions(ctx context.Context, project string) ([]string, error) {
+	parameter, err := s.SSM.GetParameterWithContext(ctx, &ssm.GetParameterInput{
+		Name: aws.String(regionsParameter(project)),
+	})
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == ssm.ErrCodeParameterNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(aws.StringValue(parameter.Parameter.Value), ","), nil
+}
+
+// DeleteProjectRegions removes the regions recorded by PutProjectRegions
+func (s sdk) DeleteProjectRegions(ctx context.Context, project string) error {
+	_, err := s.SSM.DeleteParameterWithContext(ctx, &ssm.DeleteParameterInput{
+		Name: aws.String(regionsParameter(project)),
+	})
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == ssm.ErrCodeParameterNotFound {
+		return nil
+	}
+	return err
+}
+
 func (s sdk) SecurityGroupExists(ctx context.Context, sg string) (bool, error) {
 	desc, err := s.EC2.DescribeSecurityGroupsWithContext(ctx, &ec2.DescribeSecurityGroupsInput{
 		GroupIds: aws.StringSlice([]string{sg}),
@@ -714,3 +1755,356 @@ func (s sdk) SecurityGroupExists(ctx context.Context, sg string) (bool, error) {
 	}
 	return len(desc.SecurityGroups) > 0, nil
 }
+
+const (
+	resourceLoadBalancer = "load balancer"
+	resourceTargetGroup  = "target group"
+	resourceSecret       = "secret"
+	resourceRepository   = "repository"
+	resourceFileSystem   = "file system"
+	resourceLogGroup     = "log group"
+)
+
+// ListProjectResources returns resources labelled with a compose project, which can be deleted by DeleteProjectResource
+func (s sdk) ListProjectResources(ctx context.Context) ([]compose.OrphanResource, error) {
+	resources := []compose.OrphanResource{}
+	err := s.RGT.GetResourcesPagesWithContext(ctx, &resourcegroupstaggingapi.GetResourcesInput{
+		TagFilters: []*resourcegroupstaggingapi.TagFilter{
+			{Key: aws.String(compose.ProjectTag)},
+		},
+	}, func(page *resourcegroupstaggingapi.GetResourcesOutput, lastPage bool) bool {
+		for _, r := range page.ResourceTagMappingList {
+			resourceType := projectResourceType(aws.StringValue(r.ResourceARN))
+			if resourceType == "" {
+				continue
+			}
+			for _, t := range r.Tags {
+				if aws.StringValue(t.Key) == compose.ProjectTag {
+					resources = append(resources, compose.OrphanResource{
+						ID:      aws.StringValue(r.ResourceARN),
+						Type:    resourceType,
+						Project: aws.StringValue(t.Value),
+					})
+				}
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// log groups are named after the project
+	err = s.CW.DescribeLogGroupsPagesWithContext(ctx, &cloudwatchlogs.DescribeLogGroupsInput{
+		LogGroupNamePrefix: aws.String(logGroupPrefix),
+	}, func(page *cloudwatchlogs.DescribeLogGroupsOutput, lastPage bool) bool {
+		for _, g := range page.LogGroups {
+			name := aws.StringValue(g.LogGroupName)
+			resources = append(resources, compose.OrphanResource{
+				ID:      name,
+				Type:    resourceLogGroup,
+				Project: strings.TrimPrefix(name, logGroupPrefix),
+			})
+		}
+		return true
+	})
+	return resources, err
+}
+
+// projectResourceType returns the type of a resource which can be deleted by DeleteProjectResource, or an empty string
+func projectResourceType(resourceARN string) string {
+	parsed, err := arn.Parse(resourceARN)
+	if err != nil {
+		return ""
+	}
+	switch {
+	case parsed.Service == "elasticloadbalancing" && strings.HasPrefix(parsed.Resource, "loadbalancer/"):
+		return resourceLoadBalancer
+	case parsed.Service == "elasticloadbalancing" && strings.HasPrefix(parsed.Resource, "targetgroup/"):
+		return resourceTargetGroup
+	case parsed.Service == "secretsmanager":
+		return resourceSecret
+	case parsed.Service == "ecr" && strings.HasPrefix(parsed.Resource, "repository/"):
+		return resourceRepository
+	case parsed.Service == "elasticfilesystem" && strings.HasPrefix(parsed.Resource, "file-system/"):
+		return resourceFileSystem
+	}
+	return ""
+}
+
+// DeleteProjectResource deletes a resource listed by ListProjectResources
+func (s sdk) DeleteProjectResource(ctx context.Context, resource compose.OrphanResource) error {
+	var err error
+	switch resource.Type {
+	case resourceLoadBalancer:
+		_, err = s.ELB.DeleteLoadBalancerWithContext(ctx, &elbv2.DeleteLoadBalancerInput{
+			LoadBalancerArn: aws.String(resource.ID),
+		})
+	case resourceTargetGroup:
+		_, err = s.ELB.DeleteTargetGroupWithContext(ctx, &elbv2.DeleteTargetGroupInput{
+			TargetGroupArn: aws.String(resource.ID),
+		})
+	case resourceSecret:
+		err = s.DeleteSecret(ctx, resource.ID, false)
+	case resourceRepository:
+		parsed, perr := arn.Parse(resource.ID)
+		if perr != nil {
+			return perr
+		}
+		_, err = s.ECR.DeleteRepositoryWithContext(ctx, &ecr.DeleteRepositoryInput{
+			RepositoryName: aws.String(strings.TrimPrefix(parsed.Resource, "repository/")),
+			Force:          aws.Bool(true),
+		})
+	case resourceFileSystem:
+		parsed, perr := arn.Parse(resource.ID)
+		if perr != nil {
+			return perr
+		}
+		err = s.DeleteFileSystem(ctx, strings.TrimPrefix(parsed.Resource, "file-system/"))
+	case resourceLogGroup:
+		_, err = s.CW.DeleteLogGroupWithContext(ctx, &cloudwatchlogs.DeleteLogGroupInput{
+			LogGroupName: aws.String(resource.ID),
+		})
+	default:
+		return errors.Wrapf(errdefs.ErrNotImplemented, "cannot delete %s %s", resource.Type, resource.ID)
+	}
+	return err
+}
+
+// GetFargatePrices returns the on-demand hourly prices in USD of a vCPU and of a GB of memory for Linux Fargate tasks in region
+func (s sdk) GetFargatePrices(ctx context.Context, region string) (float64, float64, error) {
+	var cpuPrice, memoryPrice float64
+	err := s.PRC.GetProductsPagesWithContext(ctx, &pricing.GetProductsInput{
+		ServiceCode: aws.String("AmazonECS"),
+		Filters: []*pricing.Filter{
+			{
+				Type:  aws.String(pricing.FilterTypeTermMatch),
+				Field: aws.String("regionCode"),
+				Value: aws.String(region),
+			},
+		},
+	}, func(page *pricing.GetProductsOutput, lastPage bool) bool {
+		for _, product := range page.PriceList {
+			usageType, price, ok := onDemandPrice(product)
+			if !ok || strings.Contains(usageType, "Spot") {
+				continue
+			}
+			switch {
+			case strings.HasSuffix(usageType, "Fargate-vCPU-Hours:perCPU"):
+				cpuPrice = price
+			case strings.HasSuffix(usageType, "Fargate-GB-Hours"):
+				memoryPrice = price
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	if cpuPrice == 0 || memoryPrice == 0 {
+		return 0, 0, errors.Wrapf(errdefs.ErrNotFound, "Fargate prices for region %q", region)
+	}
+	return cpuPrice, memoryPrice, nil
+}
+
+// onDemandPrice extracts the usage type and on-demand USD price from a Pricing API product
+func onDemandPrice(product aws.JSONValue) (string, float64, bool) {
+	attributes, _ := lookup(product, "product", "attributes")
+	usageType, _ := attributes["usagetype"].(string)
+	onDemand, _ := lookup(product, "terms", "OnDemand")
+	for _, term := range onDemand {
+		dimensions, _ := lookup(term, "priceDimensions")
+		for _, dimension := range dimensions {
+			usd, _ := lookup(dimension, "pricePerUnit")
+			value, _ := usd["USD"].(string)
+			price, err := strconv.ParseFloat(value, 64)
+			if err == nil {
+				return usageType, price, true
+			}
+		}
+	}
+	return "", 0, false
+}
+
+func lookup(value interface{}, path ...string) (map[string]interface{}, bool) {
+	current, ok := value.(map[string]interface{})
+	if !ok {
+		if j, isJSON := value.(aws.JSONValue); isJSON {
+			current, ok = map[string]interface{}(j), true
+		}
+	}
+	for _, key := range path {
+		if !ok {
+			return nil, false
+		}
+		current, ok = current[key].(map[string]interface{})
+	}
+	return current, ok
+}
+
+// CreateLockTable creates the DynamoDB table holding project locks, if it doesn't exist yet
+func (s sdk) CreateLockTable(ctx context.Context, table string) error {
+	_, err := s.DDB.DescribeTableWithContext(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(table),
+	})
+	if err == nil {
+		return nil
+	}
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != dynamodb.ErrCodeResourceNotFoundException {
+		return err
+	}
+	logrus.Debug("Create DynamoDB table for project locks: ", table)
+	_, err = s.DDB.CreateTableWithContext(ctx, &dynamodb.CreateTableInput{
+		TableName:   aws.String(table),
+		BillingMode: aws.String(dynamodb.BillingModePayPerRequest),
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{
+				AttributeName: aws.String("Project"),
+				AttributeType: aws.String(dynamodb.ScalarAttributeTypeS),
+			},
+		},
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{
+				AttributeName: aws.String("Project"),
+				KeyType:       aws.String(dynamodb.KeyTypeHash),
+			},
+		},
+	})
+	if aerr, ok := err.(awserr.Error); err != nil && (!ok || aerr.Code() != dynamodb.ErrCodeResourceInUseException) {
+		return err
+	}
+	return s.DDB.WaitUntilTableExistsWithContext(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(table),
+	})
+}
+
+// PutLock records the project lock unless it's already held, in which case the current holder is returned
+func (s sdk) PutLock(ctx context.Context, table string, project string, info compose.LockInfo) (*compose.LockInfo, error) {
+	_, err := s.DDB.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(table),
+		Item: map[string]*dynamodb.AttributeValue{
+			"Project":  {S: aws.String(project)},
+			"LockID":   {S: aws.String(info.ID)},
+			"Owner":    {S: aws.String(info.Owner)},
+			"Acquired": {S: aws.String(info.Acquired.Format(time.RFC3339))},
+		},
+		ConditionExpression: aws.String("attribute_not_exists(Project)"),
+	})
+	if err == nil {
+		return nil, nil
+	}
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != dynamodb.ErrCodeConditionalCheckFailedException {
+		return nil, err
+	}
+	item, err := s.DDB.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(table),
+		ConsistentRead: aws.Bool(true),
+		Key: map[string]*dynamodb.AttributeValue{
+			"Project": {S: aws.String(project)},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if item.Item == nil {
+		// released in the meantime
+		return s.PutLock(ctx, table, project, info)
+	}
+	holder := compose.LockInfo{Owner: "unknown"}
+	if v, ok := item.Item["LockID"]; ok {
+		holder.ID = aws.StringValue(v.S)
+	}
+	if v, ok := item.Item["Owner"]; ok {
+		holder.Owner = aws.StringValue(v.S)
+	}
+	if v, ok := item.Item["Acquired"]; ok {
+		holder.Acquired, _ = time.Parse(time.RFC3339, aws.StringValue(v.S))
+	}
+	return &holder, nil
+}
+
+// DeleteLock releases the project lock. When id is set, the lock is only released if still held with this ID
+func (s sdk) DeleteLock(ctx context.Context, table string, project string, id string) (bool, error) {
+	input := &dynamodb.DeleteItemInput{
+		TableName: aws.String(table),
+		Key: map[string]*dynamodb.AttributeValue{
+			"Project": {S: aws.String(project)},
+		},
+		ReturnValues: aws.String(dynamodb.ReturnValueAllOld),
+	}
+	if id != "" {
+		input.ConditionExpression = aws.String("LockID = :id")
+		input.ExpressionAttributeValues = map[string]*dynamodb.AttributeValue{
+			":id": {S: aws.String(id)},
+		}
+	}
+	output, err := s.DDB.DeleteItemWithContext(ctx, input)
+	if aerr, ok := err.(awserr.Error); ok {
+		switch aerr.Code() {
+		case dynamodb.ErrCodeConditionalCheckFailedException, dynamodb.ErrCodeResourceNotFoundException:
+			return false, nil
+		}
+	}
+	if err != nil {
+		return false, err
+	}
+	return output.Attributes != nil, nil
+}
+
+// fargateVCPUQuotaCode identifies the quota of vCPUs running Fargate on-demand tasks in a region
+const fargateVCPUQuotaCode = "L-3032A538"
+
+// GetFargateVCPUQuota returns the number of vCPUs the account can run as Fargate on-demand tasks in the region
+func (s sdk) GetFargateVCPUQuota(ctx context.Context) (float64, error) {
+	quota, err := s.SQ.GetServiceQuotaWithContext(ctx, &servicequotas.GetServiceQuotaInput{
+		ServiceCode: aws.String("fargate"),
+		QuotaCode:   aws.String(fargateVCPUQuotaCode),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return aws.Float64Value(quota.Quota.Value), nil
+}
+
+// GetFargateVCPUUsage returns the number of vCPUs currently used by Fargate on-demand tasks in the region
+func (s sdk) GetFargateVCPUUsage(ctx context.Context) (float64, error) {
+	now := time.Now()
+	stats, err := s.CWM.GetMetricStatisticsWithContext(ctx, &cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String("AWS/Usage"),
+		MetricName: aws.String("ResourceCount"),
+		Dimensions: []*cloudwatch.Dimension{
+			{Name: aws.String("Service"), Value: aws.String("Fargate")},
+			{Name: aws.String("Type"), Value: aws.String("Resource")},
+			{Name: aws.String("Resource"), Value: aws.String("vCPU")},
+			{Name: aws.String("Class"), Value: aws.String("Standard/OnDemand")},
+		},
+		StartTime:  aws.Time(now.Add(-5 * time.Minute)),
+		EndTime:    aws.Time(now),
+		Period:     aws.Int64(60),
+		Statistics: []*string{aws.String(cloudwatch.StatisticMaximum)},
+	})
+	if err != nil {
+		return 0, err
+	}
+	usage := 0.
+	for _, point := range stats.Datapoints {
+		if v := aws.Float64Value(point.Maximum); v > usage {
+			usage = v
+		}
+	}
+	return usage, nil
+}
+
+// CreatePolicy creates a customer managed IAM policy and returns its ARN
+func (s sdk) CreatePolicy(ctx context.Context, name string, description string, document []byte) (string, error) {
+	logrus.Debug("Create IAM policy " + name)
+	response, err := s.IAM.CreatePolicyWithContext(ctx, &iam.CreatePolicyInput{
+		PolicyName:     aws.String(name),
+		Description:    aws.String(description),
+		PolicyDocument: aws.String(string(document)),
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(response.Policy.Arn), nil
+}
@@ -28,6 +28,8 @@ import (
 type Secret struct {
 	Name string
 	Keys []string
+	// Path overrides the file the secret is written to, set when the compose secret targets an absolute path
+	Path string `json:",omitempty"`
 }
 
 // CreateSecretFiles retrieve sensitive data from env and store as plain text a a file in path
@@ -38,6 +40,9 @@ func CreateSecretFiles(secret Secret, path string) error {
 	}
 
 	secrets := filepath.Join(path, secret.Name)
+	if secret.Path != "" {
+		secrets = secret.Path
+	}
 
 	if len(secret.Keys) == 0 {
 		// raw Secret
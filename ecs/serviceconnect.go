@@ -0,0 +1,103 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/compose-spec/compose-go/types"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+const (
+	// serviceConnectMetadata is the service metadata key marshall moves to the ServiceConnectConfiguration property,
+	// goformation doesn't support Service Connect yet
+	serviceConnectMetadata = "ServiceConnectConfiguration"
+	// portNamesMetadata is the task definition metadata key marshall moves to the Name of container port mappings
+	portNamesMetadata = "PortNames"
+)
+
+// serviceConnectPortName is the name of a service port, Service Connect referencing ports by name
+func serviceConnectPortName(service string, port uint32) string {
+	name := strings.ToLower(strings.ReplaceAll(service, ".", "-"))
+	return fmt.Sprintf("%s-%d", name, port)
+}
+
+// addServiceConnectPorts maps the ports a service exposes to other services, which Service Connect routes traffic to,
+// and names them
+func addServiceConnectPorts(definition *ecs.TaskDefinition, service types.ServiceConfig) {
+	ports := compose.ServicePorts(service)
+	if len(ports) == 0 {
+		return
+	}
+	names := map[string]string{}
+	for i, container := range definition.ContainerDefinitions {
+		if container.Name != service.Name {
+			continue
+		}
+		mapped := map[int]bool{}
+		for _, mapping := range container.PortMappings {
+			mapped[mapping.ContainerPort] = true
+		}
+		for _, port := range ports {
+			names[fmt.Sprint(port)] = serviceConnectPortName(service.Name, port)
+			if !mapped[int(port)] {
+				definition.ContainerDefinitions[i].PortMappings = append(definition.ContainerDefinitions[i].PortMappings, ecs.TaskDefinition_PortMapping{
+					ContainerPort: int(port),
+					Protocol:      "tcp",
+				})
+			}
+		}
+	}
+	if definition.AWSCloudFormationMetadata == nil {
+		definition.AWSCloudFormationMetadata = map[string]interface{}{}
+	}
+	definition.AWSCloudFormationMetadata[portNamesMetadata] = map[string]map[string]string{service.Name: names}
+}
+
+// serviceConnectConfiguration returns the Service Connect configuration of an ECS service: all services are clients of
+// the namespace, reaching other services by their name, and services exposing ports are also servers, one per port
+func serviceConnectConfiguration(service types.ServiceConfig, cloudMap cloudMapConfig, logConfiguration *ecs.TaskDefinition_LogConfiguration) map[string]interface{} {
+	configuration := map[string]interface{}{
+		"Enabled":   true,
+		"Namespace": cloudMap.name,
+	}
+	var servers []map[string]interface{}
+	for _, port := range compose.ServicePorts(service) {
+		name := serviceConnectPortName(service.Name, port)
+		servers = append(servers, map[string]interface{}{
+			"PortName":      name,
+			"DiscoveryName": name,
+			"ClientAliases": []map[string]interface{}{
+				{"Port": port, "DnsName": service.Name},
+			},
+		})
+	}
+	if len(servers) > 0 {
+		configuration["Services"] = servers
+	}
+	if logConfiguration != nil {
+		configuration["LogConfiguration"] = map[string]interface{}{
+			"LogDriver": logConfiguration.LogDriver,
+			"Options":   logConfiguration.Options,
+		}
+	}
+	return configuration
+}
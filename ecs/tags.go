@@ -31,8 +31,8 @@ func projectTags(project *types.Project) []tags.Tag {
 	}
 }
 
-func serviceTags(project *types.Project, service types.ServiceConfig) []tags.Tag {
-	return []tags.Tag{
+func serviceTags(project *types.Project, service types.ServiceConfig, hash string) []tags.Tag {
+	t := []tags.Tag{
 		{
 			Key:   compose.ProjectTag,
 			Value: project.Name,
@@ -41,7 +41,15 @@ func serviceTags(project *types.Project, service types.ServiceConfig) []tags.Tag
 			Key:   compose.ServiceTag,
 			Value: service.Name,
 		},
+		{
+			Key:   compose.ConfigHashTag,
+			Value: hash,
+		},
+	}
+	if snapshot, ok := service.Labels[compose.EnvSnapshotLabel]; ok {
+		t = append(t, tags.Tag{Key: compose.EnvSnapshotLabel, Value: snapshot})
 	}
+	return t
 }
 
 func networkTags(project *types.Project, net types.NetworkConfig) []tags.Tag {
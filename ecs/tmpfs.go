@@ -0,0 +1,158 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/go-units"
+	"github.com/pkg/errors"
+)
+
+// defaultTmpfsSize is the size in MiB of tmpfs mounts declared without one, ECS requires a size while the compose spec doesn't
+const defaultTmpfsSize = 100
+
+// shmPath is where the container shared memory is mounted
+const shmPath = "/dev/shm"
+
+type tmpfsMount struct {
+	path    string
+	size    int
+	options []string
+}
+
+// serviceTmpfs collects the tmpfs mounts of a service, declared by `tmpfs` or as volumes of type tmpfs
+func serviceTmpfs(service types.ServiceConfig) ([]tmpfsMount, error) {
+	var mounts []tmpfsMount
+	for _, t := range service.Tmpfs {
+		mount, err := parseTmpfs(t)
+		if err != nil {
+			return nil, errors.Wrapf(err, "service %s", service.Name)
+		}
+		mounts = append(mounts, mount)
+	}
+	for _, v := range service.Volumes {
+		if v.Type != types.VolumeTypeTmpfs {
+			continue
+		}
+		mount := tmpfsMount{
+			path: v.Target,
+			size: defaultTmpfsSize,
+		}
+		if v.Tmpfs != nil && v.Tmpfs.Size > 0 {
+			mount.size = toMiB(v.Tmpfs.Size)
+		}
+		if v.ReadOnly {
+			mount.options = []string{"ro"}
+		}
+		mounts = append(mounts, mount)
+	}
+	return mounts, nil
+}
+
+// parseTmpfs parses a tmpfs mount as `path[:options]`, options being a comma separated list which may include `size`
+func parseTmpfs(tmpfs string) (tmpfsMount, error) {
+	mount := tmpfsMount{
+		size: defaultTmpfsSize,
+	}
+	parts := strings.SplitN(tmpfs, ":", 2)
+	mount.path = parts[0]
+	if len(parts) == 1 {
+		return mount, nil
+	}
+	for _, option := range strings.Split(parts[1], ",") {
+		if !strings.HasPrefix(option, "size=") {
+			mount.options = append(mount.options, option)
+			continue
+		}
+		size, err := units.RAMInBytes(strings.TrimPrefix(option, "size="))
+		if err != nil {
+			return mount, errors.Wrapf(err, "invalid tmpfs size for %s", mount.path)
+		}
+		mount.size = toMiB(size)
+	}
+	return mount, nil
+}
+
+// serviceShmSize returns the shared memory size of a service in MiB, 0 if not set
+func serviceShmSize(service types.ServiceConfig) (int, error) {
+	if service.ShmSize == "" {
+		return 0, nil
+	}
+	size, err := units.RAMInBytes(service.ShmSize)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid shm_size for service %s", service.Name)
+	}
+	return toMiB(size), nil
+}
+
+func toMiB(bytes int64) int {
+	size := int(bytes / units.MiB)
+	if bytes%units.MiB != 0 {
+		size++
+	}
+	return size
+}
+
+func toTmpfs(mounts []tmpfsMount) []ecs.TaskDefinition_Tmpfs {
+	if len(mounts) == 0 {
+		return nil
+	}
+	o := []ecs.TaskDefinition_Tmpfs{}
+	for _, m := range mounts {
+		o = append(o, ecs.TaskDefinition_Tmpfs{
+			ContainerPath: m.path,
+			MountOptions:  m.options,
+			Size:          m.size,
+		})
+	}
+	return o
+}
+
+// toEphemeralVolumes backs tmpfs mounts and shared memory by task ephemeral storage, as Fargate doesn't support tmpfs
+func toEphemeralVolumes(mounts []tmpfsMount, shmSize int) ([]ecs.TaskDefinition_Volume, []ecs.TaskDefinition_MountPoint) {
+	var (
+		volumes []ecs.TaskDefinition_Volume
+		points  []ecs.TaskDefinition_MountPoint
+	)
+	add := func(name string, path string, readOnly bool) {
+		volumes = append(volumes, ecs.TaskDefinition_Volume{
+			Name: name,
+		})
+		points = append(points, ecs.TaskDefinition_MountPoint{
+			ContainerPath: path,
+			ReadOnly:      readOnly,
+			SourceVolume:  name,
+		})
+	}
+	for i, m := range mounts {
+		readOnly := false
+		for _, o := range m.options {
+			if o == "ro" {
+				readOnly = true
+			}
+		}
+		add(fmt.Sprintf("tmpfs%d", i), m.path, readOnly)
+	}
+	if shmSize > 0 {
+		add("shm", shmPath, false)
+	}
+	return volumes, points
+}
@@ -18,33 +18,134 @@ package ecs
 
 import (
 	"context"
-	"fmt"
-	"os"
-	"os/signal"
-	"syscall"
 
 	"github.com/compose-spec/compose-go/types"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/registry"
+	"github.com/docker/compose-cli/utils"
 )
 
-func (b *ecsAPIService) Up(ctx context.Context, project *types.Project) error {
-	err := b.SDK.CheckRequirements(ctx, b.Region)
+func (b *ecsAPIService) Up(ctx context.Context, project *types.Project, options compose.UpOptions) error {
+	if len(options.Regions) > 0 {
+		return b.upRegions(ctx, project, options)
+	}
+	return permissionError("up", b.up(ctx, project, options))
+}
+
+func (b *ecsAPIService) up(ctx context.Context, project *types.Project, options compose.UpOptions) error {
+	err := b.preflight(ctx, project, options)
+	if err != nil {
+		return err
+	}
+
+	err = b.SDK.CheckRequirements(ctx, b.Region)
+	if err != nil {
+		return err
+	}
+
+	options.Tags, err = b.ctx.TagPolicy.Apply(options.Tags)
+	if err != nil {
+		return err
+	}
+
+	if !options.OverrideBudget {
+		err = b.checkBudget(ctx, project)
+		if err != nil {
+			return err
+		}
+	}
+
+	if options.Push {
+		err = b.pushImages(ctx, project)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = registry.CheckPlatforms(ctx, project, targetPlatform)
+	if err != nil {
+		return err
+	}
+
+	if options.ResolveImageDigests || b.ctx.ResolveImageDigests {
+		err = registry.PinImages(ctx, project)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = b.createPullCredentials(ctx, project)
+	if err != nil {
+		return err
+	}
+
+	update, err := b.reconcileStack(ctx, project.Name, options.Resume)
 	if err != nil {
 		return err
 	}
 
-	template, err := b.Convert(ctx, project)
+	digests := registry.ResolveImageDigests(ctx, project)
+	stack, err := b.convertToTemplate(ctx, project, digests)
 	if err != nil {
 		return err
 	}
 
-	update, err := b.SDK.StackExists(ctx, project.Name)
+	var (
+		cluster  string
+		deployed map[string]deployedService
+	)
+	if update {
+		cluster, deployed, err = b.deployedServices(ctx, project.Name)
+		if err != nil {
+			return err
+		}
+		if options.Recreate != compose.RecreateForce {
+			err = keepDeployedServices(ctx, project, stack, deployed, digests, options.Recreate)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	template, err := marshall(stack)
+	if err != nil {
+		return err
+	}
+
+	if len(compose.Jobs(project)) > 0 {
+		err = b.upWithJobs(ctx, project, stack, template, update, deployed, options)
+	} else {
+		err = b.deployStack(ctx, project.Name, template, update, options)
+	}
 	if err != nil {
 		return err
 	}
+	err = b.removeImportedServices(ctx, project)
+	if err != nil {
+		return err
+	}
+	if options.Recreate == compose.RecreateForce {
+		err = b.forceNewDeployments(ctx, project, cluster, deployed)
+		if err != nil {
+			return err
+		}
+	}
+	if utils.StringContains(b.ctx.Annotations, store.CloudWatchAnnotation) {
+		if err := b.SDK.PutDeploymentMetric(ctx, project.Name); err != nil {
+			compose.Warn(ctx, "failed to publish the deployment metric to CloudWatch: %s", err)
+		}
+	}
+	return nil
+}
+
+// deployStack creates or updates the project stack and waits for the deployment to complete
+func (b *ecsAPIService) deployStack(ctx context.Context, name string, template []byte, update bool, options compose.UpOptions) error {
 	operation := stackCreate
 	if update {
 		operation = stackUpdate
-		changeset, err := b.SDK.CreateChangeSet(ctx, project.Name, template)
+		changeset, err := b.SDK.CreateChangeSet(ctx, name, template, options.Tags)
 		if err != nil {
 			return err
 		}
@@ -53,20 +154,18 @@ func (b *ecsAPIService) Up(ctx context.Context, project *types.Project) error {
 			return err
 		}
 	} else {
-		err = b.SDK.CreateStack(ctx, project.Name, template)
+		err := b.SDK.CreateStack(ctx, name, template, options.Tags)
 		if err != nil {
 			return err
 		}
 	}
 
-	signalChan := make(chan os.Signal, 1)
-	signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM)
-	go func() {
-		<-signalChan
-		fmt.Println("user interrupted deployment. Deleting stack...")
-		b.Down(ctx, project.Name) // nolint:errcheck
-	}()
-
-	err = b.WaitStackCompletion(ctx, project.Name, operation)
-	return err
+	err := b.WaitStackCompletion(ctx, name, operation)
+	if err != nil {
+		if ctx.Err() != nil {
+			return b.canceledDeployment(compose.Detach(ctx), name, operation, options.CancelCleanup)
+		}
+		return err
+	}
+	return nil
 }
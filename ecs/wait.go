@@ -38,8 +38,8 @@ func (b *ecsAPIService) WaitStackCompletion(ctx context.Context, name string, op
 		return err
 	}
 
-	ticker := time.NewTicker(1 * time.Second)
-	done := make(chan bool)
+	ticker := time.NewTicker(b.ctx.Operations().PollingInterval)
+	done := make(chan bool, 1)
 	go func() {
 		b.SDK.WaitStackComplete(ctx, stackID, operation) //nolint:errcheck
 		ticker.Stop()
@@ -53,6 +53,8 @@ func (b *ecsAPIService) WaitStackCompletion(ctx context.Context, name string, op
 		case <-done:
 			completed = true
 		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
 		}
 		events, err := b.SDK.DescribeStackEvents(ctx, stackID)
 		if err != nil {
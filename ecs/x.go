@@ -29,4 +29,25 @@ const (
 	extensionRetention       = "x-aws-logs_retention"
 	extensionRole            = "x-aws-role"
 	extensionManagedPolicies = "x-aws-policies"
+	extensionCloudMap        = "x-aws-cloudmap"
+	// extensionServiceSecurityGroup attaches existing security groups to a service, as a name or a list
+	extensionServiceSecurityGroup = "x-aws-security-group"
+	// extensionTargetGroup sets a service load balancer health check, stickiness and HTTP to HTTPS redirect
+	extensionTargetGroup = "x-aws-target_group"
+	// extensionLoadBalancerIdleTimeout sets the idle timeout of the application load balancer created for the project
+	extensionLoadBalancerIdleTimeout = "x-aws-loadbalancer_idle_timeout"
+	// extensionDomain serves a service on a custom domain over HTTPS, as a domain name or a mapping
+	extensionDomain = "x-aws-domain"
+	// extensionAllowedCIDRs restricts the CIDRs exposed ports are open to
+	extensionAllowedCIDRs = "x-aws-allowed_cidrs"
+	// extensionDeniedCIDRs blocks CIDRs with a WebACL attached to the application load balancer
+	extensionDeniedCIDRs = "x-aws-denied_cidrs"
+	// extensionWAF attaches an existing WAF WebACL to the application load balancer
+	extensionWAF = "x-aws-waf"
+	// extensionSubnets places the services attached to a network in a subset of the VPC subnets
+	extensionSubnets = "x-aws-subnets"
+	// extensionPortForward enables ECS Exec on the service tasks, which port-forward opens Session Manager sessions with
+	extensionPortForward = "x-aws-port_forward"
+	// extensionMesh runs the project services in a service mesh, behind a proxy sidecar
+	extensionMesh = "x-mesh"
 )
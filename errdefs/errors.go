@@ -17,6 +17,9 @@
 package errdefs
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/pkg/errors"
 )
 
@@ -49,6 +52,11 @@ var (
 	// ErrWrongContextType is returned when the caller tries to get a context
 	// with the wrong type
 	ErrWrongContextType = errors.New("wrong context type")
+	// ErrOffline is returned when a network call is required while running
+	// in offline mode
+	ErrOffline = errors.New("network access disabled in offline mode")
+	// ErrLocked is returned when a resource is locked by another command
+	ErrLocked = errors.New("locked")
 )
 
 // IsNotFoundError returns true if the unwrapped error is ErrNotFound
@@ -85,3 +93,54 @@ func IsErrParsingFailed(err error) bool {
 func IsErrCanceled(err error) bool {
 	return errors.Is(err, ErrCanceled)
 }
+
+// IsErrOffline returns true if the unwrapped error is ErrOffline
+func IsErrOffline(err error) bool {
+	return errors.Is(err, ErrOffline)
+}
+
+// IsErrLocked returns true if the unwrapped error is ErrLocked
+func IsErrLocked(err error) bool {
+	return errors.Is(err, ErrLocked)
+}
+
+// ExitCodeError is returned when the command must exit with the status of a container
+type ExitCodeError struct {
+	Code int
+}
+
+func (e ExitCodeError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// PermissionError is returned when the cloud provider denied an operation for
+// lack of permissions. It lists the actions the operation requires and the
+// role or policy granting them, so users know what to request.
+type PermissionError struct {
+	// Operation is the compose operation that was denied
+	Operation string
+	// Actions lists the missing provider actions, the exact one when the provider reported it
+	Actions []string
+	// Grant is the minimal role or policy granting the actions
+	Grant string
+	// Err is the error returned by the provider
+	Err error
+}
+
+func (e PermissionError) Error() string {
+	msg := fmt.Sprintf("permission denied: %s requires %s", e.Operation, strings.Join(e.Actions, ", "))
+	if e.Grant != "" {
+		msg += fmt.Sprintf("; ask your administrator for %s", e.Grant)
+	}
+	return msg
+}
+
+// Unwrap returns the error returned by the provider
+func (e PermissionError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes PermissionError match ErrForbidden
+func (e PermissionError) Is(target error) bool {
+	return target == ErrForbidden
+}
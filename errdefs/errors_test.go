@@ -50,3 +50,16 @@ func TestIsUnknown(t *testing.T) {
 
 	assert.Assert(t, !IsUnknownError(errors.New("another error")))
 }
+
+func TestPermissionError(t *testing.T) {
+	cause := errors.New("AccessDenied")
+	err := errors.Wrap(PermissionError{
+		Operation: "compose up",
+		Actions:   []string{"ecs:RegisterTaskDefinition"},
+		Grant:     "the AmazonECS_FullAccess policy",
+		Err:       cause,
+	}, "deploying")
+	assert.Assert(t, IsForbiddenError(err))
+	assert.Assert(t, errors.Is(err, cause))
+	assert.Equal(t, err.Error(), "deploying: permission denied: compose up requires ecs:RegisterTaskDefinition; ask your administrator for the AmazonECS_FullAccess policy")
+}
@@ -19,25 +19,31 @@
 package example
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"io"
+	"path/filepath"
+	"time"
 
 	"github.com/compose-spec/compose-go/types"
 
 	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/api/containers"
+	"github.com/docker/compose-cli/api/images"
 	"github.com/docker/compose-cli/api/secrets"
 	"github.com/docker/compose-cli/api/volumes"
 	"github.com/docker/compose-cli/backend"
+	"github.com/docker/compose-cli/config"
+	apicontext "github.com/docker/compose-cli/context"
 	"github.com/docker/compose-cli/context/cloud"
+	"github.com/docker/compose-cli/context/store"
 	"github.com/docker/compose-cli/errdefs"
 )
 
 type apiService struct {
 	containerService
-	composeService
+	compose compose.Service
 }
 
 func (a *apiService) ContainerService() containers.Service {
@@ -45,7 +51,7 @@ func (a *apiService) ContainerService() containers.Service {
 }
 
 func (a *apiService) ComposeService() compose.Service {
-	return &a.composeService
+	return a.compose
 }
 
 func (a *apiService) SecretsService() secrets.Service {
@@ -56,12 +62,29 @@ func (a *apiService) VolumeService() volumes.Service {
 	return nil
 }
 
+func (a *apiService) ImageService() images.Service {
+	return nil
+}
+
 func init() {
 	backend.Register("example", "example", service, cloud.NotImplementedCloudService)
 }
 
 func service(ctx context.Context) (backend.Service, error) {
-	return &apiService{}, nil
+	var exampleContext store.ExampleContext
+	if s := store.ContextStore(ctx); s != nil {
+		// contexts created without endpoint data return fixed output
+		_ = s.GetEndpoint(apicontext.CurrentContext(ctx), &exampleContext)
+	}
+	if exampleContext.Scenario == "" {
+		return &apiService{compose: &composeService{}}, nil
+	}
+	scenario, err := LoadScenario(exampleContext.Scenario)
+	if err != nil {
+		return nil, err
+	}
+	stateFile := filepath.Join(config.Dir(ctx), "example", apicontext.CurrentContext(ctx)+".json")
+	return &apiService{compose: newSimulatedComposeService(scenario, stateFile)}, nil
 }
 
 type containerService struct{}
@@ -129,14 +152,19 @@ func (cs *containerService) Delete(ctx context.Context, id string, request conta
 	return nil
 }
 
+func (cs *containerService) PortForward(ctx context.Context, containerID string, localPort, remotePort uint32) error {
+	fmt.Printf("Forwarding port %d to port %d of container %q\n", localPort, remotePort, containerID)
+	return nil
+}
+
 type composeService struct{}
 
-func (cs *composeService) Up(ctx context.Context, project *types.Project) error {
+func (cs *composeService) Up(ctx context.Context, project *types.Project, options compose.UpOptions) error {
 	fmt.Printf("Up command on project %q", project.Name)
 	return nil
 }
 
-func (cs *composeService) Down(ctx context.Context, project string) error {
+func (cs *composeService) Down(ctx context.Context, project string, options compose.DownOptions) error {
 	fmt.Printf("Down command on project %q", project)
 	return nil
 }
@@ -147,10 +175,103 @@ func (cs *composeService) Ps(ctx context.Context, project string) ([]compose.Ser
 func (cs *composeService) List(ctx context.Context, project string) ([]compose.Stack, error) {
 	return nil, errdefs.ErrNotImplemented
 }
-func (cs *composeService) Logs(ctx context.Context, project string, w io.Writer) error {
+func (cs *composeService) Logs(ctx context.Context, project string, consumer compose.LogConsumer, options compose.LogOptions) error {
 	return errdefs.ErrNotImplemented
 }
 
 func (cs *composeService) Convert(ctx context.Context, project *types.Project) ([]byte, error) {
 	return nil, errdefs.ErrNotImplemented
 }
+
+func (cs *composeService) Prune(ctx context.Context, options compose.PruneOptions) ([]compose.OrphanResource, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (cs *composeService) Estimate(ctx context.Context, project *types.Project) ([]compose.CostEstimate, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (cs *composeService) Discovery(ctx context.Context, project *types.Project) ([]compose.ServiceDiscovery, error) {
+	var discovery []compose.ServiceDiscovery
+	for _, service := range project.Services {
+		discovery = append(discovery, compose.ServiceDiscovery{
+			Service:   service.Name,
+			Hostnames: []string{service.Name},
+			Address:   "127.0.0.1",
+			Mechanism: "example",
+		})
+	}
+	return discovery, nil
+}
+
+func (cs *composeService) Exposure(ctx context.Context, project *types.Project) ([]compose.PortExposure, error) {
+	var exposure []compose.PortExposure
+	for _, service := range project.Services {
+		for _, port := range service.Ports {
+			exposure = append(exposure, compose.PortExposure{
+				Service:  service.Name,
+				Port:     port.Target,
+				Protocol: compose.PortProtocol(port),
+				Source:   "example",
+			})
+		}
+	}
+	return exposure, nil
+}
+
+func (cs *composeService) Diff(ctx context.Context, project *types.Project) (compose.ProjectDiff, error) {
+	var local bytes.Buffer
+	for _, service := range project.Services {
+		fmt.Fprintf(&local, "%s: %s\n", service.Name, service.Image)
+	}
+	return compose.ProjectDiff{
+		Deployed: []byte(fmt.Sprintf("%s: nginx\n", project.Services[0].Name)),
+		Local:    local.Bytes(),
+	}, nil
+}
+
+func (cs *composeService) Import(ctx context.Context, options compose.ImportOptions) (*types.Project, error) {
+	if len(options.Resources) == 0 {
+		return nil, errors.New("no resource to import")
+	}
+	name := options.Project
+	if name == "" {
+		name = options.Resources[0]
+	}
+	project := &types.Project{Name: name}
+	for _, resource := range options.Resources {
+		project.Services = append(project.Services, types.ServiceConfig{Name: resource, Image: "nginx"})
+	}
+	return project, nil
+}
+
+func (cs *composeService) Lock(ctx context.Context, projectName string, timeout time.Duration) (func() error, error) {
+	return projectLocks(ctx).Lock(ctx, projectName, timeout)
+}
+
+func (cs *composeService) Unlock(ctx context.Context, projectName string) error {
+	return projectLocks(ctx).Unlock(projectName)
+}
+
+func (cs *composeService) Outputs(ctx context.Context, projectName string) ([]compose.StackOutput, error) {
+	return []compose.StackOutput{
+		{Name: "URL", Value: fmt.Sprintf("http://%s.example.com", projectName), Description: "example endpoint"},
+	}, nil
+}
+
+func (cs *composeService) RunJob(ctx context.Context, project *types.Project, job string) error {
+	if _, err := project.GetService(job); err != nil {
+		return err
+	}
+	fmt.Printf("Running job %q of project %q\n", job, project.Name)
+	return nil
+}
+
+func projectLocks(ctx context.Context) compose.FileLock {
+	return compose.ContextLocks(ctx, filepath.Join(config.Dir(ctx), "locks"))
+}
+
+func (cs *composeService) PortForward(ctx context.Context, projectName string, service string, localPort, remotePort uint32) error {
+	fmt.Printf("Forwarding port %d to port %d of service %q\n", localPort, remotePort, service)
+	return nil
+}
@@ -0,0 +1,39 @@
+// +build example
+
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package example
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+
+	"github.com/docker/compose-cli/backend/conformance"
+)
+
+func TestConformance(t *testing.T) {
+	scenario := Scenario{Services: map[string]ServiceScenario{
+		"web": {Logs: []string{"listening on :80"}},
+		"db":  {Logs: []string{"ready to accept connections"}},
+	}}
+	service := newSimulatedComposeService(scenario, filepath.Join(t.TempDir(), "state.json"))
+	project := &types.Project{Name: "demo", Services: []types.ServiceConfig{{Name: "web"}, {Name: "db"}}}
+	conformance.Compose(context.Background(), t, service, conformance.ComposeOptions{Project: project, Golden: "conformance.golden"})
+}
@@ -0,0 +1,121 @@
+// +build example
+
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package example
+
+import (
+	"io/ioutil"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sanathkr/go-yaml"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// Operations of the compose service for which a scenario sets latencies and failures
+const (
+	upOperation     = "up"
+	downOperation   = "down"
+	psOperation     = "ps"
+	listOperation   = "list"
+	logsOperation   = "logs"
+	runJobOperation = "job"
+)
+
+// Scenario describes how the simulated backend behaves
+type Scenario struct {
+	// Latency is the time every operation takes, unless the operation sets its own
+	Latency Duration `yaml:"latency"`
+	// Startup is the time services take to run their replicas once deployed, unless the service sets its own
+	Startup Duration `yaml:"startup"`
+	// Operations set the latency and failures of operations, by name
+	Operations map[string]Operation `yaml:"operations"`
+	// Services set the behavior of project services, by name
+	Services map[string]ServiceScenario `yaml:"services"`
+}
+
+// Operation sets the latency and failures of an operation
+type Operation struct {
+	Latency *Duration `yaml:"latency"`
+	// Error is the message of the error the operation fails with
+	Error string `yaml:"error"`
+	// Times is the number of calls failing with Error before the operation succeeds, 0 failing every call
+	Times int `yaml:"times"`
+}
+
+// ServiceScenario sets the behavior of a service
+type ServiceScenario struct {
+	Startup *Duration `yaml:"startup"`
+	// ExitCode makes the service containers exit with this code, restarting after each startup for long running
+	// services, or failing jobs with a non zero code
+	ExitCode *int `yaml:"exitCode"`
+	// Logs are the lines logged by the service once started
+	Logs []string `yaml:"logs"`
+}
+
+// Duration is a time.Duration written as a Go duration string in scenarios
+type Duration time.Duration
+
+// UnmarshalYAML parses a duration like 1s or 200ms
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	duration, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+// LoadScenario reads a scenario file
+func LoadScenario(path string) (Scenario, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Scenario{}, err
+	}
+	var scenario Scenario
+	if err := yaml.UnmarshalStrict(b, &scenario); err != nil {
+		return Scenario{}, errors.Wrapf(errdefs.ErrParsingFailed, "scenario %s: %s", path, err)
+	}
+	for name := range scenario.Operations {
+		switch name {
+		case upOperation, downOperation, psOperation, listOperation, logsOperation, runJobOperation:
+		default:
+			return Scenario{}, errors.Wrapf(errdefs.ErrParsingFailed, "scenario %s: unknown operation %q", path, name)
+		}
+	}
+	return scenario, nil
+}
+
+func (s Scenario) latency(operation string) time.Duration {
+	if op, ok := s.Operations[operation]; ok && op.Latency != nil {
+		return time.Duration(*op.Latency)
+	}
+	return time.Duration(s.Latency)
+}
+
+func (s Scenario) startup(service string) time.Duration {
+	if sc, ok := s.Services[service]; ok && sc.Startup != nil {
+		return time.Duration(*sc.Startup)
+	}
+	return time.Duration(s.Startup)
+}
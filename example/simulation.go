@@ -0,0 +1,284 @@
+// +build example
+
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package example
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/progress"
+)
+
+// simulatedComposeService deploys projects as described by a scenario, keeping the deployed projects in a state
+// file so that successive commands see them. Operations it doesn't simulate return the fixed output of the example
+// backend.
+type simulatedComposeService struct {
+	composeService
+	scenario Scenario
+	// stateFile keeps the deployed projects and the number of calls of each operation
+	stateFile string
+	now       func() time.Time
+}
+
+type simulationState struct {
+	Calls  map[string]int             `json:"calls,omitempty"`
+	Stacks map[string]*simulatedStack `json:"stacks,omitempty"`
+}
+
+type simulatedStack struct {
+	Name      string             `json:"name"`
+	Updated   bool               `json:"updated,omitempty"`
+	UpdatedAt time.Time          `json:"updatedAt"`
+	Services  []simulatedService `json:"services"`
+}
+
+type simulatedService struct {
+	Name     string `json:"name"`
+	Replicas int    `json:"replicas"`
+}
+
+func newSimulatedComposeService(scenario Scenario, stateFile string) *simulatedComposeService {
+	return &simulatedComposeService{scenario: scenario, stateFile: stateFile, now: time.Now}
+}
+
+func (s *simulatedComposeService) load() (simulationState, error) {
+	state := simulationState{Calls: map[string]int{}, Stacks: map[string]*simulatedStack{}}
+	b, err := ioutil.ReadFile(s.stateFile)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	if err := json.Unmarshal(b, &state); err != nil {
+		return state, err
+	}
+	if state.Calls == nil {
+		state.Calls = map[string]int{}
+	}
+	if state.Stacks == nil {
+		state.Stacks = map[string]*simulatedStack{}
+	}
+	return state, nil
+}
+
+func (s *simulatedComposeService) save(state simulationState) error {
+	if err := os.MkdirAll(filepath.Dir(s.stateFile), 0755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.stateFile, b, 0644)
+}
+
+// call simulates the latency of an operation and the failures the scenario injects, then applies change to the
+// state. The state is saved even if the operation fails, as its calls are counted.
+func (s *simulatedComposeService) call(ctx context.Context, operation string, change func(state *simulationState) error) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(s.scenario.latency(operation)):
+	}
+	state, err := s.load()
+	if err != nil {
+		return err
+	}
+	state.Calls[operation]++
+	err = nil
+	if op, ok := s.scenario.Operations[operation]; ok && op.Error != "" && (op.Times == 0 || state.Calls[operation] <= op.Times) {
+		err = errors.New(op.Error)
+	} else if change != nil {
+		err = change(&state)
+	}
+	if saveErr := s.save(state); saveErr != nil {
+		return saveErr
+	}
+	return err
+}
+
+func (s *simulatedComposeService) Up(ctx context.Context, project *types.Project, options compose.UpOptions) error {
+	w := progress.ContextWriter(ctx)
+	err := s.call(ctx, upOperation, func(state *simulationState) error {
+		stack := &simulatedStack{Name: project.Name, UpdatedAt: s.now()}
+		if _, ok := state.Stacks[project.Name]; ok {
+			stack.Updated = true
+		}
+		for _, service := range project.Services {
+			if compose.IsJob(service) || compose.IsScheduled(service) {
+				continue
+			}
+			replicas := 1
+			if service.Deploy != nil && service.Deploy.Replicas != nil {
+				replicas = int(*service.Deploy.Replicas)
+			}
+			stack.Services = append(stack.Services, simulatedService{Name: service.Name, Replicas: replicas})
+			w.Event(progress.Event{ID: service.Name, Status: progress.Done, StatusText: "Deployed"})
+		}
+		state.Stacks[project.Name] = stack
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return compose.RunJobs(ctx, project, s.runJob)
+}
+
+func (s *simulatedComposeService) RunJob(ctx context.Context, project *types.Project, name string) error {
+	job, err := project.GetService(name)
+	if err != nil {
+		return err
+	}
+	if !compose.IsJob(job) {
+		return errors.Wrapf(errdefs.ErrNotFound, "service %s is not a job", name)
+	}
+	if err := s.call(ctx, runJobOperation, nil); err != nil {
+		return err
+	}
+	return s.runJob(ctx, job)
+}
+
+// runJob simulates a job running for its startup time, and exiting with its exit code
+func (s *simulatedComposeService) runJob(ctx context.Context, job types.ServiceConfig) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(s.scenario.startup(job.Name)):
+	}
+	if sc, ok := s.scenario.Services[job.Name]; ok && sc.ExitCode != nil && *sc.ExitCode != 0 {
+		return compose.JobFailedError{Job: job.Name, ExitCode: *sc.ExitCode}
+	}
+	return nil
+}
+
+func (s *simulatedComposeService) Down(ctx context.Context, projectName string, options compose.DownOptions) error {
+	return s.call(ctx, downOperation, func(state *simulationState) error {
+		if _, ok := state.Stacks[projectName]; !ok {
+			return errors.Wrapf(errdefs.ErrNotFound, "project %q", projectName)
+		}
+		delete(state.Stacks, projectName)
+		return nil
+	})
+}
+
+// Ps reports services as starting until their startup time elapsed since the deployment. Services with an exit code
+// never run, and are restarted after each startup.
+func (s *simulatedComposeService) Ps(ctx context.Context, projectName string) ([]compose.ServiceStatus, error) {
+	var status []compose.ServiceStatus
+	err := s.call(ctx, psOperation, func(state *simulationState) error {
+		stack, ok := state.Stacks[projectName]
+		if !ok {
+			return errors.Wrapf(errdefs.ErrNotFound, "project %q", projectName)
+		}
+		elapsed := s.now().Sub(stack.UpdatedAt)
+		for _, service := range stack.Services {
+			startup := s.scenario.startup(service.Name)
+			current := compose.ServiceStatus{
+				ID:      fmt.Sprintf("%s-%s", projectName, service.Name),
+				Name:    service.Name,
+				Desired: service.Replicas,
+			}
+			sc := s.scenario.Services[service.Name]
+			switch {
+			case sc.ExitCode != nil:
+				exitCode := *sc.ExitCode
+				current.ExitCode = &exitCode
+				if startup < time.Second {
+					startup = time.Second
+				}
+				current.Restarts = int(elapsed / startup)
+			case elapsed >= startup:
+				current.Replicas = service.Replicas
+			}
+			status = append(status, current)
+		}
+		return nil
+	})
+	return status, err
+}
+
+func (s *simulatedComposeService) List(ctx context.Context, projectName string) ([]compose.Stack, error) {
+	var stacks []compose.Stack
+	err := s.call(ctx, listOperation, func(state *simulationState) error {
+		for _, stack := range state.Stacks {
+			if projectName != "" && stack.Name != projectName {
+				continue
+			}
+			stacks = append(stacks, compose.Stack{ID: stack.Name, Name: stack.Name, Status: s.stackStatus(*stack)})
+		}
+		return nil
+	})
+	return stacks, err
+}
+
+func (s *simulatedComposeService) stackStatus(stack simulatedStack) string {
+	elapsed := s.now().Sub(stack.UpdatedAt)
+	for _, service := range stack.Services {
+		if elapsed < s.scenario.startup(service.Name) {
+			if stack.Updated {
+				return compose.UPDATING
+			}
+			return compose.STARTING
+		}
+	}
+	return compose.RUNNING
+}
+
+// Logs returns the lines the scenario sets for the services, logged once they started
+func (s *simulatedComposeService) Logs(ctx context.Context, projectName string, consumer compose.LogConsumer, options compose.LogOptions) error {
+	var events []compose.LogEvent
+	err := s.call(ctx, logsOperation, func(state *simulationState) error {
+		stack, ok := state.Stacks[projectName]
+		if !ok {
+			return errors.Wrapf(errdefs.ErrNotFound, "project %q", projectName)
+		}
+		for _, service := range stack.Services {
+			started := stack.UpdatedAt.Add(s.scenario.startup(service.Name))
+			for i, line := range s.scenario.Services[service.Name].Logs {
+				timestamp := started.Add(time.Duration(i) * time.Millisecond)
+				if timestamp.After(s.now()) || (!options.Since.IsZero() && timestamp.Before(options.Since)) ||
+					(!options.Until.IsZero() && timestamp.After(options.Until)) {
+					continue
+				}
+				events = append(events, compose.LogEvent{
+					Service:   service.Name,
+					Container: fmt.Sprintf("%s-%s", projectName, service.Name),
+					Timestamp: timestamp,
+					Line:      line,
+				})
+			}
+		}
+		return nil
+	})
+	for _, event := range events {
+		consumer.Log(event)
+	}
+	return err
+}
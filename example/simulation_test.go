@@ -0,0 +1,114 @@
+// +build example
+
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package example
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+const testScenario = `
+startup: 10s
+operations:
+  up:
+    error: quota exceeded
+    times: 1
+services:
+  web:
+    logs: ["starting", "listening"]
+  worker:
+    startup: 2s
+    exitCode: 137
+  migrate:
+    startup: 1ms
+    exitCode: 3
+`
+
+func TestSimulatedComposeService(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "scenario.yaml")
+	assert.NilError(t, ioutil.WriteFile(path, []byte(testScenario), 0644))
+	scenario, err := LoadScenario(path)
+	assert.NilError(t, err)
+	assert.Equal(t, scenario.startup("web"), 10*time.Second)
+	assert.Equal(t, scenario.startup("worker"), 2*time.Second)
+
+	now := time.Date(2020, 9, 1, 12, 0, 0, 0, time.UTC)
+	s := newSimulatedComposeService(scenario, filepath.Join(dir, "state.json"))
+	s.now = func() time.Time { return now }
+	project := &types.Project{Name: "demo", Services: []types.ServiceConfig{{Name: "web"}, {Name: "worker"}}}
+	ctx := context.Background()
+
+	// the first call fails, as injected by the scenario
+	assert.Error(t, s.Up(ctx, project, compose.UpOptions{}), "quota exceeded")
+	assert.NilError(t, s.Up(ctx, project, compose.UpOptions{}))
+	stacks, err := s.List(ctx, "demo")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, stacks, []compose.Stack{{ID: "demo", Name: "demo", Status: compose.STARTING}})
+
+	now = now.Add(11 * time.Second)
+	status, err := s.Ps(ctx, "demo")
+	assert.NilError(t, err)
+	exitCode := 137
+	assert.DeepEqual(t, status, []compose.ServiceStatus{
+		{ID: "demo-web", Name: "web", Replicas: 1, Desired: 1},
+		{ID: "demo-worker", Name: "worker", Desired: 1, Restarts: 5, ExitCode: &exitCode},
+	})
+
+	var lines []string
+	err = s.Logs(ctx, "demo", logConsumer(func(event compose.LogEvent) { lines = append(lines, event.Line) }), compose.LogOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, lines, []string{"starting", "listening"})
+
+	// another service instance sees the state saved by the previous one
+	restarted := newSimulatedComposeService(scenario, filepath.Join(dir, "state.json"))
+	assert.NilError(t, restarted.Down(ctx, "demo", compose.DownOptions{}))
+	_, err = s.Ps(ctx, "demo")
+	assert.ErrorContains(t, err, "not found")
+
+	jobs := &types.Project{Name: "jobs", Services: []types.ServiceConfig{{Name: "migrate", Extensions: map[string]interface{}{compose.JobExtension: true}}}}
+	err = s.RunJob(ctx, jobs, "migrate")
+	assert.DeepEqual(t, err, compose.JobFailedError{Job: "migrate", ExitCode: 3})
+}
+
+func TestLoadScenarioErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "scenario.yaml")
+	assert.NilError(t, ioutil.WriteFile(path, []byte("operations:\n  deploy:\n    error: failed\n"), 0644))
+	_, err := LoadScenario(path)
+	assert.ErrorContains(t, err, `unknown operation "deploy"`)
+
+	assert.NilError(t, ioutil.WriteFile(path, []byte("latency: fast\n"), 0644))
+	_, err = LoadScenario(path)
+	assert.ErrorContains(t, err, "invalid duration")
+}
+
+type logConsumer func(event compose.LogEvent)
+
+func (f logConsumer) Log(event compose.LogEvent) {
+	f(event)
+}
@@ -0,0 +1,97 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package formatter
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/utils"
+)
+
+// LogFilter selects the log lines to be consumed
+type LogFilter struct {
+	// Services restricts logs to these services, all services being logged if empty
+	Services []string
+	// Pattern only keeps the lines matching this expression
+	Pattern *regexp.Regexp
+	// Level only keeps the JSON lines with a level field of at least this level. Lines without a level are kept.
+	Level string
+}
+
+var levels = map[string]int{
+	"trace":    0,
+	"debug":    1,
+	"info":     2,
+	"warn":     3,
+	"warning":  3,
+	"error":    4,
+	"fatal":    5,
+	"critical": 5,
+	"panic":    6,
+}
+
+// IsLogLevel checks a level is known by log filters
+func IsLogLevel(level string) bool {
+	_, ok := levels[strings.ToLower(level)]
+	return ok
+}
+
+// FilterLogs returns a consumer passing the log lines selected by the filter to the given consumer
+func FilterLogs(consumer compose.LogConsumer, filter LogFilter) compose.LogConsumer {
+	return filteredLogConsumer{consumer: consumer, filter: filter}
+}
+
+type filteredLogConsumer struct {
+	consumer compose.LogConsumer
+	filter   LogFilter
+}
+
+func (f filteredLogConsumer) Log(event compose.LogEvent) {
+	if f.filter.matches(event) {
+		f.consumer.Log(event)
+	}
+}
+
+func (f LogFilter) matches(event compose.LogEvent) bool {
+	if len(f.Services) > 0 && !utils.StringContains(f.Services, event.Service) {
+		return false
+	}
+	if f.Pattern != nil && !f.Pattern.MatchString(event.Line) {
+		return false
+	}
+	if f.Level != "" {
+		if level, ok := lineLevel(event.Line); ok && level < levels[strings.ToLower(f.Level)] {
+			return false
+		}
+	}
+	return true
+}
+
+// lineLevel returns the level of a JSON log line
+func lineLevel(line string) (int, bool) {
+	var record struct {
+		Level string `json:"level"`
+	}
+	if err := json.Unmarshal([]byte(line), &record); err != nil {
+		return 0, false
+	}
+	level, ok := levels[strings.ToLower(record.Level)]
+	return level, ok
+}
@@ -0,0 +1,47 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package formatter
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestFilterLogs(t *testing.T) {
+	b := bytes.Buffer{}
+	consumer := FilterLogs(NewLogConsumer(&b, false), LogFilter{
+		Services: []string{"web"},
+		Pattern:  regexp.MustCompile("GET|level"),
+		Level:    "warn",
+	})
+	consumer.Log(compose.LogEvent{Service: "db", Line: "GET ignored"})
+	consumer.Log(compose.LogEvent{Service: "web", Line: "POST /"})
+	consumer.Log(compose.LogEvent{Service: "web", Line: "GET /"})
+	consumer.Log(compose.LogEvent{Service: "web", Line: `{"level":"info","msg":"ok"}`})
+	consumer.Log(compose.LogEvent{Service: "web", Line: `{"level":"ERROR","msg":"failed"}`})
+	assert.Equal(t, b.String(), "web    | GET /\nweb    | {\"level\":\"ERROR\",\"msg\":\"failed\"}\n")
+}
+
+func TestIsLogLevel(t *testing.T) {
+	assert.Assert(t, IsLogLevel("Warning"))
+	assert.Assert(t, !IsLogLevel("verbose"))
+}
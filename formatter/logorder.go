@@ -0,0 +1,137 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package formatter
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+// LogOrderingDelay is long enough for lines from the different sources of a polling backend to be sorted together
+const LogOrderingDelay = time.Second
+
+// OrderedLogConsumer merges log lines from several sources, such as services running in different regions, into a
+// single ordering by timestamp. Lines are held for a delay so that late ones can be sorted before them.
+type OrderedLogConsumer struct {
+	consumer compose.LogConsumer
+	delay    time.Duration
+	now      func() time.Time
+
+	m       sync.Mutex
+	pending logHeap
+	last    time.Time
+	done    chan struct{}
+	closed  sync.WaitGroup
+}
+
+// NewOrderedLogConsumer returns a consumer passing log lines to the given consumer in timestamp order, once they've
+// been held for delay. Close flushes the lines still held.
+func NewOrderedLogConsumer(consumer compose.LogConsumer, delay time.Duration) *OrderedLogConsumer {
+	o := &OrderedLogConsumer{
+		consumer: consumer,
+		delay:    delay,
+		now:      time.Now,
+		done:     make(chan struct{}),
+	}
+	o.closed.Add(1)
+	go o.run()
+	return o
+}
+
+// Log holds a log line until it can be passed in order. A line older than one already passed can't be reordered
+// anymore, it is passed right away.
+func (o *OrderedLogConsumer) Log(event compose.LogEvent) {
+	o.m.Lock()
+	defer o.m.Unlock()
+	if event.Timestamp.Before(o.last) {
+		o.consumer.Log(event)
+		return
+	}
+	heap.Push(&o.pending, heldLog{event: event, received: o.now()})
+}
+
+// Close passes the log lines still held, in order
+func (o *OrderedLogConsumer) Close() {
+	close(o.done)
+	o.closed.Wait()
+	o.flush(true)
+}
+
+func (o *OrderedLogConsumer) run() {
+	defer o.closed.Done()
+	interval := o.delay / 4
+	if interval <= 0 {
+		interval = time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-o.done:
+			return
+		case <-ticker.C:
+			o.flush(false)
+		}
+	}
+}
+
+// flush passes the oldest held lines as long as they've been held long enough, or all of them
+func (o *OrderedLogConsumer) flush(all bool) {
+	o.m.Lock()
+	defer o.m.Unlock()
+	now := o.now()
+	for o.pending.Len() > 0 {
+		if !all && now.Sub(o.pending[0].received) < o.delay {
+			return
+		}
+		held := heap.Pop(&o.pending).(heldLog)
+		o.last = held.event.Timestamp
+		o.consumer.Log(held.event)
+	}
+}
+
+type heldLog struct {
+	event    compose.LogEvent
+	received time.Time
+}
+
+// logHeap orders held log lines by timestamp, then by arrival for lines with the same timestamp
+type logHeap []heldLog
+
+func (h logHeap) Len() int { return len(h) }
+
+func (h logHeap) Less(i, j int) bool {
+	if h[i].event.Timestamp.Equal(h[j].event.Timestamp) {
+		return h[i].received.Before(h[j].received)
+	}
+	return h[i].event.Timestamp.Before(h[j].event.Timestamp)
+}
+
+func (h logHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *logHeap) Push(x interface{}) { *h = append(*h, x.(heldLog)) }
+
+func (h *logHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
@@ -0,0 +1,66 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package formatter
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+type recordingConsumer struct {
+	lines []string
+}
+
+func (r *recordingConsumer) Log(event compose.LogEvent) {
+	r.lines = append(r.lines, event.Line)
+}
+
+func TestOrderedLogConsumer(t *testing.T) {
+	recorder := &recordingConsumer{}
+	consumer := NewOrderedLogConsumer(recorder, time.Hour)
+	base := time.Date(2020, 11, 2, 3, 0, 0, 0, time.UTC)
+	// a service logging from another region, with its own timezone
+	paris := time.FixedZone("CET", 3600)
+	consumer.Log(compose.LogEvent{Service: "web", Timestamp: base.Add(2 * time.Second), Line: "third"})
+	consumer.Log(compose.LogEvent{Service: "api", Timestamp: base.Add(time.Second).In(paris), Line: "second"})
+	consumer.Log(compose.LogEvent{Service: "web", Timestamp: base, Line: "first"})
+	assert.Equal(t, len(recorder.lines), 0)
+
+	consumer.Close()
+	assert.DeepEqual(t, recorder.lines, []string{"first", "second", "third"})
+}
+
+func TestOrderedLogConsumerDelay(t *testing.T) {
+	recorder := &recordingConsumer{}
+	now := time.Date(2020, 11, 2, 3, 0, 0, 0, time.UTC)
+	consumer := &OrderedLogConsumer{consumer: recorder, delay: time.Second, now: func() time.Time { return now }}
+	consumer.Log(compose.LogEvent{Timestamp: now, Line: "held"})
+	consumer.flush(false)
+	assert.Equal(t, len(recorder.lines), 0)
+
+	now = now.Add(time.Second)
+	consumer.flush(false)
+	assert.DeepEqual(t, recorder.lines, []string{"held"})
+
+	// too late to be sorted before the line already passed
+	consumer.Log(compose.LogEvent{Timestamp: now.Add(-time.Minute), Line: "late"})
+	assert.DeepEqual(t, recorder.lines, []string{"held", "late"})
+}
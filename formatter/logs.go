@@ -0,0 +1,115 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/output"
+)
+
+// NewLogConsumer returns a consumer writing log lines prefixed by their service name, colored by service with the
+// current theme if color is set
+func NewLogConsumer(w io.Writer, color bool) compose.LogConsumer {
+	l := &logConsumer{
+		colors: map[string]output.Color{},
+		writer: w,
+	}
+	if color {
+		l.prefixes = output.CurrentTheme().Prefixes
+	}
+	return l
+}
+
+// NewJSONLogConsumer returns a consumer writing each log line as a JSON record
+func NewJSONLogConsumer(w io.Writer) compose.LogConsumer {
+	return jsonLogConsumer{encoder: json.NewEncoder(w)}
+}
+
+// WithTimestamps prefixes log lines written by a consumer created by NewLogConsumer with their timestamp in location,
+// and converts the timestamps of the JSON records written by a consumer created by NewJSONLogConsumer to location
+func WithTimestamps(consumer compose.LogConsumer, location *time.Location) compose.LogConsumer {
+	switch c := consumer.(type) {
+	case *logConsumer:
+		c.location = location
+	case jsonLogConsumer:
+		c.location = location
+		return c
+	}
+	return consumer
+}
+
+type logConsumer struct {
+	colors map[string]output.Color
+	// prefixes are the colors given in turn to services, none when empty
+	prefixes []output.Color
+	width    int
+	writer   io.Writer
+	// location displays timestamps when set
+	location *time.Location
+}
+
+func (l *logConsumer) Log(event compose.LogEvent) {
+	color, ok := l.colors[event.Service]
+	if !ok {
+		if len(l.prefixes) > 0 {
+			color = l.prefixes[len(l.colors)%len(l.prefixes)]
+		}
+		l.colors[event.Service] = color
+		l.computeWidth()
+	}
+	prefix := fmt.Sprintf("%-"+strconv.Itoa(l.width)+"s |", event.Service)
+	timestamp := ""
+	if l.location != nil {
+		timestamp = event.Timestamp.In(l.location).Format(TimestampFormat) + " "
+	}
+
+	for _, line := range strings.Split(event.Line, "\n") {
+		fmt.Fprintf(l.writer, "%s %s%s\n", color.Apply(prefix), timestamp, line) // nolint:errcheck
+	}
+}
+
+func (l *logConsumer) computeWidth() {
+	width := 0
+	for n := range l.colors {
+		if len(n) > width {
+			width = len(n)
+		}
+	}
+	l.width = width + 3
+}
+
+// TimestampFormat is the layout of log timestamps, with a fixed width so that lines align
+const TimestampFormat = "2006-01-02T15:04:05.000000000Z07:00"
+
+type jsonLogConsumer struct {
+	encoder  *json.Encoder
+	location *time.Location
+}
+
+func (j jsonLogConsumer) Log(event compose.LogEvent) {
+	if j.location != nil {
+		event.Timestamp = event.Timestamp.In(j.location)
+	}
+	j.encoder.Encode(event) // nolint:errcheck
+}
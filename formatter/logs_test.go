@@ -0,0 +1,68 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package formatter
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/output"
+)
+
+func TestLogConsumer(t *testing.T) {
+	b := bytes.Buffer{}
+	consumer := NewLogConsumer(&b, false)
+	consumer.Log(compose.LogEvent{Service: "web", Container: "web_1", Line: "listening\nready"})
+	consumer.Log(compose.LogEvent{Service: "db", Container: "db_1", Line: "started"})
+	assert.Equal(t, b.String(), "web    | listening\nweb    | ready\ndb     | started\n")
+}
+
+func TestLogConsumerColors(t *testing.T) {
+	defer os.Unsetenv(output.ThemeEnvVar)  // nolint:errcheck
+	os.Setenv(output.ThemeEnvVar, "light") // nolint:errcheck
+	b := bytes.Buffer{}
+	consumer := NewLogConsumer(&b, true)
+	consumer.Log(compose.LogEvent{Service: "web", Line: "ready"})
+	consumer.Log(compose.LogEvent{Service: "db", Line: "started"})
+	consumer.Log(compose.LogEvent{Service: "web", Line: "GET /"})
+	assert.Equal(t, b.String(), "\x1b[34mweb    |\x1b[0m ready\n\x1b[35mdb     |\x1b[0m started\n\x1b[34mweb    |\x1b[0m GET /\n")
+}
+
+func TestLogConsumerTimestamps(t *testing.T) {
+	b := bytes.Buffer{}
+	consumer := WithTimestamps(NewLogConsumer(&b, false), time.UTC)
+	consumer.Log(compose.LogEvent{Service: "web", Timestamp: time.Date(2020, 11, 2, 4, 0, 0, 0, time.FixedZone("CET", 3600)), Line: "ready"})
+	assert.Equal(t, b.String(), "web    | 2020-11-02T03:00:00.000000000Z ready\n")
+}
+
+func TestJSONLogConsumer(t *testing.T) {
+	b := bytes.Buffer{}
+	consumer := NewJSONLogConsumer(&b)
+	consumer.Log(compose.LogEvent{
+		Service:   "web",
+		Container: "web_1",
+		Stream:    "stdout",
+		Timestamp: time.Date(2020, 11, 2, 3, 0, 0, 0, time.UTC),
+		Line:      "ready",
+	})
+	assert.Equal(t, b.String(), `{"service":"web","container":"web_1","stream":"stdout","timestamp":"2020-11-02T03:00:00Z","line":"ready"}`+"\n")
+}
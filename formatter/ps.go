@@ -0,0 +1,74 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+// PrintServiceStatus writes the status of the project services as a table, the way compose ps prints it.
+// Regions and restarts are only shown by backends reporting them.
+func PrintServiceStatus(out io.Writer, services []compose.ServiceStatus) error {
+	regions, restarts := hasRegions(services), hasRestarts(services)
+	headers := []string{"ID", "NAME"}
+	if regions {
+		headers = append(headers, "REGION")
+	}
+	headers = append(headers, "REPLICAS")
+	if restarts {
+		headers = append(headers, "RESTARTS")
+	}
+	headers = append(headers, "PORTS")
+	w := tabwriter.NewWriter(out, 20, 1, 3, ' ', 0)
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+	for _, service := range services {
+		columns := []string{service.ID, service.Name}
+		if regions {
+			columns = append(columns, service.Region)
+		}
+		columns = append(columns, fmt.Sprintf("%d/%d", service.Replicas, service.Desired))
+		if restarts {
+			columns = append(columns, fmt.Sprint(service.Restarts))
+		}
+		columns = append(columns, strings.Join(service.Ports, ", "))
+		fmt.Fprintln(w, strings.Join(columns, "\t"))
+	}
+	return w.Flush()
+}
+
+func hasRegions(services []compose.ServiceStatus) bool {
+	for _, service := range services {
+		if service.Region != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRestarts(services []compose.ServiceStatus) bool {
+	for _, service := range services {
+		if service.Restarts > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package formatter
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestPrintServiceStatus(t *testing.T) {
+	b := bytes.Buffer{}
+	err := PrintServiceStatus(&b, []compose.ServiceStatus{
+		{ID: "demo-web", Name: "web", Replicas: 1, Desired: 2, Ports: []string{"0.0.0.0:80->80/tcp"}},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, b.String(), "ID                  NAME                REPLICAS            PORTS\n"+
+		"demo-web            web                 1/2                 0.0.0.0:80->80/tcp\n")
+
+	b.Reset()
+	err = PrintServiceStatus(&b, []compose.ServiceStatus{{ID: "demo-web", Name: "web", Region: "eu-west-1", Replicas: 1, Desired: 1, Restarts: 2}})
+	assert.NilError(t, err)
+	assert.Equal(t, b.String(), "ID                  NAME                REGION              REPLICAS            RESTARTS            PORTS\n"+
+		"demo-web            web                 eu-west-1           1/1                 2                   \n")
+}
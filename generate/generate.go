@@ -0,0 +1,157 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package generate
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// Language is a programming language the scaffolding can generate a Dockerfile for
+type Language string
+
+const (
+	// Go projects are detected from a go.mod file
+	Go = Language("go")
+	// Node projects are detected from a package.json file
+	Node = Language("node")
+	// Python projects are detected from a requirements.txt, pyproject.toml or setup.py file
+	Python = Language("python")
+)
+
+const (
+	// DockerfileName is the name of the generated Dockerfile
+	DockerfileName = "Dockerfile"
+	// ComposeFileName is the name of the generated compose file
+	ComposeFileName = "compose.yaml"
+)
+
+// Languages lists the supported languages, in detection order
+var Languages = []Language{Go, Node, Python}
+
+var markers = map[Language][]string{
+	Go:     {"go.mod"},
+	Node:   {"package.json"},
+	Python: {"requirements.txt", "pyproject.toml", "setup.py"},
+}
+
+var defaultPorts = map[Language]int{
+	Go:     8080,
+	Node:   3000,
+	Python: 8000,
+}
+
+// Options configure the generated files
+type Options struct {
+	Language Language
+	// Service is the name of the compose service running the application
+	Service string
+	// Port the application listens on, defaulting to the usual port for the language
+	Port int
+	// CPUs and Memory set the deploy resources limits of the service, when not empty
+	CPUs   string
+	Memory string
+}
+
+// Detect returns the language of the project in the directory, based on the files used by language tooling
+func Detect(dir string) (Language, error) {
+	for _, language := range Languages {
+		for _, marker := range markers[language] {
+			if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+				return language, nil
+			}
+		}
+	}
+	return "", errors.Wrapf(errdefs.ErrNotFound, "no Go, Node or Python project in %s", dir)
+}
+
+// ParseLanguage validates a language name
+func ParseLanguage(name string) (Language, error) {
+	for _, language := range Languages {
+		if string(language) == name {
+			return language, nil
+		}
+	}
+	return "", errors.Wrapf(errdefs.ErrParsingFailed, "unsupported language %q", name)
+}
+
+// DefaultPort returns the port applications usually listen on for the language
+func DefaultPort(language Language) int {
+	return defaultPorts[language]
+}
+
+// Dockerfile renders a Dockerfile building and running the application
+func Dockerfile(opts Options) ([]byte, error) {
+	return render(dockerfiles[opts.Language], opts)
+}
+
+// ComposeFile renders a compose file running the application as a single service, checking its health over HTTP
+func ComposeFile(opts Options) ([]byte, error) {
+	return render(composeFile, opts)
+}
+
+// Write generates the Dockerfile and compose file in the directory, and returns their paths. Existing files are only
+// replaced when overwrite is set.
+func Write(dir string, opts Options, overwrite bool) ([]string, error) {
+	if opts.Port == 0 {
+		opts.Port = DefaultPort(opts.Language)
+	}
+	dockerfile, err := Dockerfile(opts)
+	if err != nil {
+		return nil, err
+	}
+	compose, err := ComposeFile(opts)
+	if err != nil {
+		return nil, err
+	}
+	paths := []string{filepath.Join(dir, DockerfileName), filepath.Join(dir, ComposeFileName)}
+	if !overwrite {
+		for _, path := range paths {
+			if _, err := os.Stat(path); err == nil {
+				return nil, errors.Wrapf(errdefs.ErrAlreadyExists, "%s", path)
+			}
+		}
+	}
+	for i, content := range [][]byte{dockerfile, compose} {
+		if err := ioutil.WriteFile(paths[i], content, 0644); err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
+func render(text string, opts Options) ([]byte, error) {
+	if text == "" {
+		return nil, errors.Wrapf(errdefs.ErrParsingFailed, "unsupported language %q", opts.Language)
+	}
+	tmpl, err := template.New("scaffold").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, opts); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,66 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package generate
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/cli"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestDetect(t *testing.T) {
+	dir := fs.NewDir(t, "project", fs.WithFile("requirements.txt", "flask\n"))
+	defer dir.Remove()
+	language, err := Detect(dir.Path())
+	assert.NilError(t, err)
+	assert.Equal(t, language, Python)
+
+	empty := fs.NewDir(t, "empty")
+	defer empty.Remove()
+	_, err = Detect(empty.Path())
+	assert.Assert(t, errdefs.IsNotFoundError(err))
+}
+
+func TestWrite(t *testing.T) {
+	dir := fs.NewDir(t, "project", fs.WithFile("go.mod", "module example.com/app\n"))
+	defer dir.Remove()
+	paths, err := Write(dir.Path(), Options{Language: Go, Service: "app", CPUs: "0.5", Memory: "512M"}, false)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, paths, []string{filepath.Join(dir.Path(), "Dockerfile"), filepath.Join(dir.Path(), "compose.yaml")})
+
+	dockerfile, err := ioutil.ReadFile(paths[0])
+	assert.NilError(t, err)
+	assert.Assert(t, is.Contains(string(dockerfile), "EXPOSE 8080"))
+
+	project, err := cli.ProjectFromOptions(&cli.ProjectOptions{Name: "test", ConfigPaths: []string{paths[1]}})
+	assert.NilError(t, err)
+	service := project.Services[0]
+	assert.Equal(t, service.Name, "app")
+	assert.Equal(t, service.Ports[0].Target, uint32(8080))
+	assert.DeepEqual(t, []string(service.HealthCheck.Test), []string{"CMD", "wget", "-q", "--spider", "http://localhost:8080/"})
+	assert.Equal(t, service.Deploy.Resources.Limits.NanoCPUs, "0.5")
+
+	_, err = Write(dir.Path(), Options{Language: Go, Service: "app"}, false)
+	assert.Assert(t, errdefs.IsAlreadyExistsError(err))
+}
@@ -0,0 +1,84 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package generate
+
+var dockerfiles = map[Language]string{
+	Go: `# syntax=docker/dockerfile:1
+FROM golang:1.15-alpine AS build
+WORKDIR /src
+COPY go.* ./
+RUN go mod download
+COPY . .
+RUN CGO_ENABLED=0 go build -o /out/app .
+
+FROM alpine:3.12
+COPY --from=build /out/app /usr/local/bin/app
+USER nobody
+EXPOSE {{.Port}}
+ENTRYPOINT ["/usr/local/bin/app"]
+`,
+	Node: `# syntax=docker/dockerfile:1
+FROM node:14-alpine
+ENV NODE_ENV=production
+WORKDIR /app
+COPY package*.json ./
+RUN npm ci --only=production
+COPY . .
+USER node
+EXPOSE {{.Port}}
+CMD ["npm", "start"]
+`,
+	Python: `# syntax=docker/dockerfile:1
+FROM python:3.9-slim
+ENV PYTHONDONTWRITEBYTECODE=1 PYTHONUNBUFFERED=1
+WORKDIR /app
+COPY . .
+RUN if [ -f requirements.txt ]; then pip install --no-cache-dir -r requirements.txt; else pip install --no-cache-dir .; fi
+USER nobody
+EXPOSE {{.Port}}
+CMD ["python", "app.py"]
+`,
+}
+
+// composeFile checks health with tools available in the base images: busybox wget on alpine, python on slim images
+var composeFile = `services:
+  {{.Service}}:
+    build: .
+    ports:
+      - "{{.Port}}:{{.Port}}"
+    healthcheck:
+{{- if eq .Language "python"}}
+      test: ["CMD", "python", "-c", "import urllib.request; urllib.request.urlopen('http://localhost:{{.Port}}/')"]
+{{- else}}
+      test: ["CMD", "wget", "-q", "--spider", "http://localhost:{{.Port}}/"]
+{{- end}}
+      interval: 30s
+      timeout: 5s
+      retries: 3
+      start_period: 10s
+{{- if or .CPUs .Memory}}
+    deploy:
+      resources:
+        limits:
+{{- if .CPUs}}
+          cpus: "{{.CPUs}}"
+{{- end}}
+{{- if .Memory}}
+          memory: {{.Memory}}
+{{- end}}
+{{- end}}
+`
@@ -0,0 +1,117 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package i18n
+
+func init() {
+	Register(DefaultLanguage, map[string]string{
+		// shared by commands
+		"backend.connect":      "cannot connect to backend",
+		"container.not-found":  "container %s not found",
+		"options.quiet-json":   `cannot combine "quiet" and "json" options`,
+		"console.close-failed": "Unable to close the console",
+
+		// progress
+		"progress.running": "[+] Running %d/%d",
+		"progress.eta":     " (about %s left)",
+		"progress.pending": ", pending: %s",
+
+		// login and logout
+		"login.unknown-backend":     "unknown backend type for cloud login: %s",
+		"login.canceled":            "login canceled",
+		"login.succeeded":           "login succeeded",
+		"login.client-secret-stdin": "use --client-secret-stdin",
+		"logout.canceled":           "logout canceled",
+		"logout.azure":              "Removing login credentials for Azure",
+
+		// containers
+		"ps.fetch":                "fetch containers",
+		"rm.running":              "you cannot remove a running container %s. Stop the container before attempting removal or force remove",
+		"run.command-interactive": "a command can only be specified with --interactive",
+		"run.command-arguments":   "the command of an interactive session doesn't accept arguments, only the binary can be specified",
+		"portforward.container":   "Forwarding 127.0.0.1:%d to port %d of %s, press Ctrl-C to stop",
+		"image.filter":            "filter %q, expected dangling=true or dangling=false",
+
+		// secrets
+		"secret.password-exclusive": "--password and --password-stdin are mutually exclusive",
+		"secret.password-insecure":  "WARNING! Using --password via the CLI is insecure. Use --password-stdin.",
+		"secret.password-stdin":     "use --password-stdin",
+
+		// compose
+		"compose.project":            "project %q",
+		"compose.config-not-found":   "can't find a suitable configuration file in this directory or any parent",
+		"compose.up.recreate-flags":  "--force-recreate and --no-recreate are incompatible",
+		"compose.up.abort-flags":     "--abort-on-container-exit is incompatible with --wait and --smoke-test",
+		"compose.up.exit-code-from":  "--exit-code-from requires a service or a job run on deployment, %s only runs on its schedule or from a hook",
+		"compose.up.confirm":         "Deploy the application?",
+		"compose.up.verify-oci":      "--verify requires a single compose file published as an OCI artifact, with -f oci://REPOSITORY[:TAG]",
+		"compose.up.verify-keyless":  "--verify without --verify-key requires --verify-identity and --verify-issuer, the signer trusted for keyless signatures",
+		"compose.down.rmi":           "invalid value %q for --rmi, must be %q or %q",
+		"compose.hooks.no-rollback":  "the application was deployed before this update and can't be rolled back, it is left as it is",
+		"compose.hooks.down-failed":  "failed to remove the application (%s)",
+		"compose.hooks.removed":      "the application was removed",
+		"compose.logs.format":        "format value %q could not be parsed",
+		"compose.logs.since-until":   "--since %s must be before --until %s",
+		"compose.logs.time":          "time %q, expected a timestamp such as 2020-10-20T13:23:37Z or a duration such as 42m",
+		"compose.logs.utc-local":     "--utc and --local are incompatible",
+		"compose.logs.timestamps":    "--utc and --local require --timestamps",
+		"compose.logs.filter":        "filter %q, expected service=NAME",
+		"compose.logs.grep":          "grep expression %q: %s",
+		"compose.logs.level":         "unknown log level %q",
+		"compose.prune.none":         "No orphan resources found",
+		"compose.prune.confirm":      "Delete these %d resources?",
+		"compose.diff.none":          "No difference between the deployed application and the compose file",
+		"compose.diff.drift":         "Resources modified outside of the CLI:",
+		"compose.generate.done":      "Generated %s",
+		"compose.generate.language":  "Project language",
+		"compose.generate.service":   "Service name",
+		"compose.generate.port":      "Port the application listens on",
+		"compose.generate.cpus":      "CPU limit",
+		"compose.generate.memory":    "Memory limit",
+		"compose.unlock.done":        "Project %q unlocked",
+		"compose.scan.report-format": "scan report format %q, expected json",
+		"compose.portforward":        "Forwarding 127.0.0.1:%d to port %d of service %s, press Ctrl-C to stop",
+		"compose.import.done":        "Imported %d service(s) in project %q, run `docker compose up -p %s -f %s` to manage them",
+		"compose.ui.terminal":        "the dashboard requires a terminal",
+
+		// contexts
+		"context.exists":               "context %q",
+		"context.created":              "Successfully created %s context %q",
+		"context.rm-current":           "cannot delete current context",
+		"context.routed":               "Commands not supported by the %s backend are routed to the Docker engine when available, use --%s to make them fail instead",
+		"context.scenario":             "scenario",
+		"context.local.connect":        "cannot connect to %s, context %q not created",
+		"context.local.tls-host":       "TLS flags require --host",
+		"context.local.invalid-host":   "invalid host %q, use tcp://host:port",
+		"context.local.tls-pair":       "--tlscert and --tlskey must be set together",
+		"context.ecs.connect":          "cannot connect to ECS backend",
+		"context.ecs.secret-key-stdin": "use --secret-key-stdin",
+		"context.aci.exists":           "context %s",
+		"context.aci.connect":          "cannot connect to ACI backend",
+		"context.aci.subscription":     "could not find the requested subscription from your Azure login. You might need to specify a tenant ID with docker login azure --tenant-id xxx",
+		"context.bootstrap.policy":     "Created IAM policy %s, attach it to developers' users or groups",
+		"context.bootstrap.role":       "Created role %s, assign it to developers on the resource group",
+
+		// other commands
+		"audit.since":       "invalid --since value %q, expected a timestamp or a duration",
+		"serve.listen":      "listen address %s",
+		"ecs.moved":         "The ECS integration is now part of the CLI. Use `docker compose` with an ECS context.",
+		"update.up-to-date": "Docker cloud integration %s is up to date (%s channel)",
+		"update.available":  "Docker cloud integration %s is available (%s channel), current version is %s",
+		"update.download":   "Downloading Docker cloud integration %s...",
+		"update.done":       "Updated Docker cloud integration from %s to %s",
+	})
+}
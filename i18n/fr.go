@@ -0,0 +1,57 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package i18n
+
+func init() {
+	Register("fr", map[string]string{
+		"backend.connect":      "impossible de se connecter au backend",
+		"container.not-found":  "conteneur %s introuvable",
+		"options.quiet-json":   `les options "quiet" et "json" ne peuvent pas être combinées`,
+		"console.close-failed": "Impossible de fermer la console",
+
+		"progress.running": "[+] En cours %d/%d",
+		"progress.eta":     " (environ %s restantes)",
+		"progress.pending": ", en attente : %s",
+
+		"login.canceled":  "connexion annulée",
+		"login.succeeded": "connexion réussie",
+		"logout.canceled": "déconnexion annulée",
+		"logout.azure":    "Suppression des identifiants de connexion à Azure",
+
+		"compose.prune.none":    "Aucune ressource orpheline trouvée",
+		"compose.prune.confirm": "Supprimer ces %d ressources ?",
+		"compose.diff.none":     "Aucune différence entre l'application déployée et le fichier compose",
+		"compose.diff.drift":    "Ressources modifiées en dehors de la CLI :",
+		"compose.unlock.done":   "Projet %q déverrouillé",
+		"compose.portforward":   "Redirection de 127.0.0.1:%d vers le port %d du service %s, Ctrl-C pour arrêter",
+		"compose.ui.terminal":   "le tableau de bord nécessite un terminal",
+
+		"compose.up.confirm":        "Déployer l'application ?",
+		"compose.generate.language": "Langage du projet",
+		"compose.generate.service":  "Nom du service",
+		"compose.generate.port":     "Port sur lequel l'application écoute",
+		"compose.generate.cpus":     "Limite de CPU",
+		"compose.generate.memory":   "Limite de mémoire",
+
+		"context.created":    "Contexte %s %q créé",
+		"context.rm-current": "impossible de supprimer le contexte courant",
+
+		"update.up-to-date": "L'intégration cloud de Docker %s est à jour (canal %s)",
+		"update.download":   "Téléchargement de l'intégration cloud de Docker %s...",
+		"update.done":       "Intégration cloud de Docker mise à jour de %s vers %s",
+	})
+}
@@ -0,0 +1,148 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// LanguageEnvVar is the environment variable selecting the language of messages, set by the --lang flag. LC_ALL,
+	// LC_MESSAGES and LANG are used when it's not set.
+	LanguageEnvVar = "COMPOSE_CLI_LANG"
+	// LanguageFlagName is the name of the global flag selecting the language of messages
+	LanguageFlagName = "lang"
+	// DefaultLanguage is the language of messages without translation
+	DefaultLanguage = "en"
+)
+
+var (
+	mtx      sync.RWMutex
+	catalogs = map[string]map[string]string{}
+)
+
+// Register adds the messages of a language, by key. Messages missing from a catalog are displayed in the default
+// language.
+func Register(language string, messages map[string]string) {
+	mtx.Lock()
+	defer mtx.Unlock()
+	catalog, ok := catalogs[language]
+	if !ok {
+		catalog = map[string]string{}
+		catalogs[language] = catalog
+	}
+	for key, message := range messages {
+		catalog[key] = message
+	}
+}
+
+// Languages lists the languages with a catalog
+func Languages() []string {
+	mtx.RLock()
+	defer mtx.RUnlock()
+	var languages []string
+	for language := range catalogs {
+		languages = append(languages, language)
+	}
+	return languages
+}
+
+// Language returns the language messages are displayed in: the one set with --lang or the locale of the environment,
+// the default one when it has no catalog
+func Language() string {
+	for _, name := range []string{LanguageEnvVar, "LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			language := parseLocale(value)
+			mtx.RLock()
+			_, ok := catalogs[language]
+			mtx.RUnlock()
+			if ok {
+				return language
+			}
+			return DefaultLanguage
+		}
+	}
+	return DefaultLanguage
+}
+
+// parseLocale returns the language of a POSIX locale such as fr_FR.UTF-8
+func parseLocale(locale string) string {
+	language := strings.ToLower(locale)
+	if i := strings.IndexAny(language, "_.@-"); i >= 0 {
+		language = language[:i]
+	}
+	if language == "c" || language == "posix" {
+		return DefaultLanguage
+	}
+	return language
+}
+
+// T returns the message of key in the current language, formatted with args
+func T(key string, args ...interface{}) string {
+	format := lookup(Language(), key)
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Error returns an error with the message of key
+func Error(key string, args ...interface{}) error {
+	return errors.New(T(key, args...))
+}
+
+// Wrap annotates err with the message of key
+func Wrap(err error, key string, args ...interface{}) error {
+	return errors.Wrap(err, T(key, args...))
+}
+
+func lookup(language string, key string) string {
+	mtx.RLock()
+	defer mtx.RUnlock()
+	if message, ok := catalogs[language][key]; ok {
+		return message
+	}
+	if message, ok := catalogs[DefaultLanguage][key]; ok {
+		return message
+	}
+	return key
+}
+
+// AddLanguageFlag adds the --lang flag, selecting the language of messages whenever it's parsed
+func AddLanguageFlag(flags *pflag.FlagSet) {
+	flags.Var(languageValue{}, LanguageFlagName, "Language of messages, such as fr, instead of the one of the locale")
+}
+
+type languageValue struct{}
+
+func (languageValue) Set(s string) error {
+	return os.Setenv(LanguageEnvVar, s)
+}
+
+func (languageValue) String() string {
+	return ""
+}
+
+func (languageValue) Type() string {
+	return "string"
+}
@@ -0,0 +1,125 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package i18n
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"gotest.tools/v3/assert"
+)
+
+func setLocale(t *testing.T, env map[string]string) {
+	names := []string{LanguageEnvVar, "LC_ALL", "LC_MESSAGES", "LANG"}
+	previous := map[string]string{}
+	for _, name := range names {
+		previous[name] = os.Getenv(name)
+		os.Unsetenv(name) // nolint:errcheck
+	}
+	for name, value := range env {
+		os.Setenv(name, value) // nolint:errcheck
+	}
+	t.Cleanup(func() {
+		for _, name := range names {
+			os.Setenv(name, previous[name]) // nolint:errcheck
+		}
+	})
+}
+
+func TestLanguage(t *testing.T) {
+	setLocale(t, nil)
+	assert.Equal(t, Language(), DefaultLanguage)
+
+	setLocale(t, map[string]string{"LANG": "fr_FR.UTF-8"})
+	assert.Equal(t, Language(), "fr")
+
+	setLocale(t, map[string]string{"LANG": "fr_FR.UTF-8", "LC_ALL": "C"})
+	assert.Equal(t, Language(), DefaultLanguage)
+
+	setLocale(t, map[string]string{"LANG": "en_US", LanguageEnvVar: "fr"})
+	assert.Equal(t, Language(), "fr")
+
+	setLocale(t, map[string]string{"LANG": "xx_XX"})
+	assert.Equal(t, Language(), DefaultLanguage)
+}
+
+func TestT(t *testing.T) {
+	Register("test", map[string]string{"container.not-found": "no %s"})
+	setLocale(t, map[string]string{LanguageEnvVar: "test"})
+
+	assert.Equal(t, T("container.not-found", "foo"), "no foo")
+	assert.Equal(t, T("backend.connect"), "cannot connect to backend")
+	assert.Equal(t, T("unknown.key"), "unknown.key")
+	assert.Error(t, Wrap(Error("container.not-found", "foo"), "backend.connect"), "cannot connect to backend: no foo")
+}
+
+func TestLanguageFlag(t *testing.T) {
+	setLocale(t, nil)
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	AddLanguageFlag(flags)
+
+	assert.NilError(t, flags.Parse([]string{"--lang", "fr"}))
+	assert.Equal(t, Language(), "fr")
+	assert.Equal(t, T("login.succeeded"), "connexion réussie")
+}
+
+var (
+	keyRegexp  = regexp.MustCompile(`i18n\.(?:T|Error)\("([^"]+)"|i18n\.Wrap\([^"\n]*"([^"]+)"`)
+	verbRegexp = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z]`)
+)
+
+func TestCatalogHasAllKeys(t *testing.T) {
+	var files []string
+	for _, dir := range []string{"../cli", "../progress"} {
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err == nil && strings.HasSuffix(path, ".go") && !strings.HasSuffix(path, "_test.go") {
+				files = append(files, path)
+			}
+			return err
+		})
+		assert.NilError(t, err)
+	}
+	assert.Assert(t, len(files) > 0)
+
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		assert.NilError(t, err)
+		for _, match := range keyRegexp.FindAllStringSubmatch(string(content), -1) {
+			key := match[1] + match[2]
+			_, ok := catalogs[DefaultLanguage][key]
+			assert.Assert(t, ok, "%s: message %q is missing from the catalog", file, key)
+		}
+	}
+}
+
+func TestTranslationsKeepVerbs(t *testing.T) {
+	for language, catalog := range catalogs {
+		for key, message := range catalog {
+			if language == "test" {
+				continue
+			}
+			original, ok := catalogs[DefaultLanguage][key]
+			assert.Assert(t, ok, "%s: unknown message %q", language, key)
+			assert.DeepEqual(t, verbRegexp.FindAllString(message, -1), verbRegexp.FindAllString(original, -1))
+		}
+	}
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/docker/docker/pkg/stringid"
 
 	"github.com/docker/go-connections/nat"
+	"github.com/docker/go-units"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
@@ -38,15 +39,20 @@ import (
 
 	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/api/containers"
+	"github.com/docker/compose-cli/api/images"
 	"github.com/docker/compose-cli/api/secrets"
 	"github.com/docker/compose-cli/api/volumes"
 	"github.com/docker/compose-cli/backend"
+	apicontext "github.com/docker/compose-cli/context"
 	"github.com/docker/compose-cli/context/cloud"
+	"github.com/docker/compose-cli/context/store"
 	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/utils/dockercli"
 )
 
 type local struct {
 	apiClient *client.Client
+	endpoint  store.LocalContext
 }
 
 func init() {
@@ -54,13 +60,22 @@ func init() {
 }
 
 func service(ctx context.Context) (backend.Service, error) {
-	apiClient, err := client.NewClientWithOpts(client.FromEnv)
+	var localContext store.LocalContext
+	if err := store.ContextStore(ctx).GetEndpoint(apicontext.CurrentContext(ctx), &localContext); err != nil {
+		return nil, err
+	}
+	opts, err := clientOptions(localContext)
+	if err != nil {
+		return nil, err
+	}
+	apiClient, err := client.NewClientWithOpts(opts...)
 	if err != nil {
 		return nil, err
 	}
 
 	return &local{
-		apiClient,
+		apiClient: apiClient,
+		endpoint:  localContext,
 	}, nil
 }
 
@@ -69,7 +84,7 @@ func (ms *local) ContainerService() containers.Service {
 }
 
 func (ms *local) ComposeService() compose.Service {
-	return nil
+	return &composeService{apiClient: ms.apiClient, endpoint: ms.endpoint}
 }
 
 func (ms *local) SecretsService() secrets.Service {
@@ -80,6 +95,10 @@ func (ms *local) VolumeService() volumes.Service {
 	return nil
 }
 
+func (ms *local) ImageService() images.Service {
+	return &imageService{apiClient: ms.apiClient}
+}
+
 func (ms *local) Inspect(ctx context.Context, id string) (containers.Container, error) {
 	c, err := ms.apiClient.ContainerInspect(ctx, id)
 	if err != nil {
@@ -87,8 +106,10 @@ func (ms *local) Inspect(ctx context.Context, id string) (containers.Container,
 	}
 
 	status := ""
+	exitCode := 0
 	if c.State != nil {
 		status = c.State.Status
+		exitCode = c.State.ExitCode
 	}
 
 	command := ""
@@ -97,12 +118,19 @@ func (ms *local) Inspect(ctx context.Context, id string) (containers.Container,
 		command = strings.Join(c.Config.Cmd, " ")
 	}
 
+	restartPolicyCondition := ""
+	if c.HostConfig != nil {
+		restartPolicyCondition = fromRestartPolicy(c.HostConfig.RestartPolicy)
+	}
+
 	return containers.Container{
-		ID:       stringid.TruncateID(c.ID),
-		Status:   status,
-		Image:    c.Image,
-		Command:  command,
-		Platform: c.Platform,
+		ID:                     stringid.TruncateID(c.ID),
+		Status:                 status,
+		Image:                  c.Image,
+		Command:                command,
+		Platform:               c.Platform,
+		ExitCode:               exitCode,
+		RestartPolicyCondition: restartPolicyCondition,
 	}, nil
 }
 
@@ -143,16 +171,52 @@ func (ms *local) Run(ctx context.Context, r containers.ContainerConfig) error {
 		Image:        r.Image,
 		Labels:       r.Labels,
 		ExposedPorts: exposedPorts,
+		StopSignal:   r.StopSignal,
+		StopTimeout:  r.StopTimeout,
+	}
+	info, err := ms.apiClient.Info(ctx)
+	if err != nil {
+		return err
+	}
+	isolation, err := containerIsolation(info.OSType, r.Platform, r.Isolation)
+	if err != nil {
+		return err
+	}
+	runtime, err := dockercli.CheckRuntime(info.Runtimes, r.Runtime)
+	if err != nil {
+		return err
+	}
+	ulimits, err := toUlimits(r.Ulimits)
+	if err != nil {
+		return err
+	}
+	devices, err := toDeviceMappings(info.OSType, r.Devices)
+	if err != nil {
+		return err
 	}
 	hostConfig := &container.HostConfig{
-		PortBindings: hostBindings,
+		PortBindings:   hostBindings,
+		Isolation:      isolation,
+		Runtime:        runtime,
+		RestartPolicy:  toRestartPolicy(r.RestartPolicyCondition),
+		Sysctls:        r.Sysctls,
+		CapAdd:         r.CapAdd,
+		CapDrop:        r.CapDrop,
+		ReadonlyRootfs: r.ReadOnly,
+		Tmpfs:          toTmpfs(r.Tmpfs),
+		ShmSize:        r.ShmSize,
+		Resources: container.Resources{
+			Ulimits:        ulimits,
+			Devices:        devices,
+			DeviceRequests: toDeviceRequests(r.DeviceRequests),
+		},
 	}
 
 	created, err := ms.apiClient.ContainerCreate(ctx, containerConfig, hostConfig, nil, r.ID)
 
 	if err != nil {
 		if client.IsErrNotFound(err) {
-			io, err := ms.apiClient.ImagePull(ctx, r.Image, types.ImagePullOptions{})
+			io, err := ms.apiClient.ImagePull(ctx, r.Image, types.ImagePullOptions{Platform: r.Platform})
 			if err != nil {
 				return err
 			}
@@ -277,6 +341,125 @@ func (ms *local) Delete(ctx context.Context, containerID string, request contain
 	return err
 }
 
+func toUlimits(values []string) ([]*units.Ulimit, error) {
+	var ulimits []*units.Ulimit
+	for _, value := range values {
+		ulimit, err := units.ParseUlimit(value)
+		if err != nil {
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "ulimit %q: %s", value, err)
+		}
+		ulimits = append(ulimits, ulimit)
+	}
+	return ulimits, nil
+}
+
+// toDeviceMappings maps host devices, as host[:container][:permissions], to the engine device mappings
+func toDeviceMappings(osType string, devices []string) ([]container.DeviceMapping, error) {
+	var mappings []container.DeviceMapping
+	for _, device := range devices {
+		// Windows devices are identified by their interface class, e.g. class/5B45201D-F2F2-4F3B-85BB-30FF1F953599
+		if osType == "windows" {
+			mappings = append(mappings, container.DeviceMapping{PathOnHost: device})
+			continue
+		}
+		parts := strings.Split(device, ":")
+		if len(parts) > 3 || parts[0] == "" {
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "invalid device %q", device)
+		}
+		mapping := container.DeviceMapping{
+			PathOnHost:        parts[0],
+			PathInContainer:   parts[0],
+			CgroupPermissions: "rwm",
+		}
+		switch len(parts) {
+		case 3:
+			mapping.PathInContainer = parts[1]
+			mapping.CgroupPermissions = parts[2]
+		case 2:
+			if isDevicePermissions(parts[1]) {
+				mapping.CgroupPermissions = parts[1]
+			} else {
+				mapping.PathInContainer = parts[1]
+			}
+		}
+		if !isDevicePermissions(mapping.CgroupPermissions) {
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "invalid device permissions %q", mapping.CgroupPermissions)
+		}
+		mappings = append(mappings, mapping)
+	}
+	return mappings, nil
+}
+
+// isDevicePermissions checks the cgroup permissions of a device are a combination of r, w and m
+func isDevicePermissions(permissions string) bool {
+	if permissions == "" || len(permissions) > 3 {
+		return false
+	}
+	for _, p := range permissions {
+		if !strings.ContainsRune("rwm", p) {
+			return false
+		}
+	}
+	return true
+}
+
+func toDeviceRequests(requests []containers.DeviceRequest) []container.DeviceRequest {
+	var result []container.DeviceRequest
+	for _, r := range requests {
+		var capabilities [][]string
+		if len(r.Capabilities) > 0 {
+			capabilities = [][]string{r.Capabilities}
+		}
+		result = append(result, container.DeviceRequest{
+			Driver:       r.Driver,
+			Count:        r.Count,
+			DeviceIDs:    r.DeviceIDs,
+			Capabilities: capabilities,
+		})
+	}
+	return result
+}
+
+// toTmpfs maps tmpfs mounts, as path[:options], to the engine tmpfs mounts
+func toTmpfs(mounts []string) map[string]string {
+	if len(mounts) == 0 {
+		return nil
+	}
+	tmpfs := map[string]string{}
+	for _, mount := range mounts {
+		parts := strings.SplitN(mount, ":", 2)
+		if len(parts) == 2 {
+			tmpfs[parts[0]] = parts[1]
+		} else {
+			tmpfs[parts[0]] = ""
+		}
+	}
+	return tmpfs
+}
+
+// toRestartPolicy maps a restart policy condition to the engine restart policy
+func toRestartPolicy(condition string) container.RestartPolicy {
+	switch condition {
+	case containers.RestartPolicyAny:
+		return container.RestartPolicy{Name: "always"}
+	case containers.RestartPolicyOnFailure:
+		return container.RestartPolicy{Name: "on-failure"}
+	default:
+		return container.RestartPolicy{Name: "no"}
+	}
+}
+
+func fromRestartPolicy(policy container.RestartPolicy) string {
+	switch {
+	case policy.IsAlways(), policy.IsUnlessStopped():
+		return containers.RestartPolicyAny
+	case policy.IsOnFailure():
+		return containers.RestartPolicyOnFailure
+	default:
+		return containers.RestartPolicyNone
+	}
+}
+
 func toPorts(ports []types.Port) []containers.Port {
 	result := []containers.Port{}
 	for _, port := range ports {
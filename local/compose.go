@@ -0,0 +1,349 @@
+// +build local
+
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+	"github.com/sanathkr/go-yaml"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/config"
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/registry"
+)
+
+// oneOffLabel marks the containers created by docker-compose run
+const oneOffLabel = "com.docker.compose.oneoff"
+
+// composeService runs compose applications on the engine of the context with docker-compose, and reads their state
+// from the engine
+type composeService struct {
+	apiClient client.APIClient
+	endpoint  store.LocalContext
+}
+
+func (cs *composeService) Up(ctx context.Context, project *types.Project, options compose.UpOptions) error {
+	if options.ResolveImageDigests {
+		if err := registry.PinImages(ctx, project); err != nil {
+			return err
+		}
+	}
+	converted, err := cs.Convert(ctx, project)
+	if err != nil {
+		return err
+	}
+	err = compose.RunJobs(ctx, project, func(ctx context.Context, job types.ServiceConfig) error {
+		return cs.runJob(ctx, project, converted, job.Name)
+	})
+	if err != nil {
+		return err
+	}
+	var services, scheduled []string
+	for _, service := range project.Services {
+		switch {
+		case compose.IsScheduled(service):
+			scheduled = append(scheduled, service.Name)
+		case !compose.IsJob(service):
+			services = append(services, service.Name)
+		}
+	}
+	if len(services) > 0 {
+		err := cs.dockerCompose(ctx, project, converted, append([]string{"up", "--detach", "--remove-orphans"}, services...)...)
+		if err != nil {
+			return err
+		}
+	}
+	if len(scheduled) == 0 {
+		return nil
+	}
+	// scheduled containers are only created, the scheduler of docker serve starts them when their schedule is due
+	return cs.dockerCompose(ctx, project, converted, append([]string{"up", "--no-start", "--no-deps"}, scheduled...)...)
+}
+
+// Convert labels scheduled services with their schedule, for the scheduler of docker serve to run them
+func (cs *composeService) Convert(ctx context.Context, project *types.Project) ([]byte, error) {
+	for i, service := range project.Services {
+		schedule, err := compose.ServiceSchedule(service)
+		if err != nil {
+			return nil, err
+		}
+		if schedule == nil {
+			continue
+		}
+		if service.Labels == nil {
+			service.Labels = types.Labels{}
+		}
+		service.Labels[compose.ScheduleLabel] = schedule.String()
+		project.Services[i] = service
+	}
+	return yaml.Marshal(map[string]interface{}{
+		"services": project.Services,
+		"networks": project.Networks,
+		"volumes":  project.Volumes,
+		"secrets":  project.Secrets,
+		"configs":  project.Configs,
+	})
+}
+
+// runJob runs a job to completion, returning a JobFailedError with the exit status of its container when it fails
+func (cs *composeService) runJob(ctx context.Context, project *types.Project, converted []byte, job string) error {
+	err := cs.dockerCompose(ctx, project, converted, "run", "--rm", "-T", job)
+	var exit *exec.ExitError
+	if errors.As(err, &exit) {
+		return compose.JobFailedError{Job: job, ExitCode: exit.ExitCode()}
+	}
+	return err
+}
+
+// dockerCompose runs a docker-compose command on the converted project, against the engine of the context
+func (cs *composeService) dockerCompose(ctx context.Context, project *types.Project, converted []byte, args ...string) error {
+	global := append(endpointFlags(cs.endpoint), "--project-directory", project.WorkingDir, "--project-name", project.Name, "-f", "-")
+	cmd := exec.CommandContext(ctx, "docker-compose", append(global, args...)...)
+	cmd.Stdin = bytes.NewReader(converted)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err := cmd.Run()
+	if errors.Is(err, exec.ErrNotFound) {
+		return errors.Wrap(err, "the local backend runs compose applications with docker-compose, which must be installed")
+	}
+	return err
+}
+
+// endpointFlags are the docker-compose flags connecting to the engine of the context, the environment sets it when the
+// context has no host
+func endpointFlags(endpoint store.LocalContext) []string {
+	if endpoint.Host == "" {
+		return nil
+	}
+	flags := []string{"--host", endpoint.Host}
+	if endpoint.CAFile != "" {
+		flags = append(flags, "--tlscacert", endpoint.CAFile)
+	}
+	if endpoint.CertFile != "" {
+		flags = append(flags, "--tlscert", endpoint.CertFile, "--tlskey", endpoint.KeyFile)
+	}
+	switch {
+	case endpoint.SkipTLSVerify:
+		flags = append(flags, "--tls")
+	case endpoint.CAFile != "" || endpoint.CertFile != "":
+		flags = append(flags, "--tlsverify")
+	}
+	return flags
+}
+
+func (cs *composeService) Down(ctx context.Context, projectName string, options compose.DownOptions) error {
+	if options.RemoveImages != "" {
+		return errors.Wrap(errdefs.ErrNotImplemented, "use docker image rm to remove the project images")
+	}
+	list, err := cs.apiClient.ContainerList(ctx, moby.ContainerListOptions{All: true, Filters: projectFilter(projectName)})
+	if err != nil {
+		return err
+	}
+	for _, c := range list {
+		err := cs.apiClient.ContainerRemove(ctx, c.ID, moby.ContainerRemoveOptions{Force: true, RemoveVolumes: true})
+		if err != nil && !client.IsErrNotFound(err) {
+			return err
+		}
+	}
+	networks, err := cs.apiClient.NetworkList(ctx, moby.NetworkListOptions{Filters: projectFilter(projectName)})
+	if err != nil {
+		return err
+	}
+	for _, n := range networks {
+		if err := cs.apiClient.NetworkRemove(ctx, n.ID); err != nil && !client.IsErrNotFound(err) {
+			return err
+		}
+	}
+	if !options.RemoveVolumes {
+		return nil
+	}
+	volumes, err := cs.apiClient.VolumeList(ctx, projectFilter(projectName))
+	if err != nil {
+		return err
+	}
+	for _, v := range volumes.Volumes {
+		if err := cs.apiClient.VolumeRemove(ctx, v.Name, true); err != nil && !client.IsErrNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cs *composeService) Ps(ctx context.Context, projectName string) ([]compose.ServiceStatus, error) {
+	list, err := cs.apiClient.ContainerList(ctx, moby.ContainerListOptions{All: true, Filters: projectFilter(projectName)})
+	if err != nil {
+		return nil, err
+	}
+	var containers []moby.ContainerJSON
+	for _, c := range list {
+		if c.Labels[oneOffLabel] == "True" {
+			continue
+		}
+		container, err := cs.apiClient.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			return nil, err
+		}
+		containers = append(containers, container)
+	}
+	return serviceStatuses(containers), nil
+}
+
+// serviceStatuses sums the state of the containers of each service. An exited container counts as a restart, like the
+// tasks the cloud backends replace, so that compose up can wait for it to exit.
+func serviceStatuses(containers []moby.ContainerJSON) []compose.ServiceStatus {
+	byName := map[string]*compose.ServiceStatus{}
+	var names []string
+	for _, c := range containers {
+		if c.ContainerJSONBase == nil || c.State == nil || c.Config == nil {
+			continue
+		}
+		name := c.Config.Labels[compose.ServiceTag]
+		status, ok := byName[name]
+		if !ok {
+			status = &compose.ServiceStatus{ID: name, Name: name}
+			byName[name] = status
+			names = append(names, name)
+		}
+		status.Desired++
+		status.Restarts += c.RestartCount
+		if c.State.Running {
+			status.Replicas++
+			continue
+		}
+		if c.State.Status == "exited" {
+			exitCode := c.State.ExitCode
+			status.Restarts++
+			status.ExitCode = &exitCode
+		}
+	}
+	sort.Strings(names)
+	statuses := []compose.ServiceStatus{}
+	for _, name := range names {
+		statuses = append(statuses, *byName[name])
+	}
+	return statuses
+}
+
+func (cs *composeService) List(ctx context.Context, projectName string) ([]compose.Stack, error) {
+	list, err := cs.apiClient.ContainerList(ctx, moby.ContainerListOptions{All: true, Filters: projectFilter(projectName)})
+	if err != nil {
+		return nil, err
+	}
+	running := map[string]bool{}
+	var names []string
+	for _, c := range list {
+		name := c.Labels[compose.ProjectTag]
+		if _, ok := running[name]; !ok {
+			names = append(names, name)
+		}
+		running[name] = running[name] || c.State == "running"
+	}
+	sort.Strings(names)
+	var stacks []compose.Stack
+	for _, name := range names {
+		status := compose.RUNNING
+		if !running[name] {
+			status = "Exited"
+		}
+		stacks = append(stacks, compose.Stack{ID: name, Name: name, Status: status})
+	}
+	return stacks, nil
+}
+
+// projectFilter selects the resources created by docker-compose for a project, or for all projects when its name is
+// empty
+func projectFilter(projectName string) filters.Args {
+	if projectName == "" {
+		return filters.NewArgs(filters.Arg("label", compose.ProjectTag))
+	}
+	return filters.NewArgs(filters.Arg("label", compose.ProjectTag+"="+projectName))
+}
+
+func (cs *composeService) RunJob(ctx context.Context, project *types.Project, job string) error {
+	if _, err := project.GetService(job); err != nil {
+		return err
+	}
+	converted, err := cs.Convert(ctx, project)
+	if err != nil {
+		return err
+	}
+	return cs.runJob(ctx, project, converted, job)
+}
+
+func (cs *composeService) Lock(ctx context.Context, projectName string, timeout time.Duration) (func() error, error) {
+	return cs.locks(ctx).Lock(ctx, projectName, timeout)
+}
+
+func (cs *composeService) Unlock(ctx context.Context, projectName string) error {
+	return cs.locks(ctx).Unlock(projectName)
+}
+
+// locks are kept on the local machine, along with the ones of the other local contexts
+func (cs *composeService) locks(ctx context.Context) compose.FileLock {
+	return compose.ContextLocks(ctx, filepath.Join(config.Dir(ctx), "locks"))
+}
+
+func (cs *composeService) Logs(ctx context.Context, projectName string, consumer compose.LogConsumer, options compose.LogOptions) error {
+	return errors.Wrap(errdefs.ErrNotImplemented, "use docker-compose logs")
+}
+
+func (cs *composeService) Prune(ctx context.Context, options compose.PruneOptions) ([]compose.OrphanResource, error) {
+	return nil, errors.Wrap(errdefs.ErrNotImplemented, "use docker system prune")
+}
+
+func (cs *composeService) Estimate(ctx context.Context, project *types.Project) ([]compose.CostEstimate, error) {
+	return nil, errors.Wrap(errdefs.ErrNotImplemented, "local applications have no cost")
+}
+
+func (cs *composeService) PortForward(ctx context.Context, projectName string, service string, localPort, remotePort uint32) error {
+	return errors.Wrap(errdefs.ErrNotImplemented, "use published ports to reach local services")
+}
+
+func (cs *composeService) Discovery(ctx context.Context, project *types.Project) ([]compose.ServiceDiscovery, error) {
+	return nil, errors.Wrap(errdefs.ErrNotImplemented, "services resolve each other by name on the project networks")
+}
+
+func (cs *composeService) Exposure(ctx context.Context, project *types.Project) ([]compose.PortExposure, error) {
+	return nil, errors.Wrap(errdefs.ErrNotImplemented, "use docker-compose port")
+}
+
+func (cs *composeService) Diff(ctx context.Context, project *types.Project) (compose.ProjectDiff, error) {
+	return compose.ProjectDiff{}, errors.Wrap(errdefs.ErrNotImplemented, "use docker-compose config")
+}
+
+func (cs *composeService) Import(ctx context.Context, options compose.ImportOptions) (*types.Project, error) {
+	return nil, errors.Wrap(errdefs.ErrNotImplemented, "the local backend only runs compose files")
+}
+
+func (cs *composeService) Outputs(ctx context.Context, projectName string) ([]compose.StackOutput, error) {
+	return nil, errors.Wrap(errdefs.ErrNotImplemented, "local applications have no cloud resources")
+}
@@ -0,0 +1,73 @@
+// +build local
+
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/context/store"
+)
+
+func serviceContainer(service string, state moby.ContainerState, restarts int) moby.ContainerJSON {
+	return moby.ContainerJSON{
+		ContainerJSONBase: &moby.ContainerJSONBase{State: &state, RestartCount: restarts},
+		Config:            &container.Config{Labels: map[string]string{compose.ServiceTag: service}},
+	}
+}
+
+func TestServiceStatuses(t *testing.T) {
+	statuses := serviceStatuses([]moby.ContainerJSON{
+		serviceContainer("web", moby.ContainerState{Status: "running", Running: true}, 1),
+		serviceContainer("web", moby.ContainerState{Status: "running", Running: true}, 0),
+		serviceContainer("tests", moby.ContainerState{Status: "exited", ExitCode: 3}, 0),
+		serviceContainer("db", moby.ContainerState{Status: "created"}, 0),
+	})
+	exitCode := 3
+	assert.DeepEqual(t, statuses, []compose.ServiceStatus{
+		{ID: "db", Name: "db", Desired: 1},
+		{ID: "tests", Name: "tests", Desired: 1, Restarts: 1, ExitCode: &exitCode},
+		{ID: "web", Name: "web", Desired: 2, Replicas: 2, Restarts: 1},
+	})
+}
+
+func TestEndpointFlags(t *testing.T) {
+	assert.Assert(t, endpointFlags(store.LocalContext{}) == nil)
+	assert.DeepEqual(t, endpointFlags(store.LocalContext{Host: "unix:///var/run/docker.sock"}), []string{"--host", "unix:///var/run/docker.sock"})
+	assert.DeepEqual(t, endpointFlags(store.LocalContext{Host: "tcp://engine:2376", CAFile: "ca.pem", CertFile: "cert.pem", KeyFile: "key.pem"}),
+		[]string{"--host", "tcp://engine:2376", "--tlscacert", "ca.pem", "--tlscert", "cert.pem", "--tlskey", "key.pem", "--tlsverify"})
+	assert.DeepEqual(t, endpointFlags(store.LocalContext{Host: "tcp://engine:2376", SkipTLSVerify: true}), []string{"--host", "tcp://engine:2376", "--tls"})
+}
+
+func TestConvertLabelsScheduledServices(t *testing.T) {
+	project := &types.Project{Services: []types.ServiceConfig{
+		{Name: "web", Image: "nginx"},
+		{Name: "backup", Image: "backup", Extensions: map[string]interface{}{compose.ScheduleExtension: "0 3 * * *"}},
+	}}
+	_, err := (&composeService{}).Convert(context.Background(), project)
+	assert.NilError(t, err)
+	assert.Assert(t, project.Services[0].Labels == nil)
+	assert.DeepEqual(t, project.Services[1].Labels, types.Labels{compose.ScheduleLabel: "cron(0 3 * * *)"})
+}
@@ -0,0 +1,53 @@
+// +build local
+
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"net/http"
+
+	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/tlsconfig"
+
+	"github.com/docker/compose-cli/context/store"
+)
+
+// clientOptions connects to the engine of the context, or to the one set by the environment when the context has no host
+func clientOptions(c store.LocalContext) ([]client.Opt, error) {
+	if c.Host == "" {
+		return []client.Opt{client.FromEnv}, nil
+	}
+	var opts []client.Opt
+	if c.CAFile != "" || c.CertFile != "" || c.SkipTLSVerify {
+		tlsConfig, err := tlsconfig.Client(tlsconfig.Options{
+			CAFile:             c.CAFile,
+			CertFile:           c.CertFile,
+			KeyFile:            c.KeyFile,
+			InsecureSkipVerify: c.SkipTLSVerify,
+		})
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, client.WithHTTPClient(&http.Client{
+			Transport:     &http.Transport{TLSClientConfig: tlsConfig},
+			CheckRedirect: client.CheckRedirect,
+		}))
+	}
+	// the host is set once the HTTP client is, to configure its transport for the host protocol
+	return append(opts, client.WithHost(c.Host), client.WithAPIVersionNegotiation()), nil
+}
@@ -0,0 +1,157 @@
+// +build local
+
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/images"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/progress"
+)
+
+const noneReference = "<none>"
+
+type imageService struct {
+	apiClient *client.Client
+}
+
+func (is *imageService) List(ctx context.Context, options images.ListOptions) ([]images.Image, error) {
+	args := filters.NewArgs()
+	if options.Dangling != nil {
+		if *options.Dangling {
+			args.Add("dangling", "true")
+		} else {
+			args.Add("dangling", "false")
+		}
+	}
+	summaries, err := is.apiClient.ImageList(ctx, types.ImageListOptions{Filters: args})
+	if err != nil {
+		return nil, err
+	}
+	var result []images.Image
+	for _, summary := range summaries {
+		image := images.Image{
+			ID:      stringid.TruncateID(summary.ID),
+			Created: time.Unix(summary.Created, 0),
+			Size:    summary.Size,
+		}
+		tags := taggedReferences(summary.RepoTags)
+		if len(tags) == 0 {
+			image.Repository, image.Tag, image.Dangling = noneReference, noneReference, true
+			result = append(result, image)
+			continue
+		}
+		// like docker images, an image is listed once per tag
+		for _, tag := range tags {
+			image.Repository, image.Tag = splitReference(tag)
+			result = append(result, image)
+		}
+	}
+	return result, nil
+}
+
+func (is *imageService) Remove(ctx context.Context, image string, options images.RemoveOptions) ([]string, error) {
+	deleted, err := is.apiClient.ImageRemove(ctx, image, types.ImageRemoveOptions{
+		Force:         options.Force,
+		PruneChildren: true,
+	})
+	if client.IsErrNotFound(err) {
+		return nil, errors.Wrapf(errdefs.ErrNotFound, "image %q", image)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var result []string
+	for _, item := range deleted {
+		if item.Untagged != "" {
+			result = append(result, "Untagged: "+item.Untagged)
+		}
+		if item.Deleted != "" {
+			result = append(result, "Deleted: "+item.Deleted)
+		}
+	}
+	return result, nil
+}
+
+func (is *imageService) Pull(ctx context.Context, image string, options images.PullOptions) error {
+	w := progress.ContextWriter(ctx)
+	w.Event(progress.Event{ID: image, Status: progress.Working, StatusText: "Pulling"})
+	stream, err := is.apiClient.ImagePull(ctx, image, types.ImagePullOptions{Platform: options.Platform})
+	if err != nil {
+		w.Event(progress.Event{ID: image, Status: progress.Error, StatusText: "Error"})
+		return err
+	}
+	defer stream.Close() // nolint:errcheck
+
+	decoder := json.NewDecoder(stream)
+	for {
+		var message jsonmessage.JSONMessage
+		if err := decoder.Decode(&message); err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		if message.Error != nil {
+			w.Event(progress.Event{ID: image, Status: progress.Error, StatusText: "Error"})
+			return errors.New(message.Error.Message)
+		}
+		// layers are reported by their ID, the image itself once pulled
+		if message.ID != "" && !strings.Contains(image, message.ID) {
+			status := progress.Working
+			if message.Status == "Pull complete" || message.Status == "Already exists" {
+				status = progress.Done
+			}
+			w.Event(progress.Event{ID: message.ID, Text: "Layer", Status: status, StatusText: message.Status})
+		}
+	}
+	w.Event(progress.Event{ID: image, Status: progress.Done, StatusText: "Pulled"})
+	return nil
+}
+
+// taggedReferences filters out the <none>:<none> references the engine lists for dangling images
+func taggedReferences(references []string) []string {
+	var tagged []string
+	for _, reference := range references {
+		if reference != noneReference+":"+noneReference {
+			tagged = append(tagged, reference)
+		}
+	}
+	return tagged
+}
+
+// splitReference separates the repository of a reference from its tag, the last colon after the registry host
+func splitReference(reference string) (string, string) {
+	i := strings.LastIndex(reference, ":")
+	if i < 0 || strings.Contains(reference[i:], "/") {
+		return reference, noneReference
+	}
+	return reference[:i], reference[i+1:]
+}
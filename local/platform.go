@@ -0,0 +1,51 @@
+// +build local
+
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// containerIsolation checks a container can run on the daemon OS and returns the isolation it runs with.
+// Isolation only applies to Windows containers, the daemon picks its default isolation when none is requested.
+func containerIsolation(daemonOS string, platform string, isolation string) (container.Isolation, error) {
+	if platform != "" {
+		os := strings.SplitN(platform, "/", 2)[0]
+		if !strings.EqualFold(os, daemonOS) {
+			return "", errors.Wrapf(errdefs.ErrNotImplemented, "cannot run %s containers on a %s Docker engine, switch the engine to %s containers", os, daemonOS, os)
+		}
+	}
+	if isolation == "" {
+		return "", nil
+	}
+	if daemonOS != "windows" {
+		return "", errors.Wrapf(errdefs.ErrNotImplemented, "isolation %q is only supported for Windows containers", isolation)
+	}
+	i := container.Isolation(strings.ToLower(isolation))
+	if !i.IsProcess() && !i.IsHyperV() && !i.IsDefault() {
+		return "", fmt.Errorf("invalid isolation %q, must be \"process\" or \"hyperv\"", isolation)
+	}
+	return i, nil
+}
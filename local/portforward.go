@@ -0,0 +1,84 @@
+// +build local
+
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func (ms *local) PortForward(ctx context.Context, containerID string, localPort, remotePort uint32) error {
+	c, err := ms.apiClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	ip := ""
+	if c.NetworkSettings != nil {
+		for _, network := range c.NetworkSettings.Networks {
+			if network.IPAddress != "" {
+				ip = network.IPAddress
+				break
+			}
+		}
+	}
+	if ip == "" {
+		return errors.Wrapf(errdefs.ErrNotFound, "no IP address for container %q", containerID)
+	}
+	return forward(ctx, fmt.Sprintf("127.0.0.1:%d", localPort), fmt.Sprintf("%s:%d", ip, remotePort))
+}
+
+// forward proxies TCP connections accepted on listen to target until the context is done
+func forward(ctx context.Context, listen string, target string) error {
+	listener, err := net.Listen("tcp", listen)
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		listener.Close() // nolint:errcheck
+	}()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go func(conn net.Conn) {
+			defer conn.Close() // nolint:errcheck
+			remote, err := net.Dial("tcp", target)
+			if err != nil {
+				logrus.Warnf("cannot forward connection to %s: %v", target, err)
+				return
+			}
+			defer remote.Close() // nolint:errcheck
+
+			go io.Copy(remote, conn) // nolint:errcheck
+			io.Copy(conn, remote)    // nolint:errcheck
+		}(conn)
+	}
+}
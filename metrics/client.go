@@ -23,6 +23,8 @@ import (
 	"net"
 	"net/http"
 	"time"
+
+	"github.com/docker/compose-cli/config"
 )
 
 type client struct {
@@ -72,6 +74,9 @@ func NewClient() Client {
 }
 
 func (c *client) Send(command Command) {
+	if config.IsOffline() || !config.TelemetryEnabled() {
+		return
+	}
 	result := make(chan bool, 1)
 	go func() {
 		postMetrics(command, c)
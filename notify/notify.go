@@ -0,0 +1,193 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/utils"
+)
+
+// Extension declares the notification hooks of a project, as a list of hooks with slack, url or command and events
+const Extension = "x-notifications"
+
+// hookTimeout bounds the time a hook has to complete, so that a stalled endpoint doesn't block the command
+var hookTimeout = 30 * time.Second
+
+var httpClient = &http.Client{}
+
+// Event is a lifecycle event of a project
+type Event struct {
+	Name    string    `json:"event"`
+	Project string    `json:"project"`
+	Context string    `json:"context"`
+	Error   string    `json:"error,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// NewEvent returns an event of a project, the error being set for failures
+func NewEvent(name string, project string, contextName string, err error) Event {
+	event := Event{
+		Name:    name,
+		Project: project,
+		Context: contextName,
+		Time:    time.Now(),
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	return event
+}
+
+func (e Event) message() string {
+	switch e.Name {
+	case store.UpSucceededEvent:
+		return fmt.Sprintf("%s deployed on context %s", e.Project, e.Context)
+	case store.UpFailedEvent:
+		return fmt.Sprintf("%s failed to deploy on context %s: %s", e.Project, e.Context, e.Error)
+	default:
+		return fmt.Sprintf("%s removed from context %s", e.Project, e.Context)
+	}
+}
+
+// ProjectHooks returns the hooks declared by the project x-notifications extension
+func ProjectHooks(project *types.Project) ([]store.NotificationHook, error) {
+	x, ok := project.Extensions[Extension]
+	if !ok {
+		return nil, nil
+	}
+	items, ok := x.([]interface{})
+	if !ok {
+		return nil, errors.Wrapf(errdefs.ErrParsingFailed, "%s must be a list of hooks", Extension)
+	}
+	var hooks []store.NotificationHook
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "invalid %s hook: %v", Extension, item)
+		}
+		var hook store.NotificationHook
+		for k, v := range m {
+			switch k {
+			case "slack":
+				hook.Slack = fmt.Sprint(v)
+			case "url":
+				hook.URL = fmt.Sprint(v)
+			case "command":
+				hook.Command = fmt.Sprint(v)
+			case "events":
+				events, ok := v.([]interface{})
+				if !ok {
+					return nil, errors.Wrapf(errdefs.ErrParsingFailed, "%s events must be a list", Extension)
+				}
+				for _, e := range events {
+					hook.Events = append(hook.Events, fmt.Sprint(e))
+				}
+			default:
+				return nil, errors.Wrapf(errdefs.ErrParsingFailed, "unknown %s hook attribute %q", Extension, k)
+			}
+		}
+		if err := hook.Validate(); err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, hook)
+	}
+	return hooks, nil
+}
+
+// Notify fires the hooks listening to the event, and returns the errors of the ones which failed
+func Notify(ctx context.Context, hooks []store.NotificationHook, event Event) error {
+	var errs *multierror.Error
+	for _, hook := range hooks {
+		if len(hook.Events) > 0 && !utils.StringContains(hook.Events, event.Name) {
+			continue
+		}
+		if err := fire(ctx, hook, event); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+func fire(ctx context.Context, hook store.NotificationHook, event Event) error {
+	ctx, cancel := context.WithTimeout(ctx, hookTimeout)
+	defer cancel()
+	switch {
+	case hook.Slack != "":
+		return post(ctx, hook.Slack, map[string]string{"text": event.message()})
+	case hook.URL != "":
+		return post(ctx, hook.URL, event)
+	default:
+		return run(ctx, hook.Command, event)
+	}
+}
+
+func post(ctx context.Context, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close() // nolint:errcheck
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("notification to %s returned status %d", req.URL.Host, resp.StatusCode)
+	}
+	return nil
+}
+
+func run(ctx context.Context, command string, event Event) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Env = append(os.Environ(),
+		"COMPOSE_EVENT="+event.Name,
+		"COMPOSE_PROJECT="+event.Project,
+		"COMPOSE_CONTEXT="+event.Context,
+		"COMPOSE_ERROR="+event.Error,
+	)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return errors.Wrapf(err, "notification command %q", command)
+	}
+	return nil
+}
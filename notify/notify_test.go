@@ -0,0 +1,94 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/context/store"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestProjectHooks(t *testing.T) {
+	project := &types.Project{Extensions: map[string]interface{}{
+		Extension: []interface{}{
+			map[string]interface{}{"slack": "https://hooks.slack.com/services/T/B/X", "events": []interface{}{"up.failure"}},
+			map[string]interface{}{"command": "echo deployed"},
+		},
+	}}
+	hooks, err := ProjectHooks(project)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, hooks, []store.NotificationHook{
+		{Slack: "https://hooks.slack.com/services/T/B/X", Events: []string{store.UpFailedEvent}},
+		{Command: "echo deployed"},
+	})
+
+	project.Extensions[Extension] = []interface{}{map[string]interface{}{"url": "https://example.com", "events": []interface{}{"deployed"}}}
+	_, err = ProjectHooks(project)
+	assert.Assert(t, errdefs.IsErrParsingFailed(err))
+}
+
+func TestNotify(t *testing.T) {
+	var received []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&payload))
+		received = append(received, payload)
+	}))
+	defer server.Close()
+
+	hooks := []store.NotificationHook{
+		{Slack: server.URL, Events: []string{store.UpFailedEvent}},
+		{URL: server.URL},
+	}
+	event := NewEvent(store.UpFailedEvent, "shop", "prod", errors.New("stack rolled back"))
+	assert.NilError(t, Notify(context.Background(), hooks, event))
+	assert.Equal(t, len(received), 2)
+	assert.DeepEqual(t, received[0], map[string]interface{}{"text": "shop failed to deploy on context prod: stack rolled back"})
+	assert.Equal(t, received[1]["event"], store.UpFailedEvent)
+	assert.Equal(t, received[1]["error"], "stack rolled back")
+
+	received = nil
+	assert.NilError(t, Notify(context.Background(), hooks, NewEvent(store.DownEvent, "shop", "prod", nil)))
+	assert.Equal(t, len(received), 1)
+}
+
+func TestNotifyCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook command uses sh")
+	}
+	output := filepath.Join(t.TempDir(), "event")
+	hooks := []store.NotificationHook{{Command: `echo "$COMPOSE_EVENT $COMPOSE_PROJECT" > ` + output}}
+	assert.NilError(t, Notify(context.Background(), hooks, NewEvent(store.UpSucceededEvent, "shop", "prod", nil)))
+	content, err := ioutil.ReadFile(output)
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "up.success shop\n")
+
+	err = Notify(context.Background(), []store.NotificationHook{{Command: "exit 3"}}, NewEvent(store.DownEvent, "shop", "prod", nil))
+	assert.ErrorContains(t, err, `notification command "exit 3"`)
+}
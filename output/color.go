@@ -0,0 +1,152 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package output
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/moby/term"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// NoColorEnvVar disables colors when set to any value, see https://no-color.org
+	NoColorEnvVar = "NO_COLOR"
+	// NoColorFlagName is the name of the global flag disabling colors
+	NoColorFlagName = "no-color"
+	// ThemeEnvVar is the environment variable selecting the color theme: "dark", "light", or "auto" to detect it from the
+	// terminal background
+	ThemeEnvVar = "COMPOSE_CLI_THEME"
+)
+
+// Color is an ANSI SGR code, such as "31" for red
+type Color string
+
+// Apply colors text, resetting the terminal attributes afterwards
+func (c Color) Apply(s string) string {
+	if c == "" {
+		return s
+	}
+	return "\033[" + string(c) + "m" + s + "\033[0m"
+}
+
+// Theme is the set of colors used to display the output of commands
+type Theme struct {
+	Name string
+	// Prefixes color the log prefixes, in turn for each service
+	Prefixes []Color
+	// Working, Done and Error color the progress of resources
+	Working Color
+	Done    Color
+	Error   Color
+}
+
+var (
+	// DarkTheme is readable on terminals with a dark background
+	DarkTheme = Theme{
+		Name:     "dark",
+		Prefixes: []Color{"36", "33", "32", "35", "34", "36;1", "33;1", "32;1", "35;1", "34;1"},
+		Working:  "37",
+		Done:     "34",
+		Error:    "31",
+	}
+	// LightTheme is readable on terminals with a light background, without yellow and white text
+	LightTheme = Theme{
+		Name:     "light",
+		Prefixes: []Color{"34", "35", "32", "36", "31", "34;1", "35;1", "32;1", "36;1", "31;1"},
+		Working:  "39",
+		Done:     "34",
+		Error:    "31",
+	}
+)
+
+// CurrentTheme returns the theme set with ThemeEnvVar, or the one matching the terminal background
+func CurrentTheme() Theme {
+	switch os.Getenv(ThemeEnvVar) {
+	case DarkTheme.Name:
+		return DarkTheme
+	case LightTheme.Name:
+		return LightTheme
+	}
+	if lightBackground(os.Getenv("COLORFGBG")) {
+		return LightTheme
+	}
+	return DarkTheme
+}
+
+// lightBackground reads the background color from the COLORFGBG variable set by terminals like rxvt or Konsole, "15;0"
+// meaning white on black. Backgrounds are dark when it's not set.
+func lightBackground(colorFgBg string) bool {
+	parts := strings.Split(colorFgBg, ";")
+	background, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		return false
+	}
+	return background == 7 || background > 8
+}
+
+// NoColor returns true when colors are disabled, by NO_COLOR or the --no-color flag
+func NoColor() bool {
+	return os.Getenv(NoColorEnvVar) != ""
+}
+
+// SetNoColor disables colors, in delegated commands too
+func SetNoColor() error {
+	return os.Setenv(NoColorEnvVar, "1")
+}
+
+// ColorEnabled tells whether colors are written to out: a terminal, unless colors are disabled
+func ColorEnabled(out io.Writer) bool {
+	if NoColor() {
+		return false
+	}
+	f, ok := out.(interface{ Fd() uintptr })
+	return ok && term.IsTerminal(f.Fd())
+}
+
+// AddNoColorFlag adds the --no-color flag, disabling colors whenever it's parsed
+func AddNoColorFlag(flags *pflag.FlagSet) {
+	f := flags.VarPF(&noColorValue{}, NoColorFlagName, "", "Disable colors, as NO_COLOR does")
+	f.NoOptDefVal = "true"
+}
+
+type noColorValue struct {
+	disabled bool
+}
+
+func (v *noColorValue) Set(s string) error {
+	disabled, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	v.disabled = disabled
+	if disabled {
+		return SetNoColor()
+	}
+	return nil
+}
+
+func (v *noColorValue) String() string {
+	return strconv.FormatBool(v.disabled)
+}
+
+func (v *noColorValue) Type() string {
+	return "bool"
+}
@@ -0,0 +1,61 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package output
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"gotest.tools/v3/assert"
+)
+
+func TestColorApply(t *testing.T) {
+	assert.Equal(t, Color("32").Apply("web"), "\x1b[32mweb\x1b[0m")
+	assert.Equal(t, Color("").Apply("web"), "web")
+}
+
+func TestCurrentTheme(t *testing.T) {
+	defer os.Unsetenv(ThemeEnvVar)                       // nolint:errcheck
+	defer os.Setenv("COLORFGBG", os.Getenv("COLORFGBG")) // nolint:errcheck
+
+	os.Unsetenv("COLORFGBG") // nolint:errcheck
+	assert.Equal(t, CurrentTheme().Name, "dark")
+	os.Setenv("COLORFGBG", "0;15") // nolint:errcheck
+	assert.Equal(t, CurrentTheme().Name, "light")
+	os.Setenv("COLORFGBG", "15;default;0") // nolint:errcheck
+	assert.Equal(t, CurrentTheme().Name, "dark")
+	os.Setenv(ThemeEnvVar, "light") // nolint:errcheck
+	assert.Equal(t, CurrentTheme().Name, "light")
+	os.Setenv(ThemeEnvVar, "dark") // nolint:errcheck
+	os.Setenv("COLORFGBG", "0;7")  // nolint:errcheck
+	assert.Equal(t, CurrentTheme().Name, "dark")
+}
+
+func TestNoColorFlag(t *testing.T) {
+	defer os.Unsetenv(NoColorEnvVar) // nolint:errcheck
+	os.Unsetenv(NoColorEnvVar)       // nolint:errcheck
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	AddNoColorFlag(flags)
+	assert.NilError(t, flags.Parse([]string{"--no-color=false"}))
+	assert.Assert(t, !NoColor())
+	assert.NilError(t, flags.Parse([]string{"--no-color"}))
+	assert.Assert(t, NoColor())
+	assert.Assert(t, !ColorEnabled(&bytes.Buffer{}))
+}
@@ -0,0 +1,49 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package output
+
+import (
+	"github.com/morikuni/aec"
+)
+
+// HideCursor returns the sequence hiding the terminal cursor
+func HideCursor() string {
+	return aec.Hide.String()
+}
+
+// ShowCursor returns the sequence showing the terminal cursor
+func ShowCursor() string {
+	return aec.Show.String()
+}
+
+// ClearScreen returns the sequence erasing the terminal, the cursor being moved to the top left corner
+func ClearScreen() string {
+	return aec.Position(1, 1).String() + aec.EraseDisplay(aec.EraseModes.All).String()
+}
+
+// MoveCursor returns the sequence moving the cursor up then down by some lines, to the first column, so that lines
+// written before are overwritten
+func MoveCursor(up int, down int) string {
+	b := aec.EmptyBuilder
+	for i := 0; i < up; i++ {
+		b = b.Up(1)
+	}
+	for i := 0; i < down; i++ {
+		b = b.Down(1)
+	}
+	return b.Column(0).ANSI.String()
+}
@@ -0,0 +1,150 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Types of the events streamed to programs with --json-events and the gRPC API. Their schema is stable.
+const (
+	// ResourceCreating is a resource being created or updated
+	ResourceCreating = "ResourceCreating"
+	// ResourceCreated is a resource created or updated
+	ResourceCreated = "ResourceCreated"
+	// ResourceRemoving is a resource being removed
+	ResourceRemoving = "ResourceRemoving"
+	// ResourceRemoved is a removed resource
+	ResourceRemoved = "ResourceRemoved"
+	// ServiceHealthy is a service running its desired replicas, or passing its smoke tests
+	ServiceHealthy = "ServiceHealthy"
+	// ErrorEvent is a resource which failed, or the command itself when the event has no ID
+	ErrorEvent = "Error"
+)
+
+// Operation tells whether the events of a command report resources being created or removed
+type Operation int
+
+const (
+	// Creation is the operation of commands deploying resources
+	Creation Operation = iota
+	// Removal is the operation of commands removing resources
+	Removal
+)
+
+// JSONEvent is an event streamed to programs, written as a JSON object per line
+type JSONEvent struct {
+	Type string `json:"type"`
+	// ID identifies the resource, empty for the error of the command
+	ID string `json:"id,omitempty"`
+	// Text describes the resource, such as its kind
+	Text string `json:"text,omitempty"`
+	// Status is the human readable status of the resource, or the error message
+	Status string    `json:"status,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+type jsonEventsKey struct{}
+
+type jsonEvents struct {
+	out       io.Writer
+	operation Operation
+}
+
+// WithJSONEvents makes Run write the events as JSON lines to out, instead of displaying the progress
+func WithJSONEvents(ctx context.Context, out io.Writer, operation Operation) context.Context {
+	return context.WithValue(ctx, jsonEventsKey{}, jsonEvents{out: out, operation: operation})
+}
+
+// NewEventWriter returns a writer converting events to JSONEvent for send, called by one event at a time
+func NewEventWriter(operation Operation, send func(JSONEvent)) Writer {
+	return &eventWriter{operation: operation, send: send, done: make(chan bool)}
+}
+
+func newJSONWriter(out io.Writer, operation Operation) *eventWriter {
+	encoder := json.NewEncoder(out)
+	return &eventWriter{
+		operation: operation,
+		send: func(e JSONEvent) {
+			_ = encoder.Encode(e)
+		},
+		done: make(chan bool),
+	}
+}
+
+type eventWriter struct {
+	operation Operation
+	send      func(JSONEvent)
+	mtx       sync.Mutex
+	done      chan bool
+}
+
+func (w *eventWriter) Start(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-w.done:
+		return nil
+	}
+}
+
+func (w *eventWriter) Stop() {
+	w.done <- true
+}
+
+func (w *eventWriter) Event(e Event) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.send(JSONEvent{
+		Type:   w.eventType(e),
+		ID:     e.ID,
+		Text:   e.Text,
+		Status: e.StatusText,
+		Time:   time.Now().UTC(),
+	})
+}
+
+// failed sends the error of the command, if any
+func (w *eventWriter) failed(err error) {
+	if err == nil {
+		return
+	}
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.send(JSONEvent{Type: ErrorEvent, Status: err.Error(), Time: time.Now().UTC()})
+}
+
+func (w *eventWriter) eventType(e Event) string {
+	switch {
+	case e.Status == Error:
+		return ErrorEvent
+	case e.Type != "":
+		return e.Type
+	case w.operation == Removal && e.Status == Done:
+		return ResourceRemoved
+	case w.operation == Removal:
+		return ResourceRemoving
+	case e.Status == Done:
+		return ResourceCreated
+	default:
+		return ResourceCreating
+	}
+}
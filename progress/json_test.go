@@ -0,0 +1,72 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package progress
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+)
+
+func TestEventTypes(t *testing.T) {
+	var events []JSONEvent
+	w := NewEventWriter(Creation, func(e JSONEvent) {
+		events = append(events, e)
+	})
+	w.Event(Event{ID: "web", Status: Working})
+	w.Event(Event{ID: "web", Status: Done})
+	w.Event(Event{ID: "web", Status: Done, Type: ServiceHealthy})
+	w.Event(Event{ID: "db", Status: Error})
+	w = NewEventWriter(Removal, func(e JSONEvent) {
+		events = append(events, e)
+	})
+	w.Event(Event{ID: "web", Status: Working})
+	w.Event(Event{ID: "web", Status: Done})
+
+	var types []string
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	assert.DeepEqual(t, types, []string{ResourceCreating, ResourceCreated, ServiceHealthy, ErrorEvent, ResourceRemoving, ResourceRemoved})
+}
+
+func TestRunWithJSONEvents(t *testing.T) {
+	out := &bytes.Buffer{}
+	ctx := WithJSONEvents(context.Background(), out, Creation)
+	_, err := Run(ctx, func(ctx context.Context) (string, error) {
+		ContextWriter(ctx).Event(Event{ID: "web", Status: Done})
+		return "", errors.New("deployment failed")
+	})
+	assert.Error(t, err, "deployment failed")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, len(lines), 2)
+	var created, failed map[string]interface{}
+	assert.NilError(t, json.Unmarshal([]byte(lines[0]), &created))
+	assert.NilError(t, json.Unmarshal([]byte(lines[1]), &failed))
+	assert.Equal(t, created["type"], ResourceCreated)
+	assert.Equal(t, created["id"], "web")
+	assert.Equal(t, failed["type"], ErrorEvent)
+	assert.Equal(t, failed["status"], "deployment failed")
+	_, hasID := failed["id"]
+	assert.Assert(t, !hasID)
+}
@@ -20,26 +20,100 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/compose-cli/i18n"
+	"github.com/docker/compose-cli/utils"
+)
+
+const (
+	// IntervalEnvVar is the environment variable setting how often the pending resources are reported when the
+	// output isn't a terminal, as a duration like "30s", "0" disables these status lines
+	IntervalEnvVar = "COMPOSE_PROGRESS_INTERVAL"
+
+	defaultInterval = 10 * time.Second
 )
 
 type plainWriter struct {
-	out  io.Writer
-	done chan bool
+	out      io.Writer
+	done     chan bool
+	interval time.Duration
+	mtx      sync.Mutex
+	eventIDs []string
+	events   map[string]Event
+}
+
+func newPlainWriter(out io.Writer) *plainWriter {
+	return &plainWriter{
+		out:      out,
+		done:     make(chan bool),
+		interval: statusInterval(),
+		events:   map[string]Event{},
+	}
+}
+
+func statusInterval() time.Duration {
+	value, ok := os.LookupEnv(IntervalEnvVar)
+	if !ok {
+		return defaultInterval
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return defaultInterval
+	}
+	return interval
 }
 
 func (p *plainWriter) Start(ctx context.Context) error {
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	case <-p.done:
-		return nil
+	var tick <-chan time.Time
+	if p.interval > 0 {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-p.done:
+			return nil
+		case <-tick:
+			if status := p.status(); status != "" {
+				fmt.Fprintln(p.out, status)
+			}
+		}
 	}
 }
 
 func (p *plainWriter) Event(e Event) {
+	p.mtx.Lock()
+	if !utils.StringContains(p.eventIDs, e.ID) {
+		p.eventIDs = append(p.eventIDs, e.ID)
+	}
+	p.events[e.ID] = e
+	p.mtx.Unlock()
 	fmt.Println(e.ID, e.Text, e.StatusText)
 }
 
+// status summarizes the resources still pending, so long waits show up in CI logs
+func (p *plainWriter) status() string {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	var pending []string
+	for _, id := range p.eventIDs {
+		if p.events[id].Status == Working {
+			pending = append(pending, id)
+		}
+	}
+	if len(pending) == 0 {
+		return ""
+	}
+	return i18n.T("progress.running", len(p.eventIDs)-len(pending), len(p.eventIDs)) + i18n.T("progress.pending", strings.Join(pending, ", "))
+}
+
 func (p *plainWriter) Stop() {
 	p.done <- true
 }
@@ -0,0 +1,36 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package progress
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestPlainStatus(t *testing.T) {
+	w := newPlainWriter(nil)
+	assert.Equal(t, w.status(), "")
+
+	w.Event(Event{ID: "Cluster", Status: Working})
+	w.Event(Event{ID: "Service", Status: Working})
+	w.Event(Event{ID: "Cluster", Status: Done})
+	assert.Equal(t, w.status(), "[+] Running 1/2, pending: Service")
+
+	w.Event(Event{ID: "Service", Status: Error})
+	assert.Equal(t, w.status(), "")
+}
@@ -0,0 +1,40 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package progress
+
+import (
+	"context"
+)
+
+// WithPrefix returns a context whose writer prefixes event IDs, to tell apart the events of operations
+// running concurrently on resources with the same names
+func WithPrefix(ctx context.Context, prefix string) context.Context {
+	return WithContextWriter(ctx, prefixWriter{
+		Writer: ContextWriter(ctx),
+		prefix: prefix,
+	})
+}
+
+type prefixWriter struct {
+	Writer
+	prefix string
+}
+
+func (w prefixWriter) Event(e Event) {
+	e.ID = w.prefix + e.ID
+	w.Writer.Event(e)
+}
@@ -0,0 +1,166 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/compose-cli/config"
+	apicontext "github.com/docker/compose-cli/context"
+)
+
+// summaryThreshold is the run duration from which a timing summary is displayed, quicker runs don't need one
+const summaryThreshold = 10 * time.Second
+
+// history is the duration each resource took the last time it reached a final status, used to estimate how long
+// the resources still working will take. It is cached per context as resources with the same ID usually take the
+// same time on the same backend.
+type history map[string]time.Duration
+
+func historyPath(ctx context.Context) string {
+	dir := config.Dir(ctx)
+	currentContext := apicontext.CurrentContext(ctx)
+	if dir == "" || currentContext == "" {
+		return ""
+	}
+	return filepath.Join(dir, "timings", currentContext+".json")
+}
+
+func loadHistory(path string) history {
+	h := history{}
+	if path == "" {
+		return h
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return h
+	}
+	_ = json.Unmarshal(b, &h)
+	return h
+}
+
+// save writes the durations recorded by this command over the history, keeping the ones saved meanwhile by
+// concurrent commands
+func (h history) save(path string) error {
+	if path == "" || len(h) == 0 {
+		return nil
+	}
+	merged := loadHistory(path)
+	for id, d := range h {
+		merged[id] = d
+	}
+	b, err := json.Marshal(merged)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".new")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // nolint:errcheck
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close() // nolint:errcheck
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+type timing struct {
+	id     string
+	action string
+	start  time.Time
+	end    time.Time
+}
+
+// timingWriter records how long each resource took to reach its final status
+type timingWriter struct {
+	Writer
+	mu      sync.Mutex
+	ids     []string
+	timings map[string]*timing
+}
+
+func newTimingWriter(w Writer) *timingWriter {
+	return &timingWriter{
+		Writer:  w,
+		timings: map[string]*timing{},
+	}
+}
+
+func (w *timingWriter) Event(e Event) {
+	w.mu.Lock()
+	t, ok := w.timings[e.ID]
+	if !ok {
+		t = &timing{id: e.ID, start: time.Now()}
+		w.timings[e.ID] = t
+		w.ids = append(w.ids, e.ID)
+	}
+	t.action = e.StatusText
+	if e.Status == Working {
+		t.end = time.Time{}
+	} else if t.end.IsZero() {
+		t.end = time.Now()
+	}
+	w.mu.Unlock()
+	w.Writer.Event(e)
+}
+
+func (w *timingWriter) finished() []timing {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var finished []timing
+	for _, id := range w.ids {
+		if t := w.timings[id]; !t.end.IsZero() {
+			finished = append(finished, *t)
+		}
+	}
+	return finished
+}
+
+func (w *timingWriter) record(h history) {
+	for _, t := range w.finished() {
+		h[t.id] = t.end.Sub(t.start)
+	}
+}
+
+func (w *timingWriter) summary(out io.Writer) {
+	finished := w.finished()
+	if len(finished) == 0 {
+		return
+	}
+	tw := tabwriter.NewWriter(out, 20, 1, 3, ' ', 0)
+	fmt.Fprintln(tw, "RESOURCE\tACTION\tDURATION")
+	for _, t := range finished {
+		fmt.Fprintf(tw, "%s\t%s\t%.1fs\n", t.id, t.action, t.end.Sub(t.start).Seconds())
+	}
+	_ = tw.Flush()
+}
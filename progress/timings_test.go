@@ -0,0 +1,71 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package progress
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestTimingSummary(t *testing.T) {
+	w := newTimingWriter(&noopWriter{})
+	w.Event(Event{ID: "Cluster", Status: Working, StatusText: "CREATE_IN_PROGRESS"})
+	w.Event(Event{ID: "Service", Status: Working, StatusText: "CREATE_IN_PROGRESS"})
+	w.Event(Event{ID: "Cluster", Status: Done, StatusText: "CREATE_COMPLETE"})
+
+	h := history{}
+	w.record(h)
+	_, ok := h["Cluster"]
+	assert.Assert(t, ok)
+	_, ok = h["Service"]
+	assert.Assert(t, !ok)
+
+	var b bytes.Buffer
+	w.summary(&b)
+	assert.Equal(t, b.String(), "RESOURCE            ACTION              DURATION\nCluster             CREATE_COMPLETE     0.0s\n")
+}
+
+func TestHistory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "timings")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir) // nolint:errcheck
+
+	path := filepath.Join(dir, "timings", "ecs.json")
+	assert.DeepEqual(t, loadHistory(path), history{})
+	assert.NilError(t, history{"Service": 3 * time.Minute}.save(path))
+	// saved by a concurrent command
+	assert.NilError(t, history{"Cluster": time.Minute}.save(path))
+	h := loadHistory(path)
+	assert.DeepEqual(t, h, history{"Service": 3 * time.Minute, "Cluster": time.Minute})
+
+	w := &ttyWriter{
+		events: map[string]Event{
+			"Service": {ID: "Service", Status: Working, startTime: time.Now().Add(-time.Minute)},
+			"Cluster": {ID: "Cluster", Status: Done, startTime: time.Now().Add(-time.Minute)},
+		},
+		mtx:     &sync.RWMutex{},
+		history: h,
+	}
+	assert.Equal(t, w.eta().Round(time.Minute), 2*time.Minute)
+}
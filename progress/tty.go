@@ -25,10 +25,11 @@ import (
 	"sync"
 	"time"
 
+	"github.com/docker/compose-cli/i18n"
+	"github.com/docker/compose-cli/output"
 	"github.com/docker/compose-cli/utils"
 
 	"github.com/buger/goterm"
-	"github.com/morikuni/aec"
 )
 
 type ttyWriter struct {
@@ -39,6 +40,7 @@ type ttyWriter struct {
 	numLines int
 	done     chan bool
 	mtx      *sync.RWMutex
+	history  history
 }
 
 func (w *ttyWriter) Start(ctx context.Context) error {
@@ -94,23 +96,29 @@ func (w *ttyWriter) print() {
 		return
 	}
 	terminalWidth := goterm.Width()
-	b := aec.EmptyBuilder
-	for i := 0; i <= w.numLines; i++ {
-		b = b.Up(1)
-	}
+	down := 0
 	if !w.repeated {
-		b = b.Down(1)
+		down = 1
 	}
 	w.repeated = true
-	fmt.Fprint(w.out, b.Column(0).ANSI)
+	fmt.Fprint(w.out, output.MoveCursor(w.numLines+1, down))
 
 	// Hide the cursor while we are printing
-	fmt.Fprint(w.out, aec.Hide)
-	defer fmt.Fprint(w.out, aec.Show)
+	fmt.Fprint(w.out, output.HideCursor())
+	defer fmt.Fprint(w.out, output.ShowCursor())
+
+	var theme *output.Theme
+	if !output.NoColor() && runtime.GOOS != "windows" {
+		current := output.CurrentTheme()
+		theme = &current
+	}
 
-	firstLine := fmt.Sprintf("[+] Running %d/%d", numDone(w.events), w.numLines)
-	if w.numLines != 0 && numDone(w.events) == w.numLines {
-		firstLine = aec.Apply(firstLine, aec.BlueF)
+	firstLine := i18n.T("progress.running", numDone(w.events), w.numLines)
+	if eta := w.eta(); eta > 0 {
+		firstLine += i18n.T("progress.eta", eta.Round(time.Second))
+	}
+	if theme != nil && w.numLines != 0 && numDone(w.events) == w.numLines {
+		firstLine = theme.Done.Apply(firstLine)
 	}
 	fmt.Fprintln(w.out, firstLine)
 
@@ -124,7 +132,7 @@ func (w *ttyWriter) print() {
 
 	numLines := 0
 	for _, v := range w.eventIDs {
-		line := lineText(w.events[v], terminalWidth, statusPadding, runtime.GOOS != "windows")
+		line := lineText(w.events[v], terminalWidth, statusPadding, theme)
 		// nolint: errcheck
 		fmt.Fprint(w.out, line)
 		numLines++
@@ -133,7 +141,8 @@ func (w *ttyWriter) print() {
 	w.numLines = numLines
 }
 
-func lineText(event Event, terminalWidth, statusPadding int, color bool) string {
+// lineText formats the progress of a resource, colored with theme when set
+func lineText(event Event, terminalWidth, statusPadding int, theme *output.Theme) string {
 	endTime := time.Now()
 	if event.Status != Working {
 		endTime = event.endTime
@@ -156,20 +165,36 @@ func lineText(event Event, terminalWidth, statusPadding int, color bool) string
 	timer := fmt.Sprintf("%.1fs\n", elapsed)
 	o := align(text, timer, terminalWidth)
 
-	if color {
-		color := aec.WhiteF
+	if theme != nil {
+		color := theme.Working
 		if event.Status == Done {
-			color = aec.BlueF
+			color = theme.Done
 		}
 		if event.Status == Error {
-			color = aec.RedF
+			color = theme.Error
 		}
-		return aec.Apply(o, color)
+		return color.Apply(o)
 	}
 
 	return o
 }
 
+// eta estimates the time left from the duration the resources still working took the last time
+func (w *ttyWriter) eta() time.Duration {
+	var eta time.Duration
+	for _, e := range w.events {
+		if e.Status != Working {
+			continue
+		}
+		if d, ok := w.history[e.ID]; ok {
+			if left := d - time.Since(e.startTime); left > eta {
+				eta = left
+			}
+		}
+	}
+	return eta
+}
+
 func numDone(events map[string]Event) int {
 	i := 0
 	for _, e := range events {
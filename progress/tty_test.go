@@ -23,6 +23,8 @@ import (
 	"time"
 
 	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/output"
 )
 
 func TestLineText(t *testing.T) {
@@ -41,19 +43,23 @@ func TestLineText(t *testing.T) {
 
 	lineWidth := len(fmt.Sprintf("%s %s", ev.ID, ev.Text))
 
-	out := lineText(ev, 50, lineWidth, true)
+	out := lineText(ev, 50, lineWidth, &output.DarkTheme)
 	assert.Equal(t, out, "\x1b[37m . id Text Status                            0.0s\n\x1b[0m")
 
-	out = lineText(ev, 50, lineWidth, false)
+	out = lineText(ev, 50, lineWidth, nil)
 	assert.Equal(t, out, " . id Text Status                            0.0s\n")
 
 	ev.Status = Done
-	out = lineText(ev, 50, lineWidth, true)
+	out = lineText(ev, 50, lineWidth, &output.DarkTheme)
 	assert.Equal(t, out, "\x1b[34m . id Text Status                            0.0s\n\x1b[0m")
 
 	ev.Status = Error
-	out = lineText(ev, 50, lineWidth, true)
+	out = lineText(ev, 50, lineWidth, &output.DarkTheme)
 	assert.Equal(t, out, "\x1b[31m . id Text Status                            0.0s\n\x1b[0m")
+
+	ev.Status = Working
+	out = lineText(ev, 50, lineWidth, &output.LightTheme)
+	assert.Equal(t, out, "\x1b[39m . id Text Status                            0.0s\n\x1b[0m")
 }
 
 func TestErrorEvent(t *testing.T) {
@@ -24,6 +24,7 @@ import (
 
 	"github.com/containerd/console"
 	"github.com/moby/term"
+	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -46,6 +47,8 @@ type Event struct {
 	Status     EventStatus
 	StatusText string
 	Done       bool
+	// Type overrides the type of the event streamed to programs, derived from its status otherwise
+	Type string
 
 	startTime time.Time
 	endTime   time.Time
@@ -86,16 +89,34 @@ type progressFunc func(context.Context) (string, error)
 // in parallel
 func Run(ctx context.Context, pf progressFunc) (string, error) {
 	eg, _ := errgroup.WithContext(ctx)
-	w, err := NewWriter(os.Stderr)
-	var result string
-	if err != nil {
-		return "", err
+	var (
+		w          Writer
+		jsonWriter *eventWriter
+		result     string
+		err        error
+	)
+	events, streaming := ctx.Value(jsonEventsKey{}).(jsonEvents)
+	if streaming {
+		jsonWriter = newJSONWriter(events.out, events.operation)
+		w = jsonWriter
+	} else {
+		w, err = NewWriter(os.Stderr)
+		if err != nil {
+			return "", err
+		}
 	}
+	start := time.Now()
+	path := historyPath(ctx)
+	h := loadHistory(path)
+	if tty, ok := w.(*ttyWriter); ok {
+		tty.history = h
+	}
+	tw := newTimingWriter(w)
 	eg.Go(func() error {
 		return w.Start(context.Background())
 	})
 
-	ctx = WithContextWriter(ctx, w)
+	ctx = WithContextWriter(ctx, tw)
 
 	eg.Go(func() error {
 		defer w.Stop()
@@ -107,12 +128,33 @@ func Run(ctx context.Context, pf progressFunc) (string, error) {
 	})
 
 	err = eg.Wait()
+	if streaming {
+		jsonWriter.failed(err)
+	}
+	recorded := history{}
+	tw.record(recorded)
+	if saveErr := recorded.save(path); saveErr != nil {
+		logrus.Debugf("cannot save progress timings: %v", saveErr)
+	}
+	if !streaming && time.Since(start) >= summaryThreshold {
+		tw.summary(os.Stderr)
+	}
 	return result, err
 }
 
+// ModeEnvVar is the environment variable selecting the progress display: "tty", "plain", or "auto" to display the
+// progress on a terminal when the output is one
+const ModeEnvVar = "COMPOSE_CLI_PROGRESS"
+
 // NewWriter returns a new multi-progress writer
 func NewWriter(out console.File) (Writer, error) {
 	_, isTerminal := term.GetFdInfo(out)
+	switch os.Getenv(ModeEnvVar) {
+	case "tty":
+		isTerminal = true
+	case "plain":
+		isTerminal = false
+	}
 
 	if isTerminal {
 		con, err := console.ConsoleFromFile(out)
@@ -130,8 +172,5 @@ func NewWriter(out console.File) (Writer, error) {
 		}, nil
 	}
 
-	return &plainWriter{
-		out:  out,
-		done: make(chan bool),
-	}, nil
+	return newPlainWriter(out), nil
 }
@@ -0,0 +1,45 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package prompt
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/moby/term"
+	"github.com/pkg/errors"
+)
+
+// ErrNotATerminal is returned when a secret can't be asked for as the input isn't a terminal
+var ErrNotATerminal = errors.New("cannot prompt for a secret, the input is not a terminal")
+
+// ReadSecret reads a secret from the input when fromStdin is set, otherwise asks the user for it without echoing it
+func ReadSecret(ui UI, in io.Reader, message string, fromStdin bool) (string, error) {
+	if fromStdin {
+		b, err := ioutil.ReadAll(in)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(b), "\r\n"), nil
+	}
+	if f, ok := in.(*os.File); ok && !term.IsTerminal(f.Fd()) {
+		return "", ErrNotATerminal
+	}
+	return ui.Password(message)
+}
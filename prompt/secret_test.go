@@ -0,0 +1,45 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package prompt
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+type passwordUI struct {
+	UI
+	password string
+}
+
+func (p passwordUI) Password(message string) (string, error) {
+	return p.password, nil
+}
+
+func TestReadSecretFromStdin(t *testing.T) {
+	secret, err := ReadSecret(passwordUI{}, strings.NewReader("s3cr3t\n"), "Password", true)
+	assert.NilError(t, err)
+	assert.Equal(t, secret, "s3cr3t")
+}
+
+func TestReadSecretPrompts(t *testing.T) {
+	secret, err := ReadSecret(passwordUI{password: "typed"}, strings.NewReader(""), "Password", false)
+	assert.NilError(t, err)
+	assert.Equal(t, secret, "typed")
+}
@@ -52,6 +52,7 @@ type ComposeUpRequest struct {
 	ProjectName string   `protobuf:"bytes,1,opt,name=projectName,proto3" json:"projectName,omitempty"`
 	WorkDir     string   `protobuf:"bytes,2,opt,name=workDir,proto3" json:"workDir,omitempty"`
 	Files       []string `protobuf:"bytes,3,rep,name=files,proto3" json:"files,omitempty"`
+	ContextName string   `protobuf:"bytes,4,opt,name=contextName,proto3" json:"contextName,omitempty"`
 }
 
 func (x *ComposeUpRequest) Reset() {
@@ -107,14 +108,26 @@ func (x *ComposeUpRequest) GetFiles() []string {
 	return nil
 }
 
-type ComposeUpResponse struct {
+func (x *ComposeUpRequest) GetContextName() string {
+	if x != nil {
+		return x.ContextName
+	}
+	return ""
+}
+
+type ComposeDownRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	ContextName string   `protobuf:"bytes,1,opt,name=contextName,proto3" json:"contextName,omitempty"`
+	ProjectName string   `protobuf:"bytes,2,opt,name=projectName,proto3" json:"projectName,omitempty"`
+	WorkDir     string   `protobuf:"bytes,3,opt,name=workDir,proto3" json:"workDir,omitempty"`
+	Files       []string `protobuf:"bytes,4,rep,name=files,proto3" json:"files,omitempty"`
 }
 
-func (x *ComposeUpResponse) Reset() {
-	*x = ComposeUpResponse{}
+func (x *ComposeDownRequest) Reset() {
+	*x = ComposeDownRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_protos_compose_v1_compose_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -122,13 +135,13 @@ func (x *ComposeUpResponse) Reset() {
 	}
 }
 
-func (x *ComposeUpResponse) String() string {
+func (x *ComposeDownRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ComposeUpResponse) ProtoMessage() {}
+func (*ComposeDownRequest) ProtoMessage() {}
 
-func (x *ComposeUpResponse) ProtoReflect() protoreflect.Message {
+func (x *ComposeDownRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_protos_compose_v1_compose_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -140,19 +153,58 @@ func (x *ComposeUpResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ComposeUpResponse.ProtoReflect.Descriptor instead.
-func (*ComposeUpResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ComposeDownRequest.ProtoReflect.Descriptor instead.
+func (*ComposeDownRequest) Descriptor() ([]byte, []int) {
 	return file_protos_compose_v1_compose_proto_rawDescGZIP(), []int{1}
 }
 
-type ComposeDownRequest struct {
+func (x *ComposeDownRequest) GetContextName() string {
+	if x != nil {
+		return x.ContextName
+	}
+	return ""
+}
+
+func (x *ComposeDownRequest) GetProjectName() string {
+	if x != nil {
+		return x.ProjectName
+	}
+	return ""
+}
+
+func (x *ComposeDownRequest) GetWorkDir() string {
+	if x != nil {
+		return x.WorkDir
+	}
+	return ""
+}
+
+func (x *ComposeDownRequest) GetFiles() []string {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+// ComposeEvent has the stable schema of the events written by compose up and down with --json-events
+type ComposeEvent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// type is one of ResourceCreating, ResourceCreated, ResourceRemoving, ResourceRemoved, ServiceHealthy or Error
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	// id identifies the resource, empty for the error of the operation
+	Id   string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Text string `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	// status is the human readable status of the resource, or the error message
+	Status string `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	// time is the RFC 3339 time of the event
+	Time string `protobuf:"bytes,5,opt,name=time,proto3" json:"time,omitempty"`
 }
 
-func (x *ComposeDownRequest) Reset() {
-	*x = ComposeDownRequest{}
+func (x *ComposeEvent) Reset() {
+	*x = ComposeEvent{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_protos_compose_v1_compose_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -160,13 +212,13 @@ func (x *ComposeDownRequest) Reset() {
 	}
 }
 
-func (x *ComposeDownRequest) String() string {
+func (x *ComposeEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ComposeDownRequest) ProtoMessage() {}
+func (*ComposeEvent) ProtoMessage() {}
 
-func (x *ComposeDownRequest) ProtoReflect() protoreflect.Message {
+func (x *ComposeEvent) ProtoReflect() protoreflect.Message {
 	mi := &file_protos_compose_v1_compose_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -178,19 +230,59 @@ func (x *ComposeDownRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ComposeDownRequest.ProtoReflect.Descriptor instead.
-func (*ComposeDownRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ComposeEvent.ProtoReflect.Descriptor instead.
+func (*ComposeEvent) Descriptor() ([]byte, []int) {
 	return file_protos_compose_v1_compose_proto_rawDescGZIP(), []int{2}
 }
 
-type ComposeDownResponse struct {
+func (x *ComposeEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ComposeEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ComposeEvent) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *ComposeEvent) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ComposeEvent) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+type ComposeValidateRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	ContextName string   `protobuf:"bytes,1,opt,name=contextName,proto3" json:"contextName,omitempty"`
+	ProjectName string   `protobuf:"bytes,2,opt,name=projectName,proto3" json:"projectName,omitempty"`
+	WorkDir     string   `protobuf:"bytes,3,opt,name=workDir,proto3" json:"workDir,omitempty"`
+	Files       []string `protobuf:"bytes,4,rep,name=files,proto3" json:"files,omitempty"`
 }
 
-func (x *ComposeDownResponse) Reset() {
-	*x = ComposeDownResponse{}
+func (x *ComposeValidateRequest) Reset() {
+	*x = ComposeValidateRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_protos_compose_v1_compose_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -198,13 +290,13 @@ func (x *ComposeDownResponse) Reset() {
 	}
 }
 
-func (x *ComposeDownResponse) String() string {
+func (x *ComposeValidateRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ComposeDownResponse) ProtoMessage() {}
+func (*ComposeValidateRequest) ProtoMessage() {}
 
-func (x *ComposeDownResponse) ProtoReflect() protoreflect.Message {
+func (x *ComposeValidateRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_protos_compose_v1_compose_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -216,11 +308,141 @@ func (x *ComposeDownResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ComposeDownResponse.ProtoReflect.Descriptor instead.
-func (*ComposeDownResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ComposeValidateRequest.ProtoReflect.Descriptor instead.
+func (*ComposeValidateRequest) Descriptor() ([]byte, []int) {
 	return file_protos_compose_v1_compose_proto_rawDescGZIP(), []int{3}
 }
 
+func (x *ComposeValidateRequest) GetContextName() string {
+	if x != nil {
+		return x.ContextName
+	}
+	return ""
+}
+
+func (x *ComposeValidateRequest) GetProjectName() string {
+	if x != nil {
+		return x.ProjectName
+	}
+	return ""
+}
+
+func (x *ComposeValidateRequest) GetWorkDir() string {
+	if x != nil {
+		return x.WorkDir
+	}
+	return ""
+}
+
+func (x *ComposeValidateRequest) GetFiles() []string {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+type ComposeValidateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Diagnostics []*Diagnostic `protobuf:"bytes,1,rep,name=diagnostics,proto3" json:"diagnostics,omitempty"`
+}
+
+func (x *ComposeValidateResponse) Reset() {
+	*x = ComposeValidateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_compose_v1_compose_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ComposeValidateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComposeValidateResponse) ProtoMessage() {}
+
+func (x *ComposeValidateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_compose_v1_compose_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComposeValidateResponse.ProtoReflect.Descriptor instead.
+func (*ComposeValidateResponse) Descriptor() ([]byte, []int) {
+	return file_protos_compose_v1_compose_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ComposeValidateResponse) GetDiagnostics() []*Diagnostic {
+	if x != nil {
+		return x.Diagnostics
+	}
+	return nil
+}
+
+type Diagnostic struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Severity string `protobuf:"bytes,1,opt,name=severity,proto3" json:"severity,omitempty"`
+	Message  string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *Diagnostic) Reset() {
+	*x = Diagnostic{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_protos_compose_v1_compose_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Diagnostic) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Diagnostic) ProtoMessage() {}
+
+func (x *Diagnostic) ProtoReflect() protoreflect.Message {
+	mi := &file_protos_compose_v1_compose_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Diagnostic.ProtoReflect.Descriptor instead.
+func (*Diagnostic) Descriptor() ([]byte, []int) {
+	return file_protos_compose_v1_compose_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Diagnostic) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *Diagnostic) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
 var File_protos_compose_v1_compose_proto protoreflect.FileDescriptor
 
 var file_protos_compose_v1_compose_proto_rawDesc = []byte{
@@ -228,36 +450,77 @@ var file_protos_compose_v1_compose_proto_rawDesc = []byte{
 	0x2f, 0x76, 0x31, 0x2f, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
 	0x6f, 0x12, 0x20, 0x63, 0x6f, 0x6d, 0x2e, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x61, 0x70,
 	0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65,
-	0x2e, 0x76, 0x31, 0x22, 0x64, 0x0a, 0x10, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x55, 0x70,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x72, 0x6f, 0x6a, 0x65,
-	0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72,
-	0x6f, 0x6a, 0x65, 0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x77, 0x6f, 0x72,
-	0x6b, 0x44, 0x69, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x77, 0x6f, 0x72, 0x6b,
-	0x44, 0x69, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03,
-	0x28, 0x09, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x22, 0x13, 0x0a, 0x11, 0x43, 0x6f, 0x6d,
-	0x70, 0x6f, 0x73, 0x65, 0x55, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x14,
-	0x0a, 0x12, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x44, 0x6f, 0x77, 0x6e, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x22, 0x15, 0x0a, 0x13, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x44,
-	0x6f, 0x77, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0xed, 0x01, 0x0a, 0x07,
-	0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x12, 0x6d, 0x0a, 0x02, 0x55, 0x70, 0x12, 0x32, 0x2e,
+	0x2e, 0x76, 0x31, 0x22, 0x86, 0x01, 0x0a, 0x10, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x55,
+	0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x72, 0x6f, 0x6a,
+	0x65, 0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70,
+	0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x77, 0x6f,
+	0x72, 0x6b, 0x44, 0x69, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x77, 0x6f, 0x72,
+	0x6b, 0x44, 0x69, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x6f,
+	0x6e, 0x74, 0x65, 0x78, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x88, 0x01, 0x0a,
+	0x12, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x44, 0x6f, 0x77, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x4e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78,
+	0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74,
+	0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x6a,
+	0x65, 0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x77, 0x6f, 0x72, 0x6b, 0x44,
+	0x69, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x77, 0x6f, 0x72, 0x6b, 0x44, 0x69,
+	0x72, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x22, 0x72, 0x0a, 0x0c, 0x43, 0x6f, 0x6d, 0x70, 0x6f,
+	0x73, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74,
+	0x65, 0x78, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12,
+	0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x22, 0x8c, 0x01, 0x0a, 0x16,
+	0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78,
+	0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e,
+	0x74, 0x65, 0x78, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x72, 0x6f, 0x6a,
+	0x65, 0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70,
+	0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x77, 0x6f,
+	0x72, 0x6b, 0x44, 0x69, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x77, 0x6f, 0x72,
+	0x6b, 0x44, 0x69, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x04, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x22, 0x69, 0x0a, 0x17, 0x43, 0x6f,
+	0x6d, 0x70, 0x6f, 0x73, 0x65, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4e, 0x0a, 0x0b, 0x64, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73,
+	0x74, 0x69, 0x63, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2e, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x69,
+	0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x52, 0x0b, 0x64, 0x69, 0x61, 0x67, 0x6e, 0x6f,
+	0x73, 0x74, 0x69, 0x63, 0x73, 0x22, 0x42, 0x0a, 0x0a, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73,
+	0x74, 0x69, 0x63, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x12,
+	0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x32, 0xe6, 0x02, 0x0a, 0x07, 0x43, 0x6f,
+	0x6d, 0x70, 0x6f, 0x73, 0x65, 0x12, 0x6a, 0x0a, 0x02, 0x55, 0x70, 0x12, 0x32, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2e, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43,
+	0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x55, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x2e, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30,
+	0x01, 0x12, 0x6e, 0x0a, 0x04, 0x44, 0x6f, 0x77, 0x6e, 0x12, 0x34, 0x2e, 0x63, 0x6f, 0x6d, 0x2e,
+	0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d,
+	0x70, 0x6f, 0x73, 0x65, 0x44, 0x6f, 0x77, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x2e, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30,
+	0x01, 0x12, 0x7f, 0x0a, 0x08, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x12, 0x38, 0x2e,
 	0x63, 0x6f, 0x6d, 0x2e, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x2e, 0x76, 0x31,
-	0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x55, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x33, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x61,
-	0x70, 0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x73,
-	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x55, 0x70, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x73, 0x0a, 0x04, 0x44, 0x6f, 0x77, 0x6e, 0x12, 0x34,
-	0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x61, 0x70, 0x69, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x2e, 0x76,
-	0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x44, 0x6f, 0x77, 0x6e, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x35, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x64, 0x6f, 0x63, 0x6b, 0x65,
-	0x72, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e, 0x63, 0x6f, 0x6d,
-	0x70, 0x6f, 0x73, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x44,
-	0x6f, 0x77, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x34, 0x5a, 0x32, 0x67,
-	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72,
-	0x2f, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x2d, 0x63, 0x6c, 0x69, 0x2f, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x73, 0x2f, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x2f, 0x76, 0x31, 0x3b, 0x76,
-	0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x39, 0x2e, 0x63, 0x6f, 0x6d, 0x2e, 0x64, 0x6f,
+	0x63, 0x6b, 0x65, 0x72, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2e,
+	0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x6f,
+	0x73, 0x65, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x42, 0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x2f, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x73, 0x65, 0x2d,
+	0x63, 0x6c, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x63, 0x6f, 0x6d, 0x70, 0x6f,
+	0x73, 0x65, 0x2f, 0x76, 0x31, 0x3b, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -272,23 +535,28 @@ func file_protos_compose_v1_compose_proto_rawDescGZIP() []byte {
 	return file_protos_compose_v1_compose_proto_rawDescData
 }
 
-var file_protos_compose_v1_compose_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_protos_compose_v1_compose_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_protos_compose_v1_compose_proto_goTypes = []interface{}{
-	(*ComposeUpRequest)(nil),    // 0: com.docker.api.protos.compose.v1.ComposeUpRequest
-	(*ComposeUpResponse)(nil),   // 1: com.docker.api.protos.compose.v1.ComposeUpResponse
-	(*ComposeDownRequest)(nil),  // 2: com.docker.api.protos.compose.v1.ComposeDownRequest
-	(*ComposeDownResponse)(nil), // 3: com.docker.api.protos.compose.v1.ComposeDownResponse
+	(*ComposeUpRequest)(nil),        // 0: com.docker.api.protos.compose.v1.ComposeUpRequest
+	(*ComposeDownRequest)(nil),      // 1: com.docker.api.protos.compose.v1.ComposeDownRequest
+	(*ComposeEvent)(nil),            // 2: com.docker.api.protos.compose.v1.ComposeEvent
+	(*ComposeValidateRequest)(nil),  // 3: com.docker.api.protos.compose.v1.ComposeValidateRequest
+	(*ComposeValidateResponse)(nil), // 4: com.docker.api.protos.compose.v1.ComposeValidateResponse
+	(*Diagnostic)(nil),              // 5: com.docker.api.protos.compose.v1.Diagnostic
 }
 var file_protos_compose_v1_compose_proto_depIdxs = []int32{
-	0, // 0: com.docker.api.protos.compose.v1.Compose.Up:input_type -> com.docker.api.protos.compose.v1.ComposeUpRequest
-	2, // 1: com.docker.api.protos.compose.v1.Compose.Down:input_type -> com.docker.api.protos.compose.v1.ComposeDownRequest
-	1, // 2: com.docker.api.protos.compose.v1.Compose.Up:output_type -> com.docker.api.protos.compose.v1.ComposeUpResponse
-	3, // 3: com.docker.api.protos.compose.v1.Compose.Down:output_type -> com.docker.api.protos.compose.v1.ComposeDownResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	5, // 0: com.docker.api.protos.compose.v1.ComposeValidateResponse.diagnostics:type_name -> com.docker.api.protos.compose.v1.Diagnostic
+	0, // 1: com.docker.api.protos.compose.v1.Compose.Up:input_type -> com.docker.api.protos.compose.v1.ComposeUpRequest
+	1, // 2: com.docker.api.protos.compose.v1.Compose.Down:input_type -> com.docker.api.protos.compose.v1.ComposeDownRequest
+	3, // 3: com.docker.api.protos.compose.v1.Compose.Validate:input_type -> com.docker.api.protos.compose.v1.ComposeValidateRequest
+	2, // 4: com.docker.api.protos.compose.v1.Compose.Up:output_type -> com.docker.api.protos.compose.v1.ComposeEvent
+	2, // 5: com.docker.api.protos.compose.v1.Compose.Down:output_type -> com.docker.api.protos.compose.v1.ComposeEvent
+	4, // 6: com.docker.api.protos.compose.v1.Compose.Validate:output_type -> com.docker.api.protos.compose.v1.ComposeValidateResponse
+	4, // [4:7] is the sub-list for method output_type
+	1, // [1:4] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_protos_compose_v1_compose_proto_init() }
@@ -310,7 +578,7 @@ func file_protos_compose_v1_compose_proto_init() {
 			}
 		}
 		file_protos_compose_v1_compose_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ComposeUpResponse); i {
+			switch v := v.(*ComposeDownRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -322,7 +590,7 @@ func file_protos_compose_v1_compose_proto_init() {
 			}
 		}
 		file_protos_compose_v1_compose_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ComposeDownRequest); i {
+			switch v := v.(*ComposeEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -334,7 +602,31 @@ func file_protos_compose_v1_compose_proto_init() {
 			}
 		}
 		file_protos_compose_v1_compose_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ComposeDownResponse); i {
+			switch v := v.(*ComposeValidateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_compose_v1_compose_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ComposeValidateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_protos_compose_v1_compose_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Diagnostic); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -352,7 +644,7 @@ func file_protos_compose_v1_compose_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_protos_compose_v1_compose_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   6,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -378,8 +670,11 @@ const _ = grpc.SupportPackageIsVersion6
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type ComposeClient interface {
-	Up(ctx context.Context, in *ComposeUpRequest, opts ...grpc.CallOption) (*ComposeUpResponse, error)
-	Down(ctx context.Context, in *ComposeDownRequest, opts ...grpc.CallOption) (*ComposeDownResponse, error)
+	// Up deploys a project, streaming the progress events until it is deployed
+	Up(ctx context.Context, in *ComposeUpRequest, opts ...grpc.CallOption) (Compose_UpClient, error)
+	// Down removes a project, streaming the progress events until it is removed
+	Down(ctx context.Context, in *ComposeDownRequest, opts ...grpc.CallOption) (Compose_DownClient, error)
+	Validate(ctx context.Context, in *ComposeValidateRequest, opts ...grpc.CallOption) (*ComposeValidateResponse, error)
 }
 
 type composeClient struct {
@@ -390,18 +685,73 @@ func NewComposeClient(cc grpc.ClientConnInterface) ComposeClient {
 	return &composeClient{cc}
 }
 
-func (c *composeClient) Up(ctx context.Context, in *ComposeUpRequest, opts ...grpc.CallOption) (*ComposeUpResponse, error) {
-	out := new(ComposeUpResponse)
-	err := c.cc.Invoke(ctx, "/com.docker.api.protos.compose.v1.Compose/Up", in, out, opts...)
+func (c *composeClient) Up(ctx context.Context, in *ComposeUpRequest, opts ...grpc.CallOption) (Compose_UpClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Compose_serviceDesc.Streams[0], "/com.docker.api.protos.compose.v1.Compose/Up", opts...)
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	x := &composeUpClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Compose_UpClient interface {
+	Recv() (*ComposeEvent, error)
+	grpc.ClientStream
+}
+
+type composeUpClient struct {
+	grpc.ClientStream
 }
 
-func (c *composeClient) Down(ctx context.Context, in *ComposeDownRequest, opts ...grpc.CallOption) (*ComposeDownResponse, error) {
-	out := new(ComposeDownResponse)
-	err := c.cc.Invoke(ctx, "/com.docker.api.protos.compose.v1.Compose/Down", in, out, opts...)
+func (x *composeUpClient) Recv() (*ComposeEvent, error) {
+	m := new(ComposeEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *composeClient) Down(ctx context.Context, in *ComposeDownRequest, opts ...grpc.CallOption) (Compose_DownClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Compose_serviceDesc.Streams[1], "/com.docker.api.protos.compose.v1.Compose/Down", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &composeDownClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Compose_DownClient interface {
+	Recv() (*ComposeEvent, error)
+	grpc.ClientStream
+}
+
+type composeDownClient struct {
+	grpc.ClientStream
+}
+
+func (x *composeDownClient) Recv() (*ComposeEvent, error) {
+	m := new(ComposeEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *composeClient) Validate(ctx context.Context, in *ComposeValidateRequest, opts ...grpc.CallOption) (*ComposeValidateResponse, error) {
+	out := new(ComposeValidateResponse)
+	err := c.cc.Invoke(ctx, "/com.docker.api.protos.compose.v1.Compose/Validate", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -410,57 +760,87 @@ func (c *composeClient) Down(ctx context.Context, in *ComposeDownRequest, opts .
 
 // ComposeServer is the server API for Compose service.
 type ComposeServer interface {
-	Up(context.Context, *ComposeUpRequest) (*ComposeUpResponse, error)
-	Down(context.Context, *ComposeDownRequest) (*ComposeDownResponse, error)
+	// Up deploys a project, streaming the progress events until it is deployed
+	Up(*ComposeUpRequest, Compose_UpServer) error
+	// Down removes a project, streaming the progress events until it is removed
+	Down(*ComposeDownRequest, Compose_DownServer) error
+	Validate(context.Context, *ComposeValidateRequest) (*ComposeValidateResponse, error)
 }
 
 // UnimplementedComposeServer can be embedded to have forward compatible implementations.
 type UnimplementedComposeServer struct {
 }
 
-func (*UnimplementedComposeServer) Up(context.Context, *ComposeUpRequest) (*ComposeUpResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Up not implemented")
+func (*UnimplementedComposeServer) Up(*ComposeUpRequest, Compose_UpServer) error {
+	return status.Errorf(codes.Unimplemented, "method Up not implemented")
+}
+func (*UnimplementedComposeServer) Down(*ComposeDownRequest, Compose_DownServer) error {
+	return status.Errorf(codes.Unimplemented, "method Down not implemented")
 }
-func (*UnimplementedComposeServer) Down(context.Context, *ComposeDownRequest) (*ComposeDownResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Down not implemented")
+func (*UnimplementedComposeServer) Validate(context.Context, *ComposeValidateRequest) (*ComposeValidateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Validate not implemented")
 }
 
 func RegisterComposeServer(s *grpc.Server, srv ComposeServer) {
 	s.RegisterService(&_Compose_serviceDesc, srv)
 }
 
-func _Compose_Up_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ComposeUpRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(ComposeServer).Up(ctx, in)
+func _Compose_Up_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ComposeUpRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/com.docker.api.protos.compose.v1.Compose/Up",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ComposeServer).Up(ctx, req.(*ComposeUpRequest))
+	return srv.(ComposeServer).Up(m, &composeUpServer{stream})
+}
+
+type Compose_UpServer interface {
+	Send(*ComposeEvent) error
+	grpc.ServerStream
+}
+
+type composeUpServer struct {
+	grpc.ServerStream
+}
+
+func (x *composeUpServer) Send(m *ComposeEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Compose_Down_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ComposeDownRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
 	}
-	return interceptor(ctx, in, info, handler)
+	return srv.(ComposeServer).Down(m, &composeDownServer{stream})
+}
+
+type Compose_DownServer interface {
+	Send(*ComposeEvent) error
+	grpc.ServerStream
 }
 
-func _Compose_Down_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ComposeDownRequest)
+type composeDownServer struct {
+	grpc.ServerStream
+}
+
+func (x *composeDownServer) Send(m *ComposeEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Compose_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ComposeValidateRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ComposeServer).Down(ctx, in)
+		return srv.(ComposeServer).Validate(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/com.docker.api.protos.compose.v1.Compose/Down",
+		FullMethod: "/com.docker.api.protos.compose.v1.Compose/Validate",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ComposeServer).Down(ctx, req.(*ComposeDownRequest))
+		return srv.(ComposeServer).Validate(ctx, req.(*ComposeValidateRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -470,14 +850,21 @@ var _Compose_serviceDesc = grpc.ServiceDesc{
 	HandlerType: (*ComposeServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "Up",
-			Handler:    _Compose_Up_Handler,
+			MethodName: "Validate",
+			Handler:    _Compose_Validate_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Up",
+			Handler:       _Compose_Up_Handler,
+			ServerStreams: true,
 		},
 		{
-			MethodName: "Down",
-			Handler:    _Compose_Down_Handler,
+			StreamName:    "Down",
+			Handler:       _Compose_Down_Handler,
+			ServerStreams: true,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "protos/compose/v1/compose.proto",
 }
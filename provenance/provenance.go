@@ -0,0 +1,282 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provenance
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/registry"
+)
+
+const (
+	// StatementType is the in-toto statement type of attestations
+	StatementType = "https://in-toto.io/Statement/v0.1"
+	// PredicateType identifies compose provenance predicates
+	PredicateType = "https://docs.docker.com/compose/provenance/v0.1"
+)
+
+// cosign is the executable signing and verifying artifacts
+var cosign = "cosign"
+
+// Statement is an in-toto statement attesting the content of a published application
+type Statement struct {
+	Type          string    `json:"_type"`
+	PredicateType string    `json:"predicateType"`
+	Subject       []Subject `json:"subject"`
+	Predicate     Predicate `json:"predicate"`
+}
+
+// Subject is an artifact a statement applies to
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Predicate records what a published application deploys
+type Predicate struct {
+	// ComposeFile is the digest of the published compose file
+	ComposeFile string `json:"composeFile"`
+	// Attachments are the digests of the files published along with the compose file, as env files and configs,
+	// indexed by their path relative to the compose file
+	Attachments map[string]string `json:"attachments,omitempty"`
+	// Images are the pinned images of the project services, indexed by service name
+	Images map[string]string `json:"images"`
+}
+
+// NewPredicate records the digests of a compose file and of its attachments, and the images of the project it
+// describes
+func NewPredicate(composeFile []byte, attachments map[string][]byte, project *types.Project) Predicate {
+	images := map[string]string{}
+	for _, service := range project.Services {
+		images[service.Name] = service.Image
+	}
+	var digests map[string]string
+	if len(attachments) > 0 {
+		digests = map[string]string{}
+		for name, content := range attachments {
+			digests[name] = Digest(content)
+		}
+	}
+	return Predicate{
+		ComposeFile: Digest(composeFile),
+		Attachments: digests,
+		Images:      images,
+	}
+}
+
+// Digest returns the sha256 digest of a content, as sha256:<hex>
+func Digest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// CheckProject verifies a project deploys the attested images
+func (p Predicate) CheckProject(project *types.Project) error {
+	for _, service := range project.Services {
+		attested, ok := p.Images[service.Name]
+		if !ok {
+			return errors.Wrapf(errdefs.ErrForbidden, "service %q isn't part of the attested application", service.Name)
+		}
+		if service.Image != attested {
+			return errors.Wrapf(errdefs.ErrForbidden, "service %q runs %s, but %s is attested", service.Name, service.Image, attested)
+		}
+	}
+	return nil
+}
+
+// CheckArtifact verifies the compose file and the attachments of an artifact are the attested ones
+func (p Predicate) CheckArtifact(ref string, artifact registry.ComposeArtifact) error {
+	if digest := Digest(artifact.ComposeFile); digest != p.ComposeFile {
+		return errors.Wrapf(errdefs.ErrForbidden, "compose file of %s has digest %s, but %s is attested", ref, digest, p.ComposeFile)
+	}
+	for name, content := range artifact.Attachments {
+		attested, ok := p.Attachments[name]
+		if !ok {
+			return errors.Wrapf(errdefs.ErrForbidden, "file %s of %s isn't part of the attested application", name, ref)
+		}
+		if digest := Digest(content); digest != attested {
+			return errors.Wrapf(errdefs.ErrForbidden, "file %s of %s has digest %s, but %s is attested", name, ref, digest, attested)
+		}
+	}
+	for name := range p.Attachments {
+		if _, ok := artifact.Attachments[name]; !ok {
+			return errors.Wrapf(errdefs.ErrForbidden, "attested file %s is missing from %s", name, ref)
+		}
+	}
+	return nil
+}
+
+// Sign signs an artifact, referenced by digest, and attaches the provenance predicate as an attestation. Without a
+// key, cosign keyless signing is used.
+func Sign(ctx context.Context, ref string, key string, predicate Predicate) error {
+	if _, err := run(ctx, withKey([]string{"sign"}, key, ref)...); err != nil {
+		return err
+	}
+	content, err := json.Marshal(predicate)
+	if err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile("", "provenance-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name()) // nolint:errcheck
+	if _, err := f.Write(content); err != nil {
+		f.Close() // nolint:errcheck
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	_, err = run(ctx, withKey([]string{"attest", "--type", PredicateType, "--predicate", f.Name()}, key, ref)...)
+	return err
+}
+
+// Trust is who the signature of an application must come from: the owner of a cosign public key or, for keyless
+// signatures, the signer identity and the OIDC issuer which certified it
+type Trust struct {
+	Key      string
+	Identity string
+	Issuer   string
+}
+
+// Check rejects a keyless trust missing the identity or issuer, which would accept any signature certified by Fulcio
+func (t Trust) Check() error {
+	if t.Key == "" && (t.Identity == "" || t.Issuer == "") {
+		return errors.Wrap(errdefs.ErrParsingFailed, "keyless verification requires the identity and the OIDC issuer of the signer")
+	}
+	return nil
+}
+
+func (t Trust) args(args []string, ref string) []string {
+	if t.Key != "" {
+		return withKey(args, t.Key, ref)
+	}
+	return append(args, "--certificate-identity", t.Identity, "--certificate-oidc-issuer", t.Issuer, ref)
+}
+
+// Verify checks the signature of an artifact referenced by digest is trusted, and returns its attested provenance
+func Verify(ctx context.Context, ref string, trust Trust) (Predicate, error) {
+	if err := trust.Check(); err != nil {
+		return Predicate{}, err
+	}
+	if _, err := run(ctx, trust.args([]string{"verify"}, ref)...); err != nil {
+		return Predicate{}, err
+	}
+	out, err := run(ctx, trust.args([]string{"verify-attestation", "--type", PredicateType}, ref)...)
+	if err != nil {
+		return Predicate{}, err
+	}
+	return parseAttestations(out, ref)
+}
+
+func withKey(args []string, key string, ref string) []string {
+	if key != "" {
+		args = append(args, "--key", key)
+	}
+	return append(args, ref)
+}
+
+func run(ctx context.Context, args ...string) ([]byte, error) {
+	if _, err := exec.LookPath(cosign); err != nil {
+		return nil, errors.Wrap(errdefs.ErrNotFound, "cosign is required to sign and verify applications, see https://github.com/sigstore/cosign")
+	}
+	cmd := exec.CommandContext(ctx, cosign, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrapf(errdefs.ErrForbidden, "cosign %s: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+type envelope struct {
+	PayloadType string `json:"payloadType"`
+	Payload     string `json:"payload"`
+}
+
+// parseAttestations finds the compose provenance of the artifact in the DSSE envelopes printed by cosign, one per line
+func parseAttestations(out []byte, ref string) (Predicate, error) {
+	digest := ref[strings.LastIndex(ref, "@")+1:]
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(nil, 10*1024*1024)
+	for scanner.Scan() {
+		var env envelope
+		if err := json.Unmarshal(scanner.Bytes(), &env); err != nil || env.Payload == "" {
+			continue
+		}
+		payload, err := base64.StdEncoding.DecodeString(env.Payload)
+		if err != nil {
+			return Predicate{}, err
+		}
+		var statement Statement
+		if err := json.Unmarshal(payload, &statement); err != nil {
+			return Predicate{}, err
+		}
+		if statement.PredicateType != PredicateType {
+			continue
+		}
+		for _, subject := range statement.Subject {
+			if "sha256:"+subject.Digest["sha256"] == digest {
+				return statement.Predicate, nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Predicate{}, err
+	}
+	return Predicate{}, errors.Wrapf(errdefs.ErrNotFound, "provenance attestation of %s", ref)
+}
+
+// VerifyArtifact pins an application artifact reference to its digest, verifies its signature and checks its compose
+// file and attachments are the attested ones. It returns the pinned reference and the attested provenance.
+func VerifyArtifact(ctx context.Context, ref string, trust Trust) (string, Predicate, error) {
+	if err := trust.Check(); err != nil {
+		return "", Predicate{}, err
+	}
+	pinned, err := registry.ResolveDigest(ctx, ref)
+	if err != nil {
+		return "", Predicate{}, err
+	}
+	predicate, err := Verify(ctx, pinned, trust)
+	if err != nil {
+		return "", Predicate{}, err
+	}
+	artifact, err := registry.PullComposeArtifact(ctx, pinned)
+	if err != nil {
+		return "", Predicate{}, err
+	}
+	if err := predicate.CheckArtifact(pinned, artifact); err != nil {
+		return "", Predicate{}, err
+	}
+	return pinned, predicate, nil
+}
@@ -0,0 +1,124 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package provenance
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/registry"
+)
+
+const testRef = "registry.example.com/vendor/app@sha256:4bcdffd70da292293d059d2435c7056711fab2655f8b74f48ad0abe042b63687"
+
+func envelopeLine(t *testing.T, statement Statement) []byte {
+	payload, err := json.Marshal(statement)
+	assert.NilError(t, err)
+	line, err := json.Marshal(envelope{
+		PayloadType: "application/vnd.in-toto+json",
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+	})
+	assert.NilError(t, err)
+	return append(line, '\n')
+}
+
+func TestParseAttestations(t *testing.T) {
+	project := &types.Project{Services: types.Services{{Name: "web", Image: "nginx@sha256:0123"}}}
+	predicate := NewPredicate([]byte("services: {}\n"), map[string][]byte{"web.env": []byte("DEBUG=1\n")}, project)
+	other := Statement{Type: StatementType, PredicateType: "https://slsa.dev/provenance/v0.1"}
+	attested := Statement{
+		Type:          StatementType,
+		PredicateType: PredicateType,
+		Subject: []Subject{{
+			Name:   "registry.example.com/vendor/app",
+			Digest: map[string]string{"sha256": "4bcdffd70da292293d059d2435c7056711fab2655f8b74f48ad0abe042b63687"},
+		}},
+		Predicate: predicate,
+	}
+	out := append(envelopeLine(t, other), envelopeLine(t, attested)...)
+
+	parsed, err := parseAttestations(out, testRef)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, parsed, predicate)
+	assert.NilError(t, parsed.CheckProject(project))
+
+	_, err = parseAttestations(envelopeLine(t, other), testRef)
+	assert.Assert(t, errdefs.IsNotFoundError(err))
+}
+
+func TestCheckProject(t *testing.T) {
+	predicate := Predicate{Images: map[string]string{"web": "nginx@sha256:0123"}}
+	err := predicate.CheckProject(&types.Project{Services: types.Services{{Name: "web", Image: "nginx:latest"}}})
+	assert.Assert(t, errdefs.IsForbiddenError(err))
+	assert.ErrorContains(t, err, "but nginx@sha256:0123 is attested")
+
+	err = predicate.CheckProject(&types.Project{Services: types.Services{{Name: "db", Image: "postgres"}}})
+	assert.ErrorContains(t, err, "isn't part of the attested application")
+}
+
+func TestCheckArtifact(t *testing.T) {
+	artifact := registry.ComposeArtifact{
+		ComposeFile: []byte("services: {}\n"),
+		Attachments: map[string][]byte{"web.env": []byte("DEBUG=1\n")},
+	}
+	predicate := NewPredicate(artifact.ComposeFile, artifact.Attachments, &types.Project{})
+	assert.NilError(t, predicate.CheckArtifact(testRef, artifact))
+
+	tampered := registry.ComposeArtifact{
+		ComposeFile: artifact.ComposeFile,
+		Attachments: map[string][]byte{"web.env": []byte("DEBUG=0\n")},
+	}
+	err := predicate.CheckArtifact(testRef, tampered)
+	assert.Assert(t, errdefs.IsForbiddenError(err))
+	assert.ErrorContains(t, err, "file web.env of "+testRef+" has digest")
+
+	tampered.Attachments = map[string][]byte{"web.env": []byte("DEBUG=1\n"), "db.env": nil}
+	assert.ErrorContains(t, predicate.CheckArtifact(testRef, tampered), "file db.env of "+testRef+" isn't part of the attested application")
+
+	tampered.Attachments = nil
+	assert.ErrorContains(t, predicate.CheckArtifact(testRef, tampered), "attested file web.env is missing")
+
+	tampered.ComposeFile = []byte("services: {web: {image: nginx}}\n")
+	assert.ErrorContains(t, predicate.CheckArtifact(testRef, tampered), "compose file of "+testRef+" has digest")
+}
+
+func TestVerifyWithoutCosign(t *testing.T) {
+	defer func(binary string) { cosign = binary }(cosign)
+	cosign = "cosign-not-installed"
+	_, err := Verify(context.TODO(), testRef, Trust{Identity: "dev@example.com", Issuer: "https://accounts.google.com"})
+	assert.Assert(t, errdefs.IsNotFoundError(err))
+}
+
+func TestTrust(t *testing.T) {
+	assert.NilError(t, Trust{Key: "cosign.pub"}.Check())
+	assert.NilError(t, Trust{Identity: "dev@example.com", Issuer: "https://accounts.google.com"}.Check())
+	assert.Assert(t, errdefs.IsErrParsingFailed(Trust{}.Check()))
+	assert.Assert(t, errdefs.IsErrParsingFailed(Trust{Identity: "dev@example.com"}.Check()))
+
+	_, err := Verify(context.TODO(), testRef, Trust{})
+	assert.Assert(t, errdefs.IsErrParsingFailed(err))
+
+	assert.DeepEqual(t, Trust{Key: "cosign.pub"}.args([]string{"verify"}, testRef), []string{"verify", "--key", "cosign.pub", testRef})
+	assert.DeepEqual(t, Trust{Identity: "dev@example.com", Issuer: "https://accounts.google.com"}.args([]string{"verify"}, testRef),
+		[]string{"verify", "--certificate-identity", "dev@example.com", "--certificate-oidc-issuer", "https://accounts.google.com", testRef})
+}
@@ -0,0 +1,238 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const (
+	// RecordEnvVar names the directory cloud API calls are recorded to, one file per call
+	RecordEnvVar = "COMPOSE_CLI_RECORD"
+	// ReplayEnvVar names a directory of recorded calls, replayed instead of reaching the cloud provider
+	ReplayEnvVar = "COMPOSE_CLI_REPLAY"
+)
+
+// Interaction is a recorded API call. Request headers aren't recorded, as they carry credentials, and the known
+// secret fields of the bodies are redacted. Bodies may still hold sensitive values in other fields.
+type Interaction struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	RequestBody string      `json:"requestBody,omitempty"`
+	Status      int         `json:"status"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        string      `json:"body,omitempty"`
+}
+
+// Transport returns the transport the backends send cloud API requests with: base, or a transport recording or
+// replaying calls when COMPOSE_CLI_RECORD or COMPOSE_CLI_REPLAY is set. A nil base stands for http.DefaultTransport.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if dir := os.Getenv(ReplayEnvVar); dir != "" {
+		replayer, err := NewReplayer(dir)
+		if err != nil {
+			return failingTransport{err: err}
+		}
+		return replayer
+	}
+	if dir := os.Getenv(RecordEnvVar); dir != "" {
+		return NewRecorder(dir, base)
+	}
+	return base
+}
+
+// Recorder is a transport saving the calls it sends with its base transport
+type Recorder struct {
+	dir  string
+	base http.RoundTripper
+	mu   sync.Mutex
+	seq  int
+}
+
+// NewRecorder returns a transport recording the calls sent with base into dir
+func NewRecorder(dir string, base http.RoundTripper) *Recorder {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Recorder{dir: dir, base: base}
+}
+
+// RoundTrip sends the request and records it along with its response
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	interaction := Interaction{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		RequestBody: redact(requestBody, req.Header.Get("Content-Type")),
+		Status:      resp.StatusCode,
+		Header:      resp.Header,
+		Body:        redact(body, resp.Header.Get("Content-Type")),
+	}
+	if err := r.save(interaction); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r *Recorder) save(interaction Interaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := os.MkdirAll(r.dir, 0700); err != nil {
+		return err
+	}
+	r.seq++
+	b, err := json.MarshalIndent(interaction, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(r.dir, fmt.Sprintf("%04d.json", r.seq)), b, 0600)
+}
+
+// Replayer is a transport answering requests with recorded calls. A recorded call answers a single request, the
+// first one with the same method and URL, preferably with the same redacted body so that identical calls are replayed
+// in order.
+type Replayer struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayer loads the calls recorded in dir
+func NewReplayer(dir string) (*Replayer, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	r := &Replayer{}
+	for _, file := range files {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var interaction Interaction
+		if err := json.Unmarshal(b, &interaction); err != nil {
+			return nil, errors.Wrapf(errdefs.ErrParsingFailed, "recorded call %s: %s", file, err)
+		}
+		r.interactions = append(r.interactions, interaction)
+	}
+	r.used = make([]bool, len(r.interactions))
+	return r, nil
+}
+
+// RoundTrip answers the request with its recorded response
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	body = redact(body, req.Header.Get("Content-Type"))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	match := -1
+	for i, interaction := range r.interactions {
+		if r.used[i] || interaction.Method != req.Method || interaction.URL != req.URL.String() {
+			continue
+		}
+		if interaction.RequestBody == body {
+			match = i
+			break
+		}
+		if match < 0 {
+			match = i
+		}
+	}
+	if match < 0 {
+		return nil, errors.Wrapf(errdefs.ErrNotFound, "no recorded call for %s %s", req.Method, req.URL)
+	}
+	r.used[match] = true
+	interaction := r.interactions[match]
+	header := interaction.Header
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Status, http.StatusText(interaction.Status)),
+		StatusCode:    interaction.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header.Clone(),
+		Body:          ioutil.NopCloser(strings.NewReader(interaction.Body)),
+		ContentLength: int64(len(interaction.Body)),
+		Request:       req,
+	}, nil
+}
+
+// Unused returns the recorded calls which didn't answer a request, for tests to check the backend made them all
+func (r *Replayer) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var unused []Interaction
+	for i, interaction := range r.interactions {
+		if !r.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+type failingTransport struct {
+	err error
+}
+
+func (f failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, f.err
+}
+
+// readBody reads a request or response body and replaces it with a copy, so that it can be read again
+func readBody(body *io.ReadCloser) (string, error) {
+	if *body == nil || *body == http.NoBody {
+		return "", nil
+	}
+	b, err := ioutil.ReadAll(*body)
+	_ = (*body).Close()
+	if err != nil {
+		return "", err
+	}
+	*body = ioutil.NopCloser(bytes.NewReader(b))
+	return string(b), nil
+}
@@ -0,0 +1,165 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package recorder
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("X-Call", fmt.Sprint(calls))
+		fmt.Fprintf(w, "%s %s", r.Method, body)
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+
+	client := &http.Client{Transport: NewRecorder(dir, nil)}
+	for _, body := range []string{"first", "second"} {
+		resp, err := client.Post(server.URL+"/stacks", "text/plain", strings.NewReader(body))
+		assert.NilError(t, err)
+		b, err := ioutil.ReadAll(resp.Body)
+		assert.NilError(t, err)
+		assert.Equal(t, string(b), "POST "+body)
+	}
+	server.Close()
+
+	replayer, err := NewReplayer(dir)
+	assert.NilError(t, err)
+	client = &http.Client{Transport: replayer}
+	// a call recorded with the same body is preferred to the first one with the same URL
+	resp, err := client.Post(server.URL+"/stacks", "text/plain", strings.NewReader("second"))
+	assert.NilError(t, err)
+	b, err := ioutil.ReadAll(resp.Body)
+	assert.NilError(t, err)
+	assert.Equal(t, string(b), "POST second")
+	assert.Equal(t, resp.Header.Get("X-Call"), "2")
+	assert.Equal(t, len(replayer.Unused()), 1)
+
+	resp, err = client.Post(server.URL+"/stacks", "text/plain", strings.NewReader("changed"))
+	assert.NilError(t, err)
+	assert.Equal(t, resp.Header.Get("X-Call"), "1")
+	assert.Equal(t, len(replayer.Unused()), 0)
+
+	_, err = replayer.RoundTrip(httptest.NewRequest(http.MethodGet, server.URL+"/stacks", nil))
+	assert.Assert(t, errdefs.IsNotFoundError(err))
+}
+
+func TestTransport(t *testing.T) {
+	base := &http.Transport{}
+	assert.Equal(t, Transport(base), http.RoundTripper(base))
+
+	dir := t.TempDir()
+	os.Setenv(RecordEnvVar, dir)    // nolint:errcheck
+	defer os.Unsetenv(RecordEnvVar) // nolint:errcheck
+	_, ok := Transport(base).(*Recorder)
+	assert.Assert(t, ok)
+
+	os.Setenv(ReplayEnvVar, dir)    // nolint:errcheck
+	defer os.Unsetenv(ReplayEnvVar) // nolint:errcheck
+	_, ok = Transport(base).(*Replayer)
+	assert.Assert(t, ok)
+}
+
+func TestRedact(t *testing.T) {
+	cases := []struct {
+		body        string
+		contentType string
+		expected    string
+	}{
+		{
+			body:        `{"ARN":"arn:secret","SecretString":"p4ssw0rd"}`,
+			contentType: "application/x-amz-json-1.1",
+			expected:    `{"ARN":"arn:secret","SecretString":"REDACTED"}`,
+		},
+		{
+			body:        `{"authorizationData":[{"authorizationToken":"QVdTOnRva2Vu","proxyEndpoint":"https://012345678912.dkr.ecr.eu-west-3.amazonaws.com"}]}`,
+			contentType: "application/x-amz-json-1.1",
+			expected:    `{"authorizationData":[{"authorizationToken":"REDACTED","proxyEndpoint":"https://012345678912.dkr.ecr.eu-west-3.amazonaws.com"}]}`,
+		},
+		{
+			body:        `{"properties":{"volumes":[{"name":"secrets","secret":{"password":"cDRzc3cwcmQ="}}]}}`,
+			contentType: "application/json",
+			expected:    `{"properties":{"volumes":[{"name":"secrets","secret":"REDACTED"}]}}`,
+		},
+		{
+			body:        `{"access_token":"eyJ0","refresh_token":"0.AX","token_type":"Bearer"}`,
+			contentType: "application/json; charset=utf-8",
+			expected:    `{"access_token":"REDACTED","refresh_token":"REDACTED","token_type":"Bearer"}`,
+		},
+		{
+			body:        "Action=CreateStack&StackName=demo&TemplateBody=%7B%22Resources%22%3A%7B%7D%7D",
+			contentType: "application/x-www-form-urlencoded; charset=utf-8",
+			expected:    "Action=CreateStack&StackName=demo&TemplateBody=REDACTED",
+		},
+		{
+			body:        "<GetTemplateResult><TemplateBody>{\n}</TemplateBody></GetTemplateResult>",
+			contentType: "text/xml",
+			expected:    "<GetTemplateResult><TemplateBody>REDACTED</TemplateBody></GetTemplateResult>",
+		},
+		{
+			body:        "plain text",
+			contentType: "text/plain",
+			expected:    "plain text",
+		},
+	}
+	for _, c := range cases {
+		assert.Equal(t, redact(c.body, c.contentType), c.expected)
+	}
+}
+
+func TestRecordRedactsSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		fmt.Fprint(w, `{"Name":"db","SecretString":"p4ssw0rd"}`)
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+
+	client := &http.Client{Transport: NewRecorder(dir, nil)}
+	resp, err := client.Post(server.URL, "application/x-amz-json-1.1", strings.NewReader(`{"SecretString":"p4ssw0rd"}`))
+	assert.NilError(t, err)
+	b, err := ioutil.ReadAll(resp.Body)
+	assert.NilError(t, err)
+	// the backend still gets the secret, only the recording is redacted
+	assert.Equal(t, string(b), `{"Name":"db","SecretString":"p4ssw0rd"}`)
+
+	recorded, err := ioutil.ReadFile(filepath.Join(dir, "0001.json"))
+	assert.NilError(t, err)
+	assert.Assert(t, !strings.Contains(string(recorded), "p4ssw0rd"))
+
+	// a replayed request matches the recording made with the same secret
+	replayer, err := NewReplayer(dir)
+	assert.NilError(t, err)
+	resp, err = (&http.Client{Transport: replayer}).Post(server.URL, "application/x-amz-json-1.1", strings.NewReader(`{"SecretString":"p4ssw0rd"}`))
+	assert.NilError(t, err)
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+}
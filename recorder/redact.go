@@ -0,0 +1,114 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package recorder
+
+import (
+	"encoding/json"
+	"mime"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// redacted replaces the values of secret fields in recorded bodies
+const redacted = "REDACTED"
+
+// secretFields are the fields of request and response bodies holding credentials or secret values, matched case
+// insensitively: secret values of Secrets Manager, ECR authorization tokens, CloudFormation templates which embed
+// secrets, AAD token requests and responses, and ACI secret volumes, secure environment
+// variables, registry passwords and storage account keys
+var secretFields = map[string]bool{
+	"secretstring":       true,
+	"secretbinary":       true,
+	"authorizationtoken": true,
+	"access_token":       true,
+	"refresh_token":      true,
+	"id_token":           true,
+	"templatebody":       true,
+	"secret":             true,
+	"securevalue":        true,
+	"password":           true,
+	"storageaccountkey":  true,
+	"keys":               true,
+	"client_secret":      true,
+	"client_assertion":   true,
+}
+
+// xmlSecretElements matches the content of the secret fields in the XML responses of the CloudFormation query API
+var xmlSecretElements = regexp.MustCompile(`(?s)<(TemplateBody|SecretString)>.*?</(TemplateBody|SecretString)>`)
+
+// redact replaces the values of secret fields in a JSON, form encoded or XML body
+func redact(body string, contentType string) string {
+	if body == "" {
+		return body
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(body)
+		if err != nil {
+			return redacted
+		}
+		found := false
+		for key := range values {
+			if secretFields[strings.ToLower(key)] {
+				values.Set(key, redacted)
+				found = true
+			}
+		}
+		if !found {
+			return body
+		}
+		return values.Encode()
+	case strings.HasSuffix(mediaType, "xml"):
+		return xmlSecretElements.ReplaceAllStringFunc(body, func(element string) string {
+			name := element[1:strings.Index(element, ">")]
+			return "<" + name + ">" + redacted + "</" + name + ">"
+		})
+	}
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(body), &decoded); err != nil || !redactJSON(decoded) {
+		// bodies without secrets are kept as sent, so that they compare equal when replayed
+		return body
+	}
+	b, err := json.Marshal(decoded)
+	if err != nil {
+		return redacted
+	}
+	return string(b)
+}
+
+// redactJSON replaces the values of secret fields in a decoded JSON value, and returns true if it found any
+func redactJSON(value interface{}) bool {
+	found := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if secretFields[strings.ToLower(key)] {
+				v[key] = redacted
+				found = true
+				continue
+			}
+			found = redactJSON(field) || found
+		}
+	case []interface{}:
+		for _, item := range v {
+			found = redactJSON(item) || found
+		}
+	}
+	return found
+}
@@ -0,0 +1,125 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/reference"
+	"github.com/docker/distribution/registry/api/errcode"
+	v2 "github.com/docker/distribution/registry/api/v2"
+	"github.com/docker/distribution/registry/client"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/config"
+)
+
+// CheckImages verifies the images of services exist in their registry, reporting all missing images at once.
+// Images which can't be checked, e.g. without credentials for a private registry, only get a warning.
+func CheckImages(ctx context.Context, services []types.ServiceConfig) error {
+	if config.IsOffline() {
+		logrus.Debug("offline mode, skipping image checks")
+		return nil
+	}
+	results := make([]error, len(services))
+	var wg sync.WaitGroup
+	for i, service := range services {
+		i, service := i, service
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			exists, err := imageExists(ctx, service.Image)
+			if err != nil {
+				logrus.Warnf("cannot check image %q for service %q is accessible: %v", service.Image, service.Name, err)
+				return
+			}
+			if !exists {
+				results[i] = fmt.Errorf("image %q for service %q does not exist", service.Image, service.Name)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var errs *multierror.Error
+	for _, err := range results {
+		if err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return compose.ValidationErrors(errs)
+}
+
+func imageExists(ctx context.Context, image string) (bool, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid image reference %q", image)
+	}
+	repository, err := repository(ctx, named, "pull")
+	if err != nil {
+		return false, err
+	}
+	if canonical, ok := named.(reference.Canonical); ok {
+		manifests, err := repository.Manifests(ctx)
+		if err != nil {
+			return false, err
+		}
+		exists, err := manifests.Exists(ctx, canonical.Digest())
+		if isUnknown(err) {
+			return false, nil
+		}
+		return exists, err
+	}
+	tagged := reference.TagNameOnly(named).(reference.Tagged)
+	_, err = repository.Tags(ctx).Get(ctx, tagged.Tag())
+	if isUnknown(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// isUnknown checks if the registry reported the repository or manifest doesn't exist
+func isUnknown(err error) bool {
+	if err == nil {
+		return false
+	}
+	var unexpected *client.UnexpectedHTTPResponseError
+	if errors.As(err, &unexpected) {
+		return unexpected.StatusCode == http.StatusNotFound
+	}
+	var tagUnknown distribution.ErrTagUnknown
+	if errors.As(err, &tagUnknown) {
+		return true
+	}
+	var codes errcode.Errors
+	if errors.As(err, &codes) {
+		for _, e := range codes {
+			var code errcode.Error
+			if errors.As(e, &code) && (code.Code == v2.ErrorCodeManifestUnknown || code.Code == v2.ErrorCodeNameUnknown) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestCheckImages(t *testing.T) {
+	server := newTestRegistry()
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	err := CheckImages(context.TODO(), []types.ServiceConfig{
+		{Name: "app", Image: host + "/app"},
+		{Name: "unknown", Image: host + "/app:unknown"},
+		{Name: "missing", Image: host + "/missing"},
+	})
+	assert.Error(t, err, `image "`+host+`/app:unknown" for service "unknown" does not exist`+"\n"+
+		`image "`+host+`/missing" for service "missing" does not exist`)
+}
@@ -0,0 +1,192 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/ocischema"
+	"github.com/docker/distribution/reference"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const (
+	// ComposeFileMediaType is the media type of the layer holding the compose file of an application artifact
+	ComposeFileMediaType = "application/vnd.docker.compose.file+yaml"
+	// ComposeAttachmentMediaType is the media type of layers holding files the compose file refers to
+	ComposeAttachmentMediaType = "application/vnd.docker.compose.attachment"
+	// ComposeProjectMediaType is the config media type of application artifacts
+	ComposeProjectMediaType = "application/vnd.docker.compose.project"
+)
+
+// ComposeArtifact is a compose application distributed through a registry
+type ComposeArtifact struct {
+	// ComposeFile is the content of the compose file
+	ComposeFile []byte
+	// Attachments are files the compose file refers to, indexed by their path relative to the compose file
+	Attachments map[string][]byte
+}
+
+// PushComposeArtifact stores an application artifact in a registry, and returns its canonical reference
+func PushComposeArtifact(ctx context.Context, ref string, artifact ComposeArtifact) (string, error) {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid artifact reference %q", ref)
+	}
+	tagged, ok := reference.TagNameOnly(named).(reference.Tagged)
+	if !ok {
+		return "", errors.Wrapf(errdefs.ErrParsingFailed, "artifact reference %q must have a tag", ref)
+	}
+	for name := range artifact.Attachments {
+		if err := checkAttachmentPath(name); err != nil {
+			return "", err
+		}
+	}
+	repository, err := repository(ctx, named, "pull", "push")
+	if err != nil {
+		return "", err
+	}
+
+	blobs := repository.Blobs(ctx)
+	put := func(mediaType string, content []byte, title string) (distribution.Descriptor, error) {
+		descriptor, err := blobs.Put(ctx, mediaType, content)
+		if err != nil {
+			return distribution.Descriptor{}, err
+		}
+		// Put returns application/octet-stream whatever the media type
+		descriptor.MediaType = mediaType
+		if title != "" {
+			descriptor.Annotations = map[string]string{specs.AnnotationTitle: title}
+		}
+		return descriptor, nil
+	}
+
+	configDescriptor, err := put(ComposeProjectMediaType, []byte("{}"), "")
+	if err != nil {
+		return "", err
+	}
+	composeDescriptor, err := put(ComposeFileMediaType, artifact.ComposeFile, "compose.yaml")
+	if err != nil {
+		return "", err
+	}
+	layers := []distribution.Descriptor{composeDescriptor}
+	for _, name := range sortedKeys(artifact.Attachments) {
+		descriptor, err := put(ComposeAttachmentMediaType, artifact.Attachments[name], name)
+		if err != nil {
+			return "", err
+		}
+		layers = append(layers, descriptor)
+	}
+
+	manifest, err := ocischema.FromStruct(ocischema.Manifest{
+		Versioned: ocischema.SchemaVersion,
+		Config:    configDescriptor,
+		Layers:    layers,
+	})
+	if err != nil {
+		return "", err
+	}
+	manifests, err := repository.Manifests(ctx)
+	if err != nil {
+		return "", err
+	}
+	dgst, err := manifests.Put(ctx, manifest, distribution.WithTag(tagged.Tag()))
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot push artifact %q", ref)
+	}
+	canonical, err := reference.WithDigest(reference.TrimNamed(named), dgst)
+	if err != nil {
+		return "", err
+	}
+	return reference.FamiliarString(canonical), nil
+}
+
+// PullComposeArtifact returns the compose file and attachments of an application artifact stored in a registry
+func PullComposeArtifact(ctx context.Context, ref string) (ComposeArtifact, error) {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return ComposeArtifact{}, errors.Wrapf(err, "invalid artifact reference %q", ref)
+	}
+	repository, err := repository(ctx, named, "pull")
+	if err != nil {
+		return ComposeArtifact{}, err
+	}
+	manifests, err := repository.Manifests(ctx)
+	if err != nil {
+		return ComposeArtifact{}, err
+	}
+
+	var manifest distribution.Manifest
+	if canonical, ok := named.(reference.Canonical); ok {
+		manifest, err = manifests.Get(ctx, canonical.Digest())
+	} else {
+		tagged := reference.TagNameOnly(named).(reference.Tagged)
+		manifest, err = manifests.Get(ctx, "", distribution.WithTag(tagged.Tag()))
+	}
+	if err != nil {
+		return ComposeArtifact{}, errors.Wrapf(err, "cannot get manifest of artifact %q", ref)
+	}
+
+	oci, ok := manifest.(*ocischema.DeserializedManifest)
+	if !ok {
+		return ComposeArtifact{}, errors.Wrapf(errdefs.ErrParsingFailed, "%q is not an OCI artifact", ref)
+	}
+	artifact := ComposeArtifact{Attachments: map[string][]byte{}}
+	for _, layer := range oci.Layers {
+		switch layer.MediaType {
+		case ComposeFileMediaType:
+			artifact.ComposeFile, err = repository.Blobs(ctx).Get(ctx, layer.Digest)
+		case ComposeAttachmentMediaType:
+			name := layer.Annotations[specs.AnnotationTitle]
+			if err := checkAttachmentPath(name); err != nil {
+				return ComposeArtifact{}, err
+			}
+			artifact.Attachments[name], err = repository.Blobs(ctx).Get(ctx, layer.Digest)
+		}
+		if err != nil {
+			return ComposeArtifact{}, err
+		}
+	}
+	if artifact.ComposeFile == nil {
+		return ComposeArtifact{}, errors.Wrapf(errdefs.ErrNotFound, "compose file in artifact %q", ref)
+	}
+	return artifact, nil
+}
+
+// checkAttachmentPath prevents attachments from being written outside of the directory the artifact is pulled to
+func checkAttachmentPath(name string) error {
+	if name == "" || path.IsAbs(name) || strings.HasPrefix(path.Clean(name), "..") {
+		return errors.Wrapf(errdefs.ErrParsingFailed, "invalid attachment path %q", name)
+	}
+	return nil
+}
+
+func sortedKeys(m map[string][]byte) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,58 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/docker/distribution/configuration"
+	"github.com/docker/distribution/registry/handlers"
+	_ "github.com/docker/distribution/registry/storage/driver/inmemory"
+	"gotest.tools/v3/assert"
+)
+
+func TestComposeArtifact(t *testing.T) {
+	server := httptest.NewServer(handlers.NewApp(context.TODO(), &configuration.Configuration{
+		Storage: configuration.Storage{"inmemory": configuration.Parameters{}},
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	artifact := ComposeArtifact{
+		ComposeFile: []byte("services:\n  web:\n    image: nginx\n"),
+		Attachments: map[string][]byte{"configs/nginx.conf": []byte("server {}\n")},
+	}
+	pushed, err := PushComposeArtifact(context.TODO(), host+"/vendor/app:1.0", artifact)
+	assert.NilError(t, err)
+	assert.Assert(t, strings.HasPrefix(pushed, host+"/vendor/app@sha256:"), pushed)
+
+	pulled, err := PullComposeArtifact(context.TODO(), host+"/vendor/app:1.0")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, pulled, artifact)
+
+	pulled, err = PullComposeArtifact(context.TODO(), pushed)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, pulled.ComposeFile, artifact.ComposeFile)
+
+	_, err = PushComposeArtifact(context.TODO(), host+"/vendor/app:1.0", ComposeArtifact{
+		Attachments: map[string][]byte{"../escape": nil},
+	})
+	assert.ErrorContains(t, err, "invalid attachment path")
+}
@@ -0,0 +1,113 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/distribution/reference"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/config"
+	"github.com/docker/compose-cli/progress"
+)
+
+// ResolveDigest returns the image reference pinned to the digest its tag currently resolves to
+func ResolveDigest(ctx context.Context, image string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid image reference %q", image)
+	}
+	if _, ok := named.(reference.Canonical); ok {
+		return image, nil
+	}
+	tagged := reference.TagNameOnly(named).(reference.Tagged)
+
+	repository, err := repository(ctx, named, "pull")
+	if err != nil {
+		return "", err
+	}
+	descriptor, err := repository.Tags(ctx).Get(ctx, tagged.Tag())
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot resolve digest for image %q", image)
+	}
+	pinned, err := reference.WithDigest(reference.TrimNamed(named), descriptor.Digest)
+	if err != nil {
+		return "", err
+	}
+	return pinned.String(), nil
+}
+
+// PinImages replaces all service images in project by the digest their tag currently resolves to
+func PinImages(ctx context.Context, project *types.Project) error {
+	w := progress.ContextWriter(ctx)
+	return compose.ForEachService(ctx, project, func(ctx context.Context, service *types.ServiceConfig) error {
+		if service.Image == "" {
+			return fmt.Errorf("service %q has no image to resolve", service.Name)
+		}
+		w.Event(progress.Event{
+			ID:         service.Name,
+			Status:     progress.Working,
+			StatusText: "Resolving image digest",
+		})
+		pinned, err := ResolveDigest(ctx, service.Image)
+		if err != nil {
+			w.Event(progress.Event{
+				ID:         service.Name,
+				Status:     progress.Error,
+				StatusText: "Resolving image digest",
+			})
+			return err
+		}
+		service.Image = pinned
+		w.Event(progress.Event{
+			ID:         service.Name,
+			Status:     progress.Done,
+			StatusText: pinned,
+		})
+		return nil
+	})
+}
+
+// ResolveImageDigests resolves the tag of each service image to its digest, so that a tag pushed again is detected
+// when hashing services. Images which cannot be resolved are reported as warnings, and hashed by their tag.
+func ResolveImageDigests(ctx context.Context, project *types.Project) compose.ImageDigests {
+	digests := compose.ImageDigests{}
+	if config.IsOffline() {
+		return digests
+	}
+	var mu sync.Mutex
+	_ = compose.ForEachService(ctx, project, func(ctx context.Context, service *types.ServiceConfig) error {
+		if service.Image == "" {
+			return nil
+		}
+		pinned, err := ResolveDigest(ctx, service.Image)
+		if err != nil {
+			compose.Warn(ctx, "service %q: %s, changes to the image of tag %q won't be detected", service.Name, err, service.Image)
+			return nil
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		digests[service.Image] = pinned
+		return nil
+	})
+	return digests
+}
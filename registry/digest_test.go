@@ -0,0 +1,89 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+const testDigest = "sha256:4bcdffd70da292293d059d2435c7056711fab2655f8b74f48ad0abe042b63687"
+
+func newTestRegistry() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case "/v2/app/manifests/latest":
+			w.Header().Set("Content-Type", "application/vnd.docker.distribution.manifest.v2+json")
+			w.Header().Set("Docker-Content-Digest", testDigest)
+			w.Header().Set("Content-Length", "1024")
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestResolveDigest(t *testing.T) {
+	server := newTestRegistry()
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	pinned, err := ResolveDigest(context.TODO(), host+"/app")
+	assert.NilError(t, err)
+	assert.Equal(t, pinned, host+"/app@"+testDigest)
+}
+
+func TestResolveDigestKeepsCanonicalReference(t *testing.T) {
+	pinned, err := ResolveDigest(context.TODO(), "nginx@"+testDigest)
+	assert.NilError(t, err)
+	assert.Equal(t, pinned, "nginx@"+testDigest)
+}
+
+func TestResolveDigestUnknownTag(t *testing.T) {
+	server := newTestRegistry()
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	_, err := ResolveDigest(context.TODO(), host+"/app:unknown")
+	assert.ErrorContains(t, err, "cannot resolve digest")
+}
+
+func TestResolveImageDigests(t *testing.T) {
+	server := newTestRegistry()
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	project := &types.Project{
+		Services: []types.ServiceConfig{
+			{Name: "app", Image: host + "/app"},
+			{Name: "unknown", Image: host + "/app:unknown"},
+			{Name: "built"},
+		},
+	}
+	digests := ResolveImageDigests(context.TODO(), project)
+	assert.DeepEqual(t, digests, compose.ImageDigests{host + "/app": host + "/app@" + testDigest})
+}
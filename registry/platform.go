@@ -0,0 +1,140 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/containerd/containerd/platforms"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/ocischema"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/config"
+)
+
+// ImagePlatforms returns the platforms an image is available for
+func ImagePlatforms(ctx context.Context, image string) ([]specs.Platform, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid image reference %q", image)
+	}
+	repository, err := repository(ctx, named, "pull")
+	if err != nil {
+		return nil, err
+	}
+	manifests, err := repository.Manifests(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest distribution.Manifest
+	if canonical, ok := named.(reference.Canonical); ok {
+		manifest, err = manifests.Get(ctx, canonical.Digest())
+	} else {
+		tagged := reference.TagNameOnly(named).(reference.Tagged)
+		manifest, err = manifests.Get(ctx, "", distribution.WithTag(tagged.Tag()))
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot get manifest for image %q", image)
+	}
+
+	switch m := manifest.(type) {
+	case *manifestlist.DeserializedManifestList:
+		var available []specs.Platform
+		for _, d := range m.Manifests {
+			available = append(available, specs.Platform{
+				OS:           d.Platform.OS,
+				Architecture: d.Platform.Architecture,
+				Variant:      d.Platform.Variant,
+			})
+		}
+		return available, nil
+	case *schema2.DeserializedManifest:
+		return configPlatform(ctx, repository, m.Config.Digest)
+	case *ocischema.DeserializedManifest:
+		return configPlatform(ctx, repository, m.Config.Digest)
+	default:
+		return nil, fmt.Errorf("unsupported manifest type %T for image %q", manifest, image)
+	}
+}
+
+func configPlatform(ctx context.Context, repository distribution.Repository, config digest.Digest) ([]specs.Platform, error) {
+	blob, err := repository.Blobs(ctx).Get(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	var img specs.Image
+	if err := json.Unmarshal(blob, &img); err != nil {
+		return nil, err
+	}
+	return []specs.Platform{
+		{
+			OS:           img.OS,
+			Architecture: img.Architecture,
+		},
+	}, nil
+}
+
+// CheckPlatforms verifies service images are available for the platform they will run on,
+// defaulting to the backend target platform for services which don't declare one
+func CheckPlatforms(ctx context.Context, project *types.Project, targetPlatform string) error {
+	if config.IsOffline() {
+		logrus.Debug("offline mode, skipping image platform checks")
+		return nil
+	}
+	return compose.ForEachService(ctx, project, func(ctx context.Context, service *types.ServiceConfig) error {
+		platform := service.Platform
+		if platform == "" {
+			platform = targetPlatform
+		}
+		p, err := platforms.Parse(platform)
+		if err != nil {
+			return errors.Wrapf(err, "invalid platform %q for service %q", platform, service.Name)
+		}
+		available, err := ImagePlatforms(ctx, service.Image)
+		if err != nil {
+			logrus.Warnf("cannot check platform of image %q for service %q: %v", service.Image, service.Name, err)
+			return nil
+		}
+		matcher := platforms.NewMatcher(p)
+		var found []string
+		match := false
+		for _, a := range available {
+			if matcher.Match(a) {
+				match = true
+				break
+			}
+			found = append(found, platforms.Format(a))
+		}
+		if !match {
+			return fmt.Errorf("image %q for service %q is not available for platform %s (found %s)", service.Image, service.Name, platforms.Format(p), strings.Join(found, ", "))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,91 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+const (
+	testConfigDigest = "sha256:a3ed95caeb02ffe68cdd9fd84406680ae93d633cb16422d00e8a7c22955b46d4"
+	testManifest     = `{
+   "schemaVersion": 2,
+   "mediaType": "application/vnd.docker.distribution.manifest.v2+json",
+   "config": {
+      "mediaType": "application/vnd.docker.container.image.v1+json",
+      "size": 40,
+      "digest": "` + testConfigDigest + `"
+   },
+   "layers": []
+}`
+	testConfig = `{"os":"linux","architecture":"arm64"}`
+)
+
+func newTestPlatformRegistry() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case "/v2/app/manifests/latest":
+			w.Header().Set("Content-Type", "application/vnd.docker.distribution.manifest.v2+json")
+			w.Write([]byte(testManifest)) // nolint:errcheck
+		case "/v2/app/blobs/" + testConfigDigest:
+			w.Write([]byte(testConfig)) // nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestImagePlatforms(t *testing.T) {
+	server := newTestPlatformRegistry()
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	available, err := ImagePlatforms(context.TODO(), host+"/app")
+	assert.NilError(t, err)
+	assert.Equal(t, len(available), 1)
+	assert.Equal(t, available[0].OS, "linux")
+	assert.Equal(t, available[0].Architecture, "arm64")
+}
+
+func TestCheckPlatforms(t *testing.T) {
+	server := newTestPlatformRegistry()
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	project := &types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:  "app",
+				Image: host + "/app",
+			},
+		},
+	}
+	err := CheckPlatforms(context.TODO(), project, "linux/amd64")
+	assert.ErrorContains(t, err, "is not available for platform linux/amd64 (found linux/arm64)")
+
+	project.Services[0].Platform = "linux/arm64"
+	assert.NilError(t, CheckPlatforms(context.TODO(), project, "linux/amd64"))
+}
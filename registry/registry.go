@@ -0,0 +1,134 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	cliconfig "github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/config/types"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/reference"
+	"github.com/docker/distribution/registry/client"
+	"github.com/docker/distribution/registry/client/auth"
+	"github.com/docker/distribution/registry/client/auth/challenge"
+	"github.com/docker/distribution/registry/client/transport"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/config"
+	"github.com/docker/compose-cli/errdefs"
+
+	// register supported manifest media types
+	_ "github.com/docker/distribution/manifest/manifestlist"
+	_ "github.com/docker/distribution/manifest/ocischema"
+	_ "github.com/docker/distribution/manifest/schema2"
+)
+
+const (
+	// DockerHub is the registry domain used for images without an explicit registry
+	DockerHub     = "docker.io"
+	dockerHubAuth = "https://index.docker.io/v1/"
+	dockerHubHost = "registry-1.docker.io"
+)
+
+// Domain returns the registry domain hosting an image
+func Domain(image string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid image reference %q", image)
+	}
+	return reference.Domain(named), nil
+}
+
+// Credentials returns the credentials configured in docker CLI configuration for a registry
+func Credentials(domain string) (types.AuthConfig, error) {
+	key := domain
+	if domain == DockerHub {
+		key = dockerHubAuth
+	}
+	return cliconfig.LoadDefaultConfigFile(ioutil.Discard).GetAuthConfig(key)
+}
+
+func repository(ctx context.Context, named reference.Named, actions ...string) (distribution.Repository, error) {
+	domain := reference.Domain(named)
+	if config.IsOffline() {
+		return nil, errors.Wrapf(errdefs.ErrOffline, "cannot reach registry %s", domain)
+	}
+	host := domain
+	if domain == DockerHub {
+		host = dockerHubHost
+	}
+	endpoint := fmt.Sprintf("https://%s", host)
+	if isLocalhost(host) {
+		endpoint = fmt.Sprintf("http://%s", host)
+	}
+
+	credentials, err := Credentials(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	manager := challenge.NewSimpleManager()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"/v2/", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot reach registry %s", domain)
+	}
+	defer resp.Body.Close() // nolint:errcheck
+	if err := manager.AddResponse(resp); err != nil {
+		return nil, err
+	}
+
+	store := credentialStore{auth: credentials}
+	path := reference.Path(named)
+	authorizer := auth.NewAuthorizer(manager,
+		auth.NewTokenHandler(http.DefaultTransport, store, path, actions...),
+		auth.NewBasicHandler(store))
+	name, err := reference.WithName(path)
+	if err != nil {
+		return nil, err
+	}
+	return client.NewRepository(name, endpoint, transport.NewTransport(http.DefaultTransport, authorizer))
+}
+
+func isLocalhost(host string) bool {
+	return host == "localhost" || strings.HasPrefix(host, "localhost:") || strings.HasPrefix(host, "127.0.0.1")
+}
+
+// credentialStore exposes docker CLI credentials to the distribution auth handlers
+type credentialStore struct {
+	auth types.AuthConfig
+}
+
+func (c credentialStore) Basic(*url.URL) (string, string) {
+	return c.auth.Username, c.auth.Password
+}
+
+func (c credentialStore) RefreshToken(*url.URL, string) string {
+	return c.auth.IdentityToken
+}
+
+func (c credentialStore) SetRefreshToken(*url.URL, string, string) {
+}
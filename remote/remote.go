@@ -0,0 +1,250 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package remote
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/config"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/registry"
+)
+
+const (
+	gitScheme   = "git://"
+	ociScheme   = "oci://"
+	httpsScheme = "https://"
+	// defaultFile is the compose file looked up in a git repository when the reference doesn't set a path
+	defaultFile = "compose.yaml"
+	// staleFetch is the age from which fetched compose files are removed, commands using them are long finished
+	staleFetch = 24 * time.Hour
+)
+
+var httpClient = http.DefaultClient
+
+// IsRemote returns true for compose file paths referencing a git repository, an OCI artifact or an https URL
+func IsRemote(p string) bool {
+	return strings.HasPrefix(p, gitScheme) || strings.HasPrefix(p, ociScheme) || strings.HasPrefix(p, httpsScheme)
+}
+
+// Resolve returns local paths for compose file paths, fetching remote compose files in the cache directory. Git
+// repositories are checked out entirely, so that build contexts and env files relative to the compose file are found.
+func Resolve(ctx context.Context, paths []string, cacheDir string) ([]string, error) {
+	resolved := make([]string, len(paths))
+	for i, p := range paths {
+		if !IsRemote(p) {
+			resolved[i] = p
+			continue
+		}
+		if config.IsOffline() {
+			return nil, errors.Wrapf(errdefs.ErrOffline, "cannot fetch compose file %s", p)
+		}
+		if err := os.MkdirAll(cacheDir, 0700); err != nil {
+			return nil, err
+		}
+		if err := removeStaleFetches(cacheDir, cacheKey(p)); err != nil {
+			return nil, err
+		}
+		// each command fetches in its own directory, so that concurrent commands don't remove files in use
+		dir, err := ioutil.TempDir(cacheDir, cacheKey(p)+"-")
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case strings.HasPrefix(p, gitScheme):
+			resolved[i], err = fetchGit(ctx, strings.TrimPrefix(p, gitScheme), dir)
+		case strings.HasPrefix(p, ociScheme):
+			resolved[i], err = fetchArtifact(ctx, strings.TrimPrefix(p, ociScheme), dir)
+		default:
+			resolved[i], err = fetchURL(ctx, p, dir)
+		}
+		if err != nil {
+			return nil, err
+		}
+		logrus.Debugf("compose file %s fetched as %s", p, resolved[i])
+	}
+	return resolved, nil
+}
+
+// removeStaleFetches removes the directories left by previous commands fetching the same compose file
+func removeStaleFetches(cacheDir string, key string) error {
+	entries, err := ioutil.ReadDir(cacheDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), key) || time.Since(entry.ModTime()) < staleFetch {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(cacheDir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func cacheKey(p string) string {
+	sum := sha256.Sum256([]byte(p))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// GitReference is a compose file in a git repository, as `repository#ref:path`
+type GitReference struct {
+	Repository string
+	Ref        string
+	Path       string
+}
+
+// ParseGitReference parses a git reference, without its git:// prefix. Repositories without a scheme nor a ssh
+// user are cloned over https.
+func ParseGitReference(reference string) (GitReference, error) {
+	ref := GitReference{Ref: "HEAD", Path: defaultFile}
+	repository := reference
+	if i := strings.Index(reference, "#"); i >= 0 {
+		repository = reference[:i]
+		fragment := reference[i+1:]
+		if j := strings.Index(fragment, ":"); j >= 0 {
+			if fragment[j+1:] != "" {
+				ref.Path = fragment[j+1:]
+			}
+			fragment = fragment[:j]
+		}
+		if fragment != "" {
+			ref.Ref = fragment
+		}
+	}
+	if repository == "" {
+		return GitReference{}, errors.Wrapf(errdefs.ErrParsingFailed, "git reference %q has no repository", reference)
+	}
+	if strings.HasPrefix(repository, "-") || strings.HasPrefix(ref.Ref, "-") {
+		return GitReference{}, errors.Wrapf(errdefs.ErrParsingFailed, "git reference %q must not start with \"-\"", reference)
+	}
+	if !strings.Contains(repository, "://") && !strings.Contains(repository, "@") {
+		repository = httpsScheme + repository
+	}
+	ref.Repository = repository
+	if path.IsAbs(ref.Path) || strings.HasPrefix(path.Clean(ref.Path), "..") {
+		return GitReference{}, errors.Wrapf(errdefs.ErrParsingFailed, "compose file path %q must be relative to the repository", ref.Path)
+	}
+	return ref, nil
+}
+
+func fetchGit(ctx context.Context, reference string, dir string) (string, error) {
+	ref, err := ParseGitReference(reference)
+	if err != nil {
+		return "", err
+	}
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"fetch", "--quiet", "--depth", "1", "--", ref.Repository, ref.Ref},
+		{"checkout", "--quiet", "FETCH_HEAD"},
+	} {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = dir
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return "", errors.Wrapf(err, "git %s: %s", args[0], strings.TrimSpace(stderr.String()))
+		}
+	}
+	file := filepath.Join(dir, filepath.FromSlash(ref.Path))
+	if _, err := os.Stat(file); err != nil {
+		return "", errors.Wrapf(errdefs.ErrNotFound, "%s in %s at %s", ref.Path, ref.Repository, ref.Ref)
+	}
+	return file, nil
+}
+
+// fetchArtifact pulls an application artifact, writing its attachments next to the compose file they are relative to
+func fetchArtifact(ctx context.Context, reference string, dir string) (string, error) {
+	artifact, err := registry.PullComposeArtifact(ctx, reference)
+	if err != nil {
+		return "", err
+	}
+	for name, content := range artifact.Attachments {
+		attachment := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(attachment), 0700); err != nil {
+			return "", err
+		}
+		if err := ioutil.WriteFile(attachment, content, 0600); err != nil {
+			return "", err
+		}
+	}
+	file := filepath.Join(dir, defaultFile)
+	return file, ioutil.WriteFile(file, artifact.ComposeFile, 0600)
+}
+
+// fetchURL downloads a compose file over https. A `#sha256=<digest>` fragment pins the expected content.
+func fetchURL(ctx context.Context, rawURL string, dir string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", errors.Wrapf(errdefs.ErrParsingFailed, "invalid compose file URL %q", rawURL)
+	}
+	checksum := strings.TrimPrefix(u.Fragment, "sha256=")
+	if u.Fragment != "" && checksum == u.Fragment {
+		return "", errors.Wrapf(errdefs.ErrParsingFailed, "unsupported checksum %q, only sha256=<digest> is", u.Fragment)
+	}
+	u.Fragment = ""
+	if checksum == "" {
+		logrus.Warnf("compose file %s isn't pinned, add #sha256=<digest> to the URL to check its content", u)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close() // nolint:errcheck
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("cannot fetch compose file %s: %s", u, resp.Status)
+	}
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if checksum != "" {
+		sum := sha256.Sum256(content)
+		if actual := hex.EncodeToString(sum[:]); !strings.EqualFold(actual, checksum) {
+			return "", fmt.Errorf("compose file %s has checksum sha256=%s, expected sha256=%s", u, actual, checksum)
+		}
+	}
+
+	name := path.Base(u.Path)
+	if name == "/" || name == "." {
+		name = defaultFile
+	}
+	file := filepath.Join(dir, name)
+	return file, ioutil.WriteFile(file, content, 0600)
+}
@@ -0,0 +1,115 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func TestParseGitReference(t *testing.T) {
+	cases := map[string]GitReference{
+		"github.com/docker/app":                         {Repository: "https://github.com/docker/app", Ref: "HEAD", Path: "compose.yaml"},
+		"github.com/docker/app#v1.2":                    {Repository: "https://github.com/docker/app", Ref: "v1.2", Path: "compose.yaml"},
+		"github.com/docker/app#main:deploy/compose.yml": {Repository: "https://github.com/docker/app", Ref: "main", Path: "deploy/compose.yml"},
+		"git@github.com:docker/app.git#:compose.yml":    {Repository: "git@github.com:docker/app.git", Ref: "HEAD", Path: "compose.yml"},
+	}
+	for reference, expected := range cases {
+		ref, err := ParseGitReference(reference)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, ref, expected)
+	}
+
+	_, err := ParseGitReference("github.com/docker/app#main:../compose.yaml")
+	assert.ErrorContains(t, err, "must be relative to the repository")
+
+	for _, reference := range []string{"--upload-pack=touch /tmp/pwned@host:app", "github.com/docker/app#--upload-pack=id"} {
+		_, err = ParseGitReference(reference)
+		assert.ErrorContains(t, err, `must not start with "-"`)
+	}
+}
+
+func TestResolveURL(t *testing.T) {
+	content := []byte("services:\n  web:\n    image: nginx\n")
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(content)
+	}))
+	defer server.Close()
+	defer func(client *http.Client) { httpClient = client }(httpClient)
+	httpClient = server.Client()
+
+	cache := fs.NewDir(t, "cache")
+	defer cache.Remove()
+	sum := sha256.Sum256(content)
+	checksum := hex.EncodeToString(sum[:])
+
+	paths, err := Resolve(context.TODO(), []string{"local.yaml", server.URL + "/app/compose.yaml#sha256=" + checksum}, cache.Path())
+	assert.NilError(t, err)
+	assert.Equal(t, paths[0], "local.yaml")
+	assert.Equal(t, filepath.Base(paths[1]), "compose.yaml")
+	fetched, err := ioutil.ReadFile(paths[1])
+	assert.NilError(t, err)
+	assert.DeepEqual(t, fetched, content)
+
+	// a concurrent command fetching the same file doesn't remove the files in use
+	again, err := Resolve(context.TODO(), []string{server.URL + "/app/compose.yaml#sha256=" + checksum}, cache.Path())
+	assert.NilError(t, err)
+	assert.Assert(t, again[0] != paths[1])
+	_, err = os.Stat(paths[1])
+	assert.NilError(t, err)
+
+	_, err = Resolve(context.TODO(), []string{server.URL + "/app/compose.yaml#sha256=0123"}, cache.Path())
+	assert.ErrorContains(t, err, "expected sha256=0123")
+}
+
+func TestResolveGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := fs.NewDir(t, "repo", fs.WithDir("deploy", fs.WithFile("compose.yaml", "services: {}\n")))
+	defer repo.Remove()
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "init"},
+		{"tag", "v1"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo.Path()
+		out, err := cmd.CombinedOutput()
+		assert.NilError(t, err, string(out))
+	}
+
+	cache := fs.NewDir(t, "cache")
+	defer cache.Remove()
+	paths, err := Resolve(context.TODO(), []string{"git://file://" + repo.Path() + "#v1:deploy/compose.yaml"}, cache.Path())
+	assert.NilError(t, err)
+	fetched, err := ioutil.ReadFile(paths[0])
+	assert.NilError(t, err)
+	assert.Equal(t, string(fetched), "services: {}\n")
+}
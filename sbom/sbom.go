@@ -0,0 +1,216 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package sbom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/progress"
+)
+
+const (
+	// FormatCycloneDX is the CycloneDX JSON format
+	FormatCycloneDX = "cyclonedx"
+	// FormatSPDX is the SPDX JSON format
+	FormatSPDX = "spdx"
+
+	projectProperty    = "com.docker.compose.project"
+	serviceProperty    = "com.docker.compose.service"
+	deploymentProperty = "com.docker.compose.deployment"
+	documentID         = "SPDXRef-DOCUMENT"
+)
+
+// syft is the executable analyzing image contents
+var syft = "syft"
+
+// Deployment identifies what the document describes
+type Deployment struct {
+	Project string
+	// ID identifies the deployed application on the backend, such as a stack ID, empty if not deployed
+	ID string
+}
+
+// image is the bill of materials of an image, and the project services running it
+type image struct {
+	name     string
+	services []string
+	document map[string]interface{}
+}
+
+// Generate analyzes the images of the project services from their registry, and returns a single document covering them
+func Generate(ctx context.Context, project *types.Project, format string, deployment Deployment) ([]byte, error) {
+	if format != FormatCycloneDX && format != FormatSPDX {
+		return nil, errors.Wrapf(errdefs.ErrParsingFailed, "SBOM format %q, expected %s or %s", format, FormatCycloneDX, FormatSPDX)
+	}
+	if _, err := exec.LookPath(syft); err != nil {
+		return nil, errors.Wrap(errdefs.ErrNotFound, "syft is required to generate SBOMs, see https://github.com/anchore/syft")
+	}
+	w := progress.ContextWriter(ctx)
+	var images []*image
+	byName := map[string]*image{}
+	for _, service := range project.Services {
+		if service.Image == "" {
+			continue
+		}
+		if img, ok := byName[service.Image]; ok {
+			img.services = append(img.services, service.Name)
+			continue
+		}
+		w.Event(progress.Event{ID: service.Image, Status: progress.Working, StatusText: "Analyzing"})
+		document, err := analyze(ctx, service.Image, format)
+		if err != nil {
+			w.Event(progress.Event{ID: service.Image, Status: progress.Error, StatusText: "Error"})
+			return nil, err
+		}
+		w.Event(progress.Event{ID: service.Image, Status: progress.Done, StatusText: "Analyzed"})
+		img := &image{name: service.Image, services: []string{service.Name}, document: document}
+		byName[service.Image] = img
+		images = append(images, img)
+	}
+	var merged map[string]interface{}
+	if format == FormatCycloneDX {
+		merged = mergeCycloneDX(images, deployment)
+	} else {
+		merged = mergeSPDX(images, deployment)
+	}
+	return json.MarshalIndent(merged, "", "  ")
+}
+
+func analyze(ctx context.Context, image string, format string) (map[string]interface{}, error) {
+	cmd := exec.CommandContext(ctx, syft, "registry:"+image, "--quiet", "-o", format+"-json")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Errorf("cannot analyze image %s: %s", image, strings.TrimSpace(stderr.String()))
+	}
+	var document map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &document); err != nil {
+		return nil, errors.Wrapf(errdefs.ErrParsingFailed, "invalid SBOM of image %s", image)
+	}
+	return document, nil
+}
+
+func property(name, value string) map[string]interface{} {
+	return map[string]interface{}{"name": name, "value": value}
+}
+
+// mergeCycloneDX describes the project as an application made of a container component per image, holding the
+// components found in the image
+func mergeCycloneDX(images []*image, deployment Deployment) map[string]interface{} {
+	properties := []interface{}{property(projectProperty, deployment.Project)}
+	if deployment.ID != "" {
+		properties = append(properties, property(deploymentProperty, deployment.ID))
+	}
+	specVersion := "1.3"
+	components := []interface{}{}
+	for _, img := range images {
+		if v, ok := img.document["specVersion"].(string); ok {
+			specVersion = v
+		}
+		var imageProperties []interface{}
+		for _, service := range img.services {
+			imageProperties = append(imageProperties, property(serviceProperty, service))
+		}
+		component := map[string]interface{}{
+			"type":       "container",
+			"bom-ref":    img.name,
+			"name":       img.name,
+			"properties": imageProperties,
+		}
+		if nested, ok := img.document["components"].([]interface{}); ok {
+			component["components"] = nested
+		}
+		components = append(components, component)
+	}
+	return map[string]interface{}{
+		"bomFormat":    "CycloneDX",
+		"specVersion":  specVersion,
+		"serialNumber": "urn:uuid:" + uuid.New().String(),
+		"version":      1,
+		"metadata": map[string]interface{}{
+			"timestamp": time.Now().UTC().Format(time.RFC3339),
+			"component": map[string]interface{}{
+				"type":    "application",
+				"bom-ref": deployment.Project,
+				"name":    deployment.Project,
+			},
+			"properties": properties,
+		},
+		"components": components,
+	}
+}
+
+// mergeSPDX concatenates the packages, files and relationships of the image documents, their SPDX identifiers
+// prefixed to remain unique
+func mergeSPDX(images []*image, deployment Deployment) map[string]interface{} {
+	comment := fmt.Sprintf("%s=%s", projectProperty, deployment.Project)
+	if deployment.ID != "" {
+		comment += fmt.Sprintf(", %s=%s", deploymentProperty, deployment.ID)
+	}
+	merged := map[string][]interface{}{"packages": {}, "files": {}, "relationships": {}}
+	for i, img := range images {
+		prefix := fmt.Sprintf("SPDXRef-Image%d-", i)
+		for key := range merged {
+			elements, ok := img.document[key].([]interface{})
+			if !ok {
+				continue
+			}
+			for _, e := range elements {
+				element, ok := e.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				for _, field := range []string{"SPDXID", "spdxElementId", "relatedSpdxElement"} {
+					if id, ok := element[field].(string); ok && id != documentID {
+						element[field] = prefix + strings.TrimPrefix(id, "SPDXRef-")
+					}
+				}
+				if _, ok := element["comment"]; key == "packages" && !ok {
+					element["comment"] = fmt.Sprintf("%s (%s=%s)", img.name, serviceProperty, strings.Join(img.services, ","))
+				}
+				merged[key] = append(merged[key], element)
+			}
+		}
+	}
+	return map[string]interface{}{
+		"spdxVersion":       "SPDX-2.2",
+		"dataLicense":       "CC0-1.0",
+		"SPDXID":            documentID,
+		"name":              deployment.Project,
+		"documentNamespace": fmt.Sprintf("https://docs.docker.com/compose/sbom/%s-%s", deployment.Project, uuid.New().String()),
+		"creationInfo": map[string]interface{}{
+			"created":  time.Now().UTC().Format(time.RFC3339),
+			"creators": []string{"Tool: docker-compose-cli"},
+			"comment":  comment,
+		},
+		"packages":      merged["packages"],
+		"files":         merged["files"],
+		"relationships": merged["relationships"],
+	}
+}
@@ -0,0 +1,85 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package sbom
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestMergeCycloneDX(t *testing.T) {
+	images := []*image{{
+		name:     "nginx",
+		services: []string{"web", "proxy"},
+		document: map[string]interface{}{
+			"specVersion": "1.4",
+			"components":  []interface{}{map[string]interface{}{"type": "library", "name": "openssl"}},
+		},
+	}}
+	merged := mergeCycloneDX(images, Deployment{Project: "demo", ID: "arn:aws:cloudformation:stack/demo"})
+	assert.Equal(t, merged["specVersion"], "1.4")
+	metadata := merged["metadata"].(map[string]interface{})
+	assert.DeepEqual(t, metadata["properties"], []interface{}{
+		property(projectProperty, "demo"),
+		property(deploymentProperty, "arn:aws:cloudformation:stack/demo"),
+	})
+	component := merged["components"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, component["name"], "nginx")
+	assert.DeepEqual(t, component["properties"], []interface{}{property(serviceProperty, "web"), property(serviceProperty, "proxy")})
+	assert.Equal(t, len(component["components"].([]interface{})), 1)
+}
+
+func TestMergeSPDX(t *testing.T) {
+	document := func() map[string]interface{} {
+		return map[string]interface{}{
+			"packages": []interface{}{map[string]interface{}{"SPDXID": "SPDXRef-Package-openssl", "name": "openssl"}},
+			"relationships": []interface{}{map[string]interface{}{
+				"spdxElementId":      "SPDXRef-DOCUMENT",
+				"relatedSpdxElement": "SPDXRef-Package-openssl",
+				"relationshipType":   "DESCRIBES",
+			}},
+		}
+	}
+	images := []*image{
+		{name: "nginx", services: []string{"web"}, document: document()},
+		{name: "redis", services: []string{"cache"}, document: document()},
+	}
+	merged := mergeSPDX(images, Deployment{Project: "demo"})
+	packages := merged["packages"].([]interface{})
+	assert.Equal(t, len(packages), 2)
+	assert.Equal(t, packages[0].(map[string]interface{})["SPDXID"], "SPDXRef-Image0-Package-openssl")
+	assert.Equal(t, packages[1].(map[string]interface{})["SPDXID"], "SPDXRef-Image1-Package-openssl")
+	assert.Equal(t, packages[1].(map[string]interface{})["comment"], "redis (com.docker.compose.service=cache)")
+	relationship := merged["relationships"].([]interface{})[1].(map[string]interface{})
+	assert.Equal(t, relationship["spdxElementId"], "SPDXRef-DOCUMENT")
+	assert.Equal(t, relationship["relatedSpdxElement"], "SPDXRef-Image1-Package-openssl")
+}
+
+func TestGenerateErrors(t *testing.T) {
+	_, err := Generate(context.TODO(), &types.Project{}, "swid", Deployment{})
+	assert.Assert(t, errdefs.IsErrParsingFailed(err))
+
+	defer func(binary string) { syft = binary }(syft)
+	syft = "syft-not-installed"
+	_, err = Generate(context.TODO(), &types.Project{}, FormatSPDX, Deployment{})
+	assert.Assert(t, errdefs.IsNotFoundError(err))
+}
@@ -0,0 +1,202 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package scan
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/progress"
+)
+
+// trivy is the executable scanning images for vulnerabilities
+var trivy = "trivy"
+
+// Severity ranks vulnerabilities, from Unknown to Critical
+type Severity int
+
+// Severities reported by the scanner
+const (
+	Unknown Severity = iota
+	Low
+	Medium
+	High
+	Critical
+)
+
+var severityNames = []string{"UNKNOWN", "LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+// ParseSeverity returns the severity with the given name, case insensitive
+func ParseSeverity(name string) (Severity, error) {
+	for i, s := range severityNames {
+		if strings.EqualFold(name, s) {
+			return Severity(i), nil
+		}
+	}
+	return Unknown, errors.Wrapf(errdefs.ErrParsingFailed, "invalid severity %q, expected one of %s", name, strings.Join(severityNames, ", "))
+}
+
+func (s Severity) String() string {
+	if s < Unknown || s > Critical {
+		return severityNames[Unknown]
+	}
+	return severityNames[s]
+}
+
+// MarshalJSON writes the severity name
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// Vulnerability is a vulnerable package found in an image
+type Vulnerability struct {
+	ID               string
+	Package          string
+	InstalledVersion string
+	FixedVersion     string `json:",omitempty"`
+	Severity         Severity
+	Title            string `json:",omitempty"`
+}
+
+// ImageReport lists the vulnerabilities found in the image of a service
+type ImageReport struct {
+	Service         string
+	Image           string
+	Vulnerabilities []Vulnerability
+}
+
+// Report lists the vulnerabilities found in the images of a project
+type Report struct {
+	Images []ImageReport
+}
+
+// Project scans the images of the project services, each image once
+func Project(ctx context.Context, project *types.Project) (Report, error) {
+	w := progress.ContextWriter(ctx)
+	scanned := map[string][]Vulnerability{}
+	report := Report{}
+	for _, service := range project.Services {
+		if service.Image == "" {
+			continue
+		}
+		vulnerabilities, ok := scanned[service.Image]
+		if !ok {
+			w.Event(progress.Event{ID: service.Image, Status: progress.Working, StatusText: "Scanning"})
+			var err error
+			vulnerabilities, err = Image(ctx, service.Image)
+			if err != nil {
+				w.Event(progress.Event{ID: service.Image, Status: progress.Error, StatusText: "Error"})
+				return report, err
+			}
+			scanned[service.Image] = vulnerabilities
+			w.Event(progress.Event{ID: service.Image, Status: progress.Done, StatusText: fmt.Sprintf("%d vulnerabilities", len(vulnerabilities))})
+		}
+		report.Images = append(report.Images, ImageReport{
+			Service:         service.Name,
+			Image:           service.Image,
+			Vulnerabilities: vulnerabilities,
+		})
+	}
+	return report, nil
+}
+
+// Image scans an image for vulnerabilities, most severe first
+func Image(ctx context.Context, image string) ([]Vulnerability, error) {
+	if _, err := exec.LookPath(trivy); err != nil {
+		return nil, errors.Wrap(errdefs.ErrNotFound, "trivy is required to scan images, see https://github.com/aquasecurity/trivy")
+	}
+	cmd := exec.CommandContext(ctx, trivy, "image", "--quiet", "--format", "json", image)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Errorf("cannot scan image %s: %s", image, strings.TrimSpace(stderr.String()))
+	}
+	return parseTrivyReport(stdout.Bytes())
+}
+
+type trivyReport struct {
+	Results []struct {
+		Vulnerabilities []struct {
+			VulnerabilityID  string
+			PkgName          string
+			InstalledVersion string
+			FixedVersion     string
+			Severity         string
+			Title            string
+		}
+	}
+}
+
+func parseTrivyReport(out []byte) ([]Vulnerability, error) {
+	var report trivyReport
+	if err := json.Unmarshal(out, &report); err != nil {
+		return nil, errors.Wrap(errdefs.ErrParsingFailed, "invalid trivy report")
+	}
+	vulnerabilities := []Vulnerability{}
+	for _, result := range report.Results {
+		for _, v := range result.Vulnerabilities {
+			severity, err := ParseSeverity(v.Severity)
+			if err != nil {
+				severity = Unknown
+			}
+			vulnerabilities = append(vulnerabilities, Vulnerability{
+				ID:               v.VulnerabilityID,
+				Package:          v.PkgName,
+				InstalledVersion: v.InstalledVersion,
+				FixedVersion:     v.FixedVersion,
+				Severity:         severity,
+				Title:            v.Title,
+			})
+		}
+	}
+	sort.SliceStable(vulnerabilities, func(i, j int) bool {
+		return vulnerabilities[i].Severity > vulnerabilities[j].Severity
+	})
+	return vulnerabilities, nil
+}
+
+// Check returns a forbidden error when vulnerabilities of at least the threshold severity were found
+func (r Report) Check(threshold Severity) error {
+	var found []string
+	count := 0
+	for _, image := range r.Images {
+		n := 0
+		for _, v := range image.Vulnerabilities {
+			if v.Severity >= threshold {
+				n++
+			}
+		}
+		if n > 0 {
+			count += n
+			found = append(found, fmt.Sprintf("%s (%s): %d", image.Service, image.Image, n))
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+	return errors.Wrapf(errdefs.ErrForbidden, "%d vulnerabilities of severity %s or above found, in %s", count, threshold, strings.Join(found, ", "))
+}
@@ -0,0 +1,78 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package scan
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const trivyOutput = `{
+  "Results": [
+    {
+      "Target": "nginx (debian 10.6)",
+      "Vulnerabilities": [
+        {"VulnerabilityID": "CVE-2020-1", "PkgName": "libc6", "InstalledVersion": "2.28", "Severity": "LOW"},
+        {"VulnerabilityID": "CVE-2020-2", "PkgName": "openssl", "InstalledVersion": "1.1.1d", "FixedVersion": "1.1.1g", "Severity": "CRITICAL"}
+      ]
+    }
+  ]
+}`
+
+func TestParseTrivyReport(t *testing.T) {
+	vulnerabilities, err := parseTrivyReport([]byte(trivyOutput))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, vulnerabilities, []Vulnerability{
+		{ID: "CVE-2020-2", Package: "openssl", InstalledVersion: "1.1.1d", FixedVersion: "1.1.1g", Severity: Critical},
+		{ID: "CVE-2020-1", Package: "libc6", InstalledVersion: "2.28", Severity: Low},
+	})
+}
+
+func TestCheck(t *testing.T) {
+	vulnerabilities, err := parseTrivyReport([]byte(trivyOutput))
+	assert.NilError(t, err)
+	report := Report{Images: []ImageReport{{Service: "web", Image: "nginx", Vulnerabilities: vulnerabilities}}}
+
+	assert.NilError(t, report.Check(Critical+1))
+	err = report.Check(High)
+	assert.Assert(t, errdefs.IsForbiddenError(err))
+	assert.ErrorContains(t, err, "1 vulnerabilities of severity HIGH or above found, in web (nginx): 1")
+
+	out, err := json.Marshal(vulnerabilities[0])
+	assert.NilError(t, err)
+	assert.Equal(t, string(out), `{"ID":"CVE-2020-2","Package":"openssl","InstalledVersion":"1.1.1d","FixedVersion":"1.1.1g","Severity":"CRITICAL"}`)
+}
+
+func TestParseSeverity(t *testing.T) {
+	severity, err := ParseSeverity("high")
+	assert.NilError(t, err)
+	assert.Equal(t, severity, High)
+	_, err = ParseSeverity("severe")
+	assert.Assert(t, errdefs.IsErrParsingFailed(err))
+}
+
+func TestScanWithoutTrivy(t *testing.T) {
+	defer func(binary string) { trivy = binary }(trivy)
+	trivy = "trivy-not-installed"
+	_, err := Image(context.TODO(), "nginx")
+	assert.Assert(t, errdefs.IsNotFoundError(err))
+}
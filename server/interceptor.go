@@ -114,7 +114,7 @@ func configureContext(ctx context.Context, currentContext string, method string)
 		ctx = proxy.WithClient(ctx, c)
 	}
 
-	s, err := store.New(configDir)
+	s, err := store.NewFromEnv(ctx, configDir)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,89 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/docker/compose-cli/server/proxy"
+)
+
+var (
+	backendRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "docker_cli",
+		Subsystem: "backend",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of API requests served by a backend",
+		Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+	}, []string{"backend", "method"})
+	backendRequestErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "docker_cli",
+		Subsystem: "backend",
+		Name:      "request_errors_total",
+		Help:      "Number of API requests a backend failed to serve",
+	}, []string{"backend", "method", "code"})
+)
+
+func init() {
+	prometheus.MustRegister(backendRequestDuration, backendRequestErrors)
+	grpc_prometheus.EnableHandlingTimeHistogram()
+}
+
+// prometheusServerInterceptor records the latency and errors of backends serving requests, the current backend being
+// known once the context is configured
+func prometheusServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		backend := ""
+		if backendClient := proxy.Client(ctx); backendClient != nil {
+			backend = backendClient.ContextType()
+		}
+
+		start := time.Now()
+		data, err := handler(ctx, req)
+		backendRequestDuration.WithLabelValues(backend, info.FullMethod).Observe(time.Since(start).Seconds())
+		if err != nil {
+			backendRequestErrors.WithLabelValues(backend, info.FullMethod, status.Code(err).String()).Inc()
+		}
+		return data, err
+	}
+}
+
+// ServeMetrics exposes Prometheus metrics of the gRPC server on /metrics at address, until the context is done.
+// It must be called once all the gRPC services are registered so that their metrics are initialized.
+func ServeMetrics(ctx context.Context, s *grpc.Server, address string) error {
+	grpc_prometheus.Register(s)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	httpServer := &http.Server{Addr: address, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		_ = httpServer.Close()
+	}()
+	if err := httpServer.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
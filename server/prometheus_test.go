@@ -0,0 +1,47 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/server/proxy"
+)
+
+func TestPrometheusBackendErrors(t *testing.T) {
+	newClient := client.NewClient("aci", noopService{})
+	interceptor := prometheusServerInterceptor()
+	ctx := proxy.WithClient(context.TODO(), &newClient)
+
+	route := containerMethodRoute("Inspect")
+	errors := backendRequestErrors.WithLabelValues("aci", route.FullMethod, "Unknown")
+	before := testutil.ToFloat64(errors)
+
+	_, err := interceptor(ctx, nil, route, mockHandler(nil))
+	assert.NilError(t, err)
+	assert.Equal(t, testutil.ToFloat64(errors), before)
+
+	_, err = interceptor(ctx, nil, route, mockHandler(errdefs.ErrNotFound))
+	assert.Assert(t, err == errdefs.ErrNotFound)
+	assert.Equal(t, testutil.ToFloat64(errors), before+1)
+}
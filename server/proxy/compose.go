@@ -0,0 +1,149 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"time"
+
+	"github.com/compose-spec/compose-go/cli"
+	"github.com/compose-spec/compose-go/types"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+	apicontext "github.com/docker/compose-cli/context"
+	"github.com/docker/compose-cli/progress"
+	composev1 "github.com/docker/compose-cli/protos/compose/v1"
+)
+
+// Up deploys a compose project with the backend of a context, streaming the progress events
+func (p *proxy) Up(req *composev1.ComposeUpRequest, stream composev1.Compose_UpServer) error {
+	project, err := loadProject(req.Files, req.WorkDir, req.ProjectName)
+	if err != nil {
+		return err
+	}
+	ctx, c, err := contextClient(stream.Context(), req.ContextName)
+	if err != nil {
+		return err
+	}
+	return streamEvents(ctx, progress.Creation, stream.Send, func(ctx context.Context) error {
+		return c.ComposeService().Up(ctx, project, compose.UpOptions{})
+	})
+}
+
+// Down removes a compose project deployed with the backend of a context, streaming the progress events. The project
+// name is read from the compose files when it's not set.
+func (p *proxy) Down(req *composev1.ComposeDownRequest, stream composev1.Compose_DownServer) error {
+	projectName := req.ProjectName
+	if projectName == "" {
+		project, err := loadProject(req.Files, req.WorkDir, "")
+		if err != nil {
+			return err
+		}
+		projectName = project.Name
+	}
+	ctx, c, err := contextClient(stream.Context(), req.ContextName)
+	if err != nil {
+		return err
+	}
+	return streamEvents(ctx, progress.Removal, stream.Send, func(ctx context.Context) error {
+		return c.ComposeService().Down(ctx, projectName, compose.DownOptions{})
+	})
+}
+
+func loadProject(files []string, workDir string, name string) (*types.Project, error) {
+	options, err := cli.NewProjectOptions(files,
+		cli.WithOsEnv,
+		cli.WithWorkingDirectory(workDir),
+		cli.WithName(name))
+	if err != nil {
+		return nil, err
+	}
+	return cli.ProjectFromOptions(options)
+}
+
+// contextClient returns the client of the context the request targets, the one of the proxy when it's not set
+func contextClient(ctx context.Context, contextName string) (context.Context, *client.Client, error) {
+	if contextName == "" {
+		return ctx, Client(ctx), nil
+	}
+	ctx = apicontext.WithCurrentContext(ctx, contextName)
+	c, err := client.New(ctx)
+	return ctx, c, err
+}
+
+// streamEvents runs the operation sending its progress events, and an error event when it fails
+func streamEvents(ctx context.Context, operation progress.Operation, send func(*composev1.ComposeEvent) error, run func(ctx context.Context) error) error {
+	var sendErr error
+	w := progress.NewEventWriter(operation, func(e progress.JSONEvent) {
+		if sendErr == nil {
+			sendErr = send(toGrpcEvent(e))
+		}
+	})
+	err := run(progress.WithContextWriter(ctx, w))
+	if err != nil {
+		w.Event(progress.Event{Status: progress.Error, StatusText: err.Error()})
+		return err
+	}
+	return sendErr
+}
+
+func toGrpcEvent(e progress.JSONEvent) *composev1.ComposeEvent {
+	return &composev1.ComposeEvent{
+		Type:   e.Type,
+		Id:     e.ID,
+		Text:   e.Text,
+		Status: e.Status,
+		Time:   e.Time.Format(time.RFC3339Nano),
+	}
+}
+
+// Validate converts a compose project for the backend of a context without deploying it, and returns the problems found
+func (p *proxy) Validate(ctx context.Context, req *composev1.ComposeValidateRequest) (*composev1.ComposeValidateResponse, error) {
+	options, err := cli.NewProjectOptions(req.Files,
+		cli.WithOsEnv,
+		cli.WithWorkingDirectory(req.WorkDir),
+		cli.WithName(req.ProjectName))
+	if err != nil {
+		return nil, err
+	}
+	project, err := cli.ProjectFromOptions(options)
+	if err != nil {
+		return toGrpcDiagnostics([]compose.Diagnostic{{Severity: compose.SeverityError, Message: err.Error()}}), nil
+	}
+
+	c := Client(ctx)
+	if req.ContextName != "" {
+		ctx = apicontext.WithCurrentContext(ctx, req.ContextName)
+		c, err = client.New(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return toGrpcDiagnostics(compose.Validate(ctx, c.ComposeService(), project)), nil
+}
+
+func toGrpcDiagnostics(diagnostics []compose.Diagnostic) *composev1.ComposeValidateResponse {
+	response := &composev1.ComposeValidateResponse{}
+	for _, d := range diagnostics {
+		response.Diagnostics = append(response.Diagnostics, &composev1.Diagnostic{
+			Severity: d.Severity,
+			Message:  d.Message,
+		})
+	}
+	return response
+}
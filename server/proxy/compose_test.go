@@ -0,0 +1,58 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/progress"
+	composev1 "github.com/docker/compose-cli/protos/compose/v1"
+)
+
+func TestValidateInvalidProject(t *testing.T) {
+	p := &proxy{}
+	response, err := p.Validate(context.Background(), &composev1.ComposeValidateRequest{
+		ProjectName: "test",
+		Files:       []string{"testdata/missing.yaml"},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Diagnostics), 1)
+	assert.Equal(t, response.Diagnostics[0].Severity, "error")
+}
+
+func TestStreamEvents(t *testing.T) {
+	var events []*composev1.ComposeEvent
+	send := func(e *composev1.ComposeEvent) error {
+		events = append(events, e)
+		return nil
+	}
+	err := streamEvents(context.Background(), progress.Removal, send, func(ctx context.Context) error {
+		progress.ContextWriter(ctx).Event(progress.Event{ID: "web", Status: progress.Done})
+		return errors.New("stack deletion failed")
+	})
+	assert.Error(t, err, "stack deletion failed")
+	assert.Equal(t, len(events), 2)
+	assert.Equal(t, events[0].Type, progress.ResourceRemoved)
+	assert.Equal(t, events[0].Id, "web")
+	assert.Assert(t, events[0].Time != "")
+	assert.Equal(t, events[1].Type, progress.ErrorEvent)
+	assert.Equal(t, events[1].Status, "stack deletion failed")
+}
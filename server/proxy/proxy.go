@@ -22,6 +22,7 @@ import (
 
 	"github.com/docker/compose-cli/api/client"
 	"github.com/docker/compose-cli/config"
+	composev1 "github.com/docker/compose-cli/protos/compose/v1"
 	containersv1 "github.com/docker/compose-cli/protos/containers/v1"
 	contextsv1 "github.com/docker/compose-cli/protos/contexts/v1"
 	streamsv1 "github.com/docker/compose-cli/protos/streams/v1"
@@ -45,6 +46,7 @@ func Client(ctx context.Context) *client.Client {
 // Proxy implements the gRPC server and forwards the actions
 // to the right backend
 type Proxy interface {
+	composev1.ComposeServer
 	containersv1.ContainersServer
 	streamsv1.StreamingServer
 	volumesv1.VolumesServer
@@ -52,6 +54,8 @@ type Proxy interface {
 }
 
 type proxy struct {
+	composev1.UnimplementedComposeServer
+
 	configDir     string
 	mu            sync.Mutex
 	streams       map[string]*streams.Stream
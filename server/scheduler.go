@@ -0,0 +1,85 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+// Scheduler starts the local containers labeled with a compose schedule each time their schedule is due
+type Scheduler struct {
+	apiClient client.APIClient
+}
+
+// NewScheduler returns a scheduler of the containers of the given engine
+func NewScheduler(apiClient client.APIClient) *Scheduler {
+	return &Scheduler{apiClient: apiClient}
+}
+
+// Run checks the scheduled containers at the start of every minute until the context is done
+func (s *Scheduler) Run(ctx context.Context) {
+	for {
+		now := time.Now()
+		next := now.Truncate(time.Minute).Add(time.Minute)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(next.Sub(now)):
+		}
+		// schedules run on UTC time, as they do on the cloud backends
+		if err := s.startDue(ctx, next.UTC()); err != nil {
+			logrus.WithError(err).Warn("cannot start scheduled containers")
+		}
+	}
+}
+
+// startDue starts the stopped scheduled containers whose schedule matches the given time
+func (s *Scheduler) startDue(ctx context.Context, now time.Time) error {
+	list, err := s.apiClient.ContainerList(ctx, types.ContainerListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", compose.ScheduleLabel)),
+	})
+	if err != nil {
+		return err
+	}
+	for _, container := range list {
+		if container.State == "running" {
+			continue
+		}
+		schedule, err := compose.ParseSchedule(container.Labels[compose.ScheduleLabel])
+		if err != nil {
+			logrus.WithError(err).WithField("container", container.ID).Warn("invalid schedule")
+			continue
+		}
+		if !schedule.Matches(now) {
+			continue
+		}
+		logrus.WithField("container", container.ID).WithField("schedule", schedule.String()).Info("starting scheduled container")
+		if err := s.apiClient.ContainerStart(ctx, container.ID, types.ContainerStartOptions{}); err != nil {
+			logrus.WithError(err).WithField("container", container.ID).Warn("cannot start scheduled container")
+		}
+	}
+	return nil
+}
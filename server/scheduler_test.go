@@ -0,0 +1,63 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+type fakeEngine struct {
+	client.APIClient
+	containers []types.Container
+	started    []string
+}
+
+func (f *fakeEngine) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	return f.containers, nil
+}
+
+func (f *fakeEngine) ContainerStart(ctx context.Context, container string, options types.ContainerStartOptions) error {
+	f.started = append(f.started, container)
+	return nil
+}
+
+func TestSchedulerStartsDueContainers(t *testing.T) {
+	engine := &fakeEngine{
+		containers: []types.Container{
+			{ID: "nightly", State: "exited", Labels: map[string]string{compose.ScheduleLabel: "cron(0 3 * * *)"}},
+			{ID: "hourly", State: "exited", Labels: map[string]string{compose.ScheduleLabel: "0 * * * *"}},
+			{ID: "running", State: "running", Labels: map[string]string{compose.ScheduleLabel: "* * * * *"}},
+			{ID: "invalid", State: "exited", Labels: map[string]string{compose.ScheduleLabel: "tomorrow"}},
+		},
+	}
+	err := NewScheduler(engine).startDue(context.Background(), time.Date(2020, 11, 2, 3, 0, 0, 0, time.UTC))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, engine.started, []string{"nightly", "hourly"})
+
+	engine.started = nil
+	err = NewScheduler(engine).startDue(context.Background(), time.Date(2020, 11, 2, 4, 30, 0, 0, time.UTC))
+	assert.NilError(t, err)
+	assert.Assert(t, engine.started == nil)
+}
@@ -21,6 +21,7 @@ import (
 	"net"
 	"strings"
 
+	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/health/grpc_health_v1"
@@ -32,10 +33,15 @@ import (
 func New(ctx context.Context) *grpc.Server {
 	s := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
+			grpc_prometheus.UnaryServerInterceptor,
 			unaryServerInterceptor(ctx),
 			metricsServerInterceptor(metrics.NewClient()),
+			prometheusServerInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(
+			grpc_prometheus.StreamServerInterceptor,
+			streamServerInterceptor(ctx),
 		),
-		grpc.StreamInterceptor(streamServerInterceptor(ctx)),
 	)
 	hs := health.NewServer()
 	grpc_health_v1.RegisterHealthServer(s, hs)
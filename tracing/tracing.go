@@ -0,0 +1,111 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/label"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/docker/compose-cli/config"
+)
+
+const (
+	// EndpointEnvVar is the environment variable setting the OTLP collector spans are exported to,
+	// overriding the endpoint configured on the context
+	EndpointEnvVar = "OTEL_EXPORTER_OTLP_ENDPOINT"
+
+	tracerName      = "github.com/docker/compose-cli"
+	serviceName     = "docker-compose-cli"
+	flushTimeout    = 5 * time.Second
+	defaultOtlpPort = "4317"
+)
+
+// Init configures the global tracer provider to export spans to an OTLP collector, using the endpoint from
+// environment or the context one. Tracing is disabled by default and in offline mode. The returned function flushes
+// pending spans and must be called before exiting.
+func Init(contextEndpoint string, version string) (func(), error) {
+	endpoint := os.Getenv(EndpointEnvVar)
+	if endpoint == "" {
+		endpoint = contextEndpoint
+	}
+	if endpoint == "" || config.IsOffline() {
+		return func() {}, nil
+	}
+
+	exporter, err := otlp.NewExporter(exporterOptions(endpoint)...)
+	if err != nil {
+		return nil, err
+	}
+	processor := sdktrace.NewBatchSpanProcessor(exporter)
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithResource(resource.New(
+			semconv.ServiceNameKey.String(serviceName),
+			semconv.ServiceVersionKey.String(version),
+		)),
+	)
+	global.SetTracerProvider(provider)
+
+	return func() {
+		processor.Shutdown()
+		ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+		defer cancel()
+		_ = exporter.Shutdown(ctx)
+	}, nil
+}
+
+// exporterOptions connects to "host:port" or "http://host:port" endpoints without TLS, and to "https://host:port" with TLS
+func exporterOptions(endpoint string) []otlp.ExporterOption {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return []otlp.ExporterOption{otlp.WithAddress(endpoint), otlp.WithInsecure()}
+	}
+	address := u.Host
+	if u.Port() == "" {
+		address = address + ":" + defaultOtlpPort
+	}
+	if u.Scheme == "https" {
+		return []otlp.ExporterOption{otlp.WithAddress(address), otlp.WithTLSCredentials(credentials.NewClientTLSFromCert(nil, ""))}
+	}
+	return []otlp.ExporterOption{otlp.WithAddress(address), otlp.WithInsecure()}
+}
+
+// Start begins a span named after an operation, child of the span of the context if any
+func Start(ctx context.Context, name string, attributes ...label.KeyValue) (context.Context, trace.Span) {
+	return global.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// End records the error an operation failed with, if any, and ends its span
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(context.Background(), err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,69 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/codes"
+	exporttrace "go.opentelemetry.io/otel/sdk/export/trace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"gotest.tools/v3/assert"
+)
+
+type recorder struct {
+	spans []*exporttrace.SpanData
+}
+
+func (r *recorder) ExportSpans(_ context.Context, spans []*exporttrace.SpanData) error {
+	r.spans = append(r.spans, spans...)
+	return nil
+}
+
+func (r *recorder) Shutdown(context.Context) error {
+	return nil
+}
+
+func TestInitWithoutEndpoint(t *testing.T) {
+	err := os.Unsetenv(EndpointEnvVar)
+	assert.NilError(t, err)
+	flush, err := Init("", "dev")
+	assert.NilError(t, err)
+	flush()
+}
+
+func TestSpans(t *testing.T) {
+	r := &recorder{}
+	global.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(r)))
+
+	ctx, parent := Start(context.Background(), "compose up")
+	_, child := Start(ctx, "compose.Up")
+	End(child, errors.New("deployment failed"))
+	End(parent, nil)
+
+	assert.Equal(t, len(r.spans), 2)
+	assert.Equal(t, r.spans[0].Name, "compose.Up")
+	assert.Equal(t, r.spans[0].StatusCode, codes.Error)
+	assert.Equal(t, r.spans[0].StatusMessage, "deployment failed")
+	assert.Equal(t, r.spans[0].ParentSpanID, r.spans[1].SpanContext.SpanID)
+	assert.Equal(t, r.spans[1].Name, "compose up")
+	assert.Equal(t, r.spans[1].StatusCode, codes.Unset)
+}
@@ -0,0 +1,215 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package update
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/pkg/errors"
+	"golang.org/x/mod/semver"
+
+	"github.com/docker/compose-cli/config"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+const (
+	// StableChannel gets releases
+	StableChannel = "stable"
+	// EdgeChannel gets release candidates and releases
+	EdgeChannel = "edge"
+	// FeedEnvVar overrides the URL of the release feed
+	FeedEnvVar = "COMPOSE_CLI_UPDATE_FEED"
+)
+
+var (
+	// FeedURL is the base URL of the release feed, each channel being a JSON document below it
+	FeedURL = "https://github.com/docker/compose-cli/releases/download/feed"
+	// PublicKey is the base64 encoded ed25519 key release binaries are signed with, set at build time
+	PublicKey = ""
+)
+
+// Release is the latest release of a channel, as described by the feed
+type Release struct {
+	Version  string            `json:"version"`
+	Binaries map[string]Binary `json:"binaries"`
+}
+
+// Binary is the download of a release for a platform. Its signature covers the release version followed by the
+// SHA256 checksum of the binary, so that the binary of a release can't be served as another one.
+type Binary struct {
+	URL       string `json:"url"`
+	SHA256    string `json:"sha256"`
+	Signature string `json:"signature"`
+}
+
+// Platform returns the key of the current platform in release binaries
+func Platform() string {
+	return runtime.GOOS + "-" + runtime.GOARCH
+}
+
+// Newer returns true if the release is more recent than the given version.
+// Development builds, without a semantic version, are never considered outdated.
+func (r Release) Newer(version string) bool {
+	if !semver.IsValid(version) {
+		return false
+	}
+	return semver.Compare(r.Version, version) > 0
+}
+
+// Latest fetches the latest release of a channel from the feed
+func Latest(ctx context.Context, channel string) (Release, error) {
+	if channel != StableChannel && channel != EdgeChannel {
+		return Release{}, errors.Wrapf(errdefs.ErrParsingFailed, "unknown channel %q, must be %q or %q", channel, StableChannel, EdgeChannel)
+	}
+	if config.IsOffline() {
+		return Release{}, errors.Wrap(errdefs.ErrOffline, "cannot check for updates")
+	}
+	feed := FeedURL
+	if url, ok := os.LookupEnv(FeedEnvVar); ok {
+		feed = url
+	}
+	body, err := get(ctx, fmt.Sprintf("%s/%s.json", feed, channel))
+	if err != nil {
+		return Release{}, errors.Wrap(err, "cannot fetch release feed")
+	}
+	var release Release
+	if err := json.Unmarshal(body, &release); err != nil {
+		return Release{}, errors.Wrap(err, "invalid release feed")
+	}
+	if !semver.IsValid(release.Version) {
+		return Release{}, errors.Errorf("invalid release feed: version %q is not a semantic version", release.Version)
+	}
+	return release, nil
+}
+
+// Download fetches the binary of a release for the current platform and verifies its checksum and signature. Releases
+// older than the running version are refused, a feed can't downgrade the CLI.
+func Download(ctx context.Context, release Release, version string) ([]byte, error) {
+	if semver.IsValid(version) && semver.Compare(release.Version, version) < 0 {
+		return nil, errors.Wrapf(errdefs.ErrForbidden, "release %s is older than the running version %s", release.Version, version)
+	}
+	binary, ok := release.Binaries[Platform()]
+	if !ok {
+		return nil, errors.Wrapf(errdefs.ErrNotFound, "release %s has no binary for %s", release.Version, Platform())
+	}
+	if PublicKey == "" {
+		return nil, errors.New("this build has no release signing key, updates can't be verified")
+	}
+	key, err := base64.StdEncoding.DecodeString(PublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, errors.New("invalid release signing key")
+	}
+	data, err := get(ctx, binary.URL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot download release %s", release.Version)
+	}
+	if err := Verify(ed25519.PublicKey(key), release.Version, data, binary); err != nil {
+		return nil, errors.Wrapf(err, "release %s", release.Version)
+	}
+	return data, nil
+}
+
+// Verify checks data matches the checksum of a binary, and the binary is signed by key for version
+func Verify(key ed25519.PublicKey, version string, data []byte, binary Binary) error {
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != binary.SHA256 {
+		return errors.New("checksum mismatch")
+	}
+	signature, err := base64.StdEncoding.DecodeString(binary.Signature)
+	if err != nil {
+		return errors.Wrap(err, "invalid signature")
+	}
+	if !ed25519.Verify(key, SignedMessage(version, sum), signature) {
+		return errors.New("signature verification failed")
+	}
+	return nil
+}
+
+// SignedMessage returns what the signature of a release binary covers: the release version followed by the binary
+// SHA256 checksum
+func SignedMessage(version string, sum [sha256.Size]byte) []byte {
+	return append([]byte(version), sum[:]...)
+}
+
+// Replace atomically replaces the executable at path with data, keeping its permissions.
+// The new binary is written next to it so that the final rename doesn't cross filesystems.
+func Replace(path string, data []byte) error {
+	path, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return err
+	}
+	stat, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".new")
+	if err != nil {
+		return errors.Wrapf(err, "cannot write to %s", filepath.Dir(path))
+	}
+	defer os.Remove(tmp.Name()) // nolint:errcheck
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close() // nolint:errcheck
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), stat.Mode()); err != nil {
+		return err
+	}
+	if runtime.GOOS == "windows" {
+		// a running executable can't be overwritten on Windows, but it can be renamed
+		old := path + ".old"
+		_ = os.Remove(old)
+		if err := os.Rename(path, old); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp.Name(), path); err != nil {
+			_ = os.Rename(old, path)
+			return err
+		}
+		return nil
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() // nolint:errcheck
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
@@ -0,0 +1,125 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package update
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	"github.com/docker/compose-cli/config"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func signedBinary(t *testing.T, version string, data []byte) (ed25519.PublicKey, Binary) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	assert.NilError(t, err)
+	sum := sha256.Sum256(data)
+	return pub, Binary{
+		SHA256:    hex.EncodeToString(sum[:]),
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(priv, SignedMessage(version, sum))),
+	}
+}
+
+func TestVerify(t *testing.T) {
+	data := []byte("docker")
+	key, binary := signedBinary(t, "v1.1.0", data)
+	assert.NilError(t, Verify(key, "v1.1.0", data, binary))
+
+	assert.ErrorContains(t, Verify(key, "v1.1.0", []byte("tampered"), binary), "checksum mismatch")
+
+	other, _ := signedBinary(t, "v1.1.0", data)
+	assert.ErrorContains(t, Verify(other, "v1.1.0", data, binary), "signature verification failed")
+
+	assert.ErrorContains(t, Verify(key, "v1.2.0", data, binary), "signature verification failed")
+}
+
+func TestDownloadRefusesOlderRelease(t *testing.T) {
+	_, err := Download(context.Background(), Release{Version: "v1.0.0"}, "v1.1.0")
+	assert.Assert(t, errdefs.IsForbiddenError(err))
+	assert.ErrorContains(t, err, "release v1.0.0 is older than the running version v1.1.0")
+}
+
+func TestNewer(t *testing.T) {
+	release := Release{Version: "v1.1.0"}
+	assert.Assert(t, release.Newer("v1.0.0"))
+	assert.Assert(t, release.Newer("v1.1.0-rc1"))
+	assert.Assert(t, !release.Newer("v1.1.0"))
+	assert.Assert(t, !release.Newer("v1.2.0"))
+	assert.Assert(t, !release.Newer("dev"))
+}
+
+func TestLatest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/edge.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"version": "v1.1.0-rc1", "binaries": {"linux-amd64": {"url": "https://example.com/docker"}}}`)
+	}))
+	defer server.Close()
+	defer os.Unsetenv(FeedEnvVar) // nolint:errcheck
+	assert.NilError(t, os.Setenv(FeedEnvVar, server.URL))
+
+	release, err := Latest(context.Background(), EdgeChannel)
+	assert.NilError(t, err)
+	assert.Equal(t, release.Version, "v1.1.0-rc1")
+	assert.Equal(t, release.Binaries["linux-amd64"].URL, "https://example.com/docker")
+
+	_, err = Latest(context.Background(), StableChannel)
+	assert.ErrorContains(t, err, "404")
+
+	_, err = Latest(context.Background(), "nightly")
+	assert.Assert(t, errdefs.IsErrParsingFailed(err))
+}
+
+func TestLatestOffline(t *testing.T) {
+	defer config.SetOffline(false) // nolint:errcheck
+	assert.NilError(t, config.SetOffline(true))
+	_, err := Latest(context.Background(), StableChannel)
+	assert.Assert(t, errdefs.IsErrOffline(err))
+}
+
+func TestReplace(t *testing.T) {
+	dir := fs.NewDir(t, "update", fs.WithFile("docker", "old", fs.WithMode(0755)))
+	defer dir.Remove()
+	path := dir.Join("docker")
+
+	assert.NilError(t, Replace(path, []byte("new")))
+	data, err := ioutil.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "new")
+	stat, err := os.Stat(path)
+	assert.NilError(t, err)
+	assert.Equal(t, stat.Mode().Perm(), os.FileMode(0755))
+
+	files, err := filepath.Glob(dir.Join(".docker.new*"))
+	assert.NilError(t, err)
+	assert.Equal(t, len(files), 0)
+}
@@ -0,0 +1,91 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package dockercli
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/progress"
+	"github.com/docker/compose-cli/utils"
+)
+
+// Run runs a docker command against the local engine
+func Run(ctx context.Context, workingDir string, args ...string) error {
+	_, err := output(ctx, workingDir, args...)
+	return err
+}
+
+func output(ctx context.Context, workingDir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "docker", append([]string{"--context", "default"}, args...)...)
+	cmd.Dir = workingDir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", errors.Wrapf(err, "docker %s failed:\n%s", args[0], string(out))
+	}
+	return string(out), nil
+}
+
+// ProjectLabel returns the label flag value to set on images built for a project
+func ProjectLabel(project string) string {
+	return compose.ProjectTag + "=" + project
+}
+
+// ProjectImages returns IDs of the local images built for a project
+func ProjectImages(ctx context.Context, project string) ([]string, error) {
+	out, err := output(ctx, "", "image", "ls", "--quiet", "--no-trunc", "--filter", "label="+ProjectLabel(project))
+	if err != nil {
+		return nil, err
+	}
+	var images []string
+	for _, id := range strings.Fields(out) {
+		if !utils.StringContains(images, id) {
+			images = append(images, id)
+		}
+	}
+	return images, nil
+}
+
+// RemoveImages removes images from the local engine, ignoring images which are not present
+func RemoveImages(ctx context.Context, images []string) error {
+	w := progress.ContextWriter(ctx)
+	for _, image := range images {
+		w.Event(progress.Event{
+			ID:         image,
+			Status:     progress.Working,
+			StatusText: "Removing",
+		})
+		if _, err := output(ctx, "", "image", "rm", "--force", image); err != nil && !strings.Contains(err.Error(), "No such image") {
+			w.Event(progress.Event{
+				ID:         image,
+				Status:     progress.Error,
+				StatusText: "Removing",
+			})
+			return err
+		}
+		w.Event(progress.Event{
+			ID:         image,
+			Status:     progress.Done,
+			StatusText: "Removed",
+		})
+	}
+	return nil
+}
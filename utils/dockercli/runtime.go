@@ -0,0 +1,55 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package dockercli
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+// CheckRuntime checks the runtime a container is requested to run with is registered in the engine. The engine
+// picks its default runtime when none is requested.
+func CheckRuntime(runtimes map[string]moby.Runtime, runtime string) (string, error) {
+	if runtime == "" {
+		return "", nil
+	}
+	if _, ok := runtimes[runtime]; ok {
+		return runtime, nil
+	}
+	var registered []string
+	for name := range runtimes {
+		registered = append(registered, name)
+	}
+	sort.Strings(registered)
+	return "", errors.Wrapf(errdefs.ErrNotFound, "runtime %q is not registered in the Docker engine, available runtimes: %s", runtime, strings.Join(registered, ", "))
+}
+
+// CheckServiceRuntimes checks the runtimes the project services are requested to run with are registered in the engine
+func CheckServiceRuntimes(runtimes map[string]moby.Runtime, project *types.Project) error {
+	for _, service := range project.Services {
+		if _, err := CheckRuntime(runtimes, service.Runtime); err != nil {
+			return errors.Wrapf(err, "service %q", service.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package dockercli
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/errdefs"
+)
+
+var runtimes = map[string]moby.Runtime{
+	"runc":  {Path: "runc"},
+	"runsc": {Path: "/usr/local/bin/runsc"},
+}
+
+func TestCheckRuntime(t *testing.T) {
+	runtime, err := CheckRuntime(runtimes, "")
+	assert.NilError(t, err)
+	assert.Equal(t, runtime, "")
+
+	runtime, err = CheckRuntime(runtimes, "runsc")
+	assert.NilError(t, err)
+	assert.Equal(t, runtime, "runsc")
+
+	_, err = CheckRuntime(runtimes, "kata")
+	assert.Assert(t, errdefs.IsNotFoundError(err))
+	assert.ErrorContains(t, err, `runtime "kata" is not registered in the Docker engine, available runtimes: runc, runsc`)
+}
+
+func TestCheckServiceRuntimes(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			{Name: "web"},
+			{Name: "sandboxed", Runtime: "runsc"},
+		},
+	}
+	assert.NilError(t, CheckServiceRuntimes(runtimes, project))
+
+	project.Services = append(project.Services, types.ServiceConfig{Name: "vm", Runtime: "kata"})
+	err := CheckServiceRuntimes(runtimes, project)
+	assert.Assert(t, errdefs.IsNotFoundError(err))
+	assert.ErrorContains(t, err, `service "vm": runtime "kata"`)
+}